@@ -0,0 +1,241 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// benchmarkPluginSize describes the amount of content set on a plugin by the
+// emission benchmarks.
+type benchmarkPluginSize struct {
+	name            string
+	longOutputLines int
+	errors          int
+	metrics         int
+	payloadBytes    int
+}
+
+// benchmarkPluginSizes is the collection of output sizes used by the
+// emission benchmarks.
+var benchmarkPluginSizes = []benchmarkPluginSize{
+	{name: "small", longOutputLines: 0, errors: 0, metrics: 1, payloadBytes: 0},
+	{name: "medium", longOutputLines: 50, errors: 3, metrics: 20, payloadBytes: 4 * 1024},
+	{name: "large", longOutputLines: 5000, errors: 20, metrics: 100, payloadBytes: 256 * 1024},
+}
+
+// benchmarkLongOutput returns LongServiceOutput content with the given
+// number of lines.
+func benchmarkLongOutput(lines int) string {
+	var longOutput strings.Builder
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&longOutput, "* datastore_%03d: %d%% used%s", i, 50+i%50, nagios.CheckOutputEOL)
+	}
+
+	return longOutput.String()
+}
+
+func BenchmarkPluginOutput(b *testing.B) {
+	emitters := []struct {
+		name string
+		emit func(plugin *nagios.Plugin)
+	}{
+		{
+			name: "ReturnCheckResults",
+			emit: func(plugin *nagios.Plugin) { plugin.ReturnCheckResults() },
+		},
+		{
+			name: "PassiveCheckResult",
+			emit: func(plugin *nagios.Plugin) {
+				_ = plugin.PassiveCheckResult("host.example.com", "Datastores")
+			},
+		},
+	}
+
+	for _, emitter := range emitters {
+		emitter := emitter
+
+		for _, size := range benchmarkPluginSizes {
+			size := size
+
+			b.Run(emitter.name+"/"+size.name, func(b *testing.B) {
+				longOutput := benchmarkLongOutput(size.longOutputLines)
+				payload := strings.Repeat("x", size.payloadBytes)
+
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+
+					plugin := nagios.NewPlugin()
+					plugin.SetOutputTarget(io.Discard)
+					plugin.SetExitFunc(func(int) {})
+
+					plugin.ServiceOutput = "WARNING: 3 of 20 datastores above usage threshold"
+					plugin.LongServiceOutput = longOutput
+					plugin.WarningThreshold = "80"
+					plugin.CriticalThreshold = "90"
+					plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+					plugin.BrandingCallback = func() string { return "Notification generated by check-benchmark v1.0.0" }
+
+					for j := 0; j < size.errors; j++ {
+						plugin.AddError(errors.New("datastore usage above threshold"))
+					}
+
+					for j := 0; j < size.metrics; j++ {
+						if err := plugin.AddPerfData(false, nagios.PerformanceData{
+							Label:             fmt.Sprintf("datastore_%03d", j),
+							Value:             fmt.Sprintf("%d", 50+j%50),
+							UnitOfMeasurement: "%",
+							Warn:              "80",
+							Crit:              "90",
+							Min:               "0",
+							Max:               "100",
+						}); err != nil {
+							b.Fatal(err)
+						}
+					}
+
+					if payload != "" {
+						if _, err := plugin.SetPayloadString(payload); err != nil {
+							b.Fatal(err)
+						}
+					}
+
+					b.StartTimer()
+
+					emitter.emit(plugin)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkAddPerfData(b *testing.B) {
+	pd := nagios.PerformanceData{
+		Label:             "datastore_001",
+		Value:             "51",
+		UnitOfMeasurement: "%",
+		Warn:              "80",
+		Crit:              "90",
+		Min:               "0",
+		Max:               "100",
+	}
+
+	plugin := nagios.NewPlugin()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := plugin.AddPerfData(false, pd); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPerformanceDataString(b *testing.B) {
+	pd := nagios.PerformanceData{
+		Label:             "datastore_001",
+		Value:             "51",
+		UnitOfMeasurement: "%",
+		Warn:              "80",
+		Crit:              "90",
+		Min:               "0",
+		Max:               "100",
+	}
+
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = pd.String()
+		}
+	})
+
+	b.Run("AppendTo", func(b *testing.B) {
+		buf := make([]byte, 0, 128)
+
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			buf = pd.AppendTo(buf[:0])
+		}
+	})
+}
+
+// benchmarkPortMetrics returns the given number of per-port performance data
+// metrics as emitted by a switch check.
+func benchmarkPortMetrics(count int) []nagios.PerformanceData {
+	metrics := make([]nagios.PerformanceData, count)
+	for i := range metrics {
+		metrics[i] = nagios.PerformanceData{
+			Label:             fmt.Sprintf("Port_%05d_In", i),
+			Value:             fmt.Sprintf("%d", i*1024),
+			UnitOfMeasurement: "c",
+		}
+	}
+
+	return metrics
+}
+
+func BenchmarkPerfDataManyMetrics(b *testing.B) {
+	for _, count := range []int{100, 1000, 10000} {
+		metrics := benchmarkPortMetrics(count)
+
+		b.Run(fmt.Sprintf("AddPerfData/%d", count), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				plugin := nagios.NewPlugin()
+				for _, pd := range metrics {
+					if err := plugin.AddPerfData(true, pd); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("AddPerfDataReserved/%d", count), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				plugin := nagios.NewPlugin()
+				plugin.ReservePerfData(count)
+				for _, pd := range metrics {
+					if err := plugin.AddPerfData(true, pd); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("ReturnCheckResults/%d", count), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				plugin := nagios.NewPlugin()
+				plugin.SetOutputTarget(io.Discard)
+				plugin.SetExitFunc(func(int) {})
+				plugin.ServiceOutput = "OK: all ports operational"
+				if err := plugin.AddPerfData(true, metrics...); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				plugin.ReturnCheckResults()
+			}
+		})
+	}
+}
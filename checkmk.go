@@ -0,0 +1,361 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OutputFormat is the format used when rendering plugin output.
+type OutputFormat int
+
+// Supported plugin output formats.
+const (
+	// OutputFormatNagios is the default Nagios plugin output format.
+	OutputFormatNagios OutputFormat = iota
+
+	// OutputFormatCheckmkLocal is the Checkmk "local check" output format
+	// used by plugins executed by the Checkmk agent.
+	OutputFormatCheckmkLocal
+
+	// OutputFormatSensuEvent is the Sensu Go event JSON format accepted by
+	// the Sensu agent events API.
+	OutputFormatSensuEvent
+
+	// OutputFormatMRTG is the four line MRTG external script output format
+	// (two values, uptime and target name) used by legacy MRTG consumers.
+	OutputFormatMRTG
+)
+
+// String returns the name of the output format.
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputFormatNagios:
+		return "nagios"
+	case OutputFormatCheckmkLocal:
+		return "checkmk_local"
+	case OutputFormatSensuEvent:
+		return "sensu_event"
+	case OutputFormatMRTG:
+		return "mrtg"
+	default:
+		return "unknown"
+	}
+}
+
+// checkmkNoPerfData is the placeholder used by the Checkmk local check format
+// when no performance data is available.
+const checkmkNoPerfData string = "-"
+
+// SetOutputFormat overrides the default Nagios plugin output format. This
+// allows the same plugin to be invoked by Nagios or (for example) the
+// Checkmk agent.
+//
+// The plugin output size metric (if enabled) is only emitted for the Nagios
+// plugin output format.
+func (p *Plugin) SetOutputFormat(format OutputFormat) {
+	p.outputFormat = format
+}
+
+// SetCheckmkItem overrides the default Checkmk local check item (service)
+// name. If not specified, the base name of the plugin executable is used.
+func (p *Plugin) SetCheckmkItem(item string) {
+	p.checkmkItem = item
+}
+
+// renderOutput returns the plugin output in the configured output format.
+func (p *Plugin) renderOutput() string {
+	switch p.outputFormat {
+	case OutputFormatCheckmkLocal:
+		p.logAction("Rendering Checkmk local check output")
+		return p.CheckmkLocalOutput()
+	case OutputFormatSensuEvent:
+		p.logAction("Rendering Sensu event output")
+		return p.SensuEventOutput()
+	case OutputFormatMRTG:
+		p.logAction("Rendering MRTG output")
+		return p.MRTGOutput()
+	default:
+		return p.assembleOutput()
+	}
+}
+
+// getCheckmkItem returns the user-specified Checkmk item or the base name of
+// the plugin executable as a fallback.
+func (p *Plugin) getCheckmkItem() string {
+	if p.checkmkItem != "" {
+		return p.checkmkItem
+	}
+
+	return filepath.Base(os.Args[0])
+}
+
+// CheckmkLocalOutput renders the current plugin state as a single Checkmk
+// local check line in the format:
+//
+//	<state> <item> <perfdata> <text>
+//
+// The item is quoted if it contains spaces. Performance data metrics are
+// separated by pipe characters (or a dash if no metrics are available).
+// Recorded errors and LongServiceOutput are appended to the ServiceOutput
+// text using escaped newlines (supported by Checkmk 2.0 and newer).
+//
+// Checkmk does not support the DEPENDENT state; it is reported as UNKNOWN.
+//
+// Any piggyback results (see AddCheckmkPiggybackResult) are rendered after
+// the local check line. The plugin timeout (if armed) is disarmed.
+func (p *Plugin) CheckmkLocalOutput() string {
+	p.disarmTimeoutForRender()
+	p.normalizeErrors()
+	p.checkInternalFailures()
+	p.releaseCompressedPayload()
+
+	if strings.TrimSpace(p.ServiceOutput) != "" {
+		p.tryAddDefaultTimeMetric()
+	}
+
+	var output strings.Builder
+
+	output.WriteString(checkmkLocalLine(
+		p.ExitStatusCode,
+		p.getCheckmkItem(),
+		p.getSortedPerfData(),
+		p.checkmkText(),
+	))
+
+	p.writeCheckmkPiggyback(&output)
+
+	return output.String()
+}
+
+// checkmkLocalLine returns a newline terminated Checkmk local check line for
+// the given values.
+func checkmkLocalLine(exitCode int, item string, perfData []PerformanceData, text string) string {
+	state := exitCode
+	if state < StateOKExitCode || state > StateUNKNOWNExitCode {
+		state = StateUNKNOWNExitCode
+	}
+
+	if strings.ContainsAny(item, " \t") {
+		item = `"` + strings.ReplaceAll(item, `"`, "'") + `"`
+	}
+
+	return strconv.Itoa(state) + " " +
+		item + " " +
+		checkmkPerfData(perfData) + " " +
+		text + "\n"
+}
+
+// checkmkPerfData returns the given performance data in the Checkmk local
+// check format.
+func checkmkPerfData(perfData []PerformanceData) string {
+	if len(perfData) == 0 {
+		return checkmkNoPerfData
+	}
+
+	metrics := make([]string, 0, len(perfData))
+	for _, pd := range perfData {
+		metrics = append(metrics, checkmkMetric(pd))
+	}
+
+	return strings.Join(metrics, "|")
+}
+
+// checkmkMetric returns the given performance data metric in the Checkmk
+// local check format: name=value;warn;crit;min;max
+//
+// Checkmk does not support Nagios range syntax or units of measurement;
+// thresholds which are not plain numbers are omitted and units are dropped.
+// Trailing empty fields are removed.
+func checkmkMetric(pd PerformanceData) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '|', '=', ';':
+			return '_'
+		default:
+			return r
+		}
+	}, pd.Label)
+
+	fields := []string{
+		pd.Value,
+		checkmkNumber(pd.Warn),
+		checkmkNumber(pd.Crit),
+		checkmkNumber(pd.Min),
+		checkmkNumber(pd.Max),
+	}
+
+	end := len(fields)
+	for end > 1 && fields[end-1] == "" {
+		end--
+	}
+
+	return name + "=" + strings.Join(fields[:end], ";")
+}
+
+// checkmkNumber returns the given value if it is a plain number, otherwise an
+// empty string.
+func checkmkNumber(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return ""
+	}
+
+	return value
+}
+
+// checkmkText returns the ServiceOutput text followed by recorded errors and
+// LongServiceOutput, with newlines escaped as required by the Checkmk local
+// check format.
+func (p *Plugin) checkmkText() string {
+	return checkmkEscapeText(p.summaryText())
+}
+
+// summaryText returns the ServiceOutput text followed by recorded errors,
+// LongServiceOutput and any tables (rendered as plain text) separated by
+// newlines. Output sections specific to the Nagios plugin output format
+// (e.g., thresholds, encoded payload) are not included.
+func (p *Plugin) summaryText() string {
+	lines := []string{strings.TrimSpace(p.ServiceOutput)}
+
+	if !p.isErrorsHidden() {
+		for _, err := range p.Errors {
+			if err == nil {
+				continue
+			}
+			lines = append(lines, "* "+err.Error())
+		}
+	}
+
+	if long := strings.TrimSpace(p.LongServiceOutput); long != "" {
+		lines = append(lines, long)
+	}
+
+	for _, table := range p.allOutputTables() {
+		lines = append(lines, table.Text("\n"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// checkmkEscapeText escapes newlines in the given text as required by the
+// Checkmk local check format.
+func checkmkEscapeText(text string) string {
+	text = strings.ReplaceAll(text, "\r", "")
+	text = strings.ReplaceAll(text, CheckOutputEOL, "\n")
+
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", `\n`)
+}
+
+// Checkmk piggyback section framing.
+const (
+	checkmkLocalSectionHeader string = "<<<local:sep(0)>>>"
+	checkmkPiggybackClose     string = "<<<<>>>>"
+)
+
+// CheckmkResult is a single Checkmk local check result attributed to a
+// piggyback host.
+type CheckmkResult struct {
+	// Item is the Checkmk item (service) name.
+	Item string
+
+	// ExitStatusCode is the exit status code indicating the state of the
+	// item.
+	ExitStatusCode int
+
+	// ServiceOutput is the one-line summary for the item.
+	ServiceOutput string
+
+	// LongServiceOutput is the optional detailed output for the item.
+	LongServiceOutput string
+
+	// PerfData is the optional collection of performance data metrics for
+	// the item.
+	PerfData []PerformanceData
+}
+
+// AddCheckmkPiggybackResult attributes the given Checkmk local check results
+// to the specified (piggyback) host. This allows a single aggregating plugin
+// run to report results and metrics for multiple hosts when the plugin is
+// executed by the Checkmk agent.
+//
+// Piggyback results are only rendered when using the Checkmk local check
+// output format.
+func (p *Plugin) AddCheckmkPiggybackResult(host string, results ...CheckmkResult) {
+	host = checkmkPiggybackHost(host)
+	if host == "" || len(results) == 0 {
+		return
+	}
+
+	if p.checkmkPiggyback == nil {
+		p.checkmkPiggyback = make(map[string][]CheckmkResult)
+	}
+
+	p.checkmkPiggyback[host] = append(p.checkmkPiggyback[host], results...)
+}
+
+// writeCheckmkPiggyback writes collected piggyback results to the given
+// output framed by <<<<hostname>>>> and <<<<>>>> lines. Hosts are written in
+// sorted order. The local section header is repeated after the piggyback
+// data so that any following output is attributed to the agent host.
+func (p *Plugin) writeCheckmkPiggyback(output *strings.Builder) {
+	if len(p.checkmkPiggyback) == 0 {
+		return
+	}
+
+	hosts := make([]string, 0, len(p.checkmkPiggyback))
+	for host := range p.checkmkPiggyback {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		output.WriteString("<<<<" + host + ">>>>\n")
+		output.WriteString(checkmkLocalSectionHeader + "\n")
+
+		for _, result := range p.checkmkPiggyback[host] {
+			text := result.ServiceOutput
+			if long := strings.TrimSpace(result.LongServiceOutput); long != "" {
+				text += "\n" + long
+			}
+
+			perfData := make([]PerformanceData, len(result.PerfData))
+			copy(perfData, result.PerfData)
+			sort.SliceStable(perfData, func(i, j int) bool {
+				return strings.ToLower(perfData[i].Label) < strings.ToLower(perfData[j].Label)
+			})
+
+			output.WriteString(checkmkLocalLine(
+				result.ExitStatusCode,
+				result.Item,
+				perfData,
+				checkmkEscapeText(text),
+			))
+		}
+
+		output.WriteString(checkmkPiggybackClose + "\n")
+	}
+
+	output.WriteString(checkmkLocalSectionHeader + "\n")
+}
+
+// checkmkPiggybackHost removes characters from the given host name which
+// would break piggyback section framing.
+func checkmkPiggybackHost(host string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		switch r {
+		case '<', '>', '\r', '\n':
+			return -1
+		default:
+			return r
+		}
+	}, host))
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_CheckmkLocalOutput_RendersLocalCheckLine(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetCheckmkItem("Disk usage")
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: disk 85% used"
+	plugin.LongServiceOutput = "line one" + nagios.CheckOutputEOL + "line two"
+	plugin.AddError(errors.New("slow response"))
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "used", Value: "85", UnitOfMeasurement: "%", Warn: "80", Crit: "90", Min: "0", Max: "100"},
+		nagios.PerformanceData{Label: "time", Value: "12", UnitOfMeasurement: "ms"},
+		nagios.PerformanceData{Label: "inodes", Value: "5", Warn: "10:", Crit: "@5:10"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	want := `1 "Disk usage" inodes=5|time=12|used=85;80;90;0;100 ` +
+		`WARNING: disk 85% used\n* slow response\nline one\nline two` + "\n"
+
+	if got := plugin.CheckmkLocalOutput(); got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	t.Log("OK: Checkmk local check line rendered as expected")
+}
+
+func TestPlugin_ReturnCheckResults_UsesCheckmkLocalOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetOutputFormat(nagios.OutputFormatCheckmkLocal)
+	plugin.EnablePluginOutputSizePerfDataMetric()
+	plugin.SetCheckmkItem("my_check")
+	plugin.ExitStatusCode = nagios.StateDEPENDENTExitCode
+	plugin.ServiceOutput = "waiting on parent"
+
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+
+	switch {
+	case !strings.HasPrefix(got, "3 my_check time="):
+		t.Fatalf("ERROR: unexpected output prefix: %q", got)
+	case strings.Contains(got, "plugin_output_size"):
+		t.Fatalf("ERROR: plugin output size metric emitted for Checkmk output: %q", got)
+	case strings.Count(got, "\n") != 1:
+		t.Fatalf("ERROR: want single line of output, got %q", got)
+	default:
+		t.Log("OK: Checkmk local check output format used as expected")
+	}
+}
+
+func TestPlugin_CheckmkLocalOutput_RendersPiggybackSections(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetCheckmkItem("aggregate")
+	plugin.ServiceOutput = "OK: 2 hosts checked"
+	_ = plugin.AddPerfData(false, nagios.PerformanceData{Label: "time", Value: "5", UnitOfMeasurement: "ms"})
+
+	plugin.AddCheckmkPiggybackResult("web02", nagios.CheckmkResult{
+		Item:           "HTTP",
+		ExitStatusCode: nagios.StateCRITICALExitCode,
+		ServiceOutput:  "CRITICAL: down",
+	})
+	plugin.AddCheckmkPiggybackResult("web01", nagios.CheckmkResult{
+		Item:              "HTTP",
+		ExitStatusCode:    nagios.StateOKExitCode,
+		ServiceOutput:     "OK: up",
+		LongServiceOutput: "status 200",
+		PerfData: []nagios.PerformanceData{
+			{Label: "size", Value: "10", UnitOfMeasurement: "B"},
+			{Label: "response time", Value: "0.2", UnitOfMeasurement: "s", Warn: "1", Crit: "2"},
+		},
+	})
+
+	want := "0 aggregate time=5 OK: 2 hosts checked\n" +
+		"<<<<web01>>>>\n" +
+		"<<<local:sep(0)>>>\n" +
+		`0 HTTP response_time=0.2;1;2|size=10 OK: up\nstatus 200` + "\n" +
+		"<<<<>>>>\n" +
+		"<<<<web02>>>>\n" +
+		"<<<local:sep(0)>>>\n" +
+		"2 HTTP - CRITICAL: down\n" +
+		"<<<<>>>>\n" +
+		"<<<local:sep(0)>>>\n"
+
+	if got := plugin.CheckmkLocalOutput(); got != want {
+		t.Fatalf("ERROR: want:\n%s\ngot:\n%s", want, got)
+	}
+
+	t.Log("OK: piggyback sections rendered as expected")
+}
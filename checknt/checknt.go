@@ -0,0 +1,346 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checknt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command is a check_nt numeric command.
+type Command int
+
+// Supported check_nt commands.
+const (
+	CommandClientVersion Command = 1
+	CommandCPULoad       Command = 2
+	CommandUptime        Command = 3
+	CommandUsedDiskSpace Command = 4
+	CommandServiceState  Command = 5
+	CommandProcState     Command = 6
+	CommandMemUse        Command = 7
+	CommandCounter       Command = 8
+	CommandFileAge       Command = 9
+	CommandInstances     Command = 10
+)
+
+// Protocol values used by NSClient++ and check_nt.
+const (
+	// DefaultPort is the default TCP port used by the NSClient listener.
+	DefaultPort string = "12489"
+
+	// Separator separates the fields of requests and the values of
+	// responses.
+	Separator string = "&"
+
+	// ErrorPrefix is the prefix used by NSClient++ for error responses.
+	ErrorPrefix string = "ERROR"
+
+	// DefaultCounterFormat is the format used by check_nt for counter
+	// values if a format is not specified.
+	DefaultCounterFormat string = "%.f"
+
+	// maxResponseSize limits the size of accepted responses.
+	maxResponseSize int64 = 64 * 1024
+
+	// defaultTimeout is used for connecting to and communicating with the
+	// NSClient listener if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the NSClient listener address was
+	// not provided.
+	ErrMissingAddress = errors.New("NSClient address not specified")
+
+	// ErrInvalidRequest indicates that a request is malformed or contains
+	// values which cannot be represented in a request.
+	ErrInvalidRequest = errors.New("invalid check_nt request")
+
+	// ErrEmptyResponse indicates that an empty response was received.
+	ErrEmptyResponse = errors.New("empty check_nt response")
+
+	// ErrErrorResponse indicates that an error response was received.
+	ErrErrorResponse = errors.New("check_nt error response")
+
+	// ErrInvalidValue indicates that a response value could not be
+	// converted to a numeric value.
+	ErrInvalidValue = errors.New("invalid check_nt response value")
+)
+
+// integerVerb matches printf-style integer verbs (with optional flags, width
+// and length modifiers) which are formatted as rounded floating point
+// values.
+var integerVerb = regexp.MustCompile(`%([-+ #0]*[0-9]*)(?:hh|h|ll|l|j|z|t)?[diu]`)
+
+// floatLengthModifier matches length modifiers used with printf-style
+// floating point verbs (e.g., "%lf") which are not supported by Go.
+var floatLengthModifier = regexp.MustCompile(`%([-+ #0]*[0-9]*(?:\.[0-9]*)?)(?:l|L)([fFeEgG])`)
+
+// Request is a check_nt request.
+type Request struct {
+	// Password is the NSClient password. This may be empty.
+	Password string
+
+	// Command is the requested command.
+	Command Command
+
+	// Args is the collection of optional command arguments.
+	Args []string
+}
+
+// CounterSpec is a performance counter argument as used with the COUNTER
+// command.
+type CounterSpec struct {
+	// Path is the performance counter path (e.g.,
+	// "\\Processor(_Total)\\% Processor Time").
+	Path string
+
+	// Format is the optional printf-style format used to describe the
+	// counter value.
+	Format string
+}
+
+// Client queries an NSClient++ listener using the check_nt protocol.
+type Client struct {
+	// address is the NSClient listener address in host:port format.
+	address string
+
+	// password is the NSClient password.
+	password string
+
+	// timeout limits the time spent connecting to and communicating with
+	// the NSClient listener.
+	timeout time.Duration
+
+	// dialer is used to establish connections to the NSClient listener.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given NSClient listener address
+// and password. If a port is not included in the address the default port
+// is used.
+func NewClient(address string, password string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address:  address,
+		password: password,
+		timeout:  defaultTimeout,
+	}
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with the NSClient listener. Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// Query sends the given command and arguments to the NSClient listener and
+// returns the response values. An error wrapping ErrErrorResponse is
+// returned if the listener responds with an error.
+func (c *Client) Query(ctx context.Context, cmd Command, args ...string) ([]string, error) {
+	if c.address == "" {
+		return nil, ErrMissingAddress
+	}
+
+	req, err := EncodeRequest(Request{Password: c.password, Command: cmd, Args: args})
+	if err != nil {
+		return nil, err
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, "tcp", c.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NSClient listener: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("failed to set NSClient connection deadline: %w", err)
+	}
+
+	if _, err := io.WriteString(conn, req); err != nil {
+		return nil, fmt.Errorf("failed to send check_nt request: %w", err)
+	}
+
+	// The listener closes the connection once the response is sent.
+	resp, err := io.ReadAll(io.LimitReader(conn, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read check_nt response: %w", err)
+	}
+
+	return ParseResponse(string(resp))
+}
+
+// EncodeRequest returns the given request in check_nt format. An error is
+// returned if the password or an argument contains the separator.
+func EncodeRequest(req Request) (string, error) {
+	if req.Command < CommandClientVersion {
+		return "", fmt.Errorf("command %d: %w", req.Command, ErrInvalidRequest)
+	}
+
+	fields := make([]string, 0, len(req.Args)+2)
+	fields = append(fields, req.Password, strconv.Itoa(int(req.Command)))
+	fields = append(fields, req.Args...)
+
+	for i, field := range fields {
+		if i != 1 && strings.Contains(field, Separator) {
+			return "", fmt.Errorf(
+				"value %q contains separator %q: %w",
+				field, Separator, ErrInvalidRequest,
+			)
+		}
+	}
+
+	return strings.Join(fields, Separator), nil
+}
+
+// ParseRequest parses the given check_nt request. This is intended for use
+// by replacement listeners answering queries from check_nt.
+func ParseRequest(req string) (Request, error) {
+	fields := strings.Split(strings.TrimRight(req, "\r\n\x00"), Separator)
+	if len(fields) < 2 {
+		return Request{}, fmt.Errorf("missing command: %w", ErrInvalidRequest)
+	}
+
+	cmd, err := strconv.Atoi(fields[1])
+	if err != nil || cmd < int(CommandClientVersion) {
+		return Request{}, fmt.Errorf("command %q: %w", fields[1], ErrInvalidRequest)
+	}
+
+	parsed := Request{
+		Password: fields[0],
+		Command:  Command(cmd),
+	}
+
+	if len(fields) > 2 {
+		parsed.Args = fields[2:]
+	}
+
+	return parsed, nil
+}
+
+// EncodeResponse returns the given values as a check_nt response.
+func EncodeResponse(values ...string) string {
+	return strings.Join(values, Separator)
+}
+
+// EncodeErrorResponse returns the given message as a check_nt error
+// response.
+func EncodeErrorResponse(msg string) string {
+	return ErrorPrefix + ": " + msg
+}
+
+// FormatFloats returns the given numeric values as a check_nt response.
+// Values are formatted without exponents and without trailing zeros.
+func FormatFloats(values ...float64) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return EncodeResponse(formatted...)
+}
+
+// ParseResponse returns the values of the given check_nt response. An error
+// wrapping ErrErrorResponse is returned for error responses.
+func ParseResponse(resp string) ([]string, error) {
+	resp = strings.TrimRight(resp, "\r\n\x00")
+
+	switch {
+	case strings.TrimSpace(resp) == "":
+		return nil, ErrEmptyResponse
+	case strings.HasPrefix(resp, ErrorPrefix):
+		msg := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(resp, ErrorPrefix), ":"))
+		return nil, fmt.Errorf("%s: %w", msg, ErrErrorResponse)
+	}
+
+	return strings.Split(resp, Separator), nil
+}
+
+// ParseFloats converts the given response values (e.g., as returned by
+// ParseResponse) to numeric values.
+func ParseFloats(values []string) ([]float64, error) {
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q: %w", value, ErrInvalidValue)
+		}
+		floats[i] = f
+	}
+
+	return floats, nil
+}
+
+// ParseCounterSpec parses the given COUNTER command argument. The counter
+// path is separated from the optional format by the first comma.
+func ParseCounterSpec(spec string) CounterSpec {
+	path, format, _ := strings.Cut(spec, ",")
+
+	return CounterSpec{
+		Path:   path,
+		Format: format,
+	}
+}
+
+// String returns the COUNTER command argument for the counter spec.
+func (cs CounterSpec) String() string {
+	if cs.Format == "" {
+		return cs.Path
+	}
+
+	return cs.Path + "," + cs.Format
+}
+
+// FormatValue returns the given counter value formatted using the counter spec
+// format (or DefaultCounterFormat if not set). C printf integer verbs and
+// length modifiers are converted so that formats used with check_nt
+// produce the same result.
+func (cs CounterSpec) FormatValue(value float64) string {
+	format := cs.Format
+	if format == "" {
+		format = DefaultCounterFormat
+	}
+
+	format = integerVerb.ReplaceAllString(format, "%${1}.0f")
+	format = floatLengthModifier.ReplaceAllString(format, "%${1}${2}")
+
+	// Formats without a verb are used as-is.
+	if !strings.Contains(strings.ReplaceAll(format, "%%", ""), "%") {
+		return strings.ReplaceAll(format, "%%", "%")
+	}
+
+	return fmt.Sprintf(format, value)
+}
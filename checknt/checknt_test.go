@@ -0,0 +1,208 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package checknt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeListener accepts a single connection, records the received request
+// and responds with the given response before closing the connection.
+func fakeListener(t *testing.T, response string) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	requests := make(chan string, 1)
+
+	go func() {
+		defer close(requests)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		buf := make([]byte, 1024)
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Errorf("ERROR: failed to read request: %v", err)
+			return
+		}
+		requests <- string(buf[:n])
+
+		_, _ = conn.Write([]byte(response))
+	}()
+
+	return listener.Addr().String(), requests
+}
+
+func TestClient_Query_ReturnsResponseValues(t *testing.T) {
+	t.Parallel()
+
+	addr, requests := fakeListener(t, "52428800&104857600")
+
+	client := NewClient(addr, "secret")
+
+	values, err := client.Query(context.Background(), CommandUsedDiskSpace, "C")
+	if err != nil {
+		t.Fatalf("ERROR: unexpected query failure: %v", err)
+	}
+
+	if got := <-requests; got != "secret&4&C" {
+		t.Errorf("ERROR: unexpected request %q", got)
+	}
+
+	sizes, err := ParseFloats(values)
+	if err != nil {
+		t.Fatalf("ERROR: failed to parse values: %v", err)
+	}
+
+	if d := cmp.Diff([]float64{52428800, 104857600}, sizes); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: query response parsed as expected")
+}
+
+func TestClient_Query_ReportsErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := fakeListener(t, "ERROR: Invalid password.")
+
+	client := NewClient(addr, "wrong")
+
+	_, err := client.Query(context.Background(), CommandClientVersion)
+	if !errors.Is(err, ErrErrorResponse) {
+		t.Fatalf("ERROR: want %v, got %v", ErrErrorResponse, err)
+	}
+
+	if err.Error() != "Invalid password.: check_nt error response" {
+		t.Errorf("ERROR: unexpected error message %q", err.Error())
+	}
+
+	t.Log("OK: error response reported as expected")
+}
+
+func TestParseRequest_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	want := Request{
+		Password: "secret",
+		Command:  CommandCPULoad,
+		Args:     []string{"5", "80", "90", "15", "70", "80"},
+	}
+
+	encoded, err := EncodeRequest(want)
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode request: %v", err)
+	}
+
+	if encoded != "secret&2&5&80&90&15&70&80" {
+		t.Errorf("ERROR: unexpected encoded request %q", encoded)
+	}
+
+	got, err := ParseRequest(encoded)
+	if err != nil {
+		t.Fatalf("ERROR: failed to parse request: %v", err)
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: request round trip successful")
+}
+
+func TestEncodeRequest_RejectsSeparatorInValues(t *testing.T) {
+	t.Parallel()
+
+	_, err := EncodeRequest(Request{Command: CommandServiceState, Args: []string{"a&b"}})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidRequest, err)
+	}
+
+	if _, err := ParseRequest("secret&x"); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidRequest, err)
+	}
+
+	t.Log("OK: invalid requests rejected as expected")
+}
+
+func TestFormatFloats(t *testing.T) {
+	t.Parallel()
+
+	if got := FormatFloats(12, 7.5, 0.25); got != "12&7.5&0.25" {
+		t.Fatalf("ERROR: unexpected response %q", got)
+	}
+
+	if got := EncodeErrorResponse("Unknown command"); got != "ERROR: Unknown command" {
+		t.Fatalf("ERROR: unexpected error response %q", got)
+	}
+
+	t.Log("OK: responses formatted as expected")
+}
+
+func TestCounterSpec_FormatValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		spec string
+		want string
+	}{
+		"default format": {
+			spec: `\Processor(_Total)\% Processor Time`,
+			want: "42",
+		},
+		"float format": {
+			spec: `\Processor(_Total)\% Processor Time,CPU is %.2f %%`,
+			want: "CPU is 41.75 %",
+		},
+		"C length modifier": {
+			spec: `\Memory\Available Bytes,Available: %lf`,
+			want: "Available: 41.750000",
+		},
+		"C integer verb": {
+			spec: `\System\Processes,Processes: %lu`,
+			want: "Processes: 42",
+		},
+		"format without verb": {
+			spec: `\System\Processes,100%% fine`,
+			want: "100% fine",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cs := ParseCounterSpec(tt.spec)
+
+			if cs.String() != tt.spec {
+				t.Errorf("ERROR: want spec %q, got %q", tt.spec, cs.String())
+			}
+
+			if got := cs.FormatValue(41.75); got != tt.want {
+				t.Errorf("ERROR: want %q, got %q", tt.want, got)
+			} else {
+				t.Logf("OK: %q", got)
+			}
+		})
+	}
+}
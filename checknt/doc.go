@@ -0,0 +1,43 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package checknt provides helpers for the legacy check_nt (NSClient/NSClient++)
+request and response conventions.
+
+# OVERVIEW
+
+The check_nt plugin queries the NSClient compatible listener of NSClient++
+(TCP port 12489 by default). Requests consist of the password, numeric
+command and optional arguments separated by "&" characters; responses are a
+single value or a list of values using the same separator. For example:
+
+	request:  secret&4&C         (USEDDISKSPACE for drive C)
+	response: 52428800&104857600 (free and total bytes)
+
+Helpers are provided to encode and parse requests and responses so that
+replacement plugins can interoperate with existing NSClient++ deployments
+during a migration and so that replacement listeners can answer queries
+from existing check_nt service definitions.
+
+Performance counter arguments may include an optional printf-style format
+string (e.g., "\Processor(_Total)\% Processor Time,CPU is %.f %%") which
+is supported via ParseCounterSpec and CounterSpec.FormatValue.
+
+# HOW TO USE
+
+	client := checknt.NewClient("winhost.example.com", "secret")
+
+	values, err := client.Query(ctx, checknt.CommandUsedDiskSpace, "C")
+	if err != nil {
+		// handle error
+	}
+
+	// values[0] is the free space and values[1] the total size in bytes.
+	sizes, err := checknt.ParseFloats(values)
+*/
+package checknt
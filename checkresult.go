@@ -0,0 +1,133 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+// CheckResult is a structured representation of plugin results independent
+// of a Plugin value and of emission. CheckResult values may be queued,
+// compared, transported using the check result JSON format (see
+// EncodeCheckResultJSON) and emitted using a CheckResultEmitter.
+//
+// See Plugin.Snapshot and Plugin.SetCheckResult to convert between Plugin
+// and CheckResult values.
+//
+// Plugin does not embed CheckResult. Embedding would break existing
+// composite literals which set the exported result fields of Plugin (e.g.,
+// Plugin{ExitStatusCode: ...}) and the PerfData field would clash with the
+// existing Plugin.PerfData method.
+type CheckResult struct {
+	// ExitStatusCode is the exit code indicating the state of the service
+	// or host.
+	ExitStatusCode int
+
+	// ServiceOutput is the one-line summary.
+	ServiceOutput string
+
+	// LongServiceOutput is the detailed output.
+	LongServiceOutput string
+
+	// Errors is the collection of recorded errors.
+	Errors []error
+
+	// WarningThreshold is the value used to determine when the service
+	// check has crossed the WARNING threshold.
+	WarningThreshold string
+
+	// CriticalThreshold is the value used to determine when the service
+	// check has crossed the CRITICAL threshold.
+	CriticalThreshold string
+
+	// EncodedPayload is the encoded payload (including delimiters) if
+	// present. See DecodePayload and ExtractAndDecodePayload.
+	EncodedPayload string
+
+	// PerfData is the collection of performance data metrics.
+	PerfData []PerformanceData
+}
+
+// Snapshot returns the current plugin state as a CheckResult. Collected
+// errors and performance data are copied and any payload content is encoded
+// using the configured delimiters. The default time metric and plugin
+// output size metric are not included.
+//
+// The plugin state is not modified; the plugin timeout (if armed) remains
+// armed. If the deprecated LastError field is set the check result reflects
+// the value as if it had been recorded using AddError.
+func (p *Plugin) Snapshot() CheckResult {
+	cr := CheckResult{
+		ExitStatusCode:    p.ExitStatusCode,
+		ServiceOutput:     p.ServiceOutput,
+		LongServiceOutput: p.LongServiceOutput,
+		WarningThreshold:  p.WarningThreshold,
+		CriticalThreshold: p.CriticalThreshold,
+		PerfData:          p.PerfData(),
+	}
+
+	if len(p.Errors) > 0 || p.LastError != nil {
+		cr.Errors = make([]error, 0, len(p.Errors)+1)
+		if p.LastError != nil {
+			cr.Errors = append(cr.Errors, p.LastError)
+			if exitCode, ok := p.errorExitCode(p.LastError); ok {
+				cr.ExitStatusCode = p.escalatedExitState(cr.ExitStatusCode, exitCode)
+			}
+		}
+		cr.Errors = append(cr.Errors, p.Errors...)
+	}
+
+	switch {
+	case p.encodedPayloadBuffer.Len() > 0:
+		compressedBuffer := getPayloadBuffer()
+		defer putPayloadBuffer(compressedBuffer)
+
+		cr.EncodedPayload = encodeASCII85(
+			p.compressPayloadBufferOrFallback(compressedBuffer),
+			p.getEncodedPayloadDelimiterLeft(),
+			p.getEncodedPayloadDelimiterRight(),
+		)
+
+	case p.preEncodedPayload != "":
+		cr.EncodedPayload = p.preEncodedPayload
+	}
+
+	return cr
+}
+
+// SetCheckResult replaces the plugin results (exit code, output, errors,
+// thresholds, performance data and payload) with those of the given check
+// result. The encoded payload of the check result (if any) is emitted as-is
+// in place of the payload buffer content.
+//
+// This allows a check result built or received elsewhere (e.g., decoded
+// using DecodeCheckResultJSON) to be emitted using ReturnCheckResults.
+func (p *Plugin) SetCheckResult(cr CheckResult) {
+	p.logAction("Replacing plugin results with given check result")
+
+	p.ExitStatusCode = cr.ExitStatusCode
+	p.ServiceOutput = cr.ServiceOutput
+	p.LongServiceOutput = cr.LongServiceOutput
+	p.WarningThreshold = cr.WarningThreshold
+	p.CriticalThreshold = cr.CriticalThreshold
+
+	// Avoid sharing the backing array of the errors collection with the
+	// check result; errors may be appended.
+	p.LastError = nil
+	p.Errors = nil
+	if len(cr.Errors) > 0 {
+		p.Errors = make([]error, len(cr.Errors))
+		copy(p.Errors, cr.Errors)
+	}
+
+	p.perfData = perfDataCollection{}
+	p.perfData.reserve(len(cr.PerfData))
+	for _, pd := range cr.PerfData {
+		p.perfData.set(pd)
+	}
+
+	p.encodedPayloadBuffer.Reset()
+	p.payloadSizeReported = 0
+	p.preEncodedPayload = cr.EncodedPayload
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+)
+
+// CheckResultBuilder constructs CheckResult values independently of a
+// Plugin value used to return check results. This allows check results to
+// be built (e.g., by worker goroutines) and later handed to a
+// CheckResultEmitter for emission.
+//
+// A CheckResultBuilder is not safe for concurrent use; use a separate
+// builder for each goroutine. Each CheckResult returned by Build is
+// independent of the builder and of other results; later changes made using
+// the builder are not reflected in previously built results.
+type CheckResultBuilder struct {
+	plugin *Plugin
+}
+
+// NewCheckResultBuilder returns a builder for a check result with an OK
+// state.
+func NewCheckResultBuilder() *CheckResultBuilder {
+	return &CheckResultBuilder{
+		plugin: &Plugin{
+			ExitStatusCode: StateOKExitCode,
+		},
+	}
+}
+
+// WithState sets the exit code (e.g., StateWARNINGExitCode) of the check
+// result and returns the builder.
+func (b *CheckResultBuilder) WithState(exitCode int) *CheckResultBuilder {
+	b.plugin.ExitStatusCode = exitCode
+
+	return b
+}
+
+// WithServiceOutput sets the one-line summary of the check result and
+// returns the builder.
+func (b *CheckResultBuilder) WithServiceOutput(output string) *CheckResultBuilder {
+	b.plugin.ServiceOutput = output
+
+	return b
+}
+
+// WithServiceOutputf formats the one-line summary of the check result
+// according to the given format specifier and returns the builder.
+func (b *CheckResultBuilder) WithServiceOutputf(format string, args ...interface{}) *CheckResultBuilder {
+	b.plugin.ServiceOutput = fmt.Sprintf(format, args...)
+
+	return b
+}
+
+// WithLongServiceOutput sets the detailed output of the check result and
+// returns the builder.
+func (b *CheckResultBuilder) WithLongServiceOutput(output string) *CheckResultBuilder {
+	b.plugin.LongServiceOutput = output
+
+	return b
+}
+
+// WithThresholds sets the WARNING and CRITICAL thresholds of the check
+// result and returns the builder.
+func (b *CheckResultBuilder) WithThresholds(warning string, critical string) *CheckResultBuilder {
+	b.plugin.WarningThreshold = warning
+	b.plugin.CriticalThreshold = critical
+
+	return b
+}
+
+// WithError appends the given errors to the check result and returns the
+// builder. As with Plugin.AddError, the state of a given ServiceCheckError
+// is used to escalate the exit code of the check result.
+func (b *CheckResultBuilder) WithError(errs ...error) *CheckResultBuilder {
+	b.plugin.AddError(errs...)
+
+	return b
+}
+
+// WithPerfData adds the given performance data metrics to the check result
+// and returns the builder. Metrics replace previously added metrics with the
+// same (case-insensitive) label. Metrics are validated; if validation fails
+// no metrics are added and the validation error is recorded as an error of
+// the check result.
+func (b *CheckResultBuilder) WithPerfData(perfData ...PerformanceData) *CheckResultBuilder {
+	b.plugin.WithPerfData(perfData...)
+
+	return b
+}
+
+// WithPayloadString sets the payload content of the check result and
+// returns the builder. The payload content is encoded using the default
+// delimiters when the check result is built. If the payload content could
+// not be set the error is recorded as an error of the check result.
+func (b *CheckResultBuilder) WithPayloadString(input string) *CheckResultBuilder {
+	b.plugin.WithPayloadString(input)
+
+	return b
+}
+
+// Build returns the check result. Errors and performance data metrics are
+// copied and performance data metrics are sorted by label.
+func (b *CheckResultBuilder) Build() CheckResult {
+	return b.plugin.Snapshot()
+}
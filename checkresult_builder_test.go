@@ -0,0 +1,138 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckResultBuilder_BuildsIndependentResults(t *testing.T) {
+	t.Parallel()
+
+	builder := nagios.NewCheckResultBuilder().
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutputf("WARNING: %d widgets", 91).
+		WithLongServiceOutput("details").
+		WithThresholds("80", "90").
+		WithPerfData(
+			nagios.PerformanceData{Label: "widgets", Value: "91"},
+			nagios.PerformanceData{Label: "gadgets", Value: "3"},
+		)
+
+	first := builder.Build()
+
+	builder.WithState(nagios.StateOKExitCode).
+		WithServiceOutput("OK").
+		WithPerfData(nagios.PerformanceData{Label: "widgets", Value: "1"})
+
+	want := nagios.CheckResult{
+		ExitStatusCode:    nagios.StateWARNINGExitCode,
+		ServiceOutput:     "WARNING: 91 widgets",
+		LongServiceOutput: "details",
+		WarningThreshold:  "80",
+		CriticalThreshold: "90",
+		PerfData: []nagios.PerformanceData{
+			{Label: "gadgets", Value: "3"},
+			{Label: "widgets", Value: "91"},
+		},
+	}
+
+	if d := cmp.Diff(want, first); d != "" {
+		t.Fatalf("ERROR: built check result changed by later builder use (-want, +got)\n:%s", d)
+	}
+
+	second := builder.Build()
+	if second.ExitStatusCode != nagios.StateOKExitCode || second.PerfData[1].Value != "1" {
+		t.Fatalf("ERROR: later check result does not reflect builder changes: %+v", second)
+	}
+
+	t.Log("OK: built check results are independent of builder")
+}
+
+func TestCheckResultBuilder_RecordsErrors(t *testing.T) {
+	t.Parallel()
+
+	cr := nagios.NewCheckResultBuilder().
+		WithError(nagios.NewServiceCheckError(nagios.ServiceState{
+			Label:    nagios.StateCRITICALLabel,
+			ExitCode: nagios.StateCRITICALExitCode,
+		}, "widget offline")).
+		WithPerfData(nagios.PerformanceData{Label: "invalid"}).
+		Build()
+
+	switch {
+	case cr.ExitStatusCode != nagios.StateCRITICALExitCode:
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, cr.ExitStatusCode)
+	case len(cr.Errors) != 2:
+		t.Fatalf("ERROR: want 2 errors, got %d: %v", len(cr.Errors), cr.Errors)
+	case !errors.Is(cr.Errors[1], nagios.ErrInvalidPerformanceDataFormat):
+		t.Fatalf("ERROR: want error %v, got %v", nagios.ErrInvalidPerformanceDataFormat, cr.Errors[1])
+	case len(cr.PerfData) != 0:
+		t.Fatalf("ERROR: want no performance data, got %v", cr.PerfData)
+	}
+
+	t.Log("OK: errors recorded as expected")
+}
+
+func TestCheckResultBuilder_EncodesPayload(t *testing.T) {
+	t.Parallel()
+
+	cr := nagios.NewCheckResultBuilder().
+		WithServiceOutput("OK: payload attached").
+		WithPayloadString(`{"widgets":91}`).
+		Build()
+
+	decoded, err := nagios.ExtractAndDecodePayload(cr.Output(), "", nagios.DefaultASCII85EncodingDelimiterLeft, nagios.DefaultASCII85EncodingDelimiterRight)
+	if err != nil {
+		t.Fatalf("ERROR: failed to decode payload from rendered output: %v", err)
+	}
+
+	if d := cmp.Diff(`{"widgets":91}`, decoded); d != "" {
+		t.Fatalf("ERROR: decoded payload mismatch (-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: payload encoded and emitted as expected")
+}
+
+func TestCheckResultBuilder_ConcurrentBuilders(t *testing.T) {
+	t.Parallel()
+
+	const workers = 8
+
+	results := make([]nagios.CheckResult, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = nagios.NewCheckResultBuilder().
+				WithServiceOutputf("OK: worker %d", i).
+				WithPerfData(nagios.PerformanceData{Label: "worker", Value: "1"}).
+				Build()
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, cr := range results {
+		if want := fmt.Sprintf("OK: worker %d", i); cr.ServiceOutput != want {
+			t.Errorf("ERROR: want service output %q, got %q", want, cr.ServiceOutput)
+		}
+	}
+
+	t.Log("OK: check results built concurrently")
+}
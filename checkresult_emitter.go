@@ -0,0 +1,148 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// CheckResultEmitter is implemented by types able to emit a check result
+// (e.g., by writing plugin output or submitting a passive check result).
+type CheckResultEmitter interface {
+	EmitCheckResult(ctx context.Context, cr CheckResult) error
+}
+
+// CheckResultEmitterFunc is an adapter allowing an ordinary function to be
+// used as a CheckResultEmitter.
+type CheckResultEmitterFunc func(ctx context.Context, cr CheckResult) error
+
+// EmitCheckResult satisfies the CheckResultEmitter interface.
+func (f CheckResultEmitterFunc) EmitCheckResult(ctx context.Context, cr CheckResult) error {
+	return f(ctx, cr)
+}
+
+// WriterEmitter emits check results as plugin output written to an
+// io.Writer. WriterEmitter is safe for concurrent use; each check result is
+// written using a single call to the writer.
+type WriterEmitter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterEmitter returns an emitter writing plugin output for each check
+// result to the given writer.
+func NewWriterEmitter(w io.Writer) *WriterEmitter {
+	return &WriterEmitter{w: w}
+}
+
+// EmitCheckResult satisfies the CheckResultEmitter interface.
+func (e *WriterEmitter) EmitCheckResult(ctx context.Context, cr CheckResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	output := cr.Output()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := io.WriteString(e.w, output); err != nil {
+		return fmt.Errorf("failed to write check result output: %w", err)
+	}
+
+	return nil
+}
+
+// JSONEmitter emits check results as check result JSON documents (see
+// EncodeCheckResultJSON) written to an io.Writer, one document per line.
+// JSONEmitter is safe for concurrent use.
+type JSONEmitter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONEmitter returns an emitter writing a check result JSON document for
+// each check result to the given writer.
+func NewJSONEmitter(w io.Writer) *JSONEmitter {
+	return &JSONEmitter{w: w}
+}
+
+// EmitCheckResult satisfies the CheckResultEmitter interface.
+func (e *JSONEmitter) EmitCheckResult(ctx context.Context, cr CheckResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := EncodeCheckResultJSON(&cr)
+	if err != nil {
+		return err
+	}
+
+	data = append(data, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := e.w.Write(data); err != nil {
+		return fmt.Errorf("failed to write check result JSON: %w", err)
+	}
+
+	return nil
+}
+
+// PassiveEmitter emits check results as passive check results for a host
+// or service using a PassiveSubmitter (e.g., an NRDP or NSCA client).
+type PassiveEmitter struct {
+	submitter PassiveSubmitter
+	host      string
+	service   string
+}
+
+// NewPassiveEmitter returns an emitter submitting each check result as a
+// passive check result for the given host and service using the given
+// submitter. If service is empty check results are submitted as host check
+// results.
+func NewPassiveEmitter(submitter PassiveSubmitter, host string, service string) *PassiveEmitter {
+	return &PassiveEmitter{
+		submitter: submitter,
+		host:      host,
+		service:   service,
+	}
+}
+
+// EmitCheckResult satisfies the CheckResultEmitter interface.
+func (e *PassiveEmitter) EmitCheckResult(ctx context.Context, cr CheckResult) error {
+	return e.submitter.Submit(ctx, cr.PassiveCheckResult(e.host, e.service))
+}
+
+// Output renders the check result as plugin output in the same format used
+// by Plugin.ReturnCheckResults.
+func (cr CheckResult) Output() string {
+	return cr.newPlugin().assembleOutput()
+}
+
+// PassiveCheckResult renders the check result as a passive check result for
+// the given host and service. If service is empty the result is treated as a
+// host check result and the service check state of the check result is
+// mapped to a host check state (see Plugin.PassiveCheckResult).
+func (cr CheckResult) PassiveCheckResult(host string, service string) PassiveCheckResult {
+	return cr.newPlugin().PassiveCheckResult(host, service)
+}
+
+// newPlugin returns a Plugin value used to render the check result. The
+// Plugin value is not created using the constructor; no plugin timeout is
+// armed and no default time metric is added.
+func (cr CheckResult) newPlugin() *Plugin {
+	var p Plugin
+	p.SetCheckResult(cr)
+
+	return &p
+}
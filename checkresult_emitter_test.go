@@ -0,0 +1,205 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckResult_OutputMatchesPluginOutput(t *testing.T) {
+	t.Parallel()
+
+	var pluginOutput bytes.Buffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&pluginOutput)
+	plugin.SkipOSExit()
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: 91 widgets"
+	plugin.LongServiceOutput = "widget details"
+
+	// Provide a time metric so that output is not dependent on timing.
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "widgets", Value: "91"},
+		nagios.PerformanceData{Label: "time", Value: "1", UnitOfMeasurement: "ms"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	cr := plugin.Snapshot()
+
+	if d := cmp.Diff(pluginOutput.String(), cr.Output()); d != "" {
+		t.Fatalf("ERROR: check result output mismatch (-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: check result output matches plugin output")
+}
+
+func TestWriterEmitter_EmitCheckResult(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	cr := nagios.NewCheckResultBuilder().
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutput("WARNING: 91 widgets").
+		WithLongServiceOutput("widget details").
+		WithPerfData(nagios.PerformanceData{Label: "widgets", Value: "91"}).
+		Build()
+	emitter := nagios.NewWriterEmitter(&output)
+
+	if err := emitter.EmitCheckResult(context.Background(), cr); err != nil {
+		t.Fatalf("ERROR: unexpected error emitting check result: %v", err)
+	}
+
+	if d := cmp.Diff(cr.Output(), output.String()); d != "" {
+		t.Fatalf("ERROR: emitted output mismatch (-want, +got)\n:%s", d)
+	}
+
+	if !strings.Contains(output.String(), "| 'widgets'=91;;;;") {
+		t.Fatalf("ERROR: performance data missing from emitted output:\n%s", output.String())
+	}
+
+	t.Log("OK: check result emitted as plugin output")
+}
+
+func TestWriterEmitter_EmitCheckResult_CanceledContext(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cr := nagios.NewCheckResultBuilder().
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutput("WARNING: 91 widgets").
+		WithLongServiceOutput("widget details").
+		WithPerfData(nagios.PerformanceData{Label: "widgets", Value: "91"}).
+		Build()
+
+	err := nagios.NewWriterEmitter(&output).EmitCheckResult(ctx, cr)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ERROR: want error %v, got %v", context.Canceled, err)
+	}
+
+	if output.Len() != 0 {
+		t.Fatalf("ERROR: want no output, got %q", output.String())
+	}
+
+	t.Log("OK: canceled context prevented emission")
+}
+
+func TestJSONEmitter_EmitCheckResult(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	cr := nagios.NewCheckResultBuilder().
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutput("WARNING: 91 widgets").
+		WithLongServiceOutput("widget details").
+		WithPerfData(nagios.PerformanceData{Label: "widgets", Value: "91"}).
+		Build()
+	emitter := nagios.NewJSONEmitter(&output)
+
+	for i := 0; i < 2; i++ {
+		if err := emitter.EmitCheckResult(context.Background(), cr); err != nil {
+			t.Fatalf("ERROR: unexpected error emitting check result: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ERROR: want 2 JSON documents, got %d:\n%s", len(lines), output.String())
+	}
+
+	decoded, err := nagios.DecodeCheckResultJSON([]byte(lines[1]))
+	if err != nil {
+		t.Fatalf("ERROR: failed to decode emitted JSON: %v", err)
+	}
+
+	if d := cmp.Diff(cr, *decoded); d != "" {
+		t.Fatalf("ERROR: decoded check result mismatch (-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: check result emitted as JSON")
+}
+
+func TestPassiveEmitter_EmitCheckResult(t *testing.T) {
+	t.Parallel()
+
+	submitter := &recordingSubmitter{notify: make(chan struct{}, 1)}
+
+	cr := nagios.NewCheckResultBuilder().
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutput("WARNING: 91 widgets").
+		WithLongServiceOutput("widget details").
+		WithPerfData(nagios.PerformanceData{Label: "widgets", Value: "91"}).
+		Build()
+	emitter := nagios.NewPassiveEmitter(submitter, "web01", "widgets")
+
+	if err := emitter.EmitCheckResult(context.Background(), cr); err != nil {
+		t.Fatalf("ERROR: unexpected error emitting check result: %v", err)
+	}
+
+	if len(submitter.results) != 1 {
+		t.Fatalf("ERROR: want 1 submitted result, got %d", len(submitter.results))
+	}
+
+	got := submitter.results[0]
+
+	switch {
+	case got.HostName != "web01" || got.ServiceDescription != "widgets":
+		t.Fatalf("ERROR: want host web01 and service widgets, got %q and %q", got.HostName, got.ServiceDescription)
+	case got.ExitStatusCode != nagios.StateWARNINGExitCode:
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, got.ExitStatusCode)
+	case got.Output != cr.Output():
+		t.Fatalf("ERROR: want output %q, got %q", cr.Output(), got.Output)
+	}
+
+	t.Log("OK: check result submitted as passive check result")
+}
+
+func TestCheckResultEmitterFunc(t *testing.T) {
+	t.Parallel()
+
+	var got nagios.CheckResult
+
+	var emitter nagios.CheckResultEmitter = nagios.CheckResultEmitterFunc(
+		func(_ context.Context, cr nagios.CheckResult) error {
+			got = cr
+			return nil
+		},
+	)
+
+	cr := nagios.NewCheckResultBuilder().
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutput("WARNING: 91 widgets").
+		WithLongServiceOutput("widget details").
+		WithPerfData(nagios.PerformanceData{Label: "widgets", Value: "91"}).
+		Build()
+	if err := emitter.EmitCheckResult(context.Background(), cr); err != nil {
+		t.Fatalf("ERROR: unexpected error emitting check result: %v", err)
+	}
+
+	if d := cmp.Diff(cr, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: function used as check result emitter")
+}
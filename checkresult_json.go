@@ -0,0 +1,286 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CheckResultJSONVersion is the version of the check result JSON schema
+// produced by EncodeCheckResultJSON.
+const CheckResultJSONVersion int = 1
+
+// gzipHeaderASCII85 is the Ascii85 encoding of the first four bytes of the
+// gzip header (magic number, deflate compression method and no flags)
+// written when compressing payload content. Compressed payload content
+// encoded by this library starts with this sequence (following the left
+// delimiter).
+const gzipHeaderASCII85 string = "+,^C)"
+
+// maxPayloadDelimiterScan is the number of leading bytes of an encoded
+// payload checked for the start of the gzip header; this accommodates
+// custom left delimiters.
+const maxPayloadDelimiterScan int = 16
+
+// checkResultJSON is the check result JSON document.
+type checkResultJSON struct {
+	Version           int                        `json:"version"`
+	State             checkResultStateJSON       `json:"state"`
+	ServiceOutput     string                     `json:"service_output"`
+	LongServiceOutput string                     `json:"long_service_output,omitempty"`
+	Errors            []checkResultErrorJSON     `json:"errors,omitempty"`
+	Thresholds        *checkResultThresholdsJSON `json:"thresholds,omitempty"`
+	PerfData          []checkResultPerfDataJSON  `json:"perfdata,omitempty"`
+	Payload           *checkResultPayloadJSON    `json:"payload,omitempty"`
+}
+
+// checkResultStateJSON is the state object of the check result JSON
+// document.
+type checkResultStateJSON struct {
+	ExitCode *int   `json:"exit_code"`
+	Label    string `json:"label"`
+}
+
+// checkResultErrorJSON is an entry in the errors array of the check result
+// JSON document.
+type checkResultErrorJSON struct {
+	Message  string            `json:"message"`
+	State    string            `json:"state,omitempty"`
+	Hint     string            `json:"hint,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Cause    string            `json:"cause,omitempty"`
+}
+
+// checkResultThresholdsJSON is the thresholds object of the check result
+// JSON document.
+type checkResultThresholdsJSON struct {
+	Warning  string `json:"warning,omitempty"`
+	Critical string `json:"critical,omitempty"`
+}
+
+// checkResultPerfDataJSON is an entry in the perfdata array of the check
+// result JSON document.
+type checkResultPerfDataJSON struct {
+	Label             string `json:"label"`
+	Value             string `json:"value"`
+	UnitOfMeasurement string `json:"uom,omitempty"`
+	Warn              string `json:"warn,omitempty"`
+	Crit              string `json:"crit,omitempty"`
+	Min               string `json:"min,omitempty"`
+	Max               string `json:"max,omitempty"`
+}
+
+// checkResultPayloadJSON is the payload object of the check result JSON
+// document.
+type checkResultPayloadJSON struct {
+	Encoding string `json:"encoding"`
+	Data     string `json:"data"`
+	Length   int    `json:"length"`
+}
+
+// EncodeCheckResultJSON encodes the given check result using the check
+// result JSON schema. This allows results produced by this library to be
+// queued, transported and replayed by other tools without loss.
+//
+// The schema (version 1) is:
+//
+//	{
+//	  "version": 1,
+//	  "state": {"exit_code": 2, "label": "CRITICAL"},
+//	  "service_output": "one-line summary",
+//	  "long_service_output": "detailed output",
+//	  "errors": [
+//	    {
+//	      "message": "error text",
+//	      "state": "CRITICAL",
+//	      "hint": "remediation advice",
+//	      "metadata": {"key": "value"},
+//	      "cause": "underlying error text"
+//	    }
+//	  ],
+//	  "thresholds": {"warning": "...", "critical": "..."},
+//	  "perfdata": [
+//	    {"label": "time", "value": "12", "uom": "ms", "warn": "", "crit": "", "min": "", "max": ""}
+//	  ],
+//	  "payload": {"encoding": "gzip+ascii85", "data": "<~...~>", "length": 123}
+//	}
+//
+// The version, state and service_output fields are always present. Other
+// fields are omitted if empty. The state, hint, metadata and cause error
+// fields are only used for ServiceCheckError values. The payload data is the
+// encoded payload (including delimiters) and length is the size of the data
+// field in bytes. The payload encoding is "gzip+ascii85" if the payload
+// content is gzip compressed before Ascii85 encoding (the default) or
+// "ascii85" if compression was skipped (e.g., due to a compression failure).
+func EncodeCheckResultJSON(cr *CheckResult) ([]byte, error) {
+	if cr == nil {
+		return nil, fmt.Errorf("check result not provided: %w", ErrMissingValue)
+	}
+
+	exitCode := cr.ExitStatusCode
+	doc := checkResultJSON{
+		Version: CheckResultJSONVersion,
+		State: checkResultStateJSON{
+			ExitCode: &exitCode,
+			Label:    ExitCodeToStateLabel(cr.ExitStatusCode),
+		},
+		ServiceOutput:     cr.ServiceOutput,
+		LongServiceOutput: cr.LongServiceOutput,
+	}
+
+	for _, err := range cr.Errors {
+		if err == nil {
+			continue
+		}
+		doc.Errors = append(doc.Errors, newCheckResultErrorJSON(err))
+	}
+
+	if cr.WarningThreshold != "" || cr.CriticalThreshold != "" {
+		doc.Thresholds = &checkResultThresholdsJSON{
+			Warning:  cr.WarningThreshold,
+			Critical: cr.CriticalThreshold,
+		}
+	}
+
+	for _, pd := range cr.PerfData {
+		doc.PerfData = append(doc.PerfData, checkResultPerfDataJSON(pd))
+	}
+
+	if cr.EncodedPayload != "" {
+		doc.Payload = &checkResultPayloadJSON{
+			Encoding: encodedPayloadCodec(cr.EncodedPayload),
+			Data:     cr.EncodedPayload,
+			Length:   len(cr.EncodedPayload),
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode check result as JSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// DecodeCheckResultJSON decodes a check result encoded using the check
+// result JSON schema (see EncodeCheckResultJSON). Errors with ServiceCheckError
+// specific fields are decoded as ServiceCheckError values, all others as
+// plain errors with the recorded message.
+func DecodeCheckResultJSON(data []byte) (*CheckResult, error) {
+	var doc checkResultJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidCheckResultJSON)
+	}
+
+	switch {
+	case doc.Version < 1 || doc.Version > CheckResultJSONVersion:
+		return nil, fmt.Errorf("unsupported schema version %d: %w", doc.Version, ErrInvalidCheckResultJSON)
+	case doc.State.ExitCode == nil:
+		return nil, fmt.Errorf("missing state exit_code: %w", ErrInvalidCheckResultJSON)
+	}
+
+	cr := CheckResult{
+		ExitStatusCode:    *doc.State.ExitCode,
+		ServiceOutput:     doc.ServiceOutput,
+		LongServiceOutput: doc.LongServiceOutput,
+	}
+
+	for _, e := range doc.Errors {
+		cr.Errors = append(cr.Errors, e.toError())
+	}
+
+	if doc.Thresholds != nil {
+		cr.WarningThreshold = doc.Thresholds.Warning
+		cr.CriticalThreshold = doc.Thresholds.Critical
+	}
+
+	for _, pd := range doc.PerfData {
+		cr.PerfData = append(cr.PerfData, PerformanceData(pd))
+	}
+
+	if doc.Payload != nil {
+		if enc := doc.Payload.Encoding; enc != payloadCodecGzipASCII85 && enc != payloadCodecASCII85 {
+			return nil, fmt.Errorf(
+				"unsupported payload encoding %q: %w",
+				doc.Payload.Encoding,
+				ErrInvalidCheckResultJSON,
+			)
+		}
+
+		cr.EncodedPayload = doc.Payload.Data
+	}
+
+	return &cr, nil
+}
+
+// newCheckResultErrorJSON returns the JSON representation of the given
+// error.
+func newCheckResultErrorJSON(err error) checkResultErrorJSON {
+	var sce *ServiceCheckError
+	if !errors.As(err, &sce) || err != error(sce) {
+		return checkResultErrorJSON{Message: err.Error()}
+	}
+
+	e := checkResultErrorJSON{
+		Message:  sce.Message,
+		State:    sce.State.Label,
+		Hint:     sce.Hint,
+		Metadata: sce.Metadata,
+	}
+
+	if sce.Err != nil {
+		e.Cause = sce.Err.Error()
+	}
+
+	return e
+}
+
+// toError returns the error represented by the JSON error entry.
+func (e checkResultErrorJSON) toError() error {
+	if e.State == "" && e.Hint == "" && len(e.Metadata) == 0 && e.Cause == "" {
+		return errors.New(e.Message)
+	}
+
+	sce := ServiceCheckError{
+		Message:  e.Message,
+		Hint:     e.Hint,
+		Metadata: e.Metadata,
+	}
+
+	if e.State != "" {
+		sce.State = ServiceState{
+			Label:    strings.ToUpper(e.State),
+			ExitCode: StateLabelToExitCode(e.State),
+		}
+	}
+
+	if e.Cause != "" {
+		sce.Err = errors.New(e.Cause)
+	}
+
+	return &sce
+}
+
+// encodedPayloadCodec returns the codec used for the given encoded payload
+// (including delimiters); payloadCodecGzipASCII85 if the encoded payload
+// content starts with a gzip header, otherwise payloadCodecASCII85.
+func encodedPayloadCodec(encodedPayload string) string {
+	scan := encodedPayload
+	if len(scan) > maxPayloadDelimiterScan+len(gzipHeaderASCII85) {
+		scan = scan[:maxPayloadDelimiterScan+len(gzipHeaderASCII85)]
+	}
+
+	if strings.Contains(scan, gzipHeaderASCII85) {
+		return payloadCodecGzipASCII85
+	}
+
+	return payloadCodecASCII85
+}
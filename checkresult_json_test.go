@@ -0,0 +1,165 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestCheckResultJSON_RoundTripsPluginSnapshot(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: certificate expires soon"
+	plugin.LongServiceOutput = "expires: 2024-12-01"
+	plugin.WarningThreshold = "30d"
+	plugin.CriticalThreshold = "7d"
+	plugin.AddError(
+		errors.New("OCSP responder unreachable"),
+		&nagios.ServiceCheckError{
+			Message:  "chain incomplete",
+			State:    nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode},
+			Hint:     "install intermediate certificate",
+			Metadata: map[string]string{"host": "web01"},
+			Err:      errors.New("x509: unknown authority"),
+		},
+	)
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "expires", Value: "21", UnitOfMeasurement: "d", Warn: "30", Crit: "7", Min: "0"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+	if _, err := plugin.AddPayloadString(`{"serial":"01"}`); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+
+	snapshot := plugin.Snapshot()
+
+	data, err := nagios.EncodeCheckResultJSON(&snapshot)
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode check result: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"state":{"exit_code":1,"label":"WARNING"}`) {
+		t.Errorf("ERROR: state not encoded as expected: %s", data)
+	}
+
+	got, err := nagios.DecodeCheckResultJSON(data)
+	if err != nil {
+		t.Fatalf("ERROR: failed to decode check result: %v", err)
+	}
+
+	errorText := cmp.Comparer(func(a, b error) bool { return a.Error() == b.Error() })
+	if d := cmp.Diff(&snapshot, got, errorText); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	var sce *nagios.ServiceCheckError
+	if !errors.As(got.Errors[1], &sce) ||
+		sce.State.ExitCode != nagios.StateWARNINGExitCode ||
+		sce.Hint != "install intermediate certificate" ||
+		sce.Metadata["host"] != "web01" {
+		t.Errorf("ERROR: ServiceCheckError fields not decoded: %#v", got.Errors[1])
+	}
+
+	payload, err := nagios.ExtractAndDecodePayload(
+		got.EncodedPayload,
+		"",
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+	if err != nil || payload != `{"serial":"01"}` {
+		t.Fatalf("ERROR: unexpected payload %q (%v)", payload, err)
+	}
+
+	t.Log("OK: check result round-tripped through JSON as expected")
+}
+
+func TestDecodeCheckResultJSON_RejectsInvalidDocuments(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"malformed":           `{"version":`,
+		"unsupported version": `{"version":2,"state":{"exit_code":0,"label":"OK"},"service_output":"OK"}`,
+		"missing exit code":   `{"version":1,"state":{"label":"OK"},"service_output":"OK"}`,
+		"unsupported payload": `{"version":1,"state":{"exit_code":0},"service_output":"OK","payload":{"encoding":"base64","data":"e30="}}`,
+	}
+
+	for name, input := range tests {
+		input := input
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := nagios.DecodeCheckResultJSON([]byte(input)); !errors.Is(err, nagios.ErrInvalidCheckResultJSON) {
+				t.Fatalf("ERROR: want %v, got %v", nagios.ErrInvalidCheckResultJSON, err)
+			}
+
+			t.Log("OK: invalid document rejected as expected")
+		})
+	}
+}
+
+func TestEncodeCheckResultJSON_ReportsPayloadCodec(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetEncodedPayloadDelimiterLeft("BEGIN-PAYLOAD")
+
+	if _, err := plugin.SetPayloadString("compressed payload"); err != nil {
+		t.Fatalf("ERROR: failed to set payload: %v", err)
+	}
+
+	tests := map[string]struct {
+		result       nagios.CheckResult
+		wantEncoding string
+	}{
+		"compressed payload": {
+			result:       plugin.Snapshot(),
+			wantEncoding: `"encoding":"gzip+ascii85"`,
+		},
+		"uncompressed payload": {
+			result:       nagios.CheckResult{EncodedPayload: "<~E,8rsDBNn,H#.D-A,~>"},
+			wantEncoding: `"encoding":"ascii85"`,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := nagios.EncodeCheckResultJSON(&tt.result)
+			if err != nil {
+				t.Fatalf("ERROR: failed to encode check result: %v", err)
+			}
+
+			if !strings.Contains(string(data), tt.wantEncoding) {
+				t.Fatalf("ERROR: want %s in document, got %s", tt.wantEncoding, data)
+			}
+
+			decoded, err := nagios.DecodeCheckResultJSON(data)
+			if err != nil {
+				t.Fatalf("ERROR: failed to decode check result: %v", err)
+			}
+
+			if d := cmp.Diff(tt.result.EncodedPayload, decoded.EncodedPayload); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Log("OK: payload codec reported as expected")
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: 91 widgets"
+	plugin.WarningThreshold = "80"
+
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "widgets", Value: "91"}); err != nil {
+		t.Fatalf("ERROR: failed to add perfdata: %v", err)
+	}
+
+	want := nagios.CheckResult{
+		ExitStatusCode:   nagios.StateWARNINGExitCode,
+		ServiceOutput:    "WARNING: 91 widgets",
+		WarningThreshold: "80",
+		PerfData:         []nagios.PerformanceData{{Label: "widgets", Value: "91"}},
+	}
+
+	if d := cmp.Diff(want, plugin.Snapshot()); d != "" {
+		t.Fatalf("ERROR: check result mismatch (-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: plugin state converted to check result")
+}
+
+func TestPlugin_Snapshot_DoesNotModifyPluginState(t *testing.T) {
+	t.Parallel()
+
+	lastErr := nagios.CriticalError(errors.New("widget offline"))
+
+	plugin := nagios.NewPlugin()
+	plugin.ServiceOutput = "OK: widgets"
+	plugin.LastError = lastErr
+	plugin.AddError(nagios.ErrPanicDetected)
+
+	cr := plugin.Snapshot()
+
+	if cr.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want check result exit code %d, got %d", nagios.StateCRITICALExitCode, cr.ExitStatusCode)
+	}
+
+	if len(cr.Errors) != 2 || cr.Errors[0] != lastErr {
+		t.Errorf("ERROR: want LastError value followed by recorded error, got %v", cr.Errors)
+	}
+
+	if plugin.ExitStatusCode != nagios.StateOKExitCode {
+		t.Errorf("ERROR: want plugin exit code %d, got %d", nagios.StateOKExitCode, plugin.ExitStatusCode)
+	}
+
+	if plugin.LastError != lastErr || len(plugin.Errors) != 1 {
+		t.Errorf("ERROR: plugin errors modified: LastError %v, Errors %v", plugin.LastError, plugin.Errors)
+	}
+
+	t.Log("OK: check result snapshot does not modify plugin state")
+}
+
+func TestPlugin_SetCheckResult_EmitsTransportedResult(t *testing.T) {
+	t.Parallel()
+
+	source := nagios.NewPlugin()
+	source.ExitStatusCode = nagios.StateCRITICALExitCode
+	source.ServiceOutput = "CRITICAL: widget offline"
+	source.LongServiceOutput = "widget details"
+	source.AddError(nagios.ErrPanicDetected)
+
+	if err := source.AddPerfData(false, nagios.PerformanceData{Label: "widgets", Value: "0"}); err != nil {
+		t.Fatalf("ERROR: failed to add perfdata: %v", err)
+	}
+
+	if _, err := source.SetPayloadString(`{"widgets":0}`); err != nil {
+		t.Fatalf("ERROR: failed to set payload: %v", err)
+	}
+
+	// Transport the check result using the JSON interchange format.
+	snapshot := source.Snapshot()
+
+	data, err := nagios.EncodeCheckResultJSON(&snapshot)
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode check result: %v", err)
+	}
+
+	cr, err := nagios.DecodeCheckResultJSON(data)
+	if err != nil {
+		t.Fatalf("ERROR: failed to decode check result: %v", err)
+	}
+
+	var output strings.Builder
+
+	// Setup Plugin value manually so that no default time metric is added.
+	var plugin nagios.Plugin
+	plugin.SetOutputTarget(&output)
+	plugin.SkipOSExit()
+
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "replaced", Value: "1"}); err != nil {
+		t.Fatalf("ERROR: failed to add perfdata: %v", err)
+	}
+
+	plugin.SetCheckResult(*cr)
+	plugin.ReturnCheckResults()
+
+	if d := cmp.Diff(cr.Output(), output.String()); d != "" {
+		t.Fatalf("ERROR: emitted output mismatch (-want, +got)\n:%s", d)
+	}
+
+	for _, want := range []string{"'widgets'=0;;;;", "<~", "~>"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("ERROR: want %q in output, got:\n%s", want, output.String())
+		}
+	}
+
+	if strings.Contains(output.String(), "replaced") {
+		t.Errorf("ERROR: want previous performance data replaced, got:\n%s", output.String())
+	}
+
+	t.Log("OK: transported check result emitted as expected")
+}
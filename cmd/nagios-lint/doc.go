@@ -0,0 +1,49 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Nagios-lint checks plugin output against the Nagios plugin guidelines using
+nagios.LintOutput and reports any issues found.
+
+Plugin output is read from a file, from standard input or by executing a
+plugin command. When executing a plugin, the exit code is also checked.
+
+Usage:
+
+	nagios-lint [flags] [-- plugin [args...]]
+
+Examples:
+
+	nagios-lint -file output.txt
+	./check_disk -w 20% -c 10% | nagios-lint
+	nagios-lint -strict -- ./check_disk -w 20% -c 10%
+	nagios-lint -preview -preview-limit 1024 -file output.txt
+
+Flags:
+
+	-file path
+		Read plugin output from the given file ("-" for standard input,
+		the default if no plugin command is given).
+	-strict
+		Treat warnings as failures.
+	-timeout duration
+		Maximum plugin execution time (default 30s).
+	-preview
+		Show the output as displayed by the Nagios web UI and used in
+		notifications (see nagios.PreviewOutput) after any issues. The
+		preview does not affect the exit code.
+	-preview-limit bytes
+		Maximum plugin output length applied by -preview (e.g., 1024 for
+		plugins executed via NRPE v2). Defaults to the Nagios Core limit.
+
+The exit code is suitable for use in CI pipelines:
+
+	0  no issues (or warnings only without -strict)
+	1  lint failures found
+	2  invalid usage or plugin output could not be obtained
+*/
+package main
@@ -0,0 +1,170 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Exit codes used by this tool.
+const (
+	exitOK      int = 0
+	exitFailure int = 1
+	exitUsage   int = 2
+)
+
+const (
+	// defaultTimeout is the default maximum plugin execution time.
+	defaultTimeout time.Duration = 30 * time.Second
+
+	// lintRuleExitCode is the lint rule reporting unsupported plugin exit
+	// codes.
+	lintRuleExitCode string = "exit-code"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run lints plugin output as directed by the given command-line arguments
+// and returns the exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("nagios-lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	file := flags.String("file", "", `read plugin output from the given file ("-" for standard input)`)
+	strict := flags.Bool("strict", false, "treat warnings as failures")
+	timeout := flags.Duration("timeout", defaultTimeout, "maximum plugin execution time")
+	preview := flags.Bool("preview", false, "show the output as displayed by Nagios after any issues")
+	previewLimit := flags.Int("preview-limit", 0, "maximum plugin output length (in bytes) applied by -preview (default: Nagios Core limit)")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	command := flags.Args()
+
+	var output string
+	var issues []nagios.LintIssue
+
+	switch {
+	case len(command) > 0 && *file != "":
+		fmt.Fprintln(stderr, "specify either -file or a plugin command, not both")
+
+		return exitUsage
+
+	case len(command) > 0:
+		out, exitCode, err := execPlugin(command, *timeout)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to execute plugin: %v\n", err)
+
+			return exitUsage
+		}
+
+		output = out
+		issues = append(issues, lintExitCode(exitCode)...)
+
+	default:
+		out, err := readOutput(*file, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to read plugin output: %v\n", err)
+
+			return exitUsage
+		}
+
+		output = out
+	}
+
+	issues = append(nagios.LintOutput(output), issues...)
+
+	exitCode := exitOK
+	for _, issue := range issues {
+		fmt.Fprintln(stdout, issue)
+
+		if issue.Severity == nagios.LintSeverityError || *strict {
+			exitCode = exitFailure
+		}
+	}
+
+	if *preview {
+		if len(issues) > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprint(stdout, nagios.PreviewOutputWithLimit(output, *previewLimit))
+	}
+
+	return exitCode
+}
+
+// readOutput returns plugin output read from the given file or from the given
+// reader if the file is empty or "-".
+func readOutput(file string, stdin io.Reader) (string, error) {
+	if file == "" || file == "-" {
+		data, err := io.ReadAll(stdin)
+
+		return string(data), err
+	}
+
+	data, err := os.ReadFile(file)
+
+	return string(data), err
+}
+
+// execPlugin executes the given plugin command and returns its standard
+// output and exit code.
+func execPlugin(command []string, timeout time.Duration) (string, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) // #nosec G204 -- command provided by user
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case ctx.Err() != nil:
+		return "", 0, fmt.Errorf("plugin timeout of %s reached: %w", timeout, ctx.Err())
+	case errors.As(err, &exitErr):
+		return out.String(), exitErr.ExitCode(), nil
+	case err != nil:
+		return "", 0, err
+	default:
+		return out.String(), 0, nil
+	}
+}
+
+// lintExitCode checks that the given plugin exit code is a supported plugin
+// state.
+func lintExitCode(exitCode int) []nagios.LintIssue {
+	for _, supported := range nagios.SupportedExitCodes() {
+		if exitCode == supported {
+			return nil
+		}
+	}
+
+	return []nagios.LintIssue{{
+		Rule:     lintRuleExitCode,
+		Severity: nagios.LintSeverityError,
+		Message:  fmt.Sprintf("plugin exit code %d is not a supported plugin state", exitCode),
+	}}
+}
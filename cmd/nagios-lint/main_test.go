@@ -0,0 +1,102 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ReportsExitCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args     []string
+		input    string
+		wantCode int
+		wantOut  string
+	}{
+		"valid output": {
+			input:    "OK: all good | time=1ms\n",
+			wantCode: exitOK,
+		},
+		"warnings only": {
+			input:    "all good\n",
+			wantCode: exitOK,
+			wantOut:  "line 1: warning: first-line-format",
+		},
+		"warnings with strict": {
+			args:     []string{"-strict"},
+			input:    "all good\n",
+			wantCode: exitFailure,
+			wantOut:  "line 1: warning: first-line-format",
+		},
+		"errors": {
+			input:    "OK: all good | time=\n",
+			wantCode: exitFailure,
+			wantOut:  "line 1: error: perfdata-syntax",
+		},
+		"preview": {
+			args:     []string{"-preview"},
+			input:    "OK: <b>all</b> good | time=1ms\n",
+			wantCode: exitOK,
+			wantOut:  "$SERVICEOUTPUT$ (notifications):\n  OK: ball/b good\n",
+		},
+		"preview with limit": {
+			args:     []string{"-preview", "-preview-limit", "8"},
+			input:    "OK: all good | time=1ms\n",
+			wantCode: exitOK,
+			wantOut:  "NOTE: output truncated from 24 to 8 bytes",
+		},
+		"invalid flag": {
+			args:     []string{"-bogus"},
+			wantCode: exitUsage,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr strings.Builder
+
+			got := run(tt.args, strings.NewReader(tt.input), &stdout, &stderr)
+			if got != tt.wantCode {
+				t.Errorf("ERROR: want exit code %d, got %d (stdout: %q, stderr: %q)", tt.wantCode, got, stdout.String(), stderr.String())
+			}
+
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("ERROR: %q not found in output %q", tt.wantOut, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_ReadsFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "output.txt")
+	if err := os.WriteFile(path, []byte("CRITICAL: down\x07\n"), 0600); err != nil {
+		t.Fatalf("ERROR: failed to write test file: %v", err)
+	}
+
+	var stdout, stderr strings.Builder
+
+	if got := run([]string{"-file", path}, strings.NewReader(""), &stdout, &stderr); got != exitFailure {
+		t.Fatalf("ERROR: want exit code %d, got %d", exitFailure, got)
+	}
+
+	if !strings.Contains(stdout.String(), "illegal-characters") {
+		t.Errorf("ERROR: control character issue not reported: %q", stdout.String())
+	}
+
+	t.Log("OK: plugin output read from file")
+}
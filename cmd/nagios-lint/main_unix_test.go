@@ -0,0 +1,38 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRun_ExecutesPluginAndChecksExitCode(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr strings.Builder
+
+	got := run(
+		[]string{"--", "sh", "-c", "echo 'OK: all good'; exit 7"},
+		strings.NewReader(""),
+		&stdout,
+		&stderr,
+	)
+
+	if got != exitFailure {
+		t.Fatalf("ERROR: want exit code %d, got %d (stderr: %q)", exitFailure, got, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "plugin exit code 7 is not a supported plugin state") {
+		t.Errorf("ERROR: exit code issue not reported: %q", stdout.String())
+	}
+
+	t.Log("OK: plugin executed and exit code checked")
+}
@@ -6,5 +6,5 @@
 // full license information.
 
 // Small test client app to prototype library changes and assist with updating
-// testdata input files.
+// corpus/testdata input files.
 package main
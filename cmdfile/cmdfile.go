@@ -0,0 +1,190 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cmdfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultCommandFile is the default location of the Nagios external
+	// command file.
+	DefaultCommandFile string = "/usr/local/nagios/var/rw/nagios.cmd"
+
+	// processServiceCheckResult is the external command used to submit
+	// passive service check results.
+	processServiceCheckResult string = "PROCESS_SERVICE_CHECK_RESULT"
+
+	// processHostCheckResult is the external command used to submit passive
+	// host check results.
+	processHostCheckResult string = "PROCESS_HOST_CHECK_RESULT"
+
+	// invalidObjectNameChars are characters which are not permitted in host
+	// names or service descriptions submitted via the command file. The
+	// semicolon is the command field separator, the pipe is not a valid
+	// object name character in the default Nagios configuration.
+	invalidObjectNameChars string = ";|\r\n"
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingCommandFile indicates that the command file path was not
+	// provided.
+	ErrMissingCommandFile = errors.New("command file path not specified")
+
+	// ErrNoCheckResults indicates that no check results were provided for
+	// submission.
+	ErrNoCheckResults = errors.New("no check results provided")
+
+	// ErrCommandFileNotReady indicates that the command file is a named pipe
+	// which is not currently being read by the monitoring system.
+	ErrCommandFileNotReady = errors.New("command file not being read by monitoring system")
+
+	// ErrInvalidObjectName indicates that a host name or service
+	// description contains characters which cannot be submitted via the
+	// command file.
+	ErrInvalidObjectName = errors.New("invalid host name or service description")
+)
+
+// Writer submits passive check results to the Nagios external command file.
+type Writer struct {
+	// path is the location of the external command file.
+	path string
+}
+
+// NewWriter constructs a new Writer for the given external command file. If
+// path is empty the default command file location is used.
+func NewWriter(path string) *Writer {
+	if path == "" {
+		path = DefaultCommandFile
+	}
+
+	return &Writer{
+		path: path,
+	}
+}
+
+// Submit writes the given check results to the external command file. Each
+// check result is written as a single external command using a separate
+// write to reduce the risk of commands from other submitters being
+// interleaved. Submission stops at the first failed write.
+//
+// Submit satisfies the nagios.PassiveSubmitter interface.
+func (w *Writer) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	switch {
+	case w.path == "":
+		return ErrMissingCommandFile
+	case len(results) == 0:
+		return ErrNoCheckResults
+	}
+
+	// Validate all check results before writing any of them.
+	commands := make([]string, 0, len(results))
+	for _, result := range results {
+		cmd, err := FormatCommand(result)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, cmd)
+	}
+
+	f, err := openCommandFile(w.path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	for _, cmd := range commands {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := f.WriteString(cmd); err != nil {
+			return fmt.Errorf("failed to write to command file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FormatCommand returns the given check result as a newline terminated
+// PROCESS_SERVICE_CHECK_RESULT or PROCESS_HOST_CHECK_RESULT external
+// command. The check result timestamp is used if set, otherwise the current
+// time is used. Newlines in the check result output are escaped.
+func FormatCommand(result nagios.PassiveCheckResult) (string, error) {
+	if result.HostName == "" {
+		return "", fmt.Errorf("empty host name: %w", ErrInvalidObjectName)
+	}
+
+	if err := validateObjectName(result.HostName); err != nil {
+		return "", fmt.Errorf("host name %q: %w", result.HostName, err)
+	}
+
+	if err := validateObjectName(result.ServiceDescription); err != nil {
+		return "", fmt.Errorf("service description %q: %w", result.ServiceDescription, err)
+	}
+
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	if result.IsHostCheckResult() {
+		return fmt.Sprintf(
+			"[%d] %s;%s;%d;%s\n",
+			timestamp.Unix(),
+			processHostCheckResult,
+			result.HostName,
+			result.ExitStatusCode,
+			EscapeOutput(result.Output),
+		), nil
+	}
+
+	return fmt.Sprintf(
+		"[%d] %s;%s;%s;%d;%s\n",
+		timestamp.Unix(),
+		processServiceCheckResult,
+		result.HostName,
+		result.ServiceDescription,
+		result.ExitStatusCode,
+		EscapeOutput(result.Output),
+	), nil
+}
+
+// EscapeOutput escapes the given plugin output for use in an external
+// command. Backslashes and newlines are escaped so that multi-line output
+// (and performance data following the first line) is preserved when the
+// command is processed by Nagios.
+func EscapeOutput(output string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"\r", "",
+		"\n", `\n`,
+	)
+
+	return replacer.Replace(strings.TrimRight(output, "\r\n"))
+}
+
+// validateObjectName asserts that the given host name or service description
+// can be submitted via the command file.
+func validateObjectName(name string) error {
+	if strings.ContainsAny(name, invalidObjectNameChars) {
+		return ErrInvalidObjectName
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cmdfile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure Writer satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*Writer)(nil)
+
+func TestFormatCommand_FormatsHostAndServiceResults(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Unix(1700000000, 0)
+
+	tests := map[string]struct {
+		result nagios.PassiveCheckResult
+		want   string
+	}{
+		"host check result": {
+			result: nagios.PassiveCheckResult{
+				HostName:       "web01",
+				ExitStatusCode: nagios.StateOKExitCode,
+				Output:         "UP",
+				Timestamp:      timestamp,
+			},
+			want: "[1700000000] PROCESS_HOST_CHECK_RESULT;web01;0;UP\n",
+		},
+		"service check result with multi-line output": {
+			result: nagios.PassiveCheckResult{
+				HostName:           "web01",
+				ServiceDescription: "HTTP",
+				ExitStatusCode:     nagios.StateCRITICALExitCode,
+				Output:             "CRITICAL: down\r\nC:\\temp | 'time'=5ms;;;;\n",
+				Timestamp:          timestamp,
+			},
+			want: "[1700000000] PROCESS_SERVICE_CHECK_RESULT;web01;HTTP;2;CRITICAL: down\\nC:\\\\temp | 'time'=5ms;;;;\n",
+		},
+	}
+
+	for name, tt := range tests {
+		got, err := FormatCommand(tt.result)
+		if err != nil {
+			t.Fatalf("ERROR: %s: unexpected error: %v", name, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("ERROR: %s: want %q, got %q", name, tt.want, got)
+		}
+	}
+}
+
+func TestFormatCommand_RejectsInvalidObjectNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []nagios.PassiveCheckResult{
+		{HostName: ""},
+		{HostName: "web01;evil"},
+		{HostName: "web01", ServiceDescription: "HTTP|HTTPS"},
+		{HostName: "web01", ServiceDescription: "HTTP\n[0] SHUTDOWN_PROGRAM"},
+	}
+
+	for _, result := range tests {
+		if _, err := FormatCommand(result); !errors.Is(err, ErrInvalidObjectName) {
+			t.Errorf("ERROR: want %v for %+v, got %v", ErrInvalidObjectName, result, err)
+		}
+	}
+}
+
+func TestWriter_Submit_WritesCommands(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nagios.cmd")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("ERROR: failed to create command file: %v", err)
+	}
+
+	results := []nagios.PassiveCheckResult{
+		{HostName: "web01", ServiceDescription: "HTTP", Output: "OK", Timestamp: time.Unix(1, 0)},
+		{HostName: "web01", ExitStatusCode: 1, Output: "DOWN", Timestamp: time.Unix(2, 0)},
+	}
+
+	if err := NewWriter(path).Submit(context.Background(), results...); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read command file: %v", err)
+	}
+
+	want := "[1] PROCESS_SERVICE_CHECK_RESULT;web01;HTTP;0;OK\n" +
+		"[2] PROCESS_HOST_CHECK_RESULT;web01;1;DOWN\n"
+
+	if string(got) != want {
+		t.Fatalf("ERROR: want %q, got %q", want, string(got))
+	}
+
+	t.Log("OK: commands written as expected")
+}
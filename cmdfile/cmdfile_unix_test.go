@@ -0,0 +1,38 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package cmdfile
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestWriter_Submit_DoesNotBlockWithoutReader(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nagios.cmd")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skipf("unable to create named pipe: %v", err)
+	}
+
+	result := nagios.PassiveCheckResult{HostName: "web01", Output: "UP"}
+
+	err := NewWriter(path).Submit(context.Background(), result)
+	if !errors.Is(err, ErrCommandFileNotReady) {
+		t.Fatalf("ERROR: want %v, got %v", ErrCommandFileNotReady, err)
+	}
+
+	t.Log("OK: named pipe without reader reported as expected")
+}
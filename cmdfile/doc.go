@@ -0,0 +1,38 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package cmdfile provides a writer for submitting passive check results to
+the Nagios external command file.
+
+# OVERVIEW
+
+Nagios (and compatible monitoring systems) accept passive check results
+written to a named pipe (by default /usr/local/nagios/var/rw/nagios.cmd)
+as PROCESS_SERVICE_CHECK_RESULT and PROCESS_HOST_CHECK_RESULT external
+commands. This package formats check results rendered by the nagios package
+(see nagios.Plugin.PassiveCheckResult) as external commands, escaping
+embedded newlines so that multi-line output and performance data are
+preserved.
+
+The command file is opened without blocking; if the monitoring system is
+not reading from the command file an error is returned instead of hanging
+indefinitely.
+
+The Writer type satisfies the nagios.PassiveSubmitter interface and can be
+used directly with the nagios.Runner type for daemon mode checks.
+
+# HOW TO USE
+
+	writer := cmdfile.NewWriter("/usr/local/nagios/var/rw/nagios.cmd")
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := writer.Submit(ctx, result); err != nil {
+		// handle error
+	}
+*/
+package cmdfile
@@ -0,0 +1,33 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package cmdfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// openCommandFile opens the command file for writing without blocking. If
+// the command file is a named pipe without a reader ErrCommandFileNotReady
+// is returned.
+func openCommandFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			return nil, fmt.Errorf("%s: %w", path, ErrCommandFileNotReady)
+		}
+
+		return nil, fmt.Errorf("failed to open command file: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build windows
+
+package cmdfile
+
+import (
+	"fmt"
+	"os"
+)
+
+// openCommandFile opens the command file for writing. Named pipes as used
+// by Nagios are not available on Windows; the command file is treated as a
+// regular file.
+func openCommandFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open command file: %w", err)
+	}
+
+	return f, nil
+}
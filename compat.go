@@ -0,0 +1,116 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"strings"
+)
+
+// CompatibilityMode identifies the monitoring system (Nagios Core or one of
+// its forks) that plugin output is tailored for.
+type CompatibilityMode int
+
+// Supported compatibility modes.
+const (
+	// CompatibilityModeNagios is the default compatibility mode. Output is
+	// tailored for Nagios Core.
+	CompatibilityModeNagios CompatibilityMode = iota
+
+	// CompatibilityModeNaemon tailors output for Naemon.
+	CompatibilityModeNaemon
+
+	// CompatibilityModeShinken tailors output for Shinken (and Alignak).
+	CompatibilityModeShinken
+)
+
+// shinkenMaxPerfDataLength is the conservative performance data size limit
+// (in bytes) applied when using the Shinken compatibility mode; Shinken
+// pollers are commonly reached via NRPE v2 agents.
+const shinkenMaxPerfDataLength int = NRPEv2MaxOutputLength
+
+// compatibilityQuirks is the collection of output handling differences
+// applied for a compatibility mode.
+type compatibilityQuirks struct {
+	// eol is the newline character(s) used for plugin output. An empty
+	// value indicates that CheckOutputEOL is used.
+	eol string
+
+	// maxPerfDataLength is the maximum size in bytes of the performance
+	// data section. A value of zero indicates no limit.
+	maxPerfDataLength int
+}
+
+// SetCompatibilityMode tailors plugin output for the specified monitoring
+// system. This allows one plugin binary to behave correctly across the
+// Nagios fork family without mode specific logic in client code.
+//
+// The following differences are applied:
+//
+//   - Nagios: CheckOutputEOL (a space followed by a newline) is used to
+//     work around the Nagios web UI treatment of newlines. No performance
+//     data size limit.
+//   - Naemon: a plain newline is used as the web interfaces commonly used
+//     with Naemon (e.g., Thruk) do not need the trailing space workaround.
+//     No performance data size limit.
+//   - Shinken: a plain newline is used and the performance data section is
+//     limited to 1024 bytes. Metrics which do not fit are omitted instead of
+//     being truncated mid-metric.
+//
+// Use SetMaxPerfDataLength to override the performance data size limit for
+// the selected mode.
+//
+// Compatibility modes apply to the Nagios plugin output format only.
+func (p *Plugin) SetCompatibilityMode(mode CompatibilityMode) {
+	switch mode {
+	case CompatibilityModeNaemon:
+		p.logAction("Enabling Naemon compatibility mode")
+		p.compatibility = compatibilityQuirks{eol: "\n"}
+
+	case CompatibilityModeShinken:
+		p.logAction("Enabling Shinken compatibility mode")
+		p.compatibility = compatibilityQuirks{
+			eol:               "\n",
+			maxPerfDataLength: shinkenMaxPerfDataLength,
+		}
+
+	default:
+		p.logAction("Enabling Nagios compatibility mode")
+		p.compatibility = compatibilityQuirks{}
+	}
+}
+
+// SetMaxPerfDataLength limits the size in bytes of the performance data
+// section. Metrics which do not fit are omitted. A value of zero disables
+// the limit; negative values are ignored.
+func (p *Plugin) SetMaxPerfDataLength(length int) {
+	if length < 0 {
+		return
+	}
+
+	p.compatibility.maxPerfDataLength = length
+}
+
+// outputEOL returns the newline character(s) used for plugin output.
+func (p *Plugin) outputEOL() string {
+	if p.compatibility.eol == "" {
+		return CheckOutputEOL
+	}
+
+	return p.compatibility.eol
+}
+
+// applyCompatibilityEOL replaces CheckOutputEOL in the given plugin output
+// with the newline character(s) used by the current compatibility mode.
+func (p *Plugin) applyCompatibilityEOL(pluginOutput string) string {
+	eol := p.outputEOL()
+	if eol == CheckOutputEOL {
+		return pluginOutput
+	}
+
+	return strings.ReplaceAll(pluginOutput, CheckOutputEOL, eol)
+}
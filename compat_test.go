@@ -0,0 +1,179 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetCompatibilityMode_AppliesQuirks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nagios", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuffer strings.Builder
+
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(&outputBuffer)
+		plugin.SkipOSExit()
+		plugin.SetCompatibilityMode(nagios.CompatibilityModeNagios)
+		plugin.ServiceOutput = "OK: all good"
+		plugin.LongServiceOutput = "line one" + nagios.CheckOutputEOL + "line two"
+
+		// Provide enough performance data to exceed a small performance
+		// data size limit.
+		metrics := make([]nagios.PerformanceData, 0, 60)
+		for i := 0; i < 60; i++ {
+			metrics = append(metrics, nagios.PerformanceData{
+				Label: fmt.Sprintf("metric_%02d", i),
+				Value: "1",
+			})
+		}
+
+		if err := plugin.AddPerfData(true, metrics...); err != nil {
+			t.Fatalf("ERROR: failed to add perfdata: %v", err)
+		}
+
+		plugin.ReturnCheckResults()
+
+		got := outputBuffer.String()
+		if !strings.Contains(got, "line one"+nagios.CheckOutputEOL) || !strings.Contains(got, "'metric_59'") {
+			t.Fatalf("ERROR: unexpected Nagios output:\n%q", got)
+		}
+
+		t.Log("OK: Nagios output unchanged as expected")
+	})
+
+	t.Run("naemon", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuffer strings.Builder
+
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(&outputBuffer)
+		plugin.SkipOSExit()
+		plugin.SetCompatibilityMode(nagios.CompatibilityModeNaemon)
+		plugin.ServiceOutput = "OK: all good"
+		plugin.LongServiceOutput = "line one" + nagios.CheckOutputEOL + "line two"
+
+		// Provide enough performance data to exceed a small performance
+		// data size limit.
+		metrics := make([]nagios.PerformanceData, 0, 60)
+		for i := 0; i < 60; i++ {
+			metrics = append(metrics, nagios.PerformanceData{
+				Label: fmt.Sprintf("metric_%02d", i),
+				Value: "1",
+			})
+		}
+
+		if err := plugin.AddPerfData(true, metrics...); err != nil {
+			t.Fatalf("ERROR: failed to add perfdata: %v", err)
+		}
+
+		plugin.EnablePluginOutputSizePerfDataMetric()
+		plugin.ReturnCheckResults()
+
+		got := outputBuffer.String()
+		switch {
+		case strings.Contains(got, " \n"):
+			t.Fatalf("ERROR: Naemon output contains Nagios EOL:\n%q", got)
+		case !strings.Contains(got, "line one\nline two"):
+			t.Fatalf("ERROR: unexpected Naemon long output:\n%q", got)
+		case !strings.HasSuffix(got, "B;;;;\n") || strings.Count(got, "|") != 1:
+			t.Fatalf("ERROR: plugin output size metric not appended to perfdata:\n%q", got)
+		}
+
+		t.Log("OK: Naemon EOL used as expected")
+	})
+
+	t.Run("shinken", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuffer strings.Builder
+
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(&outputBuffer)
+		plugin.SkipOSExit()
+		plugin.SetCompatibilityMode(nagios.CompatibilityModeShinken)
+		plugin.ServiceOutput = "OK: all good"
+		plugin.LongServiceOutput = "line one" + nagios.CheckOutputEOL + "line two"
+
+		// Provide enough performance data to exceed a small performance
+		// data size limit.
+		metrics := make([]nagios.PerformanceData, 0, 60)
+		for i := 0; i < 60; i++ {
+			metrics = append(metrics, nagios.PerformanceData{
+				Label: fmt.Sprintf("metric_%02d", i),
+				Value: "1",
+			})
+		}
+
+		if err := plugin.AddPerfData(true, metrics...); err != nil {
+			t.Fatalf("ERROR: failed to add perfdata: %v", err)
+		}
+
+		plugin.ReturnCheckResults()
+
+		got := outputBuffer.String()
+		idx := strings.LastIndex(got, " |")
+		if idx < 0 {
+			t.Fatalf("ERROR: performance data missing:\n%q", got)
+		}
+
+		perfData := got[idx:]
+		switch {
+		case len(perfData) > 1024+len("\n"):
+			t.Fatalf("ERROR: performance data exceeds limit (%d bytes)", len(perfData))
+		case !strings.HasSuffix(perfData, ";;;;\n"):
+			t.Fatalf("ERROR: performance data truncated mid-metric:\n%q", perfData)
+		}
+
+		t.Log("OK: Shinken performance data limit applied as expected")
+	})
+}
+
+func TestPlugin_SetMaxPerfDataLength_OverridesModeLimit(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetCompatibilityMode(nagios.CompatibilityModeShinken)
+	plugin.ServiceOutput = "OK: all good"
+	plugin.LongServiceOutput = "line one" + nagios.CheckOutputEOL + "line two"
+
+	// Provide enough performance data to exceed a small performance
+	// data size limit.
+	metrics := make([]nagios.PerformanceData, 0, 60)
+	for i := 0; i < 60; i++ {
+		metrics = append(metrics, nagios.PerformanceData{
+			Label: fmt.Sprintf("metric_%02d", i),
+			Value: "1",
+		})
+	}
+
+	if err := plugin.AddPerfData(true, metrics...); err != nil {
+		t.Fatalf("ERROR: failed to add perfdata: %v", err)
+	}
+
+	plugin.SetMaxPerfDataLength(0)
+	plugin.ReturnCheckResults()
+
+	if got := outputBuffer.String(); strings.Count(got, "metric_") != 60 {
+		t.Fatalf("ERROR: want all 60 metrics, got %d", strings.Count(got, "metric_"))
+	}
+
+	t.Log("OK: performance data limit disabled as expected")
+}
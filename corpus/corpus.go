@@ -0,0 +1,248 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package corpus
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Settings used when generating the encoded payload samples.
+const (
+	// CustomEncodingDelimiterLeft is the custom left delimiter used by
+	// samples with custom delimiters.
+	CustomEncodingDelimiterLeft string = "CUSTOM_ENCODING_DELIMITER_LEFT"
+
+	// CustomEncodingDelimiterRight is the custom right delimiter used by
+	// samples with custom delimiters.
+	CustomEncodingDelimiterRight string = "CUSTOM_ENCODING_DELIMITER_RIGHT"
+
+	// CustomSectionHeader is the custom encoded payload section header used
+	// by plugin output samples with a custom section header.
+	CustomSectionHeader string = "PAYLOAD FOR LATER API USE"
+)
+
+const (
+	// corpusDir is the embedded directory containing the corpus files.
+	corpusDir string = "testdata"
+
+	// sampleExt is the file extension used by corpus samples.
+	sampleExt string = ".txt"
+
+	// pluginOutputPrefix is the file name prefix used by plugin output
+	// samples.
+	pluginOutputPrefix string = "plugin-output-"
+
+	// payloadDir is the directory containing encoded payload fixtures.
+	payloadDir string = "payload"
+)
+
+// Encoded payload fixture file name suffixes.
+const (
+	payloadSuffixUnencoded         string = "_unencoded"
+	payloadSuffixDefaultDelimiters string = "_encoded_with_default_delimiters"
+	payloadSuffixCustomDelimiters  string = "_encoded_with_custom_delimiters"
+	payloadSuffixNoDelimiters      string = "_encoded_without_delimiters"
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrSampleNotFound indicates that the requested sample is not part of
+	// the corpus.
+	ErrSampleNotFound = errors.New("corpus sample not found")
+)
+
+// files is the embedded corpus. The trailing space and newline patterns in
+// the sample files are intentional.
+//
+//go:embed testdata/*.txt testdata/payload/*.txt
+var files embed.FS
+
+// Sample is a single file from the corpus.
+type Sample struct {
+	// Name is the path of the sample relative to the corpus root (e.g.,
+	// "plugin-output-datastore-0001.txt" or
+	// "payload/small_json_payload_unencoded.txt").
+	Name string
+
+	// Content is the exact content of the sample.
+	Content string
+}
+
+// PayloadFixture is a payload along with its encoded forms as generated by
+// the nagios package.
+type PayloadFixture struct {
+	// Name is the base name shared by the fixture files (e.g.,
+	// "small_json_payload").
+	Name string
+
+	// Unencoded is the original payload.
+	Unencoded string
+
+	// EncodedWithDefaultDelimiters is the payload encoded using the default
+	// delimiters.
+	EncodedWithDefaultDelimiters string
+
+	// EncodedWithCustomDelimiters is the payload encoded using
+	// CustomEncodingDelimiterLeft and CustomEncodingDelimiterRight.
+	EncodedWithCustomDelimiters string
+
+	// EncodedWithoutDelimiters is the payload encoded without delimiters.
+	EncodedWithoutDelimiters string
+}
+
+// FS returns the corpus as a read-only file system rooted at the corpus
+// directory. This allows use with fs.WalkDir or fstest.TestFS.
+func FS() fs.FS {
+	sub, err := fs.Sub(files, corpusDir)
+	if err != nil {
+		// The embedded directory is fixed at build time.
+		panic(fmt.Sprintf("failed to access embedded corpus: %v", err))
+	}
+
+	return sub
+}
+
+// Names returns the sorted names of all samples in the corpus.
+func Names() []string {
+	var names []string
+
+	_ = fs.WalkDir(FS(), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && path.Ext(name) == sampleExt {
+			names = append(names, name)
+		}
+
+		return nil
+	})
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the content of the named sample. An error wrapping
+// ErrSampleNotFound is returned if the sample is not part of the corpus.
+func Get(name string) (string, error) {
+	data, err := fs.ReadFile(FS(), name)
+	if err != nil {
+		return "", fmt.Errorf("sample %q: %w", name, ErrSampleNotFound)
+	}
+
+	return string(data), nil
+}
+
+// MustGet returns the content of the named sample. MustGet panics if the
+// sample is not part of the corpus; this is intended for use in tests with
+// known sample names.
+func MustGet(name string) string {
+	content, err := Get(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return content
+}
+
+// All returns every sample in the corpus sorted by name.
+func All() []Sample {
+	names := Names()
+
+	samples := make([]Sample, 0, len(names))
+	for _, name := range names {
+		samples = append(samples, Sample{Name: name, Content: MustGet(name)})
+	}
+
+	return samples
+}
+
+// PluginOutputs returns the rendered plugin output samples (including
+// samples with encoded payloads) sorted by name.
+func PluginOutputs() []Sample {
+	var samples []Sample
+
+	for _, sample := range All() {
+		if strings.HasPrefix(path.Base(sample.Name), pluginOutputPrefix) {
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples
+}
+
+// PayloadFixtures returns the encoded payload fixtures sorted by name.
+func PayloadFixtures() []PayloadFixture {
+	fixtures := make(map[string]*PayloadFixture)
+
+	for _, sample := range All() {
+		if path.Dir(sample.Name) != payloadDir {
+			continue
+		}
+
+		base := strings.TrimSuffix(path.Base(sample.Name), sampleExt)
+
+		for _, suffix := range []string{
+			payloadSuffixUnencoded,
+			payloadSuffixDefaultDelimiters,
+			payloadSuffixCustomDelimiters,
+			payloadSuffixNoDelimiters,
+		} {
+			name, found := cutSuffix(base, suffix)
+			if !found {
+				continue
+			}
+
+			fixture, ok := fixtures[name]
+			if !ok {
+				fixture = &PayloadFixture{Name: name}
+				fixtures[name] = fixture
+			}
+
+			switch suffix {
+			case payloadSuffixUnencoded:
+				fixture.Unencoded = sample.Content
+			case payloadSuffixDefaultDelimiters:
+				fixture.EncodedWithDefaultDelimiters = sample.Content
+			case payloadSuffixCustomDelimiters:
+				fixture.EncodedWithCustomDelimiters = sample.Content
+			case payloadSuffixNoDelimiters:
+				fixture.EncodedWithoutDelimiters = sample.Content
+			}
+		}
+	}
+
+	collection := make([]PayloadFixture, 0, len(fixtures))
+	for _, fixture := range fixtures {
+		collection = append(collection, *fixture)
+	}
+
+	sort.Slice(collection, func(i, j int) bool {
+		return collection[i].Name < collection[j].Name
+	})
+
+	return collection
+}
+
+// cutSuffix returns s without the given suffix and whether the suffix was
+// found. This mirrors strings.CutSuffix which requires Go 1.20.
+func cutSuffix(s string, suffix string) (string, bool) {
+	if !strings.HasSuffix(s, suffix) {
+		return s, false
+	}
+
+	return strings.TrimSuffix(s, suffix), true
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package corpus
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestFS_ContainsAllSamples(t *testing.T) {
+	t.Parallel()
+
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("ERROR: no samples found in corpus")
+	}
+
+	if err := fstest.TestFS(FS(), names...); err != nil {
+		t.Fatalf("ERROR: corpus file system failed validation: %v", err)
+	}
+
+	t.Logf("OK: %d samples found in corpus", len(names))
+}
+
+func TestGet_ReturnsExactContent(t *testing.T) {
+	t.Parallel()
+
+	got, err := Get("payload/small_json_payload_unencoded.txt")
+	if err != nil {
+		t.Fatalf("ERROR: failed to retrieve sample: %v", err)
+	}
+
+	if want := `{"Age":17,"Interests":["books","games", "Crystal Stix"]}`; got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	if _, err := Get("missing.txt"); !errors.Is(err, ErrSampleNotFound) {
+		t.Fatalf("ERROR: want %v for missing sample, got %v", ErrSampleNotFound, err)
+	}
+
+	t.Log("OK: sample content retrieved as expected")
+}
+
+func TestPluginOutputs_Parse(t *testing.T) {
+	t.Parallel()
+
+	samples := PluginOutputs()
+	if len(samples) == 0 {
+		t.Fatal("ERROR: no plugin output samples found")
+	}
+
+	for _, sample := range samples {
+		if _, err := nagios.ParsePluginOutput(sample.Content); err != nil {
+			t.Errorf("ERROR: failed to parse sample %s: %v", sample.Name, err)
+		}
+	}
+
+	t.Logf("OK: %d plugin output samples parsed", len(samples))
+}
+
+func TestPayloadFixtures_AreComplete(t *testing.T) {
+	t.Parallel()
+
+	fixtures := PayloadFixtures()
+
+	want := []string{"large_payload", "small_json_payload", "small_plaintext_payload"}
+	if len(fixtures) != len(want) {
+		t.Fatalf("ERROR: want %d payload fixtures, got %d", len(want), len(fixtures))
+	}
+
+	for i, fixture := range fixtures {
+		switch {
+		case fixture.Name != want[i]:
+			t.Errorf("ERROR: want fixture %q, got %q", want[i], fixture.Name)
+		case fixture.Unencoded == "",
+			fixture.EncodedWithDefaultDelimiters == "",
+			fixture.EncodedWithCustomDelimiters == "",
+			fixture.EncodedWithoutDelimiters == "":
+			t.Errorf("ERROR: fixture %q is incomplete: %+v", fixture.Name, fixture)
+		}
+
+		decoded, err := nagios.ExtractAndDecodePayload(
+			fixture.EncodedWithCustomDelimiters,
+			"",
+			CustomEncodingDelimiterLeft,
+			CustomEncodingDelimiterRight,
+		)
+		if err != nil {
+			t.Errorf("ERROR: failed to decode fixture %q: %v", fixture.Name, err)
+			continue
+		}
+
+		if decoded != fixture.Unencoded {
+			t.Errorf("ERROR: decoded fixture %q does not match unencoded payload", fixture.Name)
+		}
+	}
+
+	t.Log("OK: payload fixtures complete")
+}
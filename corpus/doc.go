@@ -0,0 +1,47 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package corpus provides programmatic access to the curated sample plugin
+output and encoded payload fixtures used by the nagios package tests.
+
+# OVERVIEW
+
+The samples are embedded in this package so that downstream tooling (e.g.,
+plugin output parsers and linters) can validate against the same canonical
+examples used by this project instead of maintaining copies.
+
+Sample content is returned exactly as stored. Many samples include
+intentional trailing whitespace and specific line endings; these are
+significant and should not be normalized.
+
+Encoded payload fixtures were generated using the default delimiters, the
+custom delimiters provided by the CustomEncodingDelimiterLeft and
+CustomEncodingDelimiterRight constants and without delimiters.
+
+# HOW TO USE
+
+	for _, sample := range corpus.PluginOutputs() {
+		if _, err := nagios.ParsePluginOutput(sample.Content); err != nil {
+			t.Errorf("%s: %v", sample.Name, err)
+		}
+	}
+
+	for _, fixture := range corpus.PayloadFixtures() {
+		decoded, err := nagios.ExtractAndDecodePayload(
+			fixture.EncodedWithDefaultDelimiters,
+			"",
+			nagios.DefaultASCII85EncodingDelimiterLeft,
+			nagios.DefaultASCII85EncodingDelimiterRight,
+		)
+		// compare decoded against fixture.Unencoded
+	}
+
+Individual samples may be retrieved by name using Get (or MustGet), and the
+FS function provides the corpus as an fs.FS value.
+*/
+package corpus
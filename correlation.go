@@ -0,0 +1,85 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// correlationIDBytes is the number of random bytes used to generate a
+	// correlation ID.
+	correlationIDBytes int = 8
+
+	// logAttrCorrelationID is the key of the correlation ID debug log entry
+	// attribute.
+	logAttrCorrelationID string = "correlation_id"
+
+	// CorrelationIDAnnotation is the annotation key used to record the
+	// correlation ID in structured output (e.g., Sensu events) if enabled
+	// via EnableCorrelationIDAnnotation.
+	CorrelationIDAnnotation string = "go-nagios/correlation-id"
+)
+
+// newCorrelationID generates a random correlation ID. If random data is
+// unavailable an ID derived from the current time and process ID is used
+// instead.
+func newCorrelationID() string {
+	b := make([]byte, correlationIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x-%x", time.Now().UnixNano(), os.Getpid())
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// CorrelationID returns the per-run correlation ID included in every debug
+// log entry. A random ID is generated by NewPlugin; SetCorrelationID may be
+// used to provide one instead (e.g., an ID provided by a calling process).
+func (p *Plugin) CorrelationID() string {
+	return p.correlationID
+}
+
+// SetCorrelationID overrides the per-run correlation ID included in every
+// debug log entry. This allows debug log entries from overlapping check
+// executions on the same host to be separated and related to external logs.
+// An empty value is ignored.
+func (p *Plugin) SetCorrelationID(id string) {
+	if id == "" {
+		p.logAction("Empty correlation ID provided; retaining current correlation ID")
+
+		return
+	}
+
+	p.correlationID = id
+	p.logAction(fmt.Sprintf("Correlation ID set to %q", id))
+}
+
+// EnableCorrelationIDAnnotation indicates that the correlation ID should be
+// recorded as an annotation (using the CorrelationIDAnnotation key) in
+// structured output which supports annotations (e.g., the check metadata of
+// Sensu events). The annotation is not displayed by monitoring system web
+// UIs.
+func (p *Plugin) EnableCorrelationIDAnnotation() {
+	p.logAction("Enabling correlation ID annotation")
+	p.correlationIDAnnotation = true
+}
+
+// correlationIDAnnotations returns the annotations used to record the
+// correlation ID in structured output or nil if disabled.
+func (p *Plugin) correlationIDAnnotations() map[string]string {
+	if !p.correlationIDAnnotation || p.correlationID == "" {
+		return nil
+	}
+
+	return map[string]string{CorrelationIDAnnotation: p.correlationID}
+}
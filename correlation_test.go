@@ -0,0 +1,123 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestNewPlugin_GeneratesUniqueCorrelationIDs(t *testing.T) {
+	t.Parallel()
+
+	first := nagios.NewPlugin().CorrelationID()
+	second := nagios.NewPlugin().CorrelationID()
+
+	if first == "" || second == "" {
+		t.Fatalf("ERROR: empty correlation ID generated: %q, %q", first, second)
+	}
+
+	if first == second {
+		t.Fatalf("ERROR: duplicate correlation ID generated: %q", first)
+	}
+
+	t.Logf("OK: unique correlation IDs generated: %q, %q", first, second)
+}
+
+func TestPlugin_SetCorrelationID_IgnoresEmptyValue(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetCorrelationID("run-42")
+	plugin.SetCorrelationID("")
+
+	if got := plugin.CorrelationID(); got != "run-42" {
+		t.Fatalf("ERROR: want correlation ID %q, got %q", "run-42", got)
+	}
+
+	t.Log("OK: empty correlation ID ignored")
+}
+
+func TestPlugin_CorrelationID_IncludedInEveryDebugLogEntry(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetCorrelationID("run-42")
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.DebugLoggingEnableAll()
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	lines := strings.Split(strings.TrimSpace(logBuffer.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("ERROR: expected multiple log entries, got:\n%s", logBuffer.String())
+	}
+
+	for _, line := range lines {
+		if !strings.Contains(line, "[run-42] ") {
+			t.Errorf("ERROR: correlation ID missing from log entry %q", line)
+		}
+	}
+
+	t.Log("OK: correlation ID included in plain text debug log entries")
+}
+
+func TestPlugin_CorrelationID_IncludedInJSONDebugLogEntries(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogFormat(nagios.DebugLogFormatJSON)
+	plugin.DebugLoggingEnableAll()
+	plugin.EnableStrictMode()
+
+	var entry struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+
+	if err := json.Unmarshal(bytes.TrimSpace(logBuffer.Bytes()), &entry); err != nil {
+		t.Fatalf("ERROR: failed to decode log entry %q: %v", logBuffer.String(), err)
+	}
+
+	if got := entry.Fields["correlation_id"]; got != plugin.CorrelationID() {
+		t.Fatalf("ERROR: want correlation_id %q, got %v", plugin.CorrelationID(), got)
+	}
+
+	t.Log("OK: correlation ID included in JSON debug log entries")
+}
+
+func TestPlugin_EnableCorrelationIDAnnotation_AnnotatesSensuEvent(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ServiceOutput = "OK: all good"
+
+	if got := plugin.SensuEvent().Check.Metadata.Annotations; got != nil {
+		t.Errorf("ERROR: unexpected annotations without opt-in: %v", got)
+	}
+
+	plugin.EnableCorrelationIDAnnotation()
+
+	got := plugin.SensuEvent().Check.Metadata.Annotations[nagios.CorrelationIDAnnotation]
+	if got != plugin.CorrelationID() {
+		t.Fatalf("ERROR: want annotation %q, got %q", plugin.CorrelationID(), got)
+	}
+
+	t.Log("OK: correlation ID annotation added to Sensu event")
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// crashDumpFilePattern is the file name pattern used when creating a crash
+// dump file. The plugin name replaces the first verb and the random suffix
+// provided by os.CreateTemp replaces the asterisk.
+const crashDumpFilePattern string = "%s-crash-*.log"
+
+// EnableCrashDump indicates that a crash dump file should be written to the
+// given directory if a panic in client code is detected by
+// ReturnCheckResults. The crash dump includes the panic value, the full
+// stack trace, a snapshot of the plugin state and any captured debug log
+// entries (see EnableDebugLogCapture). This complements the stack trace
+// included in the plugin output which is often truncated by monitoring
+// system web UIs and notifications.
+//
+// The path to the crash dump file is included in the LongServiceOutput. A
+// failure to write the crash dump is recorded as an error. An empty
+// directory disables writing crash dumps.
+//
+// Crash dump files may contain sensitive details so access is limited to the
+// owner.
+func (p *Plugin) EnableCrashDump(dir string) {
+	if dir == "" {
+		p.logAction("Disabling crash dump")
+		p.crashDumpDir = ""
+
+		return
+	}
+
+	p.logAction(fmt.Sprintf("Enabling crash dump to directory %q as requested", dir))
+	p.crashDumpDir = dir
+}
+
+// handleCrashDump writes a crash dump file for the given (recovered) panic
+// value and stack trace if requested. The path to the crash dump is appended
+// to the LongServiceOutput.
+func (p *Plugin) handleCrashDump(panicValue interface{}, stackTrace []byte) {
+	if p.crashDumpDir == "" {
+		return
+	}
+
+	path, err := p.writeCrashDump(panicValue, stackTrace)
+	if err != nil {
+		p.logActionLevel(DebugLogLevelError, fmt.Sprintf("Failed to write crash dump: %v", err))
+		p.AddError(fmt.Errorf("failed to write crash dump: %w", err))
+
+		return
+	}
+
+	p.logAction(fmt.Sprintf("Crash dump written to %s", path))
+
+	p.LongServiceOutput += CheckOutputEOL + CheckOutputEOL + "Crash dump: " + path
+}
+
+// writeCrashDump writes a crash dump file for the given panic value and
+// stack trace to the crash dump directory and returns the path to the file.
+func (p *Plugin) writeCrashDump(panicValue interface{}, stackTrace []byte) (string, error) {
+	pattern := fmt.Sprintf(crashDumpFilePattern, filepath.Base(os.Args[0]))
+
+	// os.CreateTemp creates the file using 0600 permissions.
+	file, err := os.CreateTemp(p.crashDumpDir, pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var dump strings.Builder
+
+	fmt.Fprintf(&dump, "time: %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&dump, "panic: %v\n", panicValue)
+
+	dump.WriteString("\n--- stack trace ---\n")
+	dump.Write(stackTrace)
+
+	dump.WriteString("\n--- plugin state ---\n")
+	for _, attr := range p.stateSnapshotAttrs() {
+		fmt.Fprintf(&dump, "%s=%v\n", attr.Key, attr.Value)
+	}
+
+	if p.debugLogCapture != nil {
+		lines := p.debugLogCapture.lines()
+
+		fmt.Fprintf(&dump, "\n"+debugLogCaptureHeader+"\n", len(lines))
+		for _, line := range lines {
+			dump.WriteString(line)
+			dump.WriteString("\n")
+		}
+	}
+
+	if _, err := file.WriteString(dump.String()); err != nil {
+		_ = file.Close()
+
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	return file.Name(), nil
+}
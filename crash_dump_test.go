@@ -0,0 +1,120 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_EnableCrashDump_WritesDumpOnPanic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableDebugLogCapture(5)
+	plugin.EnableCrashDump(dir)
+	plugin.ServiceOutput = "OK: all good"
+
+	func() {
+		defer plugin.ReturnCheckResults()
+		panic("unexpected nil datastore")
+	}()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*-crash-*.log"))
+	if err != nil {
+		t.Fatalf("ERROR: failed to list crash dump files: %v", err)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("ERROR: want 1 crash dump file, got %d: %v", len(matches), matches)
+	}
+
+	content, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("ERROR: failed to read crash dump file: %v", err)
+	}
+
+	for _, want := range []string{
+		"panic: unexpected nil datastore",
+		"--- stack trace ---",
+		"crash_dump_test.go",
+		"--- plugin state ---",
+		"exit_code=2",
+		"--- go-nagios debug log (last 5 entries) ---",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("ERROR: %q not found in crash dump:\n%s", want, content)
+		}
+	}
+
+	if !strings.Contains(outputBuffer.String(), "Crash dump: "+matches[0]) {
+		t.Errorf("ERROR: crash dump path not found in output:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: crash dump written as expected")
+}
+
+func TestPlugin_EnableCrashDump_RecordsWriteFailure(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableCrashDump(filepath.Join(t.TempDir(), "missing"))
+
+	func() {
+		defer plugin.ReturnCheckResults()
+		panic(errors.New("boom"))
+	}()
+
+	if !strings.Contains(outputBuffer.String(), "failed to write crash dump") {
+		t.Fatalf("ERROR: crash dump failure not found in output:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: crash dump failure recorded as expected")
+}
+
+func TestPlugin_EnableCrashDump_NoDumpWithoutPanic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableCrashDump(dir)
+	plugin.ServiceOutput = "CRITICAL: datastore full"
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ReturnCheckResults()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ERROR: failed to list crash dump directory: %v", err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("ERROR: want no crash dump files, got %d", len(entries))
+	}
+
+	t.Log("OK: no crash dump written as expected")
+}
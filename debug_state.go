@@ -0,0 +1,99 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// logCategoryState is the debug log entry category used for plugin state
+// snapshots.
+const logCategoryState string = "state"
+
+// DebugDumpState writes a snapshot of the current plugin state to the debug
+// log. The snapshot includes the exit code, one-line summary, error count,
+// performance data labels, payload size and configured options, making it
+// possible to answer "what did the library think was configured" questions
+// during support cases.
+//
+// The snapshot is written whenever a debug logging output target (or
+// handler) is configured, regardless of the enabled debug logging options or
+// debug log level. Structured debug log handlers (see SetDebugLogFormat)
+// receive each value as a separate attribute.
+func (p *Plugin) DebugDumpState() {
+	attrs := p.stateSnapshotAttrs()
+
+	fields := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, fmt.Sprintf("%s=%v", attr.Key, attr.Value))
+	}
+
+	p.writeLogEntry(
+		DebugLogLevelInfo,
+		logCategoryState,
+		"Plugin state snapshot: "+strings.Join(fields, " "),
+		attrs...,
+	)
+}
+
+// stateSnapshotAttrs returns the current plugin state as a collection of
+// debug log attributes.
+func (p *Plugin) stateSnapshotAttrs() []logAttr {
+	perfDataLabels := p.perfData.sortedKeys()
+
+	var errCount int
+	for _, err := range p.Errors {
+		if err != nil {
+			errCount++
+		}
+	}
+	if p.LastError != nil {
+		errCount++
+	}
+
+	return []logAttr{
+		{Key: "exit_code", Value: p.ExitStatusCode},
+		{Key: "state", Value: ExitCodeToStateLabel(p.ExitStatusCode)},
+		{Key: "service_output", Value: fmt.Sprintf("%q", p.ServiceOutput)},
+		{Key: "long_service_output_bytes", Value: len(p.LongServiceOutput)},
+		{Key: "long_service_output_reader", Value: p.longServiceOutput != nil},
+		{Key: "error_count", Value: errCount},
+		{Key: "perfdata_labels", Value: perfDataLabels},
+		{Key: "payload_bytes", Value: p.encodedPayloadBuffer.Len()},
+		{Key: "max_payload_size", Value: p.payloadSizeLimit()},
+		{Key: "output_format", Value: p.outputFormat.String()},
+		{Key: "output_eol", Value: fmt.Sprintf("%q", p.outputEOL())},
+		{Key: "max_perfdata_length", Value: p.compatibility.maxPerfDataLength},
+		{Key: "strict_mode", Value: p.strictMode},
+		{Key: "unknown_on_empty_service_output", Value: p.unknownOnEmptyServiceOutput},
+		{Key: "skip_os_exit", Value: p.shouldSkipOSExit},
+		{Key: "custom_exit_func", Value: p.exitFunc != nil},
+		{Key: "crash_dump_dir", Value: p.crashDumpDir},
+		{Key: "error_classifier", Value: p.errorClassifier != nil},
+		{Key: "timeout", Value: p.Timeout().String()},
+		{Key: "skip_default_time_metric", Value: p.skipDefaultTimeMetric},
+		{Key: "time_remaining_metric", Value: p.timeRemainingMetric},
+		{Key: "hide_thresholds_section", Value: p.hideThresholdsSection},
+		{Key: "hide_errors_section", Value: p.hideErrorsSection},
+		{Key: "hide_long_service_output_on_ok", Value: p.hideLongServiceOutputOnOK},
+		{Key: "hide_errors_and_thresholds_on_ok", Value: p.hideErrorsAndThresholdsOnOK},
+		{Key: "dedupe_errors", Value: p.dedupeErrors},
+		{Key: "host_check_mode", Value: p.hostCheckMode},
+		{Key: "component_results", Value: len(p.componentResults)},
+		{Key: "threshold_violations", Value: len(p.thresholdViolations)},
+		{Key: "metrics_summary_section", Value: p.metricsSummarySection},
+		{Key: "output_size_metric", Value: p.shouldEmitTotalPluginSizeMetric},
+		{Key: "extended_perfdata", Value: p.extendedPerfData != nil},
+		{Key: "emit_hooks", Value: len(p.emitHooks)},
+		{Key: "state_hooks", Value: p.stateHookCount()},
+		{Key: "pending_cleanups", Value: len(p.pendingCleanups())},
+		{Key: "debug_log_level", Value: p.debugLogLevel.String()},
+		{Key: "branding_callback", Value: p.BrandingCallback != nil},
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_DebugDumpState_WritesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogLevel(nagios.DebugLogLevelError)
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: disk usage high"
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "used", Value: "91"},
+		nagios.PerformanceData{Label: "free", Value: "9"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.DebugDumpState()
+
+	got := logBuffer.String()
+
+	for _, want := range []string{
+		"Plugin state snapshot",
+		"exit_code=1",
+		"state=WARNING",
+		"perfdata_labels=[free used]",
+		"payload_bytes=0",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: %q not found in debug log output:\n%s", want, got)
+		}
+	}
+
+	t.Log("OK: plugin state snapshot written regardless of debug log level")
+}
+
+func TestPlugin_DebugDumpState_EmitsStructuredFields(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogFormat(nagios.DebugLogFormatJSON)
+	plugin.AddError(nagios.ErrInternalLibraryFailure)
+
+	if _, err := plugin.AddPayloadString("payload"); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+
+	plugin.DebugDumpState()
+
+	var entry struct {
+		Category string                 `json:"category"`
+		Fields   map[string]interface{} `json:"fields"`
+	}
+
+	if err := json.Unmarshal(bytes.TrimSpace(logBuffer.Bytes()), &entry); err != nil {
+		t.Fatalf("ERROR: failed to decode log entry %q: %v", logBuffer.String(), err)
+	}
+
+	if entry.Category != "state" {
+		t.Errorf("ERROR: want category %q, got %q", "state", entry.Category)
+	}
+
+	if got := entry.Fields["error_count"]; got != float64(1) {
+		t.Errorf("ERROR: want error_count 1, got %v", got)
+	}
+
+	if got := entry.Fields["payload_bytes"]; got != float64(len("payload")) {
+		t.Errorf("ERROR: want payload_bytes %d, got %v", len("payload"), got)
+	}
+
+	t.Log("OK: plugin state snapshot fields emitted as expected")
+}
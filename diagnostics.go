@@ -0,0 +1,92 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// cleanupRegistration tracks a cleanup function registered by client code
+// and whether it was run before the plugin exited.
+type cleanupRegistration struct {
+	name string
+	once sync.Once
+	ran  atomic.Bool
+}
+
+// RegisterCleanup records a named cleanup function and returns a wrapper
+// which runs it (at most once). Client code should call (or defer) the
+// returned function in place of the original.
+//
+// Because ReturnCheckResults calls os.Exit, cleanup functions which were not
+// run by the time the plugin exits are silently skipped. If exit diagnostics
+// debug logging is enabled, any registered cleanup functions which never ran
+// are logged to help surface resource leaks.
+func (p *Plugin) RegisterCleanup(name string, fn func()) func() {
+	registration := &cleanupRegistration{name: name}
+
+	p.cleanups = append(p.cleanups, registration)
+
+	p.logAction(fmt.Sprintf("Registered cleanup function %q", name))
+
+	return func() {
+		registration.once.Do(func() {
+			if fn != nil {
+				fn()
+			}
+
+			registration.ran.Store(true)
+		})
+	}
+}
+
+// pendingCleanups returns the names of registered cleanup functions which
+// have not been run.
+func (p *Plugin) pendingCleanups() []string {
+	var pending []string
+	for _, registration := range p.cleanups {
+		if !registration.ran.Load() {
+			pending = append(pending, registration.name)
+		}
+	}
+
+	return pending
+}
+
+// reportExitDiagnostics logs the number of still running goroutines and any
+// registered cleanup functions which never ran. This is a NOOP unless exit
+// diagnostics debug logging is enabled.
+func (p *Plugin) reportExitDiagnostics() {
+	if !p.debugLogging.exitDiagnostics {
+		return
+	}
+
+	p.logExitDiagnostics(fmt.Sprintf(
+		"%d goroutines still running at exit (including the current goroutine)",
+		runtime.NumGoroutine(),
+	))
+
+	pending := p.pendingCleanups()
+	if len(pending) == 0 {
+		p.logExitDiagnostics("All registered cleanup functions ran before exit")
+
+		return
+	}
+
+	p.logExitDiagnostics(fmt.Sprintf(
+		"%d registered cleanup functions never ran",
+		len(pending),
+	))
+
+	for _, name := range pending {
+		p.logExitDiagnostics(fmt.Sprintf("cleanup function %q never ran", name))
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlugin_reportExitDiagnostics_LogsCleanupFunctionsWhichNeverRan(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	var outputBuffer strings.Builder
+
+	plugin.SetDebugLoggingOutputTarget(&outputBuffer)
+	plugin.DebugLoggingEnableExitDiagnostics()
+
+	var closedCount int
+	closeDB := plugin.RegisterCleanup("close database", func() { closedCount++ })
+	_ = plugin.RegisterCleanup("remove temp dir", func() {})
+
+	closeDB()
+	closeDB()
+
+	if closedCount != 1 {
+		t.Errorf("ERROR: want cleanup function run once, got %d", closedCount)
+	}
+
+	plugin.reportExitDiagnostics()
+
+	got := outputBuffer.String()
+
+	switch {
+	case !strings.Contains(got, "goroutines still running at exit"):
+		t.Errorf("ERROR: goroutine count missing from debug log output:\n%s", got)
+	case !strings.Contains(got, `cleanup function "remove temp dir" never ran`):
+		t.Errorf("ERROR: pending cleanup function missing from debug log output:\n%s", got)
+	case strings.Contains(got, `"close database" never ran`):
+		t.Errorf("ERROR: completed cleanup function reported as pending:\n%s", got)
+	default:
+		t.Log("OK: exit diagnostics logged as expected.")
+	}
+}
+
+func TestPlugin_reportExitDiagnostics_ProducesNoOutputWhenDisabled(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	var outputBuffer strings.Builder
+
+	plugin.SetDebugLoggingOutputTarget(&outputBuffer)
+	_ = plugin.RegisterCleanup("remove temp dir", func() {})
+
+	plugin.reportExitDiagnostics()
+
+	if got := outputBuffer.String(); got != "" {
+		t.Errorf("ERROR: unexpected debug log output:\n%s", got)
+	} else {
+		t.Log("OK: No debug logging output captured as expected.")
+	}
+}
@@ -19,24 +19,73 @@ inclusion into the project.
   - Nagios state labels (e.g., StateOKLabel), state exit codes (e.g.,
     StateOKExitCode)
   - Nagios ServiceState type useful in client code as a way to map internal
-    check results to a Nagios service state value
+    check results to a Nagios service state value (with conversion,
+    validation and severity comparison helpers)
+  - Typed State values (e.g., StateWARNING) with validation, label parsing
+    (ParseState) and conversion to exit codes and ServiceState values
   - Nagios CheckOutputEOL constant useful for consistent newline display in
     results displayed in web UI, email notifications
+  - Nagios host check states (UP, DOWN, UNREACHABLE) and optional host check
+    mode (SetHostCheckMode) mapping host states to plugin exit codes
   - Plugin type with ReturnCheckResults method used to process and return
     all applicable check results to Nagios for further processing/display
   - Optional support for collecting/emitting performance data generated by
     plugins (default time metric emitted if using constructor)
+  - Optional functional options (PluginOption) to configure common settings
+    when calling the constructor
+  - Optional time_remaining metric recording the time left before the plugin
+    (or scheduler-provided) timeout is reached
   - Supports "branding" callback function to display application name,
     version, or other information as a "trailer" for check results provided to
     Nagios
   - Panics from client code are captured and reported
+  - Optional crash dump file (stack trace, plugin state, recent debug log
+    entries) written when a panic is detected
   - Support for collecting multiple errors from client code
+  - Optional deduplication of identical error messages in the Errors section
+  - Severity-tagged errors (CriticalError, WarningError, UnknownError) which
+    escalate the plugin state and are listed grouped by severity
+  - Optional inference of the plugin state from recorded errors (see
+    AddErrorWithExitCode and SetErrorClassifier)
   - Support for explicitly omitting Errors section in LongServiceOutput
     (automatically omitted if none were recorded)
   - Support for explicitly omitting Thresholds section in LongServiceOutput
     (automatically omitted if none were recorded)
   - Automatically omit LongServiceOutput section if not specify by client code
-  - Support for overriding text used for section headers/labels
+  - Optional streaming of (size-limited) LongServiceOutput content from an
+    io.Reader at emit time
+  - Optional parallel execution of component checks (RunChecks) using a
+    worker pool with per-check durations
+  - Concurrency-safe wrapper (SyncPlugin) for recording check results from
+    multiple goroutines
+  - State escalation helpers (EscalateState, WorstState) which never
+    downgrade the plugin exit state
+  - Optional aggregation of per-component results (AddResult) with
+    worst-state rollup, summary and per-component breakdown
+  - Threshold violations found by EvaluateThreshold are recorded and listed
+    in a separate output section
+  - Optional metrics summary section generated from collected performance
+    data metrics
+  - Support for overriding text used for section headers/labels, table
+    column headers and generated summaries (individually or as a label
+    bundle; see SetLabels)
+  - Self-test helper (see RunSelfTest) validating rendered output, payload
+    round-trip and performance data syntax of a synthetic check result
+  - Exported plugin output length limits of common monitoring systems and
+    transports (see OutputLimitFor)
+  - Plugin values share no package-level mutable state; separate values may
+    be used concurrently (e.g., by parallel tests using a custom exit
+    function and output target)
+  - Optional construction of check results (CheckResultBuilder) separate
+    from their emission (CheckResultEmitter) as plugin output, JSON or
+    passive check results
+  - Optional custom exit function (SetExitFunc) used in place of os.Exit to
+    allow embedding plugins in test harnesses, agents and schedulers
+  - Rendering of plugin output and exit code without exiting (Render)
+  - Optional context-aware check execution (RunWithContext) emitting an
+    UNKNOWN check result if the deadline is reached before the check completes
+  - Optional per-state hooks (OnWarning, OnCritical, OnUnknown) called
+    before plugin output is rendered to enrich results for a specific state
 
 # HOW TO USE
 
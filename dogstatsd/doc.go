@@ -0,0 +1,44 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package dogstatsd exports Nagios plugin performance data as DogStatsD
+gauges over UDP.
+
+# OVERVIEW
+
+Each performance data metric is sent as a gauge using the DogStatsD
+(statsd extended) datagram format:
+
+	prefix.label:value|g|#host:web01,service:http,state:warning
+
+The host name, service description and plugin state are attached as tags
+along with any additional tags specified by client code. Metric names and
+tags are sanitized to the characters accepted by the Datadog Agent. Metrics
+with an undetermined value ("U") are skipped.
+
+This is intended for teams mirroring check metrics into Datadog while
+Nagios remains the system of record. Metrics are sent using UDP without
+acknowledgement; delivery is not guaranteed.
+
+The Client.EmitHook method returns a nagios.EmitHookFunc which sends the
+collected performance data when the plugin emits its output (see
+nagios.Plugin.AddEmitHook). Errors are ignored when sending from the emit
+hook; call Client.Send directly if errors need to be handled.
+
+# HOW TO USE
+
+	client := dogstatsd.NewClient("127.0.0.1:8125")
+	client.SetPrefix("nagios")
+	client.SetTags("env:prod")
+
+	plugin := nagios.NewPlugin()
+	plugin.AddEmitHook(client.EmitHook("web01", "HTTP"))
+
+	defer plugin.ReturnCheckResults()
+*/
+package dogstatsd
@@ -0,0 +1,256 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package dogstatsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultPort is the default UDP port used by the DogStatsD server.
+	DefaultPort string = "8125"
+
+	// maxDatagramSize is the maximum size of a single datagram. This is the
+	// size recommended by Datadog to avoid IP fragmentation.
+	maxDatagramSize int = 1432
+
+	// defaultTimeout is used for sending datagrams if not overridden.
+	defaultTimeout time.Duration = 2 * time.Second
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the DogStatsD server address was not
+	// provided.
+	ErrMissingAddress = errors.New("DogStatsD address not specified")
+
+	// ErrNoMetrics indicates that no metrics were available for submission.
+	ErrNoMetrics = errors.New("no metrics provided")
+
+	// ErrInvalidMetricValue indicates that a performance data value could
+	// not be converted to a numeric value.
+	ErrInvalidMetricValue = errors.New("invalid performance data value")
+)
+
+// Client sends performance data to a DogStatsD server.
+type Client struct {
+	// address is the DogStatsD server address in host:port format.
+	address string
+
+	// prefix is the optional namespace prepended to metric names.
+	prefix string
+
+	// tags is the collection of additional tags attached to each metric.
+	tags []string
+
+	// timeout limits the time spent sending datagrams.
+	timeout time.Duration
+
+	// dialer is used to create the UDP socket.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given DogStatsD server address.
+// If a port is not included in the address the default port is used.
+func NewClient(address string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address: address,
+		timeout: defaultTimeout,
+	}
+}
+
+// SetPrefix specifies the namespace prepended (followed by a dot) to metric
+// names.
+func (c *Client) SetPrefix(prefix string) {
+	c.prefix = strings.Trim(prefix, ".")
+}
+
+// SetTags specifies additional tags (e.g., "env:prod") attached to each
+// metric, replacing any previously specified tags.
+func (c *Client) SetTags(tags ...string) {
+	c.tags = make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = sanitizeTag(tag); tag != "" {
+			c.tags = append(c.tags, tag)
+		}
+	}
+}
+
+// SetTimeout overrides the default timeout used when sending datagrams.
+// Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// Lines returns the given performance data as DogStatsD gauge lines tagged
+// with the given host, service and plugin state. Empty host or service
+// values are not tagged. Metrics with an undetermined value ("U") are
+// skipped.
+func (c *Client) Lines(host string, service string, exitCode int, metrics ...nagios.PerformanceData) ([]string, error) {
+	tags := make([]string, 0, len(c.tags)+3)
+	if host != "" {
+		tags = append(tags, sanitizeTag("host:"+host))
+	}
+	if service != "" {
+		tags = append(tags, sanitizeTag("service:"+service))
+	}
+	tags = append(tags, sanitizeTag("state:"+nagios.ExitCodeToStateLabel(exitCode)))
+	tags = append(tags, c.tags...)
+
+	tagSuffix := "|#" + strings.Join(tags, ",")
+
+	lines := make([]string, 0, len(metrics))
+	for _, pd := range metrics {
+		if pd.Value == "U" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(pd.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q value %q: %w", pd.Label, pd.Value, ErrInvalidMetricValue)
+		}
+
+		lines = append(lines, c.metricName(pd.Label)+":"+
+			strconv.FormatFloat(value, 'f', -1, 64)+"|g"+tagSuffix)
+	}
+
+	return lines, nil
+}
+
+// Send sends the given performance data to the DogStatsD server as tagged
+// gauges. Lines are combined into as few datagrams as possible.
+func (c *Client) Send(ctx context.Context, host string, service string, exitCode int, metrics ...nagios.PerformanceData) error {
+	if c.address == "" {
+		return ErrMissingAddress
+	}
+
+	lines, err := c.Lines(host, service, exitCode, metrics...)
+	if err != nil {
+		return err
+	}
+
+	if len(lines) == 0 {
+		return ErrNoMetrics
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, "udp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to DogStatsD: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return fmt.Errorf("failed to set DogStatsD connection deadline: %w", err)
+	}
+
+	for _, datagram := range datagrams(lines) {
+		if _, err := conn.Write([]byte(datagram)); err != nil {
+			return fmt.Errorf("failed to send metrics to DogStatsD: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// EmitHook returns a function suitable for use with
+// nagios.Plugin.AddEmitHook which sends the collected performance data and
+// plugin state for the given host and service when plugin output is
+// emitted. Errors are ignored.
+func (c *Client) EmitHook(host string, service string) nagios.EmitHookFunc {
+	return func(p *nagios.Plugin) {
+		_ = c.Send(context.Background(), host, service, p.ExitStatusCode, p.PerfData()...)
+	}
+}
+
+// metricName returns the sanitized metric name for the given performance
+// data label, including the namespace prefix (if set).
+func (c *Client) metricName(label string) string {
+	name := sanitizeName(label)
+	if c.prefix == "" {
+		return name
+	}
+
+	return sanitizeName(c.prefix) + "." + name
+}
+
+// datagrams combines the given lines into newline separated datagrams no
+// larger than the maximum datagram size. Lines larger than the maximum size
+// are sent in their own datagram.
+func datagrams(lines []string) []string {
+	var collection []string
+	var current strings.Builder
+
+	for _, line := range lines {
+		if current.Len() > 0 && current.Len()+1+len(line) > maxDatagramSize {
+			collection = append(collection, current.String())
+			current.Reset()
+		}
+
+		if current.Len() > 0 {
+			current.WriteByte('\n')
+		}
+		current.WriteString(line)
+	}
+
+	if current.Len() > 0 {
+		collection = append(collection, current.String())
+	}
+
+	return collection
+}
+
+// sanitizeName replaces characters not permitted in DogStatsD metric names
+// with underscores.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// sanitizeTag lowercases the given tag and replaces characters which would
+// break the DogStatsD datagram format with underscores.
+func sanitizeTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '|', '#', '@', ' ', '\t', '\r', '\n':
+			return '_'
+		default:
+			return r
+		}
+	}, strings.ToLower(strings.TrimSpace(tag)))
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package dogstatsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestClient_Lines_TagsHostServiceAndState(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("127.0.0.1")
+	client.SetPrefix("nagios.")
+	client.SetTags("Env:Prod")
+
+	got, err := client.Lines("web01", "HTTP Check", nagios.StateWARNINGExitCode,
+		nagios.PerformanceData{Label: "response time", Value: "0.250", UnitOfMeasurement: "s"},
+		nagios.PerformanceData{Label: "pending", Value: "U"},
+	)
+	if err != nil {
+		t.Fatalf("ERROR: failed to generate lines: %v", err)
+	}
+
+	want := []string{
+		"nagios.response_time:0.25|g|#host:web01,service:http_check,state:warning,env:prod",
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: DogStatsD lines generated as expected")
+}
+
+func TestClient_EmitHook_SendsMetricsWhenPluginEmitsOutput(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	client := NewClient(listener.LocalAddr().String())
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.AddEmitHook(client.EmitHook("web01", "HTTP"))
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ServiceOutput = "CRITICAL: down"
+
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "down", Value: "2"}); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	if err := listener.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("ERROR: failed to set read deadline: %v", err)
+	}
+
+	buf := make([]byte, maxDatagramSize)
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ERROR: failed to receive datagram: %v", err)
+	}
+
+	got := strings.Split(string(buf[:n]), "\n")
+
+	// The default time metric is emitted alongside client-provided metrics.
+	if len(got) != 2 || got[0] != "down:2|g|#host:web01,service:http,state:critical" ||
+		!strings.HasPrefix(got[1], "time:") {
+		t.Fatalf("ERROR: unexpected datagram %q", buf[:n])
+	}
+
+	t.Log("OK: metrics sent from emit hook as expected")
+}
+
+func TestDatagrams_SplitsAtMaximumSize(t *testing.T) {
+	t.Parallel()
+
+	line := strings.Repeat("x", 600)
+
+	got := datagrams([]string{line, line, line})
+	if len(got) != 2 || len(got[0]) != 1201 || got[1] != line {
+		t.Fatalf("ERROR: unexpected datagram sizes for %d datagrams", len(got))
+	}
+
+	t.Log("OK: lines split into datagrams as expected")
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// emptyServiceOutputSummary is the summary text used in place of an empty
+// ServiceOutput field if client code opted to use an UNKNOWN state for
+// missing summary output.
+const emptyServiceOutputSummary string = "plugin produced no summary output"
+
+// EnableUnknownOnEmptyServiceOutput indicates that an empty (or whitespace
+// only) ServiceOutput field should not result in empty plugin output. If
+// enabled, the ServiceOutput field is replaced with a generated summary
+// ("UNKNOWN: plugin produced no summary output") and the plugin state is
+// escalated to UNKNOWN (if not already more severe) when plugin output is
+// processed.
+func (p *Plugin) EnableUnknownOnEmptyServiceOutput() {
+	p.logAction("Enabling UNKNOWN state for empty ServiceOutput as requested")
+	p.unknownOnEmptyServiceOutput = true
+}
+
+// checkEmptyServiceOutput replaces an empty ServiceOutput field with a
+// generated summary and escalates the plugin state to UNKNOWN. This is a
+// NOOP unless client code opted to use an UNKNOWN state for missing summary
+// output.
+func (p *Plugin) checkEmptyServiceOutput() {
+	if !p.unknownOnEmptyServiceOutput || strings.TrimSpace(p.ServiceOutput) != "" {
+		return
+	}
+
+	p.logActionLevel(DebugLogLevelWarn, "Empty ServiceOutput detected, generating summary")
+
+	if escalated := p.escalatedExitState(p.ExitStatusCode, StateUNKNOWNExitCode); escalated != p.ExitStatusCode {
+		p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf(
+			"Escalating plugin exit state to %s due to empty ServiceOutput",
+			p.stateLabel(escalated),
+		))
+		p.ExitStatusCode = escalated
+	}
+
+	p.ServiceOutput = fmt.Sprintf(
+		"%s: %s",
+		p.stateLabel(p.ExitStatusCode),
+		p.getEmptyServiceOutputLabelText(),
+	)
+}
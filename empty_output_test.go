@@ -0,0 +1,104 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestEnableUnknownOnEmptyServiceOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		enabled       bool
+		serviceOutput string
+		exitCode      int
+		wantExitCode  int
+		wantPrefix    string
+	}{
+		"option disabled": {
+			enabled:      false,
+			exitCode:     nagios.StateOKExitCode,
+			wantExitCode: nagios.StateOKExitCode,
+			wantPrefix:   "",
+		},
+		"empty summary escalates OK state": {
+			enabled:      true,
+			exitCode:     nagios.StateOKExitCode,
+			wantExitCode: nagios.StateUNKNOWNExitCode,
+			wantPrefix:   "UNKNOWN: plugin produced no summary output",
+		},
+		"whitespace only summary": {
+			enabled:       true,
+			serviceOutput: " \t\n",
+			exitCode:      nagios.StateOKExitCode,
+			wantExitCode:  nagios.StateUNKNOWNExitCode,
+			wantPrefix:    "UNKNOWN: plugin produced no summary output",
+		},
+		"more severe state retained": {
+			enabled:      true,
+			exitCode:     nagios.StateCRITICALExitCode,
+			wantExitCode: nagios.StateCRITICALExitCode,
+			wantPrefix:   "CRITICAL: plugin produced no summary output",
+		},
+		"summary provided": {
+			enabled:       true,
+			serviceOutput: "OK: all widgets accounted for",
+			exitCode:      nagios.StateOKExitCode,
+			wantExitCode:  nagios.StateOKExitCode,
+			wantPrefix:    "OK: all widgets accounted for",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+			var gotExitCode int
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SetExitFunc(func(code int) { gotExitCode = code })
+
+			if tt.enabled {
+				plugin.EnableUnknownOnEmptyServiceOutput()
+			}
+
+			plugin.ServiceOutput = tt.serviceOutput
+			plugin.ExitStatusCode = tt.exitCode
+
+			// Provide a time metric so that output is not dependent on timing.
+			if err := plugin.AddPerfData(false,
+				nagios.PerformanceData{Label: "widgets", Value: "1"},
+				nagios.PerformanceData{Label: "time", Value: "1", UnitOfMeasurement: "ms"},
+			); err != nil {
+				t.Fatalf("ERROR: failed to add perfdata: %v", err)
+			}
+
+			plugin.ReturnCheckResults()
+
+			if gotExitCode != tt.wantExitCode {
+				t.Errorf("ERROR: want exit code %d, got %d", tt.wantExitCode, gotExitCode)
+			}
+
+			switch {
+			case tt.wantPrefix == "" && outputBuffer.Len() != 0:
+				t.Fatalf("ERROR: want no output, got %q", outputBuffer.String())
+			case !strings.HasPrefix(outputBuffer.String(), tt.wantPrefix):
+				t.Fatalf("ERROR: want output prefix %q, got %q", tt.wantPrefix, outputBuffer.String())
+			}
+
+			t.Log("OK: empty ServiceOutput handled as expected")
+		})
+	}
+}
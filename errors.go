@@ -0,0 +1,419 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// missingContextValue is used in place of the value for an error context key
+// provided without a matching value.
+const missingContextValue string = "(missing)"
+
+// suggestedActionPrefix precedes the remediation hint emitted for a
+// ServiceCheckError in the errors section of the plugin output.
+const suggestedActionPrefix string = "Suggested action: "
+
+// ServiceCheckError is an error type which carries the service state
+// associated with a problem encountered during plugin execution along with
+// optional remediation advice and metadata.
+//
+// When recorded via AddError (or related methods), the associated service
+// state is used to escalate the final plugin exit state and the remediation
+// hint (if provided) is emitted as a "Suggested action" line following the
+// error in the errors section of the plugin output. Errors in the errors
+// section are grouped by service state, most severe first.
+//
+// See also CriticalError, WarningError and UnknownError.
+type ServiceCheckError struct {
+	// Message is the human-readable description of the problem.
+	Message string
+
+	// State is the service state associated with the problem.
+	State ServiceState
+
+	// Hint is optional advice for remediating the problem.
+	Hint string
+
+	// Metadata is an optional collection of key/value pairs providing
+	// additional context for the problem (e.g., hostname, port).
+	Metadata map[string]string
+
+	// Err is the optional underlying cause of the problem.
+	Err error
+}
+
+// NewServiceCheckError creates a new ServiceCheckError for the given state
+// and message.
+func NewServiceCheckError(state ServiceState, msg string) *ServiceCheckError {
+	return &ServiceCheckError{
+		Message: msg,
+		State:   state,
+	}
+}
+
+// Error satisfies the error interface. The message is followed by the
+// underlying cause (if set).
+func (sce *ServiceCheckError) Error() string {
+	switch {
+	case sce.Err != nil && sce.Message != "":
+		return fmt.Sprintf("%s: %v", sce.Message, sce.Err)
+	case sce.Err != nil:
+		return sce.Err.Error()
+	default:
+		return sce.Message
+	}
+}
+
+// Unwrap returns the underlying cause (if any) to support errors.Is and
+// errors.As.
+func (sce *ServiceCheckError) Unwrap() error {
+	return sce.Err
+}
+
+// WithHint sets the remediation hint for the error and returns the error to
+// allow chaining.
+func (sce *ServiceCheckError) WithHint(hint string) *ServiceCheckError {
+	sce.Hint = hint
+
+	return sce
+}
+
+// WithMetadata records the given key/value pair as metadata for the error
+// and returns the error to allow chaining.
+func (sce *ServiceCheckError) WithMetadata(key string, value string) *ServiceCheckError {
+	if sce.Metadata == nil {
+		sce.Metadata = make(map[string]string)
+	}
+
+	sce.Metadata[key] = value
+
+	return sce
+}
+
+// WithCause sets the underlying cause for the error and returns the error to
+// allow chaining.
+func (sce *ServiceCheckError) WithCause(err error) *ServiceCheckError {
+	sce.Err = err
+
+	return sce
+}
+
+// EnableErrorDeduplication indicates that identical error messages (e.g.,
+// from retried operations) should be listed once in the Errors section. A
+// count of the occurrences is appended to the listed error message. All
+// errors remain in the Errors collection.
+func (p *Plugin) EnableErrorDeduplication() {
+	p.logAction("Enabling error deduplication as requested")
+	p.dedupeErrors = true
+}
+
+// errorsWithCounts returns the given errors with nil values removed along with
+// the number of occurrences of each error message. If requested, only the
+// first error with a given message is returned; otherwise each error is
+// returned with a count of one.
+func (p Plugin) errorsWithCounts(errs []error) ([]error, []int) {
+	unique := make([]error, 0, len(errs))
+	counts := make([]int, 0, len(errs))
+	index := make(map[string]int, len(errs))
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if p.dedupeErrors {
+			if i, ok := index[err.Error()]; ok {
+				counts[i]++
+				continue
+			}
+
+			index[err.Error()] = len(unique)
+		}
+
+		unique = append(unique, err)
+		counts = append(counts, 1)
+	}
+
+	return unique, counts
+}
+
+// CriticalError returns an error wrapping the given error which carries the
+// CRITICAL service state (see ServiceCheckError). Nil is returned if the
+// given error is nil.
+func CriticalError(err error) error {
+	return newSeverityError(StateCRITICALExitCode, err)
+}
+
+// WarningError returns an error wrapping the given error which carries the
+// WARNING service state (see ServiceCheckError). Nil is returned if the
+// given error is nil.
+func WarningError(err error) error {
+	return newSeverityError(StateWARNINGExitCode, err)
+}
+
+// UnknownError returns an error wrapping the given error which carries the
+// UNKNOWN service state (see ServiceCheckError). Nil is returned if the
+// given error is nil.
+func UnknownError(err error) error {
+	return newSeverityError(StateUNKNOWNExitCode, err)
+}
+
+// newSeverityError returns a ServiceCheckError wrapping the given error with
+// the service state for the given exit code or nil if the given error is
+// nil.
+func newSeverityError(exitCode int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &ServiceCheckError{
+		State: ServiceState{
+			Label:    ExitCodeToStateLabel(exitCode),
+			ExitCode: exitCode,
+		},
+		Err: err,
+	}
+}
+
+// errorsBySeverity returns a copy of the given errors grouped by the
+// service state of any ServiceCheckError values in the collection, most
+// severe first. Errors without a service state follow in their original
+// order. The order of errors sharing a service state is retained.
+func errorsBySeverity(errs []error) []error {
+	sorted := make([]error, len(errs))
+	copy(sorted, errs)
+
+	// Errors without a service state sort after all others.
+	severity := func(err error) int {
+		sce := asServiceCheckError(err)
+		if sce == nil || sce.State.Label == "" {
+			return -1
+		}
+
+		return exitCodeSeverity(sce.State.ExitCode)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return severity(sorted[i]) > severity(sorted[j])
+	})
+
+	return sorted
+}
+
+// asServiceCheckError returns the first ServiceCheckError found in the
+// given error's chain or nil if not present.
+func asServiceCheckError(err error) *ServiceCheckError {
+	var sce *ServiceCheckError
+	if errors.As(err, &sce) {
+		return sce
+	}
+
+	return nil
+}
+
+// escalateStateFromErrors updates the plugin exit state using the service
+// state associated with any ServiceCheckError values in the given
+// collection or, for other errors, the exit code returned by the error
+// classifier (if set). The exit state is only changed if a more severe state
+// is found.
+func (p *Plugin) escalateStateFromErrors(errs ...error) {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		exitCode, ok := p.errorExitCode(err)
+		if !ok {
+			continue
+		}
+
+		p.EscalateState(exitCode)
+	}
+}
+
+// errorExitCode returns the exit code associated with the given error and
+// whether an exit code was found. The service state of a ServiceCheckError
+// takes precedence over the error classifier.
+func (p *Plugin) errorExitCode(err error) (int, bool) {
+	if sce := asServiceCheckError(err); sce != nil {
+		return sce.State.ExitCode, true
+	}
+
+	if p.errorClassifier != nil {
+		if exitCode, ok := p.errorClassifier(err); ok {
+			return supportedExitCodeOrUnknown(exitCode), true
+		}
+	}
+
+	return 0, false
+}
+
+// supportedExitCodeOrUnknown returns the given exit code if it is a
+// supported plugin exit code, otherwise StateUNKNOWNExitCode.
+func supportedExitCodeOrUnknown(exitCode int) int {
+	for _, supported := range SupportedExitCodes() {
+		if exitCode == supported {
+			return exitCode
+		}
+	}
+
+	return StateUNKNOWNExitCode
+}
+
+// ErrorClassifierFunc returns the plugin exit code associated with the
+// given error and whether the error was classified. See SetErrorClassifier.
+type ErrorClassifierFunc func(err error) (exitCode int, ok bool)
+
+// ErrorClassification associates errors matching a target error (as
+// reported by errors.Is) with a plugin exit code. See NewErrorClassifier.
+type ErrorClassification struct {
+	// Target is the error matched using errors.Is (e.g.,
+	// context.DeadlineExceeded or syscall.ECONNREFUSED).
+	Target error
+
+	// ExitCode is the plugin exit code associated with matching errors.
+	ExitCode int
+}
+
+// NewErrorClassifier returns an error classifier which uses the exit code of
+// the first given classification with a Target matching an error. Errors
+// not matching any classification are not classified.
+//
+// This allows an organization to share consistent error to state mappings
+// across plugins, for example:
+//
+//	nagios.NewErrorClassifier(
+//		nagios.ErrorClassification{Target: context.DeadlineExceeded, ExitCode: nagios.StateUNKNOWNExitCode},
+//		nagios.ErrorClassification{Target: syscall.ECONNREFUSED, ExitCode: nagios.StateCRITICALExitCode},
+//	)
+func NewErrorClassifier(classifications ...ErrorClassification) ErrorClassifierFunc {
+	rules := make([]ErrorClassification, len(classifications))
+	copy(rules, classifications)
+
+	return func(err error) (int, bool) {
+		for _, rule := range rules {
+			if errors.Is(err, rule.Target) {
+				return rule.ExitCode, true
+			}
+		}
+
+		return 0, false
+	}
+}
+
+// SetErrorClassifier sets the function used to infer the plugin exit state
+// from errors later recorded via AddError (or related methods). If the
+// classifier returns an exit code for an error, the plugin exit state is
+// escalated to that state (an unsupported exit code is treated as UNKNOWN);
+// the exit state is never downgraded. The service state of a
+// ServiceCheckError takes precedence over the classifier.
+//
+// A nil value removes the classifier.
+func (p *Plugin) SetErrorClassifier(classifier ErrorClassifierFunc) {
+	p.errorClassifier = classifier
+}
+
+// AddErrorWithExitCode appends the given error to the collection and
+// escalates the plugin exit state to the given exit code; the exit state is
+// never downgraded. An unsupported exit code is treated as UNKNOWN. The
+// given exit code takes precedence over the error classifier (if set). A nil
+// error is ignored.
+func (p *Plugin) AddErrorWithExitCode(err error, exitCode int) {
+	if err == nil {
+		return
+	}
+
+	p.Errors = append(p.Errors, err)
+
+	p.logAction(fmt.Sprintf("1 error added to collection with exit code %d", exitCode))
+
+	p.EscalateState(exitCode)
+}
+
+// contextError wraps an error with key/value context describing where the
+// error came from (e.g., target host, check stage).
+type contextError struct {
+	err    error
+	fields []string
+}
+
+// Error satisfies the error interface. The original error message is
+// followed by the context fields in the order given.
+func (ce *contextError) Error() string {
+	pairs := make([]string, 0, len(ce.fields)/2+1)
+	for i := 0; i < len(ce.fields); i += 2 {
+		value := missingContextValue
+		if i+1 < len(ce.fields) {
+			value = ce.fields[i+1]
+		}
+		pairs = append(pairs, ce.fields[i]+": "+value)
+	}
+
+	return fmt.Sprintf("%v (%s)", ce.err, strings.Join(pairs, ", "))
+}
+
+// Unwrap returns the original error to support errors.Is and errors.As.
+func (ce *contextError) Unwrap() error {
+	return ce.err
+}
+
+// AddErrorf formats an error according to the given format specifier and
+// appends it to the collection. The %w verb is supported for wrapping errors
+// in the same way as fmt.Errorf.
+func (p *Plugin) AddErrorf(format string, args ...interface{}) {
+	p.AddError(fmt.Errorf(format, args...))
+}
+
+// AddErrorWithContext appends the given error to the collection along with
+// key/value pairs describing where the error came from (e.g., "target",
+// hostname, "stage", "connect"). The context is included when the error is
+// emitted in the errors section of the plugin output.
+//
+// If an odd number of key/value arguments is given the final key is recorded
+// with a placeholder value. A nil error is ignored.
+func (p *Plugin) AddErrorWithContext(err error, keysAndValues ...string) {
+	if err == nil {
+		return
+	}
+
+	if len(keysAndValues) == 0 {
+		p.AddError(err)
+
+		return
+	}
+
+	p.AddError(&contextError{
+		err:    err,
+		fields: keysAndValues,
+	})
+}
+
+// normalizeErrors folds the deprecated LastError field into the Errors
+// collection so that all later processing only needs to handle a single
+// collection of errors. The LastError value is placed at the start of the
+// collection to retain the previous output order and the field is cleared to
+// prevent duplicate processing.
+func (p *Plugin) normalizeErrors() {
+	if p.LastError == nil {
+		return
+	}
+
+	p.logAction("Deprecated field p.LastError is set; use the Errors field or AddError method instead")
+	p.logAction("Moving field p.LastError value to the start of the p.Errors collection")
+
+	lastErr := p.LastError
+	p.LastError = nil
+
+	p.Errors = append([]error{lastErr}, p.Errors...)
+
+	p.escalateStateFromErrors(lastErr)
+}
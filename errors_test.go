@@ -0,0 +1,340 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAddError_ServiceCheckErrorEscalatesExitState(t *testing.T) {
+	t.Parallel()
+
+	criticalState := nagios.ServiceState{
+		Label:    nagios.StateCRITICALLabel,
+		ExitCode: nagios.StateCRITICALExitCode,
+	}
+
+	warningState := nagios.ServiceState{
+		Label:    nagios.StateWARNINGLabel,
+		ExitCode: nagios.StateWARNINGExitCode,
+	}
+
+	plugin := nagios.NewPlugin()
+
+	plugin.AddError(errors.New("plain error"))
+	if plugin.ExitStatusCode != nagios.StateOKExitCode {
+		t.Fatalf("ERROR: plain error changed exit state to %d", plugin.ExitStatusCode)
+	}
+
+	plugin.AddError(fmt.Errorf(
+		"wrapped: %w",
+		nagios.NewServiceCheckError(criticalState, "database unreachable"),
+	))
+	if plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, plugin.ExitStatusCode)
+	}
+
+	plugin.AddError(nagios.NewServiceCheckError(warningState, "disk usage high"))
+	if plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Fatalf("ERROR: exit state downgraded to %d", plugin.ExitStatusCode)
+	}
+
+	t.Log("OK: exit state escalated and not downgraded as expected")
+}
+
+func TestServiceCheckError_HintEmittedAsSuggestedAction(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SkipOSExit()
+
+	var outputBuffer strings.Builder
+	plugin.SetOutputTarget(&outputBuffer)
+
+	sce := nagios.NewServiceCheckError(
+		nagios.ServiceState{Label: nagios.StateUNKNOWNLabel, ExitCode: nagios.StateUNKNOWNExitCode},
+		"failed to query API",
+	).WithCause(context.DeadlineExceeded).
+		WithHint("increase the plugin timeout").
+		WithMetadata("host", "api.example.com")
+
+	plugin.ServiceOutput = "UNKNOWN: failed to query API"
+	plugin.AddError(sce)
+
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+
+	want := "* failed to query API: context deadline exceeded" + nagios.CheckOutputEOL +
+		"  Suggested action: increase the plugin timeout" + nagios.CheckOutputEOL
+
+	switch {
+	case !strings.Contains(got, want):
+		t.Errorf("ERROR: suggested action not found in output:\n%s", got)
+	case !errors.Is(sce, context.DeadlineExceeded):
+		t.Error("ERROR: underlying cause not reachable via errors.Is")
+	case sce.Metadata["host"] != "api.example.com":
+		t.Error("ERROR: metadata not recorded")
+	case plugin.ExitStatusCode != nagios.StateUNKNOWNExitCode:
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateUNKNOWNExitCode, plugin.ExitStatusCode)
+	default:
+		t.Log("OK: suggested action emitted as expected")
+	}
+}
+
+func TestAddErrorf_AndAddErrorWithContext_RecordErrors(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	plugin.AddErrorf("failed to read %q: %w", "/etc/widgets.conf", context.Canceled)
+	plugin.AddErrorWithContext(errors.New("connection refused"), "target", "db01.example.com", "stage")
+	plugin.AddErrorWithContext(nil, "target", "ignored")
+
+	if len(plugin.Errors) != 2 {
+		t.Fatalf("ERROR: want 2 recorded errors, got %d", len(plugin.Errors))
+	}
+
+	want := []string{
+		`failed to read "/etc/widgets.conf": context canceled`,
+		"connection refused (target: db01.example.com, stage: (missing))",
+	}
+
+	for i := range want {
+		if got := plugin.Errors[i].Error(); got != want[i] {
+			t.Errorf("ERROR: want %q, got %q", want[i], got)
+		}
+	}
+
+	if !errors.Is(plugin.Errors[0], context.Canceled) {
+		t.Error("ERROR: wrapped error not reachable via errors.Is")
+	}
+
+	t.Log("OK: errors recorded as expected")
+}
+
+func TestPlugin_AddErrorWithExitCode_EscalatesExitState(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	plugin.AddErrorWithExitCode(errors.New("invalid state"), 42)
+	if plugin.ExitStatusCode != nagios.StateUNKNOWNExitCode {
+		t.Fatalf("ERROR: want unsupported exit code treated as %d, got %d", nagios.StateUNKNOWNExitCode, plugin.ExitStatusCode)
+	}
+
+	plugin.AddErrorWithExitCode(errors.New("disk usage high"), nagios.StateWARNINGExitCode)
+	if plugin.ExitStatusCode != nagios.StateWARNINGExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, plugin.ExitStatusCode)
+	}
+
+	plugin.AddErrorWithExitCode(errors.New("minor problem"), nagios.StateOKExitCode)
+	if plugin.ExitStatusCode != nagios.StateWARNINGExitCode {
+		t.Fatalf("ERROR: exit state downgraded to %d", plugin.ExitStatusCode)
+	}
+
+	plugin.AddErrorWithExitCode(nil, nagios.StateCRITICALExitCode)
+
+	if len(plugin.Errors) != 3 {
+		t.Fatalf("ERROR: want 3 recorded errors, got %d", len(plugin.Errors))
+	}
+
+	t.Log("OK: exit state escalated and not downgraded as expected")
+}
+
+func TestPlugin_SetErrorClassifier_InfersExitState(t *testing.T) {
+	t.Parallel()
+
+	errConnectionRefused := errors.New("connection refused")
+
+	classifier := nagios.NewErrorClassifier(
+		nagios.ErrorClassification{Target: context.DeadlineExceeded, ExitCode: nagios.StateUNKNOWNExitCode},
+		nagios.ErrorClassification{Target: errConnectionRefused, ExitCode: nagios.StateCRITICALExitCode},
+	)
+
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"unclassified error": {
+			err:  errors.New("plain error"),
+			want: nagios.StateOKExitCode,
+		},
+		"deadline exceeded": {
+			err:  fmt.Errorf("query failed: %w", context.DeadlineExceeded),
+			want: nagios.StateUNKNOWNExitCode,
+		},
+		"connection refused": {
+			err:  fmt.Errorf("dial db01: %w", errConnectionRefused),
+			want: nagios.StateCRITICALExitCode,
+		},
+		"service check error takes precedence": {
+			err: nagios.NewServiceCheckError(
+				nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode},
+				"replica lagging",
+			).WithCause(errConnectionRefused),
+			want: nagios.StateWARNINGExitCode,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := nagios.NewPlugin()
+			plugin.SetErrorClassifier(classifier)
+			plugin.AddError(tt.err)
+
+			if plugin.ExitStatusCode != tt.want {
+				t.Fatalf("ERROR: want exit code %d, got %d", tt.want, plugin.ExitStatusCode)
+			}
+
+			t.Log("OK: exit state inferred from error as expected")
+		})
+	}
+}
+
+func TestSeverityErrors_EscalateAndGroupBySeverity(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "problems found"
+
+	plugin.AddError(
+		errors.New("plain error"),
+		nagios.WarningError(errors.New("disk usage high")),
+		nagios.UnknownError(errors.New("metrics unavailable")),
+		nagios.CriticalError(errors.New("database unreachable")),
+		nagios.WarningError(errors.New("swap usage high")),
+		nagios.CriticalError(nil),
+	)
+
+	if plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, plugin.ExitStatusCode)
+	}
+
+	plugin.ReturnCheckResults()
+
+	want := "* database unreachable" + nagios.CheckOutputEOL +
+		"* disk usage high" + nagios.CheckOutputEOL +
+		"* swap usage high" + nagios.CheckOutputEOL +
+		"* metrics unavailable" + nagios.CheckOutputEOL +
+		"* plain error" + nagios.CheckOutputEOL
+
+	if got := outputBuffer.String(); !strings.Contains(got, want) {
+		t.Fatalf("ERROR: want errors grouped by severity:\n%q\ngot:\n%q", want, got)
+	}
+
+	var sce *nagios.ServiceCheckError
+	if !errors.As(nagios.WarningError(context.Canceled), &sce) || sce.State.Label != nagios.StateWARNINGLabel {
+		t.Fatal("ERROR: want ServiceCheckError with WARNING state")
+	}
+
+	if !errors.Is(nagios.CriticalError(context.Canceled), context.Canceled) {
+		t.Fatal("ERROR: wrapped error not reachable via errors.Is")
+	}
+
+	t.Log("OK: severity-tagged errors escalated and grouped as expected")
+}
+
+func TestAddUniqueError_SkipsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	plugin.AddError(errors.New("connection refused"), nil)
+	plugin.AddUniqueError(
+		errors.New("Connection Refused"),
+		errors.New("timeout"),
+		errors.New("timeout"),
+		nil,
+	)
+
+	want := []string{"connection refused", "timeout"}
+
+	var got []string
+	for _, err := range plugin.Errors {
+		if err != nil {
+			got = append(got, err.Error())
+		}
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: duplicate errors skipped as expected")
+}
+
+func TestPlugin_EnableErrorDeduplication(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dedupe bool
+		want   string
+	}{
+		"disabled": {
+			want: "* connection refused" + nagios.CheckOutputEOL +
+				"* connection refused" + nagios.CheckOutputEOL +
+				"* timeout" + nagios.CheckOutputEOL +
+				"* connection refused" + nagios.CheckOutputEOL,
+		},
+		"enabled": {
+			dedupe: true,
+			want: "* connection refused (repeated 3 times)" + nagios.CheckOutputEOL +
+				"* timeout" + nagios.CheckOutputEOL,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.ServiceOutput = "CRITICAL: retries exhausted"
+
+			if tt.dedupe {
+				plugin.EnableErrorDeduplication()
+			}
+
+			for _, msg := range []string{"connection refused", "connection refused", "timeout", "connection refused"} {
+				plugin.AddErrorf("%s", msg)
+			}
+
+			plugin.ReturnCheckResults()
+
+			if got := outputBuffer.String(); !strings.Contains(got, "**ERRORS**"+nagios.CheckOutputEOL+nagios.CheckOutputEOL+tt.want) {
+				t.Fatalf("ERROR: want errors section listing:\n%q\ngot:\n%q", tt.want, got)
+			}
+
+			if len(plugin.Errors) != 4 {
+				t.Fatalf("ERROR: want 4 recorded errors, got %d", len(plugin.Errors))
+			}
+
+			t.Log("OK: errors listed as expected")
+		})
+	}
+}
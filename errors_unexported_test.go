@@ -0,0 +1,51 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPlugin_normalizeErrors_FoldsLastErrorIntoErrorsCollection(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	var outputBuffer strings.Builder
+	plugin.SetDebugLoggingOutputTarget(&outputBuffer)
+	plugin.DebugLoggingEnableActions()
+
+	lastErr := errors.New("last error")
+	otherErr := errors.New("other error")
+
+	plugin.LastError = lastErr
+	plugin.Errors = []error{otherErr}
+
+	plugin.normalizeErrors()
+
+	switch {
+	case plugin.LastError != nil:
+		t.Fatal("ERROR: LastError field not cleared")
+	case len(plugin.Errors) != 2:
+		t.Fatalf("ERROR: want 2 errors in collection, got %d", len(plugin.Errors))
+	case plugin.Errors[0] != lastErr || plugin.Errors[1] != otherErr:
+		t.Fatalf("ERROR: unexpected errors collection order: %v", plugin.Errors)
+	case !strings.Contains(outputBuffer.String(), "Deprecated field p.LastError is set"):
+		t.Fatal("ERROR: deprecation notice not logged")
+	default:
+		t.Log("OK: LastError folded into Errors collection as expected")
+	}
+
+	// Repeated normalization should not duplicate entries.
+	plugin.normalizeErrors()
+	if len(plugin.Errors) != 2 {
+		t.Fatalf("ERROR: want 2 errors in collection after repeated normalization, got %d", len(plugin.Errors))
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package eventlog provides a debug logging output target which writes to the
+Windows Event Log.
+
+# OVERVIEW
+
+The Writer type implements io.Writer and can be used with
+nagios.Plugin.SetDebugLoggingOutputTarget so that debug output from plugins
+running on Windows (e.g., via NSClient++) is recorded in the Application
+event log under the given event source.
+
+Each write is recorded as a separate event. The event type of each entry is
+determined as follows:
+
+  - messages from this library reporting failures, panics or invalid
+    settings are recorded as warning events
+  - all other messages use the default event type (information unless
+    overridden)
+
+The Windows Event Log is only available when built for Windows (the windows
+build tag). On other platforms NewWriter returns ErrUnsupportedPlatform.
+
+# EVENT SOURCES
+
+Event sources should be registered before use (e.g., during installation of
+the plugin) so that Event Viewer is able to display messages without a
+"description cannot be found" notice. For example, using PowerShell:
+
+	New-EventLog -LogName Application -Source check_disk
+
+Events are reported using event ID 1 unless overridden.
+
+# HOW TO USE
+
+	writer, err := eventlog.NewWriter("check_disk")
+	if err != nil {
+		// handle error
+	}
+	defer writer.Close()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(writer)
+	plugin.DebugLoggingEnableAll()
+*/
+package eventlog
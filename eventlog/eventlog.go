@@ -0,0 +1,173 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package eventlog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// EventType is the type (severity) of an event log entry.
+type EventType uint16
+
+// Supported event types. These values match the Windows API EVENTLOG_*_TYPE
+// constants.
+const (
+	EventTypeError       EventType = 0x0001
+	EventTypeWarning     EventType = 0x0002
+	EventTypeInformation EventType = 0x0004
+)
+
+// DefaultEventID is the event ID used for event log entries if not
+// overridden.
+const DefaultEventID uint32 = 1
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrUnsupportedPlatform indicates that the Windows Event Log is not
+	// available on the current platform.
+	ErrUnsupportedPlatform = errors.New("windows event log not supported on this platform")
+
+	// ErrMissingSource indicates that the event source was not provided.
+	ErrMissingSource = errors.New("event source not specified")
+
+	// ErrWriterClosed indicates that the Writer has already been closed.
+	ErrWriterClosed = errors.New("event log writer closed")
+)
+
+// warningKeywords are (lowercase) keywords in messages from this library
+// which are recorded as warning events.
+var warningKeywords = []string{
+	"failed",
+	"failure",
+	"panic",
+	"invalid",
+}
+
+// Writer writes each message as an entry in the Windows Event Log.
+//
+// A Writer is safe for concurrent use.
+type Writer struct {
+	// mu guards the event source handle and settings.
+	mu sync.Mutex
+
+	// source is the registered event source name.
+	source string
+
+	// handle is the event source handle returned by the Windows API. A
+	// value of zero indicates that the Writer has been closed.
+	handle uintptr
+
+	// eventID is the event ID used for event log entries.
+	eventID uint32
+
+	// eventType is the default type of event log entries.
+	eventType EventType
+}
+
+// NewWriter constructs a new Writer for the given event source. Entries are
+// recorded as information events using DefaultEventID unless overridden. An
+// error is returned if the event source could not be opened or if the
+// current platform is not Windows.
+func NewWriter(source string) (*Writer, error) {
+	if source == "" {
+		return nil, ErrMissingSource
+	}
+
+	handle, err := openEventSource(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event source %q: %w", source, err)
+	}
+
+	return &Writer{
+		source:    source,
+		handle:    handle,
+		eventID:   DefaultEventID,
+		eventType: EventTypeInformation,
+	}, nil
+}
+
+// SetEventID overrides the default event ID used for event log entries.
+func (w *Writer) SetEventID(id uint32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.eventID = id
+}
+
+// SetEventType overrides the default type of event log entries. Unsupported
+// values are ignored.
+func (w *Writer) SetEventType(eventType EventType) {
+	switch eventType {
+	case EventTypeError, EventTypeWarning, EventTypeInformation:
+	default:
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.eventType = eventType
+}
+
+// Write records the given message as an event log entry.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.handle == 0 {
+		return 0, ErrWriterClosed
+	}
+
+	msg := strings.TrimRight(string(p), " \r\n")
+
+	if err := reportEvent(w.handle, w.messageType(msg), w.eventID, msg); err != nil {
+		return 0, fmt.Errorf("failed to report event: %w", err)
+	}
+
+	return len(p), nil
+}
+
+// Close releases the event source handle. Further writes return
+// ErrWriterClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.handle == 0 {
+		return nil
+	}
+
+	err := closeEventSource(w.handle)
+	w.handle = 0
+
+	if err != nil {
+		return fmt.Errorf("failed to close event source %q: %w", w.source, err)
+	}
+
+	return nil
+}
+
+// messageType returns the event type for the given message.
+func (w *Writer) messageType(msg string) EventType {
+	if w.eventType != EventTypeInformation {
+		return w.eventType
+	}
+
+	lower := strings.ToLower(msg)
+	for _, keyword := range warningKeywords {
+		if strings.Contains(lower, keyword) {
+			return EventTypeWarning
+		}
+	}
+
+	return w.eventType
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package eventlog
+
+// openEventSource returns ErrUnsupportedPlatform; the Windows Event Log is
+// not available on this platform.
+func openEventSource(string) (uintptr, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+// reportEvent returns ErrUnsupportedPlatform; the Windows Event Log is not
+// available on this platform.
+func reportEvent(uintptr, EventType, uint32, string) error {
+	return ErrUnsupportedPlatform
+}
+
+// closeEventSource returns ErrUnsupportedPlatform; the Windows Event Log is
+// not available on this platform.
+func closeEventSource(uintptr) error {
+	return ErrUnsupportedPlatform
+}
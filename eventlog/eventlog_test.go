@@ -0,0 +1,95 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package eventlog
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestNewWriter_RequiresSource(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWriter(""); !errors.Is(err, ErrMissingSource) {
+		t.Fatalf("ERROR: want %v, got %v", ErrMissingSource, err)
+	}
+
+	t.Log("OK: missing event source rejected as expected")
+}
+
+func TestNewWriter_UnsupportedPlatform(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows Event Log is supported on this platform")
+	}
+
+	if _, err := NewWriter("check_test"); !errors.Is(err, ErrUnsupportedPlatform) {
+		t.Fatalf("ERROR: want %v, got %v", ErrUnsupportedPlatform, err)
+	}
+
+	t.Log("OK: unsupported platform reported as expected")
+}
+
+func TestWriter_messageType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		defaultType EventType
+		msg         string
+		want        EventType
+	}{
+		"debug message uses default type": {
+			defaultType: EventTypeInformation,
+			msg:         "Setting output target to specified value",
+			want:        EventTypeInformation,
+		},
+		"failure message is warning": {
+			defaultType: EventTypeInformation,
+			msg:         "Failed to encode payload",
+			want:        EventTypeWarning,
+		},
+		"overridden default type is used as-is": {
+			defaultType: EventTypeError,
+			msg:         "Setting output target to specified value",
+			want:        EventTypeError,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w := &Writer{eventType: tt.defaultType}
+
+			if got := w.messageType(tt.msg); got != tt.want {
+				t.Errorf("ERROR: want %d, got %d", tt.want, got)
+			} else {
+				t.Logf("OK: event type %d", got)
+			}
+		})
+	}
+}
+
+func TestWriter_Write_ClosedWriter(t *testing.T) {
+	t.Parallel()
+
+	w := &Writer{}
+
+	if _, err := w.Write([]byte("message")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("ERROR: want %v, got %v", ErrWriterClosed, err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("ERROR: unexpected close failure: %v", err)
+	}
+
+	t.Log("OK: closed writer handled as expected")
+}
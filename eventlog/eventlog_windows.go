@@ -0,0 +1,79 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build windows
+
+package eventlog
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modadvapi32 = syscall.NewLazyDLL("advapi32.dll")
+
+	procRegisterEventSourceW  = modadvapi32.NewProc("RegisterEventSourceW")
+	procReportEventW          = modadvapi32.NewProc("ReportEventW")
+	procDeregisterEventSource = modadvapi32.NewProc("DeregisterEventSource")
+)
+
+// openEventSource returns a handle to the given event source on the local
+// computer.
+func openEventSource(source string) (uintptr, error) {
+	name, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return 0, err
+	}
+
+	handle, _, callErr := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(name)))
+	if handle == 0 {
+		return 0, callErr
+	}
+
+	return handle, nil
+}
+
+// reportEvent writes the given message to the event log using the given
+// event source handle.
+func reportEvent(handle uintptr, eventType EventType, eventID uint32, msg string) error {
+	// NUL characters are not permitted in the message string.
+	text, err := syscall.UTF16PtrFromString(strings.ReplaceAll(msg, "\x00", ""))
+	if err != nil {
+		return err
+	}
+
+	strs := []*uint16{text}
+
+	ok, _, callErr := procReportEventW.Call(
+		handle,
+		uintptr(eventType),
+		0, // category
+		uintptr(eventID),
+		0, // user SID
+		uintptr(len(strs)),
+		0, // raw data size
+		uintptr(unsafe.Pointer(&strs[0])),
+		0, // raw data
+	)
+	if ok == 0 {
+		return callErr
+	}
+
+	return nil
+}
+
+// closeEventSource releases the given event source handle.
+func closeEventSource(handle uintptr) error {
+	ok, _, callErr := procDeregisterEventSource.Call(handle)
+	if ok == 0 {
+		return callErr
+	}
+
+	return nil
+}
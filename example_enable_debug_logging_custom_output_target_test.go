@@ -9,6 +9,7 @@ package nagios_test
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/atc0005/go-nagios"
 )
@@ -50,7 +51,14 @@ func Example_debugLoggingSetCustomDebugLoggingOutputTarget() {
 	// the io.Writer interface.
 	plugin.DebugLoggingEnableAll()
 
-	fh, err := os.Open("myPlugin_debug_output.log")
+	//
+	// Here we use a debug log file which is rotated once it reaches 1 MB,
+	// retaining up to 3 previous files.
+	logFile, err := nagios.NewRotatingDebugLogFile(
+		filepath.Join(os.TempDir(), "myPlugin_debug_output.log"),
+		1024*1024,
+		3,
+	)
 	if err != nil {
 		plugin.AddError(err)
 		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
@@ -58,7 +66,13 @@ func Example_debugLoggingSetCustomDebugLoggingOutputTarget() {
 		return
 	}
 
-	plugin.SetDebugLoggingOutputTarget(fh)
+	// Close the debug log file after plugin output has been emitted so that
+	// debug log entries recorded by ReturnCheckResults are retained.
+	plugin.AddEmitHook(func(*nagios.Plugin) {
+		_ = logFile.Close()
+	})
+
+	plugin.SetDebugLoggingOutputTarget(logFile)
 
 	// more stuff here involving performing the actual service check
 
@@ -0,0 +1,41 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package execwrap executes external (e.g., legacy C or shell) plugins and
+re-emits their results through a nagios.Plugin value.
+
+# OVERVIEW
+
+Wrapping an existing plugin allows adding an encoded payload, additional
+performance data metrics or sanitizing output without rewriting the
+plugin. The wrapped plugin is executed with a timeout; standard output is
+parsed using nagios.ParsePluginOutput and the exit code, output sections,
+errors, performance data and any encoded payload (using the default
+delimiters) are applied to the given Plugin.
+
+If the wrapped plugin times out, cannot be executed or exits with an
+unexpected exit code, the Plugin is set to UNKNOWN and the problem is
+recorded as an error along with any standard error output.
+
+The Wrapper.Check method satisfies the nagios.CheckFunc signature and may be
+used directly with the nagios.Runner type.
+
+# HOW TO USE
+
+	plugin := nagios.NewPlugin()
+	defer plugin.ReturnCheckResults()
+
+	wrapper := execwrap.NewWrapper("/usr/lib/nagios/plugins/check_disk", "-w", "20%", "-c", "10%", "-p", "/")
+	wrapper.SetTimeout(30 * time.Second)
+
+	wrapper.Check(ctx, plugin)
+
+	// Augment results as needed.
+	_, _ = plugin.AddPayloadString(extraDetails)
+*/
+package execwrap
@@ -0,0 +1,345 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package execwrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// defaultTimeout is the time the wrapped plugin is allowed to run if
+	// not overridden. This matches the default Nagios service check
+	// timeout.
+	defaultTimeout time.Duration = 60 * time.Second
+
+	// maxCapturedOutput limits the amount of standard output and standard
+	// error output captured from the wrapped plugin; excess output is
+	// discarded.
+	maxCapturedOutput int = 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingCommand indicates that the wrapped plugin command was not
+	// specified.
+	ErrMissingCommand = errors.New("plugin command not specified")
+
+	// ErrTimeout indicates that the wrapped plugin did not complete within
+	// the allowed time and was terminated.
+	ErrTimeout = errors.New("plugin execution timed out")
+
+	// ErrUnexpectedExitCode indicates that the wrapped plugin exited with
+	// an exit code which is not a supported plugin state.
+	ErrUnexpectedExitCode = errors.New("unexpected plugin exit code")
+
+	// ErrNoOutput indicates that the wrapped plugin did not produce any
+	// output.
+	ErrNoOutput = errors.New("plugin produced no output")
+)
+
+// Result is the raw result of a wrapped plugin execution.
+type Result struct {
+	// ExitCode is the exit code of the wrapped plugin or -1 if the plugin
+	// did not exit normally (e.g., was terminated due to timeout).
+	ExitCode int
+
+	// Stdout is the captured standard output.
+	Stdout string
+
+	// Stderr is the captured standard error output.
+	Stderr string
+
+	// Duration is the time taken by the wrapped plugin.
+	Duration time.Duration
+}
+
+// Wrapper executes an external plugin.
+type Wrapper struct {
+	// path is the path to the wrapped plugin executable.
+	path string
+
+	// args is the collection of arguments passed to the wrapped plugin.
+	args []string
+
+	// env is an optional collection of additional environment variables in
+	// key=value format.
+	env []string
+
+	// timeout limits the time the wrapped plugin is allowed to run.
+	timeout time.Duration
+}
+
+// NewWrapper constructs a new Wrapper for the given plugin executable and
+// arguments. The plugin is executed directly (not via a shell).
+func NewWrapper(path string, args ...string) *Wrapper {
+	return &Wrapper{
+		path:    path,
+		args:    args,
+		timeout: defaultTimeout,
+	}
+}
+
+// SetTimeout overrides the default time the wrapped plugin is allowed to
+// run. Non-positive values are ignored.
+func (w *Wrapper) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	w.timeout = timeout
+}
+
+// SetEnv adds the given environment variables (in key=value format) to the
+// environment inherited by the wrapped plugin.
+func (w *Wrapper) SetEnv(env ...string) {
+	w.env = append(w.env, env...)
+}
+
+// name returns the base name of the wrapped plugin for use in messages.
+func (w *Wrapper) name() string {
+	return filepath.Base(w.path)
+}
+
+// Run executes the wrapped plugin and returns the raw result. A non-zero
+// exit code is not treated as an error. An error is returned if the plugin
+// could not be executed, timed out (ErrTimeout) or the given context was
+// cancelled; the returned Result contains any output captured before the
+// plugin was terminated.
+func (w *Wrapper) Run(ctx context.Context) (Result, error) {
+	if w.path == "" {
+		return Result{ExitCode: -1}, ErrMissingCommand
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, w.path, w.args...) //nolint:gosec // executing arbitrary plugins is the intent
+	if len(w.env) > 0 {
+		cmd.Env = append(os.Environ(), w.env...)
+	}
+
+	stdout, err := newOutputCapture()
+	if err != nil {
+		return Result{ExitCode: -1}, err
+	}
+	stderr, err := newOutputCapture()
+	if err != nil {
+		stdout.abort()
+		return Result{ExitCode: -1}, err
+	}
+
+	cmd.Stdout = stdout.writer
+	cmd.Stderr = stderr.writer
+
+	start := time.Now()
+	startErr := cmd.Start()
+
+	// The plugin process holds its own copies of the write ends.
+	stdout.closeWriter()
+	stderr.closeWriter()
+
+	if startErr != nil {
+		stdout.abort()
+		stderr.abort()
+
+		return Result{ExitCode: -1}, fmt.Errorf("failed to execute plugin %s: %w", w.name(), startErr)
+	}
+
+	waitErr := cmd.Wait()
+
+	// Child processes of the plugin may hold the output pipes open after
+	// the plugin exits; stop waiting for output once the timeout is
+	// reached.
+	result := Result{
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Stdout:   stdout.wait(runCtx),
+		Stderr:   stderr.wait(runCtx),
+		Duration: time.Since(start),
+	}
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case ctx.Err() != nil:
+		return result, ctx.Err()
+
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		return result, fmt.Errorf("plugin %s terminated after %v: %w", w.name(), w.timeout, ErrTimeout)
+
+	case waitErr != nil && !errors.As(waitErr, &exitErr):
+		return result, fmt.Errorf("failed to execute plugin %s: %w", w.name(), waitErr)
+	}
+
+	return result, nil
+}
+
+// Check executes the wrapped plugin and applies the results to the given
+// Plugin. The signature matches nagios.CheckFunc.
+//
+// If the wrapped plugin could not be executed, timed out, produced no
+// output or exited with an unexpected exit code the Plugin is set to
+// UNKNOWN and the problem (along with any standard error output) is
+// recorded as an error.
+func (w *Wrapper) Check(ctx context.Context, p *nagios.Plugin) {
+	result, err := w.Run(ctx)
+	if err != nil {
+		w.setUnknown(p, err, result)
+
+		return
+	}
+
+	checkResult, parseErr := nagios.ParsePluginOutput(result.Stdout)
+	if checkResult == nil {
+		w.setUnknown(p, fmt.Errorf("plugin %s: %w", w.name(), ErrNoOutput), result)
+
+		return
+	}
+
+	p.ExitStatusCode = result.ExitCode
+	p.ServiceOutput = checkResult.ServiceOutput
+	p.LongServiceOutput = checkResult.LongServiceOutput
+	p.WarningThreshold = checkResult.WarningThreshold
+	p.CriticalThreshold = checkResult.CriticalThreshold
+	p.AddError(checkResult.Errors...)
+
+	if parseErr != nil {
+		p.AddError(fmt.Errorf("failed to parse plugin %s performance data: %w", w.name(), parseErr))
+	}
+
+	if len(checkResult.PerfData) > 0 {
+		// Metrics were validated when parsed.
+		_ = p.AddPerfData(true, checkResult.PerfData...)
+	}
+
+	if checkResult.EncodedPayload != "" {
+		payload, err := nagios.ExtractAndDecodePayload(
+			checkResult.EncodedPayload,
+			"",
+			nagios.DefaultASCII85EncodingDelimiterLeft,
+			nagios.DefaultASCII85EncodingDelimiterRight,
+		)
+		switch {
+		case err != nil:
+			p.AddError(fmt.Errorf("failed to decode plugin %s payload: %w", w.name(), err))
+		default:
+			_, _ = p.AddPayloadString(payload)
+		}
+	}
+
+	if result.ExitCode < nagios.StateOKExitCode || result.ExitCode > nagios.StateDEPENDENTExitCode {
+		p.ExitStatusCode = nagios.StateUNKNOWNExitCode
+		p.AddError(fmt.Errorf("plugin %s exited with code %d: %w", w.name(), result.ExitCode, ErrUnexpectedExitCode))
+		addStderrError(p, result)
+	}
+}
+
+// setUnknown sets the given Plugin to UNKNOWN and records the given error
+// along with any standard error output.
+func (w *Wrapper) setUnknown(p *nagios.Plugin, err error, result Result) {
+	p.ExitStatusCode = nagios.StateUNKNOWNExitCode
+	p.ServiceOutput = fmt.Sprintf("%s: Failed to obtain results from plugin %s", nagios.StateUNKNOWNLabel, w.name())
+	p.AddError(err)
+	addStderrError(p, result)
+}
+
+// addStderrError records any standard error output from the given result as
+// an error.
+func addStderrError(p *nagios.Plugin, result Result) {
+	if stderr := strings.TrimSpace(result.Stderr); stderr != "" {
+		p.AddError(fmt.Errorf("plugin stderr: %s", stderr))
+	}
+}
+
+// outputCapture collects output written by the wrapped plugin to a pipe.
+type outputCapture struct {
+	reader *os.File
+	writer *os.File
+	buf    limitedBuffer
+	done   chan struct{}
+}
+
+// newOutputCapture creates a pipe and begins collecting output written to
+// it.
+func newOutputCapture() (*outputCapture, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output pipe: %w", err)
+	}
+
+	c := outputCapture{
+		reader: reader,
+		writer: writer,
+		buf:    limitedBuffer{limit: maxCapturedOutput},
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(c.done)
+		_, _ = io.Copy(&c.buf, reader)
+	}()
+
+	return &c, nil
+}
+
+// closeWriter closes the write end of the pipe.
+func (c *outputCapture) closeWriter() {
+	_ = c.writer.Close()
+}
+
+// abort stops collecting output.
+func (c *outputCapture) abort() {
+	_ = c.writer.Close()
+	_ = c.reader.Close()
+	<-c.done
+}
+
+// wait returns the collected output once all writers have closed the pipe
+// or the given context is done.
+func (c *outputCapture) wait(ctx context.Context) string {
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+	}
+
+	_ = c.reader.Close()
+	<-c.done
+
+	return c.buf.String()
+}
+
+// limitedBuffer is a buffer which discards writes beyond a size limit.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write appends the given data to the buffer up to the size limit. Excess
+// data is discarded without error.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			_, _ = b.Buffer.Write(p[:remaining])
+		} else {
+			_, _ = b.Buffer.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package execwrap
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestWrapper_Check_UnknownWhenPluginCannotBeExecuted(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	NewWrapper(filepath.Join(t.TempDir(), "missing_plugin")).Check(context.Background(), plugin)
+
+	switch {
+	case plugin.ExitStatusCode != nagios.StateUNKNOWNExitCode:
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateUNKNOWNExitCode, plugin.ExitStatusCode)
+	case len(plugin.Errors) != 1:
+		t.Fatalf("ERROR: want one recorded error, got %v", plugin.Errors)
+	default:
+		t.Log("OK: execution failure reported as UNKNOWN")
+	}
+}
+
+func TestWrapper_Run_RequiresCommand(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWrapper("").Run(context.Background()); !errors.Is(err, ErrMissingCommand) {
+		t.Fatalf("ERROR: want %v, got %v", ErrMissingCommand, err)
+	}
+
+	t.Log("OK: missing command reported as expected")
+}
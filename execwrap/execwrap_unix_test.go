@@ -0,0 +1,121 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package execwrap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// writeScript writes an executable shell script with the given body to a
+// temporary directory and returns the path.
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "check_fake")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o700); err != nil { //nolint:gosec // test script must be executable
+		t.Fatalf("ERROR: failed to write test script: %v", err)
+	}
+
+	return path
+}
+
+func TestWrapper_Check_AppliesWrappedPluginResults(t *testing.T) {
+	t.Parallel()
+
+	script := writeScript(t, `echo "DISK WARNING - free space: / 20% | /=80%;75;90"
+echo "/ 80% used"
+echo "/boot 10% used | /boot=10%;75;90"
+echo "warning from plugin" >&2
+exit 1`)
+
+	plugin := nagios.NewPlugin()
+	NewWrapper(script).Check(context.Background(), plugin)
+
+	switch {
+	case plugin.ExitStatusCode != nagios.StateWARNINGExitCode:
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, plugin.ExitStatusCode)
+	case plugin.ServiceOutput != "DISK WARNING - free space: / 20%":
+		t.Fatalf("ERROR: unexpected ServiceOutput %q", plugin.ServiceOutput)
+	case plugin.LongServiceOutput != "/ 80% used\n/boot 10% used":
+		t.Fatalf("ERROR: unexpected LongServiceOutput %q", plugin.LongServiceOutput)
+	case len(plugin.Errors) != 0:
+		t.Fatalf("ERROR: unexpected errors recorded: %v", plugin.Errors)
+	}
+
+	want := []nagios.PerformanceData{
+		{Label: "/", Value: "80", UnitOfMeasurement: "%", Warn: "75", Crit: "90"},
+		{Label: "/boot", Value: "10", UnitOfMeasurement: "%", Warn: "75", Crit: "90"},
+	}
+
+	if d := cmp.Diff(want, plugin.PerfData()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: wrapped plugin results applied as expected")
+}
+
+func TestWrapper_Check_UnknownForUnexpectedExitCode(t *testing.T) {
+	t.Parallel()
+
+	script := writeScript(t, `echo "segfault imminent"
+echo "stack trace" >&2
+exit 139`)
+
+	plugin := nagios.NewPlugin()
+	NewWrapper(script).Check(context.Background(), plugin)
+
+	if plugin.ExitStatusCode != nagios.StateUNKNOWNExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateUNKNOWNExitCode, plugin.ExitStatusCode)
+	}
+
+	if len(plugin.Errors) != 2 ||
+		!errors.Is(plugin.Errors[0], ErrUnexpectedExitCode) ||
+		!strings.Contains(plugin.Errors[1].Error(), "stack trace") {
+		t.Fatalf("ERROR: unexpected errors recorded: %v", plugin.Errors)
+	}
+
+	t.Log("OK: unexpected exit code reported as UNKNOWN")
+}
+
+func TestWrapper_Run_TerminatesPluginAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	// The sleep child process inherits the output pipes; the timeout must
+	// still be honored.
+	script := writeScript(t, `echo "partial"
+sleep 30`)
+
+	wrapper := NewWrapper(script)
+	wrapper.SetTimeout(200 * time.Millisecond)
+
+	start := time.Now()
+	result, err := wrapper.Run(context.Background())
+
+	switch {
+	case !errors.Is(err, ErrTimeout):
+		t.Fatalf("ERROR: want %v, got %v", ErrTimeout, err)
+	case time.Since(start) > 5*time.Second:
+		t.Fatalf("ERROR: timeout not honored; returned after %v", time.Since(start))
+	case result.Stdout != "partial\n":
+		t.Fatalf("ERROR: want partial output retained, got %q", result.Stdout)
+	default:
+		t.Log("OK: plugin terminated after timeout")
+	}
+}
@@ -34,31 +34,31 @@ const (
 // functionality easily breaks this input it is stored in separate files to
 // reduce test breakage due to editors "helping".
 var (
-	//go:embed testdata/plugin-output-datastore-0001.txt
+	//go:embed corpus/testdata/plugin-output-datastore-0001.txt
 	pluginOutputDatastore0001 string
 
-	//go:embed testdata/plugin-output-gh103-multi-line-with-perf-data.txt
+	//go:embed corpus/testdata/plugin-output-gh103-multi-line-with-perf-data.txt
 	pluginOutputGH103MultiLineWithPerfData string
 
-	//go:embed testdata/plugin-output-gh103-one-line-with-perf-data.txt
+	//go:embed corpus/testdata/plugin-output-gh103-one-line-with-perf-data.txt
 	pluginOutputGH103OneLineWithPerfData string
 
-	//go:embed testdata/plugin-output-multiline-with-optional-perf-data-included.txt
+	//go:embed corpus/testdata/plugin-output-multiline-with-optional-perf-data-included.txt
 	pluginOutputMultiLineWithOptionalPerfDataIncluded string
 
-	//go:embed testdata/plugin-output-one-line-with-optional-perf-data-included.txt
+	//go:embed corpus/testdata/plugin-output-one-line-with-optional-perf-data-included.txt
 	pluginOutputOneLineWithOptionalPerfDataIncluded string
 
-	//go:embed testdata/payload/small_json_payload_unencoded.txt
+	//go:embed corpus/testdata/payload/small_json_payload_unencoded.txt
 	smallJSONPayloadUnencoded string
 
-	//go:embed testdata/payload/small_json_payload_encoded_with_default_delimiters.txt
+	//go:embed corpus/testdata/payload/small_json_payload_encoded_with_default_delimiters.txt
 	smallJSONPayloadEncodedWithDefaultDelimiters string
 
-	//go:embed testdata/payload/small_json_payload_encoded_with_custom_delimiters.txt
+	//go:embed corpus/testdata/payload/small_json_payload_encoded_with_custom_delimiters.txt
 	smallJSONPayloadEncodedWithCustomDelimiters string
 
-	//go:embed testdata/payload/small_json_payload_encoded_without_delimiters.txt
+	//go:embed corpus/testdata/payload/small_json_payload_encoded_without_delimiters.txt
 	smallJSONPayloadEncodedWithNoDelimiters string
 
 	// Earlier prototyping found that the stream encoding/decoding process
@@ -68,7 +68,7 @@ var (
 	// might switch back to using stream processing we explicitly test
 	// using an exclamation point to guard against future breakage.
 	//
-	//go:embed testdata/payload/small_plaintext_payload_unencoded.txt
+	//go:embed corpus/testdata/payload/small_plaintext_payload_unencoded.txt
 	smallPlaintextPayloadUnencoded string
 
 	// Earlier prototyping found that the stream encoding/decoding process
@@ -78,7 +78,7 @@ var (
 	// might switch back to using stream processing we explicitly test
 	// using an exclamation point to guard against future breakage.
 	//
-	//go:embed testdata/payload/small_plaintext_payload_encoded_with_default_delimiters.txt
+	//go:embed corpus/testdata/payload/small_plaintext_payload_encoded_with_default_delimiters.txt
 	smallPlaintextPayloadEncodedWithDefaultDelimiters string
 
 	// Earlier prototyping found that the stream encoding/decoding process
@@ -88,7 +88,7 @@ var (
 	// might switch back to using stream processing we explicitly test
 	// using an exclamation point to guard against future breakage.
 	//
-	//go:embed testdata/payload/small_plaintext_payload_encoded_with_custom_delimiters.txt
+	//go:embed corpus/testdata/payload/small_plaintext_payload_encoded_with_custom_delimiters.txt
 	smallPlaintextPayloadEncodedWithCustomDelimiters string
 
 	// Earlier prototyping found that the stream encoding/decoding process
@@ -98,73 +98,73 @@ var (
 	// might switch back to using stream processing we explicitly test
 	// using an exclamation point to guard against future breakage.
 	//
-	//go:embed testdata/payload/small_plaintext_payload_encoded_without_delimiters.txt
+	//go:embed corpus/testdata/payload/small_plaintext_payload_encoded_without_delimiters.txt
 	smallPlaintextPayloadEncodedWithNoDelimiters string
 
-	//go:embed testdata/payload/large_payload_encoded_with_default_delimiters.txt
+	//go:embed corpus/testdata/payload/large_payload_encoded_with_default_delimiters.txt
 	largePayloadEncodedWithDefaultDelimiters string
 
-	//go:embed testdata/payload/large_payload_encoded_with_custom_delimiters.txt
+	//go:embed corpus/testdata/payload/large_payload_encoded_with_custom_delimiters.txt
 	largePayloadEncodedWithCustomDelimiters string
 
-	//go:embed testdata/payload/large_payload_encoded_without_delimiters.txt
+	//go:embed corpus/testdata/payload/large_payload_encoded_without_delimiters.txt
 	largePayloadEncodedWithNoDelimiters string
 
-	//go:embed testdata/payload/large_payload_unencoded.txt
+	//go:embed corpus/testdata/payload/large_payload_unencoded.txt
 	largePayloadUnencoded string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-large-encoded-payload-with-custom-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-large-encoded-payload-with-custom-delimiters.txt
 	pluginOutputCustomSectionHeadersAndLargeEncodedPayloadWithCustomDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-large-encoded-payload-with-default-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-large-encoded-payload-with-default-delimiters.txt
 	pluginOutputCustomSectionHeadersAndLargeEncodedPayloadWithDefaultDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-large-encoded-payload-with-no-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-large-encoded-payload-with-no-delimiters.txt
 	pluginOutputCustomSectionHeadersAndLargeEncodedPayloadWithNoDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-json-payload-with-custom-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-json-payload-with-custom-delimiters.txt
 	pluginOutputCustomSectionHeadersAndSmallEncodedJSONPayloadWithCustomDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-json-payload-with-default-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-json-payload-with-default-delimiters.txt
 	pluginOutputCustomSectionHeadersAndSmallEncodedJSONPayloadWithDefaultDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-json-payload-with-no-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-json-payload-with-no-delimiters.txt
 	pluginOutputCustomSectionHeadersAndSmallEncodedJSONPayloadWithNoDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-plaintext-payload-with-custom-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-plaintext-payload-with-custom-delimiters.txt
 	pluginOutputCustomSectionHeadersAndSmallEncodedPlaintextPayloadWithCustomDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-plaintext-payload-with-default-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-plaintext-payload-with-default-delimiters.txt
 	pluginOutputCustomSectionHeadersAndSmallEncodedPlaintextPayloadWithDefaultDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-plaintext-payload-with-no-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-custom-section-header-and-small-encoded-plaintext-payload-with-no-delimiters.txt
 	pluginOutputCustomSectionHeadersAndSmallEncodedPlaintextPayloadWithNoDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-large-encoded-payload-with-custom-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-large-encoded-payload-with-custom-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndLargeEncodedPayloadWithCustomDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-large-encoded-payload-with-default-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-large-encoded-payload-with-default-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndLargeEncodedPayloadWithDefaultDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-large-encoded-payload-with-no-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-large-encoded-payload-with-no-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndLargeEncodedPayloadWithNoDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-json-payload-with-custom-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-json-payload-with-custom-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndSmallEncodedJSONPayloadWithCustomDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-json-payload-with-default-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-json-payload-with-default-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndSmallEncodedJSONPayloadWithDefaultDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-json-payload-with-no-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-json-payload-with-no-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndSmallEncodedJSONPayloadWithNoDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-plaintext-payload-with-custom-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-plaintext-payload-with-custom-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndSmallEncodedPlaintextPayloadWithCustomDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-plaintext-payload-with-default-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-plaintext-payload-with-default-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndSmallEncodedPlaintextPayloadWithDefaultDelimiters string
 
-	//go:embed testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-plaintext-payload-with-no-delimiters.txt
+	//go:embed corpus/testdata/payload/plugin-output-gh251-default-section-header-and-small-encoded-plaintext-payload-with-no-delimiters.txt
 	pluginOutputDefaultSectionHeadersAndSmallEncodedPlaintextPayloadWithNoDelimiters string
 )
 
@@ -1677,3 +1677,26 @@ func pluginOutputWithLongServiceOutputAllOptionalMetrics(t *testing.T, plugin *n
 	// 	t.Errorf("failed to add performance data: %v", err)
 	// }
 }
+
+// TestExtractEncodedPayload_MatchesDelimitersLiterally asserts that
+// delimiters containing regular expression metacharacters are matched
+// literally and cannot cause a panic when removed from the match.
+func TestExtractEncodedPayload_MatchesDelimitersLiterally(t *testing.T) {
+	t.Parallel()
+
+	encoded := nagios.EncodePayload([]byte("payload"), "|", "(")
+	text := "OK: fine\n" + encoded + "\n"
+
+	got, err := nagios.ExtractAndDecodePayload(text, "", "|", "(")
+	if err != nil {
+		t.Fatalf("ERROR: failed to extract payload: %v", err)
+	}
+
+	if got != "payload" {
+		t.Errorf("ERROR: want payload %q, got %q", "payload", got)
+	}
+
+	if _, err := nagios.ExtractEncodedPayload("0", "*", "0", "0"); err == nil {
+		t.Error("ERROR: expected error for custom regex altering delimiter match")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+)
+
+// The methods in this file are chainable variants of common Plugin setters.
+// Each method returns the plugin value so that simple checks can be
+// expressed as a single expression:
+//
+//	plugin.WithState(StateWARNINGExitCode).
+//		WithServiceOutput("WARNING: 91% used").
+//		WithPerfData(PerformanceData{Label: "used", Value: "91", UnitOfMeasurement: "%"})
+//
+// The exported fields and existing methods remain available and may be
+// used alongside the chainable methods.
+
+// WithServiceOutput sets the ServiceOutput field (the one-line summary) and
+// returns the plugin value.
+func (p *Plugin) WithServiceOutput(output string) *Plugin {
+	p.ServiceOutput = output
+
+	return p
+}
+
+// WithServiceOutputf formats the ServiceOutput field (the one-line summary)
+// according to the given format specifier and returns the plugin value.
+func (p *Plugin) WithServiceOutputf(format string, args ...interface{}) *Plugin {
+	p.ServiceOutput = fmt.Sprintf(format, args...)
+
+	return p
+}
+
+// WithLongServiceOutput sets the LongServiceOutput field and returns the
+// plugin value.
+func (p *Plugin) WithLongServiceOutput(output string) *Plugin {
+	p.LongServiceOutput = output
+
+	return p
+}
+
+// WithState sets the ExitStatusCode field to the given exit code (e.g.,
+// StateWARNINGExitCode) and returns the plugin value.
+func (p *Plugin) WithState(exitCode int) *Plugin {
+	p.ExitStatusCode = exitCode
+
+	return p
+}
+
+// WithThresholds sets the WarningThreshold and CriticalThreshold fields and
+// returns the plugin value.
+func (p *Plugin) WithThresholds(warning string, critical string) *Plugin {
+	p.WarningThreshold = warning
+	p.CriticalThreshold = critical
+
+	return p
+}
+
+// WithPerfData adds the given performance data metrics (see AddPerfData)
+// and returns the plugin value. Metrics are validated; if validation fails
+// no metrics are added and the validation error is recorded in the errors
+// collection so that it is included in the plugin output.
+func (p *Plugin) WithPerfData(perfData ...PerformanceData) *Plugin {
+	if len(perfData) == 0 {
+		return p
+	}
+
+	if err := p.AddPerfData(false, perfData...); err != nil {
+		p.AddError(err)
+	}
+
+	return p
+}
+
+// WithError appends the given errors to the collection (see AddError) and
+// returns the plugin value.
+func (p *Plugin) WithError(errs ...error) *Plugin {
+	p.AddError(errs...)
+
+	return p
+}
+
+// WithPayloadString sets the payload buffer content (see SetPayloadString)
+// and returns the plugin value. If the payload content could not be set the
+// error is recorded in the errors collection.
+func (p *Plugin) WithPayloadString(input string) *Plugin {
+	if _, err := p.SetPayloadString(input); err != nil {
+		p.AddError(err)
+	}
+
+	return p
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_FluentAPI_MatchesFieldBasedAPI(t *testing.T) {
+	t.Parallel()
+
+	errDatastore := errors.New("datastore usage above threshold")
+	perfData := nagios.PerformanceData{Label: "used", Value: "91", UnitOfMeasurement: "%"}
+
+	newTestPlugin := func(outputBuffer *strings.Builder) *nagios.Plugin {
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(outputBuffer)
+		plugin.SkipOSExit()
+
+		// Exclude the variable default time metric from the output.
+		_ = plugin.AddPerfData(false, nagios.PerformanceData{Label: "time", Value: "1", UnitOfMeasurement: "ms"})
+
+		return plugin
+	}
+
+	var wantBuffer strings.Builder
+	want := newTestPlugin(&wantBuffer)
+	want.ExitStatusCode = nagios.StateWARNINGExitCode
+	want.ServiceOutput = "WARNING: 91% used"
+	want.LongServiceOutput = "Datastore details"
+	want.WarningThreshold = "80"
+	want.CriticalThreshold = "90"
+	want.AddError(errDatastore)
+	_ = want.AddPerfData(false, perfData)
+	_, _ = want.SetPayloadString("payload")
+	want.ReturnCheckResults()
+
+	var gotBuffer strings.Builder
+	newTestPlugin(&gotBuffer).
+		WithState(nagios.StateWARNINGExitCode).
+		WithServiceOutputf("WARNING: %d%% used", 91).
+		WithLongServiceOutput("Datastore details").
+		WithThresholds("80", "90").
+		WithError(errDatastore).
+		WithPerfData(perfData).
+		WithPerfData().
+		WithPayloadString("payload").
+		ReturnCheckResults()
+
+	if d := cmp.Diff(wantBuffer.String(), gotBuffer.String()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: chained setters produce the same output as the field-based API")
+}
+
+func TestPlugin_WithPerfData_RecordsValidationError(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin().
+		WithServiceOutput("OK: all good").
+		WithPerfData(nagios.PerformanceData{Label: "missing_value"})
+
+	if len(plugin.PerfData()) != 0 {
+		t.Errorf("ERROR: want no performance data added, got %v", plugin.PerfData())
+	}
+
+	if len(plugin.Errors) != 1 || !errors.Is(plugin.Errors[0], nagios.ErrInvalidPerformanceDataFormat) {
+		t.Fatalf("ERROR: want validation error recorded, got %v", plugin.Errors)
+	}
+
+	t.Log("OK: performance data validation error recorded as expected")
+}
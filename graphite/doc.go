@@ -0,0 +1,40 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package graphite converts Nagios plugin performance data to Graphite
+plaintext protocol lines and optionally submits them to a Graphite (Carbon)
+listener.
+
+# OVERVIEW
+
+Each performance data metric is converted to a single line in the format:
+
+	prefix.host.service.metric value timestamp
+
+Dots and whitespace within the host name, service description and metric
+label are replaced (by default with underscores) so that they do not
+introduce additional path components. The characters replaced and the
+replacement value are configurable.
+
+Metrics with an undetermined value ("U") are skipped as Graphite does not
+support non-numeric values.
+
+# HOW TO USE
+
+	formatter := graphite.NewFormatter("nagios")
+	lines, err := formatter.Lines("web01.example.com", "HTTP", time.Now(), plugin.PerfData()...)
+	if err != nil {
+		// handle error
+	}
+
+	client := graphite.NewClient("carbon.example.com")
+	if err := client.Send(ctx, lines...); err != nil {
+		// handle error
+	}
+*/
+package graphite
@@ -0,0 +1,231 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package graphite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultPort is the default TCP port used by the Carbon plaintext
+	// listener.
+	DefaultPort string = "2003"
+
+	// DefaultReplacement is the default value used to replace characters in
+	// path components.
+	DefaultReplacement string = "_"
+
+	// DefaultReplaceChars is the default collection of characters replaced
+	// in path components: dots and whitespace.
+	DefaultReplaceChars string = ". \t\r\n"
+
+	// defaultTimeout is used for connecting to and communicating with the
+	// Carbon listener if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the Carbon listener address was not
+	// provided.
+	ErrMissingAddress = errors.New("Graphite address not specified")
+
+	// ErrNoLines indicates that no lines were provided for submission.
+	ErrNoLines = errors.New("no lines provided")
+
+	// ErrInvalidMetricValue indicates that a performance data value could
+	// not be converted to a numeric value.
+	ErrInvalidMetricValue = errors.New("invalid performance data value")
+)
+
+// Formatter converts performance data to Graphite plaintext protocol lines.
+type Formatter struct {
+	// prefix is the optional leading path (e.g., "nagios" or
+	// "monitoring.nagios").
+	prefix string
+
+	// replacement is the value used to replace characters in path
+	// components.
+	replacement string
+
+	// replaceChars is the collection of characters replaced in path
+	// components.
+	replaceChars string
+}
+
+// NewFormatter constructs a new Formatter using the given (optional) path
+// prefix. The prefix is used as-is and may contain dots.
+func NewFormatter(prefix string) *Formatter {
+	return &Formatter{
+		prefix:       strings.Trim(prefix, "."),
+		replacement:  DefaultReplacement,
+		replaceChars: DefaultReplaceChars,
+	}
+}
+
+// SetSanitization overrides the default characters replaced in host names,
+// service descriptions and metric labels and the value used to replace them.
+func (f *Formatter) SetSanitization(replaceChars string, replacement string) {
+	f.replaceChars = replaceChars
+	f.replacement = replacement
+}
+
+// Sanitize replaces configured characters in the given path component. If
+// the replacement value is empty the characters are removed.
+func (f *Formatter) Sanitize(component string) string {
+	if f.replaceChars == "" {
+		return component
+	}
+
+	var b strings.Builder
+	b.Grow(len(component))
+
+	for _, r := range component {
+		if strings.ContainsRune(f.replaceChars, r) {
+			b.WriteString(f.replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Path returns the metric path for the given host, service and performance
+// data label. Empty components (e.g., service for host checks) are omitted.
+func (f *Formatter) Path(host string, service string, label string) string {
+	parts := make([]string, 0, 4)
+	if f.prefix != "" {
+		parts = append(parts, f.prefix)
+	}
+
+	for _, component := range []string{host, service, label} {
+		if component == "" {
+			continue
+		}
+		parts = append(parts, f.Sanitize(component))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Lines returns the given performance data as Graphite plaintext protocol
+// lines (without trailing newlines). Metrics with an undetermined value
+// ("U") are skipped.
+func (f *Formatter) Lines(host string, service string, timestamp time.Time, metrics ...nagios.PerformanceData) ([]string, error) {
+	lines := make([]string, 0, len(metrics))
+
+	for _, pd := range metrics {
+		if pd.Value == "U" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(pd.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q value %q: %w", pd.Label, pd.Value, ErrInvalidMetricValue)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%s %s %d",
+			f.Path(host, service, pd.Label),
+			strconv.FormatFloat(value, 'f', -1, 64),
+			timestamp.Unix(),
+		))
+	}
+
+	return lines, nil
+}
+
+// Client submits plaintext protocol lines to a Carbon listener over TCP.
+type Client struct {
+	// address is the Carbon listener address in host:port format.
+	address string
+
+	// timeout limits the time spent connecting to and communicating with
+	// the Carbon listener.
+	timeout time.Duration
+
+	// dialer is used to establish connections to the Carbon listener.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given Carbon listener address. If
+// a port is not included in the address the default port is used.
+func NewClient(address string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address: address,
+		timeout: defaultTimeout,
+	}
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with the Carbon listener. Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// Send submits the given lines to the Carbon listener using a single
+// connection.
+func (c *Client) Send(ctx context.Context, lines ...string) error {
+	switch {
+	case c.address == "":
+		return ErrMissingAddress
+	case len(lines) == 0:
+		return ErrNoLines
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, "tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Graphite: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set Graphite connection deadline: %w", err)
+	}
+
+	var payload strings.Builder
+	for _, line := range lines {
+		payload.WriteString(strings.TrimRight(line, "\r\n"))
+		payload.WriteByte('\n')
+	}
+
+	if _, err := conn.Write([]byte(payload.String())); err != nil {
+		return fmt.Errorf("failed to send metrics to Graphite: %w", err)
+	}
+
+	return nil
+}
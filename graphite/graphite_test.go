@@ -0,0 +1,112 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package graphite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestFormatter_Lines_SanitizesPathComponents(t *testing.T) {
+	t.Parallel()
+
+	metrics := []nagios.PerformanceData{
+		{Label: "response time", Value: "0.874", UnitOfMeasurement: "s"},
+		{Label: "disk.used", Value: "42"},
+		{Label: "unknown", Value: "U"},
+	}
+
+	ts := time.Unix(1700000000, 0)
+
+	tests := map[string]struct {
+		formatter func() *Formatter
+		want      []string
+	}{
+		"default sanitization": {
+			formatter: func() *Formatter { return NewFormatter("monitoring.nagios.") },
+			want: []string{
+				"monitoring.nagios.web01_example_com.HTTP_check.response_time 0.874 1700000000",
+				"monitoring.nagios.web01_example_com.HTTP_check.disk_used 42 1700000000",
+			},
+		},
+		"custom sanitization": {
+			formatter: func() *Formatter {
+				f := NewFormatter("")
+				f.SetSanitization(". ", "-")
+				return f
+			},
+			want: []string{
+				"web01-example-com.HTTP-check.response-time 0.874 1700000000",
+				"web01-example-com.HTTP-check.disk-used 42 1700000000",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		got, err := tt.formatter().Lines("web01.example.com", "HTTP check", ts, metrics...)
+		if err != nil {
+			t.Fatalf("ERROR: %s: unexpected failure: %v", name, err)
+		}
+
+		if d := cmp.Diff(tt.want, got); d != "" {
+			t.Errorf("ERROR: %s: (-want, +got)\n:%s", name, d)
+		}
+	}
+}
+
+func TestFormatter_Lines_RejectsInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFormatter("").Lines("web01", "", time.Now(), nagios.PerformanceData{Label: "a", Value: "abc"})
+	if !errors.Is(err, ErrInvalidMetricValue) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidMetricValue, err)
+	}
+}
+
+func TestClient_Send_WritesNewlineTerminatedLines(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	received := make(chan string, 1)
+	go func() {
+		defer close(received)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	client := NewClient(listener.Addr().String())
+	if err := client.Send(context.Background(), "a.b 1 10", "a.c 2 10\n"); err != nil {
+		t.Fatalf("ERROR: unexpected send failure: %v", err)
+	}
+
+	if got, want := <-received, "a.b 1 10\na.c 2 10\n"; got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	t.Log("OK: lines sent as expected")
+}
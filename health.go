@@ -0,0 +1,214 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Paths served by HealthServer.
+const (
+	// HealthzPath is the path of the liveness endpoint.
+	HealthzPath string = "/healthz"
+
+	// LastResultPath is the path of the most recent check result endpoint.
+	LastResultPath string = "/lastresult"
+)
+
+// openMetricsContentType is the content type of the OpenMetrics text
+// exposition format.
+const openMetricsContentType string = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// healthServerShutdownTimeout limits the time spent waiting for in-flight
+// requests when the health server is stopped.
+const healthServerShutdownTimeout time.Duration = 5 * time.Second
+
+// HealthServer is an http.Handler exposing the most recent check result for
+// daemon mode usage (see Runner.SetHealthServer). This allows orchestrators
+// to scrape the same data that is submitted passively to the monitoring
+// system.
+//
+// The following endpoints are served:
+//
+//   - /healthz responds with HTTP 200 once a check result has been recorded
+//     (and the most recent result is not older than the optional maximum
+//     age), otherwise HTTP 503.
+//   - /lastresult responds with the most recent check result using the check
+//     result JSON format (see EncodeCheckResultJSON). If the OpenMetrics
+//     format is requested (via the Accept header or a format=openmetrics
+//     query parameter) the performance data is provided using the
+//     OpenMetrics text exposition format instead. HTTP 503 is returned if
+//     no check result has been recorded.
+//
+// A HealthServer is safe for concurrent use.
+type HealthServer struct {
+	// mu guards the most recent check result.
+	mu sync.RWMutex
+
+	// last is the most recent check result.
+	last *CheckResult
+
+	// updated indicates when the most recent check result was recorded.
+	updated time.Time
+
+	// maxAge is the maximum age of the most recent check result before the
+	// liveness endpoint reports a failure. A value of zero disables the
+	// check.
+	maxAge time.Duration
+}
+
+// NewHealthServer constructs a new HealthServer. No maximum check result age
+// is enforced unless specified.
+func NewHealthServer() *HealthServer {
+	return &HealthServer{}
+}
+
+// SetMaxAge specifies the maximum age of the most recent check result before
+// the liveness endpoint reports a failure (e.g., twice the daemon mode
+// interval). Non-positive values disable the check.
+func (h *HealthServer) SetMaxAge(maxAge time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	h.maxAge = maxAge
+}
+
+// Update records the given check result as the most recent result.
+func (h *HealthServer) Update(cr CheckResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last = &cr
+	h.updated = time.Now()
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (h *HealthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	switch r.URL.Path {
+	case HealthzPath:
+		h.serveHealthz(w)
+	case LastResultPath:
+		h.serveLastResult(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveHealthz responds with the liveness status.
+func (h *HealthServer) serveHealthz(w http.ResponseWriter) {
+	h.mu.RLock()
+	last, updated, maxAge := h.last, h.updated, h.maxAge
+	h.mu.RUnlock()
+
+	switch {
+	case last == nil:
+		http.Error(w, "no check result recorded", http.StatusServiceUnavailable)
+	case maxAge > 0 && time.Since(updated) > maxAge:
+		http.Error(w, "check result is stale", http.StatusServiceUnavailable)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprintln(w, "ok")
+	}
+}
+
+// serveLastResult responds with the most recent check result.
+func (h *HealthServer) serveLastResult(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	last := h.last
+	h.mu.RUnlock()
+
+	if last == nil {
+		http.Error(w, "no check result recorded", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	if r.URL.Query().Get("format") == "openmetrics" ||
+		strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+		var buf strings.Builder
+		if err := WritePerfDataOpenMetrics(&buf, last.PerfData...); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", openMetricsContentType)
+		_, _ = fmt.Fprint(w, buf.String())
+
+		return
+	}
+
+	data, err := EncodeCheckResultJSON(last)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// ListenAndServe serves the health endpoints on the given address until the
+// given context is cancelled. Any error other than the server being closed
+// is returned.
+func (h *HealthServer) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	return h.Serve(ctx, listener)
+}
+
+// Serve serves the health endpoints using the given listener until the
+// given context is cancelled. Any error other than the server being closed
+// is returned.
+func (h *HealthServer) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), healthServerShutdownTimeout)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		case <-done:
+		}
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("health server failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// healthGet performs a GET request against the given handler and returns the
+// response status code and body.
+func healthGet(t *testing.T, h http.Handler, target string, accept string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read response body: %v", err)
+	}
+
+	return rec.Code, string(body)
+}
+
+func TestHealthServer_ServesLastResult(t *testing.T) {
+	t.Parallel()
+
+	health := nagios.NewHealthServer()
+
+	if code, _ := healthGet(t, health, nagios.HealthzPath, ""); code != http.StatusServiceUnavailable {
+		t.Fatalf("ERROR: want HTTP %d before first result, got %d", http.StatusServiceUnavailable, code)
+	}
+
+	plugin := nagios.NewPlugin()
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: queue depth high"
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "depth", Value: "42"}); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+	health.Update(plugin.Snapshot())
+
+	if code, body := healthGet(t, health, nagios.HealthzPath, ""); code != http.StatusOK || body != "ok\n" {
+		t.Fatalf("ERROR: unexpected liveness response %d %q", code, body)
+	}
+
+	code, body := healthGet(t, health, nagios.LastResultPath, "")
+	if code != http.StatusOK {
+		t.Fatalf("ERROR: want HTTP %d, got %d", http.StatusOK, code)
+	}
+
+	cr, err := nagios.DecodeCheckResultJSON([]byte(body))
+	if err != nil || cr.ExitStatusCode != nagios.StateWARNINGExitCode || cr.ServiceOutput != plugin.ServiceOutput {
+		t.Fatalf("ERROR: unexpected check result %+v (%v)", cr, err)
+	}
+
+	for _, accept := range []string{"application/openmetrics-text; version=1.0.0", ""} {
+		target := nagios.LastResultPath
+		if accept == "" {
+			target += "?format=openmetrics"
+		}
+
+		code, body = healthGet(t, health, target, accept)
+		if code != http.StatusOK || !strings.Contains(body, "depth 42\n") || !strings.HasSuffix(body, "# EOF\n") {
+			t.Fatalf("ERROR: unexpected OpenMetrics response %d %q", code, body)
+		}
+	}
+
+	health.SetMaxAge(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if code, _ := healthGet(t, health, nagios.HealthzPath, ""); code != http.StatusServiceUnavailable {
+		t.Fatalf("ERROR: want HTTP %d for stale result, got %d", http.StatusServiceUnavailable, code)
+	}
+
+	t.Log("OK: health endpoints served as expected")
+}
+
+func TestRunner_SetHealthServer_UpdatesWithEachResult(t *testing.T) {
+	t.Parallel()
+
+	submitter := &recordingSubmitter{notify: make(chan struct{}, 1)}
+	health := nagios.NewHealthServer()
+
+	runner := nagios.NewRunner(func(_ context.Context, plugin *nagios.Plugin) {
+		plugin.ServiceOutput = "OK: widgets nominal"
+	})
+	runner.SetInterval(time.Hour)
+	runner.SetPassiveTarget("web01", "widgets", submitter)
+	runner.SetHealthServer(health)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = runner.RunDaemon(ctx)
+	}()
+
+	select {
+	case <-submitter.notify:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ERROR: timed out waiting for submission")
+	}
+
+	code, body := healthGet(t, health, nagios.LastResultPath, "")
+	if code != http.StatusOK || !strings.Contains(body, `"service_output":"OK: widgets nominal"`) {
+		t.Fatalf("ERROR: unexpected last result response %d %q", code, body)
+	}
+
+	t.Log("OK: health server updated by runner as expected")
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+)
+
+// EmitHookFunc is a function called by ReturnCheckResults with the final
+// plugin state after plugin output has been emitted and before the plugin
+// exits. This allows client code (or exporters such as the dogstatsd
+// package) to mirror check results and performance data into other systems.
+type EmitHookFunc func(p *Plugin)
+
+// AddEmitHook registers one or more functions called by ReturnCheckResults
+// after plugin output has been emitted. Hooks are called in the order they
+// were registered. A panic within a hook is recovered and logged so that it
+// does not prevent the plugin from exiting with the intended state.
+func (p *Plugin) AddEmitHook(hooks ...EmitHookFunc) {
+	for _, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+
+		p.emitHooks = append(p.emitHooks, hook)
+	}
+}
+
+// StateHookFunc is a function called by ReturnCheckResults when the plugin
+// state matches the state the hook was registered for. Hooks are called
+// before plugin output is rendered, allowing client code to enrich the
+// plugin output for a specific state (e.g., gather extra diagnostics or
+// attach an encoded payload).
+type StateHookFunc func(p *Plugin)
+
+// OnWarning registers one or more functions called by ReturnCheckResults
+// when the plugin state is WARNING.
+func (p *Plugin) OnWarning(hooks ...StateHookFunc) {
+	p.addStateHooks(StateWARNINGExitCode, hooks)
+}
+
+// OnCritical registers one or more functions called by ReturnCheckResults
+// when the plugin state is CRITICAL.
+func (p *Plugin) OnCritical(hooks ...StateHookFunc) {
+	p.addStateHooks(StateCRITICALExitCode, hooks)
+}
+
+// OnUnknown registers one or more functions called by ReturnCheckResults
+// when the plugin state is UNKNOWN.
+func (p *Plugin) OnUnknown(hooks ...StateHookFunc) {
+	p.addStateHooks(StateUNKNOWNExitCode, hooks)
+}
+
+// addStateHooks registers the given state hooks for the given plugin state.
+func (p *Plugin) addStateHooks(state int, hooks []StateHookFunc) {
+	for _, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+
+		if p.stateHooks == nil {
+			p.stateHooks = make(map[int][]StateHookFunc)
+		}
+
+		p.stateHooks[state] = append(p.stateHooks[state], hook)
+	}
+}
+
+// stateHookCount returns the number of registered state hooks.
+func (p *Plugin) stateHookCount() int {
+	var count int
+	for _, hooks := range p.stateHooks {
+		count += len(hooks)
+	}
+
+	return count
+}
+
+// runStateHooks calls each state hook registered for the current plugin
+// state in turn. The plugin state is evaluated once; a state hook changing
+// the plugin state does not cause hooks for the new state to be called. In
+// host check mode hooks are selected using the plugin exit code for the host
+// check state (e.g., CRITICAL for DOWN).
+func (p *Plugin) runStateHooks() {
+	exitCode := p.processExitCode()
+
+	hooks := p.stateHooks[exitCode]
+	if len(hooks) == 0 {
+		return
+	}
+
+	phaseDone := p.startPhase("StateHooks")
+	defer phaseDone()
+
+	stateLabel := ExitCodeToStateLabel(exitCode)
+
+	for i, hook := range hooks {
+		p.logAction(fmt.Sprintf("Running %s state hook %d of %d", stateLabel, i+1, len(hooks)))
+		p.runHook("state", hook)
+	}
+}
+
+// runEmitHooks calls each registered emit hook in turn.
+func (p *Plugin) runEmitHooks() {
+	for i, hook := range p.emitHooks {
+		p.logAction(fmt.Sprintf("Running emit hook %d of %d", i+1, len(p.emitHooks)))
+		p.runHook("emit", hook)
+	}
+}
+
+// runHook calls the given hook, recovering from any panic. The given kind
+// identifies the type of hook in log messages.
+func (p *Plugin) runHook(kind string, hook func(p *Plugin)) {
+	defer func() {
+		if err := recover(); err != nil {
+			p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf("Recovered from panic in %s hook: %v", kind, err))
+		}
+	}()
+
+	hook(p)
+}
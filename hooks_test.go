@@ -0,0 +1,200 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_AddEmitHook_CallsHooksAfterOutputIsEmitted(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+	var calls []string
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "OK: all good"
+
+	plugin.AddEmitHook(
+		func(p *nagios.Plugin) {
+			if outputBuffer.Len() == 0 {
+				t.Error("ERROR: emit hook called before output was emitted")
+			}
+			calls = append(calls, "first")
+			panic("hook failure")
+		},
+		nil,
+		func(p *nagios.Plugin) {
+			calls = append(calls, "second")
+		},
+	)
+
+	plugin.ReturnCheckResults()
+
+	if strings.Join(calls, ",") != "first,second" {
+		t.Fatalf("ERROR: want hooks called in order despite panic, got %v", calls)
+	}
+
+	t.Log("OK: emit hooks called as expected")
+}
+
+func TestPlugin_StateHooks_CalledForFinalState(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		exitCode int
+		want     string
+	}{
+		"ok": {
+			exitCode: nagios.StateOKExitCode,
+			want:     "",
+		},
+		"warning": {
+			exitCode: nagios.StateWARNINGExitCode,
+			want:     "warning",
+		},
+		"critical": {
+			exitCode: nagios.StateCRITICALExitCode,
+			want:     "critical,critical-second",
+		},
+		"unknown": {
+			exitCode: nagios.StateUNKNOWNExitCode,
+			want:     "unknown",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+			var calls []string
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.ServiceOutput = "check complete"
+			plugin.ExitStatusCode = tt.exitCode
+
+			plugin.OnWarning(func(p *nagios.Plugin) {
+				calls = append(calls, "warning")
+			})
+			plugin.OnCritical(
+				func(p *nagios.Plugin) {
+					calls = append(calls, "critical")
+					panic("hook failure")
+				},
+				nil,
+				func(p *nagios.Plugin) {
+					calls = append(calls, "critical-second")
+				},
+			)
+			plugin.OnUnknown(func(p *nagios.Plugin) {
+				calls = append(calls, "unknown")
+			})
+
+			plugin.ReturnCheckResults()
+
+			if got := strings.Join(calls, ","); got != tt.want {
+				t.Fatalf("ERROR: want hooks %q called, got %q", tt.want, got)
+			}
+
+			t.Log("OK: state hooks called as expected")
+		})
+	}
+}
+
+func TestPlugin_OnCritical_EnrichesOutput(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "CRITICAL: datastore full"
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+
+	plugin.OnCritical(func(p *nagios.Plugin) {
+		p.LongServiceOutput = "largest consumer: vm-01"
+	})
+
+	plugin.ReturnCheckResults()
+
+	if !strings.Contains(outputBuffer.String(), "largest consumer: vm-01") {
+		t.Fatalf("ERROR: state hook content not found in output:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: state hook enriched output as expected")
+}
+
+func TestPlugin_OnUnknown_CalledForLibraryEscalations(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		setup func(p *nagios.Plugin)
+	}{
+		"empty ServiceOutput": {
+			setup: func(p *nagios.Plugin) {
+				p.EnableUnknownOnEmptyServiceOutput()
+			},
+		},
+		"strict mode invalid perfdata": {
+			setup: func(p *nagios.Plugin) {
+				p.EnableStrictMode()
+				p.ServiceOutput = "OK: all widgets accounted for"
+
+				// Skip validation so that the invalid metric is accepted.
+				_ = p.AddPerfData(true, nagios.PerformanceData{Label: "widgets", Value: "abc"})
+			},
+		},
+		"deprecated LastError field": {
+			setup: func(p *nagios.Plugin) {
+				p.ServiceOutput = "OK: all widgets accounted for"
+				p.LastError = nagios.UnknownError(nagios.ErrPanicDetected)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+			var calls []string
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+
+			plugin.OnUnknown(func(p *nagios.Plugin) {
+				calls = append(calls, "unknown")
+			})
+
+			tt.setup(plugin)
+			plugin.ReturnCheckResults()
+
+			if plugin.ExitStatusCode != nagios.StateUNKNOWNExitCode {
+				t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateUNKNOWNExitCode, plugin.ExitStatusCode)
+			}
+
+			if got := strings.Join(calls, ","); got != "unknown" {
+				t.Fatalf("ERROR: want OnUnknown hook called, got %q", got)
+			}
+
+			t.Log("OK: state hook called for final plugin state")
+		})
+	}
+}
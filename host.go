@@ -0,0 +1,164 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"strings"
+)
+
+// Nagios host check states. These values are used by passive host check
+// results and are used in place of the service check states for the
+// ExitStatusCode field when host check mode is enabled (see
+// SetHostCheckMode).
+const (
+	StateUPExitCode          int = 0
+	StateDOWNExitCode        int = 1
+	StateUNREACHABLEExitCode int = 2
+)
+
+// Nagios host check state "labels". These constants are provided as an
+// alternative to using literal state strings throughout client application
+// code.
+const (
+	StateUPLabel          string = "UP"
+	StateDOWNLabel        string = "DOWN"
+	StateUNREACHABLELabel string = "UNREACHABLE"
+)
+
+// SetHostCheckMode indicates that the plugin performs a host check instead
+// of a service check. In host check mode:
+//
+//   - the ExitStatusCode field is set by client code using host check states
+//     (e.g., StateDOWNExitCode)
+//   - summaries generated by this library use host state labels (e.g.,
+//     "DOWN")
+//   - the plugin exits using the plugin exit code Nagios maps to the host
+//     state: OK for UP and CRITICAL for DOWN and UNREACHABLE (Nagios
+//     determines whether a host is DOWN or UNREACHABLE using the state of
+//     parent hosts); other values exit as UNKNOWN which Nagios also treats
+//     as DOWN
+//   - passive check results use the host check state as-is
+//   - state hooks (e.g., OnCritical) are selected using the plugin exit code
+//     for the host check state
+//
+// Service check states used by this library (e.g., by EscalateState,
+// AddResult, AddErrorWithExitCode, error severities or the UNKNOWN state
+// used for internal failures) are mapped to host check states: OK to UP
+// and any other state to DOWN. A DOWN or UNREACHABLE host check state is
+// never replaced by an escalation.
+func (p *Plugin) SetHostCheckMode() {
+	p.logAction("Enabling host check mode as requested")
+	p.hostCheckMode = true
+}
+
+// ExitCodeToHostStateLabel returns the corresponding host state label for
+// the given host check state. If an invalid value is provided the
+// StateDOWNLabel value is returned.
+func ExitCodeToHostStateLabel(exitCode int) string {
+	switch exitCode {
+	case StateUPExitCode:
+		return StateUPLabel
+	case StateUNREACHABLEExitCode:
+		return StateUNREACHABLELabel
+	default:
+		return StateDOWNLabel
+	}
+}
+
+// HostStateLabelToExitCode returns the corresponding host check state for the
+// given host state label. If an invalid value is provided the
+// StateDOWNExitCode value is returned.
+func HostStateLabelToExitCode(label string) int {
+	switch strings.ToUpper(label) {
+	case StateUPLabel:
+		return StateUPExitCode
+	case StateUNREACHABLELabel:
+		return StateUNREACHABLEExitCode
+	default:
+		return StateDOWNExitCode
+	}
+}
+
+// stateLabel returns the state label for the given exit code using host
+// state labels in host check mode and service state labels otherwise.
+func (p *Plugin) stateLabel(exitCode int) string {
+	if p.hostCheckMode {
+		return ExitCodeToHostStateLabel(exitCode)
+	}
+
+	return ExitCodeToStateLabel(exitCode)
+}
+
+// hostStateFromExitCode returns the host check state for the given service
+// check state: UP for OK and DOWN for any other state.
+func hostStateFromExitCode(exitCode int) int {
+	if exitCode == StateOKExitCode {
+		return StateUPExitCode
+	}
+
+	return StateDOWNExitCode
+}
+
+// hostStateProcessExitCode returns the plugin exit code Nagios uses to
+// determine the given host check state.
+func hostStateProcessExitCode(hostState int) int {
+	switch hostState {
+	case StateUPExitCode:
+		return StateOKExitCode
+	case StateDOWNExitCode, StateUNREACHABLEExitCode:
+		return StateCRITICALExitCode
+	default:
+		return StateUNKNOWNExitCode
+	}
+}
+
+// exitState returns the value used for the ExitStatusCode field to record
+// the given service check state. In host check mode the service check state
+// is mapped to a host check state.
+func (p *Plugin) exitState(exitCode int) int {
+	if p.hostCheckMode {
+		return hostStateFromExitCode(exitCode)
+	}
+
+	return exitCode
+}
+
+// escalatedExitState returns the exit state resulting from escalating the
+// given exit state using the given service check state. The exit state is
+// never downgraded. See EscalateState.
+func (p *Plugin) escalatedExitState(current int, exitCode int) int {
+	if !p.hostCheckMode {
+		if isMoreSevereExitCode(exitCode, current) {
+			return exitCode
+		}
+
+		return current
+	}
+
+	hostState := hostStateFromExitCode(exitCode)
+
+	switch {
+	case hostState == StateUPExitCode:
+		return current
+	case current == StateDOWNExitCode, current == StateUNREACHABLEExitCode:
+		return current
+	default:
+		return hostState
+	}
+}
+
+// processExitCode returns the exit code used when the plugin exits. In host
+// check mode the host check state is mapped to the plugin exit code Nagios
+// uses to determine the host state.
+func (p *Plugin) processExitCode() int {
+	if !p.hostCheckMode {
+		return p.ExitStatusCode
+	}
+
+	return hostStateProcessExitCode(p.ExitStatusCode)
+}
@@ -0,0 +1,297 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetHostCheckMode_MapsExitCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		hostState    int
+		wantExitCode int
+	}{
+		"UP": {
+			hostState:    nagios.StateUPExitCode,
+			wantExitCode: nagios.StateOKExitCode,
+		},
+		"DOWN": {
+			hostState:    nagios.StateDOWNExitCode,
+			wantExitCode: nagios.StateCRITICALExitCode,
+		},
+		"UNREACHABLE": {
+			hostState:    nagios.StateUNREACHABLEExitCode,
+			wantExitCode: nagios.StateCRITICALExitCode,
+		},
+		"invalid": {
+			hostState:    42,
+			wantExitCode: nagios.StateUNKNOWNExitCode,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			exitCode := -1
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SetExitFunc(func(code int) { exitCode = code })
+			plugin.SetHostCheckMode()
+			plugin.ExitStatusCode = tt.hostState
+			plugin.ServiceOutput = "host check"
+			plugin.ReturnCheckResults()
+
+			if exitCode != tt.wantExitCode {
+				t.Fatalf("ERROR: want exit code %d, got %d", tt.wantExitCode, exitCode)
+			}
+
+			result := plugin.PassiveCheckResult("web01", "")
+			if result.ExitStatusCode != tt.hostState {
+				t.Fatalf("ERROR: want passive host state %d, got %d", tt.hostState, result.ExitStatusCode)
+			}
+
+			t.Log("OK: host state mapped to exit code as expected")
+		})
+	}
+}
+
+func TestPlugin_SetHostCheckMode_UsesHostStateLabels(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetHostCheckMode()
+
+	plugin.AddResult("eth0", nagios.StateUPExitCode, "link up")
+	plugin.AddResult("eth1", nagios.StateDOWNExitCode, "link down")
+
+	output, exitCode, err := plugin.Render()
+	if err != nil {
+		t.Fatalf("ERROR: unexpected error: %v", err)
+	}
+
+	if exitCode != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, exitCode)
+	}
+
+	if !strings.HasPrefix(output, "DOWN: ") {
+		t.Fatalf("ERROR: want host state label in summary, got:\n%q", output)
+	}
+
+	t.Log("OK: host state labels used as expected")
+}
+
+func TestHostStateLabelConversions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int{
+		nagios.StateUPLabel:          nagios.StateUPExitCode,
+		nagios.StateDOWNLabel:        nagios.StateDOWNExitCode,
+		nagios.StateUNREACHABLELabel: nagios.StateUNREACHABLEExitCode,
+	}
+
+	for label, exitCode := range tests {
+		if got := nagios.HostStateLabelToExitCode(strings.ToLower(label)); got != exitCode {
+			t.Errorf("ERROR: want exit code %d for label %q, got %d", exitCode, label, got)
+		}
+
+		if got := nagios.ExitCodeToHostStateLabel(exitCode); got != label {
+			t.Errorf("ERROR: want label %q for exit code %d, got %q", label, exitCode, got)
+		}
+	}
+
+	if got := nagios.ExitCodeToHostStateLabel(42); got != nagios.StateDOWNLabel {
+		t.Errorf("ERROR: want label %q for invalid exit code, got %q", nagios.StateDOWNLabel, got)
+	}
+
+	t.Log("OK: host state labels converted as expected")
+}
+
+func TestPlugin_SetHostCheckMode_MapsEscalatedStates(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		initialState     int
+		setup            func(p *nagios.Plugin)
+		wantHostState    int
+		wantOutputPrefix string
+	}{
+		"EscalateState OK": {
+			initialState:  nagios.StateUPExitCode,
+			setup:         func(p *nagios.Plugin) { p.EscalateState(nagios.StateOKExitCode) },
+			wantHostState: nagios.StateUPExitCode,
+		},
+		"EscalateState WARNING": {
+			initialState:  nagios.StateUPExitCode,
+			setup:         func(p *nagios.Plugin) { p.EscalateState(nagios.StateWARNINGExitCode) },
+			wantHostState: nagios.StateDOWNExitCode,
+		},
+		"EscalateState keeps UNREACHABLE": {
+			initialState:  nagios.StateUNREACHABLEExitCode,
+			setup:         func(p *nagios.Plugin) { p.EscalateState(nagios.StateCRITICALExitCode) },
+			wantHostState: nagios.StateUNREACHABLEExitCode,
+		},
+		"WarningError": {
+			initialState:  nagios.StateUPExitCode,
+			setup:         func(p *nagios.Plugin) { p.AddError(nagios.WarningError(errors.New("slow response"))) },
+			wantHostState: nagios.StateDOWNExitCode,
+		},
+		"CriticalError via LastError": {
+			initialState:  nagios.StateUPExitCode,
+			setup:         func(p *nagios.Plugin) { p.LastError = nagios.CriticalError(errors.New("no response")) },
+			wantHostState: nagios.StateDOWNExitCode,
+		},
+		"AddResult": {
+			initialState:  nagios.StateUPExitCode,
+			setup:         func(p *nagios.Plugin) { p.AddResult("ping", nagios.StateUNKNOWNExitCode, "no reply") },
+			wantHostState: nagios.StateDOWNExitCode,
+		},
+		"AddErrorWithExitCode": {
+			initialState: nagios.StateUPExitCode,
+			setup: func(p *nagios.Plugin) {
+				p.AddErrorWithExitCode(errors.New("no route to host"), nagios.StateWARNINGExitCode)
+			},
+			wantHostState: nagios.StateDOWNExitCode,
+		},
+		"strict mode internal failure": {
+			initialState: nagios.StateUPExitCode,
+			setup: func(p *nagios.Plugin) {
+				p.EnableStrictMode()
+				_ = p.AddPerfData(true, nagios.PerformanceData{Label: "rta", Value: "abc"})
+			},
+			wantHostState: nagios.StateDOWNExitCode,
+		},
+		"empty ServiceOutput": {
+			initialState: nagios.StateUPExitCode,
+			setup: func(p *nagios.Plugin) {
+				p.EnableUnknownOnEmptyServiceOutput()
+				p.ServiceOutput = ""
+			},
+			wantHostState:    nagios.StateDOWNExitCode,
+			wantOutputPrefix: nagios.StateDOWNLabel + ": ",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			exitCode := -1
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SetExitFunc(func(code int) { exitCode = code })
+			plugin.SetHostCheckMode()
+			plugin.ExitStatusCode = tt.initialState
+			plugin.ServiceOutput = "host check"
+
+			tt.setup(plugin)
+			plugin.ReturnCheckResults()
+
+			if plugin.ExitStatusCode != tt.wantHostState {
+				t.Fatalf("ERROR: want host state %d, got %d", tt.wantHostState, plugin.ExitStatusCode)
+			}
+
+			if !strings.HasPrefix(outputBuffer.String(), tt.wantOutputPrefix) {
+				t.Fatalf("ERROR: want output prefix %q, got:\n%s", tt.wantOutputPrefix, outputBuffer.String())
+			}
+
+			wantExitCode := nagios.StateCRITICALExitCode
+			if tt.wantHostState == nagios.StateUPExitCode {
+				wantExitCode = nagios.StateOKExitCode
+			}
+
+			if exitCode != wantExitCode {
+				t.Fatalf("ERROR: want exit code %d, got %d", wantExitCode, exitCode)
+			}
+
+			t.Log("OK: escalated state mapped to host state as expected")
+		})
+	}
+}
+
+func TestPlugin_SetHostCheckMode_PanicSetsDOWN(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	exitCode := -1
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(func(code int) { exitCode = code })
+	plugin.SetHostCheckMode()
+
+	func() {
+		defer plugin.ReturnCheckResults()
+		panic("boom")
+	}()
+
+	if plugin.ExitStatusCode != nagios.StateDOWNExitCode {
+		t.Fatalf("ERROR: want host state %d, got %d", nagios.StateDOWNExitCode, plugin.ExitStatusCode)
+	}
+
+	if !strings.HasPrefix(outputBuffer.String(), nagios.StateDOWNLabel+": ") {
+		t.Fatalf("ERROR: want DOWN summary, got:\n%s", outputBuffer.String())
+	}
+
+	if exitCode != nagios.StateCRITICALExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, exitCode)
+	}
+
+	t.Log("OK: panic reported as DOWN host state")
+}
+
+func TestPlugin_SetHostCheckMode_TimeoutReportsDOWN(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+
+	exitCodes := make(chan int, 1)
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(func(code int) { exitCodes <- code })
+	plugin.SetHostCheckMode()
+	plugin.SetTimeout(50 * time.Millisecond)
+
+	select {
+	case exitCode := <-exitCodes:
+		if exitCode != nagios.StateCRITICALExitCode {
+			t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, exitCode)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ERROR: plugin timeout not reached")
+	}
+
+	if !strings.HasPrefix(outputBuffer.String(), nagios.StateDOWNLabel+": plugin timeout") {
+		t.Fatalf("ERROR: want DOWN timeout summary, got:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: plugin timeout reported as DOWN host state")
+}
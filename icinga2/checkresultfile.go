@@ -0,0 +1,251 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package icinga2
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultCheckResultDir is the default spool directory read by the
+	// Icinga 2 CheckResultReader feature.
+	DefaultCheckResultDir string = "/var/lib/icinga2/spool/checkresults"
+
+	// checkResultFilePrefix is the prefix of check result file names.
+	checkResultFilePrefix string = "c"
+
+	// checkResultFileSuffixLength is the number of random characters
+	// following the prefix; the CheckResultReader only processes files
+	// matching c??????.
+	checkResultFileSuffixLength int = 6
+
+	// checkResultFileSuffixChars is the set of characters used for the
+	// random portion of check result file names.
+	checkResultFileSuffixChars string = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	// checkResultOKSuffix is the suffix of the marker file indicating that
+	// the matching check result file is complete.
+	checkResultOKSuffix string = ".ok"
+
+	// checkResultFileMode is the permission mode used for check result and
+	// marker files; the files must be readable by the Icinga 2 daemon.
+	checkResultFileMode os.FileMode = 0o644
+
+	// maxCreateAttempts limits attempts to create a uniquely named check
+	// result file.
+	maxCreateAttempts int = 10
+
+	// passiveCheckType is the check_type value for passive check results.
+	passiveCheckType int = 1
+
+	// invalidObjectNameChars is the set of characters which may not be used
+	// in host names or service descriptions written to a check result file.
+	invalidObjectNameChars string = "\r\n"
+)
+
+// Check result file specific sentinel errors. Exported for potential use by
+// client code to detect & handle specific error scenarios.
+var (
+	// ErrMissingCheckResultDir indicates that the check result spool
+	// directory was not specified.
+	ErrMissingCheckResultDir = errors.New("check result directory not specified")
+
+	// ErrInvalidObjectName indicates that a host name or service
+	// description contains characters which cannot be represented in a
+	// check result file.
+	ErrInvalidObjectName = errors.New("invalid host or service name")
+)
+
+// CheckResultWriter writes passive check results to the spool directory of
+// a local Icinga 2 instance using the check result file format.
+type CheckResultWriter struct {
+	// dir is the check result spool directory.
+	dir string
+}
+
+// NewCheckResultWriter constructs a new CheckResultWriter for the given
+// spool directory. See DefaultCheckResultDir.
+func NewCheckResultWriter(dir string) *CheckResultWriter {
+	return &CheckResultWriter{
+		dir: dir,
+	}
+}
+
+// Submit writes each given check result to a separate check result file
+// followed by the matching ".ok" marker file. The marker file is only
+// created once the check result file has been completely written.
+func (w *CheckResultWriter) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	switch {
+	case w.dir == "":
+		return ErrMissingCheckResultDir
+	case len(results) == 0:
+		return ErrNoCheckResults
+	}
+
+	for _, result := range results {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		content, err := FormatCheckResultFile(result)
+		if err != nil {
+			return err
+		}
+
+		if err := w.write(content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write writes the given content to a new check result file and creates the
+// matching marker file.
+func (w *CheckResultWriter) write(content string) error {
+	file, err := createCheckResultFile(w.dir)
+	if err != nil {
+		return err
+	}
+
+	path := file.Name()
+
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+
+		return fmt.Errorf("failed to write check result file %s: %w", path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(path)
+
+		return fmt.Errorf("failed to close check result file %s: %w", path, err)
+	}
+
+	okFile, err := os.OpenFile(path+checkResultOKSuffix, os.O_WRONLY|os.O_CREATE|os.O_EXCL, checkResultFileMode)
+	if err != nil {
+		_ = os.Remove(path)
+
+		return fmt.Errorf("failed to create check result marker file for %s: %w", path, err)
+	}
+
+	if err := okFile.Close(); err != nil {
+		return fmt.Errorf("failed to close check result marker file for %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// createCheckResultFile creates a new uniquely named check result file in
+// the given directory.
+func createCheckResultFile(dir string) (*os.File, error) {
+	var lastErr error
+
+	for i := 0; i < maxCreateAttempts; i++ {
+		suffix, err := randomSuffix(checkResultFileSuffixLength)
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, checkResultFilePrefix+suffix)
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, checkResultFileMode)
+		switch {
+		case err == nil:
+			return file, nil
+		case errors.Is(err, os.ErrExist):
+			lastErr = err
+			continue
+		default:
+			return nil, fmt.Errorf("failed to create check result file: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to create uniquely named check result file: %w", lastErr)
+}
+
+// randomSuffix returns a random string of the given length.
+func randomSuffix(length int) (string, error) {
+	var suffix strings.Builder
+
+	charCount := big.NewInt(int64(len(checkResultFileSuffixChars)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, charCount)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate check result file name: %w", err)
+		}
+		suffix.WriteByte(checkResultFileSuffixChars[n.Int64()])
+	}
+
+	return suffix.String(), nil
+}
+
+// FormatCheckResultFile returns the given passive check result in the check
+// result file format. Newlines in the output are escaped so that multi-line
+// output and performance data are preserved.
+func FormatCheckResultFile(result nagios.PassiveCheckResult) (string, error) {
+	for _, name := range []string{result.HostName, result.ServiceDescription} {
+		if strings.ContainsAny(name, invalidObjectNameChars) {
+			return "", fmt.Errorf("%q: %w", name, ErrInvalidObjectName)
+		}
+	}
+
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	ts := fmt.Sprintf("%d.%06d", timestamp.Unix(), timestamp.Nanosecond()/1000)
+
+	var content strings.Builder
+
+	switch {
+	case result.IsHostCheckResult():
+		content.WriteString("### Nagios Host Check Result ###\n")
+	default:
+		content.WriteString("### Nagios Service Check Result ###\n")
+	}
+
+	fmt.Fprintf(&content, "# Time: %s\n", timestamp.Format(time.ANSIC))
+	fmt.Fprintf(&content, "host_name=%s\n", result.HostName)
+	if !result.IsHostCheckResult() {
+		fmt.Fprintf(&content, "service_description=%s\n", result.ServiceDescription)
+	}
+	fmt.Fprintf(&content, "check_type=%d\n", passiveCheckType)
+	content.WriteString("check_options=0\n")
+	content.WriteString("scheduled_check=0\n")
+	content.WriteString("reschedule_check=0\n")
+	content.WriteString("latency=0.000000\n")
+	fmt.Fprintf(&content, "start_time=%s\n", ts)
+	fmt.Fprintf(&content, "finish_time=%s\n", ts)
+	content.WriteString("early_timeout=0\n")
+	content.WriteString("exited_ok=1\n")
+	fmt.Fprintf(&content, "return_code=%d\n", result.ExitStatusCode)
+	fmt.Fprintf(&content, "output=%s\n", escapeCheckResultOutput(result.Output))
+
+	return content.String(), nil
+}
+
+// escapeCheckResultOutput escapes newlines in the given plugin output. Icinga
+// 2 only unescapes newlines when reading check result files; backslashes are
+// left as-is.
+func escapeCheckResultOutput(output string) string {
+	output = strings.ReplaceAll(output, "\r", "")
+
+	return strings.ReplaceAll(strings.TrimRight(output, "\n"), "\n", `\n`)
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package icinga2
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure CheckResultWriter satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*CheckResultWriter)(nil)
+
+func TestCheckResultWriter_Submit_WritesResultAndMarkerFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	result := nagios.PassiveCheckResult{
+		HostName:           "web01",
+		ServiceDescription: "HTTP",
+		ExitStatusCode:     nagios.StateWARNINGExitCode,
+		Output:             "WARNING: slow \n\n**DETAILED INFO**\n C:\\temp is large \n |time=874ms;;;; \n",
+		Timestamp:          time.Unix(1700000000, 250000000),
+	}
+
+	if err := NewCheckResultWriter(dir).Submit(context.Background(), result); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	markers, err := filepath.Glob(filepath.Join(dir, "c??????.ok"))
+	if err != nil || len(markers) != 1 {
+		t.Fatalf("ERROR: want one marker file, got %v (%v)", markers, err)
+	}
+
+	content, err := os.ReadFile(strings.TrimSuffix(markers[0], checkResultOKSuffix))
+	if err != nil {
+		t.Fatalf("ERROR: failed to read check result file: %v", err)
+	}
+
+	for _, want := range []string{
+		"### Nagios Service Check Result ###\n",
+		"host_name=web01\n",
+		"service_description=HTTP\n",
+		"start_time=1700000000.250000\n",
+		"return_code=1\n",
+		`output=WARNING: slow \n\n**DETAILED INFO**\n C:\temp is large \n |time=874ms;;;; ` + "\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("ERROR: check result file missing %q:\n%s", want, content)
+		}
+	}
+
+	if !regexp.MustCompile(`^c[a-zA-Z0-9]{6}$`).MatchString(filepath.Base(strings.TrimSuffix(markers[0], ".ok"))) {
+		t.Errorf("ERROR: unexpected check result file name %q", markers[0])
+	}
+
+	t.Log("OK: check result and marker files written as expected")
+}
+
+func TestFormatCheckResultFile_RejectsInvalidObjectNames(t *testing.T) {
+	t.Parallel()
+
+	result := nagios.PassiveCheckResult{HostName: "web01\nreturn_code=0"}
+
+	if _, err := FormatCheckResultFile(result); !errors.Is(err, ErrInvalidObjectName) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidObjectName, err)
+	}
+
+	t.Log("OK: invalid object name rejected as expected")
+}
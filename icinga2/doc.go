@@ -0,0 +1,71 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package icinga2 provides a client for submitting passive check results to
+the Icinga 2 REST API and a writer for the Icinga 2 check result file
+format.
+
+# OVERVIEW
+
+Icinga 2 accepts passive check results via the
+/v1/actions/process-check-result API endpoint using HTTP basic
+authentication with an API user. This package submits check results
+rendered by the nagios package (see nagios.Plugin.PassiveCheckResult),
+splitting the rendered output into the plugin_output and performance_data
+fields expected by the API.
+
+The Client type satisfies the nagios.PassiveSubmitter interface and can be
+used directly with the nagios.Runner type for daemon mode checks.
+
+# HOW TO USE
+
+	client := icinga2.NewClient("https://icinga.example.com:5665", "api-user", "s3cr3t")
+	client.SetCheckSource("collector01")
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := client.Submit(ctx, result); err != nil {
+		// handle error
+	}
+
+The API certificate is validated using the system certificate pool. Use
+SetTLSConfig to provide a custom CA or client certificate.
+
+# CHECK RESULT FILES
+
+Sidecar processes on the same system as an Icinga 2 instance may inject
+check results without the API by writing check result files to the spool
+directory read by the (deprecated) CheckResultReader feature. Each result
+is written to a file named c?????? followed by an empty marker file with
+the same name and an ".ok" suffix; Icinga 2 only processes check result
+files once the marker file exists and removes both files afterwards.
+
+	writer := icinga2.NewCheckResultWriter(icinga2.DefaultCheckResultDir)
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := writer.Submit(ctx, result); err != nil {
+		// handle error
+	}
+
+The CheckResultWriter type also satisfies the nagios.PassiveSubmitter
+interface.
+
+# TESTING
+
+The FakeServer type provides an in-memory Icinga 2 API endpoint for
+integration testing code which submits passive check results without a
+running Icinga 2 instance. Submitted check results are recorded and
+failures (invalid credentials, unknown objects, unavailable API) may be
+simulated.
+
+	server := icinga2.NewFakeServer("api-user", "s3cr3t")
+	defer server.Close()
+
+	client := server.Client("api-user", "s3cr3t")
+	// submit check results, then inspect server.Results()
+*/
+package icinga2
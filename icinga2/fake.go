@@ -0,0 +1,256 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package icinga2
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// FakeCheckResult is a check result recorded by a FakeServer.
+type FakeCheckResult struct {
+	// Result is the check result reconstructed from the request body. The
+	// Output field contains the plugin output followed by any performance
+	// data metrics.
+	Result nagios.PassiveCheckResult
+
+	// PluginOutput is the plugin output as submitted.
+	PluginOutput string
+
+	// PerformanceData is the collection of performance data metrics as
+	// submitted.
+	PerformanceData []string
+
+	// CheckSource is the check source as submitted.
+	CheckSource string
+}
+
+// FakeServer is an in-memory Icinga 2 API endpoint intended for use by
+// client code integration tests. Check results submitted to the
+// process-check-result action are decoded and recorded instead of being
+// processed by a monitoring system.
+//
+// The endpoint uses TLS with a self-signed certificate; use the HTTP client
+// returned by the HTTPClient method (or the Client method) to trust it.
+type FakeServer struct {
+	// mu guards the recorded check results and response settings.
+	mu sync.Mutex
+
+	// server is the underlying HTTP test server.
+	server *httptest.Server
+
+	// username is the API user name accepted by the endpoint.
+	username string
+
+	// password is the API user password accepted by the endpoint.
+	password string
+
+	// results is the collection of accepted check results.
+	results []FakeCheckResult
+
+	// unknownObjects is the collection of host or host!service object names
+	// for which submissions are rejected.
+	unknownObjects map[string]struct{}
+
+	// httpStatus is the HTTP status code returned for all submissions if
+	// set.
+	httpStatus int
+}
+
+// NewFakeServer starts and returns a new FakeServer which accepts
+// submissions using the given API user credentials. The caller is
+// responsible for calling Close when finished.
+func NewFakeServer(username string, password string) *FakeServer {
+	fs := FakeServer{
+		username:       username,
+		password:       password,
+		unknownObjects: make(map[string]struct{}),
+	}
+	fs.server = httptest.NewTLSServer(http.HandlerFunc(fs.handle))
+
+	return &fs
+}
+
+// URL returns the API base URL for use with NewClient.
+func (fs *FakeServer) URL() string {
+	return fs.server.URL
+}
+
+// HTTPClient returns an HTTP client configured to trust the endpoint
+// certificate for use with Client.SetHTTPClient.
+func (fs *FakeServer) HTTPClient() *http.Client {
+	return fs.server.Client()
+}
+
+// Client returns a new Client configured to submit check results to the
+// endpoint using the given API user credentials.
+func (fs *FakeServer) Client(username string, password string) *Client {
+	client := NewClient(fs.URL(), username, password)
+	client.SetHTTPClient(fs.HTTPClient())
+
+	return client
+}
+
+// Close shuts down the endpoint and blocks until all outstanding requests
+// have completed.
+func (fs *FakeServer) Close() {
+	fs.server.Close()
+}
+
+// Results returns a copy of the check results accepted by the endpoint in
+// the order received.
+func (fs *FakeServer) Results() []FakeCheckResult {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	results := make([]FakeCheckResult, len(fs.results))
+	copy(results, fs.results)
+
+	return results
+}
+
+// AddUnknownObject causes the endpoint to reject submissions for the given
+// host (or service if serviceName is not empty) in the same way as the API
+// does for objects which do not exist.
+func (fs *FakeServer) AddUnknownObject(hostName string, serviceName string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.unknownObjects[fakeObjectName(hostName, serviceName)] = struct{}{}
+}
+
+// SetHTTPStatus causes the endpoint to respond to all further submissions
+// with the given HTTP status code (e.g., to simulate an unavailable API). A
+// zero value restores the default behavior.
+func (fs *FakeServer) SetHTTPStatus(code int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.httpStatus = code
+}
+
+// Reset discards all recorded check results and restores the default
+// response behavior.
+func (fs *FakeServer) Reset() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.results = nil
+	fs.unknownObjects = make(map[string]struct{})
+	fs.httpStatus = 0
+}
+
+// handle processes a single API request.
+func (fs *FakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	user, pass, ok := r.BasicAuth()
+	switch {
+	case !ok || user != fs.username || pass != fs.password:
+		writeFakeError(w, http.StatusUnauthorized, "Unauthorized. Please check your user credentials.")
+		return
+	case r.URL.Path != processCheckResultPath:
+		writeFakeError(w, http.StatusNotFound, "The requested path is not available.")
+		return
+	case r.Method != http.MethodPost:
+		writeFakeError(w, http.StatusMethodNotAllowed, "Invalid request type. Must be POST.")
+		return
+	case fs.httpStatus != 0:
+		writeFakeError(w, fs.httpStatus, http.StatusText(fs.httpStatus))
+		return
+	}
+
+	var req checkResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	result := fakeCheckResult(req)
+	name := fakeObjectName(result.Result.HostName, result.Result.ServiceDescription)
+
+	if _, unknown := fs.unknownObjects[name]; unknown {
+		writeFakeError(w, http.StatusNotFound, "No objects found.")
+		return
+	}
+
+	fs.results = append(fs.results, result)
+
+	var resp checkResultResponse
+	resp.Results = append(resp.Results, struct {
+		Code   float64 `json:"code"`
+		Status string  `json:"status"`
+	}{
+		Code:   http.StatusOK,
+		Status: fmt.Sprintf("Successfully processed check result for object '%s'.", name),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeFakeError writes an API error response using the given HTTP status
+// code and message.
+func writeFakeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(checkResultResponse{
+		Error:  float64(code),
+		Status: message,
+	})
+}
+
+// fakeCheckResult returns the check result recorded for the given request
+// body.
+func fakeCheckResult(req checkResultRequest) FakeCheckResult {
+	result := nagios.PassiveCheckResult{
+		HostName:       req.FilterVars["hostname"],
+		ExitStatusCode: req.ExitStatus,
+		Output:         req.PluginOutput,
+	}
+
+	if req.Type == objectTypeService {
+		result.ServiceDescription = req.FilterVars["servicename"]
+	}
+
+	if len(req.PerformanceData) > 0 {
+		result.Output += " | " + strings.Join(req.PerformanceData, " ")
+	}
+
+	if req.ExecutionEnd != 0 {
+		sec, frac := math.Modf(req.ExecutionEnd)
+		result.Timestamp = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	}
+
+	return FakeCheckResult{
+		Result:          result,
+		PluginOutput:    req.PluginOutput,
+		PerformanceData: req.PerformanceData,
+		CheckSource:     req.CheckSource,
+	}
+}
+
+// fakeObjectName returns the Icinga 2 object name for the given host and
+// optional service.
+func fakeObjectName(hostName string, serviceName string) string {
+	if serviceName == "" {
+		return hostName
+	}
+
+	return hostName + "!" + serviceName
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package icinga2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestFakeServer_RecordsSubmittedResults(t *testing.T) {
+	t.Parallel()
+
+	server := NewFakeServer("api-user", "s3cr3t")
+	defer server.Close()
+
+	client := server.Client("api-user", "s3cr3t")
+	client.SetCheckSource("collector01")
+
+	results := []nagios.PassiveCheckResult{
+		{HostName: "web01", ExitStatusCode: nagios.StateOKExitCode, Output: "UP"},
+		{
+			HostName:           "web01",
+			ServiceDescription: "HTTP",
+			ExitStatusCode:     nagios.StateWARNINGExitCode,
+			Output:             "WARNING: slow response | 'time'=5ms;;;; 'size'=10B;;;;\n",
+		},
+	}
+
+	if err := client.Submit(context.Background(), results...); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	want := []FakeCheckResult{
+		{
+			Result:       results[0],
+			PluginOutput: "UP",
+			CheckSource:  "collector01",
+		},
+		{
+			Result: nagios.PassiveCheckResult{
+				HostName:           "web01",
+				ServiceDescription: "HTTP",
+				ExitStatusCode:     nagios.StateWARNINGExitCode,
+				Output:             "WARNING: slow response | 'time'=5ms;;;; 'size'=10B;;;;",
+			},
+			PluginOutput:    "WARNING: slow response",
+			PerformanceData: []string{"'time'=5ms;;;;", "'size'=10B;;;;"},
+			CheckSource:     "collector01",
+		},
+	}
+
+	if d := cmp.Diff(want, server.Results()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: submitted check results recorded as expected")
+}
+
+func TestFakeServer_SimulatesFailures(t *testing.T) {
+	t.Parallel()
+
+	server := NewFakeServer("api-user", "s3cr3t")
+	defer server.Close()
+
+	result := nagios.PassiveCheckResult{HostName: "web01", ServiceDescription: "HTTP"}
+
+	tests := map[string]struct {
+		client *Client
+		setup  func()
+	}{
+		"invalid credentials": {
+			client: server.Client("api-user", "wrong"),
+			setup:  func() {},
+		},
+		"unknown object": {
+			client: server.Client("api-user", "s3cr3t"),
+			setup:  func() { server.AddUnknownObject("web01", "HTTP") },
+		},
+		"HTTP status": {
+			client: server.Client("api-user", "s3cr3t"),
+			setup:  func() { server.SetHTTPStatus(http.StatusServiceUnavailable) },
+		},
+	}
+
+	// Subtests are not run in parallel as they share the fake server.
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			server.Reset()
+			tt.setup()
+
+			err := tt.client.Submit(context.Background(), result)
+			if !errors.Is(err, ErrSubmissionRejected) {
+				t.Fatalf("ERROR: want %v, got %v", ErrSubmissionRejected, err)
+			}
+
+			if got := len(server.Results()); got != 0 {
+				t.Fatalf("ERROR: want no recorded results, got %d", got)
+			}
+
+			t.Log("OK: failure simulated as expected")
+		})
+	}
+}
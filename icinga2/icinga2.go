@@ -0,0 +1,374 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package icinga2
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// processCheckResultPath is the API path used to submit check results.
+	processCheckResultPath string = "/v1/actions/process-check-result"
+
+	// objectTypeHost is the API object type for host check results.
+	objectTypeHost string = "Host"
+
+	// objectTypeService is the API object type for service check results.
+	objectTypeService string = "Service"
+
+	// hostFilter selects a host object by name using filter variables.
+	hostFilter string = "host.name==hostname"
+
+	// serviceFilter selects a service object by host name and service name
+	// using filter variables.
+	serviceFilter string = "host.name==hostname && service.name==servicename"
+
+	// defaultTimeout is the timeout used by the default HTTP client.
+	defaultTimeout time.Duration = 30 * time.Second
+
+	// maxResponseBodySize is the maximum number of bytes read from an API
+	// response body.
+	maxResponseBodySize int64 = 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingURL indicates that the Icinga 2 API URL was not provided.
+	ErrMissingURL = errors.New("Icinga 2 API URL not specified")
+
+	// ErrMissingCredentials indicates that the API user name or password was
+	// not provided.
+	ErrMissingCredentials = errors.New("Icinga 2 API credentials not specified")
+
+	// ErrNoCheckResults indicates that no check results were provided for
+	// submission.
+	ErrNoCheckResults = errors.New("no check results provided")
+
+	// ErrUnexpectedResponse indicates that the API returned a response which
+	// could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected Icinga 2 API response")
+
+	// ErrSubmissionRejected indicates that the API rejected the submitted
+	// check result (e.g., invalid credentials or unknown object).
+	ErrSubmissionRejected = errors.New("Icinga 2 API submission rejected")
+)
+
+// Client submits passive check results to the Icinga 2 API.
+type Client struct {
+	// endpoint is the Icinga 2 API base URL (e.g.,
+	// https://icinga.example.com:5665).
+	endpoint string
+
+	// username is the API user name.
+	username string
+
+	// password is the API user password.
+	password string
+
+	// checkSource is the optional check source reported with submitted
+	// check results.
+	checkSource string
+
+	// httpClient is used to perform requests.
+	httpClient *http.Client
+}
+
+// NewClient constructs a new Client for the given Icinga 2 API base URL and
+// API user credentials.
+func NewClient(endpoint string, username string, password string) *Client {
+	return &Client{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// SetCheckSource specifies the check source (e.g., the name of the system
+// performing the check) reported with submitted check results.
+func (c *Client) SetCheckSource(source string) {
+	c.checkSource = source
+}
+
+// SetTLSConfig overrides the TLS settings used by the default HTTP client
+// (e.g., to trust the Icinga 2 CA certificate). A nil value is ignored.
+func (c *Client) SetTLSConfig(config *tls.Config) {
+	if config == nil {
+		return
+	}
+
+	c.httpClient = &http.Client{
+		Timeout: c.httpClient.Timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: config,
+		},
+	}
+}
+
+// SetHTTPClient overrides the default HTTP client. A nil value is ignored.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		return
+	}
+
+	c.httpClient = client
+}
+
+// Submit sends the given check results to the Icinga 2 API. The API accepts
+// a single check result per request; submission stops at the first failed
+// request.
+//
+// Submit satisfies the nagios.PassiveSubmitter interface.
+func (c *Client) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	switch {
+	case strings.TrimSpace(c.endpoint) == "":
+		return ErrMissingURL
+	case c.username == "" || c.password == "":
+		return ErrMissingCredentials
+	case len(results) == 0:
+		return ErrNoCheckResults
+	}
+
+	for _, result := range results {
+		if err := c.submit(ctx, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkResultRequest is the request body used to submit a check result.
+type checkResultRequest struct {
+	Type            string            `json:"type"`
+	Filter          string            `json:"filter"`
+	FilterVars      map[string]string `json:"filter_vars"`
+	ExitStatus      int               `json:"exit_status"`
+	PluginOutput    string            `json:"plugin_output"`
+	PerformanceData []string          `json:"performance_data,omitempty"`
+	CheckSource     string            `json:"check_source,omitempty"`
+	ExecutionStart  float64           `json:"execution_start,omitempty"`
+	ExecutionEnd    float64           `json:"execution_end,omitempty"`
+}
+
+// checkResultResponse is the response body returned by the API.
+type checkResultResponse struct {
+	Results []struct {
+		Code   float64 `json:"code"`
+		Status string  `json:"status"`
+	} `json:"results"`
+
+	// Error and Status are set for failed requests.
+	Error  float64 `json:"error"`
+	Status string  `json:"status"`
+}
+
+// NewRequestBody returns the API request body for the given check result.
+// The rendered check result output is split into the plugin output and the
+// individual performance data metrics.
+func NewRequestBody(result nagios.PassiveCheckResult, checkSource string) ([]byte, error) {
+	pluginOutput, perfData := splitOutput(result.Output)
+
+	body := checkResultRequest{
+		ExitStatus:      result.ExitStatusCode,
+		PluginOutput:    pluginOutput,
+		PerformanceData: perfData,
+		CheckSource:     checkSource,
+	}
+
+	switch {
+	case result.IsHostCheckResult():
+		body.Type = objectTypeHost
+		body.Filter = hostFilter
+		body.FilterVars = map[string]string{
+			"hostname": result.HostName,
+		}
+	default:
+		body.Type = objectTypeService
+		body.Filter = serviceFilter
+		body.FilterVars = map[string]string{
+			"hostname":    result.HostName,
+			"servicename": result.ServiceDescription,
+		}
+	}
+
+	if !result.Timestamp.IsZero() {
+		ts := float64(result.Timestamp.UnixNano()) / float64(time.Second)
+		body.ExecutionStart = ts
+		body.ExecutionEnd = ts
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode check result as JSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// submit performs a single API request for the given check result.
+func (c *Client) submit(ctx context.Context, result nagios.PassiveCheckResult) error {
+	data, err := NewRequestBody(result, c.checkSource)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.endpoint+processCheckResultPath,
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prepare Icinga 2 API request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to submit check result to Icinga 2 API: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return fmt.Errorf("failed to read Icinga 2 API response: %w", err)
+	}
+
+	return parseResponse(resp.StatusCode, body)
+}
+
+// parseResponse evaluates the given API response and returns an error if the
+// check result was not accepted.
+func parseResponse(statusCode int, body []byte) error {
+	var resp checkResultResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		if statusCode != http.StatusOK {
+			return fmt.Errorf(
+				"Icinga 2 API returned HTTP status %d: %w",
+				statusCode,
+				ErrSubmissionRejected,
+			)
+		}
+
+		return fmt.Errorf("failed to decode JSON response: %v: %w", err, ErrUnexpectedResponse)
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Errorf(
+			"Icinga 2 API returned HTTP status %d (%s): %w",
+			statusCode,
+			resp.Status,
+			ErrSubmissionRejected,
+		)
+	}
+
+	if len(resp.Results) == 0 {
+		return fmt.Errorf("no results in response: %w", ErrUnexpectedResponse)
+	}
+
+	for _, r := range resp.Results {
+		if int(r.Code) != http.StatusOK {
+			return fmt.Errorf(
+				"Icinga 2 API returned code %d (%s): %w",
+				int(r.Code),
+				r.Status,
+				ErrSubmissionRejected,
+			)
+		}
+	}
+
+	return nil
+}
+
+// splitOutput splits rendered plugin output into the plugin output text and
+// individual performance data metrics following the Nagios plugin output
+// specification: performance data follows the first pipe character on the
+// first line and the first pipe character found in later lines, along with
+// any remaining lines.
+func splitOutput(output string) (string, []string) {
+	lines := strings.Split(strings.TrimRight(output, "\r\n"), "\n")
+
+	text := make([]string, 0, len(lines))
+	var perf []string
+
+	first, firstPerf, found := strings.Cut(lines[0], "|")
+	text = append(text, strings.TrimRight(first, " \r"))
+	if found {
+		perf = append(perf, firstPerf)
+	}
+
+	inPerfData := false
+	for _, line := range lines[1:] {
+		if inPerfData {
+			perf = append(perf, line)
+			continue
+		}
+
+		before, after, found := strings.Cut(line, "|")
+		if found {
+			inPerfData = true
+			if trimmed := strings.TrimRight(before, " \r"); trimmed != "" {
+				text = append(text, trimmed)
+			}
+			perf = append(perf, after)
+			continue
+		}
+
+		text = append(text, strings.TrimRight(line, "\r"))
+	}
+
+	return strings.TrimRight(strings.Join(text, "\n"), "\n"), splitPerfData(strings.Join(perf, " "))
+}
+
+// splitPerfData splits the given performance data into individual metrics.
+// Labels may be single quoted and contain spaces.
+func splitPerfData(perfData string) []string {
+	var metrics []string
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			metrics = append(metrics, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range perfData {
+		switch {
+		case r == '\'':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\r' || r == '\n'):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return metrics
+}
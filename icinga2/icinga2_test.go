@@ -0,0 +1,141 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package icinga2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure Client satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*Client)(nil)
+
+func TestClient_Submit_SendsCheckResult(t *testing.T) {
+	t.Parallel()
+
+	var got checkResultRequest
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "api-user" || pass != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != processCheckResultPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("ERROR: failed to decode request body: %v", err)
+		}
+
+		_, _ = fmt.Fprint(w, `{"results":[{"code":200.0,"status":"Successfully processed check result for object 'web01!HTTP'."}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "api-user", "s3cr3t")
+	client.SetHTTPClient(server.Client())
+	client.SetCheckSource("collector01")
+
+	result := nagios.PassiveCheckResult{
+		HostName:           "web01",
+		ServiceDescription: "HTTP",
+		ExitStatusCode:     nagios.StateWARNINGExitCode,
+		Output:             "WARNING: slow response\n\n**ERRORS**\n\n* none | 'time'=5ms;;;; 'response size'=10B;;;;\n",
+	}
+
+	if err := client.Submit(context.Background(), result); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	want := checkResultRequest{
+		Type:   objectTypeService,
+		Filter: serviceFilter,
+		FilterVars: map[string]string{
+			"hostname":    "web01",
+			"servicename": "HTTP",
+		},
+		ExitStatus:      nagios.StateWARNINGExitCode,
+		PluginOutput:    "WARNING: slow response\n\n**ERRORS**\n\n* none",
+		PerformanceData: []string{"'time'=5ms;;;;", "'response size'=10B;;;;"},
+		CheckSource:     "collector01",
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: check result submitted as expected")
+}
+
+func TestClient_Submit_ReportsRejectedSubmission(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"error":404.0,"status":"No objects found."}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "api-user", "s3cr3t")
+
+	err := client.Submit(context.Background(), nagios.PassiveCheckResult{HostName: "missing"})
+	if !errors.Is(err, ErrSubmissionRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrSubmissionRejected, err)
+	}
+
+	t.Log("OK: rejected submission reported as expected")
+}
+
+func TestSplitOutput_FollowsPluginOutputSpecification(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output   string
+		wantText string
+		wantPerf []string
+	}{
+		"no performance data": {
+			output:   "OK: all good",
+			wantText: "OK: all good",
+		},
+		"performance data on first line": {
+			output:   "OK: all good | a=1 b=2",
+			wantText: "OK: all good",
+			wantPerf: []string{"a=1", "b=2"},
+		},
+		"performance data on first and later lines": {
+			output:   "OK: all good | a=1\nline two\nline three | b=2\nc=3",
+			wantText: "OK: all good\nline two\nline three",
+			wantPerf: []string{"a=1", "b=2", "c=3"},
+		},
+	}
+
+	for name, tt := range tests {
+		text, perf := splitOutput(tt.output)
+
+		if text != tt.wantText {
+			t.Errorf("ERROR: %s: want text %q, got %q", name, tt.wantText, text)
+		}
+
+		if d := cmp.Diff(tt.wantPerf, perf); d != "" {
+			t.Errorf("ERROR: %s: (-want, +got)\n:%s", name, d)
+		}
+	}
+}
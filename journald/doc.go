@@ -0,0 +1,43 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package journald provides a debug logging output target which writes to the
+systemd journal.
+
+# OVERVIEW
+
+The Writer type implements io.Writer and can be used with
+nagios.Plugin.SetDebugLoggingOutputTarget so that per-check debug output
+lands in the systemd journal alongside other host logs. Entries are sent
+using the native journal protocol which allows structured metadata (e.g.,
+SYSLOG_IDENTIFIER or custom fields such as the monitored host and service)
+to be recorded with each message.
+
+Each write is recorded as a separate journal entry. The priority of each
+entry is determined as follows:
+
+  - a leading sd-daemon style priority prefix (e.g., "<3>") is removed
+    from the message and used as the priority
+  - messages from this library reporting failures, panics or invalid
+    settings are recorded with the warning priority
+  - all other messages use the default priority (debug unless overridden)
+
+If the journal socket is not available (e.g., the plugin is not running on
+a systemd host) messages are written to os.Stderr instead.
+
+# HOW TO USE
+
+	journal := journald.NewWriter("check_http")
+	journal.SetField("NAGIOS_HOST", "web01")
+	journal.SetField("NAGIOS_SERVICE", "HTTP")
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(journal)
+	plugin.DebugLoggingEnableAll()
+*/
+package journald
@@ -0,0 +1,278 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Priority is the syslog priority (severity) of a journal entry.
+type Priority int
+
+// Supported priorities.
+const (
+	PriorityEmergency Priority = iota
+	PriorityAlert
+	PriorityCritical
+	PriorityError
+	PriorityWarning
+	PriorityNotice
+	PriorityInfo
+	PriorityDebug
+)
+
+// DefaultSocket is the path of the journal native protocol socket.
+const DefaultSocket string = "/run/systemd/journal/socket"
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrInvalidFieldName indicates that a journal field name does not
+	// satisfy the journal field naming rules.
+	ErrInvalidFieldName = errors.New("invalid journal field name")
+)
+
+// validFieldName matches journal field names which may be set by client
+// code: uppercase letters, digits and underscores, not starting with an
+// underscore.
+var validFieldName = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_]*$`)
+
+// warningKeywords are (lowercase) keywords in messages from this library
+// which are recorded with the warning priority.
+var warningKeywords = []string{
+	"failed",
+	"failure",
+	"panic",
+	"invalid",
+}
+
+// Writer writes each message as a journal entry, falling back to a
+// secondary output target (os.Stderr by default) if the journal is not
+// available.
+//
+// A Writer is safe for concurrent use.
+type Writer struct {
+	// mu guards the connection and fields.
+	mu sync.Mutex
+
+	// socket is the path of the journal socket.
+	socket string
+
+	// conn is the connection to the journal socket. This is established on
+	// first use.
+	conn net.Conn
+
+	// unavailable indicates that the journal socket could not be reached;
+	// later messages are written directly to the fallback target.
+	unavailable bool
+
+	// fallback is the output target used if the journal is not available.
+	fallback io.Writer
+
+	// priority is the default priority of journal entries.
+	priority Priority
+
+	// fields is the collection of fields included with each entry.
+	fields map[string]string
+}
+
+// NewWriter constructs a new Writer using the given syslog identifier. If
+// identifier is empty the base name of the executable is used. Entries are
+// recorded with the debug priority by default.
+func NewWriter(identifier string) *Writer {
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	return &Writer{
+		socket:   DefaultSocket,
+		fallback: os.Stderr,
+		priority: PriorityDebug,
+		fields: map[string]string{
+			"SYSLOG_IDENTIFIER": identifier,
+		},
+	}
+}
+
+// SetPriority overrides the default priority of journal entries. Values
+// outside of the supported range are ignored.
+func (w *Writer) SetPriority(priority Priority) {
+	if priority < PriorityEmergency || priority > PriorityDebug {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.priority = priority
+}
+
+// SetField specifies a field (e.g., NAGIOS_HOST) included with each journal
+// entry. Field names must consist of uppercase letters, digits and
+// underscores and must not start with an underscore. The MESSAGE and
+// PRIORITY fields are managed by the Writer and cannot be overridden.
+func (w *Writer) SetField(name string, value string) error {
+	if !validFieldName.MatchString(name) || name == "MESSAGE" || name == "PRIORITY" {
+		return fmt.Errorf("field %q: %w", name, ErrInvalidFieldName)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.fields[name] = value
+
+	return nil
+}
+
+// SetFallbackTarget overrides the output target used if the journal is not
+// available. A nil value is ignored.
+func (w *Writer) SetFallbackTarget(fallback io.Writer) {
+	if fallback == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.fallback = fallback
+}
+
+// Write records the given message as a journal entry. If the journal is not
+// available the message is written to the fallback target instead.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.unavailable {
+		return w.fallback.Write(p)
+	}
+
+	priority, message := w.messagePriority(string(p))
+
+	if err := w.send(EncodeEntry(w.entryFields(priority, message))); err != nil {
+		w.unavailable = true
+
+		return w.fallback.Write(p)
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection to the journal socket (if any).
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
+
+// send writes the given datagram to the journal socket, establishing the
+// connection if needed.
+func (w *Writer) send(datagram []byte) error {
+	if w.conn == nil {
+		conn, err := net.Dial("unixgram", w.socket)
+		if err != nil {
+			return fmt.Errorf("failed to connect to journal socket: %w", err)
+		}
+		w.conn = conn
+	}
+
+	if _, err := w.conn.Write(datagram); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// messagePriority returns the priority and message text for the given
+// message. A leading sd-daemon style priority prefix is removed.
+func (w *Writer) messagePriority(msg string) (Priority, string) {
+	msg = strings.TrimRight(msg, " \r\n")
+
+	if len(msg) >= 3 && msg[0] == '<' && msg[2] == '>' && msg[1] >= '0' && msg[1] <= '7' {
+		return Priority(msg[1] - '0'), msg[3:]
+	}
+
+	lower := strings.ToLower(msg)
+	for _, keyword := range warningKeywords {
+		if strings.Contains(lower, keyword) && w.priority > PriorityWarning {
+			return PriorityWarning, msg
+		}
+	}
+
+	return w.priority, msg
+}
+
+// entryFields returns the fields of a journal entry for the given priority
+// and message.
+func (w *Writer) entryFields(priority Priority, message string) map[string]string {
+	fields := make(map[string]string, len(w.fields)+2)
+	for name, value := range w.fields {
+		fields[name] = value
+	}
+
+	fields["MESSAGE"] = message
+	fields["PRIORITY"] = strconv.Itoa(int(priority))
+
+	return fields
+}
+
+// EncodeEntry returns the given fields encoded using the journal native
+// protocol. Values containing newlines are encoded using the binary
+// (length-prefixed) form. MESSAGE is written first; other fields follow in
+// sorted order.
+func EncodeEntry(fields map[string]string) []byte {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if name != "MESSAGE" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if _, ok := fields["MESSAGE"]; ok {
+		names = append([]string{"MESSAGE"}, names...)
+	}
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		value := fields[name]
+
+		if !strings.Contains(value, "\n") {
+			buf.WriteString(name + "=" + value + "\n")
+			continue
+		}
+
+		buf.WriteString(name + "\n")
+		size := make([]byte, 8)
+		binary.LittleEndian.PutUint64(size, uint64(len(value)))
+		buf.Write(size)
+		buf.WriteString(value + "\n")
+	}
+
+	return buf.Bytes()
+}
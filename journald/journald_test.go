@@ -0,0 +1,75 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package journald
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncodeEntry_UsesBinaryFormForMultilineValues(t *testing.T) {
+	t.Parallel()
+
+	got := EncodeEntry(map[string]string{
+		"PRIORITY": "7",
+		"MESSAGE":  "line one\nline two",
+		"A_FIELD":  "value",
+	})
+
+	want := "MESSAGE\n\x11\x00\x00\x00\x00\x00\x00\x00line one\nline two\n" +
+		"A_FIELD=value\n" +
+		"PRIORITY=7\n"
+
+	if string(got) != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	t.Log("OK: journal entry encoded as expected")
+}
+
+func TestWriter_Write_FallsBackWhenJournalUnavailable(t *testing.T) {
+	t.Parallel()
+
+	var fallback strings.Builder
+
+	writer := NewWriter("check_test")
+	writer.socket = filepath.Join(t.TempDir(), "missing.socket")
+	writer.SetFallbackTarget(&fallback)
+
+	for _, msg := range []string{"first\n", "second\n"} {
+		if _, err := writer.Write([]byte(msg)); err != nil {
+			t.Fatalf("ERROR: unexpected write failure: %v", err)
+		}
+	}
+
+	if fallback.String() != "first\nsecond\n" {
+		t.Fatalf("ERROR: unexpected fallback output %q", fallback.String())
+	}
+
+	t.Log("OK: messages written to fallback target as expected")
+}
+
+func TestWriter_SetField_RejectsInvalidNames(t *testing.T) {
+	t.Parallel()
+
+	writer := NewWriter("check_test")
+
+	for _, name := range []string{"_PID", "lower", "MESSAGE", "PRIORITY", ""} {
+		if err := writer.SetField(name, "x"); !errors.Is(err, ErrInvalidFieldName) {
+			t.Errorf("ERROR: field %q: want %v, got %v", name, ErrInvalidFieldName, err)
+		}
+	}
+
+	if err := writer.SetField("NAGIOS_HOST", "web01"); err != nil {
+		t.Fatalf("ERROR: unexpected failure for valid field name: %v", err)
+	}
+
+	t.Log("OK: invalid field names rejected as expected")
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package journald
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestWriter_Write_SendsEntriesWithPriorityMapping(t *testing.T) {
+	t.Parallel()
+
+	// Unix socket paths are limited in length; avoid long temporary
+	// directory paths.
+	dir, err := os.MkdirTemp("", "jd")
+	if err != nil {
+		t.Fatalf("ERROR: failed to create temporary directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "journal.socket")
+
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	writer := NewWriter("check_test")
+	writer.socket = socket
+	defer func() { _ = writer.Close() }()
+
+	if err := writer.SetField("NAGIOS_HOST", "web01"); err != nil {
+		t.Fatalf("ERROR: failed to set field: %v", err)
+	}
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(writer)
+	plugin.DebugLoggingEnableActions()
+	plugin.SetOutputTarget(nil)
+
+	if _, err := writer.Write([]byte("<3>explicit error\n")); err != nil {
+		t.Fatalf("ERROR: unexpected write failure: %v", err)
+	}
+
+	if err := listener.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("ERROR: failed to set read deadline: %v", err)
+	}
+
+	var entries []string
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := listener.Read(buf)
+		if err != nil {
+			t.Fatalf("ERROR: failed to read journal entry: %v", err)
+		}
+		entries = append(entries, string(buf[:n]))
+
+		if strings.Contains(entries[len(entries)-1], "explicit error") {
+			break
+		}
+	}
+
+	var sawInvalid bool
+	for _, entry := range entries {
+		if !strings.Contains(entry, "SYSLOG_IDENTIFIER=check_test\n") || !strings.Contains(entry, "NAGIOS_HOST=web01\n") {
+			t.Errorf("ERROR: entry missing metadata: %q", entry)
+		}
+
+		switch {
+		case strings.Contains(entry, "output target is invalid"):
+			sawInvalid = true
+			if !strings.Contains(entry, "PRIORITY=4\n") {
+				t.Errorf("ERROR: want warning priority: %q", entry)
+			}
+		case strings.Contains(entry, "explicit error"):
+			if !strings.HasPrefix(entry, "MESSAGE=explicit error\n") || !strings.Contains(entry, "PRIORITY=3\n") {
+				t.Errorf("ERROR: priority prefix not applied: %q", entry)
+			}
+		default:
+			if !strings.Contains(entry, "PRIORITY=7\n") {
+				t.Errorf("ERROR: want debug priority: %q", entry)
+			}
+		}
+	}
+
+	if !sawInvalid {
+		t.Errorf("ERROR: library warning not recorded: %q", entries)
+	}
+
+	t.Log("OK: journal entries sent as expected")
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+// defaultPanicServiceOutputSummary is the summary text used in place of the
+// ServiceOutput field if a panic in client code is detected.
+const defaultPanicServiceOutputSummary string = "plugin crash detected. See details via web UI or run plugin manually via CLI."
+
+// Default text used for table column headers and other generated content.
+const (
+	defaultMetricsSummaryMetricLabel     string = "Metric"
+	defaultMetricsSummaryValueLabel      string = "Value"
+	defaultMetricsSummaryWarningLabel    string = "Warning"
+	defaultMetricsSummaryCriticalLabel   string = "Critical"
+	defaultMetricsSummaryOutcomeLabel    string = "Outcome"
+	defaultInvalidThresholdLabel         string = "(invalid threshold)"
+	defaultComponentResultsNameLabel     string = "Component"
+	defaultComponentResultsStateLabel    string = "State"
+	defaultComponentResultsMessageLabel  string = "Message"
+	defaultComponentResultsDurationLabel string = "Duration"
+	defaultRepeatedErrorFormat           string = " (repeated %d times)"
+	defaultThresholdViolationFormat      string = "%s: %s value %s outside of threshold range %q"
+	defaultTimeoutReachedFormat          string = "plugin timeout of %s reached before check completed"
+	defaultCheckCancelledSummary         string = "plugin check cancelled before check completed"
+	defaultCheckTimedOutFormat           string = "plugin timed out before check completed (%v)"
+)
+
+// Labels is a collection of text emitted by this library as part of plugin
+// output (section headers and other boilerplate text). Empty fields use the
+// default text.
+type Labels struct {
+	// Errors is the header text of the Errors section.
+	Errors string
+
+	// Thresholds is the header text of the Thresholds section.
+	Thresholds string
+
+	// ThresholdViolations is the header text of the Threshold Violations
+	// section.
+	ThresholdViolations string
+
+	// DetailedInfo is the header text of the LongServiceOutput section.
+	DetailedInfo string
+
+	// MetricsSummary is the header text of the Metrics Summary section.
+	MetricsSummary string
+
+	// EncodedPayload is the header text of the Encoded Payload section.
+	EncodedPayload string
+
+	// SuggestedAction is the text (including any trailing separator)
+	// preceding the remediation hint of a ServiceCheckError listed in the
+	// Errors section.
+	SuggestedAction string
+
+	// EmptyServiceOutput is the summary text (following the state label)
+	// used in place of an empty ServiceOutput field. See
+	// EnableUnknownOnEmptyServiceOutput.
+	EmptyServiceOutput string
+
+	// PanicServiceOutput is the summary text (following the state label)
+	// used in place of the ServiceOutput field if a panic in client code is
+	// detected.
+	PanicServiceOutput string
+
+	// MetricsSummaryMetric, MetricsSummaryValue, MetricsSummaryWarning,
+	// MetricsSummaryCritical and MetricsSummaryOutcome are the column
+	// headers of the Metrics Summary section.
+	MetricsSummaryMetric   string
+	MetricsSummaryValue    string
+	MetricsSummaryWarning  string
+	MetricsSummaryCritical string
+	MetricsSummaryOutcome  string
+
+	// MetricsSummaryNoValue is the placeholder used in the Metrics Summary
+	// section for empty metric fields.
+	MetricsSummaryNoValue string
+
+	// InvalidThreshold is the text (following the UNKNOWN state label) used
+	// in the Metrics Summary section for a metric with a threshold which
+	// cannot be parsed.
+	InvalidThreshold string
+
+	// ComponentResultsName, ComponentResultsState, ComponentResultsMessage
+	// and ComponentResultsDuration are the column headers of the component
+	// results table. See AddResult.
+	ComponentResultsName     string
+	ComponentResultsState    string
+	ComponentResultsMessage  string
+	ComponentResultsDuration string
+
+	// RepeatedError is the format string for the text following a repeated
+	// error message listed in the Errors section. The format string receives
+	// the number of occurrences. See EnableErrorDeduplication.
+	RepeatedError string
+
+	// ThresholdViolation is the format string for each entry listed in the
+	// Threshold Violations section. The format string receives the state
+	// label, the metric label, the metric value (including the unit of
+	// measurement) and the threshold range.
+	ThresholdViolation string
+
+	// TimeoutReached is the format string for the summary text (following
+	// the state label) emitted when the plugin timeout is reached. The
+	// format string receives the plugin timeout. See SetTimeout.
+	TimeoutReached string
+
+	// CheckCancelled is the summary text (following the state label)
+	// emitted when the context of check logic executed via RunWithContext is
+	// cancelled before the check completes.
+	CheckCancelled string
+
+	// CheckTimedOut is the format string for the summary text (following
+	// the state label) emitted when the context of check logic executed via
+	// RunWithContext is done for a reason other than cancellation before the
+	// check completes. The format string receives the context error.
+	CheckTimedOut string
+
+	// TimeoutAdvice is the advice emitted following the summary text when
+	// the plugin timeout is reached.
+	TimeoutAdvice string
+}
+
+// DefaultLabels returns the default text emitted by this library as part of
+// plugin output. This is intended as a starting point for a custom label
+// bundle (e.g., a translation). See SetLabels.
+func DefaultLabels() Labels {
+	return Labels{
+		Errors:              defaultErrorsLabel,
+		Thresholds:          defaultThresholdsLabel,
+		ThresholdViolations: defaultThresholdViolationsLabel,
+		DetailedInfo:        defaultDetailedInfoLabel,
+		MetricsSummary:      defaultMetricsSummaryLabel,
+		EncodedPayload:      defaultEncodedPayloadLabel,
+		SuggestedAction:     suggestedActionPrefix,
+		EmptyServiceOutput:  emptyServiceOutputSummary,
+		PanicServiceOutput:  defaultPanicServiceOutputSummary,
+
+		MetricsSummaryMetric:   defaultMetricsSummaryMetricLabel,
+		MetricsSummaryValue:    defaultMetricsSummaryValueLabel,
+		MetricsSummaryWarning:  defaultMetricsSummaryWarningLabel,
+		MetricsSummaryCritical: defaultMetricsSummaryCriticalLabel,
+		MetricsSummaryOutcome:  defaultMetricsSummaryOutcomeLabel,
+		MetricsSummaryNoValue:  metricsSummaryNoValue,
+		InvalidThreshold:       defaultInvalidThresholdLabel,
+
+		ComponentResultsName:     defaultComponentResultsNameLabel,
+		ComponentResultsState:    defaultComponentResultsStateLabel,
+		ComponentResultsMessage:  defaultComponentResultsMessageLabel,
+		ComponentResultsDuration: defaultComponentResultsDurationLabel,
+
+		RepeatedError:      defaultRepeatedErrorFormat,
+		ThresholdViolation: defaultThresholdViolationFormat,
+		TimeoutReached:     defaultTimeoutReachedFormat,
+		CheckCancelled:     defaultCheckCancelledSummary,
+		CheckTimedOut:      defaultCheckTimedOutFormat,
+		TimeoutAdvice:      runtimeTimeoutReachedAdvice,
+	}
+}
+
+// SetLabels overrides the text emitted by this library as part of plugin
+// output using the given label bundle. This allows all emitted boilerplate
+// text to be customized (e.g., translated) in one call instead of calling
+// each Set*Label method. Empty fields restore the default text.
+//
+// Sections using custom labels are not recognized by ParsePluginOutput.
+func (p *Plugin) SetLabels(labels Labels) {
+	p.logAction("Setting custom label bundle as requested")
+
+	p.errorsLabel = labels.Errors
+	p.thresholdsLabel = labels.Thresholds
+	p.thresholdViolationsLabel = labels.ThresholdViolations
+	p.detailedInfoLabel = labels.DetailedInfo
+	p.metricsSummaryLabel = labels.MetricsSummary
+	p.encodedPayloadLabel = labels.EncodedPayload
+	p.suggestedActionLabel = labels.SuggestedAction
+	p.emptyServiceOutputLabel = labels.EmptyServiceOutput
+	p.panicServiceOutputLabel = labels.PanicServiceOutput
+
+	p.labels = labels
+}
+
+// labelText returns the given custom label text if set, otherwise the given
+// default text.
+func labelText(custom string, defaultText string) string {
+	if custom != "" {
+		return custom
+	}
+
+	return defaultText
+}
+
+// getSuggestedActionLabelText retrieves the custom suggested action text if
+// set, otherwise returns the default value.
+func (p Plugin) getSuggestedActionLabelText() string {
+	switch {
+	case p.suggestedActionLabel != "":
+		return p.suggestedActionLabel
+	default:
+		return suggestedActionPrefix
+	}
+}
+
+// getEmptyServiceOutputLabelText retrieves the custom empty ServiceOutput
+// summary text if set, otherwise returns the default value.
+func (p Plugin) getEmptyServiceOutputLabelText() string {
+	switch {
+	case p.emptyServiceOutputLabel != "":
+		return p.emptyServiceOutputLabel
+	default:
+		return emptyServiceOutputSummary
+	}
+}
+
+// getPanicServiceOutputLabelText retrieves the custom panic ServiceOutput
+// summary text if set, otherwise returns the default value.
+func (p Plugin) getPanicServiceOutputLabelText() string {
+	switch {
+	case p.panicServiceOutputLabel != "":
+		return p.panicServiceOutputLabel
+	default:
+		return defaultPanicServiceOutputSummary
+	}
+}
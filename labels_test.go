@@ -0,0 +1,232 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetLabels_TranslatesOutput(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+
+	labels := nagios.DefaultLabels()
+	labels.Errors = "FEHLER"
+	labels.Thresholds = "SCHWELLENWERTE"
+	labels.DetailedInfo = "DETAILS"
+	labels.SuggestedAction = "Empfohlene Aktion: "
+	plugin.SetLabels(labels)
+
+	plugin.ServiceOutput = "CRITICAL: Datenspeicher voll"
+	plugin.LongServiceOutput = "ds-01: 95%"
+	plugin.CriticalThreshold = "90%"
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.AddError(nagios.NewServiceCheckError(
+		nagios.ServiceState{Label: nagios.StateCRITICALLabel, ExitCode: nagios.StateCRITICALExitCode},
+		"Datenspeicher voll",
+	).WithHint("Speicher freigeben"))
+
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+
+	for _, want := range []string{
+		"**FEHLER**",
+		"**SCHWELLENWERTE**",
+		"**DETAILS**",
+		"Empfohlene Aktion: Speicher freigeben",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: %q not found in output:\n%s", want, got)
+		}
+	}
+
+	for _, unwanted := range []string{"**ERRORS**", "**THRESHOLDS**", "**DETAILED INFO**", "Suggested action: "} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("ERROR: default text %q found in output:\n%s", unwanted, got)
+		}
+	}
+
+	t.Log("OK: label bundle applied to output as expected")
+}
+
+func TestPlugin_SetLabels_TranslatesGeneratedSummaries(t *testing.T) {
+	t.Parallel()
+
+	labels := nagios.Labels{
+		EmptyServiceOutput: "keine Zusammenfassung",
+		PanicServiceOutput: "Absturz erkannt",
+	}
+
+	t.Run("empty ServiceOutput", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuffer strings.Builder
+
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(&outputBuffer)
+		plugin.SkipOSExit()
+		plugin.SetLabels(labels)
+		plugin.EnableUnknownOnEmptyServiceOutput()
+		plugin.ReturnCheckResults()
+
+		if want := "UNKNOWN: keine Zusammenfassung"; !strings.HasPrefix(outputBuffer.String(), want) {
+			t.Fatalf("ERROR: want output prefix %q, got %q", want, outputBuffer.String())
+		}
+
+		t.Log("OK: empty ServiceOutput summary translated as expected")
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		var outputBuffer strings.Builder
+
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(&outputBuffer)
+		plugin.SkipOSExit()
+		plugin.SetLabels(labels)
+
+		func() {
+			defer plugin.ReturnCheckResults()
+			panic(errors.New("boom"))
+		}()
+
+		if want := "CRITICAL: Absturz erkannt"; !strings.HasPrefix(outputBuffer.String(), want) {
+			t.Fatalf("ERROR: want output prefix %q, got %q", want, outputBuffer.String())
+		}
+
+		t.Log("OK: panic summary translated as expected")
+	})
+}
+
+func TestPlugin_SetLabels_EmptyFieldsUseDefaults(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetErrorsLabel("CUSTOM ERRORS")
+	plugin.SetLabels(nagios.Labels{})
+	plugin.ServiceOutput = "WARNING: disk usage high"
+	plugin.AddError(nagios.ErrInvalidRangeThreshold)
+	plugin.ReturnCheckResults()
+
+	if !strings.Contains(outputBuffer.String(), "**ERRORS**") {
+		t.Fatalf("ERROR: default errors label not found in output:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: empty label bundle fields restored default text as expected")
+}
+
+func TestPlugin_SetLabels_TranslatesGeneratedText(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	labels := nagios.DefaultLabels()
+	labels.MetricsSummaryMetric = "Messwert"
+	labels.MetricsSummaryOutcome = "Ergebnis"
+	labels.MetricsSummaryNoValue = "k.A."
+	labels.InvalidThreshold = "(ungültiger Schwellenwert)"
+	labels.ComponentResultsName = "Komponente"
+	labels.ComponentResultsMessage = "Meldung"
+	labels.RepeatedError = " (%d-mal wiederholt)"
+	labels.ThresholdViolation = "%s: %s Wert %s außerhalb von %q"
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetLabels(labels)
+	plugin.EnableMetricsSummarySection()
+	plugin.EnableErrorDeduplication()
+	plugin.ServiceOutput = "CRITICAL: datastore full"
+
+	perfData := []nagios.PerformanceData{
+		{Label: "usage", Value: "95", Crit: "90"},
+		{Label: "latency", Value: "3", Warn: "@@bogus"},
+	}
+
+	if err := plugin.AddPerfData(false, perfData...); err != nil {
+		t.Fatalf("ERROR: failed to add perfdata: %v", err)
+	}
+
+	if err := plugin.EvaluateThreshold(perfData[0]); err != nil {
+		t.Fatalf("ERROR: failed to evaluate thresholds: %v", err)
+	}
+
+	plugin.AddResult("datastore1", nagios.StateCRITICALExitCode, "95% used")
+	plugin.AddError(nagios.ErrInvalidRangeThreshold, nagios.ErrInvalidRangeThreshold)
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+
+	for _, want := range []string{
+		"Messwert",
+		"Ergebnis",
+		"k.A.",
+		"UNKNOWN (ungültiger Schwellenwert)",
+		"Komponente",
+		"Meldung",
+		" (2-mal wiederholt)",
+		`CRITICAL: usage Wert 95 außerhalb von "90"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: want %q in output:\n%s", want, got)
+		}
+	}
+
+	t.Log("OK: generated table headers and text translated as expected")
+}
+
+func TestPlugin_SetLabels_TranslatesRunContextSummary(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+
+	labels := nagios.Labels{
+		CheckCancelled: "Prüfung abgebrochen",
+		TimeoutAdvice:  "Zeitlimit erhöhen",
+	}
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetLabels(labels)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	plugin.RunWithContext(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	got := outputBuffer.String()
+
+	for _, want := range []string{"UNKNOWN: Prüfung abgebrochen", "Zeitlimit erhöhen"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: want %q in output:\n%s", want, got)
+		}
+	}
+
+	t.Log("OK: run context summary translated as expected")
+}
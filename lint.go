@@ -0,0 +1,336 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LintSeverity indicates how serious a plugin output lint issue is.
+type LintSeverity int
+
+// Plugin output lint issue severities.
+const (
+	// LintSeverityWarning indicates output which is valid but likely to be
+	// displayed or processed incorrectly by some monitoring systems.
+	LintSeverityWarning LintSeverity = iota + 1
+
+	// LintSeverityError indicates output which violates the plugin
+	// guidelines.
+	LintSeverityError
+)
+
+// Plugin output lint rules.
+const (
+	// LintRuleEmptyOutput indicates that no plugin output was provided.
+	LintRuleEmptyOutput string = "empty-output"
+
+	// LintRuleFirstLine indicates a problem with the first line (the
+	// one-line summary) of plugin output.
+	LintRuleFirstLine string = "first-line-format"
+
+	// LintRulePerfData indicates invalid performance data syntax.
+	LintRulePerfData string = "perfdata-syntax"
+
+	// LintRuleSize indicates plugin output exceeding common size limits.
+	LintRuleSize string = "size-limit"
+
+	// LintRuleIllegalChars indicates characters which are invalid or
+	// stripped by monitoring systems.
+	LintRuleIllegalChars string = "illegal-characters"
+
+	// LintRuleEOL indicates line endings other than the expected newline.
+	LintRuleEOL string = "eol"
+)
+
+const (
+	// lintIllegalMacroOutputChars is the default Nagios
+	// illegal_macro_output_chars setting. These characters are stripped from
+	// output macros (e.g., $SERVICEOUTPUT$) used in notifications and event
+	// handlers.
+	lintIllegalMacroOutputChars string = "`~$&\"'<>"
+)
+
+// lintPayloadDelimiterReplacer removes the default encoded payload
+// delimiters from output text. The delimiters are emitted by the library and
+// are not reported as illegal macro output characters.
+var lintPayloadDelimiterReplacer = strings.NewReplacer(
+	DefaultASCII85EncodingDelimiterLeft, "",
+	DefaultASCII85EncodingDelimiterRight, "",
+)
+
+// lintStateLabelRegex matches a plugin state label as a word.
+var lintStateLabelRegex = regexp.MustCompile(
+	`\b(` + strings.Join(SupportedStateLabels(), "|") + `)\b`,
+)
+
+// LintIssue is a problem found in rendered plugin output.
+type LintIssue struct {
+	// Rule is the lint rule which found the problem (e.g.,
+	// LintRulePerfData).
+	Rule string
+
+	// Severity indicates how serious the problem is.
+	Severity LintSeverity
+
+	// Line is the (1-based) line number of the problem or zero if the
+	// problem applies to the output as a whole.
+	Line int
+
+	// Message describes the problem.
+	Message string
+}
+
+// String returns a human readable label for the lint issue severity.
+func (s LintSeverity) String() string {
+	switch s {
+	case LintSeverityWarning:
+		return "warning"
+	case LintSeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// String returns a one-line description of the lint issue.
+func (i LintIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", i.Severity, i.Rule, i.Message)
+	}
+
+	return fmt.Sprintf("line %d: %s: %s: %s", i.Line, i.Severity, i.Rule, i.Message)
+}
+
+// LintOutput checks rendered plugin output against the Nagios plugin
+// guidelines and common monitoring system limitations. The following are
+// checked:
+//
+//   - the output is not empty
+//   - the first line is not empty and includes a plugin state label (e.g.,
+//     "OK" or "CRITICAL")
+//   - performance data metrics use valid syntax
+//   - the output size is within the Nagios Core and NRPE v2 limits
+//   - the output does not contain control characters or characters removed
+//     from notification macros by the default Nagios
+//     illegal_macro_output_chars setting (the default encoded payload
+//     delimiters are exempt)
+//   - lines end with a newline instead of a carriage return/newline pair
+//
+// Issues are returned in the order found. A nil value is returned if no
+// issues are found.
+func LintOutput(s string) []LintIssue {
+	if strings.TrimSpace(s) == "" {
+		return []LintIssue{{
+			Rule:     LintRuleEmptyOutput,
+			Severity: LintSeverityError,
+			Message:  "plugin output is empty",
+		}}
+	}
+
+	var issues []LintIssue
+
+	issues = append(issues, lintSize(s)...)
+
+	lines := strings.Split(s, "\n")
+
+	issues = append(issues, lintFirstLine(lines[0])...)
+
+	perfDataLine := lintPerfDataStart(lines)
+
+	for i, line := range lines {
+		// Performance data is not included in notification macros.
+		text := line
+		if i == 0 || i == perfDataLine {
+			text, _, _ = strings.Cut(line, "|")
+		} else if perfDataLine > 0 && i > perfDataLine {
+			text = ""
+		}
+
+		issues = append(issues, lintLine(i+1, line, text)...)
+	}
+
+	issues = append(issues, lintPerfData(lines)...)
+
+	return issues
+}
+
+// lintSize checks the size of the given plugin output.
+func lintSize(s string) []LintIssue {
+	switch {
+	case len(s) > NagiosCoreMaxOutputLength:
+		return []LintIssue{{
+			Rule:     LintRuleSize,
+			Severity: LintSeverityError,
+			Message: fmt.Sprintf(
+				"output is %d bytes; Nagios Core truncates output beyond %d bytes",
+				len(s),
+				NagiosCoreMaxOutputLength,
+			),
+		}}
+
+	case len(s) > NRPEv2MaxOutputLength:
+		return []LintIssue{{
+			Rule:     LintRuleSize,
+			Severity: LintSeverityWarning,
+			Message: fmt.Sprintf(
+				"output is %d bytes; NRPE v2 truncates output beyond %d bytes",
+				len(s),
+				NRPEv2MaxOutputLength,
+			),
+		}}
+
+	default:
+		return nil
+	}
+}
+
+// lintFirstLine checks the format of the first line (the one-line summary)
+// of plugin output.
+func lintFirstLine(line string) []LintIssue {
+	summary, _, _ := strings.Cut(line, "|")
+	summary = strings.TrimSpace(summary)
+
+	switch {
+	case summary == "":
+		return []LintIssue{{
+			Rule:     LintRuleFirstLine,
+			Severity: LintSeverityError,
+			Line:     1,
+			Message:  "first line does not contain a summary",
+		}}
+
+	case !lintStateLabelRegex.MatchString(summary):
+		return []LintIssue{{
+			Rule:     LintRuleFirstLine,
+			Severity: LintSeverityWarning,
+			Line:     1,
+			Message: fmt.Sprintf(
+				"first line does not include a plugin state label (one of %s)",
+				strings.Join(SupportedStateLabels(), ", "),
+			),
+		}}
+
+	default:
+		return nil
+	}
+}
+
+// lintLine checks the characters and line ending of the given line of
+// plugin output. The given text is the portion of the line which is not
+// performance data.
+func lintLine(lineNum int, line string, text string) []LintIssue {
+	var issues []LintIssue
+
+	if strings.HasSuffix(line, "\r") {
+		issues = append(issues, LintIssue{
+			Rule:     LintRuleEOL,
+			Severity: LintSeverityWarning,
+			Line:     lineNum,
+			Message:  `line ends with "\r\n"; use "\n" line endings`,
+		})
+		line = strings.TrimSuffix(line, "\r")
+	}
+
+	var controlChars, macroChars []string
+
+	for _, r := range line {
+		if r != '\t' && (r < 0x20 || r == 0x7f) {
+			if c := fmt.Sprintf("%q", r); !inList(c, controlChars, false) {
+				controlChars = append(controlChars, c)
+			}
+		}
+	}
+
+	for _, r := range lintPayloadDelimiterReplacer.Replace(text) {
+		if strings.ContainsRune(lintIllegalMacroOutputChars, r) {
+			if c := string(r); !inList(c, macroChars, false) {
+				macroChars = append(macroChars, c)
+			}
+		}
+	}
+
+	if len(controlChars) > 0 {
+		issues = append(issues, LintIssue{
+			Rule:     LintRuleIllegalChars,
+			Severity: LintSeverityError,
+			Line:     lineNum,
+			Message:  fmt.Sprintf("line contains control characters: %s", strings.Join(controlChars, " ")),
+		})
+	}
+
+	if len(macroChars) > 0 {
+		issues = append(issues, LintIssue{
+			Rule:     LintRuleIllegalChars,
+			Severity: LintSeverityWarning,
+			Line:     lineNum,
+			Message: fmt.Sprintf(
+				"line contains characters stripped from notification macros by default: %s",
+				strings.Join(macroChars, " "),
+			),
+		})
+	}
+
+	return issues
+}
+
+// lintPerfData checks the syntax of performance data found in the given
+// lines of plugin output. Performance data follows the first pipe character
+// of the first line and, for later lines, the first pipe character found
+// through the end of the output.
+func lintPerfData(lines []string) []LintIssue {
+	var issues []LintIssue
+
+	check := func(lineNum int, perfData string) {
+		for _, metric := range splitPerfDataMetrics(strings.TrimRight(perfData, "\r")) {
+			pd, err := parsePerfData(metric)
+			if err == nil {
+				err = pd.Validate()
+			}
+
+			if err != nil {
+				issues = append(issues, LintIssue{
+					Rule:     LintRulePerfData,
+					Severity: LintSeverityError,
+					Line:     lineNum,
+					Message:  fmt.Sprintf("invalid performance data metric %q: %v", metric, err),
+				})
+			}
+		}
+	}
+
+	if _, perfData, found := strings.Cut(lines[0], "|"); found {
+		check(1, perfData)
+	}
+
+	if start := lintPerfDataStart(lines); start > 0 {
+		_, perfData, _ := strings.Cut(lines[start], "|")
+		check(start+1, perfData)
+
+		for i := start + 1; i < len(lines); i++ {
+			check(i+1, lines[i])
+		}
+	}
+
+	return issues
+}
+
+// lintPerfDataStart returns the (0-based) index of the line after the first
+// line which contains the first pipe character, starting additional
+// performance data, or zero if not found.
+func lintPerfDataStart(lines []string) int {
+	for i := 1; i < len(lines); i++ {
+		if strings.Contains(lines[i], "|") {
+			return i
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintOutput_LibraryOutputHasNoIssues(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "WARNING: disk usage high"
+	plugin.LongServiceOutput = "used: 91%"
+	plugin.AddError(nagios.ErrInvalidRangeThreshold)
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{
+		Label:             "used space",
+		Value:             "91",
+		UnitOfMeasurement: "%",
+		Warn:              "80",
+		Crit:              "95",
+	}); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	if issues := nagios.LintOutput(outputBuffer.String()); len(issues) != 0 {
+		t.Fatalf("ERROR: unexpected lint issues for output:\n%s\n%v", outputBuffer.String(), issues)
+	}
+
+	t.Log("OK: no lint issues found for library output")
+}
+
+func TestLintOutput_ReportsIssues(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+		want   []nagios.LintIssue
+	}{
+		"empty output": {
+			output: " \n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRuleEmptyOutput,
+					Severity: nagios.LintSeverityError,
+					Message:  "plugin output is empty",
+				},
+			},
+		},
+		"missing state label": {
+			output: "disk usage high\n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRuleFirstLine,
+					Severity: nagios.LintSeverityWarning,
+					Line:     1,
+					Message:  "first line does not include a plugin state label (one of OK, WARNING, CRITICAL, UNKNOWN, DEPENDENT)",
+				},
+			},
+		},
+		"invalid perfdata on continuation line": {
+			output: "OK: fine\nlong output | a=1\nb=\n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRulePerfData,
+					Severity: nagios.LintSeverityError,
+					Line:     3,
+					Message:  `invalid performance data metric "b=": failed to extract label and raw value: metric value is not present in input string "b=": invalid performance data format`,
+				},
+			},
+		},
+		"crlf and macro characters": {
+			output: "OK: <b>fine</b>\r\nmore\n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRuleEOL,
+					Severity: nagios.LintSeverityWarning,
+					Line:     1,
+					Message:  `line ends with "\r\n"; use "\n" line endings`,
+				},
+				{
+					Rule:     nagios.LintRuleIllegalChars,
+					Severity: nagios.LintSeverityWarning,
+					Line:     1,
+					Message:  "line contains characters stripped from notification macros by default: < >",
+				},
+			},
+		},
+		"default payload delimiters": {
+			output: "OK: fine\n\n" + nagios.DefaultASCII85EncodingDelimiterLeft + "9jqo^" +
+				nagios.DefaultASCII85EncodingDelimiterRight + "\n",
+			want: nil,
+		},
+		"macro characters around payload delimiters": {
+			output: "OK: fine\n\n<" + nagios.DefaultASCII85EncodingDelimiterLeft + "9jqo^" +
+				nagios.DefaultASCII85EncodingDelimiterRight + ">\n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRuleIllegalChars,
+					Severity: nagios.LintSeverityWarning,
+					Line:     3,
+					Message:  "line contains characters stripped from notification macros by default: < >",
+				},
+			},
+		},
+		"control characters": {
+			output: "OK: fine\x00\n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRuleIllegalChars,
+					Severity: nagios.LintSeverityError,
+					Line:     1,
+					Message:  `line contains control characters: '\x00'`,
+				},
+			},
+		},
+		"oversized output": {
+			output: "OK: fine\n" + strings.Repeat("x", 9000) + "\n",
+			want: []nagios.LintIssue{
+				{
+					Rule:     nagios.LintRuleSize,
+					Severity: nagios.LintSeverityError,
+					Message:  "output is 9010 bytes; Nagios Core truncates output beyond 8192 bytes",
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := nagios.LintOutput(tt.output)
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("(-want, +got)\n:%s", d)
+			}
+		})
+	}
+}
+
+func TestLintIssue_String(t *testing.T) {
+	t.Parallel()
+
+	issue := nagios.LintIssue{
+		Rule:     nagios.LintRulePerfData,
+		Severity: nagios.LintSeverityError,
+		Line:     3,
+		Message:  "invalid metric",
+	}
+
+	want := "line 3: error: perfdata-syntax: invalid metric"
+	if got := issue.String(); got != want {
+		t.Errorf("ERROR: want %q, got %q", want, got)
+	}
+}
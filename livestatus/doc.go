@@ -0,0 +1,37 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package livestatus provides a minimal MK Livestatus client for retrieving
+stored plugin output.
+
+# OVERVIEW
+
+Livestatus exposes monitoring system state via the Livestatus Query
+Language (LQL) over a unix socket or TCP connection. This package queries
+the services table for the plugin_output and long_plugin_output columns of
+a single service and (optionally) extracts & decodes an encoded payload
+using nagios.ExtractAndDecodePayload.
+
+This is intended for on-box tooling which prefers Livestatus over the
+Nagios XI API (see the nagiosxi package).
+
+NOTE: Depending on the monitoring system, newlines within long plugin
+output may be returned in escaped form (a literal backslash followed by the
+letter n). Encoded payloads are matched using delimiters and are not
+affected.
+
+# HOW TO USE
+
+	client := livestatus.NewClient("unix", "/usr/local/nagios/var/rw/live")
+
+	payload, err := client.ServicePayload(ctx, "web01", "HTTP")
+	if err != nil {
+		// handle error
+	}
+*/
+package livestatus
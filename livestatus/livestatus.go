@@ -0,0 +1,250 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package livestatus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// responseHeaderSize is the size of the fixed16 response header.
+	responseHeaderSize int = 16
+
+	// statusOK is the Livestatus response status code for successful
+	// queries.
+	statusOK int = 200
+
+	// defaultTimeout is used for connecting to and communicating with
+	// Livestatus if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+
+	// maxResponseBodySize is the maximum accepted response body size.
+	maxResponseBodySize int = 10 * 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the Livestatus address was not
+	// provided.
+	ErrMissingAddress = errors.New("Livestatus address not specified")
+
+	// ErrInvalidFilterValue indicates that a query filter value contains
+	// characters which cannot be used in an LQL query.
+	ErrInvalidFilterValue = errors.New("invalid filter value")
+
+	// ErrUnexpectedResponse indicates that Livestatus returned a response
+	// which could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected Livestatus response")
+
+	// ErrQueryFailed indicates that Livestatus rejected the query.
+	ErrQueryFailed = errors.New("Livestatus query failed")
+
+	// ErrServiceNotFound indicates that no status details were found for
+	// the requested service.
+	ErrServiceNotFound = errors.New("service not found")
+)
+
+// Client retrieves stored plugin output from Livestatus.
+type Client struct {
+	// network is the network type ("unix" or "tcp").
+	network string
+
+	// address is the unix socket path or TCP host:port address.
+	address string
+
+	// timeout limits the time spent connecting to and communicating with
+	// Livestatus.
+	timeout time.Duration
+
+	// leftDelimiter is the left delimiter used to extract encoded payloads.
+	leftDelimiter string
+
+	// rightDelimiter is the right delimiter used to extract encoded
+	// payloads.
+	rightDelimiter string
+
+	// dialer is used to establish connections to Livestatus.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given network ("unix" or "tcp")
+// and address. The default payload delimiters provided by the nagios package
+// are used to extract encoded payloads.
+func NewClient(network string, address string) *Client {
+	return &Client{
+		network:        network,
+		address:        address,
+		timeout:        defaultTimeout,
+		leftDelimiter:  nagios.DefaultASCII85EncodingDelimiterLeft,
+		rightDelimiter: nagios.DefaultASCII85EncodingDelimiterRight,
+	}
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with Livestatus. Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// SetPayloadDelimiters overrides the default delimiters used to extract
+// encoded payloads from retrieved plugin output.
+func (c *Client) SetPayloadDelimiters(left string, right string) {
+	c.leftDelimiter = left
+	c.rightDelimiter = right
+}
+
+// ServiceOutput retrieves the most recent plugin output for the given host
+// and service. The returned value is the one-line summary followed by the
+// long output (if any) separated by a newline.
+func (c *Client) ServiceOutput(ctx context.Context, host string, service string) (string, error) {
+	for _, value := range []string{host, service} {
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("%q: %w", value, ErrInvalidFilterValue)
+		}
+	}
+
+	query := "GET services\n" +
+		"Columns: plugin_output long_plugin_output\n" +
+		"Filter: host_name = " + host + "\n" +
+		"Filter: description = " + service + "\n" +
+		"OutputFormat: json\n" +
+		"ResponseHeader: fixed16\n" +
+		"\n"
+
+	body, err := c.query(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	var rows [][]string
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return "", fmt.Errorf("failed to decode JSON response: %v: %w", err, ErrUnexpectedResponse)
+	}
+
+	if len(rows) == 0 {
+		return "", fmt.Errorf("host %q, service %q: %w", host, service, ErrServiceNotFound)
+	}
+
+	if len(rows[0]) != 2 {
+		return "", fmt.Errorf("want 2 columns, got %d: %w", len(rows[0]), ErrUnexpectedResponse)
+	}
+
+	output, longOutput := rows[0][0], rows[0][1]
+	if longOutput == "" {
+		return output, nil
+	}
+
+	return output + nagios.CheckOutputEOL + longOutput, nil
+}
+
+// ServicePayload retrieves the most recent plugin output for the given host
+// and service and returns the extracted and decoded encoded payload.
+func (c *Client) ServicePayload(ctx context.Context, host string, service string) (string, error) {
+	output, err := c.ServiceOutput(ctx, host, service)
+	if err != nil {
+		return "", err
+	}
+
+	return nagios.ExtractAndDecodePayload(output, "", c.leftDelimiter, c.rightDelimiter)
+}
+
+// query sends the given LQL query and returns the response body. The query
+// must request the fixed16 response header.
+func (c *Client) query(ctx context.Context, query string) ([]byte, error) {
+	if c.address == "" {
+		return nil, ErrMissingAddress
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, c.network, c.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Livestatus: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("failed to set Livestatus connection deadline: %w", err)
+	}
+
+	if _, err := io.WriteString(conn, query); err != nil {
+		return nil, fmt.Errorf("failed to send Livestatus query: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+
+	header := make([]byte, responseHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read Livestatus response header: %w", err)
+	}
+
+	status, length, err := parseResponseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("failed to read Livestatus response body: %w", err)
+	}
+
+	if status != statusOK {
+		return nil, fmt.Errorf(
+			"status %d (%s): %w",
+			status,
+			strings.TrimSpace(string(body)),
+			ErrQueryFailed,
+		)
+	}
+
+	return body, nil
+}
+
+// parseResponseHeader parses the fixed16 response header consisting of a
+// three digit status code, a space, the body length padded to 11 characters
+// and a newline.
+func parseResponseHeader(header []byte) (int, int, error) {
+	if len(header) != responseHeaderSize || header[responseHeaderSize-1] != '\n' {
+		return 0, 0, fmt.Errorf("invalid response header %q: %w", header, ErrUnexpectedResponse)
+	}
+
+	status, err := strconv.Atoi(string(header[0:3]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid status in response header %q: %w", header, ErrUnexpectedResponse)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(string(header[4 : responseHeaderSize-1])))
+	if err != nil || length < 0 || length > maxResponseBodySize {
+		return 0, 0, fmt.Errorf("invalid length in response header %q: %w", header, ErrUnexpectedResponse)
+	}
+
+	return status, length, nil
+}
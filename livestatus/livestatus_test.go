@@ -0,0 +1,125 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package livestatus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// fakeLivestatus serves a single query on a unix socket, responding with the
+// given status and body. The received query is sent on the returned channel.
+func fakeLivestatus(t *testing.T, status int, body string) (string, <-chan string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "live")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unable to listen on unix socket: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	queries := make(chan string, 1)
+
+	go func() {
+		defer close(queries)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		var query strings.Builder
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if line == "\n" {
+				break
+			}
+			query.WriteString(line)
+		}
+		queries <- query.String()
+
+		_, _ = fmt.Fprintf(conn, "%3d %11d\n%s", status, len(body), body)
+	}()
+
+	return path, queries
+}
+
+func TestClient_ServicePayload_ReturnsDecodedPayload(t *testing.T) {
+	t.Parallel()
+
+	want := `{"Age":17}`
+	encoded := nagios.EncodePayload(
+		[]byte(want),
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+
+	body, err := json.Marshal([][]string{{"OK: all good", "details\n" + encoded}})
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode response: %v", err)
+	}
+
+	path, queries := fakeLivestatus(t, statusOK, string(body))
+
+	got, err := NewClient("unix", path).ServicePayload(context.Background(), "web01", "HTTP")
+	if err != nil {
+		t.Fatalf("ERROR: unexpected failure: %v", err)
+	}
+
+	if got != want {
+		t.Fatalf("ERROR: want payload %q, got %q", want, got)
+	}
+
+	query := <-queries
+	for _, line := range []string{"GET services", "Filter: host_name = web01", "Filter: description = HTTP"} {
+		if !strings.Contains(query, line+"\n") {
+			t.Errorf("ERROR: query missing %q:\n%s", line, query)
+		}
+	}
+
+	t.Log("OK: payload retrieved and decoded as expected")
+}
+
+func TestClient_ServiceOutput_ReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	path, _ := fakeLivestatus(t, 400, "Invalid GET request, no such table 'servicez'\n")
+
+	_, err := NewClient("unix", path).ServiceOutput(context.Background(), "web01", "HTTP")
+	if !errors.Is(err, ErrQueryFailed) {
+		t.Errorf("ERROR: want %v, got %v", ErrQueryFailed, err)
+	}
+
+	path, _ = fakeLivestatus(t, statusOK, "[]")
+
+	_, err = NewClient("unix", path).ServiceOutput(context.Background(), "web01", "HTTP")
+	if !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("ERROR: want %v, got %v", ErrServiceNotFound, err)
+	}
+
+	_, err = NewClient("unix", path).ServiceOutput(context.Background(), "web01\nGET hosts", "HTTP")
+	if !errors.Is(err, ErrInvalidFilterValue) {
+		t.Errorf("ERROR: want %v, got %v", ErrInvalidFilterValue, err)
+	}
+}
@@ -8,10 +8,12 @@
 package nagios
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"strings"
+	"time"
 )
 
 // Logger related values set as constants so that their values are exposed to
@@ -26,6 +28,150 @@ const (
 	// logFlags     int    = log.Ldate | log.Ltime | log.Lshortfile
 )
 
+// Debug log entry categories. These match the debug logging options used to
+// filter entries.
+const (
+	logCategoryGeneral          string = "general"
+	logCategoryActions          string = "actions"
+	logCategoryPluginOutputSize string = "plugin_output_size"
+	logCategoryExitDiagnostics  string = "exit_diagnostics"
+	logCategoryPhaseTiming      string = "phase_timing"
+	logCategoryThresholds       string = "thresholds"
+	logCategoryPayload          string = "payload"
+)
+
+// Debug log entry attribute keys.
+const (
+	// logAttrCategory is the key of the entry category attribute.
+	logAttrCategory string = "category"
+
+	// logAttrSection is the key of the plugin output section attribute.
+	logAttrSection string = "section"
+
+	// logAttrBytes is the key of the byte count attribute.
+	logAttrBytes string = "bytes"
+
+	// logAttrPhase is the key of the plugin output phase attribute.
+	logAttrPhase string = "phase"
+
+	// logAttrDurationMS is the key of the (fractional) duration in
+	// milliseconds attribute.
+	logAttrDurationMS string = "duration_ms"
+
+	// logAttrLabel is the key of the performance data label attribute.
+	logAttrLabel string = "label"
+
+	// logAttrValue is the key of the performance data value attribute.
+	logAttrValue string = "value"
+
+	// logAttrThreshold is the key of the threshold type (e.g., warning,
+	// critical) attribute.
+	logAttrThreshold string = "threshold"
+
+	// logAttrRange is the key of the threshold range string attribute.
+	logAttrRange string = "range"
+
+	// logAttrAlert is the key of the threshold evaluation decision
+	// attribute.
+	logAttrAlert string = "alert"
+
+	// logAttrCodec is the key of the payload codec attribute.
+	logAttrCodec string = "codec"
+
+	// logAttrLeftDelimiter is the key of the payload left delimiter
+	// attribute.
+	logAttrLeftDelimiter string = "left_delimiter"
+
+	// logAttrRightDelimiter is the key of the payload right delimiter
+	// attribute.
+	logAttrRightDelimiter string = "right_delimiter"
+
+	// logAttrChecksum is the key of the payload checksum attribute.
+	logAttrChecksum string = "checksum"
+
+	// logAttrRegex is the key of the payload extraction regex attribute.
+	logAttrRegex string = "regex"
+
+	// logAttrMatches is the key of the payload extraction match count
+	// attribute.
+	logAttrMatches string = "matches"
+)
+
+// DebugLogLevel is the severity of a debug log entry. Lower values are more
+// severe.
+type DebugLogLevel int
+
+// Supported debug log levels. Each level includes entries of all more severe
+// levels.
+const (
+	// DebugLogLevelError includes only failures which likely affect plugin
+	// results (e.g., failing to write plugin output).
+	DebugLogLevelError DebugLogLevel = iota + 1
+
+	// DebugLogLevelWarn includes recoverable problems (e.g., invalid
+	// settings replaced by defaults, omitted metrics).
+	DebugLogLevelWarn
+
+	// DebugLogLevelInfo includes summary details (e.g., exit diagnostics).
+	DebugLogLevelInfo
+
+	// DebugLogLevelDebug includes actions taken by this library.
+	DebugLogLevelDebug
+
+	// DebugLogLevelTrace includes all entries, including plugin output size
+	// measurements.
+	DebugLogLevelTrace
+)
+
+// String returns the name of the debug log level.
+func (l DebugLogLevel) String() string {
+	switch l {
+	case DebugLogLevelError:
+		return "error"
+	case DebugLogLevelWarn:
+		return "warn"
+	case DebugLogLevelInfo:
+		return "info"
+	case DebugLogLevelDebug:
+		return "debug"
+	case DebugLogLevelTrace:
+		return "trace"
+	default:
+		return "unknown"
+	}
+}
+
+// logAttr is a key/value pair providing structured details for a debug log
+// entry. Attributes are not included in plain text debug log output.
+type logAttr struct {
+	Key   string
+	Value interface{}
+}
+
+// debugLogEntry is a single debug log entry.
+type debugLogEntry struct {
+	// time is when the entry was recorded.
+	time time.Time
+
+	// level is the severity of the entry.
+	level DebugLogLevel
+
+	// category is the debug logging category of the entry.
+	category string
+
+	// msg is the log message without trailing newlines.
+	msg string
+
+	// attrs is the collection of structured details for the entry.
+	attrs []logAttr
+}
+
+// debugLogHandler receives debug log entries in place of the default plain
+// text logger.
+type debugLogHandler interface {
+	handle(entry debugLogEntry)
+}
+
 // debugLoggingOptions controls all debug logging behavior for this library.
 type debugLoggingOptions struct {
 	// actions indicates whether actions taken by this library are logged.
@@ -36,6 +182,25 @@ type debugLoggingOptions struct {
 	// pluginOutputSize indicates whether all output to the configured plugin
 	// output sink should be measured and written to the log output sink.
 	pluginOutputSize bool
+
+	// exitDiagnostics indicates whether diagnostic details (e.g., still
+	// running goroutines, registered cleanup functions which never ran) are
+	// logged just before the plugin exits.
+	exitDiagnostics bool
+
+	// phaseTiming indicates whether the time spent in each plugin output
+	// processing phase (e.g., rendering each output section, writing the
+	// final output) is logged.
+	phaseTiming bool
+
+	// thresholdEvaluation indicates whether the parsed ranges, values and
+	// resulting decisions of threshold evaluation are logged.
+	thresholdEvaluation bool
+
+	// payload indicates whether payload encoding and decoding details (e.g.,
+	// buffer sizes, codec, delimiters, checksums, extraction matches) are
+	// logged.
+	payload bool
 }
 
 // defaultPluginDebugLoggingOutputTarget returns the default debug logging
@@ -62,8 +227,12 @@ func defaultPluginDebugLoggerTarget() io.Writer {
 // debugLoggingOptions value with all settings enabled.
 func allDebugLoggingOptionsEnabled() debugLoggingOptions {
 	return debugLoggingOptions{
-		actions:          true,
-		pluginOutputSize: true,
+		actions:             true,
+		pluginOutputSize:    true,
+		exitDiagnostics:     true,
+		phaseTiming:         true,
+		thresholdEvaluation: true,
+		payload:             true,
 		// Expand this for any new fields added in the future.
 	}
 }
@@ -72,8 +241,12 @@ func allDebugLoggingOptionsEnabled() debugLoggingOptions {
 // debugLoggingOptions value with all settings disabled.
 func allDebugLoggingOptionsDisabled() debugLoggingOptions {
 	return debugLoggingOptions{
-		actions:          false,
-		pluginOutputSize: false,
+		actions:             false,
+		pluginOutputSize:    false,
+		exitDiagnostics:     false,
+		phaseTiming:         false,
+		thresholdEvaluation: false,
+		payload:             false,
 		// Expand this for any new fields added in the future.
 	}
 }
@@ -110,6 +283,47 @@ func (dlo *debugLoggingOptions) disablePluginOutputSize() {
 	dlo.pluginOutputSize = false
 }
 
+// enableExitDiagnostics enables logging exit diagnostics.
+func (dlo *debugLoggingOptions) enableExitDiagnostics() {
+	dlo.exitDiagnostics = true
+}
+
+// disableExitDiagnostics disables logging exit diagnostics.
+func (dlo *debugLoggingOptions) disableExitDiagnostics() {
+	dlo.exitDiagnostics = false
+}
+
+// enablePhaseTiming enables logging plugin output phase timing.
+func (dlo *debugLoggingOptions) enablePhaseTiming() {
+	dlo.phaseTiming = true
+}
+
+// disablePhaseTiming disables logging plugin output phase timing.
+func (dlo *debugLoggingOptions) disablePhaseTiming() {
+	dlo.phaseTiming = false
+}
+
+// enableThresholdEvaluation enables logging threshold evaluation decisions.
+func (dlo *debugLoggingOptions) enableThresholdEvaluation() {
+	dlo.thresholdEvaluation = true
+}
+
+// disableThresholdEvaluation disables logging threshold evaluation
+// decisions.
+func (dlo *debugLoggingOptions) disableThresholdEvaluation() {
+	dlo.thresholdEvaluation = false
+}
+
+// enablePayload enables logging payload encoding and decoding details.
+func (dlo *debugLoggingOptions) enablePayload() {
+	dlo.payload = true
+}
+
+// disablePayload disables logging payload encoding and decoding details.
+func (dlo *debugLoggingOptions) disablePayload() {
+	dlo.payload = false
+}
+
 // DebugLoggingEnableAll changes the default state of all debug logging
 // options for this library from disabled to enabled.
 //
@@ -189,6 +403,135 @@ func (p *Plugin) DebugLoggingEnablePluginOutputSize() {
 	p.setupLogger()
 }
 
+// DebugLoggingDisableExitDiagnostics disables debug logging of exit
+// diagnostics.
+func (p *Plugin) DebugLoggingDisableExitDiagnostics() {
+	p.debugLogging.disableExitDiagnostics()
+}
+
+// DebugLoggingEnableExitDiagnostics enables debug logging of exit
+// diagnostics. Just before the plugin exits the number of still running
+// goroutines and any registered cleanup functions which never ran are
+// logged. This is intended to help find leaks otherwise masked by the
+// immediate os.Exit call.
+//
+// Once enabled, debug logging output is emitted to os.Stderr. This can be
+// overridden by explicitly setting a custom debug output target.
+func (p *Plugin) DebugLoggingEnableExitDiagnostics() {
+	p.debugLogging.enableExitDiagnostics()
+
+	// Ensure we have a valid output target, but do not overwrite any custom
+	// target already set.
+	if p.logOutputSink == nil {
+		p.setFallbackDebugLogTarget()
+	}
+
+	// Connect logger to configured debug log target.
+	p.setupLogger()
+}
+
+// DebugLoggingDisablePhaseTiming disables debug logging of plugin output
+// phase timing.
+func (p *Plugin) DebugLoggingDisablePhaseTiming() {
+	p.debugLogging.disablePhaseTiming()
+}
+
+// DebugLoggingEnablePhaseTiming enables debug logging of the time spent in
+// each plugin output processing phase: rendering each output section
+// (ServiceOutput, Errors, Thresholds, LongServiceOutput, EncodedPayload,
+// BrandingCallback, PerformanceData), rendering the complete output, writing
+// the final output and running emit hooks. This is intended to help
+// diagnose plugins which spend a surprising amount of time emitting output.
+//
+// Once enabled, debug logging output is emitted to os.Stderr. This can be
+// overridden by explicitly setting a custom debug output target.
+func (p *Plugin) DebugLoggingEnablePhaseTiming() {
+	p.debugLogging.enablePhaseTiming()
+
+	// Ensure we have a valid output target, but do not overwrite any custom
+	// target already set.
+	if p.logOutputSink == nil {
+		p.setFallbackDebugLogTarget()
+	}
+
+	// Connect logger to configured debug log target.
+	p.setupLogger()
+}
+
+// DebugLoggingDisableThresholdEvaluation disables debug logging of threshold
+// evaluation decisions.
+func (p *Plugin) DebugLoggingDisableThresholdEvaluation() {
+	p.debugLogging.disableThresholdEvaluation()
+}
+
+// DebugLoggingEnableThresholdEvaluation enables debug logging of threshold
+// evaluation decisions. For each performance data metric evaluated by
+// EvaluateThreshold the value, the parsed warning and critical ranges and
+// the resulting decision are logged. This is intended to help explain a
+// surprising WARNING or CRITICAL state.
+//
+// Once enabled, debug logging output is emitted to os.Stderr. This can be
+// overridden by explicitly setting a custom debug output target.
+func (p *Plugin) DebugLoggingEnableThresholdEvaluation() {
+	p.debugLogging.enableThresholdEvaluation()
+
+	// Ensure we have a valid output target, but do not overwrite any custom
+	// target already set.
+	if p.logOutputSink == nil {
+		p.setFallbackDebugLogTarget()
+	}
+
+	// Connect logger to configured debug log target.
+	p.setupLogger()
+}
+
+// DebugLoggingDisablePayload disables debug logging of payload encoding and
+// decoding details.
+func (p *Plugin) DebugLoggingDisablePayload() {
+	p.debugLogging.disablePayload()
+}
+
+// DebugLoggingEnablePayload enables debug logging of payload encoding and
+// decoding details: payload buffer sizes, the chosen codec, delimiters,
+// checksum results and extraction regex matches. Decoding details are
+// logged when using the ExtractAndDecodePayload method.
+//
+// Once enabled, debug logging output is emitted to os.Stderr. This can be
+// overridden by explicitly setting a custom debug output target.
+func (p *Plugin) DebugLoggingEnablePayload() {
+	p.debugLogging.enablePayload()
+
+	// Ensure we have a valid output target, but do not overwrite any custom
+	// target already set.
+	if p.logOutputSink == nil {
+		p.setFallbackDebugLogTarget()
+	}
+
+	// Connect logger to configured debug log target.
+	p.setupLogger()
+}
+
+// SetDebugLogLevel enables all debug logging options and limits debug log
+// entries to the given level and more severe levels. For example, using
+// DebugLogLevelWarn emits only warnings and errors, providing actionable
+// messages in production without the full volume of output enabled by
+// DebugLoggingEnableAll. Select debug logging options may be disabled
+// afterwards as usual.
+//
+// Once enabled, debug logging output is emitted to os.Stderr. This can be
+// overridden by explicitly setting a custom debug output target.
+//
+// Invalid levels are ignored.
+func (p *Plugin) SetDebugLogLevel(level DebugLogLevel) {
+	if level < DebugLogLevelError || level > DebugLogLevelTrace {
+		return
+	}
+
+	p.debugLogLevel = level
+
+	p.DebugLoggingEnableAll()
+}
+
 // SetDebugLoggingOutputTarget overrides the current debug logging target with
 // the given output target. If the given output target is not valid the
 // current target will be used instead. If there isn't a debug logging target
@@ -257,8 +600,55 @@ func (p *Plugin) setupLogger() {
 }
 
 // log uses the plugin's logger to write the given message to the configured
-// output sink.
-func (p *Plugin) log(msg string) {
+// output sink. Messages are logged using the warning level.
+func (p *Plugin) log(msg string, attrs ...logAttr) {
+	p.logEntry(DebugLogLevelWarn, logCategoryGeneral, msg, attrs...)
+}
+
+// logEntry writes the given message and attributes for the given level and
+// category to the configured debug log handler (if set) or the plugin's
+// plain text logger. Entries less severe than the configured debug log level
+// (if set) are skipped. The plain text logger does not include the level,
+// category or attributes.
+func (p *Plugin) logEntry(level DebugLogLevel, category string, msg string, attrs ...logAttr) {
+	if p.debugLogLevel != 0 && level > p.debugLogLevel {
+		return
+	}
+
+	p.writeLogEntry(level, category, msg, attrs...)
+}
+
+// writeLogEntry writes the given message and attributes to the configured
+// debug log handler (if set) or the plugin's plain text logger without
+// applying debug log level filtering. The debug log filter (if set) is
+// applied.
+func (p *Plugin) writeLogEntry(level DebugLogLevel, category string, msg string, attrs ...logAttr) {
+	if p.debugLogFilter != nil {
+		msg, attrs = p.filterLogEntry(msg, attrs)
+	}
+
+	if p.correlationID != "" {
+		attrs = append(
+			[]logAttr{{Key: logAttrCorrelationID, Value: p.correlationID}},
+			attrs...,
+		)
+	}
+
+	now := time.Now()
+	p.captureLogEntry(now, level, category, msg)
+
+	if p.logHandler != nil {
+		p.logHandler.handle(debugLogEntry{
+			time:     now,
+			level:    level,
+			category: category,
+			msg:      strings.TrimRight(msg, " \r\n"),
+			attrs:    attrs,
+		})
+
+		return
+	}
+
 	if p.logger == nil {
 		return
 	}
@@ -267,25 +657,140 @@ func (p *Plugin) log(msg string) {
 		msg += CheckOutputEOL
 	}
 
+	if p.correlationID != "" {
+		msg = "[" + p.correlationID + "] " + msg
+	}
+
 	p.logger.Print(msg)
 }
 
 // logAction is used to log actions taken by this library such as
 // enabling/disabling settings or other general plugin activity.
-func (p *Plugin) logAction(msg string) {
+func (p *Plugin) logAction(msg string, attrs ...logAttr) {
 	if !p.debugLogging.actions {
 		return
 	}
 
-	p.log(msg)
+	p.logEntry(DebugLogLevelDebug, logCategoryActions, msg, attrs...)
+}
+
+// logActionLevel is used to log actions taken by this library using the
+// given level (e.g., recovered failures logged as warnings).
+func (p *Plugin) logActionLevel(level DebugLogLevel, msg string, attrs ...logAttr) {
+	if !p.debugLogging.actions {
+		return
+	}
+
+	p.logEntry(level, logCategoryActions, msg, attrs...)
 }
 
 // logPluginOutputSize is used to log activity related to measuring all output
 // to the configured plugin output sink.
-func (p *Plugin) logPluginOutputSize(msg string) {
+func (p *Plugin) logPluginOutputSize(msg string, attrs ...logAttr) {
+	if !p.debugLogging.pluginOutputSize {
+		return
+	}
+
+	p.logEntry(DebugLogLevelTrace, logCategoryPluginOutputSize, msg, attrs...)
+}
+
+// logSectionAction is used to log that the given plugin output section is
+// being processed. The log entry attributes are only assembled if actions
+// debug logging is enabled.
+func (p *Plugin) logSectionAction(msg string, section string) {
+	if !p.debugLogging.actions {
+		return
+	}
+
+	p.logEntry(DebugLogLevelDebug, logCategoryActions, msg, logAttr{Key: logAttrSection, Value: section})
+}
+
+// logSectionSkipped is used to log that the given plugin output section is
+// omitted for the given reason. The message is only assembled if actions
+// debug logging is enabled.
+func (p *Plugin) logSectionSkipped(section string, reason string) {
+	if !p.debugLogging.actions {
+		return
+	}
+
+	p.logEntry(
+		DebugLogLevelDebug,
+		logCategoryActions,
+		"Skipping processing of "+section+" section; "+reason,
+		logAttr{Key: logAttrSection, Value: section},
+	)
+}
+
+// logSectionOutputSize is used to log the number of bytes written for the
+// given plugin output section. The message is only formatted (using the
+// given format and byte count) if plugin output size debug logging is
+// enabled.
+func (p *Plugin) logSectionOutputSize(section string, format string, written int) {
 	if !p.debugLogging.pluginOutputSize {
 		return
 	}
 
-	p.log(msg)
+	p.logPluginOutputSize(
+		fmt.Sprintf(format, written),
+		logAttr{Key: logAttrSection, Value: section},
+		logAttr{Key: logAttrBytes, Value: written},
+	)
+}
+
+// logExitDiagnostics is used to log diagnostic details gathered just before
+// the plugin exits.
+func (p *Plugin) logExitDiagnostics(msg string, attrs ...logAttr) {
+	if !p.debugLogging.exitDiagnostics {
+		return
+	}
+
+	p.logEntry(DebugLogLevelInfo, logCategoryExitDiagnostics, msg, attrs...)
+}
+
+// logPhaseTiming is used to log the time spent in plugin output processing
+// phases.
+func (p *Plugin) logPhaseTiming(msg string, attrs ...logAttr) {
+	if !p.debugLogging.phaseTiming {
+		return
+	}
+
+	p.logEntry(DebugLogLevelDebug, logCategoryPhaseTiming, msg, attrs...)
+}
+
+// logThresholdEvaluation is used to log threshold evaluation decisions.
+func (p *Plugin) logThresholdEvaluation(msg string, attrs ...logAttr) {
+	if !p.debugLogging.thresholdEvaluation {
+		return
+	}
+
+	p.logEntry(DebugLogLevelDebug, logCategoryThresholds, msg, attrs...)
+}
+
+// logPayload is used to log payload encoding and decoding details.
+func (p *Plugin) logPayload(msg string, attrs ...logAttr) {
+	if !p.debugLogging.payload {
+		return
+	}
+
+	p.logEntry(DebugLogLevelDebug, logCategoryPayload, msg, attrs...)
+}
+
+// startPhase returns a function which logs the time elapsed since startPhase
+// was called for the given plugin output processing phase.
+func (p *Plugin) startPhase(phase string) func() {
+	if !p.debugLogging.phaseTiming {
+		return func() {}
+	}
+
+	start := time.Now()
+
+	return func() {
+		elapsed := time.Since(start)
+
+		p.logPhaseTiming(
+			fmt.Sprintf("Phase %s completed in %s", phase, elapsed),
+			logAttr{Key: logAttrPhase, Value: phase},
+			logAttr{Key: logAttrDurationMS, Value: float64(elapsed) / float64(time.Millisecond)},
+		)
+	}
 }
@@ -0,0 +1,151 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugLogCaptureHeader is the header written before captured debug log
+// entries appended to the encoded payload.
+const debugLogCaptureHeader string = "--- go-nagios debug log (last %d entries) ---"
+
+// debugLogRing is a fixed size ring buffer of recent debug log entries.
+type debugLogRing struct {
+	// mu guards the entries; debug log entries may be recorded by the
+	// plugin timeout handling concurrently with client code.
+	mu sync.Mutex
+
+	// entries holds the captured (formatted) debug log entries.
+	entries []string
+
+	// next is the index of the slot used for the next entry.
+	next int
+
+	// full indicates that the buffer has wrapped around.
+	full bool
+}
+
+// newDebugLogRing returns a ring buffer holding up to the given number of
+// entries.
+func newDebugLogRing(size int) *debugLogRing {
+	return &debugLogRing{entries: make([]string, size)}
+}
+
+// add records the given entry, replacing the oldest entry if full.
+func (r *debugLogRing) add(entry string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// lines returns the captured entries from oldest to newest.
+func (r *debugLogRing) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string(nil), r.entries[:r.next]...)
+	}
+
+	lines := make([]string, 0, len(r.entries))
+	lines = append(lines, r.entries[r.next:]...)
+	lines = append(lines, r.entries[:r.next]...)
+
+	return lines
+}
+
+// EnableDebugLogCapture enables capturing up to the given number of recent
+// debug log entries in memory. If the plugin exits with a non-OK state the
+// captured entries are appended to the encoded payload (following a header
+// line), giving responders the last log entries of the failing run without
+// enabling persistent debug logging. A non-positive value disables capture.
+//
+// Entries are captured for the enabled debug logging options and debug log
+// level only, after any debug log filter is applied. If no debug logging
+// options are enabled, all options are enabled. Debug log entries are not
+// written anywhere other than the capture buffer unless a debug logging
+// output target is also set.
+//
+// Because the captured entries are appended to the encoded payload, client
+// code which decodes structured payloads (e.g., JSON) should only enable
+// capture if the appended text can be handled.
+func (p *Plugin) EnableDebugLogCapture(maxEntries int) {
+	if maxEntries <= 0 {
+		p.logAction("Disabling debug log capture")
+		p.debugLogCapture = nil
+
+		return
+	}
+
+	if p.debugLogging == allDebugLoggingOptionsDisabled() {
+		p.debugLogging.enableAll()
+	}
+
+	p.debugLogCapture = newDebugLogRing(maxEntries)
+
+	p.logAction(fmt.Sprintf("Enabled capture of last %d debug log entries", maxEntries))
+}
+
+// captureLogEntry records the given debug log entry in the capture buffer
+// (if enabled).
+func (p *Plugin) captureLogEntry(t time.Time, level DebugLogLevel, category string, msg string) {
+	if p.debugLogCapture == nil {
+		return
+	}
+
+	p.debugLogCapture.add(fmt.Sprintf(
+		"%s %s %s: %s",
+		t.Format(time.RFC3339),
+		level,
+		category,
+		strings.TrimRight(msg, " \r\n"),
+	))
+}
+
+// appendDebugLogCapture appends the captured debug log entries to the encoded
+// payload if capture is enabled and the plugin state is non-OK. This is
+// called once the plugin state is final (see finalizeState) and state hooks
+// have been called.
+func (p *Plugin) appendDebugLogCapture() {
+	if p.debugLogCapture == nil || p.ExitStatusCode == StateOKExitCode {
+		return
+	}
+
+	lines := p.debugLogCapture.lines()
+	if len(lines) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+
+	if p.encodedPayloadBuffer.Len() > 0 {
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, debugLogCaptureHeader, len(lines))
+	sb.WriteString("\n")
+
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	if _, err := p.AddPayloadString(sb.String()); err != nil {
+		p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf("Omitting captured debug log entries from payload: %v", err))
+	}
+}
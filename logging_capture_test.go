@@ -0,0 +1,108 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_EnableDebugLogCapture_AppendsRecentEntriesOnNonOKState(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableDebugLogCapture(3)
+
+	if _, err := plugin.AddPayloadString("client payload"); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ServiceOutput = "CRITICAL: something broke"
+	plugin.ReturnCheckResults()
+
+	payload, err := nagios.ExtractAndDecodePayload(
+		outputBuffer.String(),
+		"",
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+	if err != nil {
+		t.Fatalf("ERROR: failed to extract payload: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(payload), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("ERROR: want 5 payload lines, got %d:\n%s", len(lines), payload)
+	}
+
+	if lines[0] != "client payload" {
+		t.Errorf("ERROR: client payload not retained: %q", lines[0])
+	}
+
+	if lines[1] != "--- go-nagios debug log (last 3 entries) ---" {
+		t.Errorf("ERROR: unexpected capture header: %q", lines[1])
+	}
+
+	for i, want := range []string{
+		"actions: Appending 14 bytes input to payload buffer",
+		"actions: Checking for unhandled panic",
+		"actions: No unhandled panic found",
+	} {
+		if !strings.HasSuffix(lines[i+2], want) {
+			t.Errorf("ERROR: want entry ending in %q, got %q", want, lines[i+2])
+		}
+	}
+
+	t.Log("OK: recent debug log entries appended to payload")
+}
+
+func TestPlugin_EnableDebugLogCapture_SkipsOKState(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableDebugLogCapture(10)
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	if strings.Contains(outputBuffer.String(), nagios.DefaultASCII85EncodingDelimiterLeft) {
+		t.Fatalf("ERROR: unexpected payload for OK state:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: debug log entries not appended for OK state")
+}
+
+func TestPlugin_EnableDebugLogCapture_UsesFinalState(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableDebugLogCapture(10)
+	plugin.EnableUnknownOnEmptyServiceOutput()
+	plugin.ReturnCheckResults()
+
+	// Only captured debug log entries are included in the payload.
+	if !strings.Contains(outputBuffer.String(), nagios.DefaultASCII85EncodingDelimiterLeft) {
+		t.Fatalf("ERROR: debug log entries not appended for UNKNOWN state:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: debug log entries appended for final plugin state")
+}
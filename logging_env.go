@@ -0,0 +1,189 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment variables used to enable debug logging for a deployed plugin
+// without a rebuild or command definition change. These are applied by
+// NewPlugin; settings made afterwards by client code take precedence.
+const (
+	// DebugEnvVar is a comma separated list of debug logging categories to
+	// enable: actions, plugin_output_size, exit_diagnostics, phase_timing,
+	// thresholds and payload. The values "all", "1" and "true" enable all
+	// categories.
+	DebugEnvVar string = "GO_NAGIOS_DEBUG"
+
+	// DebugFileEnvVar is the path of a file to which debug log entries are
+	// appended. If not set, debug log entries are written to os.Stderr.
+	DebugFileEnvVar string = "GO_NAGIOS_DEBUG_FILE"
+
+	// DebugLevelEnvVar is the debug log level (error, warn, info, debug or
+	// trace) used to limit debug log entries. If set without DebugEnvVar all
+	// categories are enabled.
+	DebugLevelEnvVar string = "GO_NAGIOS_DEBUG_LEVEL"
+
+	// DebugFormatEnvVar is the debug log format: text (the default) or
+	// json.
+	DebugFormatEnvVar string = "GO_NAGIOS_DEBUG_FORMAT"
+)
+
+// debugEnvFiles holds the files opened for DebugFileEnvVar keyed by path.
+// Each file is opened once per process and shared by all Plugin values so
+// that long-running processes creating a Plugin value per check (e.g.,
+// Runner) do not leak file descriptors.
+var (
+	debugEnvFilesMu sync.Mutex
+	debugEnvFiles   = make(map[string]*os.File)
+)
+
+// openDebugEnvFile returns the file opened for appending debug log entries
+// at the given path, opening the file if not already open.
+func openDebugEnvFile(path string) (*os.File, error) {
+	debugEnvFilesMu.Lock()
+	defer debugEnvFilesMu.Unlock()
+
+	if file, ok := debugEnvFiles[path]; ok {
+		return file, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, debugLogFilePerms)
+	if err != nil {
+		return nil, err
+	}
+
+	debugEnvFiles[path] = file
+
+	return file, nil
+}
+
+// applyDebugEnv enables debug logging as requested via environment
+// variables. Invalid values are reported via the debug log (if enabled) and
+// otherwise ignored.
+func (p *Plugin) applyDebugEnv() {
+	categories := strings.TrimSpace(os.Getenv(DebugEnvVar))
+	levelName := strings.TrimSpace(os.Getenv(DebugLevelEnvVar))
+
+	if categories == "" && levelName == "" {
+		return
+	}
+
+	var problems []string
+
+	if path := strings.TrimSpace(os.Getenv(DebugFileEnvVar)); path != "" {
+		file, err := openDebugEnvFile(path)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("failed to open %s: %v", DebugFileEnvVar, err))
+		default:
+			// The file is shared with other Plugin values and remains open
+			// for the lifetime of the process.
+			p.SetDebugLoggingOutputTarget(file)
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(DebugFormatEnvVar))) {
+	case "", "text":
+	case "json":
+		p.SetDebugLogFormat(DebugLogFormatJSON)
+	default:
+		problems = append(problems, fmt.Sprintf(
+			"unsupported %s value %q",
+			DebugFormatEnvVar,
+			os.Getenv(DebugFormatEnvVar),
+		))
+	}
+
+	var level DebugLogLevel
+	if levelName != "" {
+		level = parseDebugLogLevel(levelName)
+		if level == 0 {
+			problems = append(problems, fmt.Sprintf(
+				"unsupported %s value %q",
+				DebugLevelEnvVar,
+				levelName,
+			))
+		}
+	}
+
+	switch {
+	case categories == "" && level != 0:
+		p.SetDebugLogLevel(level)
+
+	case categories != "":
+		problems = append(problems, p.enableDebugCategories(categories)...)
+
+		if level != 0 {
+			p.debugLogLevel = level
+		}
+	}
+
+	// Use an "unfiltered" logger call to ensure that problems have the best
+	// chance of being seen.
+	for _, problem := range problems {
+		p.log(fmt.Sprintf("Ignoring debug logging environment setting: %s", problem))
+	}
+
+	p.logAction("Debug logging enabled via environment variables")
+}
+
+// enableDebugCategories enables the debug logging options matching the given
+// comma separated list of debug logging categories. A description of each
+// unsupported category is returned.
+func (p *Plugin) enableDebugCategories(categories string) []string {
+	var problems []string
+
+	for _, category := range strings.Split(categories, ",") {
+		category = strings.ToLower(strings.TrimSpace(category))
+
+		switch category {
+		case "":
+		case "all", "1", "true":
+			p.DebugLoggingEnableAll()
+		case logCategoryActions:
+			p.DebugLoggingEnableActions()
+		case logCategoryPluginOutputSize:
+			p.DebugLoggingEnablePluginOutputSize()
+		case logCategoryExitDiagnostics:
+			p.DebugLoggingEnableExitDiagnostics()
+		case logCategoryPhaseTiming:
+			p.DebugLoggingEnablePhaseTiming()
+		case logCategoryThresholds:
+			p.DebugLoggingEnableThresholdEvaluation()
+		case logCategoryPayload:
+			p.DebugLoggingEnablePayload()
+		default:
+			problems = append(problems, fmt.Sprintf(
+				"unsupported %s category %q",
+				DebugEnvVar,
+				category,
+			))
+		}
+	}
+
+	return problems
+}
+
+// parseDebugLogLevel returns the debug log level matching the given name or
+// zero if not recognized.
+func parseDebugLogLevel(name string) DebugLogLevel {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	for level := DebugLogLevelError; level <= DebugLogLevelTrace; level++ {
+		if level.String() == name {
+			return level
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// NOTE: These tests modify environment variables and cannot run in parallel.
+
+// closeDebugLogFile closes the debug log file opened by NewPlugin so that the
+// temporary directory can be removed on all platforms.
+func closeDebugLogFile(t *testing.T, plugin *nagios.Plugin) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		if closer, ok := plugin.DebugLoggingOutputTarget().(io.Closer); ok {
+			_ = closer.Close()
+		}
+	})
+}
+
+func TestNewPlugin_EnablesDebugCategoriesFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+
+	t.Setenv(nagios.DebugEnvVar, "actions, payload")
+	t.Setenv(nagios.DebugFileEnvVar, path)
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	closeDebugLogFile(t, plugin)
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "OK: all good"
+
+	if _, err := plugin.AddPayloadString("payload"); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read debug log file: %v", err)
+	}
+
+	for _, want := range []string{
+		"Debug logging enabled via environment variables",
+		"using gzip+ascii85 codec",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("ERROR: %q not found in debug log file:\n%s", want, string(got))
+		}
+	}
+
+	if strings.Contains(string(got), "Phase Render completed") {
+		t.Errorf("ERROR: unexpected phase timing entry in debug log file:\n%s", string(got))
+	}
+
+	t.Log("OK: debug categories enabled from environment")
+}
+
+func TestNewPlugin_AppliesDebugLevelAndFormatFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+
+	t.Setenv(nagios.DebugLevelEnvVar, "warn")
+	t.Setenv(nagios.DebugFormatEnvVar, "json")
+	t.Setenv(nagios.DebugFileEnvVar, path)
+
+	plugin := nagios.NewPlugin()
+	closeDebugLogFile(t, plugin)
+	plugin.EnableStrictMode()
+	plugin.SetOutputTarget(nil)
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read debug log file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(got)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("ERROR: want 1 debug log entry, got %d:\n%s", len(lines), string(got))
+	}
+
+	var entry struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("ERROR: failed to decode log entry %q: %v", lines[0], err)
+	}
+
+	if entry.Level != "warn" {
+		t.Errorf("ERROR: want level %q, got %q", "warn", entry.Level)
+	}
+
+	t.Log("OK: debug level and format applied from environment")
+}
+
+func TestNewPlugin_ReportsInvalidDebugEnvironmentSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+
+	t.Setenv(nagios.DebugEnvVar, "actions,bogus")
+	t.Setenv(nagios.DebugLevelEnvVar, "loud")
+	t.Setenv(nagios.DebugFileEnvVar, path)
+
+	closeDebugLogFile(t, nagios.NewPlugin())
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read debug log file: %v", err)
+	}
+
+	for _, want := range []string{
+		`unsupported GO_NAGIOS_DEBUG category "bogus"`,
+		`unsupported GO_NAGIOS_DEBUG_LEVEL value "loud"`,
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("ERROR: %q not found in debug log file:\n%s", want, string(got))
+		}
+	}
+
+	t.Log("OK: invalid debug environment settings reported")
+}
@@ -10,3 +10,64 @@
 //
 //nolint:dupl,gocognit // ignore "lines are duplicate of" and function complexity
 package nagios_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetDebugLogLevel_FiltersLessSevereEntries(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogLevel(nagios.DebugLogLevelWarn)
+	plugin.SkipOSExit()
+
+	// Invalid output target; logged as a warning.
+	plugin.SetOutputTarget(nil)
+	plugin.SetOutputTarget(&outputBuffer)
+
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	got := logBuffer.String()
+
+	switch {
+	case !strings.Contains(got, "Specified output target is invalid"):
+		t.Fatalf("ERROR: warning entry missing from debug log:\n%s", got)
+	case strings.Contains(got, "Processing ServiceOutput section"):
+		t.Fatalf("ERROR: debug entry present in debug log:\n%s", got)
+	case strings.Contains(got, "bytes"):
+		t.Fatalf("ERROR: trace entry present in debug log:\n%s", got)
+	}
+
+	t.Log("OK: debug log entries filtered by level as expected")
+}
+
+func TestPlugin_SetDebugLogLevel_IgnoresInvalidLevel(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogLevel(nagios.DebugLogLevel(42))
+	plugin.SetOutputTarget(nil)
+
+	if logBuffer.Len() != 0 {
+		t.Fatalf("ERROR: debug logging unexpectedly enabled:\n%s", logBuffer.String())
+	}
+
+	if nagios.DebugLogLevelTrace.String() != "trace" {
+		t.Fatalf("ERROR: unexpected level name %q", nagios.DebugLogLevelTrace.String())
+	}
+
+	t.Log("OK: invalid level ignored as expected")
+}
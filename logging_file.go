@@ -0,0 +1,186 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// debugLogFilePerms is the permissions used when creating a debug log file.
+// Debug log output may contain sensitive details so access is limited to the
+// owner.
+const debugLogFilePerms fs.FileMode = 0600
+
+// RotatingDebugLogFile is a debug log file which is rotated once it reaches
+// a maximum size. Rotated files are renamed using a numeric suffix (e.g.,
+// debug.log.1, debug.log.2) with higher numbers indicating older files.
+//
+// A RotatingDebugLogFile is safe for concurrent use and is intended for use
+// with SetDebugLoggingOutputTarget.
+type RotatingDebugLogFile struct {
+	// mu guards the file handle and current size.
+	mu sync.Mutex
+
+	// file is the open handle for the current debug log file.
+	file *os.File
+
+	// path is the path to the current debug log file.
+	path string
+
+	// size is the current size of the debug log file in bytes.
+	size int64
+
+	// maxSize is the size in bytes at which the debug log file is rotated.
+	maxSize int64
+
+	// maxBackups is the number of rotated debug log files retained.
+	maxBackups int
+}
+
+// NewRotatingDebugLogFile opens (creating if needed) the debug log file at
+// the given path for appending and returns a writer suitable for use with
+// SetDebugLoggingOutputTarget.
+//
+// Once writing an entry would grow the file beyond maxSize bytes the file is
+// rotated, retaining up to maxBackups previous files. If maxBackups is zero
+// the file is truncated instead. An entry larger than maxSize is written to
+// an empty file as-is.
+//
+// The caller is responsible for calling Close once debug logging is
+// complete.
+func NewRotatingDebugLogFile(path string, maxSize int64, maxBackups int) (*RotatingDebugLogFile, error) {
+	switch {
+	case path == "":
+		return nil, fmt.Errorf(
+			"failed to open debug log file: path not specified: %w",
+			ErrMissingValue,
+		)
+	case maxSize <= 0:
+		return nil, fmt.Errorf(
+			"failed to open debug log file: max size %d is not positive: %w",
+			maxSize,
+			ErrInvalidDebugLogFileSettings,
+		)
+	case maxBackups < 0:
+		return nil, fmt.Errorf(
+			"failed to open debug log file: max backups %d is negative: %w",
+			maxBackups,
+			ErrInvalidDebugLogFileSettings,
+		)
+	}
+
+	r := RotatingDebugLogFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// Write writes the given data to the debug log file, rotating the file first
+// if needed.
+func (r *RotatingDebugLogFile) Write(data []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, fmt.Errorf("failed to write to debug log file %s: %w", r.path, os.ErrClosed)
+	}
+
+	if r.size > 0 && r.size+int64(len(data)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(data)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the debug log file. Further writes fail.
+func (r *RotatingDebugLogFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+// open opens the debug log file for appending and records the current size.
+func (r *RotatingDebugLogFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, debugLogFilePerms)
+	if err != nil {
+		return fmt.Errorf("failed to open debug log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to determine debug log file size: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+
+	return nil
+}
+
+// rotate closes the current debug log file, shifts any retained backups and
+// opens a new, empty debug log file.
+func (r *RotatingDebugLogFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close debug log file for rotation: %w", err)
+	}
+	r.file = nil
+
+	if r.maxBackups == 0 {
+		if err := os.Truncate(r.path, 0); err != nil {
+			return fmt.Errorf("failed to truncate debug log file: %w", err)
+		}
+
+		return r.open()
+	}
+
+	// Shift backups, discarding the oldest.
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(r.backupPath(i), r.backupPath(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate debug log file backup: %w", err)
+		}
+	}
+
+	if err := os.Rename(r.path, r.backupPath(1)); err != nil {
+		return fmt.Errorf("failed to rotate debug log file: %w", err)
+	}
+
+	return r.open()
+}
+
+// backupPath returns the path of the given rotated debug log file.
+func (r *RotatingDebugLogFile) backupPath(index int) string {
+	return r.path + "." + strconv.Itoa(index)
+}
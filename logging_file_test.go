@@ -0,0 +1,156 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestNewRotatingDebugLogFile_FailsWithInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	tests := map[string]struct {
+		path       string
+		maxSize    int64
+		maxBackups int
+		wantErr    error
+	}{
+		"empty path": {
+			path:       "",
+			maxSize:    1024,
+			maxBackups: 1,
+			wantErr:    nagios.ErrMissingValue,
+		},
+		"zero max size": {
+			path:       filepath.Join(dir, "zero.log"),
+			maxSize:    0,
+			maxBackups: 1,
+			wantErr:    nagios.ErrInvalidDebugLogFileSettings,
+		},
+		"negative max backups": {
+			path:       filepath.Join(dir, "negative.log"),
+			maxSize:    1024,
+			maxBackups: -1,
+			wantErr:    nagios.ErrInvalidDebugLogFileSettings,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := nagios.NewRotatingDebugLogFile(tt.path, tt.maxSize, tt.maxBackups)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ERROR: want error %v, got %v", tt.wantErr, err)
+			}
+
+			t.Logf("OK: expected error returned: %v", err)
+		})
+	}
+}
+
+func TestRotatingDebugLogFile_RotatesAndRetainsBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "debug.log")
+
+	logFile, err := nagios.NewRotatingDebugLogFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("ERROR: failed to open debug log file: %v", err)
+	}
+
+	for _, entry := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := logFile.Write([]byte(entry)); err != nil {
+			t.Fatalf("ERROR: failed to write entry %q: %v", entry, err)
+		}
+	}
+
+	if err := logFile.Close(); err != nil {
+		t.Fatalf("ERROR: failed to close debug log file: %v", err)
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+
+	for file, wantContent := range want {
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ERROR: failed to read %s: %v", file, err)
+		}
+
+		if string(got) != wantContent {
+			t.Errorf("ERROR: want %q in %s, got %q", wantContent, file, string(got))
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ERROR: unexpected backup beyond retention limit: %v", err)
+	}
+
+	if _, err := logFile.Write([]byte("closed\n")); err == nil {
+		t.Error("ERROR: expected write to closed debug log file to fail")
+	}
+
+	t.Log("OK: debug log file rotated as expected")
+}
+
+func TestRotatingDebugLogFile_TruncatesWithoutBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "debug.log")
+
+	if err := os.WriteFile(path, []byte("existing\n"), 0600); err != nil {
+		t.Fatalf("ERROR: failed to create existing debug log file: %v", err)
+	}
+
+	logFile, err := nagios.NewRotatingDebugLogFile(path, 12, 0)
+	if err != nil {
+		t.Fatalf("ERROR: failed to open debug log file: %v", err)
+	}
+
+	var plugin = nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(logFile)
+	plugin.DebugLoggingEnableActions()
+	plugin.EnableStrictMode()
+
+	if err := logFile.Close(); err != nil {
+		t.Fatalf("ERROR: failed to close debug log file: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read %s: %v", path, err)
+	}
+
+	if strings.Contains(string(got), "existing") {
+		t.Errorf("ERROR: existing content not truncated:\n%s", string(got))
+	}
+
+	if !strings.Contains(string(got), "Enabling strict mode") {
+		t.Errorf("ERROR: debug log entry not written:\n%s", string(got))
+	}
+
+	matches, _ := filepath.Glob(path + ".*")
+	if len(matches) != 0 {
+		t.Errorf("ERROR: unexpected backups: %v", matches)
+	}
+
+	t.Log("OK: debug log file truncated as expected")
+}
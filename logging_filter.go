@@ -0,0 +1,90 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"sort"
+	"strings"
+)
+
+// RedactedPlaceholder is the text used by RedactStrings in place of secret
+// values.
+const RedactedPlaceholder string = "[REDACTED]"
+
+// DebugLogFilterFunc is a function applied to each debug log message before
+// it is written. The returned value is written in place of the given
+// message.
+type DebugLogFilterFunc func(msg string) string
+
+// SetDebugLogFilter specifies a function applied to every debug log message
+// before it is written, allowing secrets which appear in logged payload
+// content or error messages to be masked centrally. The function is also
+// applied to string (and error) values of structured attributes provided to
+// debug logging handlers (see SetDebugLogFormat).
+//
+// A nil value removes any previously set filter. See also RedactStrings.
+func (p *Plugin) SetDebugLogFilter(fn DebugLogFilterFunc) {
+	p.debugLogFilter = fn
+}
+
+// RedactStrings returns a DebugLogFilterFunc which replaces each occurrence
+// of the given secret values with RedactedPlaceholder. Empty values are
+// ignored. Longer values are replaced first so that secrets containing other
+// secrets are fully masked.
+func RedactStrings(secrets ...string) DebugLogFilterFunc {
+	values := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			values = append(values, secret)
+		}
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	pairs := make([]string, 0, len(values)*2)
+	for _, value := range values {
+		pairs = append(pairs, value, RedactedPlaceholder)
+	}
+
+	replacer := strings.NewReplacer(pairs...)
+
+	return func(msg string) string {
+		if len(pairs) == 0 {
+			return msg
+		}
+
+		return replacer.Replace(msg)
+	}
+}
+
+// filterLogEntry applies the debug log filter to the given message and the
+// string (and error) values of the given attributes. The given attributes
+// are not modified.
+func (p *Plugin) filterLogEntry(msg string, attrs []logAttr) (string, []logAttr) {
+	msg = p.debugLogFilter(msg)
+
+	if len(attrs) == 0 {
+		return msg, attrs
+	}
+
+	filtered := make([]logAttr, len(attrs))
+	for i, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			attr.Value = p.debugLogFilter(v)
+		case error:
+			attr.Value = p.debugLogFilter(v.Error())
+		}
+
+		filtered[i] = attr
+	}
+
+	return msg, filtered
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetDebugLogFilter_MasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	const secret string = "hunter2"
+
+	var logBuffer bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogFilter(nagios.RedactStrings(secret))
+	plugin.DebugLoggingEnableAll()
+	plugin.EnableStrictMode()
+
+	// Internal failure details (including the invalid metric label) are
+	// logged.
+	_ = plugin.AddPerfData(true, nagios.PerformanceData{Label: "dsn_" + secret, Value: "invalid"})
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	if !strings.Contains(logBuffer.String(), "dsn_"+nagios.RedactedPlaceholder) {
+		t.Fatalf("ERROR: redacted internal failure entry not found:\n%s", logBuffer.String())
+	}
+
+	got := logBuffer.String()
+	if strings.Contains(got, secret) {
+		t.Fatalf("ERROR: secret found in debug log:\n%s", got)
+	}
+
+	t.Log("OK: debug log does not contain secret as expected")
+}
+
+func TestRedactStrings(t *testing.T) {
+	t.Parallel()
+
+	filter := nagios.RedactStrings("", "abc", "abcdef")
+
+	want := "token=[REDACTED] key=[REDACTED]"
+	if got := filter("token=abcdef key=abc"); got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	if got := nagios.RedactStrings()("unchanged"); got != "unchanged" {
+		t.Fatalf("ERROR: unexpected filter result %q", got)
+	}
+
+	t.Log("OK: secrets redacted as expected")
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// DebugLogFormat is the format used for debug log entries.
+type DebugLogFormat int
+
+// Supported debug log formats.
+const (
+	// DebugLogFormatText is the default plain text debug log format.
+	DebugLogFormatText DebugLogFormat = iota
+
+	// DebugLogFormatJSON emits each debug log entry as a single line JSON
+	// object (JSON Lines) suitable for log aggregation systems (e.g., Loki,
+	// Elasticsearch).
+	DebugLogFormatJSON
+)
+
+// debugLogJSONEntry is the JSON representation of a debug log entry.
+type debugLogJSONEntry struct {
+	Timestamp string                 `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Category  string                 `json:"category"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// jsonLogHandler writes debug log entries as JSON Lines.
+type jsonLogHandler struct {
+	// target returns the output target for debug log entries.
+	target func() io.Writer
+}
+
+// SetDebugLogFormat overrides the default plain text format used for debug
+// log entries. Using DebugLogFormatJSON emits each entry as a single line JSON
+// object with the following fields:
+//
+//   - timestamp: RFC 3339 timestamp with nanosecond precision
+//   - level: the debug log level (e.g., "debug")
+//   - category: the debug logging category (e.g., "actions")
+//   - message: the log message
+//   - fields: structured details (e.g., plugin output section and byte
+//     counts), omitted if not available
+//
+// Selecting a format replaces any debug logging handler previously set via
+// SetDebugLoggingHandler. Unsupported formats are ignored.
+//
+// NOTE: As with SetDebugLoggingOutputTarget, calling this function does not
+// change the default debug logging state from disabled to enabled.
+func (p *Plugin) SetDebugLogFormat(format DebugLogFormat) {
+	switch format {
+	case DebugLogFormatText:
+		p.logHandler = nil
+		p.logAction("plain text debug log format set as requested")
+	case DebugLogFormatJSON:
+		p.logHandler = jsonLogHandler{target: p.debugLogHandlerTarget}
+		p.logAction("JSON debug log format set as requested")
+	}
+}
+
+// debugLogHandlerTarget returns the output target used by debug log handlers
+// writing to the debug logging output target. Entries are discarded until a
+// debug logging output target is set (or debug logging is enabled), matching
+// the behavior of the plain text logger.
+func (p *Plugin) debugLogHandlerTarget() io.Writer {
+	if p.logOutputSink == nil {
+		return defaultPluginDebugLoggerTarget()
+	}
+
+	return p.logOutputSink
+}
+
+// handle writes the given entry as a single line JSON object.
+func (jh jsonLogHandler) handle(entry debugLogEntry) {
+	doc := debugLogJSONEntry{
+		Timestamp: entry.time.Format(time.RFC3339Nano),
+		Level:     entry.level.String(),
+		Category:  entry.category,
+		Message:   entry.msg,
+	}
+
+	if len(entry.attrs) > 0 {
+		doc.Fields = make(map[string]interface{}, len(entry.attrs))
+		for _, attr := range entry.attrs {
+			value := attr.Value
+
+			// Errors do not have a useful JSON representation.
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+
+			doc.Fields[attr.Key] = value
+		}
+	}
+
+	line, err := json.Marshal(doc)
+	if err != nil {
+		line, _ = json.Marshal(debugLogJSONEntry{
+			Timestamp: doc.Timestamp,
+			Level:     doc.Level,
+			Category:  doc.Category,
+			Message:   doc.Message,
+			Fields: map[string]interface{}{
+				"marshal_error": err.Error(),
+			},
+		})
+	}
+
+	_, _ = jh.target().Write(append(line, '\n'))
+}
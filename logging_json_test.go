@@ -0,0 +1,73 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetDebugLogFormat_EmitsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLogFormat(nagios.DebugLogFormatJSON)
+	plugin.DebugLoggingEnableAll()
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	type entry struct {
+		Timestamp string                 `json:"timestamp"`
+		Level     string                 `json:"level"`
+		Category  string                 `json:"category"`
+		Message   string                 `json:"message"`
+		Fields    map[string]interface{} `json:"fields"`
+	}
+
+	lines := strings.Split(strings.TrimSpace(logBuffer.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("ERROR: expected multiple log entries, got:\n%s", logBuffer.String())
+	}
+
+	var sawFields bool
+	for _, line := range lines {
+		var e entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("ERROR: failed to decode log entry %q: %v", line, err)
+		}
+
+		if _, err := time.Parse(time.RFC3339Nano, e.Timestamp); err != nil {
+			t.Errorf("ERROR: invalid timestamp in log entry %q", line)
+		}
+
+		if e.Level == "" || e.Category == "" || e.Message == "" {
+			t.Errorf("ERROR: incomplete log entry %q", line)
+		}
+
+		if e.Fields["section"] == "ServiceOutput" && e.Category == "plugin_output_size" {
+			sawFields = true
+		}
+	}
+
+	if !sawFields {
+		t.Fatalf("ERROR: structured fields not found:\n%s", logBuffer.String())
+	}
+
+	t.Log("OK: JSON debug log entries emitted as expected")
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build go1.21
+
+package nagios
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler adapts a log/slog Handler for use as a debug log handler.
+type slogHandler struct {
+	handler slog.Handler
+}
+
+// slogLevelTrace is the log/slog level used for DebugLogLevelTrace entries.
+const slogLevelTrace slog.Level = slog.LevelDebug - 4
+
+// SetDebugLoggingHandler specifies a log/slog Handler used for debug log
+// entries in place of the default plain text logger. Each entry is recorded
+// at the log/slog level matching the debug log level of the entry (trace
+// entries use slog.LevelDebug-4) with a category attribute (e.g., "actions"
+// or "plugin_output_size") and any structured details provided by this
+// library (e.g., the plugin output section and byte counts).
+//
+// A nil value restores the default plain text logger.
+//
+// NOTE: As with SetDebugLoggingOutputTarget, calling this function does not
+// change the default debug logging state from disabled to enabled. That step
+// must be performed separately by either enabling all debug logging options
+// OR enabling select debug logging options.
+func (p *Plugin) SetDebugLoggingHandler(h slog.Handler) {
+	if h == nil {
+		p.logHandler = nil
+		p.logAction("debug logging handler cleared; using plain text logger")
+
+		return
+	}
+
+	p.logHandler = slogHandler{handler: h}
+	p.logAction("custom debug logging handler set as requested")
+}
+
+// handle records the given entry using the slog Handler.
+func (sh slogHandler) handle(entry debugLogEntry) {
+	ctx := context.Background()
+	level := slogLevel(entry.level)
+
+	if !sh.handler.Enabled(ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(entry.time, level, entry.msg, 0)
+	record.AddAttrs(slog.String(logAttrCategory, entry.category))
+
+	for _, attr := range entry.attrs {
+		record.AddAttrs(slog.Any(attr.Key, attr.Value))
+	}
+
+	_ = sh.handler.Handle(ctx, record)
+}
+
+// slogLevel returns the log/slog level for the given debug log level.
+func slogLevel(level DebugLogLevel) slog.Level {
+	switch level {
+	case DebugLogLevelError:
+		return slog.LevelError
+	case DebugLogLevelWarn:
+		return slog.LevelWarn
+	case DebugLogLevelInfo:
+		return slog.LevelInfo
+	case DebugLogLevelTrace:
+		return slogLevelTrace
+	default:
+		return slog.LevelDebug
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build go1.21
+
+package nagios_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SetDebugLoggingHandler_EmitsStructuredAttributes(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetDebugLoggingHandler(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+	plugin.DebugLoggingEnableAll()
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	var sawSection bool
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("ERROR: failed to decode log entry %q: %v", line, err)
+		}
+
+		if entry["level"] == nil || entry["category"] == nil {
+			t.Errorf("ERROR: unexpected log entry %q", line)
+		}
+
+		if entry["section"] == "ServiceOutput" && entry["category"] == "plugin_output_size" {
+			sawSection = true
+			if entry["level"] != "DEBUG-4" || entry["bytes"] != float64(len("OK: all good")) {
+				t.Errorf("ERROR: unexpected byte count in log entry %q", line)
+			}
+		}
+	}
+
+	if !sawSection {
+		t.Fatalf("ERROR: ServiceOutput section size entry not found:\n%s", logBuffer.String())
+	}
+
+	t.Log("OK: structured debug log entries emitted as expected")
+}
+
+func TestPlugin_SetDebugLoggingHandler_NilRestoresPlainText(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.SetDebugLoggingHandler(slog.NewJSONHandler(&logBuffer, nil))
+	plugin.SetDebugLoggingHandler(nil)
+	plugin.DebugLoggingEnableActions()
+	plugin.SkipOSExit()
+
+	logBuffer.Reset()
+	plugin.SetOutputTarget(nil)
+
+	if got := logBuffer.String(); !strings.HasPrefix(got, "[go-nagios] ") || strings.Contains(got, "{") {
+		t.Fatalf("ERROR: unexpected plain text log output %q", got)
+	}
+
+	t.Log("OK: plain text logger restored as expected")
+}
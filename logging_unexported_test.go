@@ -12,8 +12,11 @@ package nagios
 
 import (
 	"bytes"
+	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -754,3 +757,290 @@ func assertAllDebugLoggingOptionsAreDisabled(plugin *Plugin, t *testing.T) {
 		t.Errorf("(-want, +got)\n:%s", d)
 	}
 }
+
+func TestPlugin_DebugLoggingEnablePhaseTiming_CorrectlyEnablesOnlyDebugLoggingPhaseTimingOption(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	// Flip everything off to start with so we can selectively enable just the
+	// debug logging option we're interested in.
+	plugin.debugLogging = allDebugLoggingOptionsDisabled()
+
+	plugin.DebugLoggingEnablePhaseTiming()
+
+	selectDebugLoggingOptionsEnabled := allDebugLoggingOptionsDisabled()
+	selectDebugLoggingOptionsEnabled.phaseTiming = true
+
+	if d := cmp.Diff(
+		selectDebugLoggingOptionsEnabled,
+		plugin.debugLogging,
+		cmp.AllowUnexported(debugLoggingOptions{}),
+	); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+func TestPlugin_DebugLoggingDisablePhaseTiming_CorrectlyDisablesOnlyDebugLoggingPhaseTimingOption(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	// Flip everything on to start with so we can selectively disable specific
+	// debug logging options.
+	plugin.debugLogging = allDebugLoggingOptionsEnabled()
+
+	plugin.DebugLoggingDisablePhaseTiming()
+
+	selectDebugLoggingOptionsDisabled := allDebugLoggingOptionsEnabled()
+	selectDebugLoggingOptionsDisabled.phaseTiming = false
+
+	if d := cmp.Diff(
+		selectDebugLoggingOptionsDisabled,
+		plugin.debugLogging,
+		cmp.AllowUnexported(debugLoggingOptions{}),
+	); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+func TestPlugin_DebugLoggingEnablePhaseTiming_LogsEachPhase(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.DebugLoggingEnablePhaseTiming()
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	got := logBuffer.String()
+	for _, phase := range []string{
+		"ServiceOutput",
+		"Errors",
+		"Thresholds",
+		"LongServiceOutput",
+		"EncodedPayload",
+		"PerformanceData",
+		"Render",
+		"Write",
+		"EmitHooks",
+	} {
+		if !strings.Contains(got, "Phase "+phase+" completed in ") {
+			t.Errorf("ERROR: timing for phase %s not logged", phase)
+		}
+	}
+
+	if strings.Contains(got, "Processing ServiceOutput section") {
+		t.Errorf("ERROR: unexpected actions entry logged:\n%s", got)
+	}
+}
+
+func TestPlugin_DebugLoggingEnableThresholdEvaluation_CorrectlyEnablesOnlyDebugLoggingThresholdEvaluationOption(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	// Flip everything off to start with so we can selectively enable just the
+	// debug logging option we're interested in.
+	plugin.debugLogging = allDebugLoggingOptionsDisabled()
+
+	plugin.DebugLoggingEnableThresholdEvaluation()
+
+	selectDebugLoggingOptionsEnabled := allDebugLoggingOptionsDisabled()
+	selectDebugLoggingOptionsEnabled.thresholdEvaluation = true
+
+	if d := cmp.Diff(
+		selectDebugLoggingOptionsEnabled,
+		plugin.debugLogging,
+		cmp.AllowUnexported(debugLoggingOptions{}),
+	); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+func TestPlugin_DebugLoggingDisableThresholdEvaluation_CorrectlyDisablesOnlyDebugLoggingThresholdEvaluationOption(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	// Flip everything on to start with so we can selectively disable specific
+	// debug logging options.
+	plugin.debugLogging = allDebugLoggingOptionsEnabled()
+
+	plugin.DebugLoggingDisableThresholdEvaluation()
+
+	selectDebugLoggingOptionsDisabled := allDebugLoggingOptionsEnabled()
+	selectDebugLoggingOptionsDisabled.thresholdEvaluation = false
+
+	if d := cmp.Diff(
+		selectDebugLoggingOptionsDisabled,
+		plugin.debugLogging,
+		cmp.AllowUnexported(debugLoggingOptions{}),
+	); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+func TestPlugin_DebugLoggingEnableThresholdEvaluation_LogsDecisions(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	plugin := NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.DebugLoggingEnableThresholdEvaluation()
+
+	err := plugin.EvaluateThreshold(PerformanceData{
+		Label: "load",
+		Value: "15",
+		Warn:  "10",
+		Crit:  "@20:30",
+	})
+	if err != nil {
+		t.Fatalf("ERROR: unexpected error evaluating thresholds: %v", err)
+	}
+
+	if plugin.ExitStatusCode != StateWARNINGExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", StateWARNINGExitCode, plugin.ExitStatusCode)
+	}
+
+	got := logBuffer.String()
+	for _, want := range []string{
+		`Metric "load" value "15": critical threshold "@20:30" parsed as [20, 30] (alert inside), alert: false`,
+		`Metric "load" value "15": warning threshold "10" parsed as [0, 10] (alert outside), alert: true`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: %q not found in debug log output:\n%s", want, got)
+		}
+	}
+}
+
+func TestPlugin_DebugLoggingEnablePayload_CorrectlyEnablesOnlyDebugLoggingPayloadOption(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	// Flip everything off to start with so we can selectively enable just the
+	// debug logging option we're interested in.
+	plugin.debugLogging = allDebugLoggingOptionsDisabled()
+
+	plugin.DebugLoggingEnablePayload()
+
+	selectDebugLoggingOptionsEnabled := allDebugLoggingOptionsDisabled()
+	selectDebugLoggingOptionsEnabled.payload = true
+
+	if d := cmp.Diff(
+		selectDebugLoggingOptionsEnabled,
+		plugin.debugLogging,
+		cmp.AllowUnexported(debugLoggingOptions{}),
+	); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+func TestPlugin_DebugLoggingDisablePayload_CorrectlyDisablesOnlyDebugLoggingPayloadOption(t *testing.T) {
+	t.Parallel()
+
+	plugin := NewPlugin()
+
+	// Flip everything on to start with so we can selectively disable specific
+	// debug logging options.
+	plugin.debugLogging = allDebugLoggingOptionsEnabled()
+
+	plugin.DebugLoggingDisablePayload()
+
+	selectDebugLoggingOptionsDisabled := allDebugLoggingOptionsEnabled()
+	selectDebugLoggingOptionsDisabled.payload = false
+
+	if d := cmp.Diff(
+		selectDebugLoggingOptionsDisabled,
+		plugin.debugLogging,
+		cmp.AllowUnexported(debugLoggingOptions{}),
+	); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+}
+
+func TestPlugin_DebugLoggingEnablePayload_LogsEncodeAndDecodeDetails(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"status": "ok"}`
+
+	var encodeLog bytes.Buffer
+	var outputBuffer strings.Builder
+
+	plugin := NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetDebugLoggingOutputTarget(&encodeLog)
+	plugin.DebugLoggingEnablePayload()
+	plugin.ServiceOutput = "OK: all good"
+
+	if _, err := plugin.AddPayloadString(payload); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	checksum := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(payload)))
+
+	for _, want := range []string{
+		fmt.Sprintf("%d bytes unencoded payload buffer, CRC-32 checksum %s", len(payload), checksum),
+		"using gzip+ascii85 codec",
+		fmt.Sprintf("using delimiters %q and %q", defaultPayloadDelimiterLeft, defaultPayloadDelimiterRight),
+	} {
+		if !strings.Contains(encodeLog.String(), want) {
+			t.Errorf("ERROR: %q not found in encode debug log output:\n%s", want, encodeLog.String())
+		}
+	}
+
+	var decodeLog bytes.Buffer
+
+	consumer := NewPlugin()
+	consumer.SetDebugLoggingOutputTarget(&decodeLog)
+	consumer.DebugLoggingEnablePayload()
+
+	got, err := consumer.ExtractAndDecodePayload(outputBuffer.String(), "")
+	if err != nil {
+		t.Fatalf("ERROR: failed to extract and decode payload: %v", err)
+	}
+
+	if got != payload {
+		t.Errorf("ERROR: want payload %q, got %q", payload, got)
+	}
+
+	for _, want := range []string{
+		"matched 1 time(s)",
+		"using gzip+ascii85 codec",
+		fmt.Sprintf("%d bytes decompressed payload, CRC-32 checksum %s verified", len(payload), checksum),
+	} {
+		if !strings.Contains(decodeLog.String(), want) {
+			t.Errorf("ERROR: %q not found in decode debug log output:\n%s", want, decodeLog.String())
+		}
+	}
+}
+
+func TestNewPlugin_SharesDebugFileFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+
+	t.Setenv(DebugEnvVar, "actions")
+	t.Setenv(DebugFileEnvVar, path)
+
+	first := NewPlugin()
+	second := NewPlugin()
+
+	if first.logOutputSink == nil || first.logOutputSink != second.logOutputSink {
+		t.Fatalf(
+			"ERROR: want shared debug log file, got %v and %v",
+			first.logOutputSink,
+			second.logOutputSink,
+		)
+	}
+
+	t.Log("OK: debug log file opened once and shared by Plugin values")
+}
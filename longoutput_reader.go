@@ -0,0 +1,109 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultLongServiceOutputReaderLimit is the maximum number of bytes read
+// from a LongServiceOutput reader if a non-positive limit is given to
+// SetLongServiceOutputReader.
+const DefaultLongServiceOutputReaderLimit int64 = 64 * 1024
+
+// longServiceOutputSource is a reader providing LongServiceOutput content
+// streamed at emit time.
+type longServiceOutputSource struct {
+	// reader provides the LongServiceOutput content.
+	reader io.Reader
+
+	// limit is the maximum number of bytes read from the reader.
+	limit int64
+}
+
+// SetLongServiceOutputReader sets a reader providing LongServiceOutput
+// content. The reader content is streamed to the plugin output target when
+// plugin output is emitted instead of being held in memory alongside the
+// LongServiceOutput field. This is intended for plugins including large
+// command output.
+//
+// At most limit bytes are read (DefaultLongServiceOutputReaderLimit if
+// limit is not positive); a notice is emitted in place of any remaining
+// content. Reader content is emitted after any LongServiceOutput field
+// content and before any LongServiceOutput tables.
+//
+// The reader is read once and is not closed. Reader content is included in
+// the Nagios output format only; other output formats and Snapshot use the
+// LongServiceOutput field as-is. A nil reader removes a previously set
+// reader.
+func (p *Plugin) SetLongServiceOutputReader(r io.Reader, limit int64) {
+	if r == nil {
+		p.longServiceOutput = nil
+
+		return
+	}
+
+	if limit <= 0 {
+		limit = DefaultLongServiceOutputReaderLimit
+	}
+
+	p.longServiceOutput = &longServiceOutputSource{
+		reader: r,
+		limit:  limit,
+	}
+}
+
+// hasLongServiceOutput indicates whether LongServiceOutput content was
+// provided by client code, either via the LongServiceOutput field or a
+// LongServiceOutput reader, and is not hidden due to the final plugin state.
+func (p Plugin) hasLongServiceOutput() bool {
+	if p.isLongServiceOutputHiddenForState() {
+		return false
+	}
+
+	return p.LongServiceOutput != "" || p.longServiceOutput != nil
+}
+
+// writeTo streams the reader content (up to the limit) to the given writer.
+// If the reader content exceeds the limit or could not be read, a notice is
+// written following the content. The number of bytes written is returned
+// along with the first read error encountered (if any).
+func (src *longServiceOutputSource) writeTo(w io.Writer) (int, error) {
+	copied, readErr := io.Copy(w, io.LimitReader(src.reader, src.limit))
+	totalWritten := int(copied)
+
+	var notice string
+
+	switch {
+	case readErr != nil:
+		notice = fmt.Sprintf("[LongServiceOutput incomplete; failed to read content: %v]", readErr)
+
+	case copied == src.limit:
+		// Reading a single additional byte shows whether content was
+		// omitted.
+		var next [1]byte
+		if n, err := io.ReadFull(src.reader, next[:]); n > 0 {
+			notice = fmt.Sprintf("[LongServiceOutput truncated; content exceeds %d bytes]", src.limit)
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			readErr = err
+		}
+	}
+
+	if notice != "" {
+		written, err := writeStrings(w, CheckOutputEOL, notice)
+		if err != nil {
+			panic("Failed to write LongServiceOutput reader notice to given output sink")
+		}
+
+		totalWritten += written
+	}
+
+	return totalWritten, readErr
+}
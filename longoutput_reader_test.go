@@ -0,0 +1,190 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_SetLongServiceOutputReader_StreamsContent(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.HideThresholdsSection()
+	plugin.HideErrorsSection()
+	plugin.ServiceOutput = "OK: command completed"
+	plugin.SetLongServiceOutputReader(strings.NewReader("line one\nline two"), 0)
+	plugin.LongServiceOutput = "Command output:"
+	plugin.AddLongServiceOutputTable(nagios.OutputTable{
+		Headers: []string{"Name"},
+		Rows:    [][]string{{"value"}},
+	})
+	plugin.ReturnCheckResults()
+
+	// Reader content follows the LongServiceOutput field content and
+	// precedes any tables.
+	want := "OK: command completed" + nagios.CheckOutputEOL +
+		nagios.CheckOutputEOL + "Command output:" +
+		nagios.CheckOutputEOL + "line one\nline two" +
+		nagios.CheckOutputEOL + nagios.CheckOutputEOL + "Name"
+
+	got := outputBuffer.String()
+	if len(got) > len(want) {
+		got = got[:len(want)]
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: LongServiceOutput reader content streamed as expected")
+}
+
+func TestPlugin_SetLongServiceOutputReader_KeepsServiceOutputSeparate(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.HideThresholdsSection()
+	plugin.HideErrorsSection()
+	plugin.ServiceOutput = "OK: command completed"
+	plugin.SetLongServiceOutputReader(strings.NewReader("details"), 0)
+	plugin.ReturnCheckResults()
+
+	want := "OK: command completed" + nagios.CheckOutputEOL + nagios.CheckOutputEOL + "details" + nagios.CheckOutputEOL
+	if !strings.HasPrefix(outputBuffer.String(), want) {
+		t.Fatalf("ERROR: want output prefix %q, got %q", want, outputBuffer.String())
+	}
+
+	t.Log("OK: LongServiceOutput reader content separated from ServiceOutput")
+}
+
+func TestPlugin_SetLongServiceOutputReader_TruncatesAtLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content       string
+		limit         int64
+		wantContent   string
+		wantTruncated bool
+	}{
+		"content within limit": {
+			content:     "0123456789",
+			limit:       20,
+			wantContent: "0123456789",
+		},
+		"content equal to limit": {
+			content:     "0123456789",
+			limit:       10,
+			wantContent: "0123456789",
+		},
+		"content exceeds limit": {
+			content:       "0123456789",
+			limit:         4,
+			wantContent:   "0123",
+			wantTruncated: true,
+		},
+		"default limit": {
+			content:       strings.Repeat("x", int(nagios.DefaultLongServiceOutputReaderLimit)+1),
+			wantContent:   strings.Repeat("x", int(nagios.DefaultLongServiceOutputReaderLimit)),
+			wantTruncated: true,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.HideThresholdsSection()
+			plugin.HideErrorsSection()
+			plugin.ServiceOutput = "OK: command completed"
+			plugin.SetLongServiceOutputReader(strings.NewReader(tt.content), tt.limit)
+			plugin.ReturnCheckResults()
+
+			got := outputBuffer.String()
+			truncated := strings.Contains(got, "[LongServiceOutput truncated;")
+
+			switch {
+			case !strings.Contains(got, nagios.CheckOutputEOL+tt.wantContent+nagios.CheckOutputEOL):
+				t.Fatalf("ERROR: want content of %d bytes in output, got:\n%q", len(tt.wantContent), got)
+			case truncated != tt.wantTruncated:
+				t.Fatalf("ERROR: want truncation notice %t, got %t", tt.wantTruncated, truncated)
+			}
+
+			t.Log("OK: LongServiceOutput reader content limited as expected")
+		})
+	}
+}
+
+func TestPlugin_SetLongServiceOutputReader_ReportsReadError(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("command output unavailable")
+	r := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errRead))
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.HideThresholdsSection()
+	plugin.HideErrorsSection()
+	plugin.ServiceOutput = "OK: command completed"
+	plugin.SetLongServiceOutputReader(r, 0)
+	plugin.ReturnCheckResults()
+
+	want := "partial" + nagios.CheckOutputEOL + "[LongServiceOutput incomplete; failed to read content: " + errRead.Error() + "]"
+	if !strings.Contains(outputBuffer.String(), want) {
+		t.Fatalf("ERROR: want %q in output, got:\n%q", want, outputBuffer.String())
+	}
+
+	t.Log("OK: LongServiceOutput reader error reported as expected")
+}
+
+func TestPlugin_SetLongServiceOutputReader_NilReaderRemovesReader(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.HideThresholdsSection()
+	plugin.HideErrorsSection()
+	plugin.ServiceOutput = "OK: command completed"
+	plugin.SetLongServiceOutputReader(strings.NewReader("details"), 0)
+	plugin.SetLongServiceOutputReader(nil, 0)
+	plugin.ReturnCheckResults()
+
+	if d := cmp.Diff("OK: command completed", strings.SplitN(outputBuffer.String(), " |", 2)[0]); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: LongServiceOutput reader removed as expected")
+}
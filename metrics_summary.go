@@ -0,0 +1,179 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"io"
+)
+
+// metricsSummaryNoValue is the placeholder used in the metrics summary
+// section for empty metric fields.
+const metricsSummaryNoValue string = "-"
+
+// EnableMetricsSummarySection indicates that a metrics summary section
+// should be generated from the collected performance data metrics. The
+// section lists each metric with its value, thresholds and the outcome of
+// evaluating the value against those thresholds in aligned columns. The
+// section is omitted if no performance data metrics were collected.
+//
+// The section is rendered as an HTML table if the Icinga Web HTML output
+// profile is selected (see SetOutputProfile).
+func (p *Plugin) EnableMetricsSummarySection() {
+	p.logAction("Enabling metrics summary section as requested")
+	p.metricsSummarySection = true
+}
+
+// SetMetricsSummaryLabel overrides the default metrics summary label text.
+func (p *Plugin) SetMetricsSummaryLabel(newLabel string) {
+	p.metricsSummaryLabel = newLabel
+}
+
+// getMetricsSummaryLabelText retrieves the custom metrics summary label text
+// if set, otherwise returns the default value.
+func (p Plugin) getMetricsSummaryLabelText() string {
+	switch {
+	case p.metricsSummaryLabel != "":
+		return p.metricsSummaryLabel
+	default:
+		return defaultMetricsSummaryLabel
+	}
+}
+
+// isMetricsSummaryHidden indicates whether the Metrics Summary section
+// should be omitted from output.
+func (p Plugin) isMetricsSummaryHidden() bool {
+	return p.metricsSummarySectionSkipReason() != ""
+}
+
+// metricsSummarySectionSkipReason returns the reason the Metrics Summary
+// section is omitted from output or an empty string if the section is
+// displayed.
+func (p Plugin) metricsSummarySectionSkipReason() string {
+	switch {
+	case !p.metricsSummarySection:
+		return "metrics summary section not requested"
+	case p.perfData.len() == 0:
+		return "perfdata collection is empty"
+	default:
+		return ""
+	}
+}
+
+// handleMetricsSummarySection is a wrapper around the logic used to
+// handle/process the Metrics Summary section header and listing. The caller
+// is responsible for skipping this section if hidden (see
+// metricsSummarySectionSkipReason).
+func (p Plugin) handleMetricsSummarySection(w io.Writer) {
+	var totalWritten int
+
+	written, err := writeStrings(w,
+		CheckOutputEOL,
+		"**", p.getMetricsSummaryLabelText(), "**",
+		CheckOutputEOL,
+		CheckOutputEOL,
+	)
+	if err != nil {
+		panic("Failed to write metrics summary section label to given output sink")
+	}
+
+	totalWritten += written
+
+	table := p.metricsSummaryTable()
+
+	var content string
+	switch p.outputProfile {
+	case OutputProfileIcingaWebHTML:
+		content = table.HTML()
+	default:
+		content = table.Text(CheckOutputEOL)
+	}
+
+	written, err = writeStrings(w, content, CheckOutputEOL)
+	if err != nil {
+		panic("Failed to write metrics summary section content to given output sink")
+	}
+
+	totalWritten += written
+
+	p.logSectionOutputSize("MetricsSummary", "%d bytes plugin metrics summary section content written to given output sink", totalWritten)
+}
+
+// metricsSummaryTable returns a table listing each collected performance
+// data metric (sorted by label) along with its thresholds and the outcome
+// of evaluating the metric value against those thresholds.
+func (p Plugin) metricsSummaryTable() OutputTable {
+	perfData := p.getSortedPerfData()
+
+	table := OutputTable{
+		Headers: []string{
+			labelText(p.labels.MetricsSummaryMetric, defaultMetricsSummaryMetricLabel),
+			labelText(p.labels.MetricsSummaryValue, defaultMetricsSummaryValueLabel),
+			labelText(p.labels.MetricsSummaryWarning, defaultMetricsSummaryWarningLabel),
+			labelText(p.labels.MetricsSummaryCritical, defaultMetricsSummaryCriticalLabel),
+			labelText(p.labels.MetricsSummaryOutcome, defaultMetricsSummaryOutcomeLabel),
+		},
+		Rows: make([][]string, 0, len(perfData)),
+	}
+
+	noValue := labelText(p.labels.MetricsSummaryNoValue, metricsSummaryNoValue)
+
+	valueOrPlaceholder := func(value string) string {
+		if value == "" {
+			return noValue
+		}
+
+		return value
+	}
+
+	for _, pd := range perfData {
+		table.Rows = append(table.Rows, []string{
+			pd.Label,
+			valueOrPlaceholder(pd.Value + pd.UnitOfMeasurement),
+			valueOrPlaceholder(pd.Warn),
+			valueOrPlaceholder(pd.Crit),
+			p.metricOutcome(pd, noValue),
+		})
+	}
+
+	return table
+}
+
+// metricOutcome returns the state label resulting from evaluating the value
+// of the given performance data metric against its thresholds. A placeholder
+// value is returned if the metric has no thresholds and the UNKNOWN state
+// label is returned if a threshold cannot be parsed.
+func (p Plugin) metricOutcome(pd PerformanceData, noValue string) string {
+	if pd.Warn == "" && pd.Crit == "" {
+		return noValue
+	}
+
+	thresholds := []struct {
+		rangeStr string
+		label    string
+	}{
+		{rangeStr: pd.Crit, label: StateCRITICALLabel},
+		{rangeStr: pd.Warn, label: StateWARNINGLabel},
+	}
+
+	for _, threshold := range thresholds {
+		alert, err := evaluateThreshold(threshold.rangeStr, pd.Value)
+		switch {
+		case err != nil:
+			return fmt.Sprintf(
+				"%s %s",
+				StateUNKNOWNLabel,
+				labelText(p.labels.InvalidThreshold, defaultInvalidThresholdLabel),
+			)
+		case alert:
+			return threshold.label
+		}
+	}
+
+	return StateOKLabel
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_EnableMetricsSummarySection_ListsMetrics(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableMetricsSummarySection()
+	plugin.ServiceOutput = "CRITICAL: datastore usage high"
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "ds01_used", Value: "85", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		nagios.PerformanceData{Label: "ds02_used", Value: "95", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		nagios.PerformanceData{Label: "ds03_used", Value: "10", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		nagios.PerformanceData{Label: "datastores", Value: "3"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	want := strings.Join([]string{
+		"CRITICAL: datastore usage high",
+		"**METRICS SUMMARY**",
+		"",
+		"Metric      Value  Warning  Critical  Outcome",
+		"----------  -----  -------  --------  --------",
+		"datastores  3      -        -         -",
+		"ds01_used   85%    80       90        WARNING",
+		"ds02_used   95%    80       90        CRITICAL",
+		"ds03_used   10%    80       90        OK",
+		" |",
+	}, nagios.CheckOutputEOL)
+
+	got, _, _ := strings.Cut(outputBuffer.String(), " | ")
+	got += " |"
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: metrics summary section listed metrics as expected")
+}
+
+func TestPlugin_EnableMetricsSummarySection_Omitted(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		enable  bool
+		metrics []nagios.PerformanceData
+	}{
+		"option disabled": {
+			metrics: []nagios.PerformanceData{{Label: "datastores", Value: "3"}},
+		},
+		"no metrics collected": {
+			enable: true,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.ServiceOutput = "OK: datastores checked"
+
+			if tt.enable {
+				plugin.EnableMetricsSummarySection()
+			}
+
+			if len(tt.metrics) > 0 {
+				if err := plugin.AddPerfData(false, tt.metrics...); err != nil {
+					t.Fatalf("ERROR: failed to add performance data: %v", err)
+				}
+			}
+
+			plugin.ReturnCheckResults()
+
+			if strings.Contains(outputBuffer.String(), "**METRICS SUMMARY**") {
+				t.Fatalf("ERROR: unexpected metrics summary section in output:\n%s", outputBuffer.String())
+			}
+
+			t.Log("OK: metrics summary section omitted as expected")
+		})
+	}
+}
+
+func TestPlugin_EnableMetricsSummarySection_ReportsInvalidThreshold(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableMetricsSummarySection()
+	plugin.SetMetricsSummaryLabel("METRICS")
+	plugin.SetOutputProfile(nagios.OutputProfileIcingaWebHTML)
+	plugin.ServiceOutput = "UNKNOWN: invalid threshold"
+	plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+	if err := plugin.AddPerfData(true, nagios.PerformanceData{Label: "used", Value: "5", Warn: "10:5"}); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	want := "**METRICS**" + nagios.CheckOutputEOL + nagios.CheckOutputEOL +
+		"<table><thead><tr><th>Metric</th><th>Value</th><th>Warning</th><th>Critical</th><th>Outcome</th></tr></thead>" +
+		"<tbody><tr><td>used</td><td>5</td><td>10:5</td><td>-</td><td>UNKNOWN (invalid threshold)</td></tr></tbody></table>"
+
+	if !strings.Contains(outputBuffer.String(), want) {
+		t.Fatalf("ERROR: want %q in output, got:\n%s", want, outputBuffer.String())
+	}
+
+	t.Log("OK: metrics summary section reported invalid threshold as expected")
+}
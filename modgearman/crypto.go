@@ -0,0 +1,89 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package modgearman
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/base64"
+	"fmt"
+)
+
+// keySize is the AES-256 key size used by mod_gearman.
+const keySize int = 32
+
+// EncodePayload returns the given result data in the mod_gearman transport
+// format. If key is empty the data is base64 encoded only, otherwise it is
+// encrypted using AES-256 (ECB mode, zero padded) prior to base64 encoding.
+func EncodePayload(data []byte, key string) ([]byte, error) {
+	if key != "" {
+		block, err := aes.NewCipher(paddedKey(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare cipher: %w", err)
+		}
+
+		size := block.BlockSize()
+		padded := make([]byte, (len(data)+size-1)/size*size)
+		copy(padded, data)
+
+		// mod_gearman uses ECB mode; each block is encrypted independently.
+		for start := 0; start < len(padded); start += size {
+			block.Encrypt(padded[start:start+size], padded[start:start+size])
+		}
+		data = padded
+	}
+
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(encoded, data)
+
+	return encoded, nil
+}
+
+// DecodePayload reverses EncodePayload. Zero padding added during encryption
+// is removed.
+func DecodePayload(encoded []byte, key string) ([]byte, error) {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+	n, err := base64.StdEncoding.Decode(data, bytes.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %v: %w", err, ErrInvalidPayload)
+	}
+	data = data[:n]
+
+	if key == "" {
+		return data, nil
+	}
+
+	block, err := aes.NewCipher(paddedKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare cipher: %w", err)
+	}
+
+	size := block.BlockSize()
+	if len(data)%size != 0 {
+		return nil, fmt.Errorf(
+			"encrypted payload size %d not a multiple of the block size: %w",
+			len(data),
+			ErrInvalidPayload,
+		)
+	}
+
+	for start := 0; start < len(data); start += size {
+		block.Decrypt(data[start:start+size], data[start:start+size])
+	}
+
+	return bytes.TrimRight(data, "\x00"), nil
+}
+
+// paddedKey returns the given shared key zero padded or truncated to the
+// AES-256 key size.
+func paddedKey(key string) []byte {
+	padded := make([]byte, keySize)
+	copy(padded, key)
+
+	return padded
+}
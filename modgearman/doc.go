@@ -0,0 +1,42 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package modgearman provides a client for submitting passive check results
+to the mod_gearman result queue.
+
+# OVERVIEW
+
+Mod_gearman distributes Nagios and Naemon checks to worker processes using
+a Gearman job server. Workers return check results by submitting background
+jobs to a result queue (check_results by default) which the mod_gearman
+broker module reads and passes to the monitoring core.
+
+This package packages check results rendered by the nagios package (see
+nagios.Plugin.PassiveCheckResult) in the same key=value result format used
+by mod_gearman workers and submits them to the Gearman job server. This
+allows Go plugins to act as lightweight result producers without a
+mod_gearman worker installation.
+
+Results are base64 encoded and (optionally) encrypted using AES-256 in ECB
+mode with the shared key zero padded or truncated to 32 bytes, matching the
+mod_gearman "encryption=yes" and "key=..." settings.
+
+The Client type satisfies the nagios.PassiveSubmitter interface and can be
+used directly with the nagios.Runner type for daemon mode checks.
+
+# HOW TO USE
+
+	client := modgearman.NewClient("gearman.example.com:4730")
+	client.SetEncryptionKey("s3cr3t")
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := client.Submit(ctx, result); err != nil {
+		// handle error
+	}
+*/
+package modgearman
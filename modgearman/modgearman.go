@@ -0,0 +1,301 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package modgearman
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Protocol values used by the Gearman job server and mod_gearman.
+const (
+	// DefaultPort is the default TCP port used by the Gearman job server.
+	DefaultPort string = "4730"
+
+	// DefaultQueue is the default mod_gearman result queue.
+	DefaultQueue string = "check_results"
+
+	// headerSize is the size of a Gearman packet header (magic, type and
+	// data size).
+	headerSize int = 12
+
+	// maxResponseSize is the maximum supported Gearman response data size.
+	maxResponseSize uint32 = 64 * 1024
+
+	// Gearman packet types.
+	packetTypeJobCreated  uint32 = 8
+	packetTypeSubmitJobBG uint32 = 18
+	packetTypeError       uint32 = 19
+
+	// defaultTimeout is used for connecting to and communicating with the
+	// Gearman job server if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+)
+
+// Gearman packet magic codes.
+var (
+	requestMagic  = []byte("\x00REQ")
+	responseMagic = []byte("\x00RES")
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the Gearman job server address was
+	// not specified.
+	ErrMissingAddress = errors.New("Gearman job server address not specified")
+
+	// ErrNoCheckResults indicates that no check results were provided for
+	// submission.
+	ErrNoCheckResults = errors.New("no check results provided")
+
+	// ErrUnexpectedResponse indicates that the Gearman job server returned a
+	// response which could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected Gearman response")
+
+	// ErrJobRejected indicates that the Gearman job server rejected the
+	// submitted job.
+	ErrJobRejected = errors.New("Gearman job rejected")
+
+	// ErrInvalidPayload indicates that a mod_gearman payload could not be
+	// decoded.
+	ErrInvalidPayload = errors.New("invalid mod_gearman payload")
+)
+
+// Client submits passive check results to the mod_gearman result queue.
+type Client struct {
+	// address is the Gearman job server address in host:port format.
+	address string
+
+	// queue is the result queue (Gearman function) name.
+	queue string
+
+	// key is the optional shared encryption key.
+	key string
+
+	// timeout limits the time spent connecting to and communicating with
+	// the Gearman job server.
+	timeout time.Duration
+
+	// dialer is used to establish connections to the Gearman job server.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given Gearman job server
+// address. If a port is not included in the address the default Gearman
+// port is used. Results are submitted unencrypted to the default result
+// queue unless overridden.
+func NewClient(address string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address: address,
+		queue:   DefaultQueue,
+		timeout: defaultTimeout,
+	}
+}
+
+// SetQueue overrides the default result queue. An empty value is ignored.
+func (c *Client) SetQueue(queue string) {
+	if queue == "" {
+		return
+	}
+
+	c.queue = queue
+}
+
+// SetEncryptionKey specifies the shared key used to encrypt results; this
+// must match the mod_gearman key setting. An empty value disables
+// encryption.
+func (c *Client) SetEncryptionKey(key string) {
+	c.key = key
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with the Gearman job server. Non-positive values are
+// ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// Submit sends the given check results to the result queue as background
+// jobs using a single connection. Submission stops at the first rejected
+// job.
+//
+// Submit satisfies the nagios.PassiveSubmitter interface.
+func (c *Client) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	switch {
+	case c.address == "":
+		return ErrMissingAddress
+	case len(results) == 0:
+		return ErrNoCheckResults
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, "tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Gearman job server: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set Gearman connection deadline: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+
+	for _, result := range results {
+		payload, err := EncodePayload(EncodeResult(result), c.key)
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.Write(EncodeSubmitJob(c.queue, payload)); err != nil {
+			return fmt.Errorf(
+				"failed to send check result for %s/%s to Gearman job server: %w",
+				result.HostName,
+				result.ServiceDescription,
+				err,
+			)
+		}
+
+		if err := readJobCreated(reader); err != nil {
+			return fmt.Errorf(
+				"check result for %s/%s: %w",
+				result.HostName,
+				result.ServiceDescription,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// EncodeResult returns the given check result in the key=value result
+// format used by mod_gearman workers. Newlines and backslashes within the
+// output are escaped.
+func EncodeResult(result nagios.PassiveCheckResult) []byte {
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	ts := formatTime(timestamp)
+
+	var buf bytes.Buffer
+
+	buf.WriteString("type=passive\n")
+	buf.WriteString("host_name=" + result.HostName + "\n")
+	if !result.IsHostCheckResult() {
+		buf.WriteString("service_description=" + result.ServiceDescription + "\n")
+	}
+	buf.WriteString("start_time=" + ts + "\n")
+	buf.WriteString("finish_time=" + ts + "\n")
+	buf.WriteString("latency=0.0\n")
+	buf.WriteString("return_code=" + strconv.Itoa(result.ExitStatusCode) + "\n")
+	buf.WriteString("exited_ok=1\n")
+	buf.WriteString("output=" + escapeOutput(result.Output) + "\n")
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
+// EncodeSubmitJob returns a Gearman SUBMIT_JOB_BG request packet for the
+// given function (queue) and workload. The job server assigns a unique ID.
+func EncodeSubmitJob(function string, workload []byte) []byte {
+	data := make([]byte, 0, len(function)+2+len(workload))
+	data = append(data, function...)
+	data = append(data, 0, 0)
+	data = append(data, workload...)
+
+	packet := make([]byte, headerSize, headerSize+len(data))
+	copy(packet, requestMagic)
+	binary.BigEndian.PutUint32(packet[4:], packetTypeSubmitJobBG)
+	binary.BigEndian.PutUint32(packet[8:], uint32(len(data)))
+
+	return append(packet, data...)
+}
+
+// readJobCreated reads a Gearman response packet and returns an error unless
+// it confirms that a job was created.
+func readJobCreated(r io.Reader) error {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("failed to read Gearman response: %w", err)
+	}
+
+	if !bytes.Equal(header[:4], responseMagic) {
+		return fmt.Errorf("invalid response magic %q: %w", header[:4], ErrUnexpectedResponse)
+	}
+
+	packetType := binary.BigEndian.Uint32(header[4:])
+	size := binary.BigEndian.Uint32(header[8:])
+	if size > maxResponseSize {
+		return fmt.Errorf("response size %d exceeds limit: %w", size, ErrUnexpectedResponse)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return fmt.Errorf("failed to read Gearman response: %w", err)
+	}
+
+	switch packetType {
+	case packetTypeJobCreated:
+		return nil
+	case packetTypeError:
+		code, text, _ := bytes.Cut(data, []byte{0})
+		return fmt.Errorf("Gearman job server returned %s (%s): %w", code, text, ErrJobRejected)
+	default:
+		return fmt.Errorf("unexpected response packet type %d: %w", packetType, ErrUnexpectedResponse)
+	}
+}
+
+// formatTime returns the given time in the seconds.microseconds format used
+// by mod_gearman.
+func formatTime(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/int(time.Microsecond))
+}
+
+// escapeOutput escapes backslashes and newlines within the given plugin
+// output so that it fits on the single output line of a result.
+func escapeOutput(output string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"\r", "",
+		"\n", `\n`,
+	)
+
+	return replacer.Replace(output)
+}
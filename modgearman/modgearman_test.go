@@ -0,0 +1,169 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package modgearman
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure Client satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*Client)(nil)
+
+// fakeJobServer accepts a single connection, records the function and
+// workload of each submitted job and replies using the given response
+// packet type.
+func fakeJobServer(t *testing.T, jobs int, responseType uint32) (string, <-chan [][2]string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan [][2]string, 1)
+
+	go func() {
+		var submitted [][2]string
+		defer func() { received <- submitted }()
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for i := 0; i < jobs; i++ {
+			header := make([]byte, headerSize)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+
+			data := make([]byte, binary.BigEndian.Uint32(header[8:]))
+			if _, err := io.ReadFull(conn, data); err != nil {
+				return
+			}
+
+			fields := bytes.SplitN(data, []byte{0}, 3)
+			submitted = append(submitted, [2]string{string(fields[0]), string(fields[2])})
+
+			reply := []byte("H:gearmand:1")
+			if responseType == packetTypeError {
+				reply = []byte("ERR_QUEUE\x00queue full")
+			}
+
+			response := make([]byte, headerSize)
+			copy(response, responseMagic)
+			binary.BigEndian.PutUint32(response[4:], responseType)
+			binary.BigEndian.PutUint32(response[8:], uint32(len(reply)))
+			_, _ = conn.Write(append(response, reply...))
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestClient_Submit_SendsEncryptedResults(t *testing.T) {
+	t.Parallel()
+
+	addr, received := fakeJobServer(t, 2, packetTypeJobCreated)
+
+	client := NewClient(addr)
+	client.SetEncryptionKey("should_be_changed")
+
+	results := []nagios.PassiveCheckResult{
+		{
+			HostName:           "web01",
+			ServiceDescription: "HTTP",
+			ExitStatusCode:     nagios.StateWARNINGExitCode,
+			Output:             "WARNING: slow \nC:\\temp | time=5ms",
+			Timestamp:          time.Unix(1700000000, 250000000),
+		},
+		{HostName: "web01", Output: "UP"},
+	}
+
+	if err := client.Submit(context.Background(), results...); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	jobs := <-received
+	if len(jobs) != 2 {
+		t.Fatalf("ERROR: want 2 jobs, got %d", len(jobs))
+	}
+
+	if jobs[0][0] != DefaultQueue {
+		t.Errorf("ERROR: want queue %q, got %q", DefaultQueue, jobs[0][0])
+	}
+
+	decoded, err := DecodePayload([]byte(jobs[0][1]), "should_be_changed")
+	if err != nil {
+		t.Fatalf("ERROR: failed to decode payload: %v", err)
+	}
+
+	for _, want := range []string{
+		"type=passive\n",
+		"host_name=web01\n",
+		"service_description=HTTP\n",
+		"start_time=1700000000.250000\n",
+		"return_code=1\n",
+		`output=WARNING: slow \nC:\\temp | time=5ms` + "\n",
+	} {
+		if !strings.Contains(string(decoded), want) {
+			t.Errorf("ERROR: result missing %q:\n%s", want, decoded)
+		}
+	}
+
+	hostResult, err := DecodePayload([]byte(jobs[1][1]), "should_be_changed")
+	if err != nil || strings.Contains(string(hostResult), "service_description=") {
+		t.Errorf("ERROR: unexpected host check result %q (%v)", hostResult, err)
+	}
+
+	t.Log("OK: encrypted check results submitted as expected")
+}
+
+func TestClient_Submit_ReportsRejectedJob(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := fakeJobServer(t, 1, packetTypeError)
+
+	err := NewClient(addr).Submit(context.Background(), nagios.PassiveCheckResult{HostName: "web01", Output: "UP"})
+	if !errors.Is(err, ErrJobRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrJobRejected, err)
+	}
+
+	t.Log("OK: rejected job reported as expected")
+}
+
+func TestEncodePayload_UnencryptedIsBase64Only(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := EncodePayload([]byte("type=passive\n"), "")
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode payload: %v", err)
+	}
+
+	if string(encoded) != "dHlwZT1wYXNzaXZlCg==" {
+		t.Fatalf("ERROR: unexpected encoded payload %q", encoded)
+	}
+
+	if _, err := DecodePayload([]byte("dHlwZT1wYXNzaXZlCg=="), "key"); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidPayload, err)
+	}
+
+	t.Log("OK: unencrypted payload encoded as expected")
+}
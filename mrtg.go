@@ -0,0 +1,132 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mrtgUnknownValue is the value used by MRTG external scripts to indicate
+// that a value could not be determined.
+const mrtgUnknownValue string = "UNKNOWN"
+
+// mrtgOptions is the collection of settings used when rendering MRTG output.
+type mrtgOptions struct {
+	// inLabel is the label of the performance data metric used as the
+	// first ("incoming") value.
+	inLabel string
+
+	// outLabel is the label of the performance data metric used as the
+	// second ("outgoing") value.
+	outLabel string
+
+	// uptime is the optional uptime of the target.
+	uptime time.Duration
+
+	// target is the optional name of the target.
+	target string
+}
+
+// SetMRTGMetrics specifies the labels of the performance data metrics used
+// as the two values emitted by the MRTG output format. If out is empty, the
+// second value is always zero.
+func (p *Plugin) SetMRTGMetrics(in string, out string) {
+	p.mrtg.inLabel = in
+	p.mrtg.outLabel = out
+}
+
+// SetMRTGTarget overrides the default target name and specifies the uptime
+// emitted by the MRTG output format. If target is empty, the base name of
+// the plugin executable is used. A zero uptime results in an empty uptime
+// line.
+func (p *Plugin) SetMRTGTarget(target string, uptime time.Duration) {
+	p.mrtg.target = target
+	p.mrtg.uptime = uptime
+}
+
+// MRTGOutput renders the current plugin state using the classic four line
+// MRTG external script format:
+//
+//	<first value>
+//	<second value>
+//	<uptime>
+//	<target name>
+//
+// The values are taken from the performance data metrics specified via
+// SetMRTGMetrics. Missing metrics and metrics with an undetermined value
+// ("U") are emitted as UNKNOWN. Fractional values are truncated as MRTG
+// only supports integer values. The plugin timeout (if armed) is disarmed.
+func (p *Plugin) MRTGOutput() string {
+	p.disarmTimeoutForRender()
+
+	target := p.mrtg.target
+	if target == "" {
+		target = filepath.Base(os.Args[0])
+	}
+
+	out := "0"
+	if p.mrtg.outLabel != "" {
+		out = p.mrtgValue(p.mrtg.outLabel)
+	}
+
+	lines := []string{
+		p.mrtgValue(p.mrtg.inLabel),
+		out,
+		mrtgUptime(p.mrtg.uptime),
+		target,
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// mrtgValue returns the value of the performance data metric with the given
+// label formatted for MRTG.
+func (p *Plugin) mrtgValue(label string) string {
+	pd, ok := p.perfData.get(label)
+	if !ok || label == "" {
+		p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf("MRTG metric %q not found", label))
+
+		return mrtgUnknownValue
+	}
+
+	value, err := strconv.ParseFloat(pd.Value, 64)
+	if err != nil {
+		return mrtgUnknownValue
+	}
+
+	return strconv.FormatInt(int64(value), 10)
+}
+
+// mrtgUptime returns the given uptime in the format commonly used by MRTG
+// (e.g., "3 days, 04:05:06"). An empty string is returned for a zero
+// uptime.
+func mrtgUptime(uptime time.Duration) string {
+	if uptime <= 0 {
+		return ""
+	}
+
+	seconds := int64(uptime / time.Second)
+	days := seconds / 86400
+	seconds %= 86400
+
+	clock := fmt.Sprintf("%02d:%02d:%02d", seconds/3600, (seconds%3600)/60, seconds%60)
+
+	switch days {
+	case 0:
+		return clock
+	case 1:
+		return "1 day, " + clock
+	default:
+		return fmt.Sprintf("%d days, %s", days, clock)
+	}
+}
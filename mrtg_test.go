@@ -0,0 +1,63 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_MRTGOutput(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetOutputFormat(nagios.OutputFormatMRTG)
+	plugin.SetMRTGMetrics("bytes_in", "bytes_out")
+	plugin.SetMRTGTarget("core-switch eth0", 3*24*time.Hour+4*time.Hour+5*time.Minute+6*time.Second)
+	plugin.ServiceOutput = "OK: traffic within thresholds"
+
+	err := plugin.AddPerfData(
+		false,
+		nagios.PerformanceData{Label: "bytes_in", Value: "1024.9", UnitOfMeasurement: "c"},
+		nagios.PerformanceData{Label: "bytes_out", Value: "2048", UnitOfMeasurement: "c"},
+	)
+	if err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	want := "1024\n2048\n3 days, 04:05:06\ncore-switch eth0\n"
+	if got := outputBuffer.String(); got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	t.Log("OK: MRTG output rendered as expected")
+}
+
+func TestPlugin_MRTGOutput_MissingMetrics(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetMRTGMetrics("missing", "")
+	plugin.SetMRTGTarget("router", 0)
+
+	want := "UNKNOWN\n0\n\nrouter\n"
+	if got := plugin.MRTGOutput(); got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	t.Log("OK: missing metrics reported as UNKNOWN as expected")
+}
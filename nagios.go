@@ -67,10 +67,12 @@ const CheckOutputEOL string = " \n"
 
 // Default header text for various sections of the output if not overridden.
 const (
-	defaultThresholdsLabel     string = "THRESHOLDS"
-	defaultErrorsLabel         string = "ERRORS"
-	defaultDetailedInfoLabel   string = "DETAILED INFO"
-	defaultEncodedPayloadLabel string = "ENCODED PAYLOAD"
+	defaultThresholdsLabel          string = "THRESHOLDS"
+	defaultErrorsLabel              string = "ERRORS"
+	defaultDetailedInfoLabel        string = "DETAILED INFO"
+	defaultEncodedPayloadLabel      string = "ENCODED PAYLOAD"
+	defaultMetricsSummaryLabel      string = "METRICS SUMMARY"
+	defaultThresholdViolationsLabel string = "THRESHOLD VIOLATIONS"
 )
 
 // Default performance data metrics emitted if not specified by client code.
@@ -182,6 +184,42 @@ var (
 	// ErrCompressedInputInvalid indicates that given input expected to be in
 	// a compressed format is invalid.
 	ErrCompressedInputInvalid = errors.New("compressed input invalid")
+
+	// ErrPassiveSubmitterMissing indicates that a passive check result
+	// submitter was required but not provided.
+	ErrPassiveSubmitterMissing = errors.New("passive check result submitter not specified")
+
+	// ErrInternalLibraryFailure indicates that this library encountered a
+	// failure while processing plugin output (e.g., payload encoding or
+	// performance data rendering). This error is only recorded if strict
+	// mode is enabled.
+	ErrInternalLibraryFailure = errors.New("internal library failure")
+
+	// ErrInvalidCheckResultJSON indicates that a given check result JSON
+	// document is malformed or uses an unsupported schema version.
+	ErrInvalidCheckResultJSON = errors.New("invalid check result JSON")
+
+	// ErrInvalidDebugLogFileSettings indicates that invalid settings (e.g.,
+	// non-positive maximum size) were given for a debug log file.
+	ErrInvalidDebugLogFileSettings = errors.New("invalid debug log file settings")
+
+	// ErrPayloadSizeLimitExceeded indicates that content was not added to
+	// the payload buffer because the payload size limit would be exceeded.
+	// See PayloadSizeLimitError.
+	ErrPayloadSizeLimitExceeded = errors.New("payload size limit exceeded")
+
+	// ErrSelfTestFailed indicates that a problem was found when validating
+	// the output of a synthetic check result. See SelfTest.
+	ErrSelfTestFailed = errors.New("plugin self-test failed")
+
+	// ErrPluginTimeoutReached indicates that the plugin timeout was reached
+	// (and an UNKNOWN check result emitted) before check results were
+	// returned. See SetTimeout.
+	ErrPluginTimeoutReached = errors.New("plugin timeout reached")
+
+	// ErrInvalidServiceState indicates that a given service state label or
+	// exit code is not supported. See ServiceState.Validate.
+	ErrInvalidServiceState = errors.New("invalid service state")
 )
 
 // ServiceState represents the status label and exit code for a service check.
@@ -215,11 +253,37 @@ type Plugin struct {
 	// enabled).
 	logger *log.Logger
 
+	// debugLogLevel limits debug log entries to the specified level and more
+	// severe levels. A zero value indicates that entries are not filtered
+	// by level.
+	debugLogLevel DebugLogLevel
+
+	// debugLogFilter is an optional function applied to each debug log
+	// message (and string attribute values) before it is written.
+	debugLogFilter DebugLogFilterFunc
+
+	// logHandler optionally receives debug log entries in place of the
+	// plain text logger.
+	logHandler debugLogHandler
+
 	// encodedPayloadBuffer holds a user-specified payload *before* encoding
 	// is performed. If provided, this payload is later encoded and included
 	// in the generated plugin output.
 	encodedPayloadBuffer bytes.Buffer
 
+	// preEncodedPayload is previously encoded payload content (including
+	// delimiters) emitted as-is if the payload buffer is empty. See
+	// SetCheckResult.
+	preEncodedPayload string
+
+	// maxPayloadSize is the user-specified maximum size in bytes of the
+	// payload buffer. If not set DefaultMaxPayloadSize is used.
+	maxPayloadSize int
+
+	// payloadSizeReported is the number of payload size report intervals
+	// the payload buffer size was last reported at.
+	payloadSizeReported int
+
 	// encodedPayloadDelimiterLeft is the user-specified custom encoded
 	// payload delimiter. If not set the default payload left delimiter is
 	// used.
@@ -262,7 +326,7 @@ type Plugin struct {
 
 	// perfData is the collection of zero or more PerformanceData values
 	// generated by the plugin. Each entry in the collection is unique.
-	perfData map[string]PerformanceData
+	perfData perfDataCollection
 
 	// WarningThreshold is the value used to determine when the service check
 	// has crossed between an existing state into a WARNING state. This value
@@ -290,6 +354,51 @@ type Plugin struct {
 	// standard text prior to emitting an encoded payload.
 	encodedPayloadLabel string
 
+	// suggestedActionLabel is optional custom text used in place of the
+	// standard text prior to the remediation hint of a ServiceCheckError.
+	suggestedActionLabel string
+
+	// emptyServiceOutputLabel is optional custom text used in place of the
+	// standard summary text generated for an empty ServiceOutput field.
+	emptyServiceOutputLabel string
+
+	// panicServiceOutputLabel is optional custom text used in place of the
+	// standard summary text generated if a panic is detected.
+	panicServiceOutputLabel string
+
+	// thresholdViolationsLabel is an optional custom label used in place of
+	// the standard text prior to a list of threshold violations.
+	thresholdViolationsLabel string
+
+	// labels is the label bundle most recently given to SetLabels. Labels
+	// without a dedicated field (e.g., table column headers) are read from
+	// this bundle.
+	labels Labels
+
+	// dedupeErrors indicates whether identical error messages are listed
+	// once in the Errors section. See EnableErrorDeduplication.
+	dedupeErrors bool
+
+	// hostCheckMode indicates whether the plugin performs a host check
+	// instead of a service check. See SetHostCheckMode.
+	hostCheckMode bool
+
+	// componentResults is the collection of component results recorded by
+	// client code. See AddResult.
+	componentResults []ComponentResult
+
+	// thresholdViolations is the collection of threshold violations
+	// recorded by EvaluateThreshold.
+	thresholdViolations []ThresholdViolation
+
+	// metricsSummaryLabel is an optional custom label used in place of the
+	// standard text prior to emitting the metrics summary.
+	metricsSummaryLabel string
+
+	// metricsSummarySection indicates whether client code has opted to
+	// generate a metrics summary section from collected performance data.
+	metricsSummarySection bool
+
 	// hideThresholdsSection indicates whether client code has opted to hide
 	// the thresholds section, regardless of whether client code previously
 	// specified values for display.
@@ -300,20 +409,134 @@ type Plugin struct {
 	// values for display.
 	hideErrorsSection bool
 
+	// hideLongServiceOutputOnOK indicates whether client code has opted to
+	// omit LongServiceOutput content if the final plugin state is OK.
+	hideLongServiceOutputOnOK bool
+
+	// hideErrorsAndThresholdsOnOK indicates whether client code has opted
+	// to hide the errors and thresholds sections if the final plugin state
+	// is OK.
+	hideErrorsAndThresholdsOnOK bool
+
 	// shouldSkipOSExit is intended to support tests where actually performing
 	// the final os.Exit(x) call results in a panic (Go 1.16+). If set,
 	// calling os.Exit(x) is skipped and a message is logged to os.Stderr
 	// instead.
 	shouldSkipOSExit bool
 
+	// exitFunc is an optional function called with the plugin exit code in
+	// place of os.Exit.
+	exitFunc func(code int)
+
 	// shouldEmitTotalPluginSizeMetric indicates whether client code has opted
 	// to emit (append) a performance data metric calculating the total plugin
 	// output size.
 	shouldEmitTotalPluginSizeMetric bool
 
+	// strictMode indicates whether client code has opted to treat internal
+	// library failures (e.g., payload encoding or performance data rendering
+	// failures) as an UNKNOWN plugin state.
+	strictMode bool
+
+	// compressedPayload is the payload buffer content compressed while
+	// checking for internal failures (strict mode only). It is reused when
+	// encoding the payload and released once plugin output is processed.
+	compressedPayload *bytes.Buffer
+
+	// unknownOnEmptyServiceOutput indicates whether client code has opted to
+	// replace an empty ServiceOutput field with a generated UNKNOWN summary.
+	unknownOnEmptyServiceOutput bool
+
+	// outputFormat is the format used when rendering plugin output.
+	outputFormat OutputFormat
+
+	// checkmkItem is the optional item (service) name used when rendering
+	// Checkmk local check output.
+	checkmkItem string
+
+	// checkmkPiggyback is the collection of Checkmk local check results
+	// attributed to other (piggyback) hosts, indexed by host name.
+	checkmkPiggyback map[string][]CheckmkResult
+
+	// sensuCheckName is the optional check name used when rendering Sensu
+	// event output.
+	sensuCheckName string
+
+	// sensuEntity is the optional (proxy) entity name used when rendering
+	// Sensu event output.
+	sensuEntity string
+
+	// mrtg is the collection of settings used when rendering MRTG output.
+	mrtg mrtgOptions
+
+	// compatibility is the collection of output handling differences applied
+	// for the selected compatibility mode.
+	compatibility compatibilityQuirks
+
+	// extendedPerfData holds the extended performance data settings. A nil
+	// value indicates that extended performance data is disabled.
+	extendedPerfData *extendedPerfDataOptions
+
+	// timeout is the plugin timeout handling. A nil value indicates that no
+	// plugin timeout is set.
+	timeout *timeoutWatchdog
+
+	// outputProfile is the style used when rendering LongServiceOutput
+	// tables.
+	outputProfile OutputProfile
+
+	// outputTables is the collection of tables rendered after the
+	// LongServiceOutput content.
+	outputTables []OutputTable
+
+	// longServiceOutput is the reader providing LongServiceOutput content
+	// streamed at emit time. A nil value indicates that no reader is set.
+	longServiceOutput *longServiceOutputSource
+
+	// emitHooks is the collection of functions called after plugin output
+	// has been emitted.
+	emitHooks []EmitHookFunc
+
+	// stateHooks is the collection of functions (indexed by plugin state)
+	// called before plugin output is rendered.
+	stateHooks map[int][]StateHookFunc
+
 	// debugLogging is the collection of debug logging options for the plugin.
 	debugLogging debugLoggingOptions
 
+	// cleanups is the collection of cleanup functions registered by client
+	// code. This is used to report cleanup functions which never ran.
+	cleanups []*cleanupRegistration
+
+	// correlationID is the per-run ID included in every debug log entry to
+	// separate entries from overlapping check executions.
+	correlationID string
+
+	// correlationIDAnnotation indicates whether the correlation ID is
+	// recorded as an annotation in structured output.
+	correlationIDAnnotation bool
+
+	// skipDefaultTimeMetric indicates whether client code has opted to
+	// skip emitting the default time metric.
+	skipDefaultTimeMetric bool
+
+	// timeRemainingMetric indicates whether client code has opted to emit a
+	// metric recording the time remaining before the timeout is reached.
+	timeRemainingMetric bool
+
+	// errorClassifier is the optional function used to infer the plugin
+	// exit state from recorded errors.
+	errorClassifier ErrorClassifierFunc
+
+	// crashDumpDir is the directory a crash dump file is written to if a
+	// panic is detected. An empty value indicates that no crash dump is
+	// written.
+	crashDumpDir string
+
+	// debugLogCapture holds recent debug log entries appended to the encoded
+	// payload on non-OK exit (if enabled).
+	debugLogCapture *debugLogRing
+
 	// BrandingCallback is a function that is called before application
 	// termination to emit branding details at the end of the notification.
 	// See also ExitCallBackFunc.
@@ -323,11 +546,23 @@ type Plugin struct {
 // NewPlugin constructs a new Plugin value in the same way that client code
 // has been using this library. We also record a default time performance data
 // metric. This default metric is ignored if supplied by client code.
-func NewPlugin() *Plugin {
+//
+// The given options (if any) are applied to the new Plugin value in the order
+// given. See PluginOption.
+func NewPlugin(opts ...PluginOption) *Plugin {
 	es := Plugin{
 		start:          time.Now(),
 		LastError:      nil,
 		ExitStatusCode: StateOKExitCode,
+		correlationID:  newCorrelationID(),
+	}
+
+	es.applyDebugEnv()
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&es)
+		}
 	}
 
 	return &es
@@ -367,96 +602,225 @@ func NewPlugin() *Plugin {
 // details from the panic instead as a CRITICAL state.
 func (p *Plugin) ReturnCheckResults() {
 
-	var output strings.Builder
-
-	// ##################################################################
-	// Note: fmt.Println() (and fmt.Fprintln()) has the same issue as `\n`:
-	// Nagios seems to interpret them literally instead of emitting an actual
-	// newline. We work around that by using fmt.Fprintf() and fmt.Fprint()
-	// for output that is intended for display within the Nagios web UI.
-	// ##################################################################
-
 	// Check for unhandled panic in client code. If present, override
 	// Plugin and make clear that the client code/plugin crashed.
 	p.logAction("Checking for unhandled panic")
 	if err := recover(); err != nil {
-		p.logAction("Handling panic")
+		p.logActionLevel(DebugLogLevelError, "Handling panic")
+		p.handlePanic(err)
+	}
 
-		p.AddError(fmt.Errorf("%w: %s", ErrPanicDetected, err))
+	p.logAction("No unhandled panic found")
 
-		p.ServiceOutput = fmt.Sprintf(
-			"%s: plugin crash detected. See details via web UI or run plugin manually via CLI.",
-			StateCRITICALLabel,
-		)
+	// Disarm the plugin timeout. If the timeout was already reached an
+	// UNKNOWN check result has been emitted and the process is exiting (or
+	// os.Exit is skipped).
+	if p.finishTimeoutWatchdog() {
+		p.logAction("Plugin timeout already reached, skipping check results")
 
-		// Gather stack trace associated with panic.
-		stackTrace := debug.Stack()
-
-		// Wrap stack trace details in an attempt to prevent these details
-		// from being interpreted as formatting characters when passed through
-		// web UI, text, email, Teams, etc. We use Markdown fenced code blocks
-		// instead of `<pre>` start/end tags because Nagios strips out angle
-		// brackets (due to default `illegal_macro_output_chars` settings).
-		p.LongServiceOutput = fmt.Sprintf(
-			"```%s%s%s%s%s%s```",
-			CheckOutputEOL,
-			err,
-			CheckOutputEOL,
-			CheckOutputEOL,
-			stackTrace,
-			CheckOutputEOL,
-		)
+		return
+	}
+
+	// State hooks and captured debug log entries depend on the final plugin
+	// state.
+	p.finalizeState()
+
+	p.runStateHooks()
+
+	p.appendDebugLogCapture()
+
+	// Stream plugin output to the user-specified or fallback output target
+	// as each section is processed.
+	stream := p.newPluginOutputStream()
 
-		p.ExitStatusCode = StateCRITICALExitCode
+	phaseDone := p.startPhase("Render")
+	p.writeRenderedOutput(stream)
+	phaseDone()
 
+	p.logAction("Processing final plugin output")
+	phaseDone = p.startPhase("Write")
+	p.emitOutput(stream)
+	phaseDone()
+
+	phaseDone = p.startPhase("EmitHooks")
+	p.runEmitHooks()
+	phaseDone()
+
+	p.reportExitDiagnostics()
+
+	exitCode := p.processExitCode()
+
+	switch {
+	case p.exitFunc != nil:
+		p.logAction(fmt.Sprintf("Calling custom exit function with exit code %d", exitCode))
+		p.exitFunc(exitCode)
+	case p.shouldSkipOSExit:
+		p.logAction("Skipping os.Exit call as requested.")
+	default:
+		os.Exit(exitCode)
 	}
+}
 
-	p.logAction("No unhandled panic found")
+// handlePanic overrides exit state details from client code and surfaces
+// details from the given (recovered) panic value instead as a CRITICAL state
+// (DOWN in host check mode).
+func (p *Plugin) handlePanic(err interface{}) {
+	p.AddError(fmt.Errorf("%w: %s", ErrPanicDetected, err))
+
+	p.ExitStatusCode = p.exitState(StateCRITICALExitCode)
+
+	p.ServiceOutput = fmt.Sprintf(
+		"%s: %s",
+		p.stateLabel(p.ExitStatusCode),
+		p.getPanicServiceOutputLabelText(),
+	)
+
+	// Gather stack trace associated with panic.
+	stackTrace := debug.Stack()
+
+	// Wrap stack trace details in an attempt to prevent these details
+	// from being interpreted as formatting characters when passed through
+	// web UI, text, email, Teams, etc. We use Markdown fenced code blocks
+	// instead of `<pre>` start/end tags because Nagios strips out angle
+	// brackets (due to default `illegal_macro_output_chars` settings).
+	p.LongServiceOutput = fmt.Sprintf(
+		"```%s%s%s%s%s%s```",
+		CheckOutputEOL,
+		err,
+		CheckOutputEOL,
+		CheckOutputEOL,
+		stackTrace,
+		CheckOutputEOL,
+	)
+
+	p.handleCrashDump(err, stackTrace)
+}
+
+// assembleOutput processes each output section in turn and returns the
+// collected plugin output. No output is written to the plugin output target.
+func (p *Plugin) assembleOutput() string {
+	var output strings.Builder
+
+	p.writeSections(&output)
+
+	return p.applyCompatibilityEOL(output.String())
+}
+
+// finalizeState applies the library changes to the plugin state (the
+// deprecated LastError field, internal failures in strict mode and an empty
+// ServiceOutput field) ahead of state hooks and debug log capture. These
+// changes are evaluated again by writeSections in case a state hook modified
+// plugin state; otherwise this has no further effect.
+func (p *Plugin) finalizeState() {
+	p.normalizeErrors()
+
+	// The payload content may still change (e.g., via debug log capture);
+	// only the compressed payload retained by writeSections is used.
+	p.checkInternalFailures()
+	p.releaseCompressedPayload()
+
+	p.checkResultsServiceOutput()
+	p.checkEmptyServiceOutput()
+}
 
-	p.logAction("Processing ServiceOutput section")
-	p.handleServiceOutputSection(&output)
+// writeSections processes each output section in turn and writes the plugin
+// output to the given writer. CheckOutputEOL is used for all line endings.
+func (p *Plugin) writeSections(output io.Writer) {
+	// Fold deprecated LastError field into the Errors collection before any
+	// output sections are processed.
+	p.normalizeErrors()
 
-	p.logAction("Processing Errors section")
-	p.handleErrorsSection(&output)
+	// Surface internal failures before any output sections are processed so
+	// that they are included in the errors section.
+	p.checkInternalFailures()
+	defer p.releaseCompressedPayload()
 
-	p.logAction("Processing Thresholds section")
-	p.handleThresholdsSection(&output)
+	// Generate a summary for an empty ServiceOutput field (from component
+	// results or if requested) before the ServiceOutput section is
+	// processed.
+	p.checkResultsServiceOutput()
+	p.checkEmptyServiceOutput()
 
-	p.logAction("Processing LongServiceOutput section")
-	p.handleLongServiceOutput(&output)
+	// ##################################################################
+	// Note: fmt.Println() (and fmt.Fprintln()) has the same issue as `\n`:
+	// Nagios seems to interpret them literally instead of emitting an actual
+	// newline. We work around that by using fmt.Fprintf() and fmt.Fprint()
+	// for output that is intended for display within the Nagios web UI.
+	// ##################################################################
 
-	p.logAction("Processing Encoded Payload section")
-	p.handleEncodedPayload(&output)
+	p.logSectionAction("Processing ServiceOutput section", "ServiceOutput")
+	phaseDone := p.startPhase("ServiceOutput")
+	p.handleServiceOutputSection(output)
+	phaseDone()
+
+	// Hidden sections are skipped before any section content is assembled.
+	phaseDone = p.startPhase("Errors")
+	if reason := p.errorsSectionSkipReason(); reason != "" {
+		p.logSectionSkipped("Errors", reason)
+	} else {
+		p.logSectionAction("Processing Errors section", "Errors")
+		p.handleErrorsSection(output)
+	}
+	phaseDone()
+
+	phaseDone = p.startPhase("ThresholdViolations")
+	if reason := p.thresholdViolationsSectionSkipReason(); reason != "" {
+		p.logSectionSkipped("ThresholdViolations", reason)
+	} else {
+		p.logSectionAction("Processing Threshold Violations section", "ThresholdViolations")
+		p.handleThresholdViolationsSection(output)
+	}
+	phaseDone()
+
+	phaseDone = p.startPhase("Thresholds")
+	if reason := p.thresholdsSectionSkipReason(); reason != "" {
+		p.logSectionSkipped("Thresholds", reason)
+	} else {
+		p.logSectionAction("Processing Thresholds section", "Thresholds")
+		p.handleThresholdsSection(output)
+	}
+	phaseDone()
+
+	p.logSectionAction("Processing LongServiceOutput section", "LongServiceOutput")
+	phaseDone = p.startPhase("LongServiceOutput")
+	p.handleLongServiceOutput(output)
+	phaseDone()
+
+	phaseDone = p.startPhase("MetricsSummary")
+	if reason := p.metricsSummarySectionSkipReason(); reason != "" {
+		p.logSectionSkipped("MetricsSummary", reason)
+	} else {
+		p.logSectionAction("Processing Metrics Summary section", "MetricsSummary")
+		p.handleMetricsSummarySection(output)
+	}
+	phaseDone()
+
+	p.logSectionAction("Processing Encoded Payload section", "EncodedPayload")
+	phaseDone = p.startPhase("EncodedPayload")
+	p.handleEncodedPayload(output)
+	phaseDone()
 
 	// If set, call user-provided branding function before emitting
 	// performance data and exiting application.
 	switch {
 	case p.BrandingCallback != nil:
 		p.logAction("Adding Branding Callback")
-		written, err := fmt.Fprintf(&output, "%s%s%s", CheckOutputEOL, p.BrandingCallback(), CheckOutputEOL)
+		phaseDone = p.startPhase("BrandingCallback")
+		written, err := writeStrings(output, CheckOutputEOL, p.BrandingCallback(), CheckOutputEOL)
 		if err != nil {
 			panic("Failed to write BrandingCallback content to buffer")
 		}
-		p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin BrandingCalling content written to buffer", written))
+		p.logSectionOutputSize("BrandingCallback", "%d bytes plugin BrandingCalling content written to buffer", written)
+		phaseDone()
 
 	default:
 		p.logAction("Branding Callback not requested, skipping")
 	}
 
-	p.logAction("Processing Performance Data section")
-	p.handlePerformanceData(&output)
-
-	// Emit all collected plugin output using user-specified or fallback
-	// output target.
-	p.logAction("Processing final plugin output")
-	p.emitOutput(output.String())
-
-	switch {
-	case p.shouldSkipOSExit:
-		p.logAction("Skipping os.Exit call as requested.")
-	default:
-		os.Exit(p.ExitStatusCode)
-	}
+	p.logSectionAction("Processing Performance Data section", "PerformanceData")
+	phaseDone = p.startPhase("PerformanceData")
+	p.handlePerformanceData(output)
+	phaseDone()
 }
 
 // AddPerfData adds provided performance data to the collection overwriting
@@ -480,19 +844,30 @@ func (p *Plugin) AddPerfData(skipValidate bool, perfData ...PerformanceData) err
 		}
 	}
 
-	if p.perfData == nil {
-		p.perfData = make(map[string]PerformanceData)
-	}
-
 	for _, pd := range perfData {
-		p.perfData[strings.ToLower(pd.Label)] = pd
+		p.perfData.set(pd)
 	}
 
 	return nil
 }
 
+// PerfData returns a copy of the collected performance data metrics sorted
+// by label. This is intended for use by client code (or subpackages)
+// publishing the same metrics to other monitoring systems.
+//
+// The default time metric is not included unless explicitly added by client
+// code; it is generated when plugin output is rendered.
+func (p *Plugin) PerfData() []PerformanceData {
+	return p.getSortedPerfData()
+}
+
 // AddError appends provided errors to the collection.
 //
+// If a given error is (or wraps) a ServiceCheckError, the associated service
+// state is used to escalate the plugin exit state; the exit state is never
+// downgraded. Other errors escalate the plugin exit state if classified by
+// the error classifier (see SetErrorClassifier).
+//
 // NOTE: Deduplication of errors is *not* performed. The caller is responsible
 // for ensuring that a given error is not already recorded in the collection.
 func (p *Plugin) AddError(errs ...error) {
@@ -502,6 +877,8 @@ func (p *Plugin) AddError(errs ...error) {
 		"%d errors added to collection",
 		len(errs),
 	))
+
+	p.escalateStateFromErrors(errs...)
 }
 
 // AddUniqueError appends provided errors to the collection if they are not
@@ -510,18 +887,22 @@ func (p *Plugin) AddError(errs ...error) {
 //
 // Errors are evaluated using case-insensitive string comparison.
 func (p *Plugin) AddUniqueError(errs ...error) {
-	existingErrStrings := make([]string, 0, len(p.Errors))
+	existingErrStrings := make([]string, 0, len(p.Errors)+len(errs))
 	for i := range p.Errors {
-		existingErrStrings[i] = p.Errors[i].Error()
+		if p.Errors[i] != nil {
+			existingErrStrings = append(existingErrStrings, p.Errors[i].Error())
+		}
 	}
 
 	var totalUniqueErrors int
 
 	for _, err := range errs {
-		if inList(err.Error(), existingErrStrings, true) {
+		if err == nil || inList(err.Error(), existingErrStrings, true) {
 			continue
 		}
 		p.Errors = append(p.Errors, err)
+		p.escalateStateFromErrors(err)
+		existingErrStrings = append(existingErrStrings, err.Error())
 		totalUniqueErrors++
 	}
 
@@ -553,7 +934,7 @@ func (p *Plugin) SetOutputTarget(w io.Writer) {
 	if w == nil {
 		// We log using an "filtered" logger call to retain previous behavior
 		// of not emitting a "problem has occurred" message.
-		p.logAction("Specified output target is invalid, falling back to default")
+		p.logActionLevel(DebugLogLevelWarn, "Specified output target is invalid, falling back to default")
 
 		p.outputSink = defaultPluginOutputTarget()
 
@@ -595,6 +976,21 @@ func (p *Plugin) SkipOSExit() {
 	p.shouldSkipOSExit = true
 }
 
+// SetExitFunc overrides the os.Exit(x) call used by ReturnCheckResults to
+// signal the plugin state to Nagios with the given function. The function is
+// called with the exit code the plugin would otherwise have exited with and
+// is called even if SkipOSExit was used. If the function returns,
+// ReturnCheckResults returns normally. The function is also called with the
+// UNKNOWN exit code if the plugin timeout is reached (see SetTimeout); in
+// that case it is called from a separate goroutine. A nil value restores the
+// default behavior.
+//
+// See the nagiostest package for an ExitRecorder suitable for use in tests.
+func (p *Plugin) SetExitFunc(fn func(code int)) {
+	p.logAction("Setting custom exit function as requested")
+	p.exitFunc = fn
+}
+
 // EnablePluginOutputSizePerfDataMetric appends a performance data metric
 // noting the total plugin output size.
 func (p *Plugin) EnablePluginOutputSizePerfDataMetric() {
@@ -608,20 +1004,30 @@ func (p *Plugin) EnablePluginOutputSizePerfDataMetric() {
 // adding any content.
 //
 // The contents of this buffer will be included in the plugin's output as an
-// encoded payload suitable for later retrieval/decoding.
+// encoded payload suitable for later retrieval/decoding. A
+// PayloadSizeLimitError is returned and the payload buffer left unchanged if
+// the payload size limit would be exceeded (see SetMaxPayloadSize).
 func (p *Plugin) SetPayloadBytes(input []byte) (int, error) {
 	p.logAction(fmt.Sprintf(
 		"Overwriting payload buffer with %d bytes input",
 		len(input),
 	))
 
+	if err := p.checkPayloadSize(len(input)); err != nil {
+		return 0, err
+	}
+
 	p.encodedPayloadBuffer.Reset()
+	p.payloadSizeReported = 0
 
 	if len(input) == 0 {
 		return 0, nil
 	}
 
-	return p.encodedPayloadBuffer.Write(input)
+	written, err := p.encodedPayloadBuffer.Write(input)
+	p.reportPayloadSize()
+
+	return written, err
 }
 
 // SetPayloadString uses the given input string to overwrite any existing
@@ -630,20 +1036,30 @@ func (p *Plugin) SetPayloadBytes(input []byte) (int, error) {
 // adding any content.
 //
 // The contents of this buffer will be included in the plugin's output as an
-// encoded payload suitable for later retrieval/decoding.
+// encoded payload suitable for later retrieval/decoding. A
+// PayloadSizeLimitError is returned and the payload buffer left unchanged if
+// the payload size limit would be exceeded (see SetMaxPayloadSize).
 func (p *Plugin) SetPayloadString(input string) (int, error) {
 	p.logAction(fmt.Sprintf(
 		"Overwriting payload buffer with %d bytes input",
 		len(input),
 	))
 
+	if err := p.checkPayloadSize(len(input)); err != nil {
+		return 0, err
+	}
+
 	p.encodedPayloadBuffer.Reset()
+	p.payloadSizeReported = 0
 
 	if len(input) == 0 {
 		return 0, nil
 	}
 
-	return p.encodedPayloadBuffer.WriteString(input)
+	written, err := p.encodedPayloadBuffer.WriteString(input)
+	p.reportPayloadSize()
+
+	return written, err
 }
 
 // AddPayloadBytes appends the given input in bytes to the payload buffer. It
@@ -651,7 +1067,9 @@ func (p *Plugin) SetPayloadString(input string) (int, error) {
 // ignored.
 //
 // The contents of this buffer will be included in the plugin's output as an
-// encoded payload suitable for later retrieval/decoding.
+// encoded payload suitable for later retrieval/decoding. A
+// PayloadSizeLimitError is returned and the payload buffer left unchanged if
+// the payload size limit would be exceeded (see SetMaxPayloadSize).
 func (p *Plugin) AddPayloadBytes(input []byte) (int, error) {
 	if len(input) == 0 {
 		return 0, nil
@@ -662,7 +1080,14 @@ func (p *Plugin) AddPayloadBytes(input []byte) (int, error) {
 		len(input),
 	))
 
-	return p.encodedPayloadBuffer.Write(input)
+	if err := p.checkPayloadSize(p.encodedPayloadBuffer.Len() + len(input)); err != nil {
+		return 0, err
+	}
+
+	written, err := p.encodedPayloadBuffer.Write(input)
+	p.reportPayloadSize()
+
+	return written, err
 }
 
 // AddPayloadString appends the given input string to the payload buffer. It
@@ -670,7 +1095,9 @@ func (p *Plugin) AddPayloadBytes(input []byte) (int, error) {
 // ignored.
 //
 // The contents of this buffer will be included in the plugin's output as an
-// encoded payload suitable for later retrieval/decoding.
+// encoded payload suitable for later retrieval/decoding. A
+// PayloadSizeLimitError is returned and the payload buffer left unchanged if
+// the payload size limit would be exceeded (see SetMaxPayloadSize).
 func (p *Plugin) AddPayloadString(input string) (int, error) {
 	if len(input) == 0 {
 		return 0, nil
@@ -681,7 +1108,14 @@ func (p *Plugin) AddPayloadString(input string) (int, error) {
 		len(input),
 	))
 
-	return p.encodedPayloadBuffer.WriteString(input)
+	if err := p.checkPayloadSize(p.encodedPayloadBuffer.Len() + len(input)); err != nil {
+		return 0, err
+	}
+
+	written, err := p.encodedPayloadBuffer.WriteString(input)
+	p.reportPayloadSize()
+
+	return written, err
 }
 
 // UnencodedPayload returns the payload buffer contents in string format as-is
@@ -702,31 +1136,63 @@ func defaultPluginOutputTarget() io.Writer {
 	return os.Stdout
 }
 
-// emitOutput writes final plugin output to the previously set output target.
-// No further modifications to plugin output are performed.
-func (p Plugin) emitOutput(pluginOutput string) {
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes total plugin output to write", len(pluginOutput)))
-
-	// Emit all collected output using user-specified output target or
-	// fallback to the default if not set.
-	if p.outputSink == nil {
+// newPluginOutputStream returns an outputStream writing to the
+// user-specified plugin output target or the default output target if not
+// set.
+func (p *Plugin) newPluginOutputStream() *outputStream {
+	sink := p.outputSink
+	if sink == nil {
 		p.logAction("Custom plugin output target not set")
 		p.logAction("Falling back to default plugin output target")
-		p.outputSink = defaultPluginOutputTarget()
+		sink = defaultPluginOutputTarget()
 	}
 
-	p.logAction("Writing plugin output")
+	return p.newOutputStreamTo(sink)
+}
 
-	if p.shouldEmitTotalPluginSizeMetric {
-		pluginOutput = addPluginOutputSizeMetric(pluginOutput)
+// newOutputStreamTo returns a new outputStream writing to the given target
+// using the line ending for the configured output format and compatibility
+// mode.
+func (p *Plugin) newOutputStreamTo(sink io.Writer) *outputStream {
+	eol := CheckOutputEOL
+	if p.outputFormat == OutputFormatNagios {
+		eol = p.outputEOL()
 	}
 
+	return newOutputStream(sink, eol)
+}
+
+// writeRenderedOutput writes the plugin output in the configured output
+// format to the given stream.
+//
+// Nagios plugin output sections are streamed as they are processed. Other
+// output formats and Nagios plugin output with the plugin output size metric
+// enabled require the complete plugin output and are rendered before they
+// are written.
+func (p *Plugin) writeRenderedOutput(stream *outputStream) {
+	switch {
+	case p.outputFormat != OutputFormatNagios:
+		stream.writeRendered(p.renderOutput())
+
+	case p.shouldEmitTotalPluginSizeMetric:
+		stream.writeRendered(addPluginOutputSizeMetric(p.assembleOutput(), p.outputEOL()))
+
+	default:
+		p.writeSections(stream)
+	}
+}
+
+// emitOutput flushes plugin output written to the given stream to the
+// plugin output target. No further modifications to plugin output are
+// performed.
+func (p *Plugin) emitOutput(stream *outputStream) {
+	p.logAction("Writing plugin output")
+
 	// Attempt to write to output sink. If this fails, send error to the
 	// default abort message output target. If that fails (however unlikely),
 	// we have bigger problems and should abort.
-	pluginOutputWritten, sinkWriteErr := fmt.Fprint(p.outputSink, pluginOutput)
-	if sinkWriteErr != nil {
-		p.logAction("Failed to write plugin output")
+	if sinkWriteErr := stream.flush(); sinkWriteErr != nil {
+		p.logActionLevel(DebugLogLevelError, "Failed to write plugin output")
 
 		_, stdErrWriteErr := fmt.Fprintf(
 			defaultPluginAbortMessageOutputTarget(),
@@ -738,21 +1204,35 @@ func (p Plugin) emitOutput(pluginOutput string) {
 		}
 	}
 
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes total plugin output written", pluginOutputWritten))
+	if p.debugLogging.pluginOutputSize {
+		p.logPluginOutputSize(
+			fmt.Sprintf("%d bytes total plugin output written", stream.written),
+			logAttr{Key: logAttrBytes, Value: stream.written},
+		)
+	}
+
+	stream.release()
 }
 
 // tryAddDefaultTimeMetric inserts a default `time` performance data metric
 // into the collection IF client code has not already specified such a value
 // AND we have a non-zero start value to use.
 func (p *Plugin) tryAddDefaultTimeMetric() {
+	p.tryAddTimeRemainingMetric()
 
 	// We already have an existing time metric, skip replacing it.
-	if _, hasTimeMetric := p.perfData[defaultTimeMetricLabel]; hasTimeMetric {
+	if p.perfData.has(defaultTimeMetricLabel) {
 		p.logAction("Existing time metric present, skipping replacement")
 
 		return
 	}
 
+	if p.skipDefaultTimeMetric {
+		p.logAction("Default time metric disabled, skipping")
+
+		return
+	}
+
 	// Our Plugin value was not generated from the constructor, so we do
 	// not have an internal plugin start time that we can use to generate a
 	// default time metric.
@@ -762,19 +1242,16 @@ func (p *Plugin) tryAddDefaultTimeMetric() {
 		return
 	}
 
-	if p.perfData == nil {
-		p.perfData = make(map[string]PerformanceData)
-	}
-
-	p.perfData[defaultTimeMetricLabel] = defaultTimeMetric(p.start)
+	p.perfData.set(defaultTimeMetric(p.start))
 
 	p.logAction("Added default time metric to collection")
 }
 
 // addPluginOutputSizeMetric appends a performance data metric to the given
-// input noting the total plugin output size. If the metric is already present
-// the original input is returned unmodified.
-func addPluginOutputSizeMetric(pluginOutput string) string {
+// input noting the total plugin output size. The given newline character(s)
+// terminate the plugin output. If the metric is already present the original
+// input is returned unmodified.
+func addPluginOutputSizeMetric(pluginOutput string, eol string) string {
 	metricLabel := "plugin_output_size"
 
 	// Attempt to prevent adding the same metric twice.
@@ -782,7 +1259,7 @@ func addPluginOutputSizeMetric(pluginOutput string) string {
 		return pluginOutput
 	}
 
-	pluginOutput = strings.TrimSuffix(pluginOutput, CheckOutputEOL)
+	pluginOutput = strings.TrimSuffix(pluginOutput, eol)
 
 	outputSizeMetric := PerformanceData{
 		Label:             metricLabel,
@@ -799,7 +1276,7 @@ func addPluginOutputSizeMetric(pluginOutput string) string {
 
 		// Construct metric using updated length value.
 		outputSizeMetric.Value = strconv.Itoa(length)
-		outputSizeMetricString := outputSizeMetric.String() + CheckOutputEOL
+		outputSizeMetricString := outputSizeMetric.String() + eol
 
 		// Construct modified plugin output using the updated metric.
 		modifiedPluginOutput = pluginOutput + outputSizeMetricString
@@ -0,0 +1,138 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// AssertServiceOutput reports a test failure if the ServiceOutput field of
+// the given plugin does not match the expected value. The failure message
+// includes a diff of the expected and actual values. The result of the
+// comparison is returned so that callers may skip further assertions.
+func AssertServiceOutput(t testing.TB, plugin *nagios.Plugin, want string) bool {
+	t.Helper()
+
+	if d := cmp.Diff(want, plugin.ServiceOutput); d != "" {
+		t.Errorf("ServiceOutput mismatch (-want, +got)\n:%s", d)
+		return false
+	}
+
+	return true
+}
+
+// AssertExitCode reports a test failure if the ExitStatusCode field of the
+// given plugin does not match the expected exit code. The failure message
+// includes the associated state labels (e.g., "WARNING") in addition to the
+// numeric values. The result of the comparison is returned so that callers
+// may skip further assertions.
+func AssertExitCode(t testing.TB, plugin *nagios.Plugin, want int) bool {
+	t.Helper()
+
+	if got := plugin.ExitStatusCode; got != want {
+		t.Errorf(
+			"ExitStatusCode mismatch: want %d (%s), got %d (%s)",
+			want,
+			nagios.ExitCodeToStateLabel(want),
+			got,
+			nagios.ExitCodeToStateLabel(got),
+		)
+		return false
+	}
+
+	return true
+}
+
+// AssertPerfDataPresent reports a test failure if a performance data metric
+// with the given label and value has not been added to the given plugin. If
+// a metric with the given label is found with a different value the failure
+// message includes a diff of the expected and actual metric, otherwise the
+// labels of all recorded metrics are listed. The result of the comparison is
+// returned so that callers may skip further assertions.
+func AssertPerfDataPresent(t testing.TB, plugin *nagios.Plugin, label string, value string) bool {
+	t.Helper()
+
+	perfData := plugin.PerfData()
+
+	labels := make([]string, 0, len(perfData))
+	for _, pd := range perfData {
+		if pd.Label != label {
+			labels = append(labels, pd.Label)
+			continue
+		}
+
+		if pd.Value == value {
+			return true
+		}
+
+		want := pd
+		want.Value = value
+
+		t.Errorf(
+			"performance data %q value mismatch (-want, +got)\n:%s",
+			label,
+			cmp.Diff(want, pd),
+		)
+		return false
+	}
+
+	t.Errorf(
+		"performance data %q not found; recorded labels: %s",
+		label,
+		quoteList(labels),
+	)
+
+	return false
+}
+
+// AssertErrorRecorded reports a test failure if no error in the Errors
+// collection of the given plugin matches the target error as determined by
+// errors.Is. The failure message lists all recorded errors. The result of
+// the comparison is returned so that callers may skip further assertions.
+func AssertErrorRecorded(t testing.TB, plugin *nagios.Plugin, target error) bool {
+	t.Helper()
+
+	recorded := make([]string, 0, len(plugin.Errors))
+	for _, err := range plugin.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+
+		recorded = append(recorded, fmt.Sprintf("%v", err))
+	}
+
+	t.Errorf(
+		"error %q not recorded; recorded errors: %s",
+		target,
+		quoteList(recorded),
+	)
+
+	return false
+}
+
+// quoteList returns the given values as a comma separated list of quoted
+// strings or "none" if the list is empty.
+func quoteList(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// recordingTB records test failures reported by assertion helpers so that
+// failure messages can be evaluated without failing the calling test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertHelpers_PassForMatchingPlugin(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ServiceOutput = "WARNING: disk usage high"
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "used", Value: "91", UnitOfMeasurement: "%"},
+		nagios.PerformanceData{Label: "free", Value: "9", UnitOfMeasurement: "%"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.AddError(fmt.Errorf("volume /data: %w", nagios.ErrInvalidPerformanceDataFormat))
+
+	rec := recordingTB{TB: t}
+
+	results := []bool{
+		AssertServiceOutput(&rec, plugin, "WARNING: disk usage high"),
+		AssertExitCode(&rec, plugin, nagios.StateWARNINGExitCode),
+		AssertPerfDataPresent(&rec, plugin, "used", "91"),
+		AssertErrorRecorded(&rec, plugin, nagios.ErrInvalidPerformanceDataFormat),
+	}
+
+	for i, ok := range results {
+		if !ok {
+			t.Errorf("ERROR: assertion %d unexpectedly failed", i)
+		}
+	}
+
+	if len(rec.failures) != 0 {
+		t.Fatalf("ERROR: unexpected failures reported: %v", rec.failures)
+	}
+
+	t.Log("OK: assertions passed as expected")
+}
+
+func TestAssertHelpers_ReportReadableFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		assert func(tb testing.TB, plugin *nagios.Plugin) bool
+		want   []string
+	}{
+		"service output": {
+			assert: func(tb testing.TB, plugin *nagios.Plugin) bool {
+				return AssertServiceOutput(tb, plugin, "OK: disk usage normal")
+			},
+			want: []string{"ServiceOutput mismatch (-want, +got)", "OK: disk usage normal", "WARNING: disk usage high"},
+		},
+		"exit code": {
+			assert: func(tb testing.TB, plugin *nagios.Plugin) bool {
+				return AssertExitCode(tb, plugin, nagios.StateCRITICALExitCode)
+			},
+			want: []string{"want 2 (CRITICAL), got 1 (WARNING)"},
+		},
+		"perfdata value": {
+			assert: func(tb testing.TB, plugin *nagios.Plugin) bool {
+				return AssertPerfDataPresent(tb, plugin, "used", "95")
+			},
+			want: []string{`performance data "used" value mismatch`, `"95"`, `"91"`},
+		},
+		"perfdata label": {
+			assert: func(tb testing.TB, plugin *nagios.Plugin) bool {
+				return AssertPerfDataPresent(tb, plugin, "inodes", "10")
+			},
+			want: []string{`performance data "inodes" not found; recorded labels: "free", "used"`},
+		},
+		"error": {
+			assert: func(tb testing.TB, plugin *nagios.Plugin) bool {
+				return AssertErrorRecorded(tb, plugin, errors.New("timeout"))
+			},
+			want: []string{`error "timeout" not recorded; recorded errors: "volume /data:`},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := nagios.NewPlugin()
+			plugin.ServiceOutput = "WARNING: disk usage high"
+			plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+
+			if err := plugin.AddPerfData(false,
+				nagios.PerformanceData{Label: "used", Value: "91", UnitOfMeasurement: "%"},
+				nagios.PerformanceData{Label: "free", Value: "9", UnitOfMeasurement: "%"},
+			); err != nil {
+				t.Fatalf("ERROR: failed to add performance data: %v", err)
+			}
+
+			plugin.AddError(fmt.Errorf("volume /data: %w", nagios.ErrInvalidPerformanceDataFormat))
+
+			rec := recordingTB{TB: t}
+
+			if tt.assert(&rec, plugin) {
+				t.Fatal("ERROR: assertion unexpectedly passed")
+			}
+
+			if len(rec.failures) != 1 {
+				t.Fatalf("ERROR: want 1 failure reported, got %d: %v", len(rec.failures), rec.failures)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(rec.failures[0], want) {
+					t.Errorf("ERROR: %q not found in failure message:\n%s", want, rec.failures[0])
+				}
+			}
+
+			t.Log("OK: failure reported as expected")
+		})
+	}
+}
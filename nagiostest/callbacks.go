@@ -0,0 +1,170 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Callback kinds recorded by a CallbackRecorder.
+const (
+	// CallbackKindBranding indicates a call to a branding callback (see
+	// nagios.Plugin.BrandingCallback).
+	CallbackKindBranding string = "branding"
+
+	// CallbackKindEmitHook indicates a call to an emit hook (see
+	// nagios.Plugin.AddEmitHook).
+	CallbackKindEmitHook string = "emit-hook"
+
+	// CallbackKindExit indicates a call to an exit function (see
+	// nagios.Plugin.SetExitFunc).
+	CallbackKindExit string = "exit"
+)
+
+// CallbackCall is a single callback invocation recorded by a
+// CallbackRecorder.
+type CallbackCall struct {
+	// Kind is the kind of callback called (e.g., CallbackKindBranding).
+	Kind string
+
+	// Name is the name given when the callback double was created.
+	Name string
+
+	// Output is the content returned by a branding callback. Empty for
+	// other callback kinds.
+	Output string
+
+	// ExitStatusCode is the plugin exit code observed by an emit hook or
+	// the exit code given to an exit function. Zero for branding callbacks.
+	ExitStatusCode int
+}
+
+// CallbackRecorder provides named callback doubles which record each
+// invocation in a shared, ordered log. This allows tests to assert the
+// content provided by branding callbacks and the order in which branding
+// callbacks, emit hooks and the exit function are called. A CallbackRecorder
+// is safe for concurrent use.
+type CallbackRecorder struct {
+	// mu guards the recorded calls.
+	mu sync.Mutex
+
+	// calls is the collection of recorded calls in invocation order.
+	calls []CallbackCall
+}
+
+// NewCallbackRecorder returns a new CallbackRecorder with no recorded calls.
+func NewCallbackRecorder() *CallbackRecorder {
+	return &CallbackRecorder{}
+}
+
+// Branding returns a branding callback suitable for use as
+// nagios.Plugin.BrandingCallback which records each call under the given
+// name and returns the given content.
+func (r *CallbackRecorder) Branding(name string, content string) nagios.ExitCallBackFunc {
+	return func() string {
+		r.record(CallbackCall{
+			Kind:   CallbackKindBranding,
+			Name:   name,
+			Output: content,
+		})
+
+		return content
+	}
+}
+
+// EmitHook returns an emit hook suitable for use with
+// nagios.Plugin.AddEmitHook which records each call under the given name
+// along with the plugin exit code observed by the hook.
+func (r *CallbackRecorder) EmitHook(name string) nagios.EmitHookFunc {
+	return func(p *nagios.Plugin) {
+		r.record(CallbackCall{
+			Kind:           CallbackKindEmitHook,
+			Name:           name,
+			ExitStatusCode: p.ExitStatusCode,
+		})
+	}
+}
+
+// Exit returns an exit function suitable for use with
+// nagios.Plugin.SetExitFunc which records each call under the given name
+// along with the given exit code. Unlike os.Exit, the function returns
+// normally.
+func (r *CallbackRecorder) Exit(name string) func(code int) {
+	return func(code int) {
+		r.record(CallbackCall{
+			Kind:           CallbackKindExit,
+			Name:           name,
+			ExitStatusCode: code,
+		})
+	}
+}
+
+// Calls returns a copy of the recorded calls in invocation order.
+func (r *CallbackRecorder) Calls() []CallbackCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]CallbackCall, len(r.calls))
+	copy(calls, r.calls)
+
+	return calls
+}
+
+// Names returns the names of the recorded calls in invocation order.
+func (r *CallbackRecorder) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.calls))
+	for _, call := range r.calls {
+		names = append(names, call.Name)
+	}
+
+	return names
+}
+
+// Reset discards all recorded calls.
+func (r *CallbackRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+// record appends the given call to the log.
+func (r *CallbackRecorder) record(call CallbackCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, call)
+}
+
+// AssertCallOrder reports a test failure if the names of the calls recorded
+// by the given CallbackRecorder do not match the given names in order. The
+// failure message includes a diff of the expected and actual call order. The
+// result of the comparison is returned so that callers may skip further
+// assertions.
+func AssertCallOrder(t testing.TB, r *CallbackRecorder, want ...string) bool {
+	t.Helper()
+
+	if want == nil {
+		want = []string{}
+	}
+
+	if d := cmp.Diff(want, r.Names()); d != "" {
+		t.Errorf("callback order mismatch (-want, +got)\n:%s", d)
+		return false
+	}
+
+	return true
+}
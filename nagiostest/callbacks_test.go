@@ -0,0 +1,77 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestCallbackRecorder_RecordsCallbackOrder(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+	recorder := NewCallbackRecorder()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(recorder.Exit("exit"))
+	plugin.BrandingCallback = recorder.Branding("branding", "Notification generated by check-example v1.2.3")
+	plugin.AddEmitHook(recorder.EmitHook("metrics"), recorder.EmitHook("audit"))
+
+	plugin.ServiceOutput = "WARNING: disk usage high"
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ReturnCheckResults()
+
+	AssertCallOrder(t, recorder, "branding", "metrics", "audit", "exit")
+
+	want := []CallbackCall{
+		{Kind: CallbackKindBranding, Name: "branding", Output: "Notification generated by check-example v1.2.3"},
+		{Kind: CallbackKindEmitHook, Name: "metrics", ExitStatusCode: nagios.StateWARNINGExitCode},
+		{Kind: CallbackKindEmitHook, Name: "audit", ExitStatusCode: nagios.StateWARNINGExitCode},
+		{Kind: CallbackKindExit, Name: "exit", ExitStatusCode: nagios.StateWARNINGExitCode},
+	}
+
+	if d := cmp.Diff(want, recorder.Calls()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	if !strings.Contains(outputBuffer.String(), "Notification generated by check-example v1.2.3") {
+		t.Fatalf("ERROR: branding content not found in output:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: callback order recorded as expected")
+}
+
+func TestAssertCallOrder_ReportsMismatch(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewCallbackRecorder()
+	recorder.Branding("branding", "")()
+	recorder.Exit("exit")(0)
+
+	rec := recordingTB{TB: t}
+	if AssertCallOrder(&rec, recorder, "exit", "branding") {
+		t.Fatal("ERROR: assertion unexpectedly passed")
+	}
+
+	if len(rec.failures) != 1 || !strings.Contains(rec.failures[0], "callback order mismatch") {
+		t.Fatalf("ERROR: unexpected failures reported: %v", rec.failures)
+	}
+
+	recorder.Reset()
+	if !AssertCallOrder(&rec, recorder) {
+		t.Fatalf("ERROR: want no calls after reset, got %v", recorder.Names())
+	}
+
+	t.Log("OK: call order mismatch reported as expected")
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package nagiostest provides helpers for testing plugins built using the
+nagios package.
+
+# OVERVIEW
+
+The ExitRecorder type records the exit code a plugin attempted to exit with
+so that tests can assert on the intended plugin state instead of inferring
+it from output text.
+
+The assertion helpers (AssertServiceOutput, AssertExitCode,
+AssertPerfDataPresent and AssertErrorRecorded) compare common plugin
+result fields against expected values and report mismatches with readable
+diffs or a listing of the recorded values.
+
+The CallbackRecorder type provides named doubles for branding callbacks,
+emit hooks and exit functions which record each invocation in a shared log
+so that tests can assert branding content and the order in which callbacks
+are called (see AssertCallOrder).
+
+The golden file helpers (AssertGolden, LoadGolden, WriteGolden) compare
+plugin output against files in the testdata directory of the package under
+test. Comparison is exact since plugin output is sensitive to trailing
+whitespace and line endings; differences are reported with escaped line
+content so that they are visible. Importing this package registers the
+-update test flag; running tests with -update writes golden files instead of
+comparing against them.
+
+The exported fuzz entrypoints (e.g., FuzzParsePerfData, FuzzParseRangeString,
+FuzzExtractAndDecodePayload and FuzzParsePluginOutput) and seed corpus
+helpers (AddSeeds, LoadSeedCorpus and the built-in seed collections) allow
+client code to include the parsers provided by the nagios package in their
+own fuzzing by calling them from a fuzz target.
+
+The round-trip helpers generate random valid performance data metrics and
+threshold ranges (RandomPerformanceData, RandomRange) and check that values
+survive conversion to their string representation and back unchanged
+(CheckPerfDataRoundTrip, CheckRangeRoundTrip). RunPerfDataRoundTrip and
+RunRangeRoundTrip check many generated values using a reproducible seed for
+use in property-based tests.
+
+The RunParallelPlugins helper runs a check function against many plugin
+values concurrently, each with a private output target and exit recorder, so
+that tests running plugins in parallel can be verified using the -race flag.
+Plugin values share no package-level mutable state; a plugin timeout reached
+by one plugin value is reported via its exit function instead of terminating
+the test process.
+
+The MeasureEmit and BenchmarkEmit helpers measure the cost of emitting
+plugin output (duration, plugin output bytes written and allocations) so
+that client code can track regressions in the plugin output path of their
+own plugins. The measured values may be logged (EmitCost.Log) or recorded as
+performance data (EmitCost.PerfData).
+
+# HOW TO USE
+
+	var outputBuffer strings.Builder
+	recorder := nagiostest.NewExitRecorder()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(recorder.Exit)
+
+	runCheck(plugin)
+	plugin.ReturnCheckResults()
+
+	if code, _ := recorder.Code(); code != nagios.StateWARNINGExitCode {
+		t.Errorf("want WARNING exit code, got %d", code)
+	}
+
+	nagiostest.AssertExitCode(t, plugin, nagios.StateWARNINGExitCode)
+	nagiostest.AssertPerfDataPresent(t, plugin, "used", "91")
+
+	nagiostest.AssertGolden(t, "check-warning.txt", outputBuffer.String())
+*/
+package nagiostest
@@ -0,0 +1,164 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Performance data metric labels used by EmitCost.PerfData.
+const (
+	EmitCostTimeLabel           string = "emit_time"
+	EmitCostBytesWrittenLabel   string = "emit_bytes"
+	EmitCostAllocationsLabel    string = "emit_allocs"
+	EmitCostAllocatedBytesLabel string = "emit_alloc_bytes"
+)
+
+// EmitCost is the cost of emitting plugin output as measured by MeasureEmit.
+type EmitCost struct {
+	// Duration is the time spent returning check results (rendering and
+	// writing plugin output, calling emit hooks and the exit function).
+	Duration time.Duration
+
+	// BytesWritten is the number of bytes of plugin output written.
+	BytesWritten int
+
+	// Allocations is the number of heap allocations made while returning
+	// check results.
+	Allocations uint64
+
+	// AllocatedBytes is the number of bytes allocated on the heap while
+	// returning check results.
+	AllocatedBytes uint64
+}
+
+// countingDiscard discards written content while counting the bytes
+// written.
+type countingDiscard struct {
+	written int
+}
+
+func (cd *countingDiscard) Write(p []byte) (int, error) {
+	cd.written += len(p)
+
+	return len(p), nil
+}
+
+// MeasureEmit calls ReturnCheckResults for the given plugin value and
+// returns the cost of emitting the plugin output. Plugin output is
+// discarded; the plugin output target and exit function are replaced and
+// the plugin value should not be used to return check results again.
+//
+// Allocations are measured process-wide; avoid running other goroutines
+// (e.g., parallel tests) while measuring for accurate results.
+func MeasureEmit(tb testing.TB, plugin *nagios.Plugin) EmitCost {
+	tb.Helper()
+
+	var output countingDiscard
+	recorder := NewExitRecorder()
+
+	plugin.SetOutputTarget(&output)
+	plugin.SetExitFunc(recorder.Exit)
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	plugin.ReturnCheckResults()
+
+	duration := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	if !recorder.Called() {
+		tb.Error("plugin did not attempt to exit while returning check results")
+	}
+
+	return EmitCost{
+		Duration:       duration,
+		BytesWritten:   output.written,
+		Allocations:    after.Mallocs - before.Mallocs,
+		AllocatedBytes: after.TotalAlloc - before.TotalAlloc,
+	}
+}
+
+// BenchmarkEmit measures the cost of emitting plugin output for plugin
+// values returned by the given function. The time spent constructing each
+// plugin value is excluded. Allocations are reported along with the number
+// of plugin output bytes written per operation (as "output-B/op") so that
+// regressions in the plugin output path can be tracked using benchmark
+// results.
+func BenchmarkEmit(b *testing.B, newPlugin func() *nagios.Plugin) {
+	b.Helper()
+	b.ReportAllocs()
+
+	var output countingDiscard
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		plugin := newPlugin()
+		plugin.SetOutputTarget(&output)
+		plugin.SetExitFunc(func(int) {})
+		b.StartTimer()
+
+		plugin.ReturnCheckResults()
+	}
+
+	b.ReportMetric(float64(output.written)/float64(b.N), "output-B/op")
+}
+
+// PerfData returns the emit cost as performance data metrics suitable for
+// recording with nagios.Plugin.AddPerfData (e.g., by a later plugin
+// execution or a separate regression tracking check).
+func (c EmitCost) PerfData() []nagios.PerformanceData {
+	return []nagios.PerformanceData{
+		{
+			Label:             EmitCostTimeLabel,
+			Value:             strconv.FormatInt(c.Duration.Microseconds(), 10),
+			UnitOfMeasurement: "us",
+		},
+		{
+			Label:             EmitCostBytesWrittenLabel,
+			Value:             strconv.Itoa(c.BytesWritten),
+			UnitOfMeasurement: "B",
+		},
+		{
+			Label: EmitCostAllocationsLabel,
+			Value: strconv.FormatUint(c.Allocations, 10),
+		},
+		{
+			Label:             EmitCostAllocatedBytesLabel,
+			Value:             strconv.FormatUint(c.AllocatedBytes, 10),
+			UnitOfMeasurement: "B",
+		},
+	}
+}
+
+// String provides a single line summary of the emit cost.
+func (c EmitCost) String() string {
+	return fmt.Sprintf(
+		"emitted %d bytes in %s (%d allocations, %d bytes allocated)",
+		c.BytesWritten,
+		c.Duration,
+		c.Allocations,
+		c.AllocatedBytes,
+	)
+}
+
+// Log records the emit cost as a test log entry.
+func (c EmitCost) Log(tb testing.TB) {
+	tb.Helper()
+
+	tb.Log(c.String())
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestMeasureEmit_ReportsEmitCost(t *testing.T) {
+	// Allocations are measured process-wide; this test is not run in
+	// parallel with other tests.
+
+	longServiceOutput := strings.Repeat("* datastore within threshold"+nagios.CheckOutputEOL, 10)
+	perfData := nagios.PerformanceData{Label: "datastores", Value: "10"}
+
+	var outputBuffer strings.Builder
+	reference := nagios.NewPlugin()
+	reference.ServiceOutput = "OK: all datastores within thresholds"
+	reference.LongServiceOutput = longServiceOutput
+	if err := reference.AddPerfData(false, perfData); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+	reference.SetOutputTarget(&outputBuffer)
+	reference.SetExitFunc(func(int) {})
+	reference.ReturnCheckResults()
+
+	plugin := nagios.NewPlugin()
+	plugin.ServiceOutput = "OK: all datastores within thresholds"
+	plugin.LongServiceOutput = longServiceOutput
+	if err := plugin.AddPerfData(false, perfData); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	cost := MeasureEmit(t, plugin)
+
+	switch {
+	case cost.BytesWritten != len(outputBuffer.String()):
+		t.Errorf("ERROR: want %d bytes written, got %d", len(outputBuffer.String()), cost.BytesWritten)
+	case cost.Duration <= 0:
+		t.Errorf("ERROR: want positive duration, got %s", cost.Duration)
+	case cost.Allocations == 0 || cost.AllocatedBytes == 0:
+		t.Errorf("ERROR: want allocations recorded, got %d (%d bytes)", cost.Allocations, cost.AllocatedBytes)
+	}
+
+	cost.Log(t)
+
+	t.Log("OK: emit cost measured as expected")
+}
+
+func TestEmitCost_PerfDataIsValid(t *testing.T) {
+	t.Parallel()
+
+	cost := EmitCost{
+		Duration:       1500 * time.Microsecond,
+		BytesWritten:   512,
+		Allocations:    12,
+		AllocatedBytes: 4096,
+	}
+
+	plugin := nagios.NewPlugin()
+	if err := plugin.AddPerfData(false, cost.PerfData()...); err != nil {
+		t.Fatalf("ERROR: emit cost performance data invalid: %v", err)
+	}
+
+	AssertPerfDataPresent(t, plugin, EmitCostTimeLabel, "1500")
+	AssertPerfDataPresent(t, plugin, EmitCostBytesWrittenLabel, "512")
+	AssertPerfDataPresent(t, plugin, EmitCostAllocationsLabel, "12")
+	AssertPerfDataPresent(t, plugin, EmitCostAllocatedBytesLabel, "4096")
+
+	t.Log("OK: emit cost performance data recorded as expected")
+}
+
+func BenchmarkEmit_Example(b *testing.B) {
+	BenchmarkEmit(b, func() *nagios.Plugin {
+		plugin := nagios.NewPlugin()
+		plugin.ServiceOutput = "OK: all datastores within thresholds"
+		plugin.LongServiceOutput = strings.Repeat("* datastore within threshold"+nagios.CheckOutputEOL, 10)
+		_ = plugin.AddPerfData(false, nagios.PerformanceData{Label: "datastores", Value: "10"})
+
+		return plugin
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"sync"
+)
+
+// ExitRecorder records the exit code a plugin attempted to exit with. The
+// Exit method is intended for use with nagios.Plugin.SetExitFunc. An
+// ExitRecorder is safe for concurrent use.
+type ExitRecorder struct {
+	// mu guards the recorded values.
+	mu sync.Mutex
+
+	// code is the most recently recorded exit code.
+	code int
+
+	// calls is the number of recorded exit attempts.
+	calls int
+}
+
+// NewExitRecorder returns a new ExitRecorder with no recorded exit attempts.
+func NewExitRecorder() *ExitRecorder {
+	return &ExitRecorder{}
+}
+
+// Exit records the given exit code. Unlike os.Exit, Exit returns normally.
+func (r *ExitRecorder) Exit(code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.code = code
+	r.calls++
+}
+
+// Code returns the most recently recorded exit code and whether an exit
+// attempt was recorded.
+func (r *ExitRecorder) Code() (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.code, r.calls > 0
+}
+
+// Called indicates whether an exit attempt was recorded.
+func (r *ExitRecorder) Called() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.calls > 0
+}
+
+// Calls returns the number of recorded exit attempts. More than one attempt
+// usually indicates that check results were returned more than once.
+func (r *ExitRecorder) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.calls
+}
+
+// Reset discards all recorded exit attempts.
+func (r *ExitRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.code = 0
+	r.calls = 0
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestExitRecorder_RecordsIntendedExitCode(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewExitRecorder()
+
+	if recorder.Called() {
+		t.Fatal("ERROR: new recorder reports exit attempt")
+	}
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(io.Discard)
+	plugin.SetExitFunc(recorder.Exit)
+	plugin.ServiceOutput = "WARNING: disk usage high"
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ReturnCheckResults()
+
+	code, ok := recorder.Code()
+	if !ok {
+		t.Fatal("ERROR: exit attempt not recorded")
+	}
+
+	if code != nagios.StateWARNINGExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, code)
+	}
+
+	if recorder.Calls() != 1 {
+		t.Errorf("ERROR: want 1 exit attempt, got %d", recorder.Calls())
+	}
+
+	t.Log("OK: intended exit code recorded")
+}
+
+func TestExitRecorder_RecordsPanicExitCode(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewExitRecorder()
+
+	func() {
+		plugin := nagios.NewPlugin()
+		plugin.SetOutputTarget(io.Discard)
+		plugin.SetExitFunc(recorder.Exit)
+
+		defer plugin.ReturnCheckResults()
+
+		panic("boom")
+	}()
+
+	if code, _ := recorder.Code(); code != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, code)
+	}
+
+	recorder.Reset()
+
+	if recorder.Called() {
+		t.Error("ERROR: exit attempts not discarded by Reset")
+	}
+
+	t.Log("OK: panic exit code recorded")
+}
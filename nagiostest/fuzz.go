@@ -0,0 +1,212 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// PerfDataSeeds returns a seed corpus of performance data strings covering
+// valid metrics (quoted labels, units, thresholds, undetermined values) and
+// common malformed input.
+func PerfDataSeeds() []string {
+	return []string{
+		"time=874ms;;;;",
+		"load1=0.260;5.000;10.000;0;",
+		"'used space'=91%;80;95;0;100",
+		"'/var/log'=2048MB;4096;8192;0;16384 inodes=12%",
+		"rta=0.123ms;100.000;500.000;0; pl=0%;20;60;0;100",
+		"bytes=U;;;;",
+		"a=1 b=2 c=3",
+		"label=",
+		"=1",
+		"'unterminated=1",
+		"a=1;2;3;4;5;6",
+		";;;;",
+	}
+}
+
+// RangeSeeds returns a seed corpus of threshold range strings covering each
+// range format described by the Nagios plugin guidelines and common
+// malformed input.
+func RangeSeeds() []string {
+	return []string{
+		"10",
+		"10:",
+		"~:10",
+		"10:20",
+		"@10:20",
+		"@~:0",
+		"-5:5",
+		"1e3:1e4",
+		"20:10",
+		"@",
+		"~",
+		":",
+		"abc",
+	}
+}
+
+// PluginOutputSeeds returns a seed corpus of rendered plugin output covering
+// one-line and multi-line output, performance data placement and encoded
+// payloads.
+func PluginOutputSeeds() []string {
+	return []string{
+		"OK: all good",
+		"OK: all good | time=1ms;;;;",
+		"WARNING: disk usage high | used=91%;80;95\nused: 91%\n| free=9%",
+		"CRITICAL: down\nline one\nline two | rta=U;;;;\npl=100%",
+		"OK: payload\n\n**ENCODED PAYLOAD**\n\n<~GhQVk:N4Bl~>\n\n | time=1ms",
+		"UNKNOWN: <~~>",
+		"|",
+		"\n\n",
+	}
+}
+
+// AddSeeds adds each of the given values to the seed corpus of the given
+// fuzz test.
+func AddSeeds(f *testing.F, seeds ...string) {
+	f.Helper()
+
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+}
+
+// LoadSeedCorpus adds the content of each regular file in the given
+// directory (e.g., a testdata directory of sample plugin output) to the seed
+// corpus of the given fuzz test. The test is stopped if the directory cannot
+// be read.
+func LoadSeedCorpus(f *testing.F, dir string) {
+	f.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		f.Fatalf("failed to read seed corpus directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			f.Fatalf("failed to read seed corpus file: %v", err)
+		}
+
+		f.Add(string(data))
+	}
+}
+
+// FuzzParsePerfData fuzzes nagios.ParsePerfData using the PerfDataSeeds seed
+// corpus along with any seeds already added by the caller. Parsing must not
+// panic and successfully parsed metrics must pass validation.
+//
+// Client code may use this as a fuzz target:
+//
+//	func FuzzParsePerfData(f *testing.F) {
+//		nagiostest.FuzzParsePerfData(f)
+//	}
+func FuzzParsePerfData(f *testing.F) {
+	AddSeeds(f, PerfDataSeeds()...)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		perfData, err := nagios.ParsePerfData(input)
+		if err != nil {
+			return
+		}
+
+		for _, pd := range perfData {
+			if err := pd.Validate(); err != nil {
+				t.Errorf("parsed metric %+v from %q fails validation: %v", pd, input, err)
+			}
+		}
+	})
+}
+
+// FuzzParseRangeString fuzzes nagios.ParseRangeString using the RangeSeeds
+// seed corpus along with any seeds already added by the caller. Parsing and
+// evaluating a parsed range must not panic and parsed ranges must have valid
+// boundaries.
+//
+// Client code may use this as a fuzz target:
+//
+//	func FuzzParseRangeString(f *testing.F) {
+//		nagiostest.FuzzParseRangeString(f)
+//	}
+func FuzzParseRangeString(f *testing.F) {
+	AddSeeds(f, RangeSeeds()...)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		r := nagios.ParseRangeString(input)
+		if r == nil {
+			return
+		}
+
+		if !r.StartInfinity && !r.EndInfinity && r.Start > r.End {
+			t.Errorf("parsed range %+v from %q has start greater than end", *r, input)
+		}
+
+		_ = r.CheckRange("0")
+		_ = r.CheckRange(input)
+	})
+}
+
+// FuzzExtractAndDecodePayload fuzzes nagios.ExtractEncodedPayload and
+// nagios.ExtractAndDecodePayload using the PluginOutputSeeds seed corpus
+// (with the default and a custom set of delimiters) along with any seeds
+// already added by the caller. Extraction and decoding must not panic for any
+// combination of input text, custom regular expression and delimiters.
+//
+// Client code may use this as a fuzz target:
+//
+//	func FuzzExtractAndDecodePayload(f *testing.F) {
+//		nagiostest.FuzzExtractAndDecodePayload(f)
+//	}
+func FuzzExtractAndDecodePayload(f *testing.F) {
+	for _, seed := range PluginOutputSeeds() {
+		f.Add(
+			seed,
+			"",
+			nagios.DefaultASCII85EncodingDelimiterLeft,
+			nagios.DefaultASCII85EncodingDelimiterRight,
+		)
+		f.Add(seed, `.*`, "|", "(")
+	}
+
+	f.Fuzz(func(t *testing.T, text string, customRegex string, left string, right string) {
+		_, _ = nagios.ExtractEncodedPayload(text, customRegex, left, right)
+		_, _ = nagios.ExtractAndDecodePayload(text, customRegex, left, right)
+		_, _ = nagios.DecodePayload([]byte(text), left, right)
+	})
+}
+
+// FuzzParsePluginOutput fuzzes nagios.ParsePluginOutput and nagios.LintOutput
+// using the PluginOutputSeeds seed corpus along with any seeds already added
+// by the caller. Parsing and linting must not panic.
+//
+// Client code may use this as a fuzz target, optionally adding their own
+// sample plugin output:
+//
+//	func FuzzParsePluginOutput(f *testing.F) {
+//		nagiostest.LoadSeedCorpus(f, "testdata/output")
+//		nagiostest.FuzzParsePluginOutput(f)
+//	}
+func FuzzParsePluginOutput(f *testing.F) {
+	AddSeeds(f, PluginOutputSeeds()...)
+
+	f.Fuzz(func(t *testing.T, output string) {
+		_, _ = nagios.ParsePluginOutput(output)
+		_ = nagios.LintOutput(output)
+	})
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"testing"
+)
+
+func FuzzPerfData(f *testing.F) {
+	FuzzParsePerfData(f)
+}
+
+func FuzzRangeString(f *testing.F) {
+	FuzzParseRangeString(f)
+}
+
+func FuzzPayload(f *testing.F) {
+	FuzzExtractAndDecodePayload(f)
+}
+
+func FuzzPluginOutput(f *testing.F) {
+	LoadSeedCorpus(f, "testdata")
+	FuzzParsePluginOutput(f)
+}
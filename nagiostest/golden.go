@@ -0,0 +1,168 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	// GoldenDir is the directory (relative to the package under test)
+	// containing golden files.
+	GoldenDir string = "testdata"
+
+	// goldenFilePerms is the permissions used when writing golden files.
+	goldenFilePerms os.FileMode = 0644
+
+	// maxGoldenDiffLines is the maximum number of differing lines reported
+	// when golden file content does not match.
+	maxGoldenDiffLines int = 10
+)
+
+// update indicates whether golden files should be written instead of
+// compared. It is set using the -update test flag.
+var update = flag.Bool("update", false, "update golden files instead of comparing against them")
+
+// UpdateGolden indicates whether the -update flag was given. If set,
+// AssertGolden writes golden files instead of comparing against them.
+func UpdateGolden() bool {
+	return *update
+}
+
+// GoldenPath returns the path of the named golden file.
+func GoldenPath(name string) string {
+	return filepath.Join(GoldenDir, name)
+}
+
+// LoadGolden returns the content of the named golden file as-is (no EOL or
+// trailing whitespace normalization is performed). The test is stopped if
+// the file cannot be read.
+func LoadGolden(t testing.TB, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(GoldenPath(name))
+	if err != nil {
+		t.Fatalf("failed to load golden file: %v", err)
+	}
+
+	return string(data)
+}
+
+// WriteGolden writes the given content to the named golden file, creating
+// the golden file directory if needed. The test is stopped if the file
+// cannot be written.
+func WriteGolden(t testing.TB, name string, content string) {
+	t.Helper()
+
+	path := GoldenPath(name)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create golden file directory: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), goldenFilePerms); err != nil {
+		t.Fatalf("failed to write golden file: %v", err)
+	}
+}
+
+// AssertGolden compares the given plugin output against the named golden
+// file. The comparison is exact; differences in line endings (e.g., "\n" vs
+// "\r\n"), trailing whitespace or a missing final newline are reported with
+// escaped line content so that they are visible. If the -update flag is
+// given the golden file is written with the given output instead.
+func AssertGolden(t testing.TB, name string, got string) {
+	t.Helper()
+
+	if UpdateGolden() {
+		WriteGolden(t, name, got)
+		t.Logf("updated golden file %s", GoldenPath(name))
+
+		return
+	}
+
+	want := LoadGolden(t, name)
+
+	if d := DiffLines(want, got); d != "" {
+		t.Errorf(
+			"output does not match golden file %s (rerun with -update to accept):\n%s",
+			GoldenPath(name),
+			d,
+		)
+	}
+}
+
+// DiffLines returns a line-based description of the differences between want
+// and got or an empty string if they are identical. Line endings are retained
+// and each differing line is shown escaped (e.g., "value  \r\n") so that
+// whitespace and line ending differences are visible.
+func DiffLines(want string, got string) string {
+	if want == got {
+		return ""
+	}
+
+	wantLines := splitLines(want)
+	gotLines := splitLines(got)
+
+	count := len(wantLines)
+	if len(gotLines) > count {
+		count = len(gotLines)
+	}
+
+	var sb strings.Builder
+	var reported int
+
+	for i := 0; i < count; i++ {
+		var wantLine, gotLine string
+		var hasWant, hasGot bool
+
+		if i < len(wantLines) {
+			wantLine, hasWant = wantLines[i], true
+		}
+		if i < len(gotLines) {
+			gotLine, hasGot = gotLines[i], true
+		}
+
+		if hasWant && hasGot && wantLine == gotLine {
+			continue
+		}
+
+		if reported == maxGoldenDiffLines {
+			sb.WriteString("... additional differences omitted\n")
+			break
+		}
+		reported++
+
+		fmt.Fprintf(&sb, "line %d:\n", i+1)
+		if hasWant {
+			fmt.Fprintf(&sb, "  - %q\n", wantLine)
+		}
+		if hasGot {
+			fmt.Fprintf(&sb, "  + %q\n", gotLine)
+		}
+	}
+
+	return sb.String()
+}
+
+// splitLines splits the given text after each newline, retaining line
+// endings.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+
+	// Drop the empty element following a final newline.
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
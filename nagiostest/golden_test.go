@@ -0,0 +1,69 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestAssertGolden_MatchesPluginOutput(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.Plugin{
+		ExitStatusCode: nagios.StateWARNINGExitCode,
+	}
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "WARNING: disk usage high"
+	plugin.LongServiceOutput = "used: 91%"
+	plugin.ReturnCheckResults()
+
+	AssertGolden(t, "golden-plugin-output.txt", outputBuffer.String())
+
+	t.Log("OK: plugin output matches golden file")
+}
+
+func TestDiffLines_ShowsLineEndingDifferences(t *testing.T) {
+	t.Parallel()
+
+	if d := DiffLines("a\nb\n", "a\nb\n"); d != "" {
+		t.Fatalf("ERROR: unexpected diff for identical input:\n%s", d)
+	}
+
+	got := DiffLines("a\nb \nc\n", "a\r\nb\nc")
+
+	for _, want := range []string{
+		"line 1:\n  - \"a\\n\"\n  + \"a\\r\\n\"\n",
+		"line 2:\n  - \"b \\n\"\n  + \"b\\n\"\n",
+		"line 3:\n  - \"c\\n\"\n  + \"c\"\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: %q not found in diff:\n%s", want, got)
+		}
+	}
+
+	t.Log("OK: line ending and whitespace differences shown")
+}
+
+func TestDiffLines_ReportsExtraLines(t *testing.T) {
+	t.Parallel()
+
+	got := DiffLines("a\n", "a\nb\n")
+
+	want := "line 2:\n  + \"b\\n\"\n"
+	if got != want {
+		t.Fatalf("ERROR: want diff %q, got %q", want, got)
+	}
+
+	t.Log("OK: extra lines reported")
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultRoundTripIterations is the number of generated values checked
+	// by RunPerfDataRoundTrip and RunRangeRoundTrip if a non-positive number
+	// of iterations is given.
+	DefaultRoundTripIterations int = 1000
+
+	// maxGeneratedLabelLength is the maximum length of generated
+	// performance data labels.
+	maxGeneratedLabelLength int = 24
+
+	// generatedLabelChars is the set of characters used for generated
+	// performance data labels. Characters which are disallowed in labels or
+	// which separate performance data fields are excluded.
+	generatedLabelChars string = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 _-./%:()[]é"
+)
+
+// generatedUoMs is the collection of units of measurement used for
+// generated performance data metrics.
+var generatedUoMs = []string{"", "s", "ms", "us", "%", "B", "KB", "MB", "GB", "TB", "c"}
+
+// ErrRoundTripMismatch indicates that a value did not survive conversion to
+// its string representation and back unchanged.
+var ErrRoundTripMismatch = errors.New("round-trip mismatch")
+
+// RandomPerformanceData returns a valid performance data metric generated
+// using the given source of randomness. Labels may include spaces and
+// non-ASCII characters, values may be undetermined ("U") and threshold
+// fields use ranges generated by RandomRange.
+func RandomPerformanceData(r *rand.Rand) nagios.PerformanceData {
+	pd := nagios.PerformanceData{
+		Label: randomLabel(r),
+	}
+
+	if r.Intn(10) == 0 {
+		pd.Value = "U"
+	} else {
+		pd.Value = formatNumber(randomNumber(r))
+		pd.UnitOfMeasurement = generatedUoMs[r.Intn(len(generatedUoMs))]
+	}
+
+	if r.Intn(2) == 0 {
+		pd.Warn = RandomRange(r).String()
+	}
+
+	if r.Intn(2) == 0 {
+		pd.Crit = RandomRange(r).String()
+	}
+
+	if r.Intn(2) == 0 {
+		pd.Min = formatNumber(randomNumber(r))
+	}
+
+	if r.Intn(2) == 0 {
+		pd.Max = formatNumber(randomNumber(r))
+	}
+
+	return pd
+}
+
+// RandomRange returns a valid threshold range generated using the given
+// source of randomness. Each range format described by the Nagios plugin
+// guidelines is generated, optionally with an inverted (inside) alert.
+// Fields which do not apply to the generated range (e.g., Start for ranges
+// starting at negative infinity) are left at their zero value.
+func RandomRange(r *rand.Rand) nagios.Range {
+	rng := nagios.Range{AlertOn: "OUTSIDE"}
+	if r.Intn(3) == 0 {
+		rng.AlertOn = "INSIDE"
+	}
+
+	switch r.Intn(5) {
+	// 0 to N (e.g., "10")
+	case 0:
+		rng.End = math.Abs(randomNumber(r))
+
+	// N to positive infinity (e.g., "10:")
+	case 1:
+		rng.Start = randomNumber(r)
+		rng.EndInfinity = true
+
+	// Negative infinity to N (e.g., "~:10")
+	case 2:
+		rng.StartInfinity = true
+		rng.End = randomNumber(r)
+
+	// Negative to positive infinity ("~:")
+	case 3:
+		rng.StartInfinity = true
+		rng.EndInfinity = true
+
+	// N to M (e.g., "10:20")
+	default:
+		a, b := randomNumber(r), randomNumber(r)
+		rng.Start, rng.End = math.Min(a, b), math.Max(a, b)
+	}
+
+	return rng
+}
+
+// CheckPerfDataRoundTrip renders the given performance data metric using
+// its String method, parses the result using nagios.ParsePerfData and
+// returns an error wrapping ErrRoundTripMismatch if the parsed metric does
+// not match the given metric.
+func CheckPerfDataRoundTrip(pd nagios.PerformanceData) error {
+	rendered := pd.String()
+
+	parsed, err := nagios.ParsePerfData(rendered)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"metric %+v rendered as %q failed to parse: %v: %w",
+			pd,
+			rendered,
+			err,
+			ErrRoundTripMismatch,
+		)
+
+	case len(parsed) != 1:
+		return fmt.Errorf(
+			"metric %+v rendered as %q parsed as %d metrics: %w",
+			pd,
+			rendered,
+			len(parsed),
+			ErrRoundTripMismatch,
+		)
+
+	case parsed[0] != pd:
+		return fmt.Errorf(
+			"metric %+v rendered as %q parsed as %+v: %w",
+			pd,
+			rendered,
+			parsed[0],
+			ErrRoundTripMismatch,
+		)
+	}
+
+	return nil
+}
+
+// CheckRangeRoundTrip renders the given threshold range using its String
+// method, parses the result using nagios.ParseRangeString and returns an
+// error wrapping ErrRoundTripMismatch if the parsed range does not match the
+// given range. Fields which do not apply to the given range (e.g., Start for
+// ranges starting at negative infinity) are ignored and an empty AlertOn
+// value is treated as "OUTSIDE".
+func CheckRangeRoundTrip(rng nagios.Range) error {
+	rendered := rng.String()
+
+	parsed := nagios.ParseRangeString(rendered)
+	switch {
+	case parsed == nil:
+		return fmt.Errorf(
+			"range %+v rendered as %q failed to parse: %w",
+			rng,
+			rendered,
+			ErrRoundTripMismatch,
+		)
+
+	case normalizeRange(*parsed) != normalizeRange(rng):
+		return fmt.Errorf(
+			"range %+v rendered as %q parsed as %+v: %w",
+			rng,
+			rendered,
+			*parsed,
+			ErrRoundTripMismatch,
+		)
+	}
+
+	return nil
+}
+
+// RunPerfDataRoundTrip checks the given number of performance data metrics
+// generated by RandomPerformanceData using CheckPerfDataRoundTrip. The
+// generated values are determined by the given seed; the seed is included in
+// the failure message so that failures can be reproduced. The test is marked
+// as failed at the first mismatch.
+func RunPerfDataRoundTrip(t testing.TB, seed int64, iterations int) {
+	t.Helper()
+
+	r := rand.New(rand.NewSource(seed)) // #nosec G404 -- reproducible test data
+
+	for i := 0; i < roundTripIterations(iterations); i++ {
+		if err := CheckPerfDataRoundTrip(RandomPerformanceData(r)); err != nil {
+			t.Errorf("seed %d, iteration %d: %v", seed, i, err)
+			return
+		}
+	}
+}
+
+// RunRangeRoundTrip checks the given number of threshold ranges generated
+// by RandomRange using CheckRangeRoundTrip. The generated values are
+// determined by the given seed; the seed is included in the failure message
+// so that failures can be reproduced. The test is marked as failed at the
+// first mismatch.
+func RunRangeRoundTrip(t testing.TB, seed int64, iterations int) {
+	t.Helper()
+
+	r := rand.New(rand.NewSource(seed)) // #nosec G404 -- reproducible test data
+
+	for i := 0; i < roundTripIterations(iterations); i++ {
+		if err := CheckRangeRoundTrip(RandomRange(r)); err != nil {
+			t.Errorf("seed %d, iteration %d: %v", seed, i, err)
+			return
+		}
+	}
+}
+
+// roundTripIterations returns the given number of iterations or the default
+// if a non-positive value is given.
+func roundTripIterations(iterations int) int {
+	if iterations <= 0 {
+		return DefaultRoundTripIterations
+	}
+
+	return iterations
+}
+
+// normalizeRange returns the given range with fields which do not apply to
+// the range reset to their zero value.
+func normalizeRange(rng nagios.Range) nagios.Range {
+	if rng.AlertOn == "" {
+		rng.AlertOn = "OUTSIDE"
+	}
+
+	if rng.StartInfinity {
+		rng.Start = 0
+	}
+
+	if rng.EndInfinity {
+		rng.End = 0
+	}
+
+	return rng
+}
+
+// randomLabel returns a performance data label without leading or trailing
+// whitespace.
+func randomLabel(r *rand.Rand) string {
+	chars := []rune(generatedLabelChars)
+
+	for {
+		var b strings.Builder
+		length := 1 + r.Intn(maxGeneratedLabelLength)
+		for i := 0; i < length; i++ {
+			b.WriteRune(chars[r.Intn(len(chars))])
+		}
+
+		if label := strings.TrimSpace(b.String()); label != "" {
+			return label
+		}
+	}
+}
+
+// randomNumber returns a positive or negative number with up to three
+// decimal places.
+func randomNumber(r *rand.Rand) float64 {
+	return float64(r.Intn(2000001)-1000000) / math.Pow10(r.Intn(4))
+}
+
+// formatNumber returns the given number using the shortest decimal
+// representation without an exponent.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestRunPerfDataRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, seed := range []int64{1, 42, 20240101} {
+		RunPerfDataRoundTrip(t, seed, 0)
+	}
+
+	t.Log("OK: generated performance data survives round-trip")
+}
+
+func TestRunRangeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, seed := range []int64{1, 42, 20240101} {
+		RunRangeRoundTrip(t, seed, 0)
+	}
+
+	t.Log("OK: generated ranges survive round-trip")
+}
+
+func TestRandomPerformanceData_ProducesValidMetrics(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(7)) // #nosec G404 -- reproducible test data
+
+	var sawSpace, sawUndetermined bool
+	for i := 0; i < DefaultRoundTripIterations; i++ {
+		pd := RandomPerformanceData(r)
+		if err := pd.Validate(); err != nil {
+			t.Fatalf("ERROR: generated metric %+v fails validation: %v", pd, err)
+		}
+
+		sawSpace = sawSpace || strings.Contains(pd.Label, " ")
+		sawUndetermined = sawUndetermined || pd.Value == "U"
+	}
+
+	if !sawSpace || !sawUndetermined {
+		t.Fatalf("ERROR: want labels with spaces and undetermined values generated")
+	}
+
+	t.Log("OK: generated metrics are valid")
+}
+
+func TestCheckRoundTrip_ReportsMismatch(t *testing.T) {
+	t.Parallel()
+
+	// Surrounding whitespace is trimmed when parsing.
+	pd := nagios.PerformanceData{Label: " padded ", Value: "1"}
+	if err := CheckPerfDataRoundTrip(pd); !errors.Is(err, ErrRoundTripMismatch) {
+		t.Errorf("ERROR: want %v for metric, got %v", ErrRoundTripMismatch, err)
+	}
+
+	// Start greater than end is not a valid range.
+	rng := nagios.Range{Start: 20, End: 10}
+	if err := CheckRangeRoundTrip(rng); !errors.Is(err, ErrRoundTripMismatch) {
+		t.Errorf("ERROR: want %v for range, got %v", ErrRoundTripMismatch, err)
+	}
+
+	t.Log("OK: round-trip mismatches reported as expected")
+}
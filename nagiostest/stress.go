@@ -0,0 +1,106 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// DefaultStressInstances is the number of plugin values run concurrently by
+// RunParallelPlugins if a non-positive number of instances is given.
+const DefaultStressInstances int = 100
+
+// StressResult is the outcome of a single plugin value run by
+// RunParallelPlugins.
+type StressResult struct {
+	// Instance is the zero-based index of the plugin value.
+	Instance int
+
+	// Plugin is the plugin value used for this instance.
+	Plugin *nagios.Plugin
+
+	// Output is the plugin output written by the plugin value.
+	Output string
+
+	// ExitCode is the exit code the plugin value attempted to exit with.
+	ExitCode int
+
+	// Exited indicates whether an exit attempt was recorded.
+	Exited bool
+}
+
+// RunParallelPlugins constructs the given number of plugin values and calls
+// the given check function for each of them concurrently, followed by
+// ReturnCheckResults (deferred in the same way as client code). All check
+// functions are released at the same time to maximize overlap. Run tests
+// using this helper with the -race flag to detect unsafe shared state.
+//
+// Each plugin value is given a private output target and an exit function
+// recording the exit code; the check function may replace neither. A test
+// failure is reported for any plugin value which does not attempt to exit
+// exactly once. Results are returned in instance order.
+//
+// Separate plugin values share no mutable state, so the check function may
+// use any Plugin methods. A single plugin value is not safe for concurrent
+// use; the check function should not share its plugin value with other
+// goroutines.
+func RunParallelPlugins(t testing.TB, instances int, check func(instance int, plugin *nagios.Plugin)) []StressResult {
+	t.Helper()
+
+	if instances <= 0 {
+		instances = DefaultStressInstances
+	}
+
+	results := make([]StressResult, instances)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < instances; i++ {
+		wg.Add(1)
+
+		go func(instance int) {
+			defer wg.Done()
+
+			var outputBuffer strings.Builder
+			recorder := NewExitRecorder()
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SetExitFunc(recorder.Exit)
+
+			<-start
+
+			func() {
+				defer plugin.ReturnCheckResults()
+				check(instance, plugin)
+			}()
+
+			code, exited := recorder.Code()
+			if calls := recorder.Calls(); calls != 1 {
+				t.Errorf("instance %d: want 1 exit attempt, got %d", instance, calls)
+			}
+
+			results[instance] = StressResult{
+				Instance: instance,
+				Plugin:   plugin,
+				Output:   outputBuffer.String(),
+				ExitCode: code,
+				Exited:   exited,
+			}
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiostest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestRunParallelPlugins_IsolatesPluginValues(t *testing.T) {
+	t.Parallel()
+
+	states := []nagios.ServiceState{
+		{Label: nagios.StateOKLabel, ExitCode: nagios.StateOKExitCode},
+		{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode},
+		{Label: nagios.StateCRITICALLabel, ExitCode: nagios.StateCRITICALExitCode},
+		{Label: nagios.StateUNKNOWNLabel, ExitCode: nagios.StateUNKNOWNExitCode},
+	}
+
+	errInstance := errors.New("instance failure")
+
+	results := RunParallelPlugins(t, 200, func(instance int, plugin *nagios.Plugin) {
+		state := states[instance%len(states)]
+		id := strconv.Itoa(instance)
+
+		var debugBuffer strings.Builder
+		plugin.SetDebugLoggingOutputTarget(&debugBuffer)
+		plugin.DebugLoggingEnableAll()
+
+		plugin.SetTimeout(time.Minute)
+		plugin.BrandingCallback = func() string { return "branding for instance " + id }
+		plugin.AddEmitHook(func(p *nagios.Plugin) {})
+
+		if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "instance", Value: id}); err != nil {
+			plugin.AddError(err)
+		}
+
+		if _, err := plugin.SetPayloadString(`{"instance":` + id + `}`); err != nil {
+			plugin.AddError(err)
+		}
+
+		if state.ExitCode != nagios.StateOKExitCode {
+			plugin.AddError(fmt.Errorf("%w: %s", errInstance, id))
+		}
+
+		plugin.ServiceOutput = fmt.Sprintf("%s: instance %s", state.Label, id)
+		plugin.ExitStatusCode = state.ExitCode
+	})
+
+	if len(results) != 200 {
+		t.Fatalf("ERROR: want 200 results, got %d", len(results))
+	}
+
+	for i, result := range results {
+		state := states[i%len(states)]
+		id := strconv.Itoa(i)
+
+		switch {
+		case result.Instance != i:
+			t.Errorf("ERROR: want instance %d, got %d", i, result.Instance)
+		case !result.Exited || result.ExitCode != state.ExitCode:
+			t.Errorf("ERROR: instance %d: want exit code %d, got %d (exited: %t)", i, state.ExitCode, result.ExitCode, result.Exited)
+		case !strings.HasPrefix(result.Output, fmt.Sprintf("%s: instance %s", state.Label, id)):
+			t.Errorf("ERROR: instance %d: unexpected output:\n%s", i, result.Output)
+		case !strings.Contains(result.Output, "branding for instance "+id+nagios.CheckOutputEOL):
+			t.Errorf("ERROR: instance %d: branding missing from output:\n%s", i, result.Output)
+		case !strings.Contains(result.Output, "'instance'="+id+";"):
+			t.Errorf("ERROR: instance %d: performance data missing from output:\n%s", i, result.Output)
+		}
+
+		if state.ExitCode != nagios.StateOKExitCode {
+			AssertErrorRecorded(t, result.Plugin, errInstance)
+		}
+	}
+
+	t.Log("OK: parallel plugin values isolated as expected")
+}
+
+func TestRunParallelPlugins_PluginTimeoutUsesExitFunc(t *testing.T) {
+	t.Parallel()
+
+	results := RunParallelPlugins(t, 20, func(instance int, plugin *nagios.Plugin) {
+		plugin.SetTimeout(20 * time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+
+		plugin.ServiceOutput = "OK: too late"
+	})
+
+	for _, result := range results {
+		if result.ExitCode != nagios.StateUNKNOWNExitCode {
+			t.Errorf("ERROR: instance %d: want UNKNOWN exit code, got %d", result.Instance, result.ExitCode)
+		}
+
+		if !strings.HasPrefix(result.Output, "UNKNOWN: plugin timeout") || strings.Contains(result.Output, "too late") {
+			t.Errorf("ERROR: instance %d: unexpected output:\n%s", result.Instance, result.Output)
+		}
+	}
+
+	t.Log("OK: plugin timeout handled via exit function without terminating the test process")
+}
+
+func TestRunParallelPlugins_ReportsPanicsAsCritical(t *testing.T) {
+	t.Parallel()
+
+	results := RunParallelPlugins(t, 10, func(instance int, plugin *nagios.Plugin) {
+		if instance%2 == 0 {
+			panic(fmt.Sprintf("instance %d crashed", instance))
+		}
+
+		plugin.ServiceOutput = "OK: no crash"
+	})
+
+	for _, result := range results {
+		want := nagios.StateOKExitCode
+		if result.Instance%2 == 0 {
+			want = nagios.StateCRITICALExitCode
+			AssertErrorRecorded(t, result.Plugin, nagios.ErrPanicDetected)
+		}
+
+		if result.ExitCode != want {
+			t.Errorf("ERROR: instance %d: want exit code %d, got %d", result.Instance, want, result.ExitCode)
+		}
+	}
+
+	t.Log("OK: panics in parallel plugin values reported as expected")
+}
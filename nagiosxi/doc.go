@@ -0,0 +1,32 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package nagiosxi provides a minimal Nagios XI API client for retrieving
+stored plugin output.
+
+# OVERVIEW
+
+Plugins using the encoded payload feature of the nagios package embed
+(compressed, ASCII85 encoded) data within the plugin output for later
+retrieval. This package retrieves the most recent output for a service
+using the Nagios XI REST API (objects/servicestatus endpoint) and extracts
+& decodes the embedded payload using nagios.ExtractAndDecodePayload.
+
+# HOW TO USE
+
+	client := nagiosxi.NewClient("https://xi.example.com/nagiosxi", "api-key")
+
+	payload, err := client.ServicePayload(ctx, "web01", "HTTP")
+	if err != nil {
+		// handle error
+	}
+
+Use SetPayloadDelimiters if custom delimiters were used when the payload
+was added to the plugin output.
+*/
+package nagiosxi
@@ -0,0 +1,216 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiosxi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// serviceStatusPath is the API path used to retrieve service status
+	// details.
+	serviceStatusPath string = "/api/v1/objects/servicestatus"
+
+	// defaultTimeout is the timeout used by the default HTTP client.
+	defaultTimeout time.Duration = 30 * time.Second
+
+	// maxResponseBodySize is the maximum number of bytes read from an API
+	// response body.
+	maxResponseBodySize int64 = 10 * 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingURL indicates that the Nagios XI URL was not provided.
+	ErrMissingURL = errors.New("Nagios XI URL not specified")
+
+	// ErrMissingAPIKey indicates that the Nagios XI API key was not
+	// provided.
+	ErrMissingAPIKey = errors.New("Nagios XI API key not specified")
+
+	// ErrUnexpectedResponse indicates that the API returned a response which
+	// could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected Nagios XI API response")
+
+	// ErrRequestRejected indicates that the API rejected the request (e.g.,
+	// invalid API key).
+	ErrRequestRejected = errors.New("Nagios XI API request rejected")
+
+	// ErrServiceNotFound indicates that no status details were found for
+	// the requested service.
+	ErrServiceNotFound = errors.New("service not found")
+)
+
+// Client retrieves stored plugin output from the Nagios XI API.
+type Client struct {
+	// endpoint is the Nagios XI base URL (e.g.,
+	// https://xi.example.com/nagiosxi).
+	endpoint string
+
+	// apiKey is the Nagios XI API key.
+	apiKey string
+
+	// leftDelimiter is the left delimiter used to extract encoded payloads.
+	leftDelimiter string
+
+	// rightDelimiter is the right delimiter used to extract encoded
+	// payloads.
+	rightDelimiter string
+
+	// httpClient is used to perform requests.
+	httpClient *http.Client
+}
+
+// NewClient constructs a new Client for the given Nagios XI base URL and API
+// key. The default payload delimiters provided by the nagios package are
+// used to extract encoded payloads.
+func NewClient(endpoint string, apiKey string) *Client {
+	return &Client{
+		endpoint:       strings.TrimRight(endpoint, "/"),
+		apiKey:         apiKey,
+		leftDelimiter:  nagios.DefaultASCII85EncodingDelimiterLeft,
+		rightDelimiter: nagios.DefaultASCII85EncodingDelimiterRight,
+		httpClient:     &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// SetPayloadDelimiters overrides the default delimiters used to extract
+// encoded payloads from retrieved plugin output.
+func (c *Client) SetPayloadDelimiters(left string, right string) {
+	c.leftDelimiter = left
+	c.rightDelimiter = right
+}
+
+// SetHTTPClient overrides the default HTTP client (e.g., to provide custom
+// TLS settings). A nil value is ignored.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		return
+	}
+
+	c.httpClient = client
+}
+
+// serviceStatusResponse is the response body returned by the servicestatus
+// endpoint.
+type serviceStatusResponse struct {
+	ServiceStatus []serviceStatus `json:"servicestatus"`
+
+	// Error is set for failed requests.
+	Error string `json:"error"`
+}
+
+// serviceStatus is the status details for a single service.
+type serviceStatus struct {
+	HostName           string `json:"host_name"`
+	ServiceDescription string `json:"name"`
+	Output             string `json:"output"`
+	LongOutput         string `json:"long_output"`
+	PerfData           string `json:"perfdata"`
+}
+
+// ServiceOutput retrieves the most recent plugin output for the given host
+// and service. The returned value is the one-line summary followed by the
+// long output (if any) separated by a newline. Performance data is not
+// included.
+func (c *Client) ServiceOutput(ctx context.Context, host string, service string) (string, error) {
+	switch {
+	case strings.TrimSpace(c.endpoint) == "":
+		return "", ErrMissingURL
+	case c.apiKey == "":
+		return "", ErrMissingAPIKey
+	}
+
+	query := url.Values{}
+	query.Set("apikey", c.apiKey)
+	query.Set("host_name", host)
+	query.Set("service_description", service)
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.endpoint+serviceStatusPath+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare Nagios XI API request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query Nagios XI API: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read Nagios XI API response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(
+			"Nagios XI API returned HTTP status %q: %w",
+			resp.Status,
+			ErrRequestRejected,
+		)
+	}
+
+	var status serviceStatusResponse
+	if err := json.Unmarshal(body, &status); err != nil {
+		return "", fmt.Errorf("failed to decode JSON response: %v: %w", err, ErrUnexpectedResponse)
+	}
+
+	if status.Error != "" {
+		return "", fmt.Errorf(
+			"Nagios XI API returned error %q: %w",
+			status.Error,
+			ErrRequestRejected,
+		)
+	}
+
+	// Filtering is performed by the API, but we assert an exact match to
+	// guard against API versions which treat parameters as patterns.
+	for _, s := range status.ServiceStatus {
+		if s.HostName != host || s.ServiceDescription != service {
+			continue
+		}
+
+		if s.LongOutput == "" {
+			return s.Output, nil
+		}
+
+		return s.Output + nagios.CheckOutputEOL + s.LongOutput, nil
+	}
+
+	return "", fmt.Errorf("host %q, service %q: %w", host, service, ErrServiceNotFound)
+}
+
+// ServicePayload retrieves the most recent plugin output for the given host
+// and service and returns the extracted and decoded encoded payload.
+func (c *Client) ServicePayload(ctx context.Context, host string, service string) (string, error) {
+	output, err := c.ServiceOutput(ctx, host, service)
+	if err != nil {
+		return "", err
+	}
+
+	return nagios.ExtractAndDecodePayload(output, "", c.leftDelimiter, c.rightDelimiter)
+}
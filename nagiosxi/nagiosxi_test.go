@@ -0,0 +1,112 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagiosxi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func newTestServer(t *testing.T, longOutput string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != serviceStatusPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.URL.Query().Get("apikey") != "s3cr3t" {
+			_, _ = fmt.Fprint(w, `{"error":"Invalid API Key"}`)
+			return
+		}
+
+		resp := serviceStatusResponse{
+			ServiceStatus: []serviceStatus{
+				{
+					HostName:           r.URL.Query().Get("host_name"),
+					ServiceDescription: r.URL.Query().Get("service_description"),
+					Output:             "OK: all good",
+					LongOutput:         longOutput,
+					PerfData:           "'time'=5ms;;;;",
+				},
+			},
+		}
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("ERROR: failed to encode response: %v", err)
+		}
+	}))
+}
+
+func TestClient_ServicePayload_ReturnsDecodedPayload(t *testing.T) {
+	t.Parallel()
+
+	want := `{"Age":17,"Interests":["books","games"]}`
+
+	encoded := nagios.EncodePayload(
+		[]byte(want),
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+
+	server := newTestServer(t, "detailed text\n"+encoded+"\n")
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "s3cr3t")
+
+	got, err := client.ServicePayload(context.Background(), "web01", "HTTP")
+	if err != nil {
+		t.Fatalf("ERROR: unexpected failure retrieving payload: %v", err)
+	}
+
+	if got != want {
+		t.Fatalf("ERROR: want payload %q, got %q", want, got)
+	}
+
+	t.Log("OK: payload retrieved and decoded as expected")
+}
+
+func TestClient_ServiceOutput_ReportsInvalidAPIKey(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, "")
+	defer server.Close()
+
+	client := NewClient(server.URL, "wrong")
+
+	_, err := client.ServiceOutput(context.Background(), "web01", "HTTP")
+	if !errors.Is(err, ErrRequestRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrRequestRejected, err)
+	}
+
+	t.Log("OK: invalid API key reported as expected")
+}
+
+func TestClient_ServicePayload_ReportsMissingPayload(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, "detailed text without payload")
+	defer server.Close()
+
+	client := NewClient(server.URL, "s3cr3t")
+
+	_, err := client.ServicePayload(context.Background(), "web01", "HTTP")
+	if !errors.Is(err, nagios.ErrEncodedPayloadNotFound) {
+		t.Fatalf("ERROR: want %v, got %v", nagios.ErrEncodedPayloadNotFound, err)
+	}
+
+	t.Log("OK: missing payload reported as expected")
+}
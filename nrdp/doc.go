@@ -0,0 +1,44 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package nrdp provides a client for submitting passive check results to a
+Nagios Remote Data Processor (NRDP) endpoint.
+
+# OVERVIEW
+
+NRDP accepts check results over HTTP(S) using token authentication. This
+package submits check results rendered by the nagios package (see
+nagios.Plugin.PassiveCheckResult) as either XML or JSON request bodies,
+optionally splitting large submissions into multiple batches.
+
+The Client type satisfies the nagios.PassiveSubmitter interface and can be
+used directly with the nagios.Runner type for daemon mode checks.
+
+# HOW TO USE
+
+	client := nrdp.NewClient("https://nagios.example.com/nrdp/", "s3cr3t")
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := client.Submit(ctx, result); err != nil {
+		// handle error
+	}
+
+# TESTING
+
+The FakeServer type provides an in-memory NRDP endpoint for integration
+testing code which submits passive check results without a running Nagios
+instance. Submitted check results are recorded and rejected submissions may
+be simulated.
+
+	server := nrdp.NewFakeServer("s3cr3t")
+	defer server.Close()
+
+	client := nrdp.NewClient(server.URL(), "s3cr3t")
+	// submit check results, then inspect server.Results()
+*/
+package nrdp
@@ -0,0 +1,265 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nrdp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// fakeStatusOK is the NRDP response status for accepted submissions.
+	fakeStatusOK int = 0
+
+	// fakeStatusFailed is the NRDP response status for rejected
+	// submissions.
+	fakeStatusFailed int = -1
+
+	// fakeMessageOK is the NRDP response message for accepted submissions.
+	fakeMessageOK string = "OK"
+
+	// fakeMessageBadToken is the NRDP response message returned when an
+	// invalid token is provided.
+	fakeMessageBadToken string = "BAD TOKEN"
+
+	// fakeMessageBadCommand is the NRDP response message returned when an
+	// unsupported command is requested.
+	fakeMessageBadCommand string = "BAD COMMAND"
+
+	// fakeMessageBadData is the NRDP response message returned when the
+	// submitted check results cannot be decoded.
+	fakeMessageBadData string = "BAD DATA"
+)
+
+// FakeServer is an in-memory NRDP endpoint intended for use by client code
+// integration tests. Submitted check results are decoded and recorded
+// instead of being processed by a monitoring system.
+//
+// The endpoint accepts the same XMLDATA and JSONDATA request parameters as a
+// real NRDP endpoint and responds using the NRDP XML response format.
+type FakeServer struct {
+	// mu guards the recorded check results and response settings.
+	mu sync.Mutex
+
+	// server is the underlying HTTP test server.
+	server *httptest.Server
+
+	// token is the authentication token accepted by the endpoint.
+	token string
+
+	// results is the collection of accepted check results.
+	results []nagios.PassiveCheckResult
+
+	// requests is the number of requests received by the endpoint.
+	requests int
+
+	// rejectMessage is the message returned for all submissions if set.
+	rejectMessage string
+
+	// httpStatus is the HTTP status code returned for all submissions if
+	// set.
+	httpStatus int
+}
+
+// NewFakeServer starts and returns a new FakeServer which accepts
+// submissions using the given authentication token. The caller is
+// responsible for calling Close when finished.
+func NewFakeServer(token string) *FakeServer {
+	fs := FakeServer{token: token}
+	fs.server = httptest.NewServer(http.HandlerFunc(fs.handle))
+
+	return &fs
+}
+
+// URL returns the NRDP endpoint URL for use with NewClient.
+func (fs *FakeServer) URL() string {
+	return fs.server.URL + "/nrdp/"
+}
+
+// Close shuts down the endpoint and blocks until all outstanding requests
+// have completed.
+func (fs *FakeServer) Close() {
+	fs.server.Close()
+}
+
+// Results returns a copy of the check results accepted by the endpoint in
+// the order received.
+func (fs *FakeServer) Results() []nagios.PassiveCheckResult {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	results := make([]nagios.PassiveCheckResult, len(fs.results))
+	copy(results, fs.results)
+
+	return results
+}
+
+// Requests returns the number of requests received by the endpoint.
+func (fs *FakeServer) Requests() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.requests
+}
+
+// Reject causes the endpoint to reject all further submissions with the
+// given NRDP response message. An empty message restores the default
+// behavior of accepting submissions.
+func (fs *FakeServer) Reject(message string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.rejectMessage = message
+}
+
+// SetHTTPStatus causes the endpoint to respond to all further submissions
+// with the given HTTP status code (e.g., to simulate a misconfigured web
+// server). A zero value restores the default behavior.
+func (fs *FakeServer) SetHTTPStatus(code int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.httpStatus = code
+}
+
+// Reset discards all recorded check results and restores the default
+// response behavior.
+func (fs *FakeServer) Reset() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.results = nil
+	fs.requests = 0
+	fs.rejectMessage = ""
+	fs.httpStatus = 0
+}
+
+// handle processes a single NRDP request.
+func (fs *FakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.requests++
+
+	if fs.httpStatus != 0 {
+		w.WriteHeader(fs.httpStatus)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeFakeResponse(w, fakeStatusFailed, fakeMessageBadData)
+		return
+	}
+
+	switch {
+	case r.PostForm.Get("token") != fs.token:
+		writeFakeResponse(w, fakeStatusFailed, fakeMessageBadToken)
+		return
+	case r.PostForm.Get("cmd") != submitCheckCommand:
+		writeFakeResponse(w, fakeStatusFailed, fakeMessageBadCommand)
+		return
+	case fs.rejectMessage != "":
+		writeFakeResponse(w, fakeStatusFailed, fs.rejectMessage)
+		return
+	}
+
+	var results []nagios.PassiveCheckResult
+	var err error
+
+	switch {
+	case r.PostForm.Has("JSONDATA"):
+		results, err = decodeFakeJSON([]byte(r.PostForm.Get("JSONDATA")))
+	default:
+		results, err = decodeFakeXML([]byte(r.PostForm.Get("XMLDATA")))
+	}
+
+	if err != nil {
+		writeFakeResponse(w, fakeStatusFailed, fakeMessageBadData)
+		return
+	}
+
+	fs.results = append(fs.results, results...)
+
+	writeFakeResponse(w, fakeStatusOK, fakeMessageOK)
+}
+
+// writeFakeResponse writes an NRDP XML response using the given status and
+// message.
+func writeFakeResponse(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+
+	data, err := xml.Marshal(xmlResponse{Status: status, Message: message})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write(append([]byte(xml.Header), data...))
+}
+
+// decodeFakeXML decodes the given XMLDATA request parameter.
+func decodeFakeXML(data []byte) ([]nagios.PassiveCheckResult, error) {
+	var doc xmlCheckResults
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode XML check results: %w", err)
+	}
+
+	results := make([]nagios.PassiveCheckResult, 0, len(doc.CheckResults))
+	for _, cr := range doc.CheckResults {
+		results = append(results, fakeResult(cr.Type, cr.HostName, cr.ServiceName, cr.State, cr.Output))
+	}
+
+	return results, nil
+}
+
+// decodeFakeJSON decodes the given JSONDATA request parameter.
+func decodeFakeJSON(data []byte) ([]nagios.PassiveCheckResult, error) {
+	var doc jsonCheckResults
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON check results: %w", err)
+	}
+
+	results := make([]nagios.PassiveCheckResult, 0, len(doc.CheckResults))
+	for _, cr := range doc.CheckResults {
+		state, err := strconv.Atoi(cr.State)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state %q: %w", cr.State, err)
+		}
+
+		results = append(results, fakeResult(cr.CheckResult.Type, cr.HostName, cr.ServiceName, state, cr.Output))
+	}
+
+	return results, nil
+}
+
+// fakeResult returns a check result using the given decoded values. The
+// service name is discarded for host check results.
+func fakeResult(resultType string, host string, service string, state int, output string) nagios.PassiveCheckResult {
+	if resultType == checkResultTypeHost {
+		service = ""
+	}
+
+	return nagios.PassiveCheckResult{
+		HostName:           host,
+		ServiceDescription: service,
+		ExitStatusCode:     state,
+		Output:             output,
+	}
+}
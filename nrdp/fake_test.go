@@ -0,0 +1,124 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nrdp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestFakeServer_RecordsSubmittedResults(t *testing.T) {
+	t.Parallel()
+
+	results := []nagios.PassiveCheckResult{
+		{HostName: "web01", ExitStatusCode: nagios.StateOKExitCode, Output: "UP"},
+		{
+			HostName:           "web01",
+			ServiceDescription: "HTTP",
+			ExitStatusCode:     nagios.StateCRITICALExitCode,
+			Output:             "CRITICAL: down | 'time'=5ms;;;;",
+		},
+		{
+			HostName:           "web02",
+			ServiceDescription: "HTTP",
+			ExitStatusCode:     nagios.StateWARNINGExitCode,
+			Output:             "WARNING: slow",
+		},
+	}
+
+	formats := map[string]Format{
+		"XML":  FormatXML,
+		"JSON": FormatJSON,
+	}
+
+	for name, format := range formats {
+		format := format
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewFakeServer("s3cr3t")
+			defer server.Close()
+
+			client := NewClient(server.URL(), "s3cr3t")
+			client.SetFormat(format)
+			client.SetBatchSize(2)
+
+			if err := client.Submit(context.Background(), results...); err != nil {
+				t.Fatalf("ERROR: unexpected submission failure: %v", err)
+			}
+
+			if got := server.Requests(); got != 2 {
+				t.Errorf("ERROR: want 2 requests, got %d", got)
+			}
+
+			if d := cmp.Diff(results, server.Results()); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Log("OK: submitted check results recorded as expected")
+		})
+	}
+}
+
+func TestFakeServer_RejectsInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	server := NewFakeServer("s3cr3t")
+	defer server.Close()
+
+	client := NewClient(server.URL(), "wrong")
+
+	err := client.Submit(context.Background(), nagios.PassiveCheckResult{HostName: "web01"})
+	if !errors.Is(err, ErrSubmissionRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrSubmissionRejected, err)
+	}
+
+	if got := len(server.Results()); got != 0 {
+		t.Fatalf("ERROR: want no recorded results, got %d", got)
+	}
+
+	t.Log("OK: invalid token rejected as expected")
+}
+
+func TestFakeServer_SimulatesFailures(t *testing.T) {
+	t.Parallel()
+
+	server := NewFakeServer("s3cr3t")
+	defer server.Close()
+
+	client := NewClient(server.URL(), "s3cr3t")
+	result := nagios.PassiveCheckResult{HostName: "web01"}
+
+	server.Reject("NO DATA")
+	if err := client.Submit(context.Background(), result); !errors.Is(err, ErrSubmissionRejected) {
+		t.Errorf("ERROR: want %v for rejected submission, got %v", ErrSubmissionRejected, err)
+	}
+
+	server.Reset()
+	server.SetHTTPStatus(http.StatusServiceUnavailable)
+	if err := client.Submit(context.Background(), result); !errors.Is(err, ErrSubmissionRejected) {
+		t.Errorf("ERROR: want %v for HTTP failure, got %v", ErrSubmissionRejected, err)
+	}
+
+	server.Reset()
+	if err := client.Submit(context.Background(), result); err != nil {
+		t.Fatalf("ERROR: want submission accepted after reset, got %v", err)
+	}
+
+	if got := len(server.Results()); got != 1 {
+		t.Fatalf("ERROR: want 1 recorded result, got %d", got)
+	}
+
+	t.Log("OK: failures simulated as expected")
+}
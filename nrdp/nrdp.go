@@ -0,0 +1,396 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nrdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Format is the request body format used when submitting check results.
+type Format int
+
+// Supported request body formats.
+const (
+	// FormatXML submits check results using the XMLDATA request parameter.
+	FormatXML Format = iota
+
+	// FormatJSON submits check results using the JSONDATA request parameter.
+	FormatJSON
+)
+
+const (
+	// submitCheckCommand is the NRDP command used to submit check results.
+	submitCheckCommand string = "submitcheck"
+
+	// checkTypePassive is the NRDP check type value for passive checks.
+	checkTypePassive string = "1"
+
+	// checkResultTypeHost is the NRDP check result type for host checks.
+	checkResultTypeHost string = "host"
+
+	// checkResultTypeService is the NRDP check result type for service
+	// checks.
+	checkResultTypeService string = "service"
+
+	// defaultTimeout is the timeout used by the default HTTP client.
+	defaultTimeout time.Duration = 30 * time.Second
+
+	// maxResponseBodySize is the maximum number of bytes read from an NRDP
+	// response body.
+	maxResponseBodySize int64 = 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingURL indicates that the NRDP endpoint URL was not provided.
+	ErrMissingURL = errors.New("NRDP URL not specified")
+
+	// ErrMissingToken indicates that the NRDP authentication token was not
+	// provided.
+	ErrMissingToken = errors.New("NRDP token not specified")
+
+	// ErrNoCheckResults indicates that no check results were provided for
+	// submission.
+	ErrNoCheckResults = errors.New("no check results provided")
+
+	// ErrUnexpectedResponse indicates that the NRDP endpoint returned a
+	// response which could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected NRDP response")
+
+	// ErrSubmissionRejected indicates that the NRDP endpoint rejected the
+	// submitted check results (e.g., invalid token).
+	ErrSubmissionRejected = errors.New("NRDP submission rejected")
+)
+
+// Client submits passive check results to an NRDP endpoint.
+type Client struct {
+	// endpoint is the NRDP URL (e.g., https://nagios.example.com/nrdp/).
+	endpoint string
+
+	// token is the NRDP authentication token.
+	token string
+
+	// format is the request body format used for submissions.
+	format Format
+
+	// batchSize is the maximum number of check results included in a single
+	// request. A value of zero indicates no limit.
+	batchSize int
+
+	// httpClient is used to perform requests.
+	httpClient *http.Client
+}
+
+// NewClient constructs a new Client for the given NRDP endpoint URL and
+// authentication token. Check results are submitted as XML by default in a
+// single request.
+func NewClient(endpoint string, token string) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		token:      token,
+		format:     FormatXML,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// SetFormat overrides the default XML request body format.
+func (c *Client) SetFormat(format Format) {
+	c.format = format
+}
+
+// SetBatchSize limits the number of check results included in a single
+// request. Larger submissions are split into multiple requests. A value of
+// zero (the default) disables batching.
+func (c *Client) SetBatchSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	c.batchSize = size
+}
+
+// SetHTTPClient overrides the default HTTP client (e.g., to provide custom
+// TLS settings). A nil value is ignored.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		return
+	}
+
+	c.httpClient = client
+}
+
+// Submit sends the given check results to the NRDP endpoint, splitting them
+// into multiple requests if a batch size is set. Submission stops at the
+// first failed batch.
+//
+// Submit satisfies the nagios.PassiveSubmitter interface.
+func (c *Client) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	switch {
+	case strings.TrimSpace(c.endpoint) == "":
+		return ErrMissingURL
+	case c.token == "":
+		return ErrMissingToken
+	case len(results) == 0:
+		return ErrNoCheckResults
+	}
+
+	for _, batch := range batches(results, c.batchSize) {
+		if err := c.submitBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batches splits the given check results into collections of at most size
+// entries. If size is zero all check results are returned in a single
+// collection.
+func batches(results []nagios.PassiveCheckResult, size int) [][]nagios.PassiveCheckResult {
+	if size <= 0 || len(results) <= size {
+		return [][]nagios.PassiveCheckResult{results}
+	}
+
+	collection := make([][]nagios.PassiveCheckResult, 0, (len(results)+size-1)/size)
+	for start := 0; start < len(results); start += size {
+		end := start + size
+		if end > len(results) {
+			end = len(results)
+		}
+		collection = append(collection, results[start:end])
+	}
+
+	return collection
+}
+
+// submitBatch performs a single NRDP request for the given check results.
+func (c *Client) submitBatch(ctx context.Context, results []nagios.PassiveCheckResult) error {
+	form := url.Values{}
+	form.Set("token", c.token)
+	form.Set("cmd", submitCheckCommand)
+
+	switch c.format {
+	case FormatJSON:
+		data, err := EncodeJSON(results...)
+		if err != nil {
+			return err
+		}
+		form.Set("JSONDATA", string(data))
+
+	default:
+		data, err := EncodeXML(results...)
+		if err != nil {
+			return err
+		}
+		form.Set("XMLDATA", string(data))
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.endpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prepare NRDP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to submit check results to NRDP: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return fmt.Errorf("failed to read NRDP response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"NRDP endpoint returned HTTP status %q: %w",
+			resp.Status,
+			ErrSubmissionRejected,
+		)
+	}
+
+	return parseResponse(body)
+}
+
+// xmlCheckResults is the XML document used to submit check results.
+type xmlCheckResults struct {
+	XMLName      xml.Name         `xml:"checkresults"`
+	CheckResults []xmlCheckResult `xml:"checkresult"`
+}
+
+// xmlCheckResult is a single check result within an XML submission.
+type xmlCheckResult struct {
+	Type        string `xml:"type,attr"`
+	CheckType   string `xml:"checktype,attr"`
+	HostName    string `xml:"hostname"`
+	ServiceName string `xml:"servicename,omitempty"`
+	State       int    `xml:"state"`
+	Output      string `xml:"output"`
+}
+
+// jsonCheckResults is the JSON document used to submit check results.
+type jsonCheckResults struct {
+	CheckResults []jsonCheckResult `json:"checkresults"`
+}
+
+// jsonCheckResult is a single check result within a JSON submission.
+type jsonCheckResult struct {
+	CheckResult struct {
+		Type      string `json:"type"`
+		CheckType string `json:"checktype"`
+	} `json:"checkresult"`
+	HostName    string `json:"hostname"`
+	ServiceName string `json:"servicename,omitempty"`
+	State       string `json:"state"`
+	Output      string `json:"output"`
+}
+
+// checkResultType returns the NRDP check result type for the given check
+// result.
+func checkResultType(result nagios.PassiveCheckResult) string {
+	if result.IsHostCheckResult() {
+		return checkResultTypeHost
+	}
+
+	return checkResultTypeService
+}
+
+// EncodeXML returns the given check results as an NRDP XML document
+// suitable for use as the XMLDATA request parameter.
+func EncodeXML(results ...nagios.PassiveCheckResult) ([]byte, error) {
+	doc := xmlCheckResults{
+		CheckResults: make([]xmlCheckResult, 0, len(results)),
+	}
+
+	for _, result := range results {
+		doc.CheckResults = append(doc.CheckResults, xmlCheckResult{
+			Type:        checkResultType(result),
+			CheckType:   checkTypePassive,
+			HostName:    result.HostName,
+			ServiceName: result.ServiceDescription,
+			State:       result.ExitStatusCode,
+			Output:      result.Output,
+		})
+	}
+
+	data, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode check results as XML: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// EncodeJSON returns the given check results as an NRDP JSON document
+// suitable for use as the JSONDATA request parameter.
+func EncodeJSON(results ...nagios.PassiveCheckResult) ([]byte, error) {
+	doc := jsonCheckResults{
+		CheckResults: make([]jsonCheckResult, 0, len(results)),
+	}
+
+	for _, result := range results {
+		var entry jsonCheckResult
+		entry.CheckResult.Type = checkResultType(result)
+		entry.CheckResult.CheckType = checkTypePassive
+		entry.HostName = result.HostName
+		entry.ServiceName = result.ServiceDescription
+		entry.State = strconv.Itoa(result.ExitStatusCode)
+		entry.Output = result.Output
+
+		doc.CheckResults = append(doc.CheckResults, entry)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode check results as JSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// xmlResponse is the XML response returned by an NRDP endpoint.
+type xmlResponse struct {
+	XMLName xml.Name `xml:"result"`
+	Status  int      `xml:"status"`
+	Message string   `xml:"message"`
+}
+
+// jsonResponse is the JSON response returned by an NRDP endpoint.
+type jsonResponse struct {
+	Result struct {
+		Status  json.Number `json:"status"`
+		Message string      `json:"message"`
+	} `json:"result"`
+}
+
+// parseResponse evaluates the given NRDP response body and returns an error
+// if the submission was not accepted. NRDP may respond using either XML or
+// JSON regardless of the submitted request body format.
+func parseResponse(body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+
+	var status int
+	var message string
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var resp jsonResponse
+		if err := json.Unmarshal(trimmed, &resp); err != nil {
+			return fmt.Errorf("failed to decode JSON response: %v: %w", err, ErrUnexpectedResponse)
+		}
+
+		code, err := strconv.Atoi(resp.Result.Status.String())
+		if err != nil {
+			return fmt.Errorf("invalid status %q in response: %w", resp.Result.Status, ErrUnexpectedResponse)
+		}
+		status, message = code, resp.Result.Message
+
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		var resp xmlResponse
+		if err := xml.Unmarshal(trimmed, &resp); err != nil {
+			return fmt.Errorf("failed to decode XML response: %v: %w", err, ErrUnexpectedResponse)
+		}
+		status, message = resp.Status, resp.Message
+
+	default:
+		return fmt.Errorf("response body not in XML or JSON format: %w", ErrUnexpectedResponse)
+	}
+
+	if status != 0 {
+		return fmt.Errorf(
+			"NRDP returned status %d (%s): %w",
+			status,
+			message,
+			ErrSubmissionRejected,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nrdp
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure Client satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*Client)(nil)
+
+func testCheckResults(n int) []nagios.PassiveCheckResult {
+	results := make([]nagios.PassiveCheckResult, 0, n)
+	for i := 0; i < n; i++ {
+		results = append(results, nagios.PassiveCheckResult{
+			HostName:           "web01",
+			ServiceDescription: fmt.Sprintf("service%d", i),
+			ExitStatusCode:     nagios.StateWARNINGExitCode,
+			Output:             "WARNING: line one \nline two | 'time'=5ms;;;;",
+		})
+	}
+
+	return results
+}
+
+func TestClient_Submit_SendsBatchedXMLAndJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []Format{FormatXML, FormatJSON} {
+		format := format
+		t.Run(fmt.Sprintf("format %d", format), func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var requests int
+			var received int
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				requests++
+
+				if r.FormValue("token") != "s3cr3t" || r.FormValue("cmd") != submitCheckCommand {
+					_, _ = fmt.Fprint(w, `<result><status>-1</status><message>BAD TOKEN</message></result>`)
+					return
+				}
+
+				switch format {
+				case FormatJSON:
+					var doc jsonCheckResults
+					if err := json.Unmarshal([]byte(r.FormValue("JSONDATA")), &doc); err != nil {
+						t.Errorf("ERROR: failed to decode JSONDATA: %v", err)
+					}
+					received += len(doc.CheckResults)
+					_, _ = fmt.Fprint(w, `{"result":{"status":"0","message":"OK"}}`)
+
+				default:
+					var doc xmlCheckResults
+					if err := xml.Unmarshal([]byte(r.FormValue("XMLDATA")), &doc); err != nil {
+						t.Errorf("ERROR: failed to decode XMLDATA: %v", err)
+					}
+					received += len(doc.CheckResults)
+					_, _ = fmt.Fprint(w, `<result><status>0</status><message>OK</message></result>`)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "s3cr3t")
+			client.SetFormat(format)
+			client.SetBatchSize(2)
+
+			if err := client.Submit(context.Background(), testCheckResults(5)...); err != nil {
+				t.Fatalf("ERROR: unexpected submission failure: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if requests != 3 || received != 5 {
+				t.Fatalf("ERROR: want 5 results in 3 requests, got %d results in %d requests", received, requests)
+			}
+
+			t.Log("OK: check results submitted in batches as expected")
+		})
+	}
+}
+
+func TestClient_Submit_ReportsRejectedSubmission(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<result><status>-1</status><message>BAD TOKEN</message></result>`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "wrong")
+
+	err := client.Submit(context.Background(), testCheckResults(1)...)
+	if !errors.Is(err, ErrSubmissionRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrSubmissionRejected, err)
+	}
+
+	t.Log("OK: rejected submission reported as expected")
+}
+
+func TestEncodeXML_EncodesHostAndServiceResults(t *testing.T) {
+	t.Parallel()
+
+	results := []nagios.PassiveCheckResult{
+		{HostName: "web01", ExitStatusCode: 0, Output: "UP"},
+		{HostName: "web01", ServiceDescription: "HTTP", ExitStatusCode: 2, Output: "CRITICAL <down> & out"},
+	}
+
+	data, err := EncodeXML(results...)
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode XML: %v", err)
+	}
+
+	var doc xmlCheckResults
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("ERROR: failed to decode generated XML: %v", err)
+	}
+
+	switch {
+	case len(doc.CheckResults) != 2:
+		t.Fatalf("ERROR: want 2 check results, got %d", len(doc.CheckResults))
+	case doc.CheckResults[0].Type != checkResultTypeHost:
+		t.Errorf("ERROR: want host check result type, got %q", doc.CheckResults[0].Type)
+	case doc.CheckResults[1].Type != checkResultTypeService:
+		t.Errorf("ERROR: want service check result type, got %q", doc.CheckResults[1].Type)
+	case doc.CheckResults[1].Output != results[1].Output:
+		t.Errorf("ERROR: want output %q, got %q", results[1].Output, doc.CheckResults[1].Output)
+	default:
+		t.Log("OK: XML document encoded as expected")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package nrpe provides a client for executing remote checks using the Nagios
+Remote Plugin Executor (NRPE) protocol.
+
+# OVERVIEW
+
+NRPE allows a poller to execute plugins on a remote host. This package
+implements the NRPE packet format (versions 2, 3 and 4) and a client which
+submits a command query and returns the exit code and output of the remote
+plugin. The returned output is the complete plugin output (including any
+performance data) and may be processed further using nagios.ParsePerfData.
+
+Version 2 packets are supported by all NRPE daemons but limit plugin output
+to 1023 bytes. Version 3 and 4 packets (NRPE 3.x and 4.x) support larger
+output.
+
+# TLS
+
+The NRPE daemon uses anonymous Diffie-Hellman (ADH) ciphers by default when
+a certificate is not configured. ADH ciphers are not supported by the Go
+standard library; the daemon must either be configured with a certificate
+(see the NRPE ssl_* settings) or with SSL disabled (check_nrpe -n). TLS is
+disabled by default and enabled using SetTLSConfig.
+
+# HOW TO USE
+
+	client := nrpe.NewClient("web01.example.com")
+	client.SetPacketVersion(nrpe.Version4)
+
+	resp, err := client.Query(ctx, "check_disk", "/", "80")
+	if err != nil {
+		// handle error
+	}
+
+	fmt.Println(resp.ResultCode, resp.Output)
+*/
+package nrpe
@@ -0,0 +1,421 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nrpe
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Version is an NRPE packet version.
+type Version int16
+
+// Supported NRPE packet versions.
+const (
+	// Version2 is supported by all NRPE daemons. Plugin output is limited
+	// to 1023 bytes.
+	Version2 Version = 2
+
+	// Version3 is supported by NRPE 3.x and newer.
+	Version3 Version = 3
+
+	// Version4 is supported by NRPE 4.x and newer.
+	Version4 Version = 4
+)
+
+// PacketType is an NRPE packet type.
+type PacketType int16
+
+// Supported NRPE packet types.
+const (
+	QueryPacket    PacketType = 1
+	ResponsePacket PacketType = 2
+)
+
+// Protocol values used by the NRPE daemon and check_nrpe.
+const (
+	// DefaultPort is the default TCP port used by the NRPE daemon.
+	DefaultPort string = "5666"
+
+	// CommandArgSeparator separates the command name and arguments in a
+	// query.
+	CommandArgSeparator string = "!"
+
+	// v2BufferSize is the size of the fixed buffer used by version 2
+	// packets.
+	v2BufferSize int = nagios.NRPEv2MaxOutputLength
+
+	// v2PacketSize is the size of a version 2 packet (version, type,
+	// CRC32, result code, buffer and trailing alignment padding).
+	v2PacketSize int = 2 + 2 + 4 + 2 + v2BufferSize + 2
+
+	// v3HeaderSize is the size of the fixed fields of version 3 and 4
+	// packets (version, type, CRC32, result code, alignment padding and
+	// buffer length).
+	v3HeaderSize int = 2 + 2 + 4 + 2 + 2 + 4
+
+	// v3TrailerSize is the size of the trailing alignment padding included
+	// in version 3 packets. Version 4 packets do not include this padding.
+	v3TrailerSize int = 3
+
+	// minQueryBufferLength is the minimum buffer length used for version 3
+	// and 4 query packets; this matches the behavior of check_nrpe.
+	minQueryBufferLength int = 1024
+
+	// maxBufferLength limits the accepted buffer length of version 3 and 4
+	// packets to the maximum plugin output length returned by NRPE v3 and
+	// v4 agents.
+	maxBufferLength int = nagios.NRPEv4MaxOutputLength
+
+	// defaultTimeout is used for connecting to and communicating with the
+	// NRPE daemon if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the NRPE daemon address was not
+	// provided.
+	ErrMissingAddress = errors.New("NRPE address not specified")
+
+	// ErrMissingCommand indicates that a command was not provided.
+	ErrMissingCommand = errors.New("NRPE command not specified")
+
+	// ErrInvalidCommand indicates that a command or command argument
+	// contains characters which cannot be represented in a query.
+	ErrInvalidCommand = errors.New("invalid NRPE command")
+
+	// ErrUnsupportedVersion indicates that an unsupported packet version
+	// was specified or received.
+	ErrUnsupportedVersion = errors.New("unsupported NRPE packet version")
+
+	// ErrInvalidPacket indicates that an NRPE packet is malformed.
+	ErrInvalidPacket = errors.New("invalid NRPE packet")
+)
+
+// Packet is a decoded NRPE packet.
+type Packet struct {
+	// Version is the packet version.
+	Version Version
+
+	// Type indicates whether the packet is a query or response.
+	Type PacketType
+
+	// ResultCode is the plugin exit code for response packets.
+	ResultCode int
+
+	// Buffer is the command query or plugin output.
+	Buffer string
+}
+
+// Response is the result of a remote plugin execution.
+type Response struct {
+	// ResultCode is the exit code of the remote plugin.
+	ResultCode int
+
+	// Output is the complete output of the remote plugin, including any
+	// long output and performance data.
+	Output string
+}
+
+// Client executes remote checks using an NRPE daemon.
+type Client struct {
+	// address is the NRPE daemon address in host:port format.
+	address string
+
+	// version is the packet version used for queries.
+	version Version
+
+	// tlsConfig enables TLS if set.
+	tlsConfig *tls.Config
+
+	// timeout limits the time spent connecting to and communicating with
+	// the NRPE daemon.
+	timeout time.Duration
+
+	// dialer is used to establish connections to the NRPE daemon.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given NRPE daemon address. If a
+// port is not included in the address the default NRPE port is used.
+// Version 2 packets are sent without TLS by default.
+func NewClient(address string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address: address,
+		version: Version2,
+		timeout: defaultTimeout,
+	}
+}
+
+// SetPacketVersion overrides the default packet version used for queries.
+// Unsupported versions are ignored.
+func (c *Client) SetPacketVersion(version Version) {
+	if !version.valid() {
+		return
+	}
+
+	c.version = version
+}
+
+// SetTLSConfig enables TLS using the given configuration. A nil value
+// disables TLS.
+func (c *Client) SetTLSConfig(config *tls.Config) {
+	c.tlsConfig = config
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with the NRPE daemon. Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// Query executes the given command (as defined in the NRPE daemon
+// configuration) with optional arguments and returns the result. Arguments
+// are only used by the daemon if dont_blame_nrpe is enabled.
+func (c *Client) Query(ctx context.Context, command string, args ...string) (Response, error) {
+	if c.address == "" {
+		return Response{}, ErrMissingAddress
+	}
+
+	query, err := FormatQuery(command, args...)
+	if err != nil {
+		return Response{}, err
+	}
+
+	packet, err := EncodePacket(Packet{Version: c.version, Type: QueryPacket, Buffer: query})
+	if err != nil {
+		return Response{}, err
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var conn net.Conn
+	conn, err = c.dialer.DialContext(dialCtx, "tcp", c.address)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to connect to NRPE daemon: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return Response{}, fmt.Errorf("failed to set NRPE connection deadline: %w", err)
+	}
+
+	if c.tlsConfig != nil {
+		tlsConn := tls.Client(conn, c.tlsConfig)
+		if err := tlsConn.HandshakeContext(dialCtx); err != nil {
+			return Response{}, fmt.Errorf("failed TLS handshake with NRPE daemon: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	if _, err := conn.Write(packet); err != nil {
+		return Response{}, fmt.Errorf("failed to send NRPE query: %w", err)
+	}
+
+	resp, err := ReadPacket(conn)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if resp.Type != ResponsePacket {
+		return Response{}, fmt.Errorf("unexpected packet type %d: %w", resp.Type, ErrInvalidPacket)
+	}
+
+	return Response{
+		ResultCode: resp.ResultCode,
+		Output:     resp.Buffer,
+	}, nil
+}
+
+// FormatQuery returns the given command and arguments as an NRPE query
+// string.
+func FormatQuery(command string, args ...string) (string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", ErrMissingCommand
+	}
+
+	fields := append([]string{command}, args...)
+	for _, field := range fields {
+		if strings.ContainsAny(field, CommandArgSeparator+"\x00") {
+			return "", fmt.Errorf("%q: %w", field, ErrInvalidCommand)
+		}
+	}
+
+	return strings.Join(fields, CommandArgSeparator), nil
+}
+
+// valid indicates whether the packet version is supported.
+func (v Version) valid() bool {
+	switch v {
+	case Version2, Version3, Version4:
+		return true
+	default:
+		return false
+	}
+}
+
+// EncodePacket returns the given packet in the NRPE wire format.
+func EncodePacket(packet Packet) ([]byte, error) {
+	var buf []byte
+
+	switch packet.Version {
+	case Version2:
+		if len(packet.Buffer) >= v2BufferSize {
+			return nil, fmt.Errorf(
+				"buffer length %d exceeds version 2 limit of %d: %w",
+				len(packet.Buffer), v2BufferSize-1, ErrInvalidPacket,
+			)
+		}
+		buf = make([]byte, v2PacketSize)
+		copy(buf[10:], packet.Buffer)
+
+	case Version3, Version4:
+		bufferLength := len(packet.Buffer) + 1
+		if packet.Type == QueryPacket && bufferLength < minQueryBufferLength {
+			bufferLength = minQueryBufferLength
+		}
+
+		size := v3HeaderSize + bufferLength
+		if packet.Version == Version3 {
+			size += v3TrailerSize
+		}
+
+		buf = make([]byte, size)
+		binary.BigEndian.PutUint32(buf[12:], uint32(bufferLength))
+		copy(buf[v3HeaderSize:], packet.Buffer)
+
+	default:
+		return nil, fmt.Errorf("version %d: %w", packet.Version, ErrUnsupportedVersion)
+	}
+
+	binary.BigEndian.PutUint16(buf[0:], uint16(packet.Version))
+	binary.BigEndian.PutUint16(buf[2:], uint16(packet.Type))
+	binary.BigEndian.PutUint16(buf[8:], uint16(int16(packet.ResultCode)))
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(buf))
+
+	return buf, nil
+}
+
+// ReadPacket reads and decodes a single NRPE packet of any supported
+// version.
+func ReadPacket(r io.Reader) (Packet, error) {
+	header := make([]byte, v3HeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Packet{}, fmt.Errorf("failed to read NRPE packet: %w", err)
+	}
+
+	var buf []byte
+
+	version := Version(binary.BigEndian.Uint16(header[0:]))
+	switch version {
+	case Version2:
+		buf = make([]byte, v2PacketSize)
+
+	case Version3, Version4:
+		bufferLength := int(binary.BigEndian.Uint32(header[12:]))
+		if bufferLength > maxBufferLength {
+			return Packet{}, fmt.Errorf("buffer length %d exceeds limit: %w", bufferLength, ErrInvalidPacket)
+		}
+
+		size := v3HeaderSize + bufferLength
+		if version == Version3 {
+			size += v3TrailerSize
+		}
+		buf = make([]byte, size)
+
+	default:
+		return Packet{}, fmt.Errorf("version %d: %w", version, ErrUnsupportedVersion)
+	}
+
+	copy(buf, header)
+	if _, err := io.ReadFull(r, buf[len(header):]); err != nil {
+		return Packet{}, fmt.Errorf("failed to read NRPE packet: %w", err)
+	}
+
+	return DecodePacket(buf)
+}
+
+// DecodePacket decodes the given NRPE packet and validates the CRC32 value.
+func DecodePacket(buf []byte) (Packet, error) {
+	if len(buf) < v3HeaderSize {
+		return Packet{}, fmt.Errorf("packet length %d too short: %w", len(buf), ErrInvalidPacket)
+	}
+
+	packet := Packet{
+		Version:    Version(binary.BigEndian.Uint16(buf[0:])),
+		Type:       PacketType(binary.BigEndian.Uint16(buf[2:])),
+		ResultCode: int(int16(binary.BigEndian.Uint16(buf[8:]))),
+	}
+
+	var buffer []byte
+
+	switch packet.Version {
+	case Version2:
+		if len(buf) != v2PacketSize {
+			return Packet{}, fmt.Errorf("packet length %d, want %d: %w", len(buf), v2PacketSize, ErrInvalidPacket)
+		}
+		buffer = buf[10 : 10+v2BufferSize]
+
+	case Version3, Version4:
+		bufferLength := int(binary.BigEndian.Uint32(buf[12:]))
+		if bufferLength > len(buf)-v3HeaderSize {
+			return Packet{}, fmt.Errorf("buffer length %d exceeds packet length: %w", bufferLength, ErrInvalidPacket)
+		}
+		buffer = buf[v3HeaderSize : v3HeaderSize+bufferLength]
+
+	default:
+		return Packet{}, fmt.Errorf("version %d: %w", packet.Version, ErrUnsupportedVersion)
+	}
+
+	want := binary.BigEndian.Uint32(buf[4:])
+
+	check := make([]byte, len(buf))
+	copy(check, buf)
+	binary.BigEndian.PutUint32(check[4:], 0)
+
+	if got := crc32.ChecksumIEEE(check); got != want {
+		return Packet{}, fmt.Errorf("CRC32 mismatch (want %08x, got %08x): %w", want, got, ErrInvalidPacket)
+	}
+
+	if i := bytes.IndexByte(buffer, 0); i >= 0 {
+		buffer = buffer[:i]
+	}
+	packet.Buffer = string(buffer)
+
+	return packet, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nrpe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeDaemon accepts a single connection, records the received query packet
+// and responds with the given result code and output using the same packet
+// version.
+func fakeDaemon(t *testing.T, resultCode int, output string) (string, <-chan Packet) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	queries := make(chan Packet, 1)
+
+	go func() {
+		defer close(queries)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		query, err := ReadPacket(conn)
+		if err != nil {
+			t.Errorf("ERROR: failed to read query: %v", err)
+			return
+		}
+		queries <- query
+
+		resp, err := EncodePacket(Packet{
+			Version:    query.Version,
+			Type:       ResponsePacket,
+			ResultCode: resultCode,
+			Buffer:     output,
+		})
+		if err != nil {
+			t.Errorf("ERROR: failed to encode response: %v", err)
+			return
+		}
+
+		_, _ = conn.Write(resp)
+	}()
+
+	return listener.Addr().String(), queries
+}
+
+func TestClient_Query_SupportedPacketVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		version Version
+		output  string
+	}{
+		"version 2": {version: Version2, output: "DISK OK|/=80%;90;95"},
+		"version 3": {version: Version3, output: "DISK OK\n" + strings.Repeat("x", 2048)},
+		"version 4": {version: Version4, output: "DISK OK\n" + strings.Repeat("y", 2048) + "|/=80%;90;95"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, queries := fakeDaemon(t, 1, tt.output)
+
+			client := NewClient(addr)
+			client.SetPacketVersion(tt.version)
+
+			resp, err := client.Query(context.Background(), "check_disk", "/", "80")
+			if err != nil {
+				t.Fatalf("ERROR: unexpected query failure: %v", err)
+			}
+
+			want := Packet{Version: tt.version, Type: QueryPacket, Buffer: "check_disk!/!80"}
+			if d := cmp.Diff(want, <-queries); d != "" {
+				t.Errorf("(-want, +got)\n:%s", d)
+			}
+
+			if d := cmp.Diff(Response{ResultCode: 1, Output: tt.output}, resp); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Log("OK: query and response handled as expected")
+		})
+	}
+}
+
+func TestDecodePacket_DetectsCorruption(t *testing.T) {
+	t.Parallel()
+
+	buf, err := EncodePacket(Packet{Version: Version2, Type: ResponsePacket, Buffer: "OK"})
+	if err != nil {
+		t.Fatalf("ERROR: failed to encode packet: %v", err)
+	}
+
+	buf[12] = 'X'
+
+	if _, err := ReadPacket(bytes.NewReader(buf)); !errors.Is(err, ErrInvalidPacket) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidPacket, err)
+	}
+
+	t.Log("OK: corrupted packet rejected as expected")
+}
+
+func TestFormatQuery_RejectsSeparatorInArguments(t *testing.T) {
+	t.Parallel()
+
+	if _, err := FormatQuery("check_disk", "/!"); !errors.Is(err, ErrInvalidCommand) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidCommand, err)
+	}
+
+	if _, err := FormatQuery(" "); !errors.Is(err, ErrMissingCommand) {
+		t.Fatalf("ERROR: want %v, got %v", ErrMissingCommand, err)
+	}
+
+	t.Log("OK: invalid queries rejected as expected")
+}
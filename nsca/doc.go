@@ -0,0 +1,65 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package nsca provides a client implementing the NSCA (Nagios Service Check
+Acceptor) wire protocol used by the send_nsca tool.
+
+# OVERVIEW
+
+This package allows Go plugins to submit passive check results directly to
+an NSCA daemon without shelling out to send_nsca. The protocol version 3
+packet format is supported in both the current (4096 byte plugin output) and
+legacy (512 byte plugin output) sizes.
+
+Supported encryption methods:
+
+  - none (0)
+  - simple XOR (1)
+  - DES (2)
+  - Triple DES (3)
+  - Rijndael 128 / AES (14)
+
+The mcrypt based methods are implemented using 8-bit cipher feedback (CFB)
+mode for compatibility with the NSCA daemon.
+
+NOTE: The nsca-ng daemon uses a different protocol based on TLS with
+pre-shared keys (TLS-PSK). TLS-PSK is not supported by the Go standard
+library so nsca-ng is not supported by this package.
+
+The Client type satisfies the nagios.PassiveSubmitter interface and can be
+used directly with the nagios.Runner type for daemon mode checks.
+
+# HOW TO USE
+
+	client := nsca.NewClient("nagios.example.com:5667")
+	client.SetEncryption(nsca.EncryptionXOR, "s3cr3t")
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := client.Submit(ctx, result); err != nil {
+		// handle error
+	}
+
+# TESTING
+
+The FakeServer type provides an in-memory NSCA daemon for integration
+testing code which submits passive check results without a running NSCA
+daemon. Received packets are decrypted, validated and recorded. Because the
+NSCA protocol does not acknowledge received packets, use WaitForPackets
+before inspecting the recorded check results.
+
+	server, err := nsca.NewFakeServer(nsca.EncryptionXOR, "s3cr3t")
+	if err != nil {
+		// handle error
+	}
+	defer server.Close()
+
+	client := nsca.NewClient(server.Address())
+	client.SetEncryption(nsca.EncryptionXOR, "s3cr3t")
+	// submit check results, then call server.WaitForPackets(n, timeout)
+*/
+package nsca
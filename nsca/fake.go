@@ -0,0 +1,306 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nsca
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// ErrFakeServerTimeout indicates that the expected number of packets was not
+// received by a FakeServer within the allotted time.
+var ErrFakeServerTimeout = errors.New("timeout waiting for NSCA packets")
+
+// FakeServer is an in-memory NSCA daemon intended for use by client code
+// integration tests. The daemon listens on a loopback address, sends the
+// initialization packet expected by clients and decrypts and decodes
+// received data packets instead of passing them to a monitoring system.
+type FakeServer struct {
+	// mu guards the recorded packets and errors.
+	mu sync.Mutex
+
+	// listener accepts client connections.
+	listener net.Listener
+
+	// wg tracks active connection handlers.
+	wg sync.WaitGroup
+
+	// method is the decryption method used for received packets.
+	method EncryptionMethod
+
+	// password is the shared password used for decryption.
+	password string
+
+	// maxOutputLength is the size of the plugin output field.
+	maxOutputLength int
+
+	// timestamp is the timestamp sent in the initialization packet.
+	timestamp time.Time
+
+	// packets is the collection of decoded packets in the order received.
+	packets []Packet
+
+	// errs is the collection of errors encountered while processing client
+	// connections.
+	errs []error
+
+	// notify is closed and replaced each time a packet is recorded.
+	notify chan struct{}
+}
+
+// NewFakeServer starts and returns a new FakeServer listening on a random
+// loopback port. Packets are decrypted using the given encryption method and
+// password; these must match the settings used by the client. The caller is
+// responsible for calling Close when finished.
+func NewFakeServer(method EncryptionMethod, password string) (*FakeServer, error) {
+	// Validate the encryption settings up front instead of failing each
+	// client connection.
+	if _, err := newPacketCrypter(method, password, make([]byte, ivSize), true); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start fake NSCA daemon: %w", err)
+	}
+
+	fs := FakeServer{
+		listener:        listener,
+		method:          method,
+		password:        password,
+		maxOutputLength: MaxPluginOutputLength,
+		timestamp:       time.Unix(time.Now().Unix(), 0),
+		notify:          make(chan struct{}),
+	}
+
+	fs.wg.Add(1)
+	go fs.serve()
+
+	return &fs, nil
+}
+
+// Address returns the daemon address in host:port format for use with
+// NewClient.
+func (fs *FakeServer) Address() string {
+	return fs.listener.Addr().String()
+}
+
+// Timestamp returns the timestamp sent to clients in the initialization
+// packet. Decoded packets use this value as their timestamp.
+func (fs *FakeServer) Timestamp() time.Time {
+	return fs.timestamp
+}
+
+// UseLegacyPacketSize specifies that the packet format used by NSCA versions
+// older than 2.9 (512 byte plugin output) is expected. This should be called
+// before any clients connect.
+func (fs *FakeServer) UseLegacyPacketSize() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.maxOutputLength = LegacyMaxPluginOutputLength
+}
+
+// Close stops accepting connections and blocks until all active connections
+// have been processed.
+func (fs *FakeServer) Close() {
+	_ = fs.listener.Close()
+	fs.wg.Wait()
+}
+
+// Packets returns a copy of the decoded packets in the order received.
+func (fs *FakeServer) Packets() []Packet {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	packets := make([]Packet, len(fs.packets))
+	copy(packets, fs.packets)
+
+	return packets
+}
+
+// Results returns the decoded packets as check results in the order
+// received. The plugin output is unescaped to match the output provided by
+// the client.
+func (fs *FakeServer) Results() []nagios.PassiveCheckResult {
+	packets := fs.Packets()
+
+	results := make([]nagios.PassiveCheckResult, 0, len(packets))
+	for _, packet := range packets {
+		results = append(results, nagios.PassiveCheckResult{
+			HostName:           packet.HostName,
+			ServiceDescription: packet.ServiceDescription,
+			ExitStatusCode:     packet.ReturnCode,
+			Output:             unescapeOutput(packet.PluginOutput),
+			Timestamp:          packet.Timestamp,
+		})
+	}
+
+	return results
+}
+
+// Errors returns the errors encountered while processing client connections
+// (e.g., packets which failed CRC32 validation due to mismatched encryption
+// settings).
+func (fs *FakeServer) Errors() []error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	errs := make([]error, len(fs.errs))
+	copy(errs, fs.errs)
+
+	return errs
+}
+
+// WaitForPackets blocks until at least count packets have been received or
+// the timeout is reached. Because the NSCA protocol does not acknowledge
+// received packets, a client may finish submitting before the daemon has
+// processed all packets. The decoded packets are returned along with
+// ErrFakeServerTimeout if the timeout is reached.
+func (fs *FakeServer) WaitForPackets(count int, timeout time.Duration) ([]Packet, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		fs.mu.Lock()
+		received := len(fs.packets)
+		notify := fs.notify
+		fs.mu.Unlock()
+
+		if received >= count {
+			return fs.Packets(), nil
+		}
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return fs.Packets(), fmt.Errorf(
+				"received %d of %d packets: %w",
+				received,
+				count,
+				ErrFakeServerTimeout,
+			)
+		}
+	}
+}
+
+// serve accepts client connections until the listener is closed.
+func (fs *FakeServer) serve() {
+	defer fs.wg.Done()
+
+	for {
+		conn, err := fs.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		fs.wg.Add(1)
+		go fs.handle(conn)
+	}
+}
+
+// handle sends the initialization packet to the client and processes data
+// packets until the client closes the connection.
+func (fs *FakeServer) handle(conn net.Conn) {
+	defer fs.wg.Done()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	iv := make([]byte, ivSize)
+	if _, err := rand.Read(iv); err != nil {
+		fs.recordError(fmt.Errorf("failed to generate IV: %w", err))
+		return
+	}
+
+	if _, err := conn.Write(EncodeInitPacket(iv, fs.timestamp)); err != nil {
+		fs.recordError(fmt.Errorf("failed to send initialization packet: %w", err))
+		return
+	}
+
+	crypter, err := newPacketCrypter(fs.method, fs.password, iv, true)
+	if err != nil {
+		fs.recordError(err)
+		return
+	}
+
+	fs.mu.Lock()
+	maxOutputLength := fs.maxOutputLength
+	fs.mu.Unlock()
+
+	for {
+		buf := make([]byte, PacketSize(maxOutputLength))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			if !errors.Is(err, io.EOF) {
+				fs.recordError(fmt.Errorf("failed to read packet: %w", err))
+			}
+			return
+		}
+
+		crypter.crypt(buf)
+
+		packet, err := DecodePacket(buf, maxOutputLength)
+		if err != nil {
+			fs.recordError(err)
+			continue
+		}
+
+		fs.recordPacket(packet)
+	}
+}
+
+// recordPacket records the given packet and wakes any waiting callers.
+func (fs *FakeServer) recordPacket(packet Packet) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.packets = append(fs.packets, packet)
+
+	close(fs.notify)
+	fs.notify = make(chan struct{})
+}
+
+// recordError records the given connection processing error.
+func (fs *FakeServer) recordError(err error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.errs = append(fs.errs, err)
+}
+
+// unescapeOutput reverses the escaping applied by EscapeOutput. Carriage
+// returns removed by EscapeOutput are not restored.
+func unescapeOutput(output string) string {
+	var b strings.Builder
+	b.Grow(len(output))
+
+	for i := 0; i < len(output); i++ {
+		if output[i] != '\\' || i+1 == len(output) {
+			b.WriteByte(output[i])
+			continue
+		}
+
+		i++
+		switch output[i] {
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(output[i])
+		}
+	}
+
+	return b.String()
+}
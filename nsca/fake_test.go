@@ -0,0 +1,152 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nsca
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestFakeServer_RecordsSubmittedResults(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewFakeServer(EncryptionRijndael128, "s3cr3t")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start fake server: %v", err)
+	}
+	defer server.Close()
+
+	results := []nagios.PassiveCheckResult{
+		{HostName: "web01", ExitStatusCode: nagios.StateOKExitCode, Output: "UP"},
+		{
+			HostName:           "web01",
+			ServiceDescription: "HTTP",
+			ExitStatusCode:     nagios.StateCRITICALExitCode,
+			Output:             `CRITICAL: C:\temp missing` + "\nline two | 'time'=5ms;;;;",
+		},
+	}
+
+	client := NewClient(server.Address())
+	client.SetEncryption(EncryptionRijndael128, "s3cr3t")
+
+	if err := client.Submit(context.Background(), results...); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	if _, err := server.WaitForPackets(len(results), 5*time.Second); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := make([]nagios.PassiveCheckResult, 0, len(results))
+	for _, result := range results {
+		result.Timestamp = server.Timestamp()
+		want = append(want, result)
+	}
+
+	if d := cmp.Diff(want, server.Results()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	if errs := server.Errors(); len(errs) != 0 {
+		t.Fatalf("ERROR: unexpected errors: %v", errs)
+	}
+
+	t.Log("OK: submitted check results recorded as expected")
+}
+
+func TestFakeServer_RecordsDecryptionFailures(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewFakeServer(EncryptionTripleDES, "s3cr3t")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start fake server: %v", err)
+	}
+
+	client := NewClient(server.Address())
+	client.SetEncryption(EncryptionTripleDES, "wrong")
+
+	if err := client.Submit(context.Background(), nagios.PassiveCheckResult{HostName: "web01"}); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	// Close blocks until the connection has been processed.
+	server.Close()
+
+	if got := len(server.Packets()); got != 0 {
+		t.Errorf("ERROR: want no decoded packets, got %d", got)
+	}
+
+	errs := server.Errors()
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidPacket) {
+		t.Fatalf("ERROR: want single %v error, got %v", ErrInvalidPacket, errs)
+	}
+
+	t.Log("OK: decryption failure recorded as expected")
+}
+
+func TestFakeServer_UseLegacyPacketSize(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewFakeServer(EncryptionXOR, "s3cr3t")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start fake server: %v", err)
+	}
+	defer server.Close()
+	server.UseLegacyPacketSize()
+
+	client := NewClient(server.Address())
+	client.SetEncryption(EncryptionXOR, "s3cr3t")
+	client.UseLegacyPacketSize()
+
+	if err := client.Submit(context.Background(), nagios.PassiveCheckResult{HostName: "web01"}); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	packets, err := server.WaitForPackets(1, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if packets[0].HostName != "web01" {
+		t.Fatalf("ERROR: want host %q, got %q", "web01", packets[0].HostName)
+	}
+
+	t.Log("OK: legacy packet size decoded as expected")
+}
+
+func TestFakeServer_WaitForPacketsTimesOut(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewFakeServer(EncryptionNone, "")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start fake server: %v", err)
+	}
+	defer server.Close()
+
+	if _, err := server.WaitForPackets(1, 10*time.Millisecond); !errors.Is(err, ErrFakeServerTimeout) {
+		t.Fatalf("ERROR: want %v, got %v", ErrFakeServerTimeout, err)
+	}
+
+	t.Log("OK: timeout reported as expected")
+}
+
+func TestNewFakeServer_RejectsUnsupportedEncryption(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewFakeServer(EncryptionMethod(99), ""); !errors.Is(err, ErrUnsupportedEncryption) {
+		t.Fatalf("ERROR: want %v, got %v", ErrUnsupportedEncryption, err)
+	}
+
+	t.Log("OK: unsupported encryption method rejected as expected")
+}
@@ -0,0 +1,370 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nsca
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Protocol values used by the NSCA daemon and send_nsca.
+const (
+	// DefaultPort is the default TCP port used by the NSCA daemon.
+	DefaultPort string = "5667"
+
+	// packetVersion is the supported NSCA data packet version.
+	packetVersion int16 = 3
+
+	// ivSize is the size of the IV sent by the NSCA daemon.
+	ivSize int = 128
+
+	// initPacketSize is the size of the initialization packet sent by the
+	// NSCA daemon (IV and timestamp).
+	initPacketSize int = ivSize + 4
+
+	// maxHostNameLength is the size of the host name field.
+	maxHostNameLength int = 64
+
+	// maxDescriptionLength is the size of the service description field.
+	maxDescriptionLength int = 128
+
+	// MaxPluginOutputLength is the size of the plugin output field used by
+	// NSCA 2.9 and newer.
+	MaxPluginOutputLength int = nagios.NSCAMaxOutputLength
+
+	// LegacyMaxPluginOutputLength is the size of the plugin output field
+	// used by NSCA versions older than 2.9.
+	LegacyMaxPluginOutputLength int = 512
+
+	// packetHeaderSize is the size of the fixed fields preceding the host
+	// name (version, alignment padding, CRC32, timestamp and return code).
+	packetHeaderSize int = 2 + 2 + 4 + 4 + 2
+
+	// defaultTimeout is used for connecting to and communicating with the
+	// NSCA daemon if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the NSCA daemon address was not
+	// provided.
+	ErrMissingAddress = errors.New("NSCA address not specified")
+
+	// ErrNoCheckResults indicates that no check results were provided for
+	// submission.
+	ErrNoCheckResults = errors.New("no check results provided")
+
+	// ErrUnsupportedEncryption indicates that an unsupported encryption
+	// method was specified.
+	ErrUnsupportedEncryption = errors.New("unsupported encryption method")
+
+	// ErrInvalidPacket indicates that an NSCA packet is malformed.
+	ErrInvalidPacket = errors.New("invalid NSCA packet")
+)
+
+// Packet is a decoded NSCA data packet.
+type Packet struct {
+	// Timestamp is the packet timestamp; this is the timestamp provided by
+	// the NSCA daemon in the initialization packet.
+	Timestamp time.Time
+
+	// ReturnCode is the check result exit code.
+	ReturnCode int
+
+	// HostName is the name of the host associated with the check result.
+	HostName string
+
+	// ServiceDescription is the service associated with the check result.
+	// Empty for host check results.
+	ServiceDescription string
+
+	// PluginOutput is the (escaped) plugin output.
+	PluginOutput string
+}
+
+// Client submits passive check results to an NSCA daemon.
+type Client struct {
+	// address is the NSCA daemon address in host:port format.
+	address string
+
+	// encryption is the encryption method used for submitted packets.
+	encryption EncryptionMethod
+
+	// password is the shared password used for encryption.
+	password string
+
+	// timeout limits the time spent connecting to and communicating with
+	// the NSCA daemon.
+	timeout time.Duration
+
+	// maxOutputLength is the size of the plugin output field.
+	maxOutputLength int
+
+	// dialer is used to establish connections to the NSCA daemon.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given NSCA daemon address. If a
+// port is not included in the address the default NSCA port is used.
+// Packets are sent unencrypted using the NSCA 2.9+ packet size by default.
+func NewClient(address string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address:         address,
+		encryption:      EncryptionNone,
+		timeout:         defaultTimeout,
+		maxOutputLength: MaxPluginOutputLength,
+	}
+}
+
+// SetEncryption specifies the encryption method and password; these must
+// match the NSCA daemon configuration.
+func (c *Client) SetEncryption(method EncryptionMethod, password string) {
+	c.encryption = method
+	c.password = password
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with the NSCA daemon. Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// UseLegacyPacketSize specifies that the packet format used by NSCA versions
+// older than 2.9 (512 byte plugin output) should be used.
+func (c *Client) UseLegacyPacketSize() {
+	c.maxOutputLength = LegacyMaxPluginOutputLength
+}
+
+// Submit sends the given check results to the NSCA daemon using a single
+// connection.
+//
+// Submit satisfies the nagios.PassiveSubmitter interface.
+func (c *Client) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	switch {
+	case c.address == "":
+		return ErrMissingAddress
+	case len(results) == 0:
+		return ErrNoCheckResults
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, "tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NSCA daemon: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set NSCA connection deadline: %w", err)
+	}
+
+	iv, timestamp, err := readInitPacket(conn)
+	if err != nil {
+		return err
+	}
+
+	crypter, err := newPacketCrypter(c.encryption, c.password, iv, false)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		packet := EncodePacket(Packet{
+			Timestamp:          timestamp,
+			ReturnCode:         result.ExitStatusCode,
+			HostName:           result.HostName,
+			ServiceDescription: result.ServiceDescription,
+			PluginOutput:       EscapeOutput(result.Output),
+		}, c.maxOutputLength)
+
+		crypter.crypt(packet)
+
+		if _, err := conn.Write(packet); err != nil {
+			return fmt.Errorf(
+				"failed to send check result for %s/%s to NSCA daemon: %w",
+				result.HostName,
+				result.ServiceDescription,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// readInitPacket reads the IV and timestamp sent by the NSCA daemon
+// immediately after a connection is established.
+func readInitPacket(r io.Reader) ([]byte, time.Time, error) {
+	buf := make([]byte, initPacketSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read NSCA initialization packet: %w", err)
+	}
+
+	ts := binary.BigEndian.Uint32(buf[ivSize:])
+
+	return buf[:ivSize], time.Unix(int64(ts), 0), nil
+}
+
+// EncodeInitPacket returns the initialization packet (IV and timestamp) sent
+// by an NSCA daemon to a newly connected client.
+func EncodeInitPacket(iv []byte, timestamp time.Time) []byte {
+	buf := make([]byte, initPacketSize)
+	copy(buf, iv)
+	binary.BigEndian.PutUint32(buf[ivSize:], uint32(timestamp.Unix()))
+
+	return buf
+}
+
+// PacketSize returns the total size of a data packet using the given plugin
+// output field size. The size includes trailing alignment padding.
+func PacketSize(maxOutputLength int) int {
+	size := packetHeaderSize + maxHostNameLength + maxDescriptionLength + maxOutputLength
+
+	// Align to 4 bytes to match the C struct layout.
+	if rem := size % 4; rem != 0 {
+		size += 4 - rem
+	}
+
+	return size
+}
+
+// EncodePacket returns the given packet in unencrypted wire format using the
+// given plugin output field size. Fields which exceed the available space
+// are truncated.
+func EncodePacket(packet Packet, maxOutputLength int) []byte {
+	buf := make([]byte, PacketSize(maxOutputLength))
+
+	binary.BigEndian.PutUint16(buf[0:], uint16(packetVersion))
+	binary.BigEndian.PutUint32(buf[8:], uint32(packet.Timestamp.Unix()))
+	binary.BigEndian.PutUint16(buf[12:], uint16(int16(packet.ReturnCode)))
+
+	offset := packetHeaderSize
+	putString(buf[offset:offset+maxHostNameLength], packet.HostName)
+
+	offset += maxHostNameLength
+	putString(buf[offset:offset+maxDescriptionLength], packet.ServiceDescription)
+
+	offset += maxDescriptionLength
+	putString(buf[offset:offset+maxOutputLength], packet.PluginOutput)
+
+	// The CRC32 value is calculated with the CRC32 field zeroed.
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(buf))
+
+	return buf
+}
+
+// DecodePacket parses the given unencrypted wire format packet using the
+// given plugin output field size. An error is returned if the packet is
+// malformed or fails CRC32 validation.
+func DecodePacket(buf []byte, maxOutputLength int) (Packet, error) {
+	if len(buf) != PacketSize(maxOutputLength) {
+		return Packet{}, fmt.Errorf(
+			"packet size %d does not match expected size %d: %w",
+			len(buf),
+			PacketSize(maxOutputLength),
+			ErrInvalidPacket,
+		)
+	}
+
+	if version := int16(binary.BigEndian.Uint16(buf[0:])); version != packetVersion {
+		return Packet{}, fmt.Errorf(
+			"packet version %d not supported: %w",
+			version,
+			ErrInvalidPacket,
+		)
+	}
+
+	want := binary.BigEndian.Uint32(buf[4:])
+
+	verify := make([]byte, len(buf))
+	copy(verify, buf)
+	binary.BigEndian.PutUint32(verify[4:], 0)
+
+	if got := crc32.ChecksumIEEE(verify); got != want {
+		return Packet{}, fmt.Errorf("packet CRC32 mismatch: %w", ErrInvalidPacket)
+	}
+
+	offset := packetHeaderSize
+	host := getString(buf[offset : offset+maxHostNameLength])
+
+	offset += maxHostNameLength
+	service := getString(buf[offset : offset+maxDescriptionLength])
+
+	offset += maxDescriptionLength
+	output := getString(buf[offset : offset+maxOutputLength])
+
+	return Packet{
+		Timestamp:          time.Unix(int64(binary.BigEndian.Uint32(buf[8:])), 0),
+		ReturnCode:         int(int16(binary.BigEndian.Uint16(buf[12:]))),
+		HostName:           host,
+		ServiceDescription: service,
+		PluginOutput:       output,
+	}, nil
+}
+
+// EscapeOutput escapes the given plugin output for transmission via NSCA.
+// Backslashes and newlines are escaped so that multi-line output is
+// preserved when the check result is processed by Nagios.
+func EscapeOutput(output string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"\r", "",
+		"\n", `\n`,
+	)
+
+	return replacer.Replace(output)
+}
+
+// putString copies the given value into the fixed size field, truncating it
+// if needed to retain a trailing null byte.
+func putString(field []byte, value string) {
+	if len(value) > len(field)-1 {
+		value = value[:len(field)-1]
+	}
+	copy(field, value)
+}
+
+// getString returns the null-terminated string value of the given field.
+func getString(field []byte) string {
+	for i, b := range field {
+		if b == 0 {
+			return string(field[:i])
+		}
+	}
+
+	return string(field)
+}
@@ -0,0 +1,223 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nsca
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure Client satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*Client)(nil)
+
+// fakeDaemon accepts a single connection, sends an initialization packet and
+// decodes the requested number of packets using the given encryption
+// settings.
+func fakeDaemon(
+	t *testing.T,
+	method EncryptionMethod,
+	password string,
+	maxOutputLength int,
+	count int,
+) (string, <-chan []Packet) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	iv := make([]byte, ivSize)
+	for i := range iv {
+		iv[i] = byte(i * 7)
+	}
+
+	packets := make(chan []Packet, 1)
+
+	go func() {
+		defer close(packets)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		if _, err := conn.Write(EncodeInitPacket(iv, time.Unix(1700000000, 0))); err != nil {
+			t.Errorf("ERROR: failed to send init packet: %v", err)
+			return
+		}
+
+		crypter, err := newPacketCrypter(method, password, iv, true)
+		if err != nil {
+			t.Errorf("ERROR: failed to create crypter: %v", err)
+			return
+		}
+
+		received := make([]Packet, 0, count)
+		for i := 0; i < count; i++ {
+			buf := make([]byte, PacketSize(maxOutputLength))
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				t.Errorf("ERROR: failed to read packet: %v", err)
+				return
+			}
+			crypter.crypt(buf)
+
+			packet, err := DecodePacket(buf, maxOutputLength)
+			if err != nil {
+				t.Errorf("ERROR: failed to decode packet: %v", err)
+				return
+			}
+			received = append(received, packet)
+		}
+
+		packets <- received
+	}()
+
+	return listener.Addr().String(), packets
+}
+
+func TestClient_Submit_SendsDecodablePackets(t *testing.T) {
+	t.Parallel()
+
+	methods := []EncryptionMethod{
+		EncryptionNone,
+		EncryptionXOR,
+		EncryptionDES,
+		EncryptionTripleDES,
+		EncryptionRijndael128,
+	}
+
+	results := []nagios.PassiveCheckResult{
+		{HostName: "web01", ExitStatusCode: nagios.StateOKExitCode, Output: "UP"},
+		{
+			HostName:           "web01",
+			ServiceDescription: "HTTP",
+			ExitStatusCode:     nagios.StateCRITICALExitCode,
+			Output:             "CRITICAL: down\nline two | 'time'=5ms;;;;",
+		},
+	}
+
+	for _, method := range methods {
+		method := method
+		t.Run(method.String(), func(t *testing.T) {
+			t.Parallel()
+
+			addr, packets := fakeDaemon(t, method, "s3cr3t", MaxPluginOutputLength, len(results))
+
+			client := NewClient(addr)
+			client.SetEncryption(method, "s3cr3t")
+
+			if err := client.Submit(context.Background(), results...); err != nil {
+				t.Fatalf("ERROR: unexpected submission failure: %v", err)
+			}
+
+			received := <-packets
+			if len(received) != len(results) {
+				t.Fatalf("ERROR: want %d packets, got %d", len(results), len(received))
+			}
+
+			for i, packet := range received {
+				switch {
+				case packet.HostName != results[i].HostName:
+					t.Errorf("ERROR: want host %q, got %q", results[i].HostName, packet.HostName)
+				case packet.ServiceDescription != results[i].ServiceDescription:
+					t.Errorf("ERROR: want service %q, got %q", results[i].ServiceDescription, packet.ServiceDescription)
+				case packet.ReturnCode != results[i].ExitStatusCode:
+					t.Errorf("ERROR: want return code %d, got %d", results[i].ExitStatusCode, packet.ReturnCode)
+				case packet.PluginOutput != EscapeOutput(results[i].Output):
+					t.Errorf("ERROR: want output %q, got %q", EscapeOutput(results[i].Output), packet.PluginOutput)
+				case packet.Timestamp.Unix() != 1700000000:
+					t.Errorf("ERROR: want daemon timestamp, got %v", packet.Timestamp)
+				}
+			}
+
+			t.Log("OK: packets decoded as expected")
+		})
+	}
+}
+
+func TestClient_Submit_TruncatesLegacyPacketOutput(t *testing.T) {
+	t.Parallel()
+
+	addr, packets := fakeDaemon(t, EncryptionXOR, "", LegacyMaxPluginOutputLength, 1)
+
+	client := NewClient(addr)
+	client.SetEncryption(EncryptionXOR, "")
+	client.UseLegacyPacketSize()
+
+	result := nagios.PassiveCheckResult{
+		HostName:           "web01",
+		ServiceDescription: "HTTP",
+		Output:             strings.Repeat("x", 1000),
+	}
+
+	if err := client.Submit(context.Background(), result); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	received := <-packets
+	if len(received) != 1 {
+		t.Fatalf("ERROR: want 1 packet, got %d", len(received))
+	}
+
+	if got := len(received[0].PluginOutput); got != LegacyMaxPluginOutputLength-1 {
+		t.Fatalf("ERROR: want output length %d, got %d", LegacyMaxPluginOutputLength-1, got)
+	}
+
+	t.Log("OK: legacy packet output truncated as expected")
+}
+
+func TestClient_Submit_RejectsUnsupportedEncryption(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := fakeDaemon(t, EncryptionNone, "", MaxPluginOutputLength, 0)
+
+	client := NewClient(addr)
+	client.SetEncryption(EncryptionMethod(99), "s3cr3t")
+
+	err := client.Submit(context.Background(), nagios.PassiveCheckResult{HostName: "web01"})
+	if !errors.Is(err, ErrUnsupportedEncryption) {
+		t.Fatalf("ERROR: want %v, got %v", ErrUnsupportedEncryption, err)
+	}
+
+	t.Log("OK: unsupported encryption method rejected as expected")
+}
+
+func TestPacketSize_MatchesNSCAStructSize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]int{
+		MaxPluginOutputLength:       4304,
+		LegacyMaxPluginOutputLength: 720,
+	}
+
+	for outputLength, want := range tests {
+		if got := PacketSize(outputLength); got != want {
+			t.Errorf("ERROR: want packet size %d for output length %d, got %d", want, outputLength, got)
+		}
+	}
+}
+
+func TestNewClient_AppliesDefaultPort(t *testing.T) {
+	t.Parallel()
+
+	if got := NewClient("nagios.example.com").address; got != "nagios.example.com:5667" {
+		t.Fatalf("ERROR: want default port applied, got %q", got)
+	}
+
+	t.Log("OK: default port applied as expected")
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// openMetricsUnit describes how a performance data unit of measurement maps
+// to an OpenMetrics base unit.
+type openMetricsUnit struct {
+	unit       string
+	multiplier float64
+}
+
+// openMetricsUnits maps (lowercase) performance data units of measurement to
+// OpenMetrics base units.
+var openMetricsUnits = map[string]openMetricsUnit{
+	"s":  {unit: "seconds", multiplier: 1},
+	"ms": {unit: "seconds", multiplier: 1e-3},
+	"us": {unit: "seconds", multiplier: 1e-6},
+	"b":  {unit: "bytes", multiplier: 1},
+	"kb": {unit: "bytes", multiplier: 1 << 10},
+	"mb": {unit: "bytes", multiplier: 1 << 20},
+	"gb": {unit: "bytes", multiplier: 1 << 30},
+	"tb": {unit: "bytes", multiplier: 1 << 40},
+	"%":  {unit: "percent", multiplier: 1},
+}
+
+// WritePerfDataOpenMetrics writes the given performance data metrics to w
+// using the OpenMetrics text exposition format.
+//
+// Each metric is written as a separate metric family. The family name is
+// derived from the (sanitized) label. Time and byte based units of
+// measurement are normalized to seconds and bytes and noted via UNIT
+// metadata. Continuous counters (UoM "c") are written as counters, all other
+// metrics are written as gauges. HELP metadata notes the original label and
+// unit of measurement.
+//
+// Metrics with an undetermined value ("U") are written with a NaN value. An
+// error is returned if a metric value is invalid or if two metrics map to the
+// same family name; nothing is written in that case.
+func WritePerfDataOpenMetrics(w io.Writer, metrics ...PerformanceData) error {
+	var buf strings.Builder
+	seen := make(map[string]string, len(metrics))
+
+	for _, pd := range metrics {
+		name := openMetricsName(pd.Label)
+
+		value, err := openMetricsValue(pd.Value)
+		if err != nil {
+			return fmt.Errorf("metric %q: %w", pd.Label, err)
+		}
+
+		metricType := "gauge"
+		sampleName := name
+		var unit string
+
+		uom := strings.ToLower(pd.UnitOfMeasurement)
+		switch conversion, ok := openMetricsUnits[uom]; {
+		case ok:
+			unit = conversion.unit
+			name += "_" + unit
+			sampleName = name
+			if value != "NaN" && conversion.multiplier != 1 {
+				f, _ := strconv.ParseFloat(value, 64)
+				value = strconv.FormatFloat(f*conversion.multiplier, 'g', -1, 64)
+			}
+		case uom == "c":
+			metricType = "counter"
+			sampleName = name + "_total"
+		}
+
+		if label, ok := seen[name]; ok {
+			return fmt.Errorf(
+				"labels %q and %q both map to %q: %w",
+				label,
+				pd.Label,
+				name,
+				ErrInvalidPerformanceDataFormat,
+			)
+		}
+		seen[name] = pd.Label
+
+		help := fmt.Sprintf("Nagios performance data metric %q", pd.Label)
+		if pd.UnitOfMeasurement != "" {
+			help += fmt.Sprintf(" (UoM %q)", pd.UnitOfMeasurement)
+		}
+
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, metricType)
+		if unit != "" {
+			fmt.Fprintf(&buf, "# UNIT %s %s\n", name, unit)
+		}
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, openMetricsEscape(help))
+		fmt.Fprintf(&buf, "%s %s\n", sampleName, value)
+	}
+
+	buf.WriteString("# EOF\n")
+
+	if _, err := io.WriteString(w, buf.String()); err != nil {
+		return fmt.Errorf("failed to write OpenMetrics output: %w", err)
+	}
+
+	return nil
+}
+
+// openMetricsName converts the given performance data label to a valid
+// OpenMetrics metric name. Invalid characters are replaced with underscores
+// and a leading underscore is added if the label begins with a digit.
+func openMetricsName(label string) string {
+	var b strings.Builder
+	b.Grow(len(label) + 1)
+
+	for i, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
+
+// openMetricsValue validates and normalizes the given performance data value.
+// The undetermined value "U" is converted to NaN.
+func openMetricsValue(value string) (string, error) {
+	if value == "U" {
+		return "NaN", nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", fmt.Errorf(
+			"value %q is not numeric: %w",
+			value,
+			ErrInvalidPerformanceDataFormat,
+		)
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
+
+// openMetricsEscape escapes backslashes, double quotes and newlines in
+// metadata text.
+func openMetricsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWritePerfDataOpenMetrics_ProducesValidExposition(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+
+	err := nagios.WritePerfDataOpenMetrics(
+		&buf,
+		nagios.PerformanceData{Label: "time", Value: "874", UnitOfMeasurement: "ms"},
+		nagios.PerformanceData{Label: "packets sent", Value: "12", UnitOfMeasurement: "c"},
+		nagios.PerformanceData{Label: "load1", Value: "U"},
+	)
+	if err != nil {
+		t.Fatalf("ERROR: unexpected failure: %v", err)
+	}
+
+	want := "# TYPE time_seconds gauge\n" +
+		"# UNIT time_seconds seconds\n" +
+		"# HELP time_seconds Nagios performance data metric \\\"time\\\" (UoM \\\"ms\\\")\n" +
+		"time_seconds 0.874\n" +
+		"# TYPE packets_sent counter\n" +
+		"# HELP packets_sent Nagios performance data metric \\\"packets sent\\\" (UoM \\\"c\\\")\n" +
+		"packets_sent_total 12\n" +
+		"# TYPE load1 gauge\n" +
+		"# HELP load1 Nagios performance data metric \\\"load1\\\"\n" +
+		"load1 NaN\n" +
+		"# EOF\n"
+
+	if d := cmp.Diff(want, buf.String()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: OpenMetrics exposition written as expected")
+}
+
+func TestWritePerfDataOpenMetrics_RejectsInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+
+	err := nagios.WritePerfDataOpenMetrics(&buf, nagios.PerformanceData{Label: "a", Value: "abc"})
+	if !errors.Is(err, nagios.ErrInvalidPerformanceDataFormat) {
+		t.Fatalf("ERROR: want %v, got %v", nagios.ErrInvalidPerformanceDataFormat, err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("ERROR: want no output on failure, got %q", buf.String())
+	}
+
+	t.Log("OK: invalid value rejected as expected")
+}
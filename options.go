@@ -0,0 +1,87 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"io"
+)
+
+// PluginOption is a functional option used to configure a Plugin value when
+// it is created via the constructor (see NewPlugin). Options are applied in
+// the order given and after any debug logging settings provided via
+// environment variables (see DebugEnvVar) are applied.
+//
+// Each option is equivalent to the Plugin method it wraps; the methods
+// remain available and may be used alongside options.
+type PluginOption func(p *Plugin)
+
+// WithOutputTarget returns an option which assigns a target for plugin
+// output. See Plugin.SetOutputTarget.
+func WithOutputTarget(w io.Writer) PluginOption {
+	return func(p *Plugin) {
+		p.SetOutputTarget(w)
+	}
+}
+
+// WithExitFunc returns an option which overrides the os.Exit(x) call used to
+// signal the plugin state with the given function. See Plugin.SetExitFunc.
+func WithExitFunc(fn func(code int)) PluginOption {
+	return func(p *Plugin) {
+		p.SetExitFunc(fn)
+	}
+}
+
+// WithDebugLogging returns an option which enables all debug logging options
+// and emits debug log messages to the given target. If the target is nil the
+// default debug log target (os.Stderr) is used. See
+// Plugin.DebugLoggingEnableAll and Plugin.SetDebugLoggingOutputTarget.
+func WithDebugLogging(w io.Writer) PluginOption {
+	return func(p *Plugin) {
+		if w != nil {
+			p.SetDebugLoggingOutputTarget(w)
+		}
+
+		p.DebugLoggingEnableAll()
+	}
+}
+
+// WithLabels returns an option which overrides the text emitted by this
+// library as part of plugin output using the given label bundle. See
+// Plugin.SetLabels.
+func WithLabels(labels Labels) PluginOption {
+	return func(p *Plugin) {
+		p.SetLabels(labels)
+	}
+}
+
+// WithEncodedPayloadDelimiters returns an option which overrides the default
+// left and right delimiters used when encoding a provided payload. See
+// Plugin.SetEncodedPayloadDelimiterLeft and
+// Plugin.SetEncodedPayloadDelimiterRight.
+func WithEncodedPayloadDelimiters(left string, right string) PluginOption {
+	return func(p *Plugin) {
+		p.SetEncodedPayloadDelimiterLeft(left)
+		p.SetEncodedPayloadDelimiterRight(right)
+	}
+}
+
+// WithoutDefaultTimeMetric returns an option which disables the default time
+// performance data metric. See Plugin.SkipDefaultTimeMetric.
+func WithoutDefaultTimeMetric() PluginOption {
+	return func(p *Plugin) {
+		p.SkipDefaultTimeMetric()
+	}
+}
+
+// SkipDefaultTimeMetric indicates that the default time performance data
+// metric recorded by plugins created via the constructor should not be
+// emitted. A time metric provided by client code is still emitted.
+func (p *Plugin) SkipDefaultTimeMetric() {
+	p.logAction("Skipping default time metric as requested")
+	p.skipDefaultTimeMetric = true
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestNewPlugin_WithOptions_AppliesOptions(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+	var debugBuffer strings.Builder
+
+	labels := nagios.DefaultLabels()
+	labels.Errors = "PROBLEMS"
+
+	plugin := nagios.NewPlugin(
+		nagios.WithOutputTarget(&outputBuffer),
+		nagios.WithDebugLogging(&debugBuffer),
+		nagios.WithLabels(labels),
+		nagios.WithEncodedPayloadDelimiters("<<", ">>"),
+		nagios.WithoutDefaultTimeMetric(),
+		nil,
+	)
+	plugin.SkipOSExit()
+
+	plugin.ServiceOutput = "CRITICAL: datastore usage high"
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.AddError(nagios.ErrInvalidRangeThreshold)
+
+	if _, err := plugin.AddPayloadString("payload"); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+
+	for _, want := range []string{"**PROBLEMS**", "<<", ">>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: want %q in output, got:\n%q", want, got)
+		}
+	}
+
+	if strings.Contains(got, "'time'=") {
+		t.Errorf("ERROR: unexpected default time metric in output:\n%q", got)
+	}
+
+	if debugBuffer.Len() == 0 {
+		t.Error("ERROR: want debug log output, got none")
+	}
+
+	t.Log("OK: constructor options applied as expected")
+}
+
+func TestNewPlugin_WithoutOptions_EmitsDefaultTimeMetric(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin(nagios.WithOutputTarget(&outputBuffer))
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "OK: all good"
+	plugin.ReturnCheckResults()
+
+	if got := outputBuffer.String(); !strings.Contains(got, "'time'=") {
+		t.Fatalf("ERROR: want default time metric in output, got:\n%q", got)
+	}
+
+	t.Log("OK: default time metric emitted as expected")
+}
+
+func TestPlugin_SkipDefaultTimeMetric_KeepsClientTimeMetric(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin(
+		nagios.WithOutputTarget(&outputBuffer),
+		nagios.WithoutDefaultTimeMetric(),
+	)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "OK: all good"
+
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "time", Value: "42", UnitOfMeasurement: "ms"}); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	if got := outputBuffer.String(); !strings.Contains(got, "'time'=42ms") {
+		t.Fatalf("ERROR: want client time metric in output, got:\n%q", got)
+	}
+
+	t.Log("OK: client time metric emitted as expected")
+}
+
+func TestNewPlugin_WithExitFunc_ReceivesExitCode(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	exitCode := -1
+
+	plugin := nagios.NewPlugin(
+		nagios.WithOutputTarget(&outputBuffer),
+		nagios.WithExitFunc(func(code int) { exitCode = code }),
+	)
+	plugin.ServiceOutput = "CRITICAL: service down"
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ReturnCheckResults()
+
+	if exitCode != nagios.StateCRITICALExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, exitCode)
+	}
+
+	t.Log("OK: custom exit function called with expected exit code")
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package otelbridge records Nagios plugin performance data onto OpenTelemetry
+style metric instruments and optionally reports the check run as a span.
+
+# OVERVIEW
+
+This package does not depend on the OpenTelemetry SDK. Instead it defines
+small interfaces (InstrumentProvider, Float64Gauge, Float64Counter, Tracer
+and Span) modeled after the OpenTelemetry metric and trace APIs. Client code
+satisfies these interfaces with thin wrappers around an OpenTelemetry Meter
+and Tracer, allowing the same check logic to publish to both Nagios and an
+OpenTelemetry pipeline.
+
+Performance data metrics using the continuous counter unit of measurement
+("c") are recorded onto counters; the difference from the previously
+recorded value is added. All other metrics are recorded onto gauges. Metrics
+with an undetermined value ("U") are skipped.
+
+# HOW TO USE
+
+	bridge := otelbridge.NewBridge(provider, otelbridge.Attribute{Key: "service", Value: "HTTP"})
+
+	ctx, finish := otelbridge.StartCheckSpan(ctx, tracer, "check_http")
+	defer finish(plugin)
+
+	// ... perform check, collect performance data ...
+
+	if err := bridge.Record(ctx, plugin.PerfData()...); err != nil {
+		// handle error
+	}
+*/
+package otelbridge
@@ -0,0 +1,176 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package otelbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// counterUoM is the performance data unit of measurement indicating a
+// continuous counter.
+const counterUoM string = "c"
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingInstrumentProvider indicates that an InstrumentProvider was
+	// not provided.
+	ErrMissingInstrumentProvider = errors.New("instrument provider not specified")
+
+	// ErrInvalidMetricValue indicates that a performance data value could
+	// not be converted to a numeric value.
+	ErrInvalidMetricValue = errors.New("invalid performance data value")
+)
+
+// Attribute is a key/value pair associated with recorded measurements or
+// spans.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Float64Gauge records the current value of a measurement.
+type Float64Gauge interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Float64Counter records monotonically increasing values.
+type Float64Counter interface {
+	Add(ctx context.Context, incr float64, attrs ...Attribute)
+}
+
+// InstrumentProvider creates metric instruments. Implementations typically
+// wrap an OpenTelemetry Meter.
+type InstrumentProvider interface {
+	Float64Gauge(name string, unit string, description string) (Float64Gauge, error)
+	Float64Counter(name string, unit string, description string) (Float64Counter, error)
+}
+
+// Bridge records performance data onto metric instruments created by an
+// InstrumentProvider. Instruments are created once per performance data
+// label and reused. Bridge is safe for concurrent use.
+type Bridge struct {
+	provider InstrumentProvider
+	attrs    []Attribute
+
+	mu           sync.Mutex
+	gauges       map[string]Float64Gauge
+	counters     map[string]Float64Counter
+	counterState map[string]float64
+}
+
+// NewBridge constructs a new Bridge using the given InstrumentProvider. The
+// given attributes are applied to all recorded measurements.
+func NewBridge(provider InstrumentProvider, attrs ...Attribute) *Bridge {
+	return &Bridge{
+		provider:     provider,
+		attrs:        attrs,
+		gauges:       make(map[string]Float64Gauge),
+		counters:     make(map[string]Float64Counter),
+		counterState: make(map[string]float64),
+	}
+}
+
+// Record records the given performance data metrics. Processing stops at the
+// first metric which could not be recorded.
+func (b *Bridge) Record(ctx context.Context, metrics ...nagios.PerformanceData) error {
+	if b.provider == nil {
+		return ErrMissingInstrumentProvider
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pd := range metrics {
+		if pd.Value == "U" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(pd.Value, 64)
+		if err != nil {
+			return fmt.Errorf("metric %q value %q: %w", pd.Label, pd.Value, ErrInvalidMetricValue)
+		}
+
+		name := InstrumentName(pd.Label)
+		description := fmt.Sprintf("Nagios performance data metric %q", pd.Label)
+
+		if pd.UnitOfMeasurement == counterUoM {
+			if err := b.recordCounter(ctx, name, description, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		gauge, ok := b.gauges[name]
+		if !ok {
+			gauge, err = b.provider.Float64Gauge(name, pd.UnitOfMeasurement, description)
+			if err != nil {
+				return fmt.Errorf("failed to create gauge %q: %w", name, err)
+			}
+			b.gauges[name] = gauge
+		}
+
+		gauge.Record(ctx, value, b.attrs...)
+	}
+
+	return nil
+}
+
+// recordCounter adds the difference between the given cumulative value and
+// the previously recorded value to the named counter. If the value decreased
+// (e.g., counter reset) the full value is added.
+func (b *Bridge) recordCounter(ctx context.Context, name string, description string, value float64) error {
+	counter, ok := b.counters[name]
+	if !ok {
+		var err error
+		counter, err = b.provider.Float64Counter(name, "", description)
+		if err != nil {
+			return fmt.Errorf("failed to create counter %q: %w", name, err)
+		}
+		b.counters[name] = counter
+	}
+
+	incr := value
+	if previous, seen := b.counterState[name]; seen && value >= previous {
+		incr = value - previous
+	}
+	b.counterState[name] = value
+
+	counter.Add(ctx, incr, b.attrs...)
+
+	return nil
+}
+
+// InstrumentName converts the given performance data label to an instrument
+// name. Characters other than ASCII letters, digits, underscores, periods
+// and hyphens are replaced with underscores and the result is prefixed with
+// "nagios.".
+func InstrumentName(label string) string {
+	var b strings.Builder
+	b.Grow(len(label) + 7)
+	b.WriteString("nagios.")
+
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package otelbridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+type measurement struct {
+	Instrument string
+	Value      float64
+}
+
+type fakeProvider struct {
+	created      []string
+	measurements []measurement
+}
+
+type fakeInstrument struct {
+	name     string
+	provider *fakeProvider
+}
+
+func (f fakeInstrument) Record(_ context.Context, value float64, _ ...Attribute) {
+	f.provider.measurements = append(f.provider.measurements, measurement{f.name, value})
+}
+
+func (f fakeInstrument) Add(_ context.Context, incr float64, _ ...Attribute) {
+	f.provider.measurements = append(f.provider.measurements, measurement{f.name, incr})
+}
+
+func (p *fakeProvider) Float64Gauge(name string, _ string, _ string) (Float64Gauge, error) {
+	p.created = append(p.created, "gauge:"+name)
+	return fakeInstrument{name, p}, nil
+}
+
+func (p *fakeProvider) Float64Counter(name string, _ string, _ string) (Float64Counter, error) {
+	p.created = append(p.created, "counter:"+name)
+	return fakeInstrument{name, p}, nil
+}
+
+type fakeSpan struct {
+	attrs       []Attribute
+	code        StatusCode
+	description string
+	ended       bool
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...Attribute) { s.attrs = append(s.attrs, attrs...) }
+
+func (s *fakeSpan) SetStatus(code StatusCode, description string) {
+	s.code, s.description = code, description
+}
+
+func (s *fakeSpan) End() { s.ended = true }
+
+type fakeTracer struct {
+	span *fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	t.span = &fakeSpan{}
+	return ctx, t.span
+}
+
+func TestBridge_Record_UsesGaugesAndCounterDeltas(t *testing.T) {
+	t.Parallel()
+
+	provider := &fakeProvider{}
+	bridge := NewBridge(provider)
+	ctx := context.Background()
+
+	for _, sent := range []string{"100", "150", "20"} {
+		err := bridge.Record(ctx,
+			nagios.PerformanceData{Label: "time", Value: "5", UnitOfMeasurement: "ms"},
+			nagios.PerformanceData{Label: "bytes sent", Value: sent, UnitOfMeasurement: "c"},
+			nagios.PerformanceData{Label: "unknown", Value: "U"},
+		)
+		if err != nil {
+			t.Fatalf("ERROR: unexpected failure: %v", err)
+		}
+	}
+
+	wantCreated := []string{"gauge:nagios.time", "counter:nagios.bytes_sent"}
+	if d := cmp.Diff(wantCreated, provider.created); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	wantMeasurements := []measurement{
+		{"nagios.time", 5}, {"nagios.bytes_sent", 100},
+		{"nagios.time", 5}, {"nagios.bytes_sent", 50},
+		{"nagios.time", 5}, {"nagios.bytes_sent", 20},
+	}
+	if d := cmp.Diff(wantMeasurements, provider.measurements); d != "" {
+		t.Errorf("(-want, +got)\n:%s", d)
+	}
+
+	err := bridge.Record(ctx, nagios.PerformanceData{Label: "a", Value: "abc"})
+	if !errors.Is(err, ErrInvalidMetricValue) {
+		t.Errorf("ERROR: want %v, got %v", ErrInvalidMetricValue, err)
+	}
+}
+
+func TestStartCheckSpan_SetsStatusFromPluginState(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]StatusCode{
+		nagios.StateOKExitCode:       StatusOK,
+		nagios.StateWARNINGExitCode:  StatusError,
+		nagios.StateCRITICALExitCode: StatusError,
+	}
+
+	for exitCode, want := range tests {
+		tracer := &fakeTracer{}
+		plugin := nagios.NewPlugin()
+		plugin.ExitStatusCode = exitCode
+
+		_, finish := StartCheckSpan(context.Background(), tracer, "check")
+		finish(plugin)
+
+		switch {
+		case !tracer.span.ended:
+			t.Errorf("ERROR: span not ended for exit code %d", exitCode)
+		case tracer.span.code != want:
+			t.Errorf("ERROR: want status %d for exit code %d, got %d", want, exitCode, tracer.span.code)
+		case len(tracer.span.attrs) != 2:
+			t.Errorf("ERROR: want 2 span attributes, got %d", len(tracer.span.attrs))
+		}
+	}
+}
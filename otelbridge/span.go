@@ -0,0 +1,88 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package otelbridge
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// StatusCode is the status of a span. Values match the OpenTelemetry trace
+// status codes.
+type StatusCode int
+
+// Supported span status codes.
+const (
+	// StatusUnset is the default span status.
+	StatusUnset StatusCode = 0
+
+	// StatusError indicates that the operation failed.
+	StatusError StatusCode = 1
+
+	// StatusOK indicates that the operation completed successfully.
+	StatusOK StatusCode = 2
+)
+
+// Span is a single traced operation. Implementations typically wrap an
+// OpenTelemetry Span.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	SetStatus(code StatusCode, description string)
+	End()
+}
+
+// Tracer starts spans. Implementations typically wrap an OpenTelemetry
+// Tracer.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span attribute keys used for check run spans.
+const (
+	AttributeKeyState    string = "nagios.state"
+	AttributeKeyExitCode string = "nagios.exit_code"
+)
+
+// StartCheckSpan starts a span for a check run using the given Tracer. The
+// returned function ends the span using the final state of the given plugin
+// and should be called (e.g., deferred) once the check has completed. A nil
+// Tracer results in a no-op.
+//
+// The span status is set to StatusOK for an OK plugin state and StatusError
+// (described by the state label) for all other states.
+func StartCheckSpan(ctx context.Context, tracer Tracer, name string) (context.Context, func(p *nagios.Plugin)) {
+	if tracer == nil {
+		return ctx, func(*nagios.Plugin) {}
+	}
+
+	spanCtx, span := tracer.Start(ctx, name)
+
+	return spanCtx, func(p *nagios.Plugin) {
+		defer span.End()
+
+		if p == nil {
+			return
+		}
+
+		state := nagios.ExitCodeToStateLabel(p.ExitStatusCode)
+
+		span.SetAttributes(
+			Attribute{Key: AttributeKeyState, Value: state},
+			Attribute{Key: AttributeKeyExitCode, Value: strconv.Itoa(p.ExitStatusCode)},
+		)
+
+		switch p.ExitStatusCode {
+		case nagios.StateOKExitCode:
+			span.SetStatus(StatusOK, "")
+		default:
+			span.SetStatus(StatusError, state)
+		}
+	}
+}
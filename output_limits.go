@@ -0,0 +1,86 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+// OutputLimitTarget identifies a monitoring system or transport which
+// receives plugin output and limits its length. See OutputLimitFor.
+type OutputLimitTarget int
+
+// Supported output limit targets.
+const (
+	// OutputLimitTargetNagiosCore is Nagios Core (and Nagios XI) executing
+	// the plugin directly.
+	OutputLimitTargetNagiosCore OutputLimitTarget = iota
+
+	// OutputLimitTargetNRPEv2 is a plugin executed via an NRPE v2 agent.
+	OutputLimitTargetNRPEv2
+
+	// OutputLimitTargetNRPEv3 is a plugin executed via an NRPE v3 agent.
+	OutputLimitTargetNRPEv3
+
+	// OutputLimitTargetNRPEv4 is a plugin executed via an NRPE v4 agent.
+	OutputLimitTargetNRPEv4
+
+	// OutputLimitTargetNSCA is a check result submitted via NSCA 2.9 or
+	// newer.
+	OutputLimitTargetNSCA
+
+	// OutputLimitTargetIcinga2 is Icinga 2 executing the plugin directly.
+	OutputLimitTargetIcinga2
+)
+
+// Practical maximum plugin output lengths (in bytes) of supported output
+// targets. Output beyond these lengths is truncated (or rejected) by the
+// output target.
+const (
+	// NagiosCoreMaxOutputLength is the maximum plugin output length read by
+	// Nagios Core (MAX_PLUGIN_OUTPUT_LENGTH).
+	NagiosCoreMaxOutputLength int = 8192
+
+	// NRPEv2MaxOutputLength is the maximum plugin output length returned by
+	// NRPE v2 agents (the fixed packet buffer size).
+	NRPEv2MaxOutputLength int = 1024
+
+	// NRPEv3MaxOutputLength is the maximum plugin output length returned by
+	// NRPE v3 agents.
+	NRPEv3MaxOutputLength int = 64 * 1024
+
+	// NRPEv4MaxOutputLength is the maximum plugin output length returned by
+	// NRPE v4 agents.
+	NRPEv4MaxOutputLength int = 64 * 1024
+
+	// NSCAMaxOutputLength is the size of the plugin output field used by
+	// NSCA 2.9 and newer.
+	NSCAMaxOutputLength int = 4096
+
+	// Icinga2MaxOutputLength is the maximum plugin output length read by
+	// Icinga 2. Icinga 2 does not truncate plugin output; a value of zero
+	// indicates that no limit applies.
+	Icinga2MaxOutputLength int = 0
+)
+
+// OutputLimitFor returns the practical maximum plugin output length (in
+// bytes) of the given output target. A value of zero indicates that no
+// limit applies. The Nagios Core limit is returned for unsupported output
+// targets.
+func OutputLimitFor(target OutputLimitTarget) int {
+	switch target {
+	case OutputLimitTargetNRPEv2:
+		return NRPEv2MaxOutputLength
+	case OutputLimitTargetNRPEv3:
+		return NRPEv3MaxOutputLength
+	case OutputLimitTargetNRPEv4:
+		return NRPEv4MaxOutputLength
+	case OutputLimitTargetNSCA:
+		return NSCAMaxOutputLength
+	case OutputLimitTargetIcinga2:
+		return Icinga2MaxOutputLength
+	default:
+		return NagiosCoreMaxOutputLength
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestOutputLimitFor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		target nagios.OutputLimitTarget
+		want   int
+	}{
+		"Nagios Core": {
+			target: nagios.OutputLimitTargetNagiosCore,
+			want:   8192,
+		},
+		"NRPE v2": {
+			target: nagios.OutputLimitTargetNRPEv2,
+			want:   1024,
+		},
+		"NRPE v3": {
+			target: nagios.OutputLimitTargetNRPEv3,
+			want:   65536,
+		},
+		"NRPE v4": {
+			target: nagios.OutputLimitTargetNRPEv4,
+			want:   65536,
+		},
+		"NSCA": {
+			target: nagios.OutputLimitTargetNSCA,
+			want:   4096,
+		},
+		"Icinga 2": {
+			target: nagios.OutputLimitTargetIcinga2,
+			want:   0,
+		},
+		"unsupported target": {
+			target: nagios.OutputLimitTarget(-1),
+			want:   nagios.NagiosCoreMaxOutputLength,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := nagios.OutputLimitFor(tt.target); got != tt.want {
+				t.Fatalf("ERROR: want output limit %d, got %d", tt.want, got)
+			}
+
+			t.Log("OK: output limit returned as expected")
+		})
+	}
+}
+
+func TestPreviewOutputWithLimit_UsesOutputLimit(t *testing.T) {
+	t.Parallel()
+
+	output := "OK: " + string(make([]byte, nagios.NRPEv2MaxOutputLength))
+
+	preview := nagios.PreviewOutputWithLimit(output, nagios.OutputLimitFor(nagios.OutputLimitTargetNRPEv2))
+
+	switch {
+	case !preview.Truncated:
+		t.Fatal("ERROR: want output truncated at NRPE v2 limit")
+	case preview.MaxLength != nagios.NRPEv2MaxOutputLength:
+		t.Fatalf("ERROR: want max length %d, got %d", nagios.NRPEv2MaxOutputLength, preview.MaxLength)
+	}
+
+	t.Log("OK: preview truncated at output limit as expected")
+}
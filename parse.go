@@ -0,0 +1,237 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParsePluginOutput parses rendered plugin output into a CheckResult. This
+// is the inverse of the output produced by ReturnCheckResults.
+//
+// Output is split following the Nagios plugin output specification: the
+// first line contains the one-line summary optionally followed by a pipe
+// character and performance data. The first pipe character found in later
+// lines begins additional performance data which continues through the end
+// of the output; the preceding lines are the long output.
+//
+// Output rendered by this library using the default section header labels
+// is handled specially; the errors, thresholds, detailed info and encoded
+// payload sections are parsed into the matching CheckResult fields and
+// performance data is taken from the final line. Content following the
+// encoded payload (e.g., branding text) is appended to LongServiceOutput.
+//
+// The exit status code is not part of plugin output; ExitStatusCode is set
+// to StateUNKNOWNExitCode and should be replaced by the caller with the
+// actual plugin exit code. If only the performance data is invalid, the
+// remaining fields are returned along with an error wrapping
+// ErrInvalidPerformanceDataFormat.
+func ParsePluginOutput(output string) (*CheckResult, error) {
+	output = strings.ReplaceAll(output, "\r", "")
+	if strings.TrimSpace(output) == "" {
+		return nil, fmt.Errorf("plugin output not provided: %w", ErrMissingValue)
+	}
+
+	lines := strings.Split(strings.TrimRight(output, " \t\n"), "\n")
+	rest := lines[1:]
+
+	result := CheckResult{
+		ExitStatusCode: StateUNKNOWNExitCode,
+	}
+
+	var perfData []string
+
+	switch {
+	case hasLibrarySections(rest):
+		result.ServiceOutput = strings.TrimSpace(lines[0])
+
+		last := len(rest) - 1
+		if trimmed := strings.TrimSpace(rest[last]); strings.HasPrefix(trimmed, "|") {
+			perfData = append(perfData, strings.TrimPrefix(trimmed, "|"))
+			rest = rest[:last]
+		}
+
+		result.parseSections(rest)
+
+	default:
+		summary, summaryPerfData, _ := strings.Cut(lines[0], "|")
+		result.ServiceOutput = strings.TrimSpace(summary)
+		perfData = append(perfData, summaryPerfData)
+
+		long := rest
+		for i, line := range rest {
+			before, after, found := strings.Cut(line, "|")
+			if !found {
+				continue
+			}
+
+			long = append(rest[:i:i], before)
+			perfData = append(perfData, after)
+			perfData = append(perfData, rest[i+1:]...)
+
+			break
+		}
+
+		result.LongServiceOutput = trimBlankLines(long)
+	}
+
+	for _, metric := range splitPerfDataMetrics(strings.Join(perfData, " ")) {
+		pd, err := parsePerfData(metric)
+		if err != nil {
+			return &result, err
+		}
+		result.PerfData = append(result.PerfData, pd)
+	}
+
+	return &result, nil
+}
+
+// librarySectionLabels is the collection of default section header labels
+// used to recognize output rendered by this library.
+var librarySectionLabels = []string{
+	defaultErrorsLabel,
+	defaultThresholdsLabel,
+	defaultDetailedInfoLabel,
+	defaultEncodedPayloadLabel,
+}
+
+// sectionHeaderLabel returns the default section label for the given output
+// line if it is a section header.
+func sectionHeaderLabel(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	for _, label := range librarySectionLabels {
+		if line == "**"+label+"**" {
+			return label, true
+		}
+	}
+
+	return "", false
+}
+
+// hasLibrarySections indicates whether the given output lines contain
+// section headers rendered by this library.
+func hasLibrarySections(lines []string) bool {
+	for _, line := range lines {
+		if _, ok := sectionHeaderLabel(line); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseSections parses output lines rendered by this library (excluding the
+// one-line summary and performance data) into the matching fields.
+func (cr *CheckResult) parseSections(lines []string) {
+	var section string
+	var long, trailing []string
+	var errMessages []string
+	var errHints []string
+
+	for _, line := range lines {
+		if label, ok := sectionHeaderLabel(line); ok {
+			section = label
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		switch section {
+		case defaultErrorsLabel:
+			last := len(errMessages) - 1
+
+			switch {
+			case strings.HasPrefix(trimmed, "* "):
+				errMessages = append(errMessages, strings.TrimPrefix(trimmed, "* "))
+				errHints = append(errHints, "")
+			case last >= 0 && strings.HasPrefix(trimmed, suggestedActionPrefix):
+				errHints[last] = strings.TrimPrefix(trimmed, suggestedActionPrefix)
+			case last >= 0 && trimmed != "":
+				errMessages[last] += "\n" + trimmed
+			}
+
+		case defaultThresholdsLabel:
+			switch {
+			case strings.HasPrefix(trimmed, "* "+StateCRITICALLabel+": "):
+				cr.CriticalThreshold = strings.TrimPrefix(trimmed, "* "+StateCRITICALLabel+": ")
+			case strings.HasPrefix(trimmed, "* "+StateWARNINGLabel+": "):
+				cr.WarningThreshold = strings.TrimPrefix(trimmed, "* "+StateWARNINGLabel+": ")
+			}
+
+		case defaultEncodedPayloadLabel:
+			switch {
+			case cr.EncodedPayload == "":
+				cr.EncodedPayload = trimmed
+			default:
+				trailing = append(trailing, line)
+			}
+
+		default:
+			long = append(long, line)
+		}
+	}
+
+	for i, msg := range errMessages {
+		switch {
+		case errHints[i] != "":
+			cr.Errors = append(cr.Errors, &ServiceCheckError{Message: msg, Hint: errHints[i]})
+		default:
+			cr.Errors = append(cr.Errors, errors.New(msg))
+		}
+	}
+
+	cr.LongServiceOutput = trimBlankLines(append(long, trailing...))
+}
+
+// trimBlankLines joins the given lines after removing leading and trailing
+// blank lines. Trailing whitespace is removed from the result.
+func trimBlankLines(lines []string) string {
+	start, end := 0, len(lines)
+
+	for start < end && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	return strings.TrimRight(strings.Join(lines[start:end], "\n"), " \t")
+}
+
+// splitPerfDataMetrics splits the given performance data into individual
+// metrics. Single quoted labels may contain spaces.
+func splitPerfDataMetrics(perfData string) []string {
+	var metrics []string
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			metrics = append(metrics, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range perfData {
+		switch {
+		case r == '\'':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\r' || r == '\n'):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return metrics
+}
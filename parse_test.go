@@ -0,0 +1,143 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestParsePluginOutput_ParsesStandardPluginOutput(t *testing.T) {
+	t.Parallel()
+
+	output := "DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968\n" +
+		"/ 15272 MB (77%);\n" +
+		"/boot 68 MB (69%);\n" +
+		"/home 69357 MB (27%); | /boot=68MB;88;93;0;98\n" +
+		"/home=69357MB;253404;253409;0;253414 'var log'=818MB;970;975;0;980\n"
+
+	got, err := nagios.ParsePluginOutput(output)
+	if err != nil {
+		t.Fatalf("ERROR: failed to parse plugin output: %v", err)
+	}
+
+	want := &nagios.CheckResult{
+		ExitStatusCode:    nagios.StateUNKNOWNExitCode,
+		ServiceOutput:     "DISK OK - free space: / 3326 MB (56%);",
+		LongServiceOutput: "/ 15272 MB (77%);\n/boot 68 MB (69%);\n/home 69357 MB (27%);",
+		PerfData: []nagios.PerformanceData{
+			{Label: "/", Value: "2643", UnitOfMeasurement: "MB", Warn: "5948", Crit: "5958", Min: "0", Max: "5968"},
+			{Label: "/boot", Value: "68", UnitOfMeasurement: "MB", Warn: "88", Crit: "93", Min: "0", Max: "98"},
+			{Label: "/home", Value: "69357", UnitOfMeasurement: "MB", Warn: "253404", Crit: "253409", Min: "0", Max: "253414"},
+			{Label: "var log", Value: "818", UnitOfMeasurement: "MB", Warn: "970", Crit: "975", Min: "0", Max: "980"},
+		},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: standard plugin output parsed as expected")
+}
+
+func TestParsePluginOutput_ParsesLibraryOutputSections(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ServiceOutput = "CRITICAL: 1 of 2 endpoints down | (not perfdata)"
+	plugin.LongServiceOutput = "* endpoint a: up | 12ms" + nagios.CheckOutputEOL + "* endpoint b: down"
+	plugin.WarningThreshold = "1 down"
+	plugin.CriticalThreshold = "2 down"
+	plugin.AddError(
+		errors.New("connection refused"),
+		&nagios.ServiceCheckError{Message: "timeout", Hint: "increase the plugin timeout"},
+	)
+	if _, err := plugin.AddPayloadString(`{"down":["b"]}`); err != nil {
+		t.Fatalf("ERROR: failed to add payload: %v", err)
+	}
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "down", Value: "1"},
+		nagios.PerformanceData{Label: "time", Value: "12", UnitOfMeasurement: "ms"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	got, err := nagios.ParsePluginOutput(outputBuffer.String())
+	if err != nil {
+		t.Fatalf("ERROR: failed to parse plugin output: %v", err)
+	}
+
+	want := &nagios.CheckResult{
+		ExitStatusCode:    nagios.StateUNKNOWNExitCode,
+		ServiceOutput:     plugin.ServiceOutput,
+		LongServiceOutput: "* endpoint a: up | 12ms \n* endpoint b: down",
+		Errors: []error{
+			errors.New("connection refused"),
+			&nagios.ServiceCheckError{Message: "timeout", Hint: "increase the plugin timeout"},
+		},
+		WarningThreshold:  "1 down",
+		CriticalThreshold: "2 down",
+		PerfData: []nagios.PerformanceData{
+			{Label: "down", Value: "1"},
+			{Label: "time", Value: "12", UnitOfMeasurement: "ms"},
+		},
+	}
+
+	errorText := cmp.Comparer(func(a, b error) bool { return a.Error() == b.Error() })
+	if d := cmp.Diff(want, got, errorText, cmpopts.IgnoreFields(nagios.CheckResult{}, "EncodedPayload")); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	var sce *nagios.ServiceCheckError
+	if !errors.As(got.Errors[1], &sce) || sce.Hint != "increase the plugin timeout" {
+		t.Errorf("ERROR: suggested action not parsed: %#v", got.Errors[1])
+	}
+
+	payload, err := nagios.ExtractAndDecodePayload(
+		got.EncodedPayload,
+		"",
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+	if err != nil || payload != `{"down":["b"]}` {
+		t.Fatalf("ERROR: failed to decode parsed payload %q: %q, %v", got.EncodedPayload, payload, err)
+	}
+
+	t.Log("OK: library output sections parsed as expected")
+}
+
+func TestParsePluginOutput_ReportsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	if _, err := nagios.ParsePluginOutput(" \n"); !errors.Is(err, nagios.ErrMissingValue) {
+		t.Errorf("ERROR: want %v, got %v", nagios.ErrMissingValue, err)
+	}
+
+	got, err := nagios.ParsePluginOutput("OK: fine | =broken")
+	if !errors.Is(err, nagios.ErrInvalidPerformanceDataFormat) {
+		t.Fatalf("ERROR: want %v, got %v", nagios.ErrInvalidPerformanceDataFormat, err)
+	}
+	if got == nil || got.ServiceOutput != "OK: fine" {
+		t.Fatalf("ERROR: want partial result for invalid perfdata, got %+v", got)
+	}
+
+	t.Log("OK: invalid input reported as expected")
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"time"
+)
+
+// PassiveCheckResult represents the rendered results of a service or host
+// check intended for passive submission to a monitoring system (e.g., via
+// NRDP, NSCA or the external command file) instead of being returned to the
+// monitoring system as the output and exit code of an actively scheduled
+// plugin.
+type PassiveCheckResult struct {
+	// HostName is the name of the host associated with the check result.
+	HostName string
+
+	// ServiceDescription is the description of the service associated with
+	// the check result. If empty, the check result is treated as a host
+	// check result.
+	ServiceDescription string
+
+	// ExitStatusCode is the exit or exit status code indicating the state of
+	// the host or service.
+	ExitStatusCode int
+
+	// Output is the complete plugin output (ServiceOutput, LongServiceOutput
+	// and performance data) as it would have been emitted by an actively
+	// scheduled plugin.
+	Output string
+
+	// Timestamp indicates when the check result was generated.
+	Timestamp time.Time
+}
+
+// PassiveSubmitter is implemented by types able to submit one or more passive
+// check results to a monitoring system.
+type PassiveSubmitter interface {
+	Submit(ctx context.Context, results ...PassiveCheckResult) error
+}
+
+// IsHostCheckResult indicates whether the check result is associated with a
+// host instead of a service.
+func (pcr PassiveCheckResult) IsHostCheckResult() bool {
+	return pcr.ServiceDescription == ""
+}
+
+// PassiveCheckResult renders the current plugin state as a passive check
+// result for the given host and service. If service is empty the result is
+// treated as a host check result.
+//
+// The exit code of the check result uses the host check states for host
+// check results and the service check states otherwise. Unless host check
+// mode is enabled (see SetHostCheckMode) the plugin state is a service check
+// state and is mapped for host check results: OK to UP and any other state to
+// DOWN. In host check mode the plugin state is mapped for service check
+// results: UP to OK, DOWN and UNREACHABLE to CRITICAL.
+//
+// Unlike ReturnCheckResults, no output is written to the plugin output
+// target and os.Exit is not called. The plugin timeout (if armed) is
+// disarmed.
+func (p *Plugin) PassiveCheckResult(host string, service string) PassiveCheckResult {
+	p.logAction("Rendering plugin output for passive check result")
+
+	p.disarmTimeoutForRender()
+
+	output := p.assembleOutput()
+
+	if p.shouldEmitTotalPluginSizeMetric {
+		output = addPluginOutputSizeMetric(output, p.outputEOL())
+	}
+
+	exitCode := p.ExitStatusCode
+	switch {
+	case service == "" && !p.hostCheckMode:
+		exitCode = hostStateFromExitCode(exitCode)
+	case service != "" && p.hostCheckMode:
+		exitCode = hostStateProcessExitCode(exitCode)
+	}
+
+	return PassiveCheckResult{
+		HostName:           host,
+		ServiceDescription: service,
+		ExitStatusCode:     exitCode,
+		Output:             output,
+		Timestamp:          time.Now(),
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_PassiveCheckResult_MapsStates(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		hostCheckMode bool
+		service       string
+		exitCode      int
+		wantExitCode  int
+	}{
+		"service result OK": {
+			service:      "HTTP",
+			exitCode:     nagios.StateOKExitCode,
+			wantExitCode: nagios.StateOKExitCode,
+		},
+		"service result WARNING": {
+			service:      "HTTP",
+			exitCode:     nagios.StateWARNINGExitCode,
+			wantExitCode: nagios.StateWARNINGExitCode,
+		},
+		"host result from OK service state": {
+			exitCode:     nagios.StateOKExitCode,
+			wantExitCode: nagios.StateUPExitCode,
+		},
+		"host result from WARNING service state": {
+			exitCode:     nagios.StateWARNINGExitCode,
+			wantExitCode: nagios.StateDOWNExitCode,
+		},
+		"host result from CRITICAL service state": {
+			exitCode:     nagios.StateCRITICALExitCode,
+			wantExitCode: nagios.StateDOWNExitCode,
+		},
+		"host result from UNKNOWN service state": {
+			exitCode:     nagios.StateUNKNOWNExitCode,
+			wantExitCode: nagios.StateDOWNExitCode,
+		},
+		"host result in host check mode": {
+			hostCheckMode: true,
+			exitCode:      nagios.StateUNREACHABLEExitCode,
+			wantExitCode:  nagios.StateUNREACHABLEExitCode,
+		},
+		"service result in host check mode": {
+			hostCheckMode: true,
+			service:       "PING",
+			exitCode:      nagios.StateDOWNExitCode,
+			wantExitCode:  nagios.StateCRITICALExitCode,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := nagios.NewPlugin()
+			if tt.hostCheckMode {
+				plugin.SetHostCheckMode()
+			}
+			plugin.ExitStatusCode = tt.exitCode
+			plugin.ServiceOutput = "check complete"
+
+			result := plugin.PassiveCheckResult("web01", tt.service)
+
+			if result.ExitStatusCode != tt.wantExitCode {
+				t.Fatalf("ERROR: want exit code %d, got %d", tt.wantExitCode, result.ExitStatusCode)
+			}
+
+			t.Log("OK: passive check result state mapped as expected")
+		})
+	}
+}
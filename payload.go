@@ -15,10 +15,89 @@ import (
 	"compress/gzip"
 	"encoding/ascii85"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"regexp"
+	"strings"
+	"sync"
 )
 
+// Payload codecs recorded by payload debug logging.
+const (
+	// payloadCodecGzipASCII85 indicates that the payload is compressed using
+	// gzip and then encoded as Ascii85.
+	payloadCodecGzipASCII85 string = "gzip+ascii85"
+
+	// payloadCodecASCII85 indicates that the payload is encoded as Ascii85
+	// without compression.
+	payloadCodecASCII85 string = "ascii85"
+)
+
+// maxPooledPayloadBufferSize is the maximum capacity (in bytes) of a buffer
+// returned to the payload buffer pool. Larger buffers are discarded so that
+// an occasional very large payload does not pin memory indefinitely.
+const maxPooledPayloadBufferSize int = 4 * 1024 * 1024
+
+// payloadBufferPool provides reusable buffers for payload compression and
+// encoding. Reusing buffers avoids allocating large temporary byte slices on
+// every emission for long running (e.g., daemon or aggregator) use cases.
+// Pooled buffers hold no state between uses and are safe for concurrent use
+// by separate Plugin values.
+var payloadBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// gzipWriterPool provides reusable gzip writers for payload compression. The
+// compressor state allocated by each gzip writer is large relative to
+// typical payloads.
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, err := gzip.NewWriterLevel(io.Discard, gzip.BestCompression)
+		if err != nil {
+			// Documentation notes that err is nil unless we specify an
+			// invalid level; since we use a stdlib package constant that's
+			// highly unlikely to produce a complaint, but we guard against
+			// it anyway.
+			panic("invalid compression level specified")
+		}
+
+		return w
+	},
+}
+
+// getPayloadBuffer returns an empty buffer from the payload buffer pool.
+func getPayloadBuffer() *bytes.Buffer {
+	buf := payloadBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	return buf
+}
+
+// putPayloadBuffer returns the given buffer to the payload buffer pool. The
+// buffer (and any slices of its content) must not be used afterwards.
+func putPayloadBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledPayloadBufferSize {
+		return
+	}
+
+	payloadBufferPool.Put(buf)
+}
+
+// payloadLogFunc is used to log payload encoding and decoding details. A nil
+// value disables logging.
+type payloadLogFunc func(msg string, attrs ...logAttr)
+
+// log logs the given message and attributes if logging is enabled.
+func (f payloadLogFunc) log(msg string, attrs ...logAttr) {
+	if f == nil {
+		return
+	}
+
+	f(msg, attrs...)
+}
+
 // getEncodedPayloadDelimiterLeft retrieves the custom left delimiter used
 // when enclosing an encoded payload if set, otherwise returns the default
 // value.
@@ -53,14 +132,26 @@ func encodeASCII85(data []byte, leftDelimiter string, rightDelimiter string) str
 		return ""
 	}
 
-	encoded := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	// Encode into a pooled scratch buffer sized for the worst case.
+	scratch := getPayloadBuffer()
+	defer putPayloadBuffer(scratch)
+
+	maxLen := ascii85.MaxEncodedLen(len(data))
+	scratch.Grow(maxLen)
+	encoded := scratch.Bytes()[:maxLen]
 
 	// Encode and trim the encoded slice to the exact number of encoded bytes.
 	n := ascii85.Encode(encoded, data)
 	encoded = encoded[:n]
 
-	// Add optional delimiters.
-	return leftDelimiter + string(encoded) + rightDelimiter
+	// Add optional delimiters, allocating the result only once.
+	var result strings.Builder
+	result.Grow(len(leftDelimiter) + n + len(rightDelimiter))
+	result.WriteString(leftDelimiter)
+	result.Write(encoded)
+	result.WriteString(rightDelimiter)
+
+	return result.String()
 }
 
 // unescapeASCII85 unescapes an Ascii85 input payload by removing escape
@@ -95,7 +186,10 @@ func EncodePayload(data []byte, leftDelimiter string, rightDelimiter string) str
 
 	var encoded string
 
-	compressedData, compressErr := compressPayloadContent(data)
+	compressedBuffer := getPayloadBuffer()
+	defer putPayloadBuffer(compressedBuffer)
+
+	compressErr := compressPayloadContentTo(compressedBuffer, data)
 	switch {
 	case compressErr != nil:
 		// Fallback to skipping compression if an error occurs, use original
@@ -103,7 +197,7 @@ func EncodePayload(data []byte, leftDelimiter string, rightDelimiter string) str
 		encoded = encodeASCII85(data, leftDelimiter, rightDelimiter)
 
 	default:
-		encoded = encodeASCII85(compressedData, leftDelimiter, rightDelimiter)
+		encoded = encodeASCII85(compressedBuffer.Bytes(), leftDelimiter, rightDelimiter)
 	}
 
 	return encoded
@@ -244,7 +338,9 @@ func DecodePayload(encodedInput []byte, leftDelimiter string, rightDelimiter str
 // If not provided, a default regular expression for the encoding format is
 // used to perform matching/extraction.
 //
-// If specified, delimiters are removed during the extraction process.
+// If specified, delimiters are matched literally (regular expression
+// metacharacters have no special meaning) and removed during the extraction
+// process.
 //
 // NOTE: While technically optional, the use of delimiters for matching an
 // encoded payload is *highly* recommended; reliability of payload matching is
@@ -253,6 +349,12 @@ func DecodePayload(encodedInput []byte, leftDelimiter string, rightDelimiter str
 // The extracted payload is encoded and will need to be decoded and then
 // decompressed before the original content is accessible.
 func ExtractEncodedPayload(text string, customRegex string, leftDelimiter string, rightDelimiter string) (string, error) {
+	return extractEncodedPayload(text, customRegex, leftDelimiter, rightDelimiter, nil)
+}
+
+// extractEncodedPayload implements ExtractEncodedPayload, logging extraction
+// details using the given (optional) payload logging function.
+func extractEncodedPayload(text string, customRegex string, leftDelimiter string, rightDelimiter string, logf payloadLogFunc) (string, error) {
 	if len(text) == 0 {
 		return "", fmt.Errorf(
 			"failed to extract encoded payload from empty input: %w",
@@ -260,11 +362,18 @@ func ExtractEncodedPayload(text string, customRegex string, leftDelimiter string
 		)
 	}
 
-	defaultMatchPattern := leftDelimiter + defaultEncodingPatternRegex + rightDelimiter
+	// Delimiters are matched literally. This also guarantees that a match
+	// is enclosed by both delimiters, allowing them to be safely removed.
+	quotedLeftDelimiter := regexp.QuoteMeta(leftDelimiter)
+	quotedRightDelimiter := regexp.QuoteMeta(rightDelimiter)
+
+	defaultMatchPattern := quotedLeftDelimiter + defaultEncodingPatternRegex + quotedRightDelimiter
 
 	chosenRegex := defaultMatchPattern
 	if customRegex != "" {
-		chosenRegex = leftDelimiter + customRegex + rightDelimiter
+		// Group the custom expression so that it cannot alter how the
+		// delimiters are matched (e.g., a leading quantifier).
+		chosenRegex = quotedLeftDelimiter + "(?:" + customRegex + ")" + quotedRightDelimiter
 	}
 
 	// Assert that combined expression is valid.
@@ -279,6 +388,16 @@ func ExtractEncodedPayload(text string, customRegex string, leftDelimiter string
 	}
 
 	matches := re.FindStringSubmatch(text)
+
+	logf.log(
+		fmt.Sprintf("Encoded payload extraction regex %q matched %d time(s) in %d bytes input", chosenRegex, len(matches), len(text)),
+		logAttr{Key: logAttrRegex, Value: chosenRegex},
+		logAttr{Key: logAttrMatches, Value: len(matches)},
+		logAttr{Key: logAttrLeftDelimiter, Value: leftDelimiter},
+		logAttr{Key: logAttrRightDelimiter, Value: rightDelimiter},
+		logAttr{Key: logAttrBytes, Value: len(text)},
+	)
+
 	if len(matches) == 0 {
 		return "", fmt.Errorf("no encoded payload data found: %w", ErrEncodedPayloadNotFound)
 	}
@@ -287,6 +406,10 @@ func ExtractEncodedPayload(text string, customRegex string, leftDelimiter string
 	leftDelimiterLength := len(leftDelimiter)
 	rightDelimiterLength := len(rightDelimiter)
 
+	if len(matches[0]) < leftDelimiterLength+rightDelimiterLength {
+		return "", fmt.Errorf("no delimited encoded payload data found: %w", ErrEncodedPayloadNotFound)
+	}
+
 	return matches[0][leftDelimiterLength : len(matches[0])-rightDelimiterLength], nil
 }
 
@@ -307,6 +430,30 @@ func ExtractEncodedPayload(text string, customRegex string, leftDelimiter string
 // retrieved payload may require additional processing (e.g., JSON vs
 // plaintext).
 func ExtractAndDecodePayload(text string, customRegex string, leftDelimiter string, rightDelimiter string) (string, error) {
+	return extractAndDecodePayload(text, customRegex, leftDelimiter, rightDelimiter, nil)
+}
+
+// ExtractAndDecodePayload extracts, decodes and decompresses an encoded
+// payload from given input text using the encoded payload delimiters
+// configured for the plugin (or the defaults if not set).
+//
+// This method behaves the same as the ExtractAndDecodePayload function, but
+// also logs extraction and decoding details (e.g., regex matches, buffer
+// sizes, codec, checksum results) if payload debug logging is enabled.
+func (p *Plugin) ExtractAndDecodePayload(text string, customRegex string) (string, error) {
+	return extractAndDecodePayload(
+		text,
+		customRegex,
+		p.getEncodedPayloadDelimiterLeft(),
+		p.getEncodedPayloadDelimiterRight(),
+		p.logPayload,
+	)
+}
+
+// extractAndDecodePayload implements ExtractAndDecodePayload, logging
+// extraction and decoding details using the given (optional) payload logging
+// function.
+func extractAndDecodePayload(text string, customRegex string, leftDelimiter string, rightDelimiter string, logf payloadLogFunc) (string, error) {
 	if len(text) == 0 {
 		return "", fmt.Errorf(
 			"failed to extract and decode payload from empty input: %w",
@@ -314,13 +461,20 @@ func ExtractAndDecodePayload(text string, customRegex string, leftDelimiter stri
 		)
 	}
 
-	encodedPayload, err := ExtractEncodedPayload(text, customRegex, leftDelimiter, rightDelimiter)
+	encodedPayload, err := extractEncodedPayload(text, customRegex, leftDelimiter, rightDelimiter, logf)
 	if err != nil {
 		return "", err
 	}
 
+	logf.log(
+		fmt.Sprintf("%d bytes encoded payload extracted", len(encodedPayload)),
+		logAttr{Key: logAttrBytes, Value: len(encodedPayload)},
+	)
+
 	decodedPayload, err := decodeASCII85([]byte(encodedPayload))
 	if err != nil {
+		logf.log(fmt.Sprintf("Failed to decode extracted payload: %v", err))
+
 		return "", err
 	}
 
@@ -329,66 +483,88 @@ func ExtractAndDecodePayload(text string, customRegex string, leftDelimiter stri
 	// buffer content. Due to this, we opt to skip decompressing what may
 	// already be an uncompressed payload.
 	if isGzipCompressed(decodedPayload) {
+		logf.log(
+			fmt.Sprintf("%d bytes decoded payload using %s codec", len(decodedPayload), payloadCodecGzipASCII85),
+			logAttr{Key: logAttrCodec, Value: payloadCodecGzipASCII85},
+			logAttr{Key: logAttrBytes, Value: len(decodedPayload)},
+		)
+
 		decodedPayload, err = decompressPayloadContent(decodedPayload)
 		if err != nil {
+			logf.log(fmt.Sprintf("Failed to decompress decoded payload: %v", err))
+
 			return "", err
 		}
+
+		// The gzip reader verifies the CRC-32 checksum recorded in the gzip
+		// trailer; decompression fails if the checksum does not match.
+		checksum := fmt.Sprintf("%08x", crc32.ChecksumIEEE(decodedPayload))
+		logf.log(
+			fmt.Sprintf("%d bytes decompressed payload, CRC-32 checksum %s verified", len(decodedPayload), checksum),
+			logAttr{Key: logAttrBytes, Value: len(decodedPayload)},
+			logAttr{Key: logAttrChecksum, Value: checksum},
+		)
+	} else {
+		logf.log(
+			fmt.Sprintf("%d bytes decoded payload using %s codec", len(decodedPayload), payloadCodecASCII85),
+			logAttr{Key: logAttrCodec, Value: payloadCodecASCII85},
+			logAttr{Key: logAttrBytes, Value: len(decodedPayload)},
+		)
 	}
 
 	return string(decodedPayload), nil
 }
 
-// compressPayloadBufferOrFallback returns the compressed payload buffer
-// contents or the uncompressed/original payload buffer contents if an error
-// occurs during compression.
-func (p Plugin) compressPayloadBufferOrFallback() []byte {
-	compressedData, compressErr := compressPayloadContent(p.encodedPayloadBuffer.Bytes())
+// compressPayloadBufferOrFallback compresses the payload buffer contents
+// into the given buffer and returns the compressed content or the
+// uncompressed/original payload buffer contents if an error occurs during
+// compression. The returned content is only valid until either buffer is
+// modified.
+func (p Plugin) compressPayloadBufferOrFallback(dst *bytes.Buffer) []byte {
+	compressErr := compressPayloadContentTo(dst, p.encodedPayloadBuffer.Bytes())
+	compressedData := dst.Bytes()
 	switch {
 	case compressErr != nil:
 		// Skip compression if an error occurs, use original payload buffer
 		// contents as-is.
-		p.logAction("failed to compress unencoded payload content, skipping compression")
+		p.logActionLevel(DebugLogLevelWarn, "failed to compress unencoded payload content, skipping compression")
+		p.logPayload(
+			fmt.Sprintf("Compression failed (%v), using %s codec", compressErr, payloadCodecASCII85),
+			logAttr{Key: logAttrCodec, Value: payloadCodecASCII85},
+		)
 
 		return p.encodedPayloadBuffer.Bytes()
 
 	default:
 		p.logAction("successfully compressed unencoded payload content")
 		p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin unencoded payload content after compression", len(compressedData)))
+		p.logPayload(
+			fmt.Sprintf("%d bytes payload after compression, using %s codec", len(compressedData), payloadCodecGzipASCII85),
+			logAttr{Key: logAttrCodec, Value: payloadCodecGzipASCII85},
+			logAttr{Key: logAttrBytes, Value: len(compressedData)},
+		)
 
 		return compressedData
 	}
 }
 
-// compressPayloadContent compresses given input data or returns an error if
-// one occurs.
-func compressPayloadContent(uncompressedContent []byte) ([]byte, error) {
-	var compressedBuffer bytes.Buffer
-
-	gzipWriter, gzipWriterLevelErr := gzip.NewWriterLevel(&compressedBuffer, gzip.BestCompression)
-	if gzipWriterLevelErr != nil {
-		// Documentation notes that err is nil unless we specify an invalid
-		// level; since we use a stdlib package constant that's highly
-		// unlikely to produce a complaint, but we guard against it anyway.
-		panic("invalid compression level specified")
-	}
-	defer func() {
-		// Fallback close attempt in case later errors are encountered.
-		if err := gzipWriter.Close(); err != nil {
-			panic("failed to close gzip writer")
-		}
-	}()
+// compressPayloadContentTo compresses given input data into the given buffer
+// using a pooled gzip writer or returns an error if one occurs. The buffer
+// content is unspecified if an error occurs.
+func compressPayloadContentTo(dst *bytes.Buffer, uncompressedContent []byte) error {
+	gzipWriter := gzipWriterPool.Get().(*gzip.Writer)
+	defer gzipWriterPool.Put(gzipWriter)
 
-	_, gzipWriteErr := io.Copy(gzipWriter, bytes.NewReader(uncompressedContent))
-	if gzipWriteErr != nil {
-		return nil, gzipWriteErr
-	}
+	// Reset discards any state from previous use (including after a failed
+	// write) and directs output to the given buffer.
+	gzipWriter.Reset(dst)
 
-	// Explicitly close gzip writer to complete compression.
-	if err := gzipWriter.Close(); err != nil {
-		return nil, err
+	if _, gzipWriteErr := gzipWriter.Write(uncompressedContent); gzipWriteErr != nil {
+		return gzipWriteErr
 	}
 
-	return compressedBuffer.Bytes(), nil
+	// Explicitly close gzip writer to complete compression.
+	return gzipWriter.Close()
 }
 
 // isGzipCompressed checks if the data is gzip-compressed by examining the
@@ -0,0 +1,123 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+)
+
+// DefaultMaxPayloadSize is the default maximum size in bytes of the
+// unencoded payload buffer. See SetMaxPayloadSize.
+const DefaultMaxPayloadSize int = 32 * 1024 * 1024
+
+// payloadSizeReportInterval is the amount of payload buffer growth in bytes
+// between payload buffer size debug log entries.
+const payloadSizeReportInterval int = 1024 * 1024
+
+// payloadSizeLimitDisabled is the internal value used to indicate that
+// client code disabled the payload size limit.
+const payloadSizeLimitDisabled int = -1
+
+// PayloadSizeLimitError indicates that adding content to the payload buffer
+// was refused because the payload buffer would exceed the configured
+// maximum size. The payload buffer is left unchanged.
+//
+// PayloadSizeLimitError wraps ErrPayloadSizeLimitExceeded.
+type PayloadSizeLimitError struct {
+	// Limit is the maximum size in bytes of the payload buffer.
+	Limit int
+
+	// Size is the size in bytes the payload buffer would have reached if
+	// the content had been added.
+	Size int
+}
+
+// Error satisfies the error interface.
+func (e *PayloadSizeLimitError) Error() string {
+	return fmt.Sprintf(
+		"%v: payload buffer would grow to %d bytes; limit is %d bytes",
+		ErrPayloadSizeLimitExceeded,
+		e.Size,
+		e.Limit,
+	)
+}
+
+// Unwrap returns ErrPayloadSizeLimitExceeded to support errors.Is.
+func (e *PayloadSizeLimitError) Unwrap() error {
+	return ErrPayloadSizeLimitExceeded
+}
+
+// SetMaxPayloadSize limits the size in bytes of the unencoded payload
+// buffer (DefaultMaxPayloadSize if not set). Attempts to set or add payload
+// content exceeding the limit are refused with a PayloadSizeLimitError
+// instead of growing the payload buffer. A value of zero disables the limit;
+// negative values are ignored.
+func (p *Plugin) SetMaxPayloadSize(size int) {
+	switch {
+	case size < 0:
+		return
+	case size == 0:
+		p.logAction("Disabling payload size limit as requested")
+		p.maxPayloadSize = payloadSizeLimitDisabled
+	default:
+		p.logAction(fmt.Sprintf("Setting payload size limit to %d bytes as requested", size))
+		p.maxPayloadSize = size
+	}
+}
+
+// payloadSizeLimit returns the maximum size in bytes of the payload buffer
+// or zero if the limit is disabled.
+func (p *Plugin) payloadSizeLimit() int {
+	switch p.maxPayloadSize {
+	case 0:
+		return DefaultMaxPayloadSize
+	case payloadSizeLimitDisabled:
+		return 0
+	default:
+		return p.maxPayloadSize
+	}
+}
+
+// checkPayloadSize returns a PayloadSizeLimitError if the payload buffer
+// would exceed the payload size limit after growing to the given size.
+func (p *Plugin) checkPayloadSize(size int) error {
+	limit := p.payloadSizeLimit()
+	if limit == 0 || size <= limit {
+		return nil
+	}
+
+	err := &PayloadSizeLimitError{Limit: limit, Size: size}
+	p.logActionLevel(DebugLogLevelWarn, err.Error())
+
+	return err
+}
+
+// reportPayloadSize logs the payload buffer size each time the payload
+// buffer grows past another payloadSizeReportInterval boundary so that
+// runaway payload growth is visible in the debug log before the limit is
+// reached.
+func (p *Plugin) reportPayloadSize() {
+	size := p.encodedPayloadBuffer.Len()
+	reported := size / payloadSizeReportInterval
+
+	if reported <= p.payloadSizeReported {
+		return
+	}
+
+	p.payloadSizeReported = reported
+
+	if !p.debugLogging.payload {
+		return
+	}
+
+	limit := p.payloadSizeLimit()
+	p.logPayload(
+		fmt.Sprintf("Payload buffer grown to %d bytes (limit %d bytes; 0 is unlimited)", size, limit),
+		logAttr{Key: logAttrBytes, Value: size},
+	)
+}
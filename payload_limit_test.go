@@ -0,0 +1,148 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_SetMaxPayloadSize_RefusesContentExceedingLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		add      func(plugin *nagios.Plugin) (int, error)
+		wantSize int
+	}{
+		"SetPayloadString": {
+			add: func(plugin *nagios.Plugin) (int, error) {
+				return plugin.SetPayloadString(strings.Repeat("x", 11))
+			},
+			wantSize: 11,
+		},
+		"SetPayloadBytes": {
+			add: func(plugin *nagios.Plugin) (int, error) {
+				return plugin.SetPayloadBytes([]byte(strings.Repeat("x", 12)))
+			},
+			wantSize: 12,
+		},
+		"AddPayloadString": {
+			add: func(plugin *nagios.Plugin) (int, error) {
+				return plugin.AddPayloadString("678901")
+			},
+			wantSize: 11,
+		},
+		"AddPayloadBytes": {
+			add: func(plugin *nagios.Plugin) (int, error) {
+				return plugin.AddPayloadBytes([]byte("6789012"))
+			},
+			wantSize: 12,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := nagios.NewPlugin()
+			plugin.SetMaxPayloadSize(10)
+
+			if _, err := plugin.SetPayloadString("12345"); err != nil {
+				t.Fatalf("ERROR: unexpected error setting payload within limit: %v", err)
+			}
+
+			written, err := tt.add(plugin)
+
+			var limitErr *nagios.PayloadSizeLimitError
+
+			switch {
+			case !errors.Is(err, nagios.ErrPayloadSizeLimitExceeded):
+				t.Fatalf("ERROR: want error %v, got %v", nagios.ErrPayloadSizeLimitExceeded, err)
+			case !errors.As(err, &limitErr):
+				t.Fatalf("ERROR: want PayloadSizeLimitError, got %T", err)
+			case limitErr.Limit != 10 || limitErr.Size != tt.wantSize:
+				t.Fatalf("ERROR: want limit 10 and size %d, got limit %d and size %d", tt.wantSize, limitErr.Limit, limitErr.Size)
+			case written != 0:
+				t.Fatalf("ERROR: want 0 bytes written, got %d", written)
+			}
+
+			if d := cmp.Diff("12345", plugin.UnencodedPayload()); d != "" {
+				t.Fatalf("ERROR: payload buffer changed (-want, +got)\n:%s", d)
+			}
+
+			t.Log("OK: content exceeding payload size limit refused")
+		})
+	}
+}
+
+func TestPlugin_SetMaxPayloadSize_ZeroDisablesLimit(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetMaxPayloadSize(10)
+	plugin.SetMaxPayloadSize(-1)
+
+	if _, err := plugin.SetPayloadString(strings.Repeat("x", 11)); err == nil {
+		t.Fatal("ERROR: negative value unexpectedly changed payload size limit")
+	}
+
+	plugin.SetMaxPayloadSize(0)
+
+	content := strings.Repeat("x", nagios.DefaultMaxPayloadSize+1)
+	if _, err := plugin.SetPayloadString(content); err != nil {
+		t.Fatalf("ERROR: unexpected error with payload size limit disabled: %v", err)
+	}
+
+	t.Log("OK: payload size limit disabled as expected")
+}
+
+func TestPlugin_AddPayloadString_EnforcesDefaultLimit(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	if _, err := plugin.SetPayloadString(strings.Repeat("x", nagios.DefaultMaxPayloadSize)); err != nil {
+		t.Fatalf("ERROR: unexpected error setting payload at default limit: %v", err)
+	}
+
+	if _, err := plugin.AddPayloadString("x"); !errors.Is(err, nagios.ErrPayloadSizeLimitExceeded) {
+		t.Fatalf("ERROR: want error %v, got %v", nagios.ErrPayloadSizeLimitExceeded, err)
+	}
+
+	t.Log("OK: default payload size limit enforced")
+}
+
+func TestPlugin_AddPayloadString_ReportsBufferGrowth(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.DebugLoggingEnablePayload()
+
+	chunk := strings.Repeat("x", 512*1024)
+	for i := 0; i < 5; i++ {
+		if _, err := plugin.AddPayloadString(chunk); err != nil {
+			t.Fatalf("ERROR: unexpected error adding payload content: %v", err)
+		}
+	}
+
+	// 2.5 MiB of content crosses two 1 MiB reporting boundaries.
+	if got := strings.Count(logBuffer.String(), "Payload buffer grown to"); got != 2 {
+		t.Fatalf("ERROR: want 2 payload buffer size entries, got %d:\n%s", got, logBuffer.String())
+	}
+
+	t.Log("OK: payload buffer growth reported as expected")
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEncodePayload_PooledBuffersProduceConsistentResults(t *testing.T) {
+	t.Parallel()
+
+	payloads := []string{
+		"Hello, World!",
+		smallJSONPayloadUnencoded,
+		strings.Repeat("large payload content ", 50000),
+	}
+
+	want := make([]string, len(payloads))
+	for i, payload := range payloads {
+		want[i] = EncodePayload([]byte(payload), defaultPayloadDelimiterLeft, defaultPayloadDelimiterRight)
+	}
+
+	// Encode concurrently and repeatedly so that pooled buffers of varying
+	// sizes are reused across payloads.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*len(payloads))
+
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range payloads {
+				for _, j := range []int{i, len(payloads) - 1 - i} {
+					got := EncodePayload([]byte(payloads[j]), defaultPayloadDelimiterLeft, defaultPayloadDelimiterRight)
+					if got != want[j] {
+						errs <- fmt.Errorf("payload %d: encoded result differs from initial result", j)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for i, payload := range payloads {
+		decoded, err := DecodePayload([]byte(want[i]), defaultPayloadDelimiterLeft, defaultPayloadDelimiterRight)
+		if err != nil {
+			t.Fatalf("ERROR: failed to decode payload %d: %v", i, err)
+		}
+
+		if string(decoded) != payload {
+			t.Fatalf("ERROR: decoded payload %d does not match original payload", i)
+		}
+	}
+
+	t.Log("OK: pooled payload encoding produced consistent results")
+}
+
+func TestPutPayloadBuffer_DiscardsOversizedBuffers(t *testing.T) {
+	t.Parallel()
+
+	oversized := bytes.NewBuffer(make([]byte, 0, maxPooledPayloadBufferSize+1))
+	oversized.WriteString("stale content")
+	putPayloadBuffer(oversized)
+
+	// Pool contents are not guaranteed, but an oversized buffer must never
+	// be handed out again and every buffer handed out must be empty.
+	for i := 0; i < 100; i++ {
+		buf := getPayloadBuffer()
+
+		switch {
+		case buf == oversized:
+			t.Fatal("ERROR: oversized buffer returned from pool")
+		case buf.Len() != 0:
+			t.Fatalf("ERROR: want empty buffer from pool, got %d bytes", buf.Len())
+		}
+
+		buf.WriteString("content written by previous user")
+		putPayloadBuffer(buf)
+	}
+
+	t.Log("OK: oversized buffers discarded and pooled buffers reset")
+}
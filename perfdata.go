@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
@@ -26,6 +27,11 @@ const (
 	// string; this value is the number of total permitted semicolons + 1.
 	perfDataMaxSemicolonSeparatedFields int = 5
 
+	// perfDataScratchBufferSize is the size of the scratch buffer used when
+	// formatting a performance data metric. Longer metrics are supported but
+	// require additional allocations.
+	perfDataScratchBufferSize int = 128
+
 	// perfDataValueFieldRegex represents the regex character class used to
 	// validate the Value field. In addition to the characters used to
 	// represent whole and fractional numbers a literal U character is also
@@ -70,6 +76,28 @@ const (
 	// perfDataUnitOfMeasurementRegex string = `[^0-9;"']+`
 )
 
+// Compiled performance data validation and parsing expressions. These are
+// compiled once instead of for every validated or parsed metric; compiled
+// expressions are safe for concurrent use.
+var (
+	perfDataValueFieldRegexp           = regexp.MustCompile(perfDataValueFieldRegex)
+	perfDataMinMaxFieldsRegexp         = regexp.MustCompile(perfDataMinMaxFieldsRegex)
+	perfDataThresholdRangeSyntaxRegexp = regexp.MustCompile(perfDataThresholdRangeSyntaxRegex)
+	perfDataValueAndUoMFieldsRegexp    = regexp.MustCompile(perfDataValueAndUoMFieldsRegex)
+)
+
+// perfDataBufferPool provides reusable buffers for formatting performance
+// data metrics during plugin output emission. Buffers are only used for the
+// duration of a single emission and are safe for concurrent use by separate
+// Plugin values.
+var perfDataBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, perfDataScratchBufferSize)
+
+		return &buf
+	},
+}
+
 // PerformanceData represents the performance data generated by a Nagios
 // plugin.
 //
@@ -167,7 +195,7 @@ func ParsePerfData(rawPerfdata string) ([]PerformanceData, error) {
 	// fmt.Printf("rawPerfdata without double quotes: %s\n", rawPerfdata)
 
 	// Split raw perfdata string into individual metrics using whitespace
-	// separators.
+	// separators. Whitespace within single quoted labels is retained.
 	//
 	// This turns an input string such as:
 	//
@@ -182,7 +210,7 @@ func ParsePerfData(rawPerfdata string) ([]PerformanceData, error) {
 	//
 	// If we are working with a single metric we get back that one metric, so
 	// we're working from at least a slice of one element.
-	perfdataStrings := strings.Fields(rawPerfdata)
+	perfdataStrings := splitPerfDataMetrics(rawPerfdata)
 
 	// DEBUG
 	// fmt.Printf("space separated fields from rawPerfdata: %q\n", perfdataStrings)
@@ -236,27 +264,45 @@ func (pd PerformanceData) Validate() error {
 // String provides a PerformanceData metric in format ready for use in plugin
 // output.
 func (pd PerformanceData) String() string {
-	return fmt.Sprintf(
-		// The expected format of a performance data metric:
-		//
-		// 'label'=value[UOM];[warn];[crit];[min];[max]
-		//
-		// References:
-		//
-		// https://nagios-plugins.org/doc/guidelines.html
-		// https://assets.nagios.com/downloads/nagioscore/docs/nagioscore/3/en/perfdata.html
-		// https://assets.nagios.com/downloads/nagioscore/docs/nagioscore/3/en/pluginapi.html
-		// https://www.monitoring-plugins.org/doc/guidelines.html
-		// https://icinga.com/docs/icinga-2/latest/doc/05-service-monitoring/#performance-data-metrics
-		" '%s'=%s%s;%s;%s;%s;%s",
-		pd.Label,
-		pd.Value,
-		pd.UnitOfMeasurement,
-		pd.Warn,
-		pd.Crit,
-		pd.Min,
-		pd.Max,
-	)
+	// Typical metrics fit in the stack allocated scratch buffer, leaving
+	// only the returned string to allocate.
+	var scratch [perfDataScratchBufferSize]byte
+
+	return string(pd.AppendTo(scratch[:0]))
+}
+
+// AppendTo appends the PerformanceData metric in format ready for use in
+// plugin output to the given byte slice and returns the extended slice. This
+// is the same content as provided by String but allows client code emitting
+// many metrics to reuse a single buffer instead of allocating a new string
+// for each metric.
+func (pd PerformanceData) AppendTo(dst []byte) []byte {
+	// The expected format of a performance data metric:
+	//
+	// 'label'=value[UOM];[warn];[crit];[min];[max]
+	//
+	// References:
+	//
+	// https://nagios-plugins.org/doc/guidelines.html
+	// https://assets.nagios.com/downloads/nagioscore/docs/nagioscore/3/en/perfdata.html
+	// https://assets.nagios.com/downloads/nagioscore/docs/nagioscore/3/en/pluginapi.html
+	// https://www.monitoring-plugins.org/doc/guidelines.html
+	// https://icinga.com/docs/icinga-2/latest/doc/05-service-monitoring/#performance-data-metrics
+	dst = append(dst, " '"...)
+	dst = append(dst, pd.Label...)
+	dst = append(dst, "'="...)
+	dst = append(dst, pd.Value...)
+	dst = append(dst, pd.UnitOfMeasurement...)
+	dst = append(dst, ';')
+	dst = append(dst, pd.Warn...)
+	dst = append(dst, ';')
+	dst = append(dst, pd.Crit...)
+	dst = append(dst, ';')
+	dst = append(dst, pd.Min...)
+	dst = append(dst, ';')
+	dst = append(dst, pd.Max...)
+
+	return dst
 }
 
 // parsePerfData parses an input string representing a performance data
@@ -442,7 +488,7 @@ func extractValueAndUoM(input string) (string, string, error) {
 		return input, "", nil
 	}
 
-	re := regexp.MustCompile(perfDataValueAndUoMFieldsRegex)
+	re := perfDataValueAndUoMFieldsRegexp
 
 	matches := re.FindStringSubmatch(input)
 	if len(matches) == 0 {
@@ -620,8 +666,7 @@ func validatePerfDataLabelField(input string) error {
 func validatePerfDataValueField(input string) error {
 	input = strings.TrimSpace(input)
 
-	re := regexp.MustCompile(perfDataValueFieldRegex)
-	if re.MatchString(input) {
+	if perfDataValueFieldRegexp.MatchString(input) {
 		return nil
 	}
 
@@ -679,8 +724,7 @@ func validatePerfDataWarnField(input string) error {
 		return nil
 	}
 
-	re := regexp.MustCompile(perfDataThresholdRangeSyntaxRegex)
-	if re.MatchString(input) {
+	if perfDataThresholdRangeSyntaxRegexp.MatchString(input) {
 		return nil
 	}
 
@@ -706,8 +750,7 @@ func validatePerfDataCritField(input string) error {
 		return nil
 	}
 
-	re := regexp.MustCompile(perfDataThresholdRangeSyntaxRegex)
-	if re.MatchString(input) {
+	if perfDataThresholdRangeSyntaxRegexp.MatchString(input) {
 		return nil
 	}
 
@@ -733,8 +776,7 @@ func validatePerfDataMinField(input string) error {
 		return nil
 	}
 
-	re := regexp.MustCompile(perfDataMinMaxFieldsRegex)
-	if re.MatchString(input) {
+	if perfDataMinMaxFieldsRegexp.MatchString(input) {
 		return nil
 	}
 
@@ -760,8 +802,7 @@ func validatePerfDataMaxField(input string) error {
 		return nil
 	}
 
-	re := regexp.MustCompile(perfDataMinMaxFieldsRegex)
-	if re.MatchString(input) {
+	if perfDataMinMaxFieldsRegexp.MatchString(input) {
 		return nil
 	}
 
@@ -0,0 +1,134 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// extendedPerfDataLabelSeparator separates the components of the extended
+// performance data label used to group metrics by check command.
+const extendedPerfDataLabelSeparator string = "::"
+
+// extendedPerfDataOptions is the collection of settings used when emitting
+// extended performance data.
+type extendedPerfDataOptions struct {
+	// tag is the name of the metric group (e.g., the service name).
+	tag string
+
+	// checkCommand is the check command name used by PNP4Nagios to select
+	// a graph template.
+	checkCommand string
+}
+
+// EnableExtendedPerfData enables the extended performance data conventions
+// recognized by Thruk, PNP4Nagios and Checkmk Multisite so that graphs group
+// correctly in those interfaces. When enabled:
+//
+//   - the label of the first metric is prefixed using the
+//     'tag::check_command::label' convention (as used by check_multi) which
+//     assigns all metrics to the given group and graph template
+//   - the min and max fields are populated for metrics which do not specify
+//     them: percentage metrics use 0 and 100, other metrics use 0 (or the
+//     value if negative) as min and the upper bound of the critical or
+//     warning threshold (or the value if larger) as max
+//
+// If checkCommand is empty the base name of the plugin executable is used.
+// If tag is empty the check command is used.
+//
+// Extended performance data applies to the Nagios plugin output format
+// only.
+func (p *Plugin) EnableExtendedPerfData(tag string, checkCommand string) {
+	if checkCommand == "" {
+		checkCommand = filepath.Base(os.Args[0])
+	}
+
+	if tag == "" {
+		tag = checkCommand
+	}
+
+	p.logAction("Enabling extended performance data")
+
+	p.extendedPerfData = &extendedPerfDataOptions{
+		tag:          tag,
+		checkCommand: checkCommand,
+	}
+}
+
+// applyExtendedPerfData returns a copy of the given (sorted) performance data
+// with the extended performance data conventions applied. The given
+// performance data is returned as-is if extended performance data is not
+// enabled.
+func (p *Plugin) applyExtendedPerfData(perfData []PerformanceData) []PerformanceData {
+	if p.extendedPerfData == nil || len(perfData) == 0 {
+		return perfData
+	}
+
+	extended := make([]PerformanceData, len(perfData))
+	for i, pd := range perfData {
+		extended[i] = populateMinMax(pd)
+	}
+
+	extended[0].Label = p.extendedPerfData.tag +
+		extendedPerfDataLabelSeparator +
+		p.extendedPerfData.checkCommand +
+		extendedPerfDataLabelSeparator +
+		extended[0].Label
+
+	return extended
+}
+
+// populateMinMax returns the given performance data with empty min and max
+// fields populated using the best available values.
+func populateMinMax(pd PerformanceData) PerformanceData {
+	if pd.UnitOfMeasurement == "%" {
+		if pd.Min == "" {
+			pd.Min = "0"
+		}
+		if pd.Max == "" {
+			pd.Max = "100"
+		}
+
+		return pd
+	}
+
+	value, err := strconv.ParseFloat(pd.Value, 64)
+	if err != nil {
+		// An undetermined ("U") value provides no hints.
+		return pd
+	}
+
+	if pd.Min == "" {
+		pd.Min = "0"
+		if value < 0 {
+			pd.Min = pd.Value
+		}
+	}
+
+	if pd.Max == "" {
+		pd.Max = pd.Value
+
+		for _, threshold := range []string{pd.Crit, pd.Warn} {
+			if threshold == "" {
+				continue
+			}
+
+			r := ParseRangeString(threshold)
+			if r != nil && !r.EndInfinity {
+				if r.End > value {
+					pd.Max = strconv.FormatFloat(r.End, 'f', -1, 64)
+				}
+				break
+			}
+		}
+	}
+
+	return pd
+}
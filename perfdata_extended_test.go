@@ -0,0 +1,77 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_EnableExtendedPerfData(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.EnableExtendedPerfData("Disk C", "check_disk")
+	plugin.ServiceOutput = "OK: disk usage within thresholds"
+
+	err := plugin.AddPerfData(
+		false,
+		nagios.PerformanceData{Label: "used", Value: "42", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		nagios.PerformanceData{Label: "free", Value: "120", UnitOfMeasurement: "GB", Crit: "10:500"},
+		nagios.PerformanceData{Label: "delta", Value: "-3", Warn: "5"},
+		nagios.PerformanceData{Label: "time", Value: "12", UnitOfMeasurement: "ms", Min: "1", Max: "2"},
+	)
+	if err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	want := " | 'Disk C::check_disk::delta'=-3;5;;-3;5" +
+		" 'free'=120GB;;10:500;0;500" +
+		" 'time'=12ms;;;1;2" +
+		" 'used'=42%;80;90;0;100" +
+		nagios.CheckOutputEOL
+
+	got := outputBuffer.String()
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("ERROR: want performance data %q, got output %q", want, got)
+	}
+
+	t.Log("OK: extended performance data emitted as expected")
+}
+
+func TestPlugin_EnableExtendedPerfData_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.ServiceOutput = "OK: disk usage within thresholds"
+
+	if err := plugin.AddPerfData(false, nagios.PerformanceData{Label: "used", Value: "42", UnitOfMeasurement: "%"}); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+	if strings.Contains(got, "::") || !strings.Contains(got, "'used'=42%;;;;") {
+		t.Fatalf("ERROR: unexpected performance data in output %q", got)
+	}
+
+	t.Log("OK: standard performance data emitted as expected")
+}
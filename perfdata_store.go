@@ -0,0 +1,222 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"sort"
+	"strings"
+)
+
+// perfDataEntry is a performance data metric along with the normalized
+// (lowercase) label used to identify it within a perfDataCollection.
+type perfDataEntry struct {
+	// key is the lowercase metric label.
+	key string
+
+	// metric is the performance data metric.
+	metric PerformanceData
+}
+
+// perfDataCollection is the collection of performance data metrics for a
+// plugin. Metrics are stored in a slice with a separate index keyed by
+// lowercase label; adding a metric using an existing label (compared
+// case-insensitively) replaces the previous metric.
+//
+// This layout keeps metrics contiguous in memory for plugins emitting
+// thousands of metrics and allows the storage to be sized up front (see
+// Plugin.ReservePerfData). The zero value is an empty collection ready for
+// use.
+type perfDataCollection struct {
+	// entries is the collection of metrics in insertion order.
+	entries []perfDataEntry
+
+	// index maps a lowercase metric label to the position of the metric in
+	// entries.
+	index map[string]int
+
+	// sorted indicates whether entries are known to be sorted by key.
+	// Metrics are commonly added in label order (e.g., per-port metrics),
+	// in which case sorting prior to emission is skipped.
+	sorted bool
+}
+
+// ReservePerfData preallocates storage for the given number of additional
+// performance data metrics. This is an optional optimization for plugins
+// emitting a large (and known) number of metrics; the storage grows as
+// needed without it.
+func (p *Plugin) ReservePerfData(n int) {
+	p.perfData.reserve(n)
+}
+
+// reserve grows the collection capacity to hold n additional metrics
+// without further allocation.
+func (c *perfDataCollection) reserve(n int) {
+	if n <= 0 {
+		return
+	}
+
+	if free := cap(c.entries) - len(c.entries); free >= n && c.index != nil {
+		return
+	}
+
+	c.growEntries(n)
+
+	index := make(map[string]int, len(c.entries)+n)
+	for key, i := range c.index {
+		index[key] = i
+	}
+	c.index = index
+}
+
+// growEntries grows the entries capacity to hold at least n additional
+// metrics.
+func (c *perfDataCollection) growEntries(n int) {
+	const minCapacity = 8
+
+	if n < minCapacity {
+		n = minCapacity
+	}
+
+	entries := make([]perfDataEntry, len(c.entries), len(c.entries)+n)
+	copy(entries, c.entries)
+	c.entries = entries
+}
+
+// set adds the given metric to the collection, replacing any existing
+// metric using the same label (compared case-insensitively).
+func (c *perfDataCollection) set(pd PerformanceData) {
+	key := strings.ToLower(pd.Label)
+
+	if i, ok := c.lookup(key); ok {
+		c.entries[i].metric = pd
+
+		return
+	}
+
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+
+	switch n := len(c.entries); {
+	case n == 0:
+		c.sorted = true
+	case c.sorted && c.entries[n-1].key > key:
+		c.sorted = false
+	}
+
+	// Double the storage when full instead of relying on append, which
+	// grows large slices more conservatively and so copies the (large)
+	// entries more often.
+	if len(c.entries) == cap(c.entries) {
+		c.growEntries(len(c.entries))
+	}
+
+	c.index[key] = len(c.entries)
+	c.entries = append(c.entries, perfDataEntry{key: key, metric: pd})
+}
+
+// get returns the metric using the given lowercase label and whether it was
+// found.
+func (c perfDataCollection) get(key string) (PerformanceData, bool) {
+	i, ok := c.lookup(key)
+	if !ok {
+		return PerformanceData{}, false
+	}
+
+	return c.entries[i].metric, true
+}
+
+// has indicates whether a metric using the given lowercase label is present.
+func (c perfDataCollection) has(key string) bool {
+	_, ok := c.lookup(key)
+
+	return ok
+}
+
+// lookup returns the position of the metric using the given lowercase label
+// and whether it was found. Index entries not matching a stored metric (e.g.,
+// added via a copy of the Plugin value sharing the index) are ignored.
+func (c perfDataCollection) lookup(key string) (int, bool) {
+	i, ok := c.index[key]
+	if !ok || i >= len(c.entries) || c.entries[i].key != key {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// len returns the number of metrics in the collection.
+func (c perfDataCollection) len() int {
+	return len(c.entries)
+}
+
+// deleteFunc removes all metrics for which the given function returns true.
+// The relative order of the remaining metrics is retained.
+func (c *perfDataCollection) deleteFunc(fn func(pd PerformanceData) bool) {
+	kept := c.entries[:0]
+
+	for _, entry := range c.entries {
+		if fn(entry.metric) {
+			delete(c.index, entry.key)
+
+			continue
+		}
+
+		c.index[entry.key] = len(kept)
+		kept = append(kept, entry)
+	}
+
+	// Clear removed entries so that their content may be garbage collected.
+	for i := len(kept); i < len(c.entries); i++ {
+		c.entries[i] = perfDataEntry{}
+	}
+
+	c.entries = kept
+}
+
+// sortedKeys returns the lowercase labels of all metrics in sorted order.
+func (c perfDataCollection) sortedKeys() []string {
+	keys := make([]string, len(c.entries))
+	for i := range c.entries {
+		keys[i] = c.entries[i].key
+	}
+
+	if !c.sorted {
+		sort.Strings(keys)
+	}
+
+	return keys
+}
+
+// sortedMetrics returns a copy of all metrics sorted by lowercase label.
+func (c perfDataCollection) sortedMetrics() []PerformanceData {
+	metrics := make([]PerformanceData, len(c.entries))
+
+	if c.sorted {
+		for i := range c.entries {
+			metrics[i] = c.entries[i].metric
+		}
+
+		return metrics
+	}
+
+	order := make([]int, len(c.entries))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return c.entries[order[i]].key < c.entries[order[j]].key
+	})
+
+	for i, pos := range order {
+		metrics[i] = c.entries[pos].metric
+	}
+
+	return metrics
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPerfDataCollection_SetReplacesCaseInsensitiveLabel(t *testing.T) {
+	t.Parallel()
+
+	var c perfDataCollection
+
+	c.set(PerformanceData{Label: "Port_1", Value: "1"})
+	c.set(PerformanceData{Label: "port_2", Value: "2"})
+	c.set(PerformanceData{Label: "PORT_1", Value: "3"})
+
+	if c.len() != 2 {
+		t.Fatalf("ERROR: want 2 metrics, got %d", c.len())
+	}
+
+	got, ok := c.get("port_1")
+	if !ok {
+		t.Fatal("ERROR: metric port_1 not found")
+	}
+
+	if d := cmp.Diff(PerformanceData{Label: "PORT_1", Value: "3"}, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: metric with existing label replaced as expected")
+}
+
+func TestPerfDataCollection_SortedMetrics(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		labels     []string
+		wantSorted bool
+	}{
+		"added in label order": {
+			labels:     []string{"a", "B", "c"},
+			wantSorted: true,
+		},
+		"added out of label order": {
+			labels:     []string{"c", "a", "B"},
+			wantSorted: false,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var c perfDataCollection
+			for _, label := range tt.labels {
+				c.set(PerformanceData{Label: label, Value: "1"})
+			}
+
+			if c.sorted != tt.wantSorted {
+				t.Errorf("ERROR: want sorted %t, got %t", tt.wantSorted, c.sorted)
+			}
+
+			want := []PerformanceData{
+				{Label: "a", Value: "1"},
+				{Label: "B", Value: "1"},
+				{Label: "c", Value: "1"},
+			}
+
+			if d := cmp.Diff(want, c.sortedMetrics()); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			if d := cmp.Diff([]string{"a", "b", "c"}, c.sortedKeys()); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Log("OK: metrics returned in label order")
+		})
+	}
+}
+
+func TestPerfDataCollection_DeleteFunc(t *testing.T) {
+	t.Parallel()
+
+	var c perfDataCollection
+	for i := 0; i < 10; i++ {
+		c.set(PerformanceData{Label: fmt.Sprintf("metric_%d", i), Value: fmt.Sprintf("%d", i)})
+	}
+
+	c.deleteFunc(func(pd PerformanceData) bool {
+		return pd.Value != "3" && pd.Value != "7"
+	})
+
+	want := []PerformanceData{
+		{Label: "metric_3", Value: "3"},
+		{Label: "metric_7", Value: "7"},
+	}
+
+	if d := cmp.Diff(want, c.sortedMetrics()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	if c.has("metric_0") {
+		t.Fatal("ERROR: deleted metric still present")
+	}
+
+	// Replacing a retained metric must update the retained entry.
+	c.set(PerformanceData{Label: "metric_7", Value: "70"})
+	if got, _ := c.get("metric_7"); got.Value != "70" || c.len() != 2 {
+		t.Fatalf("ERROR: want metric_7 replaced in place, got %+v (%d metrics)", got, c.len())
+	}
+
+	t.Log("OK: metrics deleted as expected")
+}
+
+func TestPerfDataCollection_ReserveRetainsMetrics(t *testing.T) {
+	t.Parallel()
+
+	var c perfDataCollection
+	c.set(PerformanceData{Label: "existing", Value: "1"})
+
+	c.reserve(1000)
+
+	if free := cap(c.entries) - len(c.entries); free < 1000 {
+		t.Fatalf("ERROR: want capacity for 1000 additional metrics, got %d", free)
+	}
+
+	if _, ok := c.get("existing"); !ok || c.len() != 1 {
+		t.Fatal("ERROR: existing metric not retained after reserving storage")
+	}
+
+	t.Log("OK: storage reserved without losing existing metrics")
+}
+
+func TestPerfDataCollection_IgnoresIndexEntriesFromCopies(t *testing.T) {
+	t.Parallel()
+
+	var original perfDataCollection
+	original.set(PerformanceData{Label: "shared", Value: "1"})
+
+	// A copy shares the index but not the length of the entries slice.
+	copied := original
+	copied.set(PerformanceData{Label: "copy_only", Value: "2"})
+
+	if original.has("copy_only") {
+		t.Fatal("ERROR: metric added to copy reported by original")
+	}
+
+	original.set(PerformanceData{Label: "original_only", Value: "3"})
+
+	if got, ok := original.get("original_only"); !ok || got.Value != "3" {
+		t.Fatalf("ERROR: want metric added to original, got %+v", got)
+	}
+
+	t.Log("OK: index entries added via copies ignored as expected")
+}
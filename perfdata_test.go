@@ -10,6 +10,7 @@
 package nagios_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/atc0005/go-nagios"
@@ -210,6 +211,30 @@ func TestParsePerfDataSucceedsForValidInput(t *testing.T) {
 			},
 		},
 
+		"Single quoted labels containing spaces": {
+			input: `'used space'=91%;80;95;0;100 'free space'=9%;;;;`,
+			result: []nagios.PerformanceData{
+				{
+					Label:             "used space",
+					Value:             "91",
+					UnitOfMeasurement: "%",
+					Warn:              "80",
+					Crit:              "95",
+					Min:               "0",
+					Max:               "100",
+				},
+				{
+					Label:             "free space",
+					Value:             "9",
+					UnitOfMeasurement: "%",
+					Warn:              "",
+					Crit:              "",
+					Min:               "",
+					Max:               "",
+				},
+			},
+		},
+
 		"Disk usage labels single quoted": {
 			input: `'/'=7826MB;28621;30211;0;31802 '/dev/shm'=0MB;3542;3739;0;3936 '/boot'=40MB;428;452;0;476`,
 			result: []nagios.PerformanceData{
@@ -680,3 +705,111 @@ func testParsePerfDataCollection(
 		}
 	}
 }
+
+func TestPlugin_PerfData_ReturnsSortedCopy(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "zeta", Value: "1"},
+		nagios.PerformanceData{Label: "alpha", Value: "2"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	got := plugin.PerfData()
+	want := []nagios.PerformanceData{
+		{Label: "alpha", Value: "2"},
+		{Label: "zeta", Value: "1"},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	// Modifying the returned collection should not affect the plugin.
+	got[0].Value = "99"
+	if plugin.PerfData()[0].Value != "2" {
+		t.Fatal("ERROR: returned collection shares state with plugin")
+	}
+
+	t.Log("OK: sorted copy of performance data returned as expected")
+}
+
+func TestPerformanceData_AppendToMatchesString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		perfData nagios.PerformanceData
+		want     string
+	}{
+		"label and value only": {
+			perfData: nagios.PerformanceData{Label: "time", Value: "49"},
+			want:     " 'time'=49;;;;",
+		},
+		"all fields": {
+			perfData: nagios.PerformanceData{
+				Label:             "datastore_001",
+				Value:             "51",
+				UnitOfMeasurement: "%",
+				Warn:              "80",
+				Crit:              "90",
+				Min:               "0",
+				Max:               "100",
+			},
+			want: " 'datastore_001'=51%;80;90;0;100",
+		},
+		"exceeds scratch buffer": {
+			perfData: nagios.PerformanceData{Label: strings.Repeat("a", 200), Value: "1"},
+			want:     " '" + strings.Repeat("a", 200) + "'=1;;;;",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if d := cmp.Diff(tt.want, tt.perfData.String()); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			prefix := []byte("existing content")
+			got := tt.perfData.AppendTo(prefix)
+			if d := cmp.Diff("existing content"+tt.want, string(got)); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Logf("OK: %q formatted as expected", tt.want)
+		})
+	}
+}
+
+func TestPlugin_ReservePerfData_RetainsBehavior(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ReservePerfData(0)
+	plugin.ReservePerfData(2)
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "zeta", Value: "1"},
+		nagios.PerformanceData{Label: "alpha", Value: "2"},
+		nagios.PerformanceData{Label: "ALPHA", Value: "3"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	want := []nagios.PerformanceData{
+		{Label: "ALPHA", Value: "3"},
+		{Label: "zeta", Value: "1"},
+	}
+
+	if d := cmp.Diff(want, plugin.PerfData()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: reserved performance data storage used as expected")
+}
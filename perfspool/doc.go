@@ -0,0 +1,52 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package perfspool parses Nagios host and service performance data spool
+files.
+
+# OVERVIEW
+
+Nagios (and compatible monitoring systems) can write performance data for
+processed check results to spool files using the host_perfdata_file_template
+and service_perfdata_file_template settings. Performance data processors
+(e.g., PNP4Nagios, Graphios) periodically rotate and process these files.
+
+Two template styles are supported:
+
+The self-describing (bulk mode) style where each tab-separated field is
+prefixed by the macro name:
+
+	DATATYPE::SERVICEPERFDATA	TIMET::$TIMET$	HOSTNAME::$HOSTNAME$	SERVICEDESC::$SERVICEDESC$	SERVICEPERFDATA::$SERVICEPERFDATA$	...
+
+and the positional style used by the sample Nagios configuration:
+
+	[SERVICEPERFDATA]	$TIMET$	$HOSTNAME$	$SERVICEDESC$	$SERVICEEXECUTIONTIME$	$SERVICELATENCY$	$SERVICEOUTPUT$	$SERVICEPERFDATA$
+	[HOSTPERFDATA]	$TIMET$	$HOSTNAME$	$HOSTEXECUTIONTIME$	$HOSTOUTPUT$	$HOSTPERFDATA$
+
+Each line is returned as a typed Record with the embedded performance data
+parsed using nagios.ParsePerfData. All macro values are also available as
+provided for custom templates.
+
+# HOW TO USE
+
+	reader := perfspool.NewReader(file)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// log and skip invalid line
+			continue
+		}
+
+		// process record
+	}
+*/
+package perfspool
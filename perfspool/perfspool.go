@@ -0,0 +1,339 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package perfspool
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// RecordType indicates whether a Record contains host or service performance
+// data.
+type RecordType string
+
+// Supported record types.
+const (
+	HostPerfData    RecordType = "HOSTPERFDATA"
+	ServicePerfData RecordType = "SERVICEPERFDATA"
+)
+
+const (
+	// fieldSeparator separates fields in a spool file line.
+	fieldSeparator string = "\t"
+
+	// macroSeparator separates the macro name and value for the
+	// self-describing template style.
+	macroSeparator string = "::"
+
+	// dataTypeMacro is the macro indicating the record type for the
+	// self-describing template style.
+	dataTypeMacro string = "DATATYPE"
+
+	// maxLineSize is the maximum supported spool file line length.
+	maxLineSize int = 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrInvalidRecord indicates that a spool file line could not be parsed
+	// as a performance data record.
+	ErrInvalidRecord = errors.New("invalid performance data record")
+
+	// ErrUnknownRecordType indicates that a spool file line did not specify
+	// a supported record type.
+	ErrUnknownRecordType = errors.New("unknown performance data record type")
+)
+
+// positionalMacros is the ordered list of macros used by the sample Nagios
+// configuration host and service performance data templates.
+var positionalMacros = map[RecordType][]string{
+	HostPerfData: {
+		"TIMET", "HOSTNAME", "HOSTEXECUTIONTIME", "HOSTOUTPUT", "HOSTPERFDATA",
+	},
+	ServicePerfData: {
+		"TIMET", "HOSTNAME", "SERVICEDESC", "SERVICEEXECUTIONTIME",
+		"SERVICELATENCY", "SERVICEOUTPUT", "SERVICEPERFDATA",
+	},
+}
+
+// Record is a single host or service performance data spool file entry.
+type Record struct {
+	// Type indicates whether the record contains host or service
+	// performance data.
+	Type RecordType
+
+	// Time is the time the check result was processed ($TIMET$).
+	Time time.Time
+
+	// HostName is the short name of the host ($HOSTNAME$).
+	HostName string
+
+	// ServiceDescription is the service name ($SERVICEDESC$). This is empty
+	// for host records.
+	ServiceDescription string
+
+	// CheckCommand is the check command and arguments
+	// ($SERVICECHECKCOMMAND$ or $HOSTCHECKCOMMAND$) if provided.
+	CheckCommand string
+
+	// State is the state label (e.g., OK, CRITICAL, UP) if provided.
+	State string
+
+	// StateType is the state type (SOFT or HARD) if provided.
+	StateType string
+
+	// Output is the one-line check output if provided.
+	Output string
+
+	// ExecutionTime is the check execution time if provided.
+	ExecutionTime time.Duration
+
+	// Latency is the check scheduling latency if provided.
+	Latency time.Duration
+
+	// RawPerfData is the performance data as recorded in the spool file.
+	RawPerfData string
+
+	// PerfData is the parsed performance data. This is empty if no
+	// performance data was recorded.
+	PerfData []nagios.PerformanceData
+
+	// Macros is the collection of all macro values provided by the spool
+	// file line indexed by macro name (without $ delimiters). This allows
+	// access to values from custom templates.
+	Macros map[string]string
+}
+
+// ParseError is returned for spool file lines which could not be parsed. The
+// line number is 1-based.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+// Error provides a human readable error message.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads performance data records from a spool file.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader returns a new Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	return &Reader{scanner: scanner}
+}
+
+// Read returns the next record. Blank lines are skipped. io.EOF is returned
+// when no further records are available.
+//
+// A *ParseError is returned for lines which could not be parsed; reading may
+// continue with the next line. If only the embedded performance data is
+// invalid the remaining record fields are also returned.
+func (r *Reader) Read() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := strings.TrimRight(r.scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		record, err := ParseLine(line)
+		if err != nil {
+			return record, &ParseError{Line: r.line, Err: err}
+		}
+
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, fmt.Errorf("failed to read spool file: %w", err)
+	}
+
+	return Record{}, io.EOF
+}
+
+// ReadAll reads all remaining records. Reading stops at the first error.
+func (r *Reader) ReadAll() ([]Record, error) {
+	var records []Record
+
+	for {
+		record, err := r.Read()
+		switch {
+		case errors.Is(err, io.EOF):
+			return records, nil
+		case err != nil:
+			return records, err
+		}
+
+		records = append(records, record)
+	}
+}
+
+// ParseLine parses a single spool file line using either the
+// self-describing (MACRO::value) or positional template style.
+//
+// If only the embedded performance data is invalid the remaining record
+// fields are returned along with an error wrapping
+// nagios.ErrInvalidPerformanceDataFormat.
+func ParseLine(line string) (Record, error) {
+	fields := strings.Split(line, fieldSeparator)
+
+	var macros map[string]string
+	var err error
+
+	if strings.HasPrefix(fields[0], "[") {
+		macros, err = positionalFields(fields)
+	} else {
+		macros, err = macroFields(fields)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	return newRecord(macros)
+}
+
+// positionalFields returns macro values for a line using the positional
+// template style.
+func positionalFields(fields []string) (map[string]string, error) {
+	recordType := RecordType(strings.Trim(fields[0], "[]"))
+
+	names, ok := positionalMacros[recordType]
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", fields[0], ErrUnknownRecordType)
+	}
+
+	values := fields[1:]
+	if len(values) != len(names) {
+		return nil, fmt.Errorf(
+			"%s: want %d fields, got %d: %w",
+			recordType, len(names), len(values), ErrInvalidRecord,
+		)
+	}
+
+	macros := make(map[string]string, len(names)+1)
+	macros[dataTypeMacro] = string(recordType)
+	for i, name := range names {
+		macros[name] = values[i]
+	}
+
+	return macros, nil
+}
+
+// macroFields returns macro values for a line using the self-describing
+// template style.
+func macroFields(fields []string) (map[string]string, error) {
+	macros := make(map[string]string, len(fields))
+
+	for _, field := range fields {
+		name, value, found := strings.Cut(field, macroSeparator)
+		if !found || name == "" {
+			return nil, fmt.Errorf("field %q not in MACRO::value format: %w", field, ErrInvalidRecord)
+		}
+		macros[name] = value
+	}
+
+	return macros, nil
+}
+
+// newRecord returns a Record populated from the given macro values.
+func newRecord(macros map[string]string) (Record, error) {
+	record := Record{
+		Type:   RecordType(macros[dataTypeMacro]),
+		Macros: macros,
+	}
+
+	var prefix string
+	switch record.Type {
+	case HostPerfData:
+		prefix = "HOST"
+	case ServicePerfData:
+		prefix = "SERVICE"
+		record.ServiceDescription = macros["SERVICEDESC"]
+	default:
+		return Record{}, fmt.Errorf("%q: %w", record.Type, ErrUnknownRecordType)
+	}
+
+	record.HostName = macros["HOSTNAME"]
+	if record.HostName == "" {
+		return Record{}, fmt.Errorf("missing HOSTNAME: %w", ErrInvalidRecord)
+	}
+
+	if record.Type == ServicePerfData && record.ServiceDescription == "" {
+		return Record{}, fmt.Errorf("missing SERVICEDESC: %w", ErrInvalidRecord)
+	}
+
+	if raw, ok := macros["TIMET"]; ok {
+		timet, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return Record{}, fmt.Errorf("invalid TIMET %q: %w", raw, ErrInvalidRecord)
+		}
+		record.Time = time.Unix(timet, 0)
+	}
+
+	var err error
+	if record.ExecutionTime, err = parseSeconds(macros, prefix+"EXECUTIONTIME"); err != nil {
+		return Record{}, err
+	}
+	if record.Latency, err = parseSeconds(macros, prefix+"LATENCY"); err != nil {
+		return Record{}, err
+	}
+
+	record.CheckCommand = macros[prefix+"CHECKCOMMAND"]
+	record.State = macros[prefix+"STATE"]
+	record.StateType = macros[prefix+"STATETYPE"]
+	record.Output = macros[prefix+"OUTPUT"]
+	record.RawPerfData = strings.TrimSpace(macros[prefix+"PERFDATA"])
+
+	if record.RawPerfData != "" {
+		perfData, err := nagios.ParsePerfData(record.RawPerfData)
+		if err != nil {
+			return record, err
+		}
+		record.PerfData = perfData
+	}
+
+	return record, nil
+}
+
+// parseSeconds returns the named macro value (in fractional seconds) as a
+// duration. Missing or empty values are returned as zero.
+func parseSeconds(macros map[string]string, name string) (time.Duration, error) {
+	raw := macros[name]
+	if raw == "" {
+		return 0, nil
+	}
+
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, raw, ErrInvalidRecord)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package perfspool
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestReader_Read_ParsesSupportedTemplateStyles(t *testing.T) {
+	t.Parallel()
+
+	spool := strings.Join([]string{
+		"DATATYPE::SERVICEPERFDATA\tTIMET::1700000000\tHOSTNAME::web01\tSERVICEDESC::HTTP\t" +
+			"SERVICEPERFDATA::time=0.5s;1;2;0; size=512B\tSERVICECHECKCOMMAND::check_http!-S\t" +
+			"SERVICESTATE::OK\tSERVICESTATETYPE::HARD",
+		"",
+		"[HOSTPERFDATA]\t1700000010\tweb02\t0.250\tPING OK\trta=1.2ms;100;500;0",
+	}, "\n")
+
+	reader := NewReader(strings.NewReader(spool))
+
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("ERROR: failed to read spool file: %v", err)
+	}
+
+	want := []Record{
+		{
+			Type:               ServicePerfData,
+			Time:               time.Unix(1700000000, 0),
+			HostName:           "web01",
+			ServiceDescription: "HTTP",
+			CheckCommand:       "check_http!-S",
+			State:              "OK",
+			StateType:          "HARD",
+			RawPerfData:        "time=0.5s;1;2;0; size=512B",
+			PerfData: []nagios.PerformanceData{
+				{Label: "time", Value: "0.5", UnitOfMeasurement: "s", Warn: "1", Crit: "2", Min: "0"},
+				{Label: "size", Value: "512", UnitOfMeasurement: "B"},
+			},
+		},
+		{
+			Type:          HostPerfData,
+			Time:          time.Unix(1700000010, 0),
+			HostName:      "web02",
+			Output:        "PING OK",
+			ExecutionTime: 250 * time.Millisecond,
+			RawPerfData:   "rta=1.2ms;100;500;0",
+			PerfData: []nagios.PerformanceData{
+				{Label: "rta", Value: "1.2", UnitOfMeasurement: "ms", Warn: "100", Crit: "500", Min: "0"},
+			},
+		},
+	}
+
+	if d := cmp.Diff(want, got, cmpopts.IgnoreFields(Record{}, "Macros")); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	if got[1].Macros["HOSTEXECUTIONTIME"] != "0.250" {
+		t.Errorf("ERROR: macro value not retained: %v", got[1].Macros)
+	}
+
+	t.Log("OK: spool file records parsed as expected")
+}
+
+func TestReader_Read_ReportsInvalidLinesAndContinues(t *testing.T) {
+	t.Parallel()
+
+	spool := "[SERVICEPERFDATA]\t1700000000\tweb01\n" +
+		"DATATYPE::SERVICEPERFDATA\tHOSTNAME::web01\tSERVICEDESC::Disk\tSERVICEPERFDATA::=broken\n" +
+		"DATATYPE::HOSTPERFDATA\tHOSTNAME::web01\tHOSTPERFDATA::\n"
+
+	reader := NewReader(strings.NewReader(spool))
+
+	_, err := reader.Read()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 1 || !errors.Is(err, ErrInvalidRecord) {
+		t.Fatalf("ERROR: want line 1 %v, got %v", ErrInvalidRecord, err)
+	}
+
+	record, err := reader.Read()
+	if !errors.Is(err, nagios.ErrInvalidPerformanceDataFormat) {
+		t.Fatalf("ERROR: want %v, got %v", nagios.ErrInvalidPerformanceDataFormat, err)
+	}
+	if record.ServiceDescription != "Disk" {
+		t.Errorf("ERROR: want partial record for invalid perfdata, got %+v", record)
+	}
+
+	record, err = reader.Read()
+	if err != nil || record.Type != HostPerfData || record.PerfData != nil {
+		t.Fatalf("ERROR: unexpected result for empty perfdata: %+v, %v", record, err)
+	}
+
+	if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("ERROR: want %v, got %v", io.EOF, err)
+	}
+
+	t.Log("OK: invalid lines reported as expected")
+}
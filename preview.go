@@ -0,0 +1,178 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputPreview is rendered plugin output as it is processed by Nagios Core
+// (and Nagios XI) and displayed in the web UI and notifications.
+type OutputPreview struct {
+	// ServiceOutput is the one-line summary displayed in web UI status
+	// views. This is the value of the $SERVICEOUTPUT$ (or $HOSTOUTPUT$)
+	// macro.
+	ServiceOutput string
+
+	// LongServiceOutput is the additional output displayed on the service
+	// (or host) details page. This is the value of the $LONGSERVICEOUTPUT$
+	// (or $LONGHOSTOUTPUT$) macro.
+	LongServiceOutput string
+
+	// PerfData is the performance data collected from all lines of output.
+	// This is the value of the $SERVICEPERFDATA$ (or $HOSTPERFDATA$) macro
+	// and is not displayed in web UI status views.
+	PerfData string
+
+	// NotificationServiceOutput is the ServiceOutput value as used in
+	// notifications and event handlers; characters listed in the default
+	// illegal_macro_output_chars setting are removed.
+	NotificationServiceOutput string
+
+	// NotificationLongServiceOutput is the LongServiceOutput value as used
+	// in notifications and event handlers; characters listed in the default
+	// illegal_macro_output_chars setting are removed.
+	NotificationLongServiceOutput string
+
+	// Length is the length (in bytes) of the plugin output before
+	// truncation.
+	Length int
+
+	// MaxLength is the maximum plugin output length (in bytes) applied.
+	MaxLength int
+
+	// Truncated indicates whether the plugin output exceeded MaxLength and
+	// was truncated.
+	Truncated bool
+}
+
+// PreviewOutput returns a preview of the given rendered plugin output as
+// displayed by Nagios using the default Nagios Core maximum plugin output
+// length. See PreviewOutputWithLimit for details.
+func PreviewOutput(output string) OutputPreview {
+	return PreviewOutputWithLimit(output, NagiosCoreMaxOutputLength)
+}
+
+// PreviewOutputWithLimit returns a preview of the given rendered plugin
+// output as displayed by Nagios after applying the given maximum plugin
+// output length (e.g., NRPEv2MaxOutputLength for plugins executed via NRPE
+// v2; see OutputLimitFor). A non-positive length uses the default Nagios
+// Core maximum plugin output length.
+//
+// The output is processed in the same way as Nagios:
+//
+//   - output beyond the maximum length is discarded
+//   - carriage returns are removed
+//   - the first line is split into the summary and performance data at the
+//     first pipe character
+//   - later lines up to the first pipe character found are long output; the
+//     remaining text (including any later lines) is performance data
+//   - escaped newlines (a backslash followed by "n") in long output are
+//     displayed as line breaks
+//   - leading and trailing whitespace is removed from the summary, long
+//     output and performance data
+//
+// Notification values additionally have characters listed in the default
+// illegal_macro_output_chars setting removed.
+func PreviewOutputWithLimit(output string, maxLength int) OutputPreview {
+	if maxLength <= 0 {
+		maxLength = NagiosCoreMaxOutputLength
+	}
+
+	preview := OutputPreview{
+		Length:    len(output),
+		MaxLength: maxLength,
+	}
+
+	if len(output) > maxLength {
+		output = output[:maxLength]
+		preview.Truncated = true
+	}
+
+	output = strings.ReplaceAll(output, "\r", "")
+
+	lines := strings.Split(output, "\n")
+
+	summary, perfData, _ := strings.Cut(lines[0], "|")
+	preview.ServiceOutput = strings.TrimSpace(summary)
+
+	perfDataFields := []string{strings.TrimSpace(perfData)}
+
+	longOutput := make([]string, 0, len(lines)-1)
+	for i := 1; i < len(lines); i++ {
+		text, perfData, found := strings.Cut(lines[i], "|")
+		longOutput = append(longOutput, strings.TrimRight(text, " \t"))
+
+		if found {
+			perfDataFields = append(perfDataFields, strings.TrimSpace(perfData))
+			for _, line := range lines[i+1:] {
+				perfDataFields = append(perfDataFields, strings.TrimSpace(line))
+			}
+
+			break
+		}
+	}
+
+	preview.LongServiceOutput = strings.TrimSpace(
+		strings.ReplaceAll(strings.Join(longOutput, "\n"), `\n`, "\n"),
+	)
+	preview.PerfData = strings.Join(strings.Fields(strings.Join(perfDataFields, " ")), " ")
+
+	preview.NotificationServiceOutput = stripIllegalMacroOutputChars(preview.ServiceOutput)
+	preview.NotificationLongServiceOutput = stripIllegalMacroOutputChars(preview.LongServiceOutput)
+
+	return preview
+}
+
+// String returns a human readable report of the preview suitable for
+// display to plugin authors.
+func (op OutputPreview) String() string {
+	var b strings.Builder
+
+	section := func(title string, value string) {
+		fmt.Fprintf(&b, "%s:\n", title)
+		if value == "" {
+			b.WriteString("  (empty)\n")
+			return
+		}
+
+		for _, line := range strings.Split(value, "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	section("Status information (web UI)", op.ServiceOutput)
+	section("Long output (web UI details)", op.LongServiceOutput)
+	section("Performance data", op.PerfData)
+	section("$SERVICEOUTPUT$ (notifications)", op.NotificationServiceOutput)
+	section("$LONGSERVICEOUTPUT$ (notifications)", op.NotificationLongServiceOutput)
+
+	if op.Truncated {
+		fmt.Fprintf(
+			&b,
+			"NOTE: output truncated from %d to %d bytes\n",
+			op.Length,
+			op.MaxLength,
+		)
+	}
+
+	return b.String()
+}
+
+// stripIllegalMacroOutputChars returns the given value with characters
+// listed in the default Nagios illegal_macro_output_chars setting removed.
+func stripIllegalMacroOutputChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(lintIllegalMacroOutputChars, r) {
+			return -1
+		}
+
+		return r
+	}, s)
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPreviewOutput_SplitsOutputAsNagios(t *testing.T) {
+	t.Parallel()
+
+	output := "WARNING: disk \"/data\" usage high | 'used'=91%;80;90;; \n" +
+		"**ERRORS** \n" +
+		"* volume /data: 91% used\\nsecond line \r\n" +
+		"details & more | 'free'=9%;;;;\n" +
+		"'inodes'=12;;;;\n"
+
+	want := nagios.OutputPreview{
+		ServiceOutput:                 `WARNING: disk "/data" usage high`,
+		LongServiceOutput:             "**ERRORS**\n* volume /data: 91% used\nsecond line\ndetails & more",
+		PerfData:                      "'used'=91%;80;90;; 'free'=9%;;;; 'inodes'=12;;;;",
+		NotificationServiceOutput:     "WARNING: disk /data usage high",
+		NotificationLongServiceOutput: "**ERRORS**\n* volume /data: 91% used\nsecond line\ndetails  more",
+		Length:                        len(output),
+		MaxLength:                     8192,
+	}
+
+	if d := cmp.Diff(want, nagios.PreviewOutput(output)); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: output preview matches Nagios processing")
+}
+
+func TestPreviewOutputWithLimit_TruncatesOutput(t *testing.T) {
+	t.Parallel()
+
+	output := "OK: all good\n" + strings.Repeat("x", 2000) + "\n"
+
+	got := nagios.PreviewOutputWithLimit(output, 1024)
+
+	switch {
+	case !got.Truncated:
+		t.Error("ERROR: want output marked as truncated")
+	case got.Length != len(output) || got.MaxLength != 1024:
+		t.Errorf("ERROR: want length %d and max length 1024, got %d and %d", len(output), got.Length, got.MaxLength)
+	case len(got.LongServiceOutput) != 1024-len("OK: all good\n"):
+		t.Errorf("ERROR: unexpected long output length %d", len(got.LongServiceOutput))
+	case !strings.Contains(got.String(), "NOTE: output truncated from 2014 to 1024 bytes"):
+		t.Errorf("ERROR: truncation note not found in report:\n%s", got.String())
+	}
+
+	if def := nagios.PreviewOutputWithLimit(output, 0); def.Truncated || def.MaxLength != 8192 {
+		t.Errorf("ERROR: want default limit applied for non-positive length, got %+v", def)
+	}
+
+	t.Log("OK: output truncated as expected")
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package prombridge
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Collector retains the most recently converted metric families and serves
+// them to Prometheus scrape requests. Collector is safe for concurrent use.
+type Collector struct {
+	converter *Converter
+
+	mu       sync.RWMutex
+	families []MetricFamily
+}
+
+// NewCollector constructs a new Collector using the given Converter. If nil,
+// a Converter without namespace or constant labels is used.
+func NewCollector(converter *Converter) *Collector {
+	if converter == nil {
+		converter = NewConverter("", nil)
+	}
+
+	return &Collector{
+		converter: converter,
+	}
+}
+
+// Update replaces the retained metric families with the given performance
+// data. The retained metric families are unmodified if conversion fails.
+func (c *Collector) Update(metrics ...nagios.PerformanceData) error {
+	families, err := c.converter.Convert(metrics...)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.families = families
+
+	return nil
+}
+
+// Families returns a copy of the retained metric families.
+func (c *Collector) Families() []MetricFamily {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	families := make([]MetricFamily, len(c.families))
+	copy(families, c.families)
+
+	return families
+}
+
+// ServeHTTP writes the retained metric families using the Prometheus text
+// exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, c.Families()...); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", TextContentType)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package prombridge converts Nagios plugin performance data to Prometheus
+metric families and serves them using the Prometheus text exposition
+format.
+
+# OVERVIEW
+
+Each performance data metric is converted to a separate metric family.
+Metric names are derived from the performance data label (sanitized) and
+unit of measurement. Time and byte based units of measurement are
+normalized to seconds and bytes as recommended by Prometheus naming
+conventions. Continuous counters (UoM "c") are exposed as counters, all
+other metrics are exposed as gauges.
+
+This package does not depend on the Prometheus client library. The Collector
+type implements http.Handler and can be registered directly with an HTTP
+server to provide a scrape endpoint.
+
+# HOW TO USE
+
+	converter := prombridge.NewConverter("nagios", map[string]string{
+		"host":    "web01",
+		"service": "HTTP",
+	})
+	collector := prombridge.NewCollector(converter)
+
+	http.Handle("/metrics", collector)
+
+	// After each check run:
+	if err := collector.Update(plugin.PerfData()...); err != nil {
+		// handle error
+	}
+*/
+package prombridge
@@ -0,0 +1,96 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package prombridge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TextContentType is the HTTP Content-Type of the Prometheus text exposition
+// format.
+const TextContentType string = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteText writes the given metric families to w using the Prometheus text
+// exposition format.
+func WriteText(w io.Writer, families ...MetricFamily) error {
+	bw := bufio.NewWriter(w)
+
+	for _, family := range families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", family.Name, escapeHelp(family.Help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", family.Name, family.Type)
+
+		for _, metric := range family.Metrics {
+			bw.WriteString(family.Name)
+			writeLabels(bw, metric.Labels)
+			bw.WriteByte(' ')
+			bw.WriteString(formatValue(metric.Value))
+			bw.WriteByte('\n')
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return nil
+}
+
+// writeLabels writes the given labels (sorted by name) in exposition format.
+// Nothing is written if no labels are provided.
+func writeLabels(bw *bufio.Writer, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		fmt.Fprintf(bw, "%s=\"%s\"", name, escapeLabelValue(labels[name]))
+	}
+	bw.WriteByte('}')
+}
+
+// formatValue formats the given sample value using the representations
+// required by the exposition format for special values.
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// escapeHelp escapes backslashes and newlines in help text.
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and newlines in label
+// values.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,236 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package prombridge
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// MetricType is the Prometheus metric type of a metric family.
+type MetricType string
+
+// Supported metric types.
+const (
+	// MetricTypeGauge is used for metrics which may go up or down.
+	MetricTypeGauge MetricType = "gauge"
+
+	// MetricTypeCounter is used for continuous counters (UoM "c").
+	MetricTypeCounter MetricType = "counter"
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrInvalidMetricValue indicates that a performance data value could
+	// not be converted to a numeric value.
+	ErrInvalidMetricValue = errors.New("invalid performance data value")
+
+	// ErrDuplicateMetricName indicates that multiple performance data
+	// metrics map to the same Prometheus metric name.
+	ErrDuplicateMetricName = errors.New("duplicate metric name")
+)
+
+// Metric is a single sample within a metric family.
+type Metric struct {
+	// Labels is the collection of label names and values for the sample.
+	Labels map[string]string
+
+	// Value is the sample value.
+	Value float64
+}
+
+// MetricFamily is a collection of samples sharing a name, type and help
+// text.
+type MetricFamily struct {
+	// Name is the metric family name.
+	Name string
+
+	// Help is the metric family help text.
+	Help string
+
+	// Type is the metric family type.
+	Type MetricType
+
+	// Metrics is the collection of samples.
+	Metrics []Metric
+}
+
+// unitConversion describes how a performance data unit of measurement maps
+// to a Prometheus base unit.
+type unitConversion struct {
+	suffix     string
+	multiplier float64
+}
+
+// unitConversions maps (lowercase) performance data units of measurement to
+// Prometheus base units.
+var unitConversions = map[string]unitConversion{
+	"s":  {suffix: "seconds", multiplier: 1},
+	"ms": {suffix: "seconds", multiplier: 1e-3},
+	"us": {suffix: "seconds", multiplier: 1e-6},
+	"b":  {suffix: "bytes", multiplier: 1},
+	"kb": {suffix: "bytes", multiplier: 1 << 10},
+	"mb": {suffix: "bytes", multiplier: 1 << 20},
+	"gb": {suffix: "bytes", multiplier: 1 << 30},
+	"tb": {suffix: "bytes", multiplier: 1 << 40},
+	"%":  {suffix: "percent", multiplier: 1},
+}
+
+// counterUoM is the performance data unit of measurement indicating a
+// continuous counter.
+const counterUoM string = "c"
+
+// Converter converts performance data to Prometheus metric families.
+type Converter struct {
+	// namespace is the optional prefix applied to all metric names.
+	namespace string
+
+	// constLabels is the collection of labels applied to all samples.
+	constLabels map[string]string
+}
+
+// NewConverter constructs a new Converter using the given (optional)
+// namespace and constant labels (e.g., host and service names).
+func NewConverter(namespace string, constLabels map[string]string) *Converter {
+	labels := make(map[string]string, len(constLabels))
+	for k, v := range constLabels {
+		labels[SanitizeName(k)] = v
+	}
+
+	return &Converter{
+		namespace:   SanitizeName(namespace),
+		constLabels: labels,
+	}
+}
+
+// Convert returns the given performance data as metric families sorted by
+// name. Metrics with an undetermined value ("U") are returned with a NaN
+// value.
+func (c *Converter) Convert(metrics ...nagios.PerformanceData) ([]MetricFamily, error) {
+	families := make([]MetricFamily, 0, len(metrics))
+	seen := make(map[string]string, len(metrics))
+
+	for _, pd := range metrics {
+		family, err := c.convert(pd)
+		if err != nil {
+			return nil, err
+		}
+
+		if label, ok := seen[family.Name]; ok {
+			return nil, fmt.Errorf(
+				"labels %q and %q both map to %q: %w",
+				label,
+				pd.Label,
+				family.Name,
+				ErrDuplicateMetricName,
+			)
+		}
+		seen[family.Name] = pd.Label
+
+		families = append(families, family)
+	}
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	return families, nil
+}
+
+// convert returns the given performance data metric as a metric family.
+func (c *Converter) convert(pd nagios.PerformanceData) (MetricFamily, error) {
+	value, err := parseValue(pd.Value)
+	if err != nil {
+		return MetricFamily{}, fmt.Errorf("metric %q: %w", pd.Label, err)
+	}
+
+	metricType := MetricTypeGauge
+	nameParts := make([]string, 0, 3)
+	if c.namespace != "" {
+		nameParts = append(nameParts, c.namespace)
+	}
+	nameParts = append(nameParts, SanitizeName(pd.Label))
+
+	uom := strings.ToLower(pd.UnitOfMeasurement)
+	switch conversion, ok := unitConversions[uom]; {
+	case ok:
+		value *= conversion.multiplier
+		nameParts = append(nameParts, conversion.suffix)
+	case uom == counterUoM:
+		metricType = MetricTypeCounter
+		nameParts = append(nameParts, "total")
+	}
+
+	help := fmt.Sprintf("Nagios performance data metric %q", pd.Label)
+	if pd.UnitOfMeasurement != "" {
+		help += fmt.Sprintf(" (UoM %q)", pd.UnitOfMeasurement)
+	}
+
+	labels := make(map[string]string, len(c.constLabels))
+	for k, v := range c.constLabels {
+		labels[k] = v
+	}
+
+	return MetricFamily{
+		Name: strings.Join(nameParts, "_"),
+		Help: help,
+		Type: metricType,
+		Metrics: []Metric{
+			{
+				Labels: labels,
+				Value:  value,
+			},
+		},
+	}, nil
+}
+
+// parseValue converts the given performance data value to a float. The
+// undetermined value "U" is converted to NaN.
+func parseValue(value string) (float64, error) {
+	if value == "U" {
+		return math.NaN(), nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", value, ErrInvalidMetricValue)
+	}
+
+	return f, nil
+}
+
+// SanitizeName converts the given value to a valid Prometheus metric or label
+// name. Invalid characters are replaced with underscores and a leading
+// underscore is added if the value begins with a digit.
+func SanitizeName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package prombridge
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func testPerfData() []nagios.PerformanceData {
+	return []nagios.PerformanceData{
+		{Label: "time", Value: "874", UnitOfMeasurement: "ms"},
+		{Label: "bytes sent", Value: "12", UnitOfMeasurement: "c"},
+		{Label: "1min-load", Value: "0.26"},
+		{Label: "memory", Value: "2", UnitOfMeasurement: "KB"},
+		{Label: "status", Value: "U"},
+	}
+}
+
+func TestConverter_Convert_MapsNamesTypesAndUnits(t *testing.T) {
+	t.Parallel()
+
+	converter := NewConverter("nagios", map[string]string{"host": "web01"})
+
+	families, err := converter.Convert(testPerfData()...)
+	if err != nil {
+		t.Fatalf("ERROR: unexpected conversion failure: %v", err)
+	}
+
+	type summary struct {
+		Name  string
+		Type  MetricType
+		Value string
+	}
+
+	got := make([]summary, 0, len(families))
+	for _, f := range families {
+		got = append(got, summary{f.Name, f.Type, formatValue(f.Metrics[0].Value)})
+
+		if f.Metrics[0].Labels["host"] != "web01" {
+			t.Errorf("ERROR: constant label missing from %s", f.Name)
+		}
+	}
+
+	want := []summary{
+		{"nagios__1min_load", MetricTypeGauge, "0.26"},
+		{"nagios_bytes_sent_total", MetricTypeCounter, "12"},
+		{"nagios_memory_bytes", MetricTypeGauge, "2048"},
+		{"nagios_status", MetricTypeGauge, "NaN"},
+		{"nagios_time_seconds", MetricTypeGauge, "0.874"},
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: performance data converted as expected")
+}
+
+func TestConverter_Convert_RejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	converter := NewConverter("", nil)
+
+	_, err := converter.Convert(nagios.PerformanceData{Label: "a", Value: "abc"})
+	if !errors.Is(err, ErrInvalidMetricValue) {
+		t.Errorf("ERROR: want %v, got %v", ErrInvalidMetricValue, err)
+	}
+
+	_, err = converter.Convert(
+		nagios.PerformanceData{Label: "a b", Value: "1"},
+		nagios.PerformanceData{Label: "a-b", Value: "2"},
+	)
+	if !errors.Is(err, ErrDuplicateMetricName) {
+		t.Errorf("ERROR: want %v, got %v", ErrDuplicateMetricName, err)
+	}
+}
+
+func TestCollector_ServeHTTP_WritesTextExposition(t *testing.T) {
+	t.Parallel()
+
+	collector := NewCollector(NewConverter("nagios", map[string]string{
+		"service": `HTTP "main"`,
+		"host":    "web01",
+	}))
+
+	if err := collector.Update(nagios.PerformanceData{Label: "time", Value: "1500", UnitOfMeasurement: "ms"}); err != nil {
+		t.Fatalf("ERROR: unexpected update failure: %v", err)
+	}
+
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("ERROR: scrape failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ERROR: failed to read scrape response: %v", err)
+	}
+
+	want := "# HELP nagios_time_seconds Nagios performance data metric \"time\" (UoM \"ms\")\n" +
+		"# TYPE nagios_time_seconds gauge\n" +
+		"nagios_time_seconds{host=\"web01\",service=\"HTTP \\\"main\\\"\"} 1.5\n"
+
+	if d := cmp.Diff(want, string(body)); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != TextContentType {
+		t.Errorf("ERROR: want Content-Type %q, got %q", TextContentType, ct)
+	}
+
+	t.Log("OK: metrics served as expected")
+}
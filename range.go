@@ -18,6 +18,16 @@ import (
 	"strings"
 )
 
+// Regular expressions used to validate and parse threshold ranges. These are
+// compiled once instead of for every parsed range; compiled expressions are
+// safe for concurrent use.
+var (
+	rangeDigitOrInfinityRegex        = regexp.MustCompile(`[\d~]`)
+	rangeOptionalInvertAndRangeRegex = regexp.MustCompile(`^@?([-+]?[\d.]+(?:e[-+]?[\d.]+)?|~)?(:([-+]?[\d.]+(?:e[-+]?[\d.]+)?)?)?$`)
+	rangeFirstHalfRegex              = regexp.MustCompile(`^([-+]?[\d.]+(?:e[-+]?[\d.]+)?)?:`)
+	rangeEndRegex                    = regexp.MustCompile(`^[-+]?[\d.]+(?:e[-+]?[\d.]+)?$`)
+)
+
 // Range represents the thresholds that the user can pass in for warning and
 // critical, this format is based on the [Nagios Plugin Dev Guidelines:
 // Threshold and Ranges] definition.
@@ -42,6 +52,37 @@ func (r Range) CheckRange(value string) bool {
 	return isOutsideRange
 }
 
+// String returns the range in the threshold format described by the [Nagios
+// Plugin Dev Guidelines: Threshold and Ranges] definition. The result is
+// suitable for use with ParseRangeString and as a performance data Warn or
+// Crit field value.
+//
+// A range starting at zero is returned in the short form (e.g., "10"
+// instead of "0:10").
+//
+// [Nagios Plugin Dev Guidelines: Threshold and Ranges]: https://nagios-plugins.org/doc/guidelines.html#THRESHOLDFORMAT
+func (r Range) String() string {
+	var b strings.Builder
+
+	if r.AlertOn == "INSIDE" {
+		b.WriteString("@")
+	}
+
+	switch {
+	case r.StartInfinity:
+		b.WriteString("~:")
+	case r.EndInfinity || r.Start != 0:
+		b.WriteString(strconv.FormatFloat(r.Start, 'f', -1, 64))
+		b.WriteString(":")
+	}
+
+	if !r.EndInfinity {
+		b.WriteString(strconv.FormatFloat(r.End, 'f', -1, 64))
+	}
+
+	return b.String()
+}
+
 // checkOutsideRange returns in the inverse of CheckRange. It is used to
 // handle the inverting logic of "inside" vs "outside" ranges.
 //
@@ -114,31 +155,26 @@ func ParseRangeString(input string) *Range {
 		AlertOn:       "OUTSIDE",
 	}
 
-	// Define regular expressions
-	digitOrInfinity := regexp.MustCompile(`[\d~]`)
-	optionalInvertAndRange := regexp.MustCompile(`^@?([-+]?[\d.]+(?:e[-+]?[\d.]+)?|~)?(:([-+]?[\d.]+(?:e[-+]?[\d.]+)?)?)?$`)
-	firstHalfOfRange := regexp.MustCompile(`^([-+]?[\d.]+(?:e[-+]?[\d.]+)?)?:`)
-	endOfRange := regexp.MustCompile(`^[-+]?[\d.]+(?:e[-+]?[\d.]+)?$`)
-
 	// Validate input format
-	if !(digitOrInfinity.MatchString(input) && optionalInvertAndRange.MatchString(input)) {
+	if !(rangeDigitOrInfinityRegex.MatchString(input) && rangeOptionalInvertAndRangeRegex.MatchString(input)) {
 		return nil
 	}
 
-	switch {
 	// Parse alert inversion (starts with @)
-	case strings.HasPrefix(input, "@"):
+	if strings.HasPrefix(input, "@") {
 		r.AlertOn = "INSIDE"
 		input = input[1:]
+	}
 
-	// Parse start infinity (~ symbol at start)
-	case strings.HasPrefix(input, "~"):
+	// Parse start infinity (~ symbol at start, optionally following the
+	// alert inversion symbol)
+	if strings.HasPrefix(input, "~") {
 		r.StartInfinity = true
 		input = input[1:]
 	}
 
 	// Parse start of range (e.g., "10:")
-	if rangeComponents := firstHalfOfRange.FindStringSubmatch(input); rangeComponents != nil {
+	if rangeComponents := rangeFirstHalfRegex.FindStringSubmatch(input); rangeComponents != nil {
 		if rangeComponents[1] != "" {
 			r.Start, _ = strconv.ParseFloat(rangeComponents[1], 64)
 			r.StartInfinity = false
@@ -148,7 +184,7 @@ func ParseRangeString(input string) *Range {
 	}
 
 	// Parse end of range (e.g., "10" or "x:10")
-	if endOfRangeComponents := endOfRange.FindStringSubmatch(input); endOfRangeComponents != nil {
+	if endOfRangeComponents := rangeEndRegex.FindStringSubmatch(input); endOfRangeComponents != nil {
 		r.End, _ = strconv.ParseFloat(endOfRangeComponents[0], 64)
 		r.EndInfinity = false
 	}
@@ -164,28 +200,119 @@ func ParseRangeString(input string) *Range {
 // EvaluateThreshold causes the performance data to be checked against the
 // Warn and Crit thresholds provided by client code and sets the
 // ExitStatusCode of the plugin as appropriate.
+//
+// Each given metric is evaluated. Every metric value found outside of a
+// threshold range is recorded as a violation (see ThresholdViolations) and
+// listed in a separate section of the plugin output; evaluating a metric
+// again replaces the violation recorded for the metric label. The
+// ExitStatusCode is set using the most severe outcome. If a threshold cannot
+// be parsed the outcome for the metric is UNKNOWN and the first such error is
+// returned once all metrics are evaluated.
 func (p *Plugin) EvaluateThreshold(perfData ...PerformanceData) error {
+	var firstErr error
+
+	outcome := StateOKExitCode
+	escalate := func(exitCode int) {
+		if isMoreSevereExitCode(exitCode, outcome) {
+			outcome = exitCode
+		}
+	}
+
 	for i := range perfData {
 		// Evaluate critical threshold
-		if inCritical, err := evaluateThreshold(perfData[i].Crit, perfData[i].Value); err != nil {
-			p.ExitStatusCode = StateUNKNOWNExitCode
-			return err
-		} else if inCritical {
-			p.ExitStatusCode = StateCRITICALExitCode
-			return nil
+		inCritical, err := evaluateThreshold(perfData[i].Crit, perfData[i].Value)
+		p.logThresholdDecision(perfData[i], "critical", perfData[i].Crit, inCritical, err)
+		switch {
+		case err != nil:
+			escalate(StateUNKNOWNExitCode)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		case inCritical:
+			escalate(StateCRITICALExitCode)
+			p.recordThresholdViolation(perfData[i], perfData[i].Crit, StateCRITICALExitCode)
+			continue
 		}
 
 		// Evaluate warning threshold
-		if inWarning, err := evaluateThreshold(perfData[i].Warn, perfData[i].Value); err != nil {
-			p.ExitStatusCode = StateUNKNOWNExitCode
-			return err
-		} else if inWarning {
-			p.ExitStatusCode = StateWARNINGExitCode
-			return nil
+		inWarning, err := evaluateThreshold(perfData[i].Warn, perfData[i].Value)
+		p.logThresholdDecision(perfData[i], "warning", perfData[i].Warn, inWarning, err)
+		switch {
+		case err != nil:
+			escalate(StateUNKNOWNExitCode)
+			if firstErr == nil {
+				firstErr = err
+			}
+		case inWarning:
+			escalate(StateWARNINGExitCode)
+			p.recordThresholdViolation(perfData[i], perfData[i].Warn, StateWARNINGExitCode)
 		}
 	}
 
-	return nil
+	if outcome != StateOKExitCode {
+		p.ExitStatusCode = p.exitState(outcome)
+	}
+
+	return firstErr
+}
+
+// logThresholdDecision logs the parsed range, value and resulting decision
+// for the given threshold of a performance data metric.
+func (p *Plugin) logThresholdDecision(pd PerformanceData, threshold string, rangeStr string, alert bool, err error) {
+	if !p.debugLogging.thresholdEvaluation {
+		return
+	}
+
+	attrs := []logAttr{
+		{Key: logAttrLabel, Value: pd.Label},
+		{Key: logAttrValue, Value: pd.Value},
+		{Key: logAttrThreshold, Value: threshold},
+		{Key: logAttrRange, Value: rangeStr},
+		{Key: logAttrAlert, Value: alert},
+	}
+
+	var msg string
+	switch {
+	case rangeStr == "":
+		msg = fmt.Sprintf(
+			"Metric %q value %q: no %s threshold set, skipping",
+			pd.Label, pd.Value, threshold,
+		)
+	case err != nil:
+		msg = fmt.Sprintf(
+			"Metric %q value %q: failed to parse %s threshold %q: %v",
+			pd.Label, pd.Value, threshold, rangeStr, err,
+		)
+	default:
+		msg = fmt.Sprintf(
+			"Metric %q value %q: %s threshold %q parsed as %s, alert: %t",
+			pd.Label, pd.Value, threshold, rangeStr,
+			describeRange(ParseRangeString(rangeStr)), alert,
+		)
+	}
+
+	p.logThresholdEvaluation(msg, attrs...)
+}
+
+// describeRange returns a human readable description of the given parsed
+// range for use in debug log messages.
+func describeRange(r *Range) string {
+	if r == nil {
+		return "invalid range"
+	}
+
+	start := "-inf"
+	if !r.StartInfinity {
+		start = strconv.FormatFloat(r.Start, 'f', -1, 64)
+	}
+
+	end := "+inf"
+	if !r.EndInfinity {
+		end = strconv.FormatFloat(r.End, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("[%s, %s] (alert %s)", start, end, strings.ToLower(r.AlertOn))
 }
 
 // evaluateThreshold is a helper function used to handle both parsing and
@@ -85,6 +85,16 @@ func TestParseRange(t *testing.T) {
 		assert.Equal(t, parsedThing.CheckRange("65"), false)
 	})
 
+	t.Run("Alert inside a range involving -inf", func(t *testing.T) {
+		parsedThing := ParseRangeString("@~:30")
+		assert.Equal(t, parsedThing.AlertOn, "INSIDE")
+		assert.Equal(t, parsedThing.StartInfinity, true)
+		assert.Equal(t, parsedThing.End, 30.0)
+		assert.Equal(t, parsedThing.CheckRange("-100"), true)
+		assert.Equal(t, parsedThing.CheckRange("30"), true)
+		assert.Equal(t, parsedThing.CheckRange("31"), false)
+	})
+
 	t.Run("If invalid range is provided (with positive infinity) parsing should return nil", func(t *testing.T) {
 		parsedThing := ParseRangeString("50:~")
 		assert.Nil(t, parsedThing)
@@ -245,3 +255,38 @@ func TestParseRange(t *testing.T) {
 		assert.Equal(t, StateUNKNOWNExitCode, plugin.ExitStatusCode)
 	})
 }
+
+// TestRangeString asserts that ranges are rendered in the threshold format
+// accepted by ParseRangeString.
+func TestRangeString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"10":        "10",
+		"0:10":      "10",
+		"10:":       "10:",
+		"~:10":      "~:10",
+		"~:":        "~:",
+		"10:20":     "10:20",
+		"-5.5:0.25": "-5.5:0.25",
+		"@10:20":    "@10:20",
+		"@~:0":      "@~:0",
+		"@0:":       "@0:",
+	}
+
+	for input, want := range tests {
+		parsed := ParseRangeString(input)
+		if parsed == nil {
+			t.Errorf("ERROR: failed to parse range %q", input)
+			continue
+		}
+
+		if got := parsed.String(); got != want {
+			t.Errorf("ERROR: want %q for range %q, got %q", want, input, got)
+		}
+
+		if reparsed := ParseRangeString(parsed.String()); reparsed == nil || *reparsed != *parsed {
+			t.Errorf("ERROR: range %q does not survive round-trip: %+v", input, reparsed)
+		}
+	}
+}
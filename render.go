@@ -0,0 +1,66 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Render returns the plugin output and exit code exactly as they would be
+// emitted by ReturnCheckResults, without writing to the plugin output target
+// or calling os.Exit (or the custom exit function). This allows long-running
+// agents, tests and wrappers to reuse the output formatting of this library.
+// See SetHostCheckMode for the exit code used for host checks.
+//
+// As with ReturnCheckResults, any state hooks are called (see OnWarning,
+// OnCritical and OnUnknown) and the plugin timeout (if armed) is disarmed.
+// Unlike ReturnCheckResults, panics in client code are not recovered and
+// emit hooks are not called.
+//
+// If the plugin timeout was already reached (and an UNKNOWN check result
+// emitted) no output is returned along with the exit code used for that
+// result and an error wrapping ErrPluginTimeoutReached.
+func (p *Plugin) Render() (string, int, error) {
+	p.logAction("Rendering plugin output without exiting as requested")
+
+	if p.finishTimeoutWatchdog() {
+		p.logAction("Plugin timeout already reached, skipping rendering")
+
+		exitCode := StateUNKNOWNExitCode
+		if p.hostCheckMode {
+			exitCode = hostStateProcessExitCode(StateDOWNExitCode)
+		}
+
+		return "", exitCode, fmt.Errorf(
+			"failed to render plugin output: %w",
+			ErrPluginTimeoutReached,
+		)
+	}
+
+	p.finalizeState()
+
+	p.runStateHooks()
+
+	p.appendDebugLogCapture()
+
+	var output strings.Builder
+
+	stream := p.newOutputStreamTo(&output)
+	defer stream.release()
+
+	phaseDone := p.startPhase("Render")
+	p.writeRenderedOutput(stream)
+	phaseDone()
+
+	if err := stream.flush(); err != nil {
+		return "", p.processExitCode(), fmt.Errorf("failed to render plugin output: %w", err)
+	}
+
+	return output.String(), p.processExitCode(), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_Render_MatchesReturnCheckResults(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		compatMode nagios.CompatibilityMode
+	}{
+		"default": {},
+		"shinken": {
+			compatMode: nagios.CompatibilityModeShinken,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			configure := func(plugin *nagios.Plugin) {
+				plugin.SetCompatibilityMode(tt.compatMode)
+				plugin.ServiceOutput = "WARNING: datastore usage high"
+				plugin.LongServiceOutput = "ds01 at 85%"
+				plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+				plugin.AddError(nagios.ErrInvalidRangeThreshold)
+
+				pd := nagios.PerformanceData{Label: "ds01_used", Value: "85", UnitOfMeasurement: "%"}
+				if err := plugin.AddPerfData(false, pd, nagios.PerformanceData{Label: "time", Value: "42", UnitOfMeasurement: "ms"}); err != nil {
+					t.Fatalf("ERROR: failed to add performance data: %v", err)
+				}
+			}
+
+			exitCode := -1
+
+			reference := nagios.NewPlugin()
+			reference.SetOutputTarget(&outputBuffer)
+			reference.SetExitFunc(func(code int) { exitCode = code })
+			configure(reference)
+			reference.ReturnCheckResults()
+
+			plugin := nagios.NewPlugin()
+			plugin.SetExitFunc(func(int) { t.Fatal("ERROR: exit function called by Render") })
+			configure(plugin)
+
+			output, gotExitCode, err := plugin.Render()
+			if err != nil {
+				t.Fatalf("ERROR: unexpected error: %v", err)
+			}
+
+			if gotExitCode != exitCode {
+				t.Errorf("ERROR: want exit code %d, got %d", exitCode, gotExitCode)
+			}
+
+			if d := cmp.Diff(outputBuffer.String(), output); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Log("OK: rendered output matches ReturnCheckResults output")
+		})
+	}
+}
+
+func TestPlugin_Render_TimeoutReached(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(func(int) {})
+	plugin.SetTimeout(10 * time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(outputBuffer.String(), "UNKNOWN: plugin timeout") {
+		if time.Now().After(deadline) {
+			t.Fatal("ERROR: plugin timeout not reached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	output, exitCode, err := plugin.Render()
+	if !errors.Is(err, nagios.ErrPluginTimeoutReached) {
+		t.Fatalf("ERROR: want error %v, got %v", nagios.ErrPluginTimeoutReached, err)
+	}
+
+	if output != "" || exitCode != nagios.StateUNKNOWNExitCode {
+		t.Fatalf("ERROR: unexpected output %q and exit code %d", output, exitCode)
+	}
+
+	t.Log("OK: rendering skipped after plugin timeout reached")
+}
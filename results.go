@@ -0,0 +1,168 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ComponentResult is the result of checking a single component (e.g., one
+// of several datastores, hosts or endpoints) as part of a plugin which checks
+// multiple targets. See Plugin.AddResult.
+type ComponentResult struct {
+	// Name identifies the checked component.
+	Name string
+
+	// State is the state of the checked component.
+	State ServiceState
+
+	// Message describes the result of checking the component.
+	Message string
+
+	// Duration is the (optional) time taken to check the component. See
+	// RunChecks.
+	Duration time.Duration
+}
+
+// String returns a human readable description of the component result.
+func (cr ComponentResult) String() string {
+	return fmt.Sprintf("%s: %s: %s", cr.State.Label, cr.Name, cr.Message)
+}
+
+// AddResult records the result of checking a single component. The plugin
+// exit state is set to the given state if it is more severe than the current
+// exit state; the exit state is never downgraded. Unsupported exit codes are
+// recorded as UNKNOWN.
+//
+// Once results are recorded:
+//
+//   - a summary of the number of components in each state (e.g., "3 OK, 1
+//     WARNING, 1 CRITICAL") is used in place of an empty ServiceOutput field
+//     (see ResultsSummary)
+//   - a per-component breakdown is rendered as a table after the
+//     LongServiceOutput content
+func (p *Plugin) AddResult(name string, exitCode int, message string) {
+	p.addResult(name, exitCode, message, 0)
+}
+
+// addResult records the result of checking a single component which took
+// the given duration (if known) to check. See AddResult.
+func (p *Plugin) addResult(name string, exitCode int, message string, duration time.Duration) {
+	exitCode = supportedExitCodeOrUnknown(exitCode)
+
+	p.componentResults = append(p.componentResults, ComponentResult{
+		Name: name,
+		State: ServiceState{
+			Label:    ExitCodeToStateLabel(exitCode),
+			ExitCode: exitCode,
+		},
+		Message:  message,
+		Duration: duration,
+	})
+
+	p.logAction(fmt.Sprintf(
+		"Recorded %s result for component %q",
+		ExitCodeToStateLabel(exitCode),
+		name,
+	))
+
+	p.EscalateState(exitCode)
+}
+
+// Results returns a copy of the component results recorded by AddResult in
+// the order they were recorded. A nil value is returned if no results were
+// recorded.
+func (p *Plugin) Results() []ComponentResult {
+	if len(p.componentResults) == 0 {
+		return nil
+	}
+
+	results := make([]ComponentResult, len(p.componentResults))
+	copy(results, p.componentResults)
+
+	return results
+}
+
+// ResultsSummary returns a one-line summary of the number of components in
+// each state (e.g., "3 OK, 1 WARNING, 1 CRITICAL") for the component results
+// recorded by AddResult. States without results are omitted. An empty string
+// is returned if no results were recorded.
+func (p *Plugin) ResultsSummary() string {
+	if len(p.componentResults) == 0 {
+		return ""
+	}
+
+	counts := make(map[int]int, len(SupportedExitCodes()))
+	for _, result := range p.componentResults {
+		counts[result.State.ExitCode]++
+	}
+
+	parts := make([]string, 0, len(counts))
+	for _, state := range SupportedServiceStates() {
+		if count := counts[state.ExitCode]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, state.Label))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// checkResultsServiceOutput generates a summary of recorded component results
+// for an empty ServiceOutput field.
+func (p *Plugin) checkResultsServiceOutput() {
+	if len(p.componentResults) == 0 || strings.TrimSpace(p.ServiceOutput) != "" {
+		return
+	}
+
+	p.logAction("Empty ServiceOutput with component results, generating summary")
+
+	p.ServiceOutput = fmt.Sprintf(
+		"%s: %s",
+		p.stateLabel(p.ExitStatusCode),
+		p.ResultsSummary(),
+	)
+}
+
+// componentResultsTable returns the per-component breakdown of recorded
+// component results as a table. Check durations are included if known for
+// any component.
+func (p Plugin) componentResultsTable() OutputTable {
+	var withDuration bool
+	for _, result := range p.componentResults {
+		if result.Duration > 0 {
+			withDuration = true
+			break
+		}
+	}
+
+	headers := []string{
+		labelText(p.labels.ComponentResultsName, defaultComponentResultsNameLabel),
+		labelText(p.labels.ComponentResultsState, defaultComponentResultsStateLabel),
+		labelText(p.labels.ComponentResultsMessage, defaultComponentResultsMessageLabel),
+	}
+	if withDuration {
+		headers = append(headers, labelText(p.labels.ComponentResultsDuration, defaultComponentResultsDurationLabel))
+	}
+
+	rows := make([][]string, 0, len(p.componentResults))
+	for _, result := range p.componentResults {
+		row := []string{result.Name, result.State.Label, result.Message}
+		if withDuration {
+			row = append(row, result.Duration.Round(time.Millisecond).String())
+		}
+
+		rows = append(rows, row)
+	}
+
+	return OutputTable{
+		Headers: headers,
+		Rows:    rows,
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_AddResult_RollsUpWorstState(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	plugin.AddResult("ds01", nagios.StateOKExitCode, "42% used")
+	plugin.AddResult("ds02", nagios.StateCRITICALExitCode, "95% used")
+	plugin.AddResult("ds03", nagios.StateWARNINGExitCode, "85% used")
+	plugin.AddResult("ds04", nagios.StateOKExitCode, "12% used")
+	plugin.AddResult("ds05", 42, "invalid state")
+
+	if plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, plugin.ExitStatusCode)
+	}
+
+	want := "2 OK, 1 WARNING, 1 CRITICAL, 1 UNKNOWN"
+	if got := plugin.ResultsSummary(); got != want {
+		t.Errorf("ERROR: want summary %q, got %q", want, got)
+	}
+
+	results := plugin.Results()
+	if len(results) != 5 {
+		t.Fatalf("ERROR: want 5 results, got %d", len(results))
+	}
+
+	wantResult := nagios.ComponentResult{
+		Name:    "ds05",
+		State:   nagios.ServiceState{Label: nagios.StateUNKNOWNLabel, ExitCode: nagios.StateUNKNOWNExitCode},
+		Message: "invalid state",
+	}
+	if d := cmp.Diff(wantResult, results[4]); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: component results rolled up as expected")
+}
+
+func TestPlugin_AddResult_NeverDowngradesState(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+
+	plugin.AddResult("ds01", nagios.StateOKExitCode, "42% used")
+
+	if plugin.ExitStatusCode != nagios.StateWARNINGExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, plugin.ExitStatusCode)
+	}
+
+	t.Log("OK: plugin exit state not downgraded")
+}
+
+func TestPlugin_Results_NoResults(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	if got := plugin.Results(); got != nil {
+		t.Errorf("ERROR: want no results, got %v", got)
+	}
+
+	if got := plugin.ResultsSummary(); got != "" {
+		t.Errorf("ERROR: want empty summary, got %q", got)
+	}
+
+	t.Log("OK: no component results recorded as expected")
+}
+
+func TestPlugin_AddResult_RenderedInOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		serviceOutput string
+		want          []string
+	}{
+		"generated summary": {
+			want: []string{
+				"WARNING: 1 OK, 1 WARNING" + nagios.CheckOutputEOL,
+				"Component  State    Message",
+				"ds01       OK       42% used",
+				"ds02       WARNING  85% used",
+			},
+		},
+		"client summary": {
+			serviceOutput: "WARNING: datastore usage high",
+			want: []string{
+				"WARNING: datastore usage high" + nagios.CheckOutputEOL,
+				"ds02       WARNING  85% used",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.ServiceOutput = tt.serviceOutput
+
+			plugin.AddResult("ds01", nagios.StateOKExitCode, "42% used")
+			plugin.AddResult("ds02", nagios.StateWARNINGExitCode, "85% used")
+
+			plugin.ReturnCheckResults()
+
+			got := outputBuffer.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("ERROR: want %q in output, got:\n%q", want, got)
+				}
+			}
+
+			t.Log("OK: component results rendered as expected")
+		})
+	}
+}
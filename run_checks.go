@@ -0,0 +1,141 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SubCheckFunc represents the logic used to check a single component (e.g.,
+// one of several hosts or endpoints) as part of a plugin which checks
+// multiple targets. The returned exit code and message describe the result
+// of the check. A returned error is recorded in the errors collection; if
+// the returned exit code is OK the component result is recorded as UNKNOWN.
+type SubCheckFunc func(ctx context.Context) (exitCode int, message string, err error)
+
+// SubCheck is a named component check executed by RunChecks.
+type SubCheck struct {
+	// Name identifies the checked component.
+	Name string
+
+	// Check is the logic used to check the component.
+	Check SubCheckFunc
+}
+
+// subCheckOutcome is the outcome of executing a single SubCheck.
+type subCheckOutcome struct {
+	exitCode int
+	message  string
+	err      error
+	duration time.Duration
+}
+
+// RunChecks executes the given component checks in parallel using at most
+// the given number of concurrent workers and records the result of each
+// check (see AddResult) along with the time taken. Results are recorded in
+// the order the checks are given once all checks have finished. A
+// non-positive concurrency executes all checks at once.
+//
+// Errors returned by checks are recorded in the errors collection prefixed
+// with the component name. Panics in checks are recorded as errors (wrapping
+// ErrPanicDetected) with an UNKNOWN component result. Checks not yet started
+// when the given context is done are not executed and are recorded as
+// UNKNOWN.
+//
+// RunChecks must not be called concurrently with other methods of the
+// plugin; checks should not modify the plugin.
+func (p *Plugin) RunChecks(ctx context.Context, concurrency int, checks []SubCheck) {
+	if len(checks) == 0 {
+		return
+	}
+
+	if concurrency <= 0 || concurrency > len(checks) {
+		concurrency = len(checks)
+	}
+
+	p.logAction(fmt.Sprintf(
+		"Running %d checks using %d workers",
+		len(checks),
+		concurrency,
+	))
+
+	outcomes := make([]subCheckOutcome, len(checks))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range indexes {
+				outcomes[idx] = runSubCheck(ctx, checks[idx])
+			}
+		}()
+	}
+
+	for i := range checks {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	for i, outcome := range outcomes {
+		name := checks[i].Name
+
+		exitCode := outcome.exitCode
+		message := outcome.message
+
+		if outcome.err != nil {
+			p.AddError(fmt.Errorf("%s: %w", name, outcome.err))
+
+			if exitCode == StateOKExitCode {
+				exitCode = StateUNKNOWNExitCode
+			}
+
+			if message == "" {
+				message = outcome.err.Error()
+			}
+		}
+
+		p.addResult(name, exitCode, message, outcome.duration)
+	}
+}
+
+// runSubCheck executes the given component check unless the given context
+// is already done, converting any panic into an error.
+func runSubCheck(ctx context.Context, check SubCheck) (outcome subCheckOutcome) {
+	if err := ctx.Err(); err != nil {
+		return subCheckOutcome{
+			exitCode: StateUNKNOWNExitCode,
+			message:  "check not started",
+			err:      err,
+		}
+	}
+
+	start := time.Now()
+
+	defer func() {
+		outcome.duration = time.Since(start)
+
+		if err := recover(); err != nil {
+			outcome.exitCode = StateUNKNOWNExitCode
+			outcome.message = "check crashed"
+			outcome.err = fmt.Errorf("%w: %s", ErrPanicDetected, err)
+		}
+	}()
+
+	outcome.exitCode, outcome.message, outcome.err = check.Check(ctx)
+
+	return outcome
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_RunChecks_AggregatesResults(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning int32
+
+	track := func(exitCode int, message string, err error) nagios.SubCheckFunc {
+		return func(_ context.Context) (int, string, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+
+			return exitCode, message, err
+		}
+	}
+
+	checks := []nagios.SubCheck{
+		{Name: "web01", Check: track(nagios.StateOKExitCode, "responded in 12ms", nil)},
+		{Name: "web02", Check: track(nagios.StateWARNINGExitCode, "responded in 900ms", nil)},
+		{Name: "web03", Check: track(nagios.StateOKExitCode, "", errors.New("connection refused"))},
+		{Name: "web04", Check: func(context.Context) (int, string, error) { panic("boom") }},
+		{Name: "web05", Check: track(nagios.StateOKExitCode, "responded in 15ms", nil)},
+	}
+
+	plugin := nagios.NewPlugin()
+	plugin.RunChecks(context.Background(), 2, checks)
+
+	if got := atomic.LoadInt32(&maxRunning); got > 2 {
+		t.Errorf("ERROR: want at most 2 concurrent checks, got %d", got)
+	}
+
+	if plugin.ExitStatusCode != nagios.StateWARNINGExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, plugin.ExitStatusCode)
+	}
+
+	results := plugin.Results()
+	if len(results) != len(checks) {
+		t.Fatalf("ERROR: want %d results, got %d", len(checks), len(results))
+	}
+
+	for i, result := range results {
+		if result.Name != checks[i].Name {
+			t.Errorf("ERROR: want result %d for %q, got %q", i, checks[i].Name, result.Name)
+		}
+	}
+
+	if results[2].State.ExitCode != nagios.StateUNKNOWNExitCode || results[2].Message != "connection refused" {
+		t.Errorf("ERROR: unexpected result for failed check: %+v", results[2])
+	}
+
+	if results[3].State.ExitCode != nagios.StateUNKNOWNExitCode || results[3].Message != "check crashed" {
+		t.Errorf("ERROR: unexpected result for crashed check: %+v", results[3])
+	}
+
+	if results[1].Duration < 20*time.Millisecond {
+		t.Errorf("ERROR: want duration of at least 20ms, got %s", results[1].Duration)
+	}
+
+	if len(plugin.Errors) != 2 {
+		t.Fatalf("ERROR: want 2 errors, got %d: %v", len(plugin.Errors), plugin.Errors)
+	}
+
+	if !strings.HasPrefix(plugin.Errors[0].Error(), "web03: ") {
+		t.Errorf("ERROR: want error prefixed with component name, got %q", plugin.Errors[0])
+	}
+
+	if !errors.Is(plugin.Errors[1], nagios.ErrPanicDetected) {
+		t.Errorf("ERROR: want error %v, got %v", nagios.ErrPanicDetected, plugin.Errors[1])
+	}
+
+	t.Log("OK: check results aggregated as expected")
+}
+
+func TestPlugin_RunChecks_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var called int32
+
+	check := func(context.Context) (int, string, error) {
+		atomic.AddInt32(&called, 1)
+
+		return nagios.StateOKExitCode, "ok", nil
+	}
+
+	plugin := nagios.NewPlugin()
+	plugin.RunChecks(ctx, 0, []nagios.SubCheck{
+		{Name: "db01", Check: check},
+		{Name: "db02", Check: check},
+	})
+
+	if got := atomic.LoadInt32(&called); got != 0 {
+		t.Errorf("ERROR: want no checks executed, got %d", got)
+	}
+
+	for _, result := range plugin.Results() {
+		if result.State.ExitCode != nagios.StateUNKNOWNExitCode || result.Message != "check not started" {
+			t.Errorf("ERROR: unexpected result: %+v", result)
+		}
+	}
+
+	t.Log("OK: checks not started after context done")
+}
+
+func TestPlugin_RunChecks_RendersDurations(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+
+	plugin.RunChecks(context.Background(), 1, []nagios.SubCheck{
+		{
+			Name: "db01",
+			Check: func(context.Context) (int, string, error) {
+				time.Sleep(5 * time.Millisecond)
+
+				return nagios.StateOKExitCode, "replication healthy", nil
+			},
+		},
+	})
+
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+	for _, want := range []string{"OK: 1 OK", "Component  State  Message              Duration", "db01       OK     replication healthy  "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: want %q in output, got:\n%q", want, got)
+		}
+	}
+
+	t.Log("OK: check durations rendered as expected")
+}
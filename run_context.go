@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RunWithContext executes the given check logic bounded by the given context
+// and the plugin timeout (if set; see SetTimeout and EnableSchedulerTimeout)
+// and then returns the check results via ReturnCheckResults. Client code
+// using this method should not also call ReturnCheckResults.
+//
+// The check logic is given a context which is done when either the given
+// context is done or the plugin timeout is reached. If this happens before
+// the check logic returns, an UNKNOWN check result noting that the plugin
+// timed out is emitted and the plugin exits without waiting on the check
+// logic. Because the check logic may still be running at that point, the
+// plugin state (e.g., ServiceOutput, performance data) is not included in
+// the check result.
+//
+// If the check logic returns an error, the error is recorded (see AddError).
+// If the plugin state is still OK after recording the error, the plugin
+// state is set to UNKNOWN and ServiceOutput is replaced with a summary of the
+// error. Panics in the check logic are reported in the same way as with
+// ReturnCheckResults.
+func (p *Plugin) RunWithContext(ctx context.Context, check func(ctx context.Context) error) {
+	// Track whether check results have been returned even if no plugin
+	// timeout is set so that a deferred ReturnCheckResults call in client
+	// code does not emit output after the context is done.
+	if p.timeout == nil {
+		p.timeout = &timeoutWatchdog{}
+	}
+	watchdog := p.timeout
+
+	checkCtx, cancel := p.TimeoutContext(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				p.logActionLevel(DebugLogLevelError, "Handling panic")
+				p.handlePanic(err)
+				done <- nil
+			}
+		}()
+
+		done <- check(checkCtx)
+	}()
+
+	select {
+	case err := <-done:
+		// Errors caused by the check context being done are reported as a
+		// plugin timeout.
+		if err == nil || checkCtx.Err() == nil || !errors.Is(err, checkCtx.Err()) {
+			if err != nil {
+				p.handleRunError(err)
+			}
+
+			p.ReturnCheckResults()
+
+			return
+		}
+
+	case <-checkCtx.Done():
+	}
+
+	p.logActionLevel(DebugLogLevelWarn, "Check context done before check completed")
+	p.handleTimeout(watchdog, p.runContextDoneSummary(checkCtx.Err()))
+}
+
+// handleRunError records the given error returned by check logic executed
+// via RunWithContext and sets the plugin state to UNKNOWN if the recorded
+// error does not already indicate a non-OK state.
+func (p *Plugin) handleRunError(err error) {
+	p.AddError(err)
+
+	if p.ExitStatusCode != StateOKExitCode {
+		return
+	}
+
+	p.ExitStatusCode = p.exitState(StateUNKNOWNExitCode)
+	p.ServiceOutput = fmt.Sprintf("%s: check failed: %v", p.stateLabel(p.ExitStatusCode), err)
+}
+
+// runContextDoneSummary returns the summary used in place of ServiceOutput
+// for the given context error if the check context is done before check
+// logic executed via RunWithContext returns.
+func (p *Plugin) runContextDoneSummary(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return labelText(p.labels.CheckCancelled, defaultCheckCancelledSummary)
+	default:
+		return fmt.Sprintf(labelText(p.labels.CheckTimedOut, defaultCheckTimedOutFormat), err)
+	}
+}
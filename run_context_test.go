@@ -0,0 +1,149 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_RunWithContext(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		timeout      time.Duration
+		check        func(ctx context.Context, plugin *nagios.Plugin) error
+		wantExitCode int
+		wantOutput   string
+	}{
+		"check completes": {
+			check: func(_ context.Context, plugin *nagios.Plugin) error {
+				plugin.ServiceOutput = "OK: all good"
+
+				return nil
+			},
+			wantExitCode: nagios.StateOKExitCode,
+			wantOutput:   "OK: all good",
+		},
+		"check returns error": {
+			check: func(_ context.Context, plugin *nagios.Plugin) error {
+				plugin.ServiceOutput = "OK: all good"
+
+				return errors.New("connection refused")
+			},
+			wantExitCode: nagios.StateUNKNOWNExitCode,
+			wantOutput:   "UNKNOWN: check failed: connection refused",
+		},
+		"check returns error after setting state": {
+			check: func(_ context.Context, plugin *nagios.Plugin) error {
+				plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+				plugin.ServiceOutput = "CRITICAL: service down"
+
+				return errors.New("connection refused")
+			},
+			wantExitCode: nagios.StateCRITICALExitCode,
+			wantOutput:   "CRITICAL: service down",
+		},
+		"context deadline reached": {
+			timeout: 50 * time.Millisecond,
+			check: func(ctx context.Context, _ *nagios.Plugin) error {
+				<-ctx.Done()
+
+				return ctx.Err()
+			},
+			wantExitCode: nagios.StateUNKNOWNExitCode,
+			wantOutput:   "UNKNOWN: plugin timed out before check completed (context deadline exceeded)",
+		},
+		"check panics": {
+			check: func(_ context.Context, _ *nagios.Plugin) error {
+				panic("boom")
+			},
+			wantExitCode: nagios.StateCRITICALExitCode,
+			wantOutput:   "CRITICAL: plugin crash detected",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer syncBuffer
+
+			exitCode := -1
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SetExitFunc(func(code int) { exitCode = code })
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			plugin.RunWithContext(ctx, func(ctx context.Context) error {
+				return tt.check(ctx, plugin)
+			})
+
+			got := outputBuffer.String()
+
+			if exitCode != tt.wantExitCode {
+				t.Errorf("ERROR: want exit code %d, got %d", tt.wantExitCode, exitCode)
+			}
+
+			if !strings.HasPrefix(got, tt.wantOutput) {
+				t.Fatalf("ERROR: want output beginning with %q, got:\n%q", tt.wantOutput, got)
+			}
+
+			t.Log("OK: check results returned as expected")
+		})
+	}
+}
+
+func TestPlugin_RunWithContext_DeferredReturnCheckResultsSkipped(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(func(int) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	plugin.RunWithContext(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	// Simulate a deferred ReturnCheckResults call in client code.
+	plugin.ServiceOutput = "OK: too late"
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+
+	if !strings.HasPrefix(got, "UNKNOWN: plugin check cancelled before check completed") {
+		t.Fatalf("ERROR: unexpected output:\n%q", got)
+	}
+
+	if strings.Contains(got, "too late") {
+		t.Fatalf("ERROR: check results emitted after context done:\n%q", got)
+	}
+
+	t.Log("OK: check results not emitted after context done")
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultRunnerInterval is the interval used between check executions when
+// running in daemon mode if not overridden by client code.
+const defaultRunnerInterval time.Duration = time.Minute
+
+// CheckFunc represents the service or host check logic provided by client
+// code. The given Plugin value is prepared by the caller and is used to
+// record check results (ServiceOutput, ExitStatusCode, performance data,
+// errors, etc.) in the same way as with a traditional one-shot plugin.
+//
+// CheckFunc implementations should not call ReturnCheckResults; the caller is
+// responsible for processing the final plugin state.
+type CheckFunc func(ctx context.Context, plugin *Plugin)
+
+// Runner executes client check logic either once as an actively scheduled
+// plugin or repeatedly on an interval ("daemon mode") with each result
+// submitted passively to a monitoring system.
+//
+// Daemon mode is intended for high-frequency checks where the cost of
+// process creation for each check execution is too expensive.
+type Runner struct {
+	// check is the client check logic executed by the runner.
+	check CheckFunc
+
+	// interval is the time between check executions in daemon mode.
+	interval time.Duration
+
+	// hostName is the host associated with passive check results.
+	hostName string
+
+	// serviceDescription is the service associated with passive check
+	// results. If empty, results are submitted as host check results.
+	serviceDescription string
+
+	// submitter is used to submit passive check results in daemon mode.
+	submitter PassiveSubmitter
+
+	// submitErrorHandler is called for each failed passive check result
+	// submission. If not set, errors are written to the default abort
+	// message output target.
+	submitErrorHandler func(error)
+
+	// pluginSetup is an optional function called to configure each new
+	// Plugin value before the check is executed.
+	pluginSetup func(*Plugin)
+
+	// healthServer is optionally updated with each check result in daemon
+	// mode.
+	healthServer *HealthServer
+}
+
+// NewRunner constructs a new Runner for the given check logic. The default
+// daemon mode interval is one minute.
+func NewRunner(check CheckFunc) *Runner {
+	return &Runner{
+		check:    check,
+		interval: defaultRunnerInterval,
+	}
+}
+
+// SetInterval overrides the default time between check executions in daemon
+// mode. Non-positive values are ignored.
+func (r *Runner) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	r.interval = interval
+}
+
+// SetPassiveTarget specifies the host, service and submitter used for
+// passive check result submission in daemon mode. If service is empty,
+// results are submitted as host check results.
+func (r *Runner) SetPassiveTarget(host string, service string, submitter PassiveSubmitter) {
+	r.hostName = host
+	r.serviceDescription = service
+	r.submitter = submitter
+}
+
+// SetSubmitErrorHandler specifies a function called for each failed passive
+// check result submission in daemon mode. By default these errors are
+// written to os.Stderr and execution continues with the next interval.
+func (r *Runner) SetSubmitErrorHandler(fn func(error)) {
+	r.submitErrorHandler = fn
+}
+
+// SetPluginSetup specifies a function used to configure each new Plugin value
+// (e.g., custom section labels, debug logging) before the check logic is
+// executed.
+func (r *Runner) SetPluginSetup(fn func(*Plugin)) {
+	r.pluginSetup = fn
+}
+
+// SetHealthServer specifies a HealthServer updated with each check result
+// in daemon mode. Client code is responsible for serving the HealthServer
+// (e.g., via HealthServer.ListenAndServe).
+func (r *Runner) SetHealthServer(h *HealthServer) {
+	r.healthServer = h
+}
+
+// RunActive executes the check logic once and processes the results as an
+// actively scheduled plugin; output is emitted and the process exits with
+// the final plugin exit code.
+func (r *Runner) RunActive(ctx context.Context) {
+	plugin := r.newPlugin()
+	defer plugin.ReturnCheckResults()
+
+	r.check(ctx, plugin)
+}
+
+// RunDaemon executes the check logic immediately and then once per interval
+// until the given context is cancelled, submitting each result passively. An
+// error is returned if no passive submitter has been specified, otherwise
+// the context error is returned once the context is done.
+//
+// Failed passive submissions do not stop the runner; see
+// SetSubmitErrorHandler.
+func (r *Runner) RunDaemon(ctx context.Context) error {
+	if r.submitter == nil {
+		return fmt.Errorf(
+			"failed to start daemon mode: %w",
+			ErrPassiveSubmitterMissing,
+		)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.runPassive(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPassive executes the check logic once and submits the result using the
+// configured passive submitter.
+func (r *Runner) runPassive(ctx context.Context) {
+	plugin := r.newPlugin()
+	plugin.SkipOSExit()
+
+	// Check results are submitted passively; the plugin timeout (if any)
+	// does not apply.
+	plugin.SetTimeout(0)
+
+	r.runCheck(ctx, plugin)
+
+	result := plugin.PassiveCheckResult(r.hostName, r.serviceDescription)
+
+	if r.healthServer != nil {
+		r.healthServer.Update(plugin.Snapshot())
+	}
+
+	if err := r.submitter.Submit(ctx, result); err != nil {
+		r.handleSubmitError(err)
+	}
+}
+
+// runCheck executes the check logic, converting any panic from client code
+// into a CRITICAL check result in the same way as ReturnCheckResults.
+func (r *Runner) runCheck(ctx context.Context, plugin *Plugin) {
+	defer func() {
+		if err := recover(); err != nil {
+			plugin.logActionLevel(DebugLogLevelError, "Handling panic")
+			plugin.handlePanic(err)
+		}
+	}()
+
+	r.check(ctx, plugin)
+}
+
+// newPlugin constructs and (optionally) configures a Plugin value for a
+// single check execution.
+func (r *Runner) newPlugin() *Plugin {
+	plugin := NewPlugin()
+
+	if r.pluginSetup != nil {
+		r.pluginSetup(plugin)
+	}
+
+	return plugin
+}
+
+// handleSubmitError passes the given submission error to the user-specified
+// handler or writes it to the default abort message output target.
+func (r *Runner) handleSubmitError(err error) {
+	if r.submitErrorHandler != nil {
+		r.submitErrorHandler(err)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(
+		defaultPluginAbortMessageOutputTarget(),
+		"Failed to submit passive check result: %s\n",
+		err.Error(),
+	)
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// recordingSubmitter is a PassiveSubmitter which records all submitted check
+// results.
+type recordingSubmitter struct {
+	mu      sync.Mutex
+	results []nagios.PassiveCheckResult
+	notify  chan struct{}
+}
+
+func (rs *recordingSubmitter) Submit(_ context.Context, results ...nagios.PassiveCheckResult) error {
+	rs.mu.Lock()
+	rs.results = append(rs.results, results...)
+	rs.mu.Unlock()
+
+	// Avoid blocking the runner if the test is no longer listening.
+	select {
+	case rs.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func TestRunner_RunDaemon_FailsWithoutSubmitter(t *testing.T) {
+	t.Parallel()
+
+	runner := nagios.NewRunner(func(_ context.Context, _ *nagios.Plugin) {})
+
+	err := runner.RunDaemon(context.Background())
+	if !errors.Is(err, nagios.ErrPassiveSubmitterMissing) {
+		t.Fatalf("ERROR: want %v, got %v", nagios.ErrPassiveSubmitterMissing, err)
+	}
+
+	t.Log("OK: daemon mode refused to start without a passive submitter")
+}
+
+func TestRunner_RunDaemon_SubmitsResultsOnInterval(t *testing.T) {
+	t.Parallel()
+
+	const wantSubmissions = 3
+
+	submitter := &recordingSubmitter{notify: make(chan struct{}, wantSubmissions)}
+
+	runner := nagios.NewRunner(func(_ context.Context, plugin *nagios.Plugin) {
+		plugin.ServiceOutput = "WARNING: widget count high"
+		plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	})
+	runner.SetInterval(time.Millisecond)
+	runner.SetPassiveTarget("web01", "widgets", submitter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.RunDaemon(ctx)
+	}()
+
+	for i := 0; i < wantSubmissions; i++ {
+		select {
+		case <-submitter.notify:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("ERROR: timed out waiting for submission %d", i+1)
+		}
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("ERROR: want %v, got %v", context.Canceled, err)
+	}
+
+	submitter.mu.Lock()
+	defer submitter.mu.Unlock()
+
+	for _, result := range submitter.results[:wantSubmissions] {
+		switch {
+		case result.HostName != "web01" || result.ServiceDescription != "widgets":
+			t.Errorf("ERROR: unexpected host/service %q/%q", result.HostName, result.ServiceDescription)
+		case result.ExitStatusCode != nagios.StateWARNINGExitCode:
+			t.Errorf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, result.ExitStatusCode)
+		case !strings.HasPrefix(result.Output, "WARNING: widget count high"):
+			t.Errorf("ERROR: unexpected output %q", result.Output)
+		case result.IsHostCheckResult():
+			t.Error("ERROR: service check result reported as host check result")
+		}
+	}
+
+	t.Logf("OK: %d passive results submitted as expected", wantSubmissions)
+}
+
+func TestRunner_RunDaemon_ReportsPanicAsCritical(t *testing.T) {
+	t.Parallel()
+
+	submitter := &recordingSubmitter{notify: make(chan struct{}, 1)}
+
+	runner := nagios.NewRunner(func(_ context.Context, _ *nagios.Plugin) {
+		panic("boom")
+	})
+	runner.SetInterval(time.Hour)
+	runner.SetPassiveTarget("web01", "", submitter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = runner.RunDaemon(ctx)
+	}()
+
+	select {
+	case <-submitter.notify:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ERROR: timed out waiting for submission")
+	}
+
+	submitter.mu.Lock()
+	defer submitter.mu.Unlock()
+
+	// The CRITICAL plugin state is submitted as the DOWN host state.
+	result := submitter.results[0]
+	if result.ExitStatusCode != nagios.StateDOWNExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateDOWNExitCode, result.ExitStatusCode)
+	}
+
+	if !result.IsHostCheckResult() {
+		t.Error("ERROR: host check result reported as service check result")
+	}
+
+	if !strings.Contains(result.Output, nagios.ErrPanicDetected.Error()) {
+		t.Errorf("ERROR: panic details missing from output %q", result.Output)
+	}
+
+	t.Log("OK: panic in check logic reported as CRITICAL passive result")
+}
@@ -9,15 +9,15 @@ package nagios
 
 import (
 	"fmt"
+	"hash/crc32"
 	"io"
-	"sort"
 	"strings"
 )
 
 // handleServiceOutputSection is a wrapper around the logic used to process
 // the Service Output or "one-line summary" content.
 func (p Plugin) handleServiceOutputSection(w io.Writer) {
-	if p.LongServiceOutput == "" {
+	if !p.hasLongServiceOutput() {
 		// If Long Service Output was not specified, explicitly trim any
 		// formatted trailing spacing so that performance data output will be
 		// emitted immediately following the Service Output on the same line.
@@ -25,7 +25,7 @@ func (p Plugin) handleServiceOutputSection(w io.Writer) {
 		// NOTE: We explicitly include a space character in the cut set just
 		// on the off chance that a future update to the CheckOutputEOL
 		// constant removes the explicitly leading whitespace character.
-		cutSet := fmt.Sprintf(" \t%s", CheckOutputEOL)
+		const cutSet = " \t" + CheckOutputEOL
 		p.ServiceOutput = strings.TrimRight(p.ServiceOutput, cutSet)
 	}
 
@@ -33,44 +33,50 @@ func (p Plugin) handleServiceOutputSection(w io.Writer) {
 	// formatting changes to this content, simply emit it as-is. This helps
 	// avoid potential issues with literal characters being interpreted as
 	// formatting verbs.
-	written, err := fmt.Fprint(w, p.ServiceOutput)
+	written, err := io.WriteString(w, p.ServiceOutput)
 	if err != nil {
 		// Very unlikely to occur, but we should still account for it.
 		panic("Failed to write ServiceOutput to given output sink")
 	}
 
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin ServiceOutput content written to given output sink", written))
+	p.logSectionOutputSize("ServiceOutput", "%d bytes plugin ServiceOutput content written to given output sink", written)
 }
 
 // handleErrorsSection is a wrapper around the logic used to handle/process
-// the Errors section header and listing.
+// the Errors section header and listing. The caller is responsible for
+// skipping this section if hidden (see errorsSectionSkipReason).
 func (p Plugin) handleErrorsSection(w io.Writer) {
-	if p.isErrorsHidden() {
-		p.logAction("Skipping processing of errors section; option to hide errors enabled")
-
-		return
-	}
-
-	// If one or more errors were recorded and client code has not opted to
-	// hide the section ...
-
 	var totalWritten int
 
-	writeErrorToOutputSink := func(err error, fieldname string) {
-		written, writeErr := fmt.Fprintf(w, "* %v%s", err, CheckOutputEOL)
+	writeErrorToOutputSink := func(err error, count int, fieldname string) {
+		var repeated string
+		if count > 1 {
+			repeated = fmt.Sprintf(labelText(p.labels.RepeatedError, defaultRepeatedErrorFormat), count)
+		}
+
+		written, writeErr := writeStrings(w, "* ", err.Error(), repeated, CheckOutputEOL)
 		if writeErr != nil {
 			msg := fmt.Sprintf("Failed to write error field %q value to given output sink", fieldname)
 			panic(msg)
 		}
 
 		totalWritten += written
+
+		if sce := asServiceCheckError(err); sce != nil && sce.Hint != "" {
+			written, writeErr := writeStrings(w, "  ", p.getSuggestedActionLabelText(), sce.Hint, CheckOutputEOL)
+			if writeErr != nil {
+				msg := fmt.Sprintf("Failed to write error field %q suggested action to given output sink", fieldname)
+				panic(msg)
+			}
+
+			totalWritten += written
+		}
 	}
 
-	written, writeErr := fmt.Fprintf(w,
-		"%s%s**%s**%s%s",
+	written, writeErr := writeStrings(w,
 		CheckOutputEOL,
 		CheckOutputEOL,
-		p.getErrorsLabelText(),
+		"**", p.getErrorsLabelText(), "**",
 		CheckOutputEOL,
 		CheckOutputEOL,
 	)
@@ -79,46 +85,26 @@ func (p Plugin) handleErrorsSection(w io.Writer) {
 	}
 	totalWritten += written
 
-	if p.LastError != nil {
-		p.logAction("Writing field p.LastError value to output sink")
-
-		writeErrorToOutputSink(p.LastError, "p.LastError")
-	}
-
-	// Process any non-nil errors in the collection.
+	// Process any non-nil errors in the collection, grouped by severity.
 	p.logAction(fmt.Sprintf("Writing %d errors from field %q to output sink", len(p.Errors), "p.Errors"))
-	for _, err := range p.Errors {
-		if err != nil {
-			writeErrorToOutputSink(err, "p.Errors")
-		}
+	errs, counts := p.errorsWithCounts(errorsBySeverity(p.Errors))
+	for i, err := range errs {
+		writeErrorToOutputSink(err, counts[i], "p.Errors")
 	}
 
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes total plugin errors content written to given output sink", totalWritten))
+	p.logSectionOutputSize("Errors", "%d bytes total plugin errors content written to given output sink", totalWritten)
 }
 
 // handleThresholdsSection is a wrapper around the logic used to
-// handle/process the Thresholds section header and listing.
+// handle/process the Thresholds section header and listing. The caller is
+// responsible for skipping this section if hidden (see
+// thresholdsSectionSkipReason).
 func (p Plugin) handleThresholdsSection(w io.Writer) {
-	switch {
-	case p.LongServiceOutput == "":
-		p.logAction("Skipping emission of thresholds section; LongServiceOutput is empty")
-
-		return
-
-	case p.isThresholdsSectionHidden():
-		p.logAction("Skipping emission of thresholds section; option to hide thresholds enabled")
-
-		return
-	}
-
-	// If one or more threshold values were recorded and client code has
-	// not opted to hide the section ...
-
 	var totalWritten int
 
-	written, err := fmt.Fprintf(w, "%s**%s**%s%s",
+	written, err := writeStrings(w,
 		CheckOutputEOL,
-		p.getThresholdsLabelText(),
+		"**", p.getThresholdsLabelText(), "**",
 		CheckOutputEOL,
 		CheckOutputEOL,
 	)
@@ -129,11 +115,7 @@ func (p Plugin) handleThresholdsSection(w io.Writer) {
 	totalWritten += written
 
 	if p.CriticalThreshold != "" {
-		written, err := fmt.Fprintf(w, "* %s: %v%s",
-			StateCRITICALLabel,
-			p.CriticalThreshold,
-			CheckOutputEOL,
-		)
+		written, err := writeStrings(w, "* ", StateCRITICALLabel, ": ", p.CriticalThreshold, CheckOutputEOL)
 		if err != nil {
 			panic("Failed to write thresholds section label to given output sink")
 		}
@@ -142,14 +124,7 @@ func (p Plugin) handleThresholdsSection(w io.Writer) {
 	}
 
 	if p.WarningThreshold != "" {
-		warningThresholdText := fmt.Sprintf(
-			"* %s: %v%s",
-			StateWARNINGLabel,
-			p.WarningThreshold,
-			CheckOutputEOL,
-		)
-
-		written, err := fmt.Fprint(w, warningThresholdText)
+		written, err := writeStrings(w, "* ", StateWARNINGLabel, ": ", p.WarningThreshold, CheckOutputEOL)
 		if err != nil {
 			panic("Failed to write thresholds section label to given output sink")
 		}
@@ -157,15 +132,23 @@ func (p Plugin) handleThresholdsSection(w io.Writer) {
 		totalWritten += written
 	}
 
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin thresholds section content written to given output sink", totalWritten))
+	p.logSectionOutputSize("Thresholds", "%d bytes plugin thresholds section content written to given output sink", totalWritten)
 }
 
 // handleLongServiceOutput is a wrapper around the logic used to
 // handle/process the LongServiceOutput content.
 func (p Plugin) handleLongServiceOutput(w io.Writer) {
 
+	if p.isLongServiceOutputHiddenForState() {
+		p.logAction("Skipping processing of LongServiceOutput; option to hide LongServiceOutput for OK state enabled")
+
+		return
+	}
+
+	tables := p.longServiceOutputTables(p.outputProfile)
+
 	// Early exit if there is no content to emit.
-	if p.LongServiceOutput == "" {
+	if !p.hasLongServiceOutput() && tables == "" {
 		p.logAction("Skipping processing of LongServiceOutput; LongServiceOutput is empty")
 
 		return
@@ -173,22 +156,24 @@ func (p Plugin) handleLongServiceOutput(w io.Writer) {
 
 	var totalWritten int
 
-	// Hide section header/label if threshold and error values were not
-	// specified by client code, if client code opted to explicitly hide
-	// threshold or error sections or if no encoded payload content was
-	// provided; there is no need to use a header to separate the
-	// LongServiceOutput from those sections if they are not displayed (or
-	// provided in the case of an encoded payload).
+	// Hide section header/label if none of the Thresholds, Errors, Threshold
+	// Violations, Encoded Payload or Metrics Summary sections are displayed
+	// (e.g., because client code did not provide content for them or opted
+	// to hide them).
+	//
+	// There is no need to use a header to separate the LongServiceOutput
+	// from those sections if they are not displayed.
 	//
 	// If we hide the section header, we still provide some padding to
 	// prevent the LongServiceOutput from running up against the
 	// ServiceOutput content.
 	switch {
-	case !p.isThresholdsSectionHidden() || !p.isErrorsHidden() || !p.isPayloadSectionHidden():
-		written, err := fmt.Fprintf(w,
-			"%s**%s**%s",
+	case !p.isThresholdsSectionHidden() || !p.isErrorsHidden() ||
+		!p.isThresholdViolationsHidden() || !p.isPayloadSectionHidden() ||
+		!p.isMetricsSummaryHidden():
+		written, err := writeStrings(w,
 			CheckOutputEOL,
-			p.getDetailedInfoLabelText(),
+			"**", p.getDetailedInfoLabelText(), "**",
 			CheckOutputEOL,
 		)
 		if err != nil {
@@ -198,7 +183,7 @@ func (p Plugin) handleLongServiceOutput(w io.Writer) {
 		totalWritten += written
 
 	default:
-		written, err := fmt.Fprint(w, CheckOutputEOL)
+		written, err := io.WriteString(w, CheckOutputEOL)
 		if err != nil {
 			panic("Failed to write LongServiceOutput section label spacer to given output sink")
 		}
@@ -208,39 +193,143 @@ func (p Plugin) handleLongServiceOutput(w io.Writer) {
 
 	// Note: fmt.Println() (and fmt.Fprintln()) has the same issue as `\n`:
 	// Nagios seems to interpret them literally instead of emitting an actual
-	// newline. We work around that by using fmt.Fprintf() for output that is
-	// intended for display within the Nagios web UI.
-	written, err := fmt.Fprintf(w,
-		"%s%v%s",
-		CheckOutputEOL,
-		p.LongServiceOutput,
-		CheckOutputEOL,
-	)
+	// newline. We work around that by explicitly writing CheckOutputEOL for
+	// output that is intended for display within the Nagios web UI. The
+	// content is written as-is to avoid copying (potentially large) content
+	// into intermediate formatting buffers.
+	written, err := writeStrings(w, CheckOutputEOL, p.LongServiceOutput)
+	if err != nil {
+		panic("Failed to write LongServiceOutput field content to given output sink")
+	}
+
+	totalWritten += written
+
+	if p.longServiceOutput != nil {
+		if p.LongServiceOutput != "" {
+			written, err = io.WriteString(w, CheckOutputEOL)
+			if err != nil {
+				panic("Failed to write LongServiceOutput reader content separator to given output sink")
+			}
+
+			totalWritten += written
+		}
+
+		p.logAction("Streaming LongServiceOutput reader content to output sink")
+
+		written, err = p.longServiceOutput.writeTo(w)
+		if err != nil {
+			p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf(
+				"Failed to read LongServiceOutput reader content: %v", err,
+			))
+		}
+
+		totalWritten += written
+	}
+
+	if tables != "" {
+		separator := ""
+		if p.hasLongServiceOutput() {
+			separator = CheckOutputEOL + CheckOutputEOL
+		}
+
+		written, err = writeStrings(w, separator, tables)
+		if err != nil {
+			panic("Failed to write LongServiceOutput tables to given output sink")
+		}
+
+		totalWritten += written
+	}
+
+	written, err = io.WriteString(w, CheckOutputEOL)
 	if err != nil {
 		panic("Failed to write LongServiceOutput field content to given output sink")
 	}
 
 	totalWritten += written
 
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin LongServiceOutput content written to given output sink", totalWritten))
+	p.logSectionOutputSize("LongServiceOutput", "%d bytes plugin LongServiceOutput content written to given output sink", totalWritten)
 }
 
 // handleEncodedPayload is a wrapper around the logic used to handle/process
 // any user-provided content to be encoded and included in the plugin output.
 func (p Plugin) handleEncodedPayload(w io.Writer) {
-	// Early exit if there is no content to process.
-	if p.encodedPayloadBuffer.Len() == 0 {
+	var encodedWithDelimiters string
+
+	switch {
+	case p.encodedPayloadBuffer.Len() > 0:
+		encodedWithDelimiters = p.encodePayloadBuffer()
+
+	case p.preEncodedPayload != "":
+		p.logAction("Using previously encoded payload content as-is")
+		encodedWithDelimiters = p.preEncodedPayload
+
+	default:
+		// Early exit if there is no content to process.
 		p.logAction("Skipping processing of encoded payload buffer; buffer is empty")
 
 		return
 	}
 
+	var totalWritten int
+
+	written, err := writeStrings(w,
+		CheckOutputEOL,
+		"**", p.getEncodedPayloadLabelText(), "**",
+		CheckOutputEOL,
+	)
+	if err != nil {
+		panic("Failed to write EncodedPayload section label to given output sink")
+	} else if p.debugLogging.pluginOutputSize {
+		p.logPluginOutputSize(fmt.Sprintf("%d bytes EncodedPayload section header written", len(encodedWithDelimiters)))
+	}
+
+	totalWritten += written
+
+	// Note: fmt.Println() (and fmt.Fprintln()) has the same issue as `\n`:
+	// Nagios seems to interpret them literally instead of emitting an actual
+	// newline. We work around that by explicitly writing CheckOutputEOL for
+	// output that is intended for display within the Nagios web UI.
+	written, err = writeStrings(w, CheckOutputEOL, encodedWithDelimiters, CheckOutputEOL)
+
+	if err != nil {
+		panic("Failed to write EncodedPayload content to given output sink")
+	}
+
+	totalWritten += written
+
+	p.logSectionOutputSize("EncodedPayload", "%d bytes plugin EncodedPayload content written to given output sink", totalWritten)
+}
+
+// encodePayloadBuffer compresses (if possible) and encodes the payload
+// buffer content using the configured delimiters.
+func (p Plugin) encodePayloadBuffer() string {
 	p.logPluginOutputSize(fmt.Sprintf("%d bytes unencoded EncodedPayload content before compression attempt", p.encodedPayloadBuffer.Len()))
 
+	if p.debugLogging.payload {
+		checksum := fmt.Sprintf("%08x", crc32.ChecksumIEEE(p.encodedPayloadBuffer.Bytes()))
+		p.logPayload(
+			fmt.Sprintf("%d bytes unencoded payload buffer, CRC-32 checksum %s", p.encodedPayloadBuffer.Len(), checksum),
+			logAttr{Key: logAttrBytes, Value: p.encodedPayloadBuffer.Len()},
+			logAttr{Key: logAttrChecksum, Value: checksum},
+		)
+	}
+
 	// We opt to continue with original data instead of failing due to a
 	// compression error; failing at this stage loses all results gathered by
 	// the plugin.
-	payloadData := p.compressPayloadBufferOrFallback()
+	var payloadData []byte
+
+	switch {
+	case p.compressedPayload != nil:
+		p.logAction("Using payload content compressed while checking for internal failures")
+		payloadData = p.compressedPayload.Bytes()
+
+	default:
+		compressedBuffer := getPayloadBuffer()
+		defer putPayloadBuffer(compressedBuffer)
+
+		payloadData = p.compressPayloadBufferOrFallback(compressedBuffer)
+	}
 	p.logPluginOutputSize(fmt.Sprintf("%d bytes EncodedPayload data retrieved", len(payloadData)))
 
 	leftDelimiter := p.getEncodedPayloadDelimiterLeft()
@@ -253,56 +342,14 @@ func (p Plugin) handleEncodedPayload(w io.Writer) {
 	)
 
 	p.logPluginOutputSize(fmt.Sprintf("%d bytes EncodedPayload data encoded", len(encodedWithDelimiters)))
+	p.logPayload(
+		fmt.Sprintf("%d bytes encoded payload using delimiters %q and %q", len(encodedWithDelimiters), leftDelimiter, rightDelimiter),
+		logAttr{Key: logAttrBytes, Value: len(encodedWithDelimiters)},
+		logAttr{Key: logAttrLeftDelimiter, Value: leftDelimiter},
+		logAttr{Key: logAttrRightDelimiter, Value: rightDelimiter},
+	)
 
-	var totalWritten int
-
-	// Hide section header/label if no payload was specified.
-	//
-	// If we hide the section header, we still provide some padding to prevent
-	// this output from running up against the LongServiceOutput content.
-	switch {
-	case p.encodedPayloadBuffer.Len() > 0:
-		written, err := fmt.Fprintf(w,
-			"%s**%s**%s",
-			CheckOutputEOL,
-			p.getEncodedPayloadLabelText(),
-			CheckOutputEOL,
-		)
-		if err != nil {
-			panic("Failed to write EncodedPayload section label to given output sink")
-		} else {
-			p.logPluginOutputSize(fmt.Sprintf("%d bytes EncodedPayload section header written", len(encodedWithDelimiters)))
-		}
-
-		totalWritten += written
-
-		// Note: fmt.Println() (and fmt.Fprintln()) has the same issue as
-		// `\n`: Nagios seems to interpret them literally instead of emitting
-		// an actual newline. We work around that by using fmt.Fprintf() for
-		// output that is intended for display within the Nagios web UI.
-		written, err = fmt.Fprintf(w,
-			"%s%v%s",
-			CheckOutputEOL,
-			encodedWithDelimiters,
-			CheckOutputEOL,
-		)
-
-		if err != nil {
-			panic("Failed to write EncodedPayload content to given output sink")
-		}
-
-		totalWritten += written
-
-	default:
-		written, err := fmt.Fprint(w, CheckOutputEOL)
-		if err != nil {
-			panic("Failed to write EncodedPayload section spacer to given output sink")
-		}
-
-		totalWritten += written
-	}
-
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin EncodedPayload content written to given output sink", totalWritten))
+	return encodedWithDelimiters
 }
 
 // handlePerformanceData is a wrapper around the logic used to
@@ -322,7 +369,7 @@ func (p *Plugin) handlePerformanceData(w io.Writer) {
 
 	// If no metrics have been collected by this point we have nothing further
 	// to do.
-	if len(p.perfData) == 0 {
+	if p.perfData.len() == 0 {
 		p.logAction("Skipping processing of performance data; perfdata collection is empty")
 
 		return
@@ -334,7 +381,7 @@ func (p *Plugin) handlePerformanceData(w io.Writer) {
 	// metrics are provided as a single line, leading with a pipe
 	// character, a space and one or more metrics each separated from
 	// another by a single space.
-	written, err := fmt.Fprint(w, " |")
+	written, err := io.WriteString(w, " |")
 	if err != nil {
 		panic("Failed to write performance data content to given output sink")
 	}
@@ -343,50 +390,110 @@ func (p *Plugin) handlePerformanceData(w io.Writer) {
 
 	// Sort performance data values prior to emitting them so that the
 	// output is consistent across plugin execution.
-	perfData := p.getSortedPerfData()
+	perfData := p.applyExtendedPerfData(p.getSortedPerfData())
+
+	// Apply the performance data size limit (if any) by omitting metrics
+	// which do not fit instead of truncating them mid-metric.
+	maxLength := p.compatibility.maxPerfDataLength
+	var omitted int
+
+	// Format each metric into a single reused buffer instead of allocating
+	// a new string per metric.
+	metricBuf := perfDataBufferPool.Get().(*[]byte)
+	defer perfDataBufferPool.Put(metricBuf)
 
 	for _, pd := range perfData {
-		written, err = fmt.Fprint(w, pd.String())
+		metric := pd.AppendTo((*metricBuf)[:0])
+		*metricBuf = metric
+
+		if maxLength > 0 && totalWritten+len(metric) > maxLength {
+			omitted++
+			continue
+		}
+
+		written, err = w.Write(metric)
 		if err != nil {
 			panic("Failed to write performance data content to given output sink")
 		}
 		totalWritten += written
 	}
 
+	if omitted > 0 {
+		p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf(
+			"Omitted %d performance data metrics exceeding the %d byte limit",
+			omitted,
+			maxLength,
+		))
+	}
+
 	// Add final trailing newline to satisfy Nagios plugin output format.
-	written, err = fmt.Fprint(w, CheckOutputEOL)
+	written, err = io.WriteString(w, CheckOutputEOL)
 	if err != nil {
 		panic("Failed to write performance data content to given output sink")
 	}
 
 	totalWritten += written
 
-	p.logPluginOutputSize(fmt.Sprintf("%d bytes plugin performance data content written to given output sink", totalWritten))
+	p.logSectionOutputSize("PerformanceData", "%d bytes plugin performance data content written to given output sink", totalWritten)
 
 }
 
 // isThresholdsSectionHidden indicates whether the Thresholds section should
 // be omitted from output.
 func (p Plugin) isThresholdsSectionHidden() bool {
-	if p.hideThresholdsSection || (p.WarningThreshold == "" && p.CriticalThreshold == "") {
+	if p.hideThresholdsSection || p.isErrorsAndThresholdsHiddenForState() ||
+		(p.WarningThreshold == "" && p.CriticalThreshold == "") {
 		return true
 	}
 	return false
 }
 
-// isErrorsHidden indicates whether the Thresholds section should be omitted
+// isErrorsHidden indicates whether the Errors section should be omitted
 // from output.
 func (p Plugin) isErrorsHidden() bool {
-	if p.hideErrorsSection || (len(p.Errors) == 0 && p.LastError == nil) {
-		return true
+	return p.errorsSectionSkipReason() != ""
+}
+
+// errorsSectionSkipReason returns the reason the Errors section is omitted
+// from output or an empty string if the section is displayed.
+func (p Plugin) errorsSectionSkipReason() string {
+	switch {
+	case p.hideErrorsSection:
+		return "option to hide errors enabled"
+	case p.isErrorsAndThresholdsHiddenForState():
+		return "option to hide errors for OK state enabled"
+	}
+
+	for _, err := range p.Errors {
+		if err != nil {
+			return ""
+		}
+	}
+
+	return "no errors recorded"
+}
+
+// thresholdsSectionSkipReason returns the reason the Thresholds section is
+// omitted from output or an empty string if the section is displayed.
+func (p Plugin) thresholdsSectionSkipReason() string {
+	switch {
+	case !p.hasLongServiceOutput():
+		return "LongServiceOutput is empty"
+	case p.hideThresholdsSection:
+		return "option to hide thresholds enabled"
+	case p.isErrorsAndThresholdsHiddenForState():
+		return "option to hide thresholds for OK state enabled"
+	case p.WarningThreshold == "" && p.CriticalThreshold == "":
+		return "no thresholds specified"
+	default:
+		return ""
 	}
-	return false
 }
 
 // isPayloadSectionHidden indicates whether the Payload section should be
 // omitted from output.
 func (p Plugin) isPayloadSectionHidden() bool {
-	return p.encodedPayloadBuffer.Len() == 0
+	return p.encodedPayloadBuffer.Len() == 0 && p.preEncodedPayload == ""
 }
 
 // getThresholdsLabelText retrieves the custom thresholds label text if set,
@@ -466,20 +573,37 @@ func (p *Plugin) HideErrorsSection() {
 	p.hideErrorsSection = true
 }
 
-// getSortedPerfData returns a sorted copy of the performance data metrics.
-func (p Plugin) getSortedPerfData() []PerformanceData {
-	keys := make([]string, 0, len(p.perfData))
-	perfData := make([]PerformanceData, 0, len(p.perfData))
+// HideLongServiceOutputOnOK indicates that client code has opted to omit
+// LongServiceOutput content (including content from a LongServiceOutput
+// reader and tables) if the final plugin state is OK. This keeps OK results
+// small while retaining full detail for problem states.
+//
+// See also HideErrorsAndThresholdsOnOK.
+func (p *Plugin) HideLongServiceOutputOnOK() {
+	p.hideLongServiceOutputOnOK = true
+}
 
-	for k := range p.perfData {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
+// HideErrorsAndThresholdsOnOK indicates that client code has opted to hide
+// the errors and thresholds sections if the final plugin state is OK.
+//
+// See also HideLongServiceOutputOnOK.
+func (p *Plugin) HideErrorsAndThresholdsOnOK() {
+	p.hideErrorsAndThresholdsOnOK = true
+}
 
-	for _, key := range keys {
-		pd := p.perfData[key]
-		perfData = append(perfData, pd)
-	}
+// isLongServiceOutputHiddenForState indicates whether LongServiceOutput
+// content is omitted due to the final plugin state.
+func (p Plugin) isLongServiceOutputHiddenForState() bool {
+	return p.hideLongServiceOutputOnOK && p.ExitStatusCode == StateOKExitCode
+}
+
+// isErrorsAndThresholdsHiddenForState indicates whether the errors and
+// thresholds sections are omitted due to the final plugin state.
+func (p Plugin) isErrorsAndThresholdsHiddenForState() bool {
+	return p.hideErrorsAndThresholdsOnOK && p.ExitStatusCode == StateOKExitCode
+}
 
-	return perfData
+// getSortedPerfData returns a sorted copy of the performance data metrics.
+func (p Plugin) getSortedPerfData() []PerformanceData {
+	return p.perfData.sortedMetrics()
 }
@@ -0,0 +1,117 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_HideDetailsOnOK(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		exitCode           int
+		hideLongOutput     bool
+		hideErrsThresholds bool
+		wantContains       []string
+		wantMissing        []string
+	}{
+		"options disabled": {
+			exitCode:     nagios.StateOKExitCode,
+			wantContains: []string{"widget details", "**ERRORS**", "**THRESHOLDS**"},
+		},
+		"long output hidden for OK state": {
+			exitCode:       nagios.StateOKExitCode,
+			hideLongOutput: true,
+			wantContains:   []string{"**ERRORS**"},
+			wantMissing:    []string{"widget details", "**THRESHOLDS**", "**DETAILED INFO**"},
+		},
+		"long output retained for WARNING state": {
+			exitCode:       nagios.StateWARNINGExitCode,
+			hideLongOutput: true,
+			wantContains:   []string{"widget details", "**ERRORS**", "**THRESHOLDS**"},
+		},
+		"errors and thresholds hidden for OK state": {
+			exitCode:           nagios.StateOKExitCode,
+			hideErrsThresholds: true,
+			wantContains:       []string{"widget details"},
+			wantMissing:        []string{"**ERRORS**", "**THRESHOLDS**"},
+		},
+		"all details hidden for OK state": {
+			exitCode:           nagios.StateOKExitCode,
+			hideLongOutput:     true,
+			hideErrsThresholds: true,
+			wantMissing:        []string{"widget details", "**ERRORS**", "**THRESHOLDS**", "**DETAILED INFO**"},
+		},
+		"all details retained for CRITICAL state": {
+			exitCode:           nagios.StateCRITICALExitCode,
+			hideLongOutput:     true,
+			hideErrsThresholds: true,
+			wantContains:       []string{"widget details", "**ERRORS**", "**THRESHOLDS**"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var output strings.Builder
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&output)
+			plugin.SkipOSExit()
+
+			if tt.hideLongOutput {
+				plugin.HideLongServiceOutputOnOK()
+			}
+
+			if tt.hideErrsThresholds {
+				plugin.HideErrorsAndThresholdsOnOK()
+			}
+
+			plugin.ExitStatusCode = tt.exitCode
+			plugin.ServiceOutput = "widgets checked"
+			plugin.LongServiceOutput = "widget details"
+			plugin.WarningThreshold = "80"
+			plugin.CriticalThreshold = "90"
+			plugin.Errors = []error{errors.New("widget sensor flapping")}
+
+			plugin.ReturnCheckResults()
+
+			got := output.String()
+
+			if !strings.HasPrefix(got, "widgets checked") {
+				t.Errorf("ERROR: want ServiceOutput retained, got:\n%s", got)
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got, want) {
+					t.Errorf("ERROR: want %q in output, got:\n%s", want, got)
+				}
+			}
+
+			for _, unwanted := range tt.wantMissing {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("ERROR: want %q omitted from output, got:\n%s", unwanted, got)
+				}
+			}
+
+			if !strings.Contains(got, "| 'time'=") {
+				t.Errorf("ERROR: want performance data retained, got:\n%s", got)
+			}
+
+			t.Log("OK: plugin output details emitted as expected")
+		})
+	}
+}
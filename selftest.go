@@ -0,0 +1,192 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SelfTestFlag is the command-line flag used to request a self-test of a
+// plugin binary (e.g., by a packaging pipeline before deployment). See
+// SelfTestRequested and RunSelfTest.
+const SelfTestFlag string = "--selftest"
+
+// Synthetic check result content used by SelfTest.
+const (
+	selfTestServiceOutput     string = "OK: go-nagios self-test result"
+	selfTestLongServiceOutput string = "Synthetic check result rendered by self-test"
+	selfTestPayload           string = "go-nagios self-test payload"
+)
+
+// selfTestPerfData is the synthetic performance data collection used by
+// SelfTest.
+var selfTestPerfData = []PerformanceData{
+	{
+		Label:             "selftest_usage",
+		Value:             "42",
+		UnitOfMeasurement: "%",
+		Warn:              "80",
+		Crit:              "90",
+		Min:               "0",
+		Max:               "100",
+	},
+	{
+		Label: "selftest_items",
+		Value: "7",
+	},
+}
+
+// SelfTestRequested indicates whether the given command-line arguments
+// (e.g., os.Args[1:]) include the self-test flag. The single dash form of the
+// flag is also accepted.
+func SelfTestRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == SelfTestFlag || arg == strings.TrimPrefix(SelfTestFlag, "-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelfTest renders a synthetic check result using a new plugin value and
+// validates the output. The given function (if not nil) is called to apply
+// plugin settings (e.g., compatibility mode, labels, payload delimiters)
+// before the synthetic check result is rendered; the output target and exit
+// behavior are overridden by SelfTest.
+//
+// The output is validated as follows:
+//
+//   - the output linter (see LintOutput) reports no errors
+//   - the encoded payload is extracted and decoded to the original content
+//   - the performance data is parsed and includes each synthetic metric
+//
+// A collection of problems found is returned (each wrapping
+// ErrSelfTestFailed) or nil if no problems are found.
+func SelfTest(configure func(p *Plugin)) []error {
+	var output strings.Builder
+
+	plugin := NewPlugin()
+
+	if configure != nil {
+		configure(plugin)
+	}
+
+	plugin.SetOutputTarget(&output)
+	plugin.SetExitFunc(func(int) {})
+
+	plugin.ServiceOutput = selfTestServiceOutput
+	plugin.LongServiceOutput = selfTestLongServiceOutput
+	plugin.ExitStatusCode = StateOKExitCode
+
+	var problems []error
+
+	if err := plugin.AddPerfData(false, selfTestPerfData...); err != nil {
+		problems = append(problems, fmt.Errorf("%w: failed to add performance data: %v", ErrSelfTestFailed, err))
+	}
+
+	if _, err := plugin.AddPayloadString(selfTestPayload); err != nil {
+		problems = append(problems, fmt.Errorf("%w: failed to add payload: %v", ErrSelfTestFailed, err))
+	}
+
+	plugin.ReturnCheckResults()
+
+	rendered := output.String()
+
+	for _, issue := range LintOutput(rendered) {
+		if issue.Severity == LintSeverityError {
+			problems = append(problems, fmt.Errorf("%w: lint issue: %s", ErrSelfTestFailed, issue))
+		}
+	}
+
+	payload, err := plugin.ExtractAndDecodePayload(rendered, "")
+	switch {
+	case err != nil:
+		problems = append(problems, fmt.Errorf("%w: failed to decode payload: %v", ErrSelfTestFailed, err))
+	case payload != selfTestPayload:
+		problems = append(problems, fmt.Errorf(
+			"%w: decoded payload %q does not match original payload %q",
+			ErrSelfTestFailed,
+			payload,
+			selfTestPayload,
+		))
+	}
+
+	problems = append(problems, selfTestCheckPerfData(PreviewOutput(rendered).PerfData)...)
+
+	return problems
+}
+
+// selfTestCheckPerfData parses the given rendered performance data and
+// returns a collection of problems found.
+func selfTestCheckPerfData(rawPerfData string) []error {
+	perfData, err := ParsePerfData(rawPerfData)
+	if err != nil {
+		return []error{fmt.Errorf("%w: failed to parse performance data: %v", ErrSelfTestFailed, err)}
+	}
+
+	var problems []error
+
+	for _, want := range selfTestPerfData {
+		var found bool
+		for _, got := range perfData {
+			if got.Label == want.Label && got.Value == want.Value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			problems = append(problems, fmt.Errorf(
+				"%w: performance data metric %q not found in output",
+				ErrSelfTestFailed,
+				want.Label,
+			))
+		}
+	}
+
+	return problems
+}
+
+// RunSelfTest runs SelfTest (see that function for details) and returns the
+// outcome as the check result of the plugin: OK if no problems are found,
+// otherwise UNKNOWN with each problem recorded as an error. The plugin exits
+// as it would for any other check result.
+//
+// Plugins typically call this method early in main:
+//
+//	if nagios.SelfTestRequested(os.Args[1:]) {
+//		plugin.RunSelfTest(nil)
+//		return
+//	}
+func (p *Plugin) RunSelfTest(configure func(p *Plugin)) {
+	p.logAction("Running self-test as requested")
+
+	problems := SelfTest(configure)
+
+	switch {
+	case len(problems) > 0:
+		p.ExitStatusCode = StateUNKNOWNExitCode
+		p.ServiceOutput = fmt.Sprintf(
+			"%s: self-test failed; %d problems found",
+			StateUNKNOWNLabel,
+			len(problems),
+		)
+		p.AddError(problems...)
+
+	default:
+		p.ExitStatusCode = StateOKExitCode
+		p.ServiceOutput = fmt.Sprintf(
+			"%s: self-test passed",
+			StateOKLabel,
+		)
+	}
+
+	p.ReturnCheckResults()
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestSelfTestRequested(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args []string
+		want bool
+	}{
+		"no arguments": {
+			args: nil,
+			want: false,
+		},
+		"other flags": {
+			args: []string{"--host", "db01", "--selftests"},
+			want: false,
+		},
+		"double dash flag": {
+			args: []string{"--host", "db01", "--selftest"},
+			want: true,
+		},
+		"single dash flag": {
+			args: []string{"-selftest"},
+			want: true,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := nagios.SelfTestRequested(tt.args); got != tt.want {
+				t.Fatalf("ERROR: want %t, got %t", tt.want, got)
+			}
+
+			t.Log("OK: self-test flag detected as expected")
+		})
+	}
+}
+
+func TestSelfTest_PassesForValidConfiguration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(p *nagios.Plugin){
+		"default settings": nil,
+		"custom settings": func(p *nagios.Plugin) {
+			p.SetCompatibilityMode(nagios.CompatibilityModeNaemon)
+			p.SetEncodedPayloadDelimiterLeft("[[")
+			p.SetEncodedPayloadDelimiterRight("]]")
+			p.EnableMetricsSummarySection()
+		},
+	}
+
+	for name, configure := range tests {
+		configure := configure
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if problems := nagios.SelfTest(configure); problems != nil {
+				t.Fatalf("ERROR: unexpected self-test problems: %v", problems)
+			}
+
+			t.Log("OK: self-test passed as expected")
+		})
+	}
+}
+
+func TestSelfTest_ReportsProblems(t *testing.T) {
+	t.Parallel()
+
+	// A performance data size limit too small for the synthetic metrics
+	// causes metrics to be omitted from the output.
+	problems := nagios.SelfTest(func(p *nagios.Plugin) {
+		p.SetMaxPerfDataLength(10)
+	})
+
+	if len(problems) == 0 {
+		t.Fatal("ERROR: want self-test problems, got none")
+	}
+
+	for _, problem := range problems {
+		if !errors.Is(problem, nagios.ErrSelfTestFailed) {
+			t.Errorf("ERROR: want problem wrapping %v, got %v", nagios.ErrSelfTestFailed, problem)
+		}
+	}
+
+	t.Log("OK: self-test problems reported as expected")
+}
+
+func TestPlugin_RunSelfTest_ReportsOutcome(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		configure    func(p *nagios.Plugin)
+		wantExitCode int
+		wantPrefix   string
+	}{
+		"passed": {
+			wantExitCode: nagios.StateOKExitCode,
+			wantPrefix:   "OK: self-test passed",
+		},
+		"failed": {
+			configure: func(p *nagios.Plugin) {
+				p.SetMaxPerfDataLength(10)
+			},
+			wantExitCode: nagios.StateUNKNOWNExitCode,
+			wantPrefix:   "UNKNOWN: self-test failed;",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+			exitCode := -1
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SetExitFunc(func(code int) { exitCode = code })
+			plugin.RunSelfTest(tt.configure)
+
+			switch {
+			case exitCode != tt.wantExitCode:
+				t.Fatalf("ERROR: want exit code %d, got %d", tt.wantExitCode, exitCode)
+			case !strings.HasPrefix(outputBuffer.String(), tt.wantPrefix):
+				t.Fatalf("ERROR: want output prefix %q, got %q", tt.wantPrefix, outputBuffer.String())
+			}
+
+			t.Log("OK: self-test outcome reported as expected")
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sensuUnitTag is the name of the Sensu metric point tag used to record the
+// unit of measurement of a performance data metric.
+const sensuUnitTag string = "unit"
+
+// SensuEvent is a Sensu Go event. Only the fields needed to report a check
+// result and its metrics are provided.
+type SensuEvent struct {
+	// Entity optionally identifies the (proxy) entity associated with the
+	// event. If omitted, the Sensu agent attributes the event to its own
+	// entity.
+	Entity *SensuEntity `json:"entity,omitempty"`
+
+	// Check is the check result.
+	Check SensuCheck `json:"check"`
+
+	// Metrics is the optional collection of metric points derived from
+	// performance data.
+	Metrics *SensuMetrics `json:"metrics,omitempty"`
+}
+
+// SensuObjectMeta is the metadata of a Sensu Go resource.
+type SensuObjectMeta struct {
+	// Name is the resource name.
+	Name string `json:"name"`
+
+	// Namespace is the optional resource namespace.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Annotations is the optional collection of non-identifying metadata.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SensuEntity is a Sensu Go entity reference.
+type SensuEntity struct {
+	// Metadata identifies the entity.
+	Metadata SensuObjectMeta `json:"metadata"`
+
+	// EntityClass is the entity class (e.g., proxy).
+	EntityClass string `json:"entity_class,omitempty"`
+}
+
+// SensuCheck is the check result of a Sensu Go event.
+type SensuCheck struct {
+	// Metadata identifies the check.
+	Metadata SensuObjectMeta `json:"metadata"`
+
+	// Output is the check output.
+	Output string `json:"output"`
+
+	// Status is the exit status code of the check.
+	Status int `json:"status"`
+
+	// Executed is the time (Unix seconds) the check was executed.
+	Executed int64 `json:"executed"`
+
+	// Duration is the check execution time in seconds.
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// SensuMetrics is the collection of metric points of a Sensu Go event.
+type SensuMetrics struct {
+	// Points is the collection of metric points.
+	Points []SensuMetricPoint `json:"points"`
+}
+
+// SensuMetricPoint is a single Sensu Go metric point.
+type SensuMetricPoint struct {
+	// Name is the metric name.
+	Name string `json:"name"`
+
+	// Value is the metric value.
+	Value float64 `json:"value"`
+
+	// Timestamp is the time (Unix seconds) the metric was collected.
+	Timestamp int64 `json:"timestamp"`
+
+	// Tags is the optional collection of metric tags.
+	Tags []SensuMetricTag `json:"tags,omitempty"`
+}
+
+// SensuMetricTag is a name/value pair attached to a Sensu Go metric point.
+type SensuMetricTag struct {
+	// Name is the tag name.
+	Name string `json:"name"`
+
+	// Value is the tag value.
+	Value string `json:"value"`
+}
+
+// SetSensuEventTarget overrides the default check name used for Sensu event
+// output and optionally attributes the event to a (proxy) entity. If check
+// is empty, the base name of the plugin executable is used. If entity is
+// empty, the Sensu agent attributes the event to its own entity.
+func (p *Plugin) SetSensuEventTarget(check string, entity string) {
+	p.sensuCheckName = check
+	p.sensuEntity = entity
+}
+
+// SensuEvent renders the current plugin state as a Sensu Go event. The check
+// output is the ServiceOutput text followed by recorded errors and
+// LongServiceOutput. Performance data metrics with numeric values are
+// provided as metric points, with the unit of measurement (if any) recorded
+// as a tag. The plugin timeout (if armed) is disarmed.
+func (p *Plugin) SensuEvent() SensuEvent {
+	p.disarmTimeoutForRender()
+	p.normalizeErrors()
+	p.checkInternalFailures()
+	p.releaseCompressedPayload()
+
+	if strings.TrimSpace(p.ServiceOutput) != "" {
+		p.tryAddDefaultTimeMetric()
+	}
+
+	now := time.Now()
+
+	checkName := p.sensuCheckName
+	if checkName == "" {
+		checkName = p.getCheckmkItem()
+	}
+
+	event := SensuEvent{
+		Check: SensuCheck{
+			Metadata: SensuObjectMeta{
+				Name:        checkName,
+				Annotations: p.correlationIDAnnotations(),
+			},
+			Output:   p.summaryText(),
+			Status:   p.ExitStatusCode,
+			Executed: now.Unix(),
+		},
+	}
+
+	if !p.start.IsZero() {
+		event.Check.Duration = now.Sub(p.start).Seconds()
+	}
+
+	if p.sensuEntity != "" {
+		event.Entity = &SensuEntity{
+			Metadata:    SensuObjectMeta{Name: p.sensuEntity},
+			EntityClass: "proxy",
+		}
+	}
+
+	if points := sensuMetricPoints(p.getSortedPerfData(), now); len(points) > 0 {
+		event.Metrics = &SensuMetrics{Points: points}
+	}
+
+	return event
+}
+
+// SensuEventOutput renders the current plugin state as a newline terminated
+// Sensu Go event JSON document suitable for submission to the Sensu agent
+// events API. See SensuEvent for details.
+func (p *Plugin) SensuEventOutput() string {
+	data, err := json.Marshal(p.SensuEvent())
+	if err != nil {
+		// The event consists solely of strings and numbers; failure here
+		// indicates a programming error.
+		panic("Failed to encode Sensu event: " + err.Error())
+	}
+
+	return string(data) + "\n"
+}
+
+// sensuMetricPoints returns metric points for the given performance data
+// metrics. Metrics without a numeric value (e.g., "U") are skipped.
+func sensuMetricPoints(perfData []PerformanceData, collected time.Time) []SensuMetricPoint {
+	points := make([]SensuMetricPoint, 0, len(perfData))
+
+	for _, pd := range perfData {
+		value, err := strconv.ParseFloat(pd.Value, 64)
+		if err != nil {
+			continue
+		}
+
+		point := SensuMetricPoint{
+			Name:      pd.Label,
+			Value:     value,
+			Timestamp: collected.Unix(),
+		}
+
+		if pd.UnitOfMeasurement != "" {
+			point.Tags = []SensuMetricTag{{Name: sensuUnitTag, Value: pd.UnitOfMeasurement}}
+		}
+
+		points = append(points, point)
+	}
+
+	return points
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_SensuEvent_RendersCheckAndMetrics(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.SetSensuEventTarget("check-disk", "nas01")
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ServiceOutput = "CRITICAL: disk 95% used"
+	plugin.LongServiceOutput = "volume: /srv"
+	plugin.AddError(errors.New("quota exceeded"))
+
+	if err := plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "used", Value: "95", UnitOfMeasurement: "%"},
+		nagios.PerformanceData{Label: "pending", Value: "U"},
+	); err != nil {
+		t.Fatalf("ERROR: failed to add performance data: %v", err)
+	}
+
+	event := plugin.SensuEvent()
+
+	switch {
+	case event.Entity == nil || event.Entity.Metadata.Name != "nas01":
+		t.Fatalf("ERROR: unexpected entity %+v", event.Entity)
+	case event.Check.Metadata.Name != "check-disk" || event.Check.Status != nagios.StateCRITICALExitCode:
+		t.Fatalf("ERROR: unexpected check %+v", event.Check)
+	case event.Check.Output != "CRITICAL: disk 95% used\n* quota exceeded\nvolume: /srv":
+		t.Fatalf("ERROR: unexpected check output %q", event.Check.Output)
+	case event.Metrics == nil:
+		t.Fatal("ERROR: metrics not rendered")
+	}
+
+	// The default time metric is added alongside client-provided metrics;
+	// the non-numeric pending metric is skipped.
+	var names []string
+	for _, point := range event.Metrics.Points {
+		names = append(names, point.Name)
+	}
+
+	if d := cmp.Diff([]string{"time", "used"}, names); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	used := event.Metrics.Points[1]
+	if used.Value != 95 || len(used.Tags) != 1 || used.Tags[0].Value != "%" || used.Timestamp != event.Check.Executed {
+		t.Fatalf("ERROR: unexpected metric point %+v", used)
+	}
+
+	t.Log("OK: Sensu event rendered as expected")
+}
+
+func TestPlugin_ReturnCheckResults_UsesSensuEventOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetOutputFormat(nagios.OutputFormatSensuEvent)
+	plugin.ServiceOutput = "OK: all good"
+
+	plugin.ReturnCheckResults()
+
+	var event nagios.SensuEvent
+	if err := json.Unmarshal([]byte(outputBuffer.String()), &event); err != nil {
+		t.Fatalf("ERROR: output is not a Sensu event: %v\n%s", err, outputBuffer.String())
+	}
+
+	switch {
+	case event.Entity != nil:
+		t.Fatalf("ERROR: unexpected entity %+v", event.Entity)
+	case event.Check.Output != "OK: all good" || event.Check.Status != nagios.StateOKExitCode:
+		t.Fatalf("ERROR: unexpected check %+v", event.Check)
+	case event.Check.Metadata.Name == "":
+		t.Fatal("ERROR: default check name not set")
+	}
+
+	t.Log("OK: Sensu event output format used as expected")
+}
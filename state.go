@@ -0,0 +1,230 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// exitCodeSeverity returns the relative severity of the given plugin exit
+// code. Higher values indicate a more severe state.
+//
+// The ordering (from least to most severe) is OK, DEPENDENT, UNKNOWN,
+// WARNING, CRITICAL. This matches the "max_state_alt" ordering used by the
+// Monitoring Plugins project when combining the results of multiple checks.
+// Invalid exit codes are treated as UNKNOWN.
+func exitCodeSeverity(exitCode int) int {
+	switch exitCode {
+	case StateOKExitCode:
+		return 0
+	case StateDEPENDENTExitCode:
+		return 1
+	case StateUNKNOWNExitCode:
+		return 2
+	case StateWARNINGExitCode:
+		return 3
+	case StateCRITICALExitCode:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// isMoreSevereExitCode indicates whether exit code a represents a more
+// severe state than exit code b.
+func isMoreSevereExitCode(a int, b int) bool {
+	return exitCodeSeverity(a) > exitCodeSeverity(b)
+}
+
+// EscalateState sets the plugin exit state to the given exit code if it
+// represents a more severe state than the current exit state. The exit state
+// is never downgraded. Unsupported exit codes are treated as UNKNOWN.
+//
+// The ordering (from least to most severe) is OK, DEPENDENT, UNKNOWN,
+// WARNING, CRITICAL. In host check mode the given exit code is mapped to a
+// host check state (see SetHostCheckMode).
+func (p *Plugin) EscalateState(exitCode int) {
+	exitCode = supportedExitCodeOrUnknown(exitCode)
+
+	escalated := p.escalatedExitState(p.ExitStatusCode, exitCode)
+	if escalated == p.ExitStatusCode {
+		return
+	}
+
+	p.logAction(fmt.Sprintf(
+		"Escalating plugin exit state from %s to %s",
+		p.stateLabel(p.ExitStatusCode),
+		p.stateLabel(escalated),
+	))
+
+	p.ExitStatusCode = escalated
+}
+
+// WorstState sets the plugin exit state to the most severe of the given exit
+// codes if it is more severe than the current exit state (see EscalateState)
+// and returns the resulting plugin exit state.
+func (p *Plugin) WorstState(exitCodes ...int) int {
+	for _, exitCode := range exitCodes {
+		p.EscalateState(exitCode)
+	}
+
+	return p.ExitStatusCode
+}
+
+// ServiceStateFromExitCode returns the service state for the given plugin
+// exit code. If an unsupported exit code is given the UNKNOWN service state is
+// returned along with an error.
+func ServiceStateFromExitCode(exitCode int) (ServiceState, error) {
+	for _, state := range SupportedServiceStates() {
+		if state.ExitCode == exitCode {
+			return state, nil
+		}
+	}
+
+	return unknownServiceState(), fmt.Errorf(
+		"%w: unsupported exit code %d",
+		ErrInvalidServiceState,
+		exitCode,
+	)
+}
+
+// ServiceStateFromLabel returns the service state for the given plugin state
+// label. Labels are evaluated using case-insensitive comparison. If an
+// unsupported label is given the UNKNOWN service state is returned along
+// with an error.
+func ServiceStateFromLabel(label string) (ServiceState, error) {
+	for _, state := range SupportedServiceStates() {
+		if strings.EqualFold(state.Label, strings.TrimSpace(label)) {
+			return state, nil
+		}
+	}
+
+	return unknownServiceState(), fmt.Errorf(
+		"%w: unsupported label %q",
+		ErrInvalidServiceState,
+		label,
+	)
+}
+
+// unknownServiceState returns the UNKNOWN service state.
+func unknownServiceState() ServiceState {
+	return ServiceState{
+		Label:    StateUNKNOWNLabel,
+		ExitCode: StateUNKNOWNExitCode,
+	}
+}
+
+// String returns the label of the service state (e.g., "WARNING").
+func (s ServiceState) String() string {
+	return s.Label
+}
+
+// Validate returns an error if the service state label and exit code are not
+// a supported pairing (e.g., "WARNING" and StateWARNINGExitCode).
+func (s ServiceState) Validate() error {
+	for _, state := range SupportedServiceStates() {
+		if state == s {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"%w: unsupported label %q and exit code %d pairing",
+		ErrInvalidServiceState,
+		s.Label,
+		s.ExitCode,
+	)
+}
+
+// IsMoreSevereThan indicates whether the service state is more severe than
+// the given service state. Service states are compared using their exit
+// codes; see EscalateState for the ordering used.
+func (s ServiceState) IsMoreSevereThan(other ServiceState) bool {
+	return isMoreSevereExitCode(s.ExitCode, other.ExitCode)
+}
+
+// Compare returns -1 if the service state is less severe than the given
+// service state, 1 if it is more severe and 0 if both are equally severe.
+// This is intended for sorting service states by severity.
+func (s ServiceState) Compare(other ServiceState) int {
+	switch a, b := exitCodeSeverity(s.ExitCode), exitCodeSeverity(other.ExitCode); {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// State is a typed plugin state. State values are plugin exit codes and
+// convert directly to and from the StateOKExitCode, StateWARNINGExitCode,
+// etc. constants (e.g., State(StateWARNINGExitCode) or int(StateWARNING)).
+//
+// Unlike labels or exit codes passed around as plain values, a State can be
+// validated (see Valid) and parsed from a label (see ParseState) without
+// relying on package-level lookup tables.
+type State int
+
+// Typed plugin states. See State.
+const (
+	StateOK        State = State(StateOKExitCode)
+	StateWARNING   State = State(StateWARNINGExitCode)
+	StateCRITICAL  State = State(StateCRITICALExitCode)
+	StateUNKNOWN   State = State(StateUNKNOWNExitCode)
+	StateDEPENDENT State = State(StateDEPENDENTExitCode)
+)
+
+// ParseState returns the State for the given plugin state label. Labels are
+// evaluated using case-insensitive comparison. If an unsupported label is
+// given StateUNKNOWN is returned along with an error.
+func ParseState(label string) (State, error) {
+	serviceState, err := ServiceStateFromLabel(label)
+
+	return State(serviceState.ExitCode), err
+}
+
+// String returns the label of the state (e.g., "WARNING"). Unsupported
+// states are returned in the form "State(N)".
+func (s State) String() string {
+	if !s.Valid() {
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+
+	return ExitCodeToStateLabel(int(s))
+}
+
+// Valid indicates whether the state is a supported plugin state.
+func (s State) Valid() bool {
+	for _, exitCode := range SupportedExitCodes() {
+		if int(s) == exitCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExitCode returns the plugin exit code for the state.
+func (s State) ExitCode() int {
+	return int(s)
+}
+
+// ServiceState returns the ServiceState for the state. Unsupported states
+// are returned as the UNKNOWN service state.
+func (s State) ServiceState() ServiceState {
+	if !s.Valid() {
+		return unknownServiceState()
+	}
+
+	return ServiceState{
+		Label:    ExitCodeToStateLabel(int(s)),
+		ExitCode: int(s),
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_EscalateState(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		current int
+		given   int
+		want    int
+	}{
+		"OK to WARNING": {
+			current: nagios.StateOKExitCode,
+			given:   nagios.StateWARNINGExitCode,
+			want:    nagios.StateWARNINGExitCode,
+		},
+		"WARNING to CRITICAL": {
+			current: nagios.StateWARNINGExitCode,
+			given:   nagios.StateCRITICALExitCode,
+			want:    nagios.StateCRITICALExitCode,
+		},
+		"CRITICAL not downgraded to WARNING": {
+			current: nagios.StateCRITICALExitCode,
+			given:   nagios.StateWARNINGExitCode,
+			want:    nagios.StateCRITICALExitCode,
+		},
+		"WARNING not downgraded to OK": {
+			current: nagios.StateWARNINGExitCode,
+			given:   nagios.StateOKExitCode,
+			want:    nagios.StateWARNINGExitCode,
+		},
+		"UNKNOWN not downgraded to DEPENDENT": {
+			current: nagios.StateUNKNOWNExitCode,
+			given:   nagios.StateDEPENDENTExitCode,
+			want:    nagios.StateUNKNOWNExitCode,
+		},
+		"unsupported exit code treated as UNKNOWN": {
+			current: nagios.StateOKExitCode,
+			given:   42,
+			want:    nagios.StateUNKNOWNExitCode,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := nagios.NewPlugin()
+			plugin.ExitStatusCode = tt.current
+
+			plugin.EscalateState(tt.given)
+
+			if plugin.ExitStatusCode != tt.want {
+				t.Fatalf("ERROR: want exit code %d, got %d", tt.want, plugin.ExitStatusCode)
+			}
+
+			t.Log("OK: plugin exit state escalated as expected")
+		})
+	}
+}
+
+func TestPlugin_WorstState(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	got := plugin.WorstState(
+		nagios.StateOKExitCode,
+		nagios.StateCRITICALExitCode,
+		nagios.StateWARNINGExitCode,
+	)
+
+	if got != nagios.StateCRITICALExitCode || plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d (plugin: %d)", nagios.StateCRITICALExitCode, got, plugin.ExitStatusCode)
+	}
+
+	if got := plugin.WorstState(); got != nagios.StateCRITICALExitCode {
+		t.Fatalf("ERROR: want unchanged exit code %d, got %d", nagios.StateCRITICALExitCode, got)
+	}
+
+	t.Log("OK: worst state applied as expected")
+}
+
+func TestServiceStateFromExitCode(t *testing.T) {
+	t.Parallel()
+
+	for _, want := range nagios.SupportedServiceStates() {
+		got, err := nagios.ServiceStateFromExitCode(want.ExitCode)
+		if err != nil {
+			t.Fatalf("ERROR: unexpected error for exit code %d: %v", want.ExitCode, err)
+		}
+
+		if got != want {
+			t.Fatalf("ERROR: want %+v, got %+v", want, got)
+		}
+	}
+
+	got, err := nagios.ServiceStateFromExitCode(42)
+	if !errors.Is(err, nagios.ErrInvalidServiceState) {
+		t.Fatalf("ERROR: want error %v, got %v", nagios.ErrInvalidServiceState, err)
+	}
+
+	if got.ExitCode != nagios.StateUNKNOWNExitCode {
+		t.Fatalf("ERROR: want UNKNOWN state for unsupported exit code, got %+v", got)
+	}
+
+	t.Log("OK: service states converted from exit codes as expected")
+}
+
+func TestServiceStateFromLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		label   string
+		want    int
+		wantErr error
+	}{
+		"uppercase": {
+			label: "CRITICAL",
+			want:  nagios.StateCRITICALExitCode,
+		},
+		"mixed case with whitespace": {
+			label: " Warning ",
+			want:  nagios.StateWARNINGExitCode,
+		},
+		"unsupported": {
+			label:   "BROKEN",
+			want:    nagios.StateUNKNOWNExitCode,
+			wantErr: nagios.ErrInvalidServiceState,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := nagios.ServiceStateFromLabel(tt.label)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ERROR: want error %v, got %v", tt.wantErr, err)
+			}
+
+			if got.ExitCode != tt.want {
+				t.Fatalf("ERROR: want exit code %d, got %d", tt.want, got.ExitCode)
+			}
+
+			t.Log("OK: service state converted from label as expected")
+		})
+	}
+}
+
+func TestServiceState_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("ERROR: unexpected error for valid state: %v", err)
+	}
+
+	invalid := nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateCRITICALExitCode}
+	if err := invalid.Validate(); !errors.Is(err, nagios.ErrInvalidServiceState) {
+		t.Fatalf("ERROR: want error %v, got %v", nagios.ErrInvalidServiceState, err)
+	}
+
+	t.Log("OK: service states validated as expected")
+}
+
+func TestServiceState_Comparison(t *testing.T) {
+	t.Parallel()
+
+	warning, _ := nagios.ServiceStateFromExitCode(nagios.StateWARNINGExitCode)
+	critical, _ := nagios.ServiceStateFromExitCode(nagios.StateCRITICALExitCode)
+	unknown, _ := nagios.ServiceStateFromExitCode(nagios.StateUNKNOWNExitCode)
+
+	if !critical.IsMoreSevereThan(warning) || warning.IsMoreSevereThan(critical) {
+		t.Error("ERROR: want CRITICAL more severe than WARNING")
+	}
+
+	if !warning.IsMoreSevereThan(unknown) {
+		t.Error("ERROR: want WARNING more severe than UNKNOWN")
+	}
+
+	if got := warning.Compare(critical); got != -1 {
+		t.Errorf("ERROR: want -1, got %d", got)
+	}
+
+	if got := critical.Compare(warning); got != 1 {
+		t.Errorf("ERROR: want 1, got %d", got)
+	}
+
+	if got := warning.Compare(warning); got != 0 {
+		t.Errorf("ERROR: want 0, got %d", got)
+	}
+
+	if got := critical.String(); got != nagios.StateCRITICALLabel {
+		t.Errorf("ERROR: want %q, got %q", nagios.StateCRITICALLabel, got)
+	}
+
+	t.Log("OK: service states compared as expected")
+}
+
+func TestState(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		state     nagios.State
+		wantLabel string
+		wantValid bool
+		wantCode  int
+	}{
+		"OK": {
+			state:     nagios.StateOK,
+			wantLabel: nagios.StateOKLabel,
+			wantValid: true,
+			wantCode:  nagios.StateOKExitCode,
+		},
+		"CRITICAL": {
+			state:     nagios.StateCRITICAL,
+			wantLabel: nagios.StateCRITICALLabel,
+			wantValid: true,
+			wantCode:  nagios.StateCRITICALExitCode,
+		},
+		"DEPENDENT": {
+			state:     nagios.StateDEPENDENT,
+			wantLabel: nagios.StateDEPENDENTLabel,
+			wantValid: true,
+			wantCode:  nagios.StateDEPENDENTExitCode,
+		},
+		"unsupported": {
+			state:     nagios.State(42),
+			wantLabel: "State(42)",
+			wantValid: false,
+			wantCode:  42,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.state.String(); got != tt.wantLabel {
+				t.Errorf("ERROR: want label %q, got %q", tt.wantLabel, got)
+			}
+
+			if got := tt.state.Valid(); got != tt.wantValid {
+				t.Errorf("ERROR: want valid %t, got %t", tt.wantValid, got)
+			}
+
+			if got := tt.state.ExitCode(); got != tt.wantCode {
+				t.Errorf("ERROR: want exit code %d, got %d", tt.wantCode, got)
+			}
+		})
+	}
+
+	if got := nagios.StateUNKNOWN.ServiceState(); got.Validate() != nil || got.Label != nagios.StateUNKNOWNLabel {
+		t.Errorf("ERROR: want UNKNOWN service state, got %v", got)
+	}
+
+	if got := nagios.State(42).ServiceState(); got.ExitCode != nagios.StateUNKNOWNExitCode {
+		t.Errorf("ERROR: want UNKNOWN service state for unsupported state, got %v", got)
+	}
+
+	t.Log("OK: typed states behave as expected")
+}
+
+func TestParseState(t *testing.T) {
+	t.Parallel()
+
+	got, err := nagios.ParseState(" warning ")
+	if err != nil {
+		t.Fatalf("ERROR: failed to parse state: %v", err)
+	}
+
+	if got != nagios.StateWARNING {
+		t.Errorf("ERROR: want %v, got %v", nagios.StateWARNING, got)
+	}
+
+	got, err = nagios.ParseState("bogus")
+	if !errors.Is(err, nagios.ErrInvalidServiceState) {
+		t.Fatalf("ERROR: want error %v, got %v", nagios.ErrInvalidServiceState, err)
+	}
+
+	if got != nagios.StateUNKNOWN {
+		t.Errorf("ERROR: want %v for unsupported label, got %v", nagios.StateUNKNOWN, got)
+	}
+
+	t.Log("OK: state labels parsed as expected")
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package statsd exports Nagios plugin performance data as gauges using the
+original (Etsy) statsd dialect over UDP.
+
+# OVERVIEW
+
+Unlike the dogstatsd package, no tags are used. The host name and service
+description are instead included as path components of a flat, namespaced
+metric name:
+
+	prefix.host.service.label:value|g
+
+Dots, whitespace and characters used by the statsd datagram format within
+the host name, service description and metric label are replaced with
+underscores so that they do not introduce additional path components.
+Metrics with an undetermined value ("U") are skipped.
+
+An optional sample rate (between 0 and 1) may be specified. Each metric is
+then sent with the given probability and the rate is included in the
+datagram (e.g., "|@0.5") as expected by statsd relays.
+
+The Client.EmitHook method returns a nagios.EmitHookFunc which sends the
+collected performance data when the plugin emits its output (see
+nagios.Plugin.AddEmitHook). Errors are ignored when sending from the emit
+hook; call Client.Send directly if errors need to be handled.
+
+# HOW TO USE
+
+	client := statsd.NewClient("statsd.example.com:8125")
+	client.SetPrefix("nagios")
+	client.SetSampleRate(0.5)
+
+	plugin := nagios.NewPlugin()
+	plugin.AddEmitHook(client.EmitHook("web01", "HTTP"))
+
+	defer plugin.ReturnCheckResults()
+*/
+package statsd
@@ -0,0 +1,261 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package statsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultPort is the default UDP port used by statsd.
+	DefaultPort string = "8125"
+
+	// maxDatagramSize is the maximum size of a single datagram, chosen to
+	// avoid IP fragmentation on common networks.
+	maxDatagramSize int = 1432
+
+	// defaultTimeout is used for sending datagrams if not overridden.
+	defaultTimeout time.Duration = 2 * time.Second
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the statsd address was not provided.
+	ErrMissingAddress = errors.New("statsd address not specified")
+
+	// ErrNoMetrics indicates that no metrics were available for submission.
+	ErrNoMetrics = errors.New("no metrics provided")
+
+	// ErrInvalidMetricValue indicates that a performance data value could
+	// not be converted to a numeric value.
+	ErrInvalidMetricValue = errors.New("invalid performance data value")
+)
+
+// Client sends performance data to statsd.
+type Client struct {
+	// address is the statsd address in host:port format.
+	address string
+
+	// prefix is the optional namespace prepended to metric names.
+	prefix string
+
+	// sampleRate is the probability that a metric is sent. A value of 1
+	// indicates that all metrics are sent.
+	sampleRate float64
+
+	// random returns a pseudo-random number in [0.0,1.0) used for sampling.
+	random func() float64
+
+	// timeout limits the time spent sending datagrams.
+	timeout time.Duration
+
+	// dialer is used to create the UDP socket.
+	dialer net.Dialer
+}
+
+// NewClient constructs a new Client for the given statsd address. If a port
+// is not included in the address the default port is used. All metrics are
+// sent unless a sample rate is specified.
+func NewClient(address string) *Client {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Client{
+		address:    address,
+		sampleRate: 1,
+		random:     rand.Float64, //nolint:gosec // sampling does not require a secure source
+		timeout:    defaultTimeout,
+	}
+}
+
+// SetPrefix specifies the namespace (e.g., "monitoring.nagios") prepended
+// to metric names. The prefix is used as-is and may contain dots.
+func (c *Client) SetPrefix(prefix string) {
+	c.prefix = strings.Trim(prefix, ".")
+}
+
+// SetSampleRate specifies the probability (greater than 0 and at most 1)
+// that each metric is sent. Values outside of this range are ignored.
+func (c *Client) SetSampleRate(rate float64) {
+	if rate <= 0 || rate > 1 {
+		return
+	}
+
+	c.sampleRate = rate
+}
+
+// SetTimeout overrides the default timeout used when sending datagrams.
+// Non-positive values are ignored.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout = timeout
+}
+
+// Name returns the flat metric name for the given host, service and
+// performance data label. Empty components (e.g., service for host checks)
+// are omitted.
+func (c *Client) Name(host string, service string, label string) string {
+	parts := make([]string, 0, 4)
+	if c.prefix != "" {
+		parts = append(parts, c.prefix)
+	}
+
+	for _, component := range []string{host, service, label} {
+		if component == "" {
+			continue
+		}
+		parts = append(parts, sanitize(component))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Lines returns the given performance data as statsd gauge lines. The
+// sample rate (if set) is included, but sampling is not applied. Metrics
+// with an undetermined value ("U") are skipped.
+func (c *Client) Lines(host string, service string, metrics ...nagios.PerformanceData) ([]string, error) {
+	var rateSuffix string
+	if c.sampleRate < 1 {
+		rateSuffix = "|@" + strconv.FormatFloat(c.sampleRate, 'f', -1, 64)
+	}
+
+	lines := make([]string, 0, len(metrics))
+	for _, pd := range metrics {
+		if pd.Value == "U" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(pd.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q value %q: %w", pd.Label, pd.Value, ErrInvalidMetricValue)
+		}
+
+		lines = append(lines, c.Name(host, service, pd.Label)+":"+
+			strconv.FormatFloat(value, 'f', -1, 64)+"|g"+rateSuffix)
+	}
+
+	return lines, nil
+}
+
+// Send sends the given performance data to statsd as gauges, applying the
+// sample rate (if set). Lines are combined into as few datagrams as
+// possible.
+func (c *Client) Send(ctx context.Context, host string, service string, metrics ...nagios.PerformanceData) error {
+	if c.address == "" {
+		return ErrMissingAddress
+	}
+
+	lines, err := c.Lines(host, service, metrics...)
+	if err != nil {
+		return err
+	}
+
+	if len(lines) == 0 {
+		return ErrNoMetrics
+	}
+
+	sampled := lines[:0]
+	for _, line := range lines {
+		if c.sampleRate < 1 && c.random() >= c.sampleRate {
+			continue
+		}
+		sampled = append(sampled, line)
+	}
+
+	if len(sampled) == 0 {
+		return nil
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(dialCtx, "udp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to statsd: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return fmt.Errorf("failed to set statsd connection deadline: %w", err)
+	}
+
+	for _, datagram := range datagrams(sampled) {
+		if _, err := conn.Write([]byte(datagram)); err != nil {
+			return fmt.Errorf("failed to send metrics to statsd: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// EmitHook returns a function suitable for use with
+// nagios.Plugin.AddEmitHook which sends the collected performance data for
+// the given host and service when plugin output is emitted. Errors are
+// ignored.
+func (c *Client) EmitHook(host string, service string) nagios.EmitHookFunc {
+	return func(p *nagios.Plugin) {
+		_ = c.Send(context.Background(), host, service, p.PerfData()...)
+	}
+}
+
+// datagrams combines the given lines into newline separated datagrams no
+// larger than the maximum datagram size. Lines larger than the maximum size
+// are sent in their own datagram.
+func datagrams(lines []string) []string {
+	var collection []string
+	var current strings.Builder
+
+	for _, line := range lines {
+		if current.Len() > 0 && current.Len()+1+len(line) > maxDatagramSize {
+			collection = append(collection, current.String())
+			current.Reset()
+		}
+
+		if current.Len() > 0 {
+			current.WriteByte('\n')
+		}
+		current.WriteString(line)
+	}
+
+	if current.Len() > 0 {
+		collection = append(collection, current.String())
+	}
+
+	return collection
+}
+
+// sanitize replaces dots, whitespace and characters used by the statsd
+// datagram format in the given name component with underscores.
+func sanitize(component string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ':', '|', '@', '#', ' ', '\t', '\r', '\n':
+			return '_'
+		default:
+			return r
+		}
+	}, component)
+}
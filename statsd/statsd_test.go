@@ -0,0 +1,93 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package statsd
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestClient_Lines_UsesFlatNamespacedNames(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("127.0.0.1")
+	client.SetPrefix("monitoring.nagios")
+	client.SetSampleRate(0.25)
+
+	got, err := client.Lines("web01.example.com", "HTTP: main", nagios.PerformanceData{Label: "time", Value: "12", UnitOfMeasurement: "ms"})
+	if err != nil {
+		t.Fatalf("ERROR: failed to generate lines: %v", err)
+	}
+
+	want := []string{"monitoring.nagios.web01_example_com.HTTP__main.time:12|g|@0.25"}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: statsd lines generated as expected")
+}
+
+func TestClient_Send_AppliesSampleRate(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	client := NewClient(listener.LocalAddr().String())
+	client.SetSampleRate(0.5)
+
+	// Alternate between values above and below the sample rate so that
+	// every other metric is dropped.
+	var calls int
+	client.random = func() float64 {
+		calls++
+		if calls%2 == 0 {
+			return 0.9
+		}
+		return 0.1
+	}
+
+	metrics := []nagios.PerformanceData{
+		{Label: "a", Value: "1"},
+		{Label: "b", Value: "2"},
+		{Label: "c", Value: "3"},
+		{Label: "d", Value: "U"},
+	}
+
+	if err := client.Send(context.Background(), "web01", "", metrics...); err != nil {
+		t.Fatalf("ERROR: unexpected send failure: %v", err)
+	}
+
+	if err := listener.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("ERROR: failed to set read deadline: %v", err)
+	}
+
+	buf := make([]byte, maxDatagramSize)
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ERROR: failed to receive datagram: %v", err)
+	}
+
+	want := []string{"web01.a:1|g|@0.5", "web01.c:3|g|@0.5"}
+	if d := cmp.Diff(want, strings.Split(string(buf[:n]), "\n")); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: sample rate applied as expected")
+}
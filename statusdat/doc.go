@@ -0,0 +1,36 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package statusdat parses the Nagios Core status.dat and objects.cache files
+to locate the current state and plugin output of a service.
+
+# OVERVIEW
+
+Nagios Core periodically writes the current state of all monitored objects
+to status.dat and the (expanded) object configuration to objects.cache.
+This package provides a streaming parser for both file formats along with
+helpers to retrieve the most recent plugin output of a single service and
+(optionally) extract & decode an encoded payload using
+nagios.ExtractAndDecodePayload.
+
+This is intended for on-box tooling on plain Nagios Core installations
+which do not provide an API (see the nagiosxi and livestatus packages).
+
+NOTE: status.dat is only updated every status_update_interval seconds; the
+retrieved plugin output may lag behind the most recent check result.
+
+# HOW TO USE
+
+	reader := statusdat.NewReader(statusdat.DefaultStatusFile)
+
+	payload, err := reader.ServicePayload(ctx, "web01", "HTTP")
+	if err != nil {
+		// handle error
+	}
+*/
+package statusdat
@@ -0,0 +1,394 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package statusdat
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultStatusFile is the default location of the Nagios Core status
+	// file.
+	DefaultStatusFile string = "/usr/local/nagios/var/status.dat"
+
+	// DefaultObjectsCacheFile is the default location of the Nagios Core
+	// object cache file.
+	DefaultObjectsCacheFile string = "/usr/local/nagios/var/objects.cache"
+
+	// ServiceStatusType is the block type of service status entries in the
+	// status file.
+	ServiceStatusType string = "servicestatus"
+
+	// HostStatusType is the block type of host status entries in the status
+	// file.
+	HostStatusType string = "hoststatus"
+
+	// ServiceObjectType is the block type of service definitions in the
+	// object cache file.
+	ServiceObjectType string = "service"
+
+	// objectDefinitionPrefix precedes the object type of object cache
+	// definitions.
+	objectDefinitionPrefix string = "define "
+
+	// maxLineSize is the maximum supported line length; long plugin output
+	// is stored on a single line.
+	maxLineSize int = 16 * 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingFile indicates that the status or object cache file path
+	// was not specified.
+	ErrMissingFile = errors.New("file not specified")
+
+	// ErrInvalidFormat indicates that the status or object cache file could
+	// not be parsed.
+	ErrInvalidFormat = errors.New("invalid file format")
+
+	// ErrServiceNotFound indicates that the requested service could not be
+	// found.
+	ErrServiceNotFound = errors.New("service not found")
+)
+
+// Block is a single status entry (e.g., servicestatus { ... }) or object
+// definition (e.g., define service { ... }).
+type Block struct {
+	// Type is the status entry type (e.g., servicestatus) or object type
+	// (e.g., service).
+	Type string
+
+	// Attributes is the collection of values indexed by attribute name.
+	// Values are provided as written to the file.
+	Attributes map[string]string
+}
+
+// ServiceStatus is the current state of a service as recorded in the status
+// file.
+type ServiceStatus struct {
+	// HostName is the name of the host associated with the service.
+	HostName string
+
+	// ServiceDescription is the service name.
+	ServiceDescription string
+
+	// CurrentState is the current service state exit code.
+	CurrentState int
+
+	// StateType indicates whether the current state is SOFT (0) or HARD
+	// (1).
+	StateType int
+
+	// PluginOutput is the most recent one-line summary.
+	PluginOutput string
+
+	// LongPluginOutput is the most recent long output with newlines
+	// unescaped.
+	LongPluginOutput string
+
+	// PerformanceData is the most recent performance data.
+	PerformanceData string
+
+	// LastCheck is the time of the most recent check.
+	LastCheck time.Time
+
+	// Attributes is the collection of all recorded values indexed by
+	// attribute name.
+	Attributes map[string]string
+}
+
+// Output returns the one-line summary followed by the long output (if any)
+// separated by a newline.
+func (ss ServiceStatus) Output() string {
+	if ss.LongPluginOutput == "" {
+		return ss.PluginOutput
+	}
+
+	return ss.PluginOutput + nagios.CheckOutputEOL + ss.LongPluginOutput
+}
+
+// Parse reads status or object cache entries from r and calls fn for each
+// block. Parsing stops early if fn returns false.
+func Parse(r io.Reader, fn func(Block) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	var current *Block
+	var isDefinition bool
+	var lineNum int
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimLeft(strings.TrimRight(scanner.Text(), "\r"), " \t")
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+
+		case current == nil && strings.HasSuffix(line, "{"):
+			blockType := strings.TrimSpace(strings.TrimSuffix(line, "{"))
+			isDefinition = strings.HasPrefix(blockType, objectDefinitionPrefix)
+			if isDefinition {
+				blockType = strings.TrimSpace(strings.TrimPrefix(blockType, objectDefinitionPrefix))
+			}
+
+			current = &Block{
+				Type:       blockType,
+				Attributes: make(map[string]string),
+			}
+
+		case current == nil:
+			return fmt.Errorf("line %d: content outside of block: %w", lineNum, ErrInvalidFormat)
+
+		case strings.TrimSpace(line) == "}":
+			if !fn(*current) {
+				return nil
+			}
+			current = nil
+
+		case isDefinition:
+			fields := strings.SplitN(line, "\t", 2)
+			if len(fields) != 2 {
+				fields = strings.SplitN(line, " ", 2)
+			}
+			value := ""
+			if len(fields) == 2 {
+				value = strings.TrimSpace(fields[1])
+			}
+			current.Attributes[strings.TrimSpace(fields[0])] = value
+
+		default:
+			name, value, found := strings.Cut(line, "=")
+			if !found {
+				return fmt.Errorf("line %d: attribute not in name=value format: %w", lineNum, ErrInvalidFormat)
+			}
+			current.Attributes[name] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if current != nil {
+		return fmt.Errorf("unterminated %s block: %w", current.Type, ErrInvalidFormat)
+	}
+
+	return nil
+}
+
+// Reader retrieves service details from Nagios Core status and object cache
+// files.
+type Reader struct {
+	// statusFile is the path to the status file.
+	statusFile string
+
+	// objectsCacheFile is the path to the object cache file.
+	objectsCacheFile string
+
+	// leftDelimiter is the left delimiter used to extract encoded
+	// payloads.
+	leftDelimiter string
+
+	// rightDelimiter is the right delimiter used to extract encoded
+	// payloads.
+	rightDelimiter string
+}
+
+// NewReader constructs a new Reader for the given status file. The default
+// object cache file location and payload delimiters are used unless
+// overridden.
+func NewReader(statusFile string) *Reader {
+	return &Reader{
+		statusFile:       statusFile,
+		objectsCacheFile: DefaultObjectsCacheFile,
+		leftDelimiter:    nagios.DefaultASCII85EncodingDelimiterLeft,
+		rightDelimiter:   nagios.DefaultASCII85EncodingDelimiterRight,
+	}
+}
+
+// SetObjectsCacheFile overrides the default object cache file location.
+func (r *Reader) SetObjectsCacheFile(path string) {
+	r.objectsCacheFile = path
+}
+
+// SetPayloadDelimiters overrides the default delimiters used to extract
+// encoded payloads.
+func (r *Reader) SetPayloadDelimiters(left string, right string) {
+	r.leftDelimiter = left
+	r.rightDelimiter = right
+}
+
+// ServiceStatus retrieves the current state of the given host and service
+// from the status file.
+func (r *Reader) ServiceStatus(ctx context.Context, host string, service string) (ServiceStatus, error) {
+	block, err := findBlock(ctx, r.statusFile, ServiceStatusType, host, service)
+	if err != nil {
+		return ServiceStatus{}, err
+	}
+
+	attrs := block.Attributes
+	status := ServiceStatus{
+		HostName:           attrs["host_name"],
+		ServiceDescription: attrs["service_description"],
+		PluginOutput:       unescapeValue(attrs["plugin_output"]),
+		LongPluginOutput:   unescapeValue(attrs["long_plugin_output"]),
+		PerformanceData:    attrs["performance_data"],
+		Attributes:         attrs,
+	}
+
+	if status.CurrentState, err = intAttribute(attrs, "current_state"); err != nil {
+		return ServiceStatus{}, err
+	}
+
+	if status.StateType, err = intAttribute(attrs, "state_type"); err != nil {
+		return ServiceStatus{}, err
+	}
+
+	lastCheck, err := intAttribute(attrs, "last_check")
+	if err != nil {
+		return ServiceStatus{}, err
+	}
+	if lastCheck > 0 {
+		status.LastCheck = time.Unix(int64(lastCheck), 0)
+	}
+
+	return status, nil
+}
+
+// ServiceOutput retrieves the most recent plugin output for the given host
+// and service. The returned value is the one-line summary followed by the
+// long output (if any) separated by a newline.
+func (r *Reader) ServiceOutput(ctx context.Context, host string, service string) (string, error) {
+	status, err := r.ServiceStatus(ctx, host, service)
+	if err != nil {
+		return "", err
+	}
+
+	return status.Output(), nil
+}
+
+// ServicePayload retrieves the most recent plugin output for the given host
+// and service and returns the extracted and decoded encoded payload.
+func (r *Reader) ServicePayload(ctx context.Context, host string, service string) (string, error) {
+	output, err := r.ServiceOutput(ctx, host, service)
+	if err != nil {
+		return "", err
+	}
+
+	return nagios.ExtractAndDecodePayload(output, "", r.leftDelimiter, r.rightDelimiter)
+}
+
+// ServiceDefinition retrieves the definition of the given host and service
+// from the object cache file. This is useful to confirm that a service is
+// configured or to retrieve its check command.
+func (r *Reader) ServiceDefinition(ctx context.Context, host string, service string) (Block, error) {
+	return findBlock(ctx, r.objectsCacheFile, ServiceObjectType, host, service)
+}
+
+// findBlock returns the first block of the given type matching the given
+// host and service from the specified file.
+func findBlock(ctx context.Context, path string, blockType string, host string, service string) (Block, error) {
+	if path == "" {
+		return Block{}, ErrMissingFile
+	}
+
+	file, err := os.Open(path) //nolint:gosec // path provided by client code
+	if err != nil {
+		return Block{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var match *Block
+	var ctxErr error
+
+	parseErr := Parse(file, func(block Block) bool {
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			return false
+		}
+
+		if block.Type == blockType &&
+			block.Attributes["host_name"] == host &&
+			block.Attributes["service_description"] == service {
+			match = &block
+			return false
+		}
+
+		return true
+	})
+
+	switch {
+	case ctxErr != nil:
+		return Block{}, ctxErr
+	case parseErr != nil:
+		return Block{}, fmt.Errorf("failed to parse %s: %w", path, parseErr)
+	case match == nil:
+		return Block{}, fmt.Errorf("host %q, service %q: %w", host, service, ErrServiceNotFound)
+	}
+
+	return *match, nil
+}
+
+// intAttribute returns the named attribute as an integer. Missing or empty
+// values are returned as zero.
+func intAttribute(attrs map[string]string, name string) (int, error) {
+	raw := attrs[name]
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q: %w", name, raw, ErrInvalidFormat)
+	}
+
+	return value, nil
+}
+
+// unescapeValue reverses the escaping applied by Nagios to plugin output
+// written to the status file (backslashes and newlines).
+func unescapeValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var result strings.Builder
+	result.Grow(len(value))
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			switch value[i+1] {
+			case 'n':
+				result.WriteByte('\n')
+				i++
+				continue
+			case '\\':
+				result.WriteByte('\\')
+				i++
+				continue
+			}
+		}
+		result.WriteByte(value[i])
+	}
+
+	return result.String()
+}
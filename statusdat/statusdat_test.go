@@ -0,0 +1,143 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package statusdat
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// writeFile writes the given content to a temporary file and returns the
+// path.
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("ERROR: failed to write %s: %v", name, err)
+	}
+
+	return path
+}
+
+func TestReader_ServiceStatus_LocatesService(t *testing.T) {
+	t.Parallel()
+
+	payload := nagios.EncodePayload(
+		[]byte(`{"down":["b"]}`),
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+
+	statusFile := writeFile(t, "status.dat", `# NAGIOS STATE RETENTION FILE
+info {
+	created=1700000000
+	version=4.4.14
+	}
+
+servicestatus {
+	host_name=web01
+	service_description=Disk
+	current_state=0
+	plugin_output=DISK OK
+	}
+
+servicestatus {
+	host_name=web01
+	service_description=HTTP
+	current_state=2
+	state_type=1
+	last_check=1700000000
+	plugin_output=CRITICAL: 1 of 2 endpoints down
+	long_plugin_output=path C:\\temp\n\n**ENCODED PAYLOAD**\n\n`+strings.ReplaceAll(payload, `\`, `\\`)+`\n
+	performance_data=down=1
+	}
+`)
+
+	reader := NewReader(statusFile)
+
+	status, err := reader.ServiceStatus(context.Background(), "web01", "HTTP")
+	if err != nil {
+		t.Fatalf("ERROR: failed to retrieve service status: %v", err)
+	}
+
+	switch {
+	case status.CurrentState != nagios.StateCRITICALExitCode || status.StateType != 1:
+		t.Fatalf("ERROR: unexpected state %d (type %d)", status.CurrentState, status.StateType)
+	case !status.LastCheck.Equal(time.Unix(1700000000, 0)):
+		t.Fatalf("ERROR: unexpected last check %v", status.LastCheck)
+	case !strings.HasPrefix(status.LongPluginOutput, "path C:\\temp\n\n**ENCODED PAYLOAD**\n"):
+		t.Fatalf("ERROR: long output not unescaped: %q", status.LongPluginOutput)
+	case status.PerformanceData != "down=1":
+		t.Fatalf("ERROR: unexpected performance data %q", status.PerformanceData)
+	}
+
+	got, err := reader.ServicePayload(context.Background(), "web01", "HTTP")
+	if err != nil || got != `{"down":["b"]}` {
+		t.Fatalf("ERROR: unexpected payload %q (%v)", got, err)
+	}
+
+	if _, err := reader.ServiceStatus(context.Background(), "web02", "HTTP"); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("ERROR: want %v, got %v", ErrServiceNotFound, err)
+	}
+
+	t.Log("OK: service status located as expected")
+}
+
+func TestReader_ServiceDefinition_LocatesObjectCacheEntry(t *testing.T) {
+	t.Parallel()
+
+	objectsFile := writeFile(t, "objects.cache", `########################################
+#       NAGIOS OBJECT CACHE FILE
+########################################
+
+define host {
+	host_name	web01
+	address	192.0.2.10
+	}
+
+define service {
+	host_name	web01
+	service_description	HTTP
+	check_command	check_http!-S -p 443
+	max_check_attempts	3
+	}
+`)
+
+	reader := NewReader(DefaultStatusFile)
+	reader.SetObjectsCacheFile(objectsFile)
+
+	definition, err := reader.ServiceDefinition(context.Background(), "web01", "HTTP")
+	if err != nil {
+		t.Fatalf("ERROR: failed to retrieve service definition: %v", err)
+	}
+
+	if definition.Type != ServiceObjectType || definition.Attributes["check_command"] != "check_http!-S -p 443" {
+		t.Fatalf("ERROR: unexpected service definition: %+v", definition)
+	}
+
+	t.Log("OK: service definition located as expected")
+}
+
+func TestParse_RejectsUnterminatedBlock(t *testing.T) {
+	t.Parallel()
+
+	err := Parse(strings.NewReader("servicestatus {\n\thost_name=web01\n"), func(Block) bool { return true })
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("ERROR: want %v, got %v", ErrInvalidFormat, err)
+	}
+
+	t.Log("OK: unterminated block rejected as expected")
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// outputStreamBufferSize is the size of the buffer used when streaming
+// plugin output to the plugin output target.
+const outputStreamBufferSize int = 32 * 1024
+
+// outputStreamWriterPool provides reusable buffered writers for streaming
+// plugin output. Reusing writers avoids allocating a new buffer for every
+// emission. Pooled writers hold no state between uses and are safe for
+// concurrent use by separate Plugin values.
+var outputStreamWriterPool = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewWriterSize(io.Discard, outputStreamBufferSize)
+	},
+}
+
+// outputStream writes plugin output sections to the plugin output target as
+// they are processed instead of first collecting the complete plugin output.
+// This limits peak memory use for plugins with large LongServiceOutput or
+// encoded payload content.
+//
+// Writes are buffered and CheckOutputEOL is replaced with the line ending
+// for the selected compatibility mode (if different). Write errors are
+// recorded instead of returned so that processing of the remaining sections
+// is not aborted; the first recorded error is returned by flush.
+type outputStream struct {
+	// buf is the buffered plugin output target.
+	buf *bufio.Writer
+
+	// eol is the line ending used in place of CheckOutputEOL.
+	eol string
+
+	// pendingSpace indicates that a trailing space from the previous write
+	// is held back in case it is the start of CheckOutputEOL.
+	pendingSpace bool
+
+	// written is the number of bytes accepted by the buffered target.
+	written int
+
+	// err is the first error returned by the buffered target.
+	err error
+}
+
+// newOutputStream returns a new outputStream writing to the given plugin
+// output target and using the given line ending in place of CheckOutputEOL.
+func newOutputStream(w io.Writer, eol string) *outputStream {
+	buf := outputStreamWriterPool.Get().(*bufio.Writer)
+	buf.Reset(w)
+
+	return &outputStream{
+		buf: buf,
+		eol: eol,
+	}
+}
+
+// Write writes the given plugin output content, replacing CheckOutputEOL
+// with the configured line ending. Write always reports success; see flush.
+func (s *outputStream) Write(p []byte) (int, error) {
+	n := len(p)
+
+	if s.eol == CheckOutputEOL {
+		s.write(p)
+
+		return n, nil
+	}
+
+	// CheckOutputEOL starts with a space; hold back a trailing space until
+	// the next write (or flush) shows whether a newline follows.
+	if s.pendingSpace {
+		p = append([]byte{' '}, p...)
+		s.pendingSpace = false
+	}
+
+	if len(p) > 0 && p[len(p)-1] == ' ' {
+		p = p[:len(p)-1]
+		s.pendingSpace = true
+	}
+
+	if bytes.Contains(p, []byte(CheckOutputEOL)) {
+		p = bytes.ReplaceAll(p, []byte(CheckOutputEOL), []byte(s.eol))
+	}
+
+	s.write(p)
+
+	return n, nil
+}
+
+// WriteString writes the given plugin output content in the same way as
+// Write without first converting the content to a byte slice when no line
+// ending replacement is needed.
+func (s *outputStream) WriteString(str string) (int, error) {
+	if s.eol != CheckOutputEOL {
+		return s.Write([]byte(str))
+	}
+
+	if s.err == nil {
+		written, err := s.buf.WriteString(str)
+		s.written += written
+		s.err = err
+	}
+
+	return len(str), nil
+}
+
+// writeRendered writes the given already rendered plugin output as-is
+// without line ending replacement.
+func (s *outputStream) writeRendered(output string) {
+	s.releasePendingSpace()
+
+	if s.err != nil {
+		return
+	}
+
+	written, err := s.buf.WriteString(output)
+	s.written += written
+	s.err = err
+}
+
+// flush writes any buffered content to the plugin output target. The first
+// error encountered while writing plugin output is returned.
+func (s *outputStream) flush() error {
+	s.releasePendingSpace()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.err = s.buf.Flush()
+
+	return s.err
+}
+
+// release returns the buffered writer to the pool. The stream must not be
+// used afterwards; any content not yet flushed is discarded.
+func (s *outputStream) release() {
+	if s.buf == nil {
+		return
+	}
+
+	s.buf.Reset(io.Discard)
+	outputStreamWriterPool.Put(s.buf)
+	s.buf = nil
+}
+
+// releasePendingSpace writes a held back trailing space (if any).
+func (s *outputStream) releasePendingSpace() {
+	if s.pendingSpace {
+		s.pendingSpace = false
+		s.write([]byte{' '})
+	}
+}
+
+// write writes the given content to the buffered target unless an error was
+// previously recorded.
+func (s *outputStream) write(p []byte) {
+	if s.err != nil {
+		return
+	}
+
+	written, err := s.buf.Write(p)
+	s.written += written
+	s.err = err
+}
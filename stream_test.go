@@ -0,0 +1,130 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// countingWriter records the number of writes.
+type countingWriter struct {
+	strings.Builder
+	writes int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.writes++
+
+	return cw.Builder.Write(p)
+}
+
+// failingWriter fails every write with the given error.
+type failingWriter struct {
+	err error
+}
+
+func (fw failingWriter) Write(p []byte) (int, error) {
+	return 0, fw.err
+}
+
+func TestOutputStream_ReplacesEOLAcrossWrites(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+	stream := newOutputStream(&outputBuffer, "\n")
+
+	for _, chunk := range []string{"OK: summary ", "\nline one  ", "\n", "line two ", "", "trailing "} {
+		if _, err := stream.Write([]byte(chunk)); err != nil {
+			t.Fatalf("ERROR: unexpected write error: %v", err)
+		}
+	}
+	stream.writeRendered("rendered \n")
+
+	if err := stream.flush(); err != nil {
+		t.Fatalf("ERROR: unexpected flush error: %v", err)
+	}
+
+	want := strings.ReplaceAll("OK: summary \nline one  \nline two trailing ", CheckOutputEOL, "\n") + "rendered \n"
+	if d := cmp.Diff(want, outputBuffer.String()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	if stream.written != len(want) {
+		t.Fatalf("ERROR: want %d bytes written, got %d", len(want), stream.written)
+	}
+
+	t.Log("OK: line endings replaced across write boundaries")
+}
+
+func TestOutputStream_RecordsFirstWriteError(t *testing.T) {
+	t.Parallel()
+
+	errSink := errors.New("sink unavailable")
+
+	stream := newOutputStream(failingWriter{err: errSink}, CheckOutputEOL)
+
+	// Exceed the buffer size to force a write to the failing target.
+	content := strings.Repeat("x", outputStreamBufferSize+1)
+	if n, err := stream.Write([]byte(content)); err != nil || n != len(content) {
+		t.Fatalf("ERROR: want write error to be deferred, got %d bytes written, err %v", n, err)
+	}
+
+	if err := stream.flush(); !errors.Is(err, errSink) {
+		t.Fatalf("ERROR: want error %v, got %v", errSink, err)
+	}
+
+	t.Log("OK: write error deferred until flush")
+}
+
+func TestReturnCheckResults_StreamedOutputMatchesAssembledOutput(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []CompatibilityMode{
+		CompatibilityModeNagios,
+		CompatibilityModeNaemon,
+		CompatibilityModeShinken,
+	} {
+		newTestPlugin := func() *Plugin {
+			plugin := NewPlugin()
+			plugin.SkipOSExit()
+			plugin.SetCompatibilityMode(mode)
+			plugin.ServiceOutput = "WARNING: large output "
+			plugin.LongServiceOutput = strings.Repeat("detail line with trailing space "+CheckOutputEOL, 20000)
+			plugin.WarningThreshold = "80"
+			plugin.CriticalThreshold = "90"
+			plugin.AddError(errors.New("something went wrong"))
+			plugin.ExitStatusCode = StateWARNINGExitCode
+			plugin.BrandingCallback = func() string { return "branding " }
+			_, _ = plugin.SetPayloadString(strings.Repeat("payload ", 10000))
+			_ = plugin.AddPerfData(false, PerformanceData{Label: "time", Value: "1", UnitOfMeasurement: "s"})
+
+			return plugin
+		}
+
+		want := newTestPlugin().assembleOutput()
+
+		var outputBuffer countingWriter
+		plugin := newTestPlugin()
+		plugin.SetOutputTarget(&outputBuffer)
+		plugin.ReturnCheckResults()
+
+		if d := cmp.Diff(want, outputBuffer.String()); d != "" {
+			t.Fatalf("ERROR: compatibility mode %d: (-want, +got)\n:%s", mode, d)
+		}
+
+		if outputBuffer.writes < 2 {
+			t.Errorf("ERROR: compatibility mode %d: want output streamed in multiple writes, got %d", mode, outputBuffer.writes)
+		}
+	}
+
+	t.Log("OK: streamed output matches assembled output")
+}
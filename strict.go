@@ -0,0 +1,93 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+)
+
+// EnableStrictMode indicates that internal library failures encountered while
+// processing plugin output should not be silently handled. If enabled, a
+// failure to encode the payload or to render valid performance data results
+// in an explanatory error being recorded and the plugin state being forced
+// to UNKNOWN (even if the plugin state was WARNING or CRITICAL); the output
+// of the plugin can no longer be trusted to reflect the monitored state.
+// This prevents broken output from masquerading as a valid result.
+//
+// Performance data metrics which fail validation are omitted from the output
+// when strict mode is enabled.
+func (p *Plugin) EnableStrictMode() {
+	p.logAction("Enabling strict mode as requested")
+	p.strictMode = true
+}
+
+// checkInternalFailures evaluates plugin state for conditions which would
+// cause this library to emit broken output. This is a NOOP unless strict
+// mode is enabled.
+func (p *Plugin) checkInternalFailures() {
+	if !p.strictMode {
+		return
+	}
+
+	p.logAction("Strict mode enabled, checking for internal failures")
+
+	if p.encodedPayloadBuffer.Len() > 0 {
+		p.releaseCompressedPayload()
+
+		// Retain the compressed payload so that the payload content is only
+		// compressed once while processing plugin output.
+		compressedBuffer := getPayloadBuffer()
+		if err := compressPayloadContentTo(compressedBuffer, p.encodedPayloadBuffer.Bytes()); err != nil {
+			putPayloadBuffer(compressedBuffer)
+			p.recordInternalFailure(fmt.Errorf("failed to encode payload: %w", err))
+		} else {
+			p.compressedPayload = compressedBuffer
+		}
+	}
+
+	p.perfData.deleteFunc(func(pd PerformanceData) bool {
+		err := pd.Validate()
+		if err != nil {
+			p.recordInternalFailure(fmt.Errorf(
+				"failed to render performance data metric %q: %w",
+				pd.Label,
+				err,
+			))
+		}
+
+		return err != nil
+	})
+}
+
+// recordInternalFailure records the given internal failure and forces the
+// plugin state to UNKNOWN.
+func (p *Plugin) recordInternalFailure(err error) {
+	p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf("Internal failure detected: %v", err))
+
+	p.AddError(fmt.Errorf("%w: %v", ErrInternalLibraryFailure, err))
+
+	if exitState := p.exitState(StateUNKNOWNExitCode); p.ExitStatusCode != exitState {
+		p.logActionLevel(DebugLogLevelWarn, fmt.Sprintf(
+			"Forcing plugin exit state from %s to %s due to internal failure",
+			p.stateLabel(p.ExitStatusCode),
+			p.stateLabel(exitState),
+		))
+		p.ExitStatusCode = exitState
+	}
+}
+
+// releaseCompressedPayload returns the payload content compressed by
+// checkInternalFailures (if any) to the pool.
+func (p *Plugin) releaseCompressedPayload() {
+	if p.compressedPayload == nil {
+		return
+	}
+
+	putPayloadBuffer(p.compressedPayload)
+	p.compressedPayload = nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestEnableStrictMode_InvalidPerfDataEscalatesToUnknown(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		strict       bool
+		wantExitCode int
+	}{
+		"strict mode disabled": {
+			strict:       false,
+			wantExitCode: nagios.StateOKExitCode,
+		},
+		"strict mode enabled": {
+			strict:       true,
+			wantExitCode: nagios.StateUNKNOWNExitCode,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := nagios.NewPlugin()
+			plugin.SkipOSExit()
+
+			var outputBuffer strings.Builder
+			plugin.SetOutputTarget(&outputBuffer)
+
+			if tt.strict {
+				plugin.EnableStrictMode()
+			}
+
+			plugin.ServiceOutput = "OK: all widgets accounted for"
+
+			// Skip validation so that the invalid metric is accepted.
+			if err := plugin.AddPerfData(true, nagios.PerformanceData{
+				Label: "widgets",
+				Value: "abc",
+			}); err != nil {
+				t.Fatalf("ERROR: failed to add perfdata: %v", err)
+			}
+
+			plugin.ReturnCheckResults()
+
+			got := outputBuffer.String()
+
+			switch {
+			case plugin.ExitStatusCode != tt.wantExitCode:
+				t.Errorf("ERROR: want exit code %d, got %d", tt.wantExitCode, plugin.ExitStatusCode)
+			case tt.strict && !strings.Contains(got, nagios.ErrInternalLibraryFailure.Error()):
+				t.Errorf("ERROR: internal failure not reported in output:\n%s", got)
+			case tt.strict && strings.Contains(got, "'widgets'="):
+				t.Errorf("ERROR: invalid metric emitted in output:\n%s", got)
+			default:
+				t.Log("OK: strict mode behavior as expected")
+			}
+		})
+	}
+}
+
+func TestEnableStrictMode_InternalFailureForcesUnknown(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SkipOSExit()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.EnableStrictMode()
+
+	plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+	plugin.ServiceOutput = "CRITICAL: widgets offline"
+
+	// Skip validation so that the invalid metric is accepted.
+	if err := plugin.AddPerfData(true, nagios.PerformanceData{
+		Label: "widgets",
+		Value: "abc",
+	}); err != nil {
+		t.Fatalf("ERROR: failed to add perfdata: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	if plugin.ExitStatusCode != nagios.StateUNKNOWNExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateUNKNOWNExitCode, plugin.ExitStatusCode)
+	}
+
+	t.Log("OK: internal failure forced UNKNOWN state")
+}
+
+func TestEnableStrictMode_EmitsValidPayload(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SkipOSExit()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.EnableStrictMode()
+
+	plugin.ServiceOutput = "OK: all widgets accounted for"
+
+	if _, err := plugin.SetPayloadString(`{"widgets":42}`); err != nil {
+		t.Fatalf("ERROR: failed to set payload: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	payload, err := nagios.ExtractAndDecodePayload(
+		outputBuffer.String(),
+		"",
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+	if err != nil {
+		t.Fatalf("ERROR: failed to extract payload: %v", err)
+	}
+
+	if payload != `{"widgets":42}` {
+		t.Fatalf("ERROR: want payload %q, got %q", `{"widgets":42}`, payload)
+	}
+
+	if plugin.ExitStatusCode != nagios.StateOKExitCode {
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateOKExitCode, plugin.ExitStatusCode)
+	}
+
+	t.Log("OK: payload compressed once and emitted as expected in strict mode")
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"sync"
+)
+
+// SyncPlugin wraps a Plugin value so that check results may be recorded
+// from multiple goroutines (e.g., plugins which fan out checks of several
+// targets). Each method locks the wrapped Plugin value for the duration of
+// the call to the Plugin method of the same name.
+//
+// Plugin methods (and fields) not provided by SyncPlugin may be used via Do.
+// Check results are returned using the wrapped Plugin value (e.g., via
+// ReturnCheckResults) once all goroutines recording results have finished:
+//
+//	plugin := nagios.NewPlugin()
+//	defer plugin.ReturnCheckResults()
+//
+//	syncPlugin := nagios.NewSyncPlugin(plugin)
+//
+//	var wg sync.WaitGroup
+//	for _, target := range targets {
+//		wg.Add(1)
+//		go func(target string) {
+//			defer wg.Done()
+//			exitCode, message := check(target)
+//			syncPlugin.AddResult(target, exitCode, message)
+//		}(target)
+//	}
+//	wg.Wait()
+type SyncPlugin struct {
+	// mu guards the wrapped Plugin value.
+	mu sync.Mutex
+
+	// plugin is the wrapped Plugin value.
+	plugin *Plugin
+}
+
+// NewSyncPlugin returns a SyncPlugin wrapping the given Plugin value. The
+// Plugin value should not be modified directly while it is in use by
+// multiple goroutines.
+func NewSyncPlugin(plugin *Plugin) *SyncPlugin {
+	return &SyncPlugin{plugin: plugin}
+}
+
+// Plugin returns the wrapped Plugin value. This is intended for returning
+// check results once all goroutines recording results have finished.
+func (s *SyncPlugin) Plugin() *Plugin {
+	return s.plugin
+}
+
+// Do calls the given function with the wrapped Plugin value while holding
+// the lock. The function should not retain the Plugin value or call methods
+// of the SyncPlugin value.
+func (s *SyncPlugin) Do(fn func(p *Plugin)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s.plugin)
+}
+
+// AddPerfData is a concurrency-safe wrapper for Plugin.AddPerfData.
+func (s *SyncPlugin) AddPerfData(skipValidate bool, perfData ...PerformanceData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.plugin.AddPerfData(skipValidate, perfData...)
+}
+
+// AddError is a concurrency-safe wrapper for Plugin.AddError.
+func (s *SyncPlugin) AddError(errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin.AddError(errs...)
+}
+
+// AddUniqueError is a concurrency-safe wrapper for Plugin.AddUniqueError.
+func (s *SyncPlugin) AddUniqueError(errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin.AddUniqueError(errs...)
+}
+
+// AddErrorWithExitCode is a concurrency-safe wrapper for
+// Plugin.AddErrorWithExitCode.
+func (s *SyncPlugin) AddErrorWithExitCode(err error, exitCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin.AddErrorWithExitCode(err, exitCode)
+}
+
+// AddPayloadBytes is a concurrency-safe wrapper for Plugin.AddPayloadBytes.
+func (s *SyncPlugin) AddPayloadBytes(input []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.plugin.AddPayloadBytes(input)
+}
+
+// AddPayloadString is a concurrency-safe wrapper for
+// Plugin.AddPayloadString.
+func (s *SyncPlugin) AddPayloadString(input string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.plugin.AddPayloadString(input)
+}
+
+// AddResult is a concurrency-safe wrapper for Plugin.AddResult.
+func (s *SyncPlugin) AddResult(name string, exitCode int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin.AddResult(name, exitCode, message)
+}
+
+// EscalateState is a concurrency-safe wrapper for Plugin.EscalateState.
+func (s *SyncPlugin) EscalateState(exitCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin.EscalateState(exitCode)
+}
+
+// AddLongServiceOutputTable is a concurrency-safe wrapper for
+// Plugin.AddLongServiceOutputTable.
+func (s *SyncPlugin) AddLongServiceOutputTable(table OutputTable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.plugin.AddLongServiceOutputTable(table)
+}
+
+// AppendLongServiceOutput appends the given lines to the LongServiceOutput
+// field of the wrapped Plugin value. Lines are separated from each other
+// (and from any existing content) using CheckOutputEOL.
+func (s *SyncPlugin) AppendLongServiceOutput(lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range lines {
+		if s.plugin.LongServiceOutput != "" {
+			s.plugin.LongServiceOutput += CheckOutputEOL
+		}
+
+		s.plugin.LongServiceOutput += line
+	}
+}
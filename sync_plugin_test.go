@@ -0,0 +1,101 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestSyncPlugin_ConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	const workers = 20
+
+	plugin := nagios.NewPlugin()
+	syncPlugin := nagios.NewSyncPlugin(plugin)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("target%02d", i)
+
+			if err := syncPlugin.AddPerfData(false, nagios.PerformanceData{Label: name, Value: "1"}); err != nil {
+				t.Errorf("ERROR: failed to add performance data: %v", err)
+			}
+
+			if _, err := syncPlugin.AddPayloadString(name); err != nil {
+				t.Errorf("ERROR: failed to add payload: %v", err)
+			}
+
+			syncPlugin.AddError(fmt.Errorf("%s: problem found", name))
+			syncPlugin.AddResult(name, nagios.StateWARNINGExitCode, "problem found")
+			syncPlugin.AppendLongServiceOutput(name + " checked")
+			syncPlugin.Do(func(p *nagios.Plugin) {
+				p.WarningThreshold = "80"
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(plugin.PerfData()); got != workers {
+		t.Errorf("ERROR: want %d metrics, got %d", workers, got)
+	}
+
+	if got := len(plugin.Errors); got != workers {
+		t.Errorf("ERROR: want %d errors, got %d", workers, got)
+	}
+
+	if got := len(plugin.Results()); got != workers {
+		t.Errorf("ERROR: want %d results, got %d", workers, got)
+	}
+
+	if got := strings.Count(plugin.LongServiceOutput, " checked"); got != workers {
+		t.Errorf("ERROR: want %d LongServiceOutput lines, got %d", workers, got)
+	}
+
+	if got := len(plugin.UnencodedPayload()); got != workers*len("target00") {
+		t.Errorf("ERROR: want payload length %d, got %d", workers*len("target00"), got)
+	}
+
+	if plugin.ExitStatusCode != nagios.StateWARNINGExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateWARNINGExitCode, plugin.ExitStatusCode)
+	}
+
+	if syncPlugin.Plugin() != plugin {
+		t.Error("ERROR: want wrapped plugin value")
+	}
+
+	t.Log("OK: concurrent use of SyncPlugin recorded all results")
+}
+
+func TestSyncPlugin_AppendLongServiceOutput(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.LongServiceOutput = "existing"
+
+	syncPlugin := nagios.NewSyncPlugin(plugin)
+	syncPlugin.AppendLongServiceOutput()
+	syncPlugin.AppendLongServiceOutput("first", "second")
+
+	want := "existing" + nagios.CheckOutputEOL + "first" + nagios.CheckOutputEOL + "second"
+	if plugin.LongServiceOutput != want {
+		t.Fatalf("ERROR: want %q, got %q", want, plugin.LongServiceOutput)
+	}
+
+	t.Log("OK: LongServiceOutput lines appended as expected")
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// OutputProfile is the style used when rendering LongServiceOutput tables.
+type OutputProfile int
+
+// Supported output profiles.
+const (
+	// OutputProfileNagios is the default output profile. Tables are
+	// rendered as aligned plain text.
+	OutputProfileNagios OutputProfile = iota
+
+	// OutputProfileIcingaWebHTML renders tables as simple HTML tables
+	// suitable for display within the Icinga Web detail view. Icinga Web
+	// renders a limited subset of HTML in plugin output.
+	OutputProfileIcingaWebHTML
+)
+
+// OutputTable is a table of values rendered after the LongServiceOutput
+// content.
+type OutputTable struct {
+	// Caption is the optional title of the table.
+	Caption string
+
+	// Headers is the optional collection of column headers.
+	Headers []string
+
+	// Rows is the collection of table rows. Rows with fewer values than
+	// other rows are padded with empty values.
+	Rows [][]string
+}
+
+// SetOutputProfile overrides the default output profile used when rendering
+// LongServiceOutput tables (see AddLongServiceOutputTable). The output
+// profile applies to the Nagios plugin output format only.
+func (p *Plugin) SetOutputProfile(profile OutputProfile) {
+	p.outputProfile = profile
+}
+
+// AddLongServiceOutputTable adds the given table to the collection rendered
+// after the LongServiceOutput content. Tables are rendered as plain text by
+// default or as HTML if the Icinga Web HTML output profile is selected.
+// Tables without headers or rows are ignored.
+func (p *Plugin) AddLongServiceOutputTable(table OutputTable) {
+	if len(table.Headers) == 0 && len(table.Rows) == 0 {
+		return
+	}
+
+	p.outputTables = append(p.outputTables, table)
+}
+
+// longServiceOutputTables returns any tables rendered using the given output
+// profile, separated by a blank line.
+func (p Plugin) longServiceOutputTables(profile OutputProfile) string {
+	tables := p.allOutputTables()
+	parts := make([]string, 0, len(tables))
+
+	for _, table := range tables {
+		switch profile {
+		case OutputProfileIcingaWebHTML:
+			parts = append(parts, table.HTML())
+		default:
+			parts = append(parts, table.Text(CheckOutputEOL))
+		}
+	}
+
+	return strings.Join(parts, CheckOutputEOL+CheckOutputEOL)
+}
+
+// allOutputTables returns the collection of tables rendered after the
+// LongServiceOutput content: the per-component breakdown of any recorded
+// component results (see AddResult) followed by any tables added by client
+// code.
+func (p Plugin) allOutputTables() []OutputTable {
+	if len(p.componentResults) == 0 {
+		return p.outputTables
+	}
+
+	tables := make([]OutputTable, 0, len(p.outputTables)+1)
+	tables = append(tables, p.componentResultsTable())
+
+	return append(tables, p.outputTables...)
+}
+
+// columns returns the number of columns in the table.
+func (t OutputTable) columns() int {
+	columns := len(t.Headers)
+	for _, row := range t.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	return columns
+}
+
+// Text returns the table as aligned plain text using the given line
+// separator (e.g., CheckOutputEOL). Headers (if any) are separated from the
+// rows by a line of dashes.
+func (t OutputTable) Text(eol string) string {
+	columns := t.columns()
+	widths := make([]int, columns)
+
+	measure := func(values []string) {
+		for i, value := range values {
+			if n := utf8.RuneCountInString(value); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	measure(t.Headers)
+	for _, row := range t.Rows {
+		measure(row)
+	}
+
+	formatRow := func(values []string) string {
+		var line strings.Builder
+		for i := 0; i < columns; i++ {
+			var value string
+			if i < len(values) {
+				value = values[i]
+			}
+
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(value)
+
+			if i < columns-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(value)))
+			}
+		}
+
+		return strings.TrimRight(line.String(), " ")
+	}
+
+	lines := make([]string, 0, len(t.Rows)+3)
+
+	if t.Caption != "" {
+		lines = append(lines, t.Caption)
+	}
+
+	if len(t.Headers) > 0 {
+		lines = append(lines, formatRow(t.Headers))
+
+		separators := make([]string, columns)
+		for i, width := range widths {
+			separators[i] = strings.Repeat("-", width)
+		}
+		lines = append(lines, formatRow(separators))
+	}
+
+	for _, row := range t.Rows {
+		lines = append(lines, formatRow(row))
+	}
+
+	return strings.Join(lines, eol)
+}
+
+// HTML returns the table as a single line HTML table. All values are
+// escaped.
+func (t OutputTable) HTML() string {
+	columns := t.columns()
+
+	var output strings.Builder
+
+	writeRow := func(values []string, cell string) {
+		output.WriteString("<tr>")
+		for i := 0; i < columns; i++ {
+			var value string
+			if i < len(values) {
+				value = values[i]
+			}
+			output.WriteString("<" + cell + ">" + html.EscapeString(value) + "</" + cell + ">")
+		}
+		output.WriteString("</tr>")
+	}
+
+	output.WriteString("<table>")
+
+	if t.Caption != "" {
+		output.WriteString("<caption>" + html.EscapeString(t.Caption) + "</caption>")
+	}
+
+	if len(t.Headers) > 0 {
+		output.WriteString("<thead>")
+		writeRow(t.Headers, "th")
+		output.WriteString("</thead>")
+	}
+
+	if len(t.Rows) > 0 {
+		output.WriteString("<tbody>")
+		for _, row := range t.Rows {
+			writeRow(row, "td")
+		}
+		output.WriteString("</tbody>")
+	}
+
+	output.WriteString("</table>")
+
+	return output.String()
+}
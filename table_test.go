@@ -0,0 +1,110 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestPlugin_AddLongServiceOutputTable_RendersPlainTextByDefault(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetOutputProfile(nagios.OutputProfileNagios)
+	plugin.ServiceOutput = "OK: 2 datastores checked"
+	plugin.LongServiceOutput = "Datastore usage:"
+	plugin.AddLongServiceOutputTable(nagios.OutputTable{
+		Headers: []string{"Name", "Used"},
+		Rows: [][]string{
+			{"ds-01", "42%"},
+			{"ds-<lab>", "7%"},
+		},
+	})
+
+	plugin.ReturnCheckResults()
+
+	want := strings.Join([]string{
+		"Datastore usage:",
+		"",
+		"Name      Used",
+		"--------  ----",
+		"ds-01     42%",
+		"ds-<lab>  7%",
+	}, nagios.CheckOutputEOL)
+
+	got := outputBuffer.String()
+	if !strings.Contains(got, want) {
+		t.Fatalf("ERROR: plain text table not found in output:\n%q", got)
+	}
+
+	if strings.Contains(got, "<table>") {
+		t.Fatalf("ERROR: unexpected HTML in default output:\n%q", got)
+	}
+
+	t.Log("OK: plain text table rendered as expected")
+}
+
+func TestPlugin_AddLongServiceOutputTable_RendersHTMLForIcingaWebProfile(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetOutputProfile(nagios.OutputProfileIcingaWebHTML)
+	plugin.ServiceOutput = "OK: 2 datastores checked"
+	plugin.LongServiceOutput = "Datastore usage:"
+	plugin.AddLongServiceOutputTable(nagios.OutputTable{
+		Headers: []string{"Name", "Used"},
+		Rows: [][]string{
+			{"ds-01", "42%"},
+			{"ds-<lab>", "7%"},
+		},
+	})
+
+	plugin.ReturnCheckResults()
+
+	want := "<table><thead><tr><th>Name</th><th>Used</th></tr></thead>" +
+		"<tbody><tr><td>ds-01</td><td>42%</td></tr>" +
+		"<tr><td>ds-&lt;lab&gt;</td><td>7%</td></tr></tbody></table>"
+
+	got := outputBuffer.String()
+	if !strings.Contains(got, "Datastore usage:"+nagios.CheckOutputEOL+nagios.CheckOutputEOL+want) {
+		t.Fatalf("ERROR: HTML table not found in output:\n%q", got)
+	}
+
+	t.Log("OK: HTML table rendered as expected")
+}
+
+func TestOutputTable_Text_PadsShortRows(t *testing.T) {
+	t.Parallel()
+
+	table := nagios.OutputTable{
+		Caption: "Interfaces",
+		Rows: [][]string{
+			{"eth0", "up", "1000"},
+			{"eth1"},
+		},
+	}
+
+	want := "Interfaces\neth0  up  1000\neth1"
+
+	if got := table.Text("\n"); got != want {
+		t.Fatalf("ERROR: want %q, got %q", want, got)
+	}
+
+	t.Log("OK: short rows padded as expected")
+}
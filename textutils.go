@@ -7,7 +7,10 @@
 
 package nagios
 
-import "strings"
+import (
+	"io"
+	"strings"
+)
 
 // inList is a helper function to emulate Python's `if "x" in list:`
 // functionality. The caller can optionally ignore case of compared items.
@@ -56,3 +59,22 @@ func removeEntry(input string, substr string, delimiter string) string {
 
 	return strings.Join(removeAtIndex(lines, idxToRemove), delimiter)
 }
+
+// writeStrings writes the given values to w in order and returns the total
+// number of bytes written along with the first error encountered. Unlike
+// fmt.Fprint, values are written without intermediate formatting buffers or
+// interface conversions.
+func writeStrings(w io.Writer, values ...string) (int, error) {
+	var total int
+
+	for _, value := range values {
+		written, err := io.WriteString(w, value)
+		total += written
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
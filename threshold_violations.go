@@ -0,0 +1,168 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"fmt"
+	"io"
+)
+
+// ThresholdViolation describes a performance data metric value found by
+// EvaluateThreshold to be outside of a WARNING or CRITICAL threshold range.
+type ThresholdViolation struct {
+	// Metric is the label of the performance data metric.
+	Metric string
+
+	// Value is the value of the performance data metric.
+	Value string
+
+	// UnitOfMeasurement is the (optional) unit of measurement of the
+	// performance data metric value.
+	UnitOfMeasurement string
+
+	// Range is the threshold range violated by the metric value.
+	Range string
+
+	// State is the plugin state resulting from the violation.
+	State ServiceState
+}
+
+// String returns a human readable description of the threshold violation.
+func (tv ThresholdViolation) String() string {
+	return tv.format(defaultThresholdViolationFormat)
+}
+
+// format returns the threshold violation formatted using the given format
+// string. See Labels.ThresholdViolation.
+func (tv ThresholdViolation) format(format string) string {
+	return fmt.Sprintf(
+		format,
+		tv.State.Label,
+		tv.Metric,
+		tv.Value+tv.UnitOfMeasurement,
+		tv.Range,
+	)
+}
+
+// ThresholdViolations returns a copy of the threshold violations recorded
+// by EvaluateThreshold in the order they were found. A nil value is returned
+// if no violations were recorded.
+func (p *Plugin) ThresholdViolations() []ThresholdViolation {
+	if len(p.thresholdViolations) == 0 {
+		return nil
+	}
+
+	violations := make([]ThresholdViolation, len(p.thresholdViolations))
+	copy(violations, p.thresholdViolations)
+
+	return violations
+}
+
+// SetThresholdViolationsLabel overrides the default threshold violations
+// label text.
+func (p *Plugin) SetThresholdViolationsLabel(newLabel string) {
+	p.thresholdViolationsLabel = newLabel
+}
+
+// recordThresholdViolation records that the value of the given performance
+// data metric is outside of the given threshold range. A violation already
+// recorded for the metric label is replaced.
+func (p *Plugin) recordThresholdViolation(pd PerformanceData, rangeStr string, exitCode int) {
+	violation := ThresholdViolation{
+		Metric:            pd.Label,
+		Value:             pd.Value,
+		UnitOfMeasurement: pd.UnitOfMeasurement,
+		Range:             rangeStr,
+		State: ServiceState{
+			Label:    ExitCodeToStateLabel(exitCode),
+			ExitCode: exitCode,
+		},
+	}
+
+	for i := range p.thresholdViolations {
+		if p.thresholdViolations[i].Metric == pd.Label {
+			p.thresholdViolations[i] = violation
+			return
+		}
+	}
+
+	p.thresholdViolations = append(p.thresholdViolations, violation)
+}
+
+// getThresholdViolationsLabelText retrieves the custom threshold violations
+// label text if set, otherwise returns the default value.
+func (p Plugin) getThresholdViolationsLabelText() string {
+	switch {
+	case p.thresholdViolationsLabel != "":
+		return p.thresholdViolationsLabel
+	default:
+		return defaultThresholdViolationsLabel
+	}
+}
+
+// isThresholdViolationsHidden indicates whether the Threshold Violations
+// section should be omitted from output.
+func (p Plugin) isThresholdViolationsHidden() bool {
+	return p.thresholdViolationsSectionSkipReason() != ""
+}
+
+// thresholdViolationsSectionSkipReason returns the reason the Threshold
+// Violations section is omitted from output or an empty string if the
+// section is displayed.
+func (p Plugin) thresholdViolationsSectionSkipReason() string {
+	switch {
+	case p.hideThresholdsSection:
+		return "option to hide thresholds enabled"
+	case p.isErrorsAndThresholdsHiddenForState():
+		return "option to hide thresholds for OK state enabled"
+	case len(p.thresholdViolations) == 0:
+		return "no threshold violations recorded"
+	default:
+		return ""
+	}
+}
+
+// handleThresholdViolationsSection is a wrapper around the logic used to
+// handle/process the Threshold Violations section header and listing. The
+// caller is responsible for skipping this section if hidden (see
+// thresholdViolationsSectionSkipReason).
+func (p Plugin) handleThresholdViolationsSection(w io.Writer) {
+	var totalWritten int
+
+	// The Errors section (if displayed) ends with a newline; otherwise
+	// this section directly follows the ServiceOutput content.
+	separator := CheckOutputEOL
+	if p.isErrorsHidden() {
+		separator += CheckOutputEOL
+	}
+
+	written, err := writeStrings(w,
+		separator,
+		"**", p.getThresholdViolationsLabelText(), "**",
+		CheckOutputEOL,
+		CheckOutputEOL,
+	)
+	if err != nil {
+		panic("Failed to write threshold violations section label to given output sink")
+	}
+
+	totalWritten += written
+
+	format := labelText(p.labels.ThresholdViolation, defaultThresholdViolationFormat)
+
+	for _, violation := range p.thresholdViolations {
+		written, err := writeStrings(w, "* ", violation.format(format), CheckOutputEOL)
+		if err != nil {
+			panic("Failed to write threshold violation to given output sink")
+		}
+
+		totalWritten += written
+	}
+
+	p.logSectionOutputSize("ThresholdViolations", "%d bytes plugin threshold violations content written to given output sink", totalWritten)
+}
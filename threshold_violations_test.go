@@ -0,0 +1,224 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlugin_ThresholdViolations_RecordsViolations(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	metrics := []nagios.PerformanceData{
+		{Label: "ds01_used", Value: "42", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		{Label: "ds02_used", Value: "85", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		{Label: "ds03_used", Value: "95", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+	}
+
+	for _, pd := range metrics {
+		if err := plugin.EvaluateThreshold(pd); err != nil {
+			t.Fatalf("ERROR: failed to evaluate threshold: %v", err)
+		}
+	}
+
+	want := []nagios.ThresholdViolation{
+		{
+			Metric:            "ds02_used",
+			Value:             "85",
+			UnitOfMeasurement: "%",
+			Range:             "80",
+			State:             nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode},
+		},
+		{
+			Metric:            "ds03_used",
+			Value:             "95",
+			UnitOfMeasurement: "%",
+			Range:             "90",
+			State:             nagios.ServiceState{Label: nagios.StateCRITICALLabel, ExitCode: nagios.StateCRITICALExitCode},
+		},
+	}
+
+	got := plugin.ThresholdViolations()
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	// Modifying the returned collection does not affect recorded violations.
+	got[0].Metric = "modified"
+	if plugin.ThresholdViolations()[0].Metric != "ds02_used" {
+		t.Fatal("ERROR: recorded threshold violations modified via returned collection")
+	}
+
+	t.Log("OK: threshold violations recorded as expected")
+}
+
+func TestPlugin_EvaluateThreshold_RecordsAllViolations(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	metrics := []nagios.PerformanceData{
+		{Label: "ds01_used", Value: "95", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		{Label: "ds02_used", Value: "42", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		{Label: "ds03_used", Value: "85", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+		{Label: "ds04_used", Value: "99", UnitOfMeasurement: "%", Warn: "80", Crit: "90"},
+	}
+
+	// Repeated evaluation does not record duplicate violations.
+	for i := 0; i < 2; i++ {
+		if err := plugin.EvaluateThreshold(metrics...); err != nil {
+			t.Fatalf("ERROR: failed to evaluate thresholds: %v", err)
+		}
+	}
+
+	if plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, plugin.ExitStatusCode)
+	}
+
+	critical := nagios.ServiceState{Label: nagios.StateCRITICALLabel, ExitCode: nagios.StateCRITICALExitCode}
+	warning := nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode}
+
+	want := []nagios.ThresholdViolation{
+		{Metric: "ds01_used", Value: "95", UnitOfMeasurement: "%", Range: "90", State: critical},
+		{Metric: "ds03_used", Value: "85", UnitOfMeasurement: "%", Range: "80", State: warning},
+		{Metric: "ds04_used", Value: "99", UnitOfMeasurement: "%", Range: "90", State: critical},
+	}
+
+	if d := cmp.Diff(want, plugin.ThresholdViolations()); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: all threshold violations recorded once as expected")
+}
+
+func TestPlugin_EvaluateThreshold_EvaluatesAllMetricsOnError(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	err := plugin.EvaluateThreshold(
+		nagios.PerformanceData{Label: "invalid", Value: "1", Crit: "x20"},
+		nagios.PerformanceData{Label: "used", Value: "95", Crit: "90"},
+	)
+	if err == nil {
+		t.Fatal("ERROR: want error for invalid threshold")
+	}
+
+	if plugin.ExitStatusCode != nagios.StateCRITICALExitCode {
+		t.Errorf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, plugin.ExitStatusCode)
+	}
+
+	if got := plugin.ThresholdViolations(); len(got) != 1 || got[0].Metric != "used" {
+		t.Errorf("ERROR: want violation recorded for metric following invalid threshold, got %v", got)
+	}
+
+	t.Log("OK: metrics following an invalid threshold evaluated as expected")
+}
+
+func TestPlugin_ThresholdViolations_NoViolations(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+
+	if err := plugin.EvaluateThreshold(nagios.PerformanceData{Label: "used", Value: "42", Warn: "80", Crit: "90"}); err != nil {
+		t.Fatalf("ERROR: failed to evaluate threshold: %v", err)
+	}
+
+	if got := plugin.ThresholdViolations(); got != nil {
+		t.Fatalf("ERROR: want no threshold violations, got %v", got)
+	}
+
+	t.Log("OK: no threshold violations recorded as expected")
+}
+
+func TestPlugin_ThresholdViolations_RenderedInOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		addError bool
+		want     string
+	}{
+		"without errors section": {
+			want: "CRITICAL: datastore usage high" + nagios.CheckOutputEOL +
+				nagios.CheckOutputEOL +
+				"**THRESHOLD VIOLATIONS**" + nagios.CheckOutputEOL +
+				nagios.CheckOutputEOL +
+				`* CRITICAL: ds03_used value 95% outside of threshold range "90"` + nagios.CheckOutputEOL,
+		},
+		"following errors section": {
+			addError: true,
+			want: "* " + nagios.ErrInvalidRangeThreshold.Error() + nagios.CheckOutputEOL +
+				nagios.CheckOutputEOL +
+				"**THRESHOLD VIOLATIONS**" + nagios.CheckOutputEOL +
+				nagios.CheckOutputEOL +
+				`* CRITICAL: ds03_used value 95% outside of threshold range "90"` + nagios.CheckOutputEOL,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var outputBuffer strings.Builder
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.ServiceOutput = "CRITICAL: datastore usage high"
+
+			if tt.addError {
+				plugin.AddError(nagios.ErrInvalidRangeThreshold)
+			}
+
+			pd := nagios.PerformanceData{Label: "ds03_used", Value: "95", UnitOfMeasurement: "%", Warn: "80", Crit: "90"}
+			if err := plugin.EvaluateThreshold(pd); err != nil {
+				t.Fatalf("ERROR: failed to evaluate threshold: %v", err)
+			}
+
+			plugin.ReturnCheckResults()
+
+			if !strings.Contains(outputBuffer.String(), tt.want) {
+				t.Fatalf("ERROR: want %q in output, got:\n%q", tt.want, outputBuffer.String())
+			}
+
+			t.Log("OK: threshold violations section rendered as expected")
+		})
+	}
+}
+
+func TestPlugin_ThresholdViolations_SectionHidden(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer strings.Builder
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.HideThresholdsSection()
+	plugin.ServiceOutput = "WARNING: datastore usage high"
+
+	if err := plugin.EvaluateThreshold(nagios.PerformanceData{Label: "used", Value: "85", Warn: "80"}); err != nil {
+		t.Fatalf("ERROR: failed to evaluate threshold: %v", err)
+	}
+
+	plugin.ReturnCheckResults()
+
+	if strings.Contains(outputBuffer.String(), "**THRESHOLD VIOLATIONS**") {
+		t.Fatalf("ERROR: unexpected threshold violations section in output:\n%s", outputBuffer.String())
+	}
+
+	t.Log("OK: threshold violations section hidden as expected")
+}
@@ -0,0 +1,392 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minSchedulerTimeoutMargin is the minimum time reserved between the
+	// plugin timeout and a scheduler-provided timeout.
+	minSchedulerTimeoutMargin time.Duration = time.Second
+
+	// maxSchedulerTimeoutMargin is the maximum time reserved between the
+	// plugin timeout and a scheduler-provided timeout.
+	maxSchedulerTimeoutMargin time.Duration = 5 * time.Second
+
+	// timeRemainingMetricLabel is the label of the performance data metric
+	// recording the time remaining before the timeout is reached.
+	timeRemainingMetricLabel string = "time_remaining"
+)
+
+// schedulerTimeoutEnvVars is the collection of environment variables (in
+// order of precedence) checked for a scheduler-provided timeout. Nagios
+// compatible schedulers export macros as environment variables using the
+// NAGIOS_ prefix.
+var schedulerTimeoutEnvVars = []string{
+	"NAGIOS_TIMEOUT",
+	"ICINGA_TIMEOUT",
+	"NAGIOS_SERVICECHECKTIMEOUT",
+	"NAGIOS_HOSTCHECKTIMEOUT",
+}
+
+// timeoutWatchdog emits an UNKNOWN check result if the plugin timeout is
+// reached before check results are returned.
+type timeoutWatchdog struct {
+	// mu guards the timer and finished flag.
+	mu sync.Mutex
+
+	// timer triggers the timeout handling.
+	timer *time.Timer
+
+	// timeout is the plugin timeout.
+	timeout time.Duration
+
+	// finished indicates that check results have been (or are being)
+	// returned, either by ReturnCheckResults or by the timeout handling.
+	finished bool
+
+	// expired indicates that the timeout was reached and an UNKNOWN check
+	// result emitted.
+	expired bool
+}
+
+// SetTimeout overrides the plugin timeout, including any timeout provided by
+// the scheduler via environment variables (see EnableSchedulerTimeout). If
+// check results have not been returned by the time the timeout (measured
+// from plugin construction) is reached, an UNKNOWN check result is emitted
+// and the plugin exits. A non-positive value disables the plugin timeout.
+//
+// See also TimeoutContext for bounding client code operations by the same
+// timeout.
+func (p *Plugin) SetTimeout(timeout time.Duration) {
+	p.stopTimeoutWatchdog()
+
+	if timeout <= 0 {
+		p.logAction("Disabling plugin timeout")
+		p.timeout = nil
+
+		return
+	}
+
+	p.logAction(fmt.Sprintf("Setting plugin timeout to %s", timeout))
+	p.startTimeoutWatchdog(timeout)
+}
+
+// Timeout returns the plugin timeout or zero if no timeout is set.
+func (p *Plugin) Timeout() time.Duration {
+	if p.timeout == nil {
+		return 0
+	}
+
+	return p.timeout.timeout
+}
+
+// TimeoutContext returns a copy of the given context with a deadline
+// matching the plugin timeout (if set). Client code should use this context
+// for network requests and other potentially slow operations so that they
+// are abandoned before the plugin timeout is reached.
+func (p *Plugin) TimeoutContext(parent context.Context) (context.Context, context.CancelFunc) {
+	timeout := p.Timeout()
+	if timeout == 0 {
+		return context.WithCancel(parent)
+	}
+
+	return context.WithDeadline(parent, p.start.Add(timeout))
+}
+
+// EnableSchedulerTimeout arms the plugin timeout slightly below the timeout
+// provided by the scheduler via environment variables (e.g.,
+// NAGIOS_TIMEOUT), if any. This is intended for actively scheduled plugins
+// which return check results using ReturnCheckResults. If check results have
+// not been returned by the time the timeout (measured from plugin
+// construction) is reached, an UNKNOWN check result is emitted and the
+// plugin exits.
+//
+// The plugin timeout is disarmed when plugin output is rendered without
+// exiting (e.g., via PassiveCheckResult or Snapshot).
+//
+// See also SetTimeout.
+func (p *Plugin) EnableSchedulerTimeout() {
+	timeout, source := schedulerTimeout()
+	if timeout == 0 {
+		p.logAction("No scheduler-provided timeout found")
+
+		return
+	}
+
+	p.stopTimeoutWatchdog()
+
+	p.logAction(fmt.Sprintf("Using scheduler-provided timeout from %s", source))
+	p.startTimeoutWatchdog(pluginTimeoutForScheduler(timeout))
+}
+
+// EnableTimeRemainingMetric indicates that a time_remaining performance data
+// metric should be emitted alongside the default time metric if a timeout
+// is known. The metric records the time (in milliseconds) remaining before
+// the plugin timeout (see SetTimeout and EnableSchedulerTimeout) or, if no
+// plugin timeout is set, before the timeout provided by the scheduler via
+// environment variables (e.g., NAGIOS_TIMEOUT). The timeout is recorded as
+// the maximum value of the metric.
+//
+// This allows dashboards to spot checks creeping toward their timeout before
+// they start failing. As with the default time metric, the plugin must be
+// created using the constructor.
+func (p *Plugin) EnableTimeRemainingMetric() {
+	p.logAction("Enabling time remaining metric as requested")
+	p.timeRemainingMetric = true
+}
+
+// tryAddTimeRemainingMetric inserts a time_remaining performance data metric
+// into the collection if requested and a timeout is known. An existing
+// metric of the same name is not replaced.
+func (p *Plugin) tryAddTimeRemainingMetric() {
+	switch {
+	case !p.timeRemainingMetric:
+		return
+	case p.perfData.has(timeRemainingMetricLabel):
+		p.logAction("Existing time remaining metric present, skipping replacement")
+
+		return
+	case p.start.IsZero():
+		p.logAction("Plugin not created using constructor, so no time remaining metric to use")
+
+		return
+	}
+
+	timeout := p.Timeout()
+	if timeout == 0 {
+		timeout, _ = schedulerTimeout()
+	}
+
+	if timeout == 0 {
+		p.logAction("No plugin or scheduler-provided timeout found, skipping time remaining metric")
+
+		return
+	}
+
+	p.perfData.set(timeRemainingMetric(p.start, timeout))
+
+	p.logAction("Added time remaining metric to collection")
+}
+
+// timeRemainingMetric returns a performance data metric recording the time
+// remaining before the given timeout (measured from the given start time)
+// is reached.
+func timeRemainingMetric(start time.Time, timeout time.Duration) PerformanceData {
+	remaining := timeout - time.Since(start)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return PerformanceData{
+		Label:             timeRemainingMetricLabel,
+		Value:             strconv.FormatInt(remaining.Milliseconds(), 10),
+		UnitOfMeasurement: defaultTimeMetricUnitOfMeasurement,
+		Min:               "0",
+		Max:               strconv.FormatInt(timeout.Milliseconds(), 10),
+	}
+}
+
+// startTimeoutWatchdog arms the timeout handling for the given timeout.
+func (p *Plugin) startTimeoutWatchdog(timeout time.Duration) {
+	watchdog := &timeoutWatchdog{timeout: timeout}
+
+	watchdog.mu.Lock()
+	defer watchdog.mu.Unlock()
+
+	watchdog.timer = time.AfterFunc(time.Until(p.start.Add(timeout)), func() {
+		p.handleTimeout(watchdog, fmt.Sprintf(
+			labelText(p.labels.TimeoutReached, defaultTimeoutReachedFormat),
+			timeout,
+		))
+	})
+
+	p.timeout = watchdog
+}
+
+// stopTimeoutWatchdog disarms the timeout handling (if armed). The watchdog
+// is marked as finished so that timeout handling which already fired (and
+// is waiting on the watchdog lock) does not emit output.
+func (p *Plugin) stopTimeoutWatchdog() {
+	if p.timeout == nil {
+		return
+	}
+
+	p.timeout.mu.Lock()
+	defer p.timeout.mu.Unlock()
+
+	p.timeout.finished = true
+
+	if p.timeout.timer != nil {
+		p.timeout.timer.Stop()
+	}
+}
+
+// disarmTimeoutForRender disarms the timeout handling (if armed) ahead of
+// rendering plugin output without returning check results via
+// ReturnCheckResults. The timeout handling would otherwise emit an UNKNOWN
+// check result and exit after the work of client code is complete.
+func (p *Plugin) disarmTimeoutForRender() {
+	if p.timeout == nil {
+		return
+	}
+
+	p.logAction("Disarming plugin timeout ahead of rendering plugin output")
+	p.finishTimeoutWatchdog()
+}
+
+// finishTimeoutWatchdog disarms the timeout handling ahead of returning
+// check results. The return value indicates whether the timeout was already
+// reached (and an UNKNOWN check result emitted).
+func (p *Plugin) finishTimeoutWatchdog() bool {
+	if p.timeout == nil {
+		return false
+	}
+
+	p.timeout.mu.Lock()
+	defer p.timeout.mu.Unlock()
+
+	if p.timeout.timer != nil {
+		p.timeout.timer.Stop()
+	}
+
+	p.timeout.finished = true
+
+	return p.timeout.expired
+}
+
+// handleTimeout emits an UNKNOWN check result using the given summary and
+// exits if check results have not yet been returned.
+//
+// Because this runs concurrently with client code, the plugin state (e.g.,
+// ServiceOutput) is not used. The watchdog lock is held until the process
+// exits (or the custom exit function returns) to prevent check results from
+// also being returned by client code.
+func (p *Plugin) handleTimeout(watchdog *timeoutWatchdog, summary string) {
+	watchdog.mu.Lock()
+	defer watchdog.mu.Unlock()
+
+	if watchdog.finished {
+		return
+	}
+
+	watchdog.finished = true
+	watchdog.expired = true
+
+	// The plugin state is owned by client code while the check is running;
+	// the state is reported without being recorded.
+	stateLabel, exitCode := StateUNKNOWNLabel, StateUNKNOWNExitCode
+	if p.hostCheckMode {
+		stateLabel = StateDOWNLabel
+		exitCode = hostStateProcessExitCode(StateDOWNExitCode)
+	}
+
+	output := fmt.Sprintf(
+		"%s: %s%s%s%s%s",
+		stateLabel,
+		summary,
+		CheckOutputEOL,
+		CheckOutputEOL,
+		labelText(p.labels.TimeoutAdvice, runtimeTimeoutReachedAdvice),
+		CheckOutputEOL,
+	)
+
+	p.logActionLevel(DebugLogLevelWarn, "Plugin timeout reached")
+
+	sink := p.outputSink
+	if sink == nil {
+		sink = defaultPluginOutputTarget()
+	}
+
+	if _, err := fmt.Fprint(sink, p.applyCompatibilityEOL(output)); err != nil {
+		_, _ = fmt.Fprintf(
+			defaultPluginAbortMessageOutputTarget(),
+			"Failed to write output to given output sink: %s",
+			err.Error(),
+		)
+	}
+
+	// Honor a custom exit function so that a plugin timeout does not
+	// terminate processes running many plugin values (e.g., parallel tests).
+	switch {
+	case p.exitFunc != nil:
+		p.logAction(fmt.Sprintf("Calling custom exit function with exit code %d", exitCode))
+		p.exitFunc(exitCode)
+	case p.shouldSkipOSExit:
+		p.logAction("Skipping os.Exit call as requested.")
+	default:
+		os.Exit(exitCode)
+	}
+}
+
+// schedulerTimeout returns the timeout provided by the scheduler via
+// environment variables along with the name of the environment variable.
+// Zero is returned if no valid timeout is provided.
+func schedulerTimeout() (time.Duration, string) {
+	for _, name := range schedulerTimeoutEnvVars {
+		if timeout := parseSchedulerTimeout(os.Getenv(name)); timeout > 0 {
+			return timeout, name
+		}
+	}
+
+	return 0, ""
+}
+
+// parseSchedulerTimeout parses the given timeout value specified either in
+// whole or fractional seconds (e.g., "60") or as a duration (e.g., "1m").
+// Zero is returned for empty or invalid values.
+func parseSchedulerTimeout(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0
+	}
+
+	return timeout
+}
+
+// pluginTimeoutForScheduler returns a plugin timeout slightly below the
+// given scheduler timeout so that a useful UNKNOWN check result is emitted
+// before the scheduler kills the plugin. Ten percent of the scheduler timeout
+// (between one and five seconds) is reserved.
+func pluginTimeoutForScheduler(schedulerTimeout time.Duration) time.Duration {
+	margin := schedulerTimeout / 10
+
+	switch {
+	case margin < minSchedulerTimeoutMargin:
+		margin = minSchedulerTimeoutMargin
+	case margin > maxSchedulerTimeoutMargin:
+		margin = maxSchedulerTimeoutMargin
+	}
+
+	if schedulerTimeout <= margin {
+		return schedulerTimeout / 2
+	}
+
+	return schedulerTimeout - margin
+}
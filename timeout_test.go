@@ -0,0 +1,267 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// syncBuffer is a strings.Builder safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.String()
+}
+
+// Environment variables are process-wide; this test cannot run in parallel.
+func TestPlugin_EnableSchedulerTimeout_HonorsSchedulerTimeout(t *testing.T) {
+	t.Setenv("NAGIOS_TIMEOUT", "")
+	t.Setenv("ICINGA_TIMEOUT", "30")
+
+	plugin := nagios.NewPlugin()
+	defer plugin.SetTimeout(0)
+
+	if got := plugin.Timeout(); got != 0 {
+		t.Fatalf("ERROR: want no timeout before opting in, got %s", got)
+	}
+
+	plugin.EnableSchedulerTimeout()
+
+	if got := plugin.Timeout(); got != 27*time.Second {
+		t.Fatalf("ERROR: want timeout %s, got %s", 27*time.Second, got)
+	}
+
+	ctx, cancel := plugin.TimeoutContext(context.Background())
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > 27*time.Second {
+		t.Fatalf("ERROR: unexpected context deadline %v (set: %t)", deadline, ok)
+	}
+
+	plugin.SetTimeout(time.Minute)
+	if got := plugin.Timeout(); got != time.Minute {
+		t.Fatalf("ERROR: want overridden timeout %s, got %s", time.Minute, got)
+	}
+
+	t.Log("OK: scheduler timeout honored as expected")
+}
+
+func TestPlugin_SetTimeout_EmitsUnknownWhenReached(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetTimeout(50 * time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(outputBuffer.String(), "UNKNOWN: plugin timeout") {
+		if time.Now().After(deadline) {
+			t.Fatal("ERROR: plugin timeout not reached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Check results are not emitted once the timeout has been reached.
+	plugin.ServiceOutput = "OK: too late"
+	plugin.ReturnCheckResults()
+
+	got := outputBuffer.String()
+	if strings.Contains(got, "too late") {
+		t.Fatalf("ERROR: check results emitted after timeout:\n%q", got)
+	}
+
+	t.Log("OK: UNKNOWN result emitted when plugin timeout reached")
+}
+
+func TestPlugin_SetTimeout_DisarmedByReturnCheckResults(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+	plugin.SetTimeout(50 * time.Millisecond)
+	plugin.ServiceOutput = "OK: done in time"
+	plugin.ReturnCheckResults()
+
+	time.Sleep(100 * time.Millisecond)
+
+	got := outputBuffer.String()
+	if strings.Contains(got, "UNKNOWN") || !strings.Contains(got, "OK: done in time") {
+		t.Fatalf("ERROR: unexpected output:\n%q", got)
+	}
+
+	t.Log("OK: plugin timeout disarmed as expected")
+}
+
+func TestPlugin_SetTimeout_UsesCustomExitFunc(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+	exitCodes := make(chan int, 1)
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(func(code int) { exitCodes <- code })
+	plugin.SetTimeout(20 * time.Millisecond)
+
+	select {
+	case code := <-exitCodes:
+		if code != nagios.StateUNKNOWNExitCode {
+			t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateUNKNOWNExitCode, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ERROR: custom exit function not called when plugin timeout reached")
+	}
+
+	if got := outputBuffer.String(); !strings.Contains(got, "UNKNOWN: plugin timeout") {
+		t.Fatalf("ERROR: unexpected output:\n%q", got)
+	}
+
+	t.Log("OK: custom exit function called when plugin timeout reached")
+}
+
+func TestPlugin_PassiveCheckResult_DisarmsTimeout(t *testing.T) {
+	t.Parallel()
+
+	var outputBuffer syncBuffer
+	exitCodes := make(chan int, 1)
+
+	plugin := nagios.NewPlugin()
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SetExitFunc(func(code int) { exitCodes <- code })
+	plugin.SetTimeout(50 * time.Millisecond)
+	plugin.ServiceOutput = "OK: done in time"
+
+	result := plugin.PassiveCheckResult("web01", "widgets")
+	if !strings.Contains(result.Output, "OK: done in time") {
+		t.Fatalf("ERROR: unexpected passive check result output:\n%q", result.Output)
+	}
+
+	select {
+	case code := <-exitCodes:
+		t.Fatalf("ERROR: plugin timeout fired after rendering (exit code %d):\n%q", code, outputBuffer.String())
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	t.Log("OK: plugin timeout disarmed by rendering passive check result")
+}
+
+// Environment variables are process-wide; this test cannot run in parallel.
+func TestPlugin_EnableTimeRemainingMetric_EmitsMetric(t *testing.T) {
+	t.Setenv("NAGIOS_TIMEOUT", "")
+	t.Setenv("ICINGA_TIMEOUT", "")
+
+	tests := map[string]struct {
+		timeout    time.Duration
+		enable     bool
+		envTimeout string
+		wantMax    string
+	}{
+		"plugin timeout": {
+			timeout: time.Minute,
+			enable:  true,
+			wantMax: "60000",
+		},
+		"scheduler timeout": {
+			envTimeout: "30",
+			enable:     true,
+			wantMax:    "30000",
+		},
+		"no timeout": {
+			enable: true,
+		},
+		"not enabled": {
+			timeout: time.Minute,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NAGIOS_TIMEOUT", tt.envTimeout)
+
+			var outputBuffer syncBuffer
+
+			plugin := nagios.NewPlugin()
+			plugin.SetOutputTarget(&outputBuffer)
+			plugin.SkipOSExit()
+			plugin.SetTimeout(tt.timeout)
+			defer plugin.SetTimeout(0)
+
+			if tt.enable {
+				plugin.EnableTimeRemainingMetric()
+			}
+
+			plugin.ServiceOutput = "OK: done in time"
+			plugin.ReturnCheckResults()
+
+			perfData, err := nagios.ParsePerfData(nagios.PreviewOutput(outputBuffer.String()).PerfData)
+			if err != nil {
+				t.Fatalf("ERROR: failed to parse performance data: %v", err)
+			}
+
+			var got *nagios.PerformanceData
+			for i := range perfData {
+				if perfData[i].Label == "time_remaining" {
+					got = &perfData[i]
+				}
+			}
+
+			switch {
+			case tt.wantMax == "" && got != nil:
+				t.Fatalf("ERROR: unexpected time_remaining metric %+v", *got)
+			case tt.wantMax == "":
+				t.Log("OK: time_remaining metric omitted as expected")
+
+				return
+			case got == nil:
+				t.Fatalf("ERROR: time_remaining metric not found in output:\n%q", outputBuffer.String())
+			}
+
+			if got.Max != tt.wantMax || got.Min != "0" || got.UnitOfMeasurement != "ms" {
+				t.Fatalf("ERROR: unexpected time_remaining metric %+v", *got)
+			}
+
+			remaining, err := strconv.ParseInt(got.Value, 10, 64)
+			if err != nil {
+				t.Fatalf("ERROR: failed to parse time_remaining value %q: %v", got.Value, err)
+			}
+
+			maxRemaining, _ := strconv.ParseInt(tt.wantMax, 10, 64)
+			if remaining <= 0 || remaining > maxRemaining {
+				t.Fatalf("ERROR: time_remaining value %d outside of expected range (0, %d]", remaining, maxRemaining)
+			}
+
+			t.Log("OK: time_remaining metric emitted as expected")
+		})
+	}
+}
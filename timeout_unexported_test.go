@@ -0,0 +1,80 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package nagios
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSchedulerTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]time.Duration{
+		"60":      60 * time.Second,
+		" 7.5 ":   7500 * time.Millisecond,
+		"1m30s":   90 * time.Second,
+		"":        0,
+		"0":       0,
+		"-10":     0,
+		"invalid": 0,
+	}
+
+	for input, want := range tests {
+		if got := parseSchedulerTimeout(input); got != want {
+			t.Errorf("ERROR: input %q: want %s, got %s", input, want, got)
+		}
+	}
+
+	t.Log("OK: scheduler timeout values parsed as expected")
+}
+
+func TestPluginTimeoutForScheduler(t *testing.T) {
+	t.Parallel()
+
+	tests := map[time.Duration]time.Duration{
+		time.Second:       500 * time.Millisecond,
+		5 * time.Second:   4 * time.Second,
+		30 * time.Second:  27 * time.Second,
+		120 * time.Second: 115 * time.Second,
+	}
+
+	for scheduler, want := range tests {
+		if got := pluginTimeoutForScheduler(scheduler); got != want {
+			t.Errorf("ERROR: scheduler timeout %s: want %s, got %s", scheduler, want, got)
+		}
+	}
+
+	t.Log("OK: plugin timeouts calculated as expected")
+}
+
+func TestStopTimeoutWatchdog_PreventsPendingTimeoutHandling(t *testing.T) {
+	t.Parallel()
+
+	var output strings.Builder
+
+	exitCalled := false
+
+	plugin := Plugin{start: time.Now()}
+	plugin.SetOutputTarget(&output)
+	plugin.SetExitFunc(func(int) { exitCalled = true })
+	plugin.SetTimeout(time.Hour)
+
+	// Simulate timeout handling which already fired and is waiting on the
+	// watchdog lock while the timeout is changed.
+	watchdog := plugin.timeout
+	plugin.SetTimeout(0)
+	plugin.handleTimeout(watchdog, "plugin timeout reached")
+
+	if exitCalled || output.Len() != 0 {
+		t.Fatalf("ERROR: stopped watchdog emitted output %q (exit called: %t)", output.String(), exitCalled)
+	}
+
+	t.Log("OK: stopped watchdog did not emit output")
+}
@@ -24,7 +24,7 @@ import (
 // functionality easily breaks this input it is stored in separate files to
 // reduce test breakage due to editors "helping".
 var (
-	//go:embed testdata/payload/small_json_payload_unencoded.txt
+	//go:embed corpus/testdata/payload/small_json_payload_unencoded.txt
 	smallJSONPayloadUnencoded string
 
 	// Earlier prototyping found that the stream encoding/decoding process
@@ -34,7 +34,7 @@ var (
 	// might switch back to using stream processing we explicitly test
 	// using an exclamation point to guard against future breakage.
 	//
-	//go:embed testdata/payload/small_plaintext_payload_unencoded.txt
+	//go:embed corpus/testdata/payload/small_plaintext_payload_unencoded.txt
 	smallPlaintextPayloadUnencoded string
 )
 
@@ -202,7 +202,7 @@ func TestPerformanceDataIsNotDuplicated(t *testing.T) {
 
 	// Two unique labels, so should be just two performance data metrics.
 	want := 2
-	got := len(plugin.perfData)
+	got := plugin.perfData.len()
 
 	if got != want {
 		t.Errorf(
@@ -1097,7 +1097,7 @@ func TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric(t *tes
 	plugin.handlePerformanceData(&outputBuffer)
 
 	// Assert that the metric is present.
-	defaultTimePerfData, ok := plugin.perfData[defaultTimeMetricLabel]
+	defaultTimePerfData, ok := plugin.perfData.get(defaultTimeMetricLabel)
 	if !ok {
 		t.Fatal("Default time performance data metric not present when client code omits metrics")
 	}
@@ -1143,7 +1143,7 @@ func TestNonEmptyClientPerfDataAndConstructedPluginRetainsExistingTimeMetric(t *
 	clientRuntimeMetric := addTestTimeMetric(t, plugin)
 
 	// Assert that the metric is present.
-	_, ok := plugin.perfData[strings.ToLower(clientRuntimeMetric.Label)]
+	ok := plugin.perfData.has(strings.ToLower(clientRuntimeMetric.Label))
 	if !ok {
 		t.Fatal("Expected performance data metric from client code is missing")
 	}
@@ -1183,11 +1183,99 @@ func addTestTimeMetric(t *testing.T, p *Plugin) PerformanceData {
 		UnitOfMeasurement: defaultTimeMetricUnitOfMeasurement,
 	}
 
-	if p.perfData == nil {
-		p.perfData = make(map[string]PerformanceData)
+	p.perfData.set(runtimeMetric)
+
+	return runtimeMetric
+}
+
+func TestWriteSections_SkipsHiddenSectionsBeforeProcessing(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer strings.Builder
+	var outputBuffer strings.Builder
+
+	plugin := NewPlugin()
+	plugin.SetDebugLoggingOutputTarget(&logBuffer)
+	plugin.DebugLoggingEnableActions()
+	plugin.ServiceOutput = "WARNING: datastore usage high"
+	plugin.LongServiceOutput = "Datastore usage details"
+	plugin.WarningThreshold = "80"
+	plugin.CriticalThreshold = "90"
+	plugin.AddError(fmt.Errorf("datastore usage above threshold"))
+	plugin.HideErrorsSection()
+	plugin.HideThresholdsSection()
+
+	plugin.writeSections(&outputBuffer)
+
+	output := outputBuffer.String()
+	for _, label := range []string{defaultErrorsLabel, defaultThresholdsLabel} {
+		if strings.Contains(output, "**"+label+"**") {
+			t.Errorf("ERROR: hidden section %s emitted:\n%s", label, output)
+		}
 	}
 
-	p.perfData[defaultTimeMetricLabel] = runtimeMetric
+	logs := logBuffer.String()
+	for _, want := range []string{
+		"Skipping processing of Errors section; option to hide errors enabled",
+		"Skipping processing of Thresholds section; option to hide thresholds enabled",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("ERROR: want %q logged, got:\n%s", want, logs)
+		}
+	}
 
-	return runtimeMetric
+	for _, unwanted := range []string{"Processing Errors section", "Processing Thresholds section"} {
+		if strings.Contains(logs, unwanted) {
+			t.Errorf("ERROR: hidden section processed; unexpected %q logged", unwanted)
+		}
+	}
+
+	t.Log("OK: hidden sections skipped before processing")
+}
+
+func TestErrorsSectionSkipReason(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		errors []error
+		hide   bool
+		want   string
+	}{
+		"no errors": {
+			want: "no errors recorded",
+		},
+		"only nil errors": {
+			errors: []error{nil, nil},
+			want:   "no errors recorded",
+		},
+		"hidden": {
+			errors: []error{fmt.Errorf("failure")},
+			hide:   true,
+			want:   "option to hide errors enabled",
+		},
+		"displayed": {
+			errors: []error{nil, fmt.Errorf("failure")},
+			want:   "",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := NewPlugin()
+			plugin.Errors = tt.errors
+			if tt.hide {
+				plugin.HideErrorsSection()
+			}
+
+			if d := cmp.Diff(tt.want, plugin.errorsSectionSkipReason()); d != "" {
+				t.Fatalf("(-want, +got)\n:%s", d)
+			}
+
+			t.Logf("OK: errors section skip reason %q as expected", tt.want)
+		})
+	}
 }
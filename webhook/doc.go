@@ -0,0 +1,57 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package webhook provides a client for submitting passive check results as
+JSON documents to an arbitrary HTTP(S) endpoint.
+
+# OVERVIEW
+
+Some environments bridge Nagios-style checks into homegrown event
+pipelines instead of (or in addition to) a Nagios compatible monitoring
+system. This package POSTs each check result to a webhook endpoint using
+the check result JSON format provided by the nagios package (see
+nagios.EncodeCheckResultJSON) wrapped in an event document identifying the
+host, service and check time:
+
+	{
+	  "host_name": "web01",
+	  "service_description": "HTTP",
+	  "timestamp": "2024-01-01T00:00:00Z",
+	  "check_result": { ... }
+	}
+
+Custom request headers, HTTP Basic or bearer token authentication and
+retries of failed requests are supported. Requests are retried on network
+errors and HTTP 429 or 5xx responses; other non-2xx responses are reported
+immediately.
+
+The Client type satisfies the nagios.PassiveSubmitter interface and can be
+used directly with the nagios.Runner type for daemon mode checks. Rendered
+plugin output is parsed back into a structured check result (see
+nagios.ParsePluginOutput) before submission.
+
+# HOW TO USE
+
+	client := webhook.NewClient("https://events.example.com/hooks/nagios")
+	client.SetBearerToken("s3cr3t")
+	client.SetHeader("X-Source", "web01")
+	client.SetRetry(3, 2*time.Second)
+
+	result := plugin.PassiveCheckResult("web01", "HTTP")
+	if err := client.Submit(ctx, result); err != nil {
+		// handle error
+	}
+
+Structured results may also be submitted directly:
+
+	snapshot := plugin.Snapshot()
+	if err := client.SubmitCheckResult(ctx, "web01", "HTTP", &snapshot); err != nil {
+		// handle error
+	}
+*/
+package webhook
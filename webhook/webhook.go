@@ -0,0 +1,328 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// defaultTimeout is the timeout used by the default HTTP client.
+	defaultTimeout time.Duration = 30 * time.Second
+
+	// defaultRetryDelay is the delay between retried requests if not
+	// overridden.
+	defaultRetryDelay time.Duration = time.Second
+
+	// maxResponseBodySize is the maximum number of bytes read from a webhook
+	// response body.
+	maxResponseBodySize int64 = 64 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingURL indicates that the webhook endpoint URL was not
+	// provided.
+	ErrMissingURL = errors.New("webhook URL not specified")
+
+	// ErrNoCheckResults indicates that no check results were provided for
+	// submission.
+	ErrNoCheckResults = errors.New("no check results provided")
+
+	// ErrSubmissionRejected indicates that the webhook endpoint rejected the
+	// submitted check result.
+	ErrSubmissionRejected = errors.New("webhook submission rejected")
+)
+
+// Event is the document submitted to the webhook endpoint for each check
+// result.
+type Event struct {
+	// HostName is the name of the host associated with the check result.
+	HostName string `json:"host_name"`
+
+	// ServiceDescription is the description of the service associated with
+	// the check result. Empty for host check results.
+	ServiceDescription string `json:"service_description,omitempty"`
+
+	// Timestamp indicates when the check result was generated.
+	Timestamp time.Time `json:"timestamp"`
+
+	// CheckResult is the check result encoded using the check result JSON
+	// format (see nagios.EncodeCheckResultJSON).
+	CheckResult json.RawMessage `json:"check_result"`
+}
+
+// Client submits passive check results to a webhook endpoint.
+type Client struct {
+	// endpoint is the webhook URL.
+	endpoint string
+
+	// headers is the collection of custom headers added to each request.
+	headers http.Header
+
+	// username is the optional HTTP Basic authentication user name.
+	username string
+
+	// password is the optional HTTP Basic authentication password.
+	password string
+
+	// bearerToken is the optional bearer token used for authentication.
+	bearerToken string
+
+	// retries is the number of times a failed request is retried.
+	retries int
+
+	// retryDelay is the delay between retried requests.
+	retryDelay time.Duration
+
+	// httpClient is used to perform requests.
+	httpClient *http.Client
+}
+
+// NewClient constructs a new Client for the given webhook endpoint URL.
+// Failed requests are not retried by default.
+func NewClient(endpoint string) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		headers:    make(http.Header),
+		retryDelay: defaultRetryDelay,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// SetHeader sets a custom header added to each request, replacing any
+// previous value for the same header name.
+func (c *Client) SetHeader(name string, value string) {
+	c.headers.Set(name, value)
+}
+
+// SetBasicAuth specifies HTTP Basic authentication credentials. Bearer token
+// authentication takes precedence if also set.
+func (c *Client) SetBasicAuth(username string, password string) {
+	c.username = username
+	c.password = password
+}
+
+// SetBearerToken specifies the token sent using the Authorization header.
+func (c *Client) SetBearerToken(token string) {
+	c.bearerToken = token
+}
+
+// SetRetry specifies the number of times a failed request is retried and
+// the delay between attempts. Negative retry counts are treated as zero and
+// non-positive delays are ignored.
+func (c *Client) SetRetry(retries int, delay time.Duration) {
+	if retries < 0 {
+		retries = 0
+	}
+	c.retries = retries
+
+	if delay > 0 {
+		c.retryDelay = delay
+	}
+}
+
+// SetTLSConfig overrides the TLS settings used by the default HTTP client
+// (e.g., to trust a private CA certificate). A nil value is ignored.
+func (c *Client) SetTLSConfig(config *tls.Config) {
+	if config == nil {
+		return
+	}
+
+	c.httpClient = &http.Client{
+		Timeout: c.httpClient.Timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: config,
+		},
+	}
+}
+
+// SetHTTPClient overrides the default HTTP client. A nil value is ignored.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		return
+	}
+
+	c.httpClient = client
+}
+
+// Submit sends the given check results to the webhook endpoint using one
+// request per check result. The rendered output of each check result is
+// parsed into a structured check result prior to submission. Submission
+// stops at the first failed request.
+//
+// Submit satisfies the nagios.PassiveSubmitter interface.
+func (c *Client) Submit(ctx context.Context, results ...nagios.PassiveCheckResult) error {
+	if len(results) == 0 {
+		return ErrNoCheckResults
+	}
+
+	for _, result := range results {
+		event, err := NewEvent(result)
+		if err != nil {
+			return err
+		}
+
+		if err := c.send(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SubmitCheckResult sends the given structured check result (e.g., from
+// nagios.Plugin.Snapshot) for the specified host and service to the webhook
+// endpoint. If service is empty the result is treated as a host check
+// result.
+func (c *Client) SubmitCheckResult(ctx context.Context, host string, service string, cr *nagios.CheckResult) error {
+	if cr == nil {
+		return ErrNoCheckResults
+	}
+
+	data, err := nagios.EncodeCheckResultJSON(cr)
+	if err != nil {
+		return err
+	}
+
+	return c.send(ctx, Event{
+		HostName:           host,
+		ServiceDescription: service,
+		Timestamp:          time.Now(),
+		CheckResult:        data,
+	})
+}
+
+// NewEvent returns the webhook event for the given passive check result.
+// The rendered output is parsed into a structured check result; invalid
+// performance data is dropped instead of preventing submission.
+func NewEvent(result nagios.PassiveCheckResult) (Event, error) {
+	cr, err := nagios.ParsePluginOutput(result.Output)
+	if cr == nil {
+		return Event{}, fmt.Errorf("failed to parse check result output: %w", err)
+	}
+	cr.ExitStatusCode = result.ExitStatusCode
+
+	data, err := nagios.EncodeCheckResultJSON(cr)
+	if err != nil {
+		return Event{}, err
+	}
+
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return Event{
+		HostName:           result.HostName,
+		ServiceDescription: result.ServiceDescription,
+		Timestamp:          timestamp,
+		CheckResult:        data,
+	}, nil
+}
+
+// send submits the given event, retrying failed requests as configured.
+func (c *Client) send(ctx context.Context, event Event) error {
+	if strings.TrimSpace(c.endpoint) == "" {
+		return ErrMissingURL
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.retryDelay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%v: %w", lastErr, ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		retryable, err := c.post(ctx, data)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !retryable || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return lastErr
+}
+
+// post performs a single webhook request and indicates whether a failed
+// request may be retried.
+func (c *Client) post(ctx context.Context, data []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.endpoint,
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to prepare webhook request: %w", err)
+	}
+
+	for name, values := range c.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	switch {
+	case c.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	case c.username != "" || c.password != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to submit check result to webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// Drain (a limited amount of) the response body to allow connection
+	// reuse.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retryable := resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= http.StatusInternalServerError
+
+	return retryable, fmt.Errorf(
+		"webhook endpoint returned HTTP status %q: %w",
+		resp.Status,
+		ErrSubmissionRejected,
+	)
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Ensure Client satisfies the nagios.PassiveSubmitter interface.
+var _ nagios.PassiveSubmitter = (*Client)(nil)
+
+func TestClient_Submit_PostsEventWithHeadersAndRetries(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var attempts int
+	var event Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+
+		switch {
+		case r.Header.Get("Authorization") != "Bearer s3cr3t":
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		case r.Header.Get("X-Source") != "web01":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		case attempts < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("ERROR: failed to decode event: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetBearerToken("s3cr3t")
+	client.SetHeader("X-Source", "web01")
+	client.SetRetry(2, time.Millisecond)
+
+	result := nagios.PassiveCheckResult{
+		HostName:           "web01",
+		ServiceDescription: "HTTP",
+		ExitStatusCode:     nagios.StateCRITICALExitCode,
+		Output:             "CRITICAL: 1 of 2 endpoints down | 'down'=1;;1;0;2",
+		Timestamp:          time.Unix(1700000000, 0),
+	}
+
+	if err := client.Submit(context.Background(), result); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if attempts != 3 {
+		t.Fatalf("ERROR: want 3 attempts, got %d", attempts)
+	}
+
+	cr, err := nagios.DecodeCheckResultJSON(event.CheckResult)
+	if err != nil {
+		t.Fatalf("ERROR: failed to decode submitted check result: %v", err)
+	}
+
+	switch {
+	case event.HostName != "web01" || event.ServiceDescription != "HTTP":
+		t.Fatalf("ERROR: unexpected event target %q/%q", event.HostName, event.ServiceDescription)
+	case !event.Timestamp.Equal(result.Timestamp):
+		t.Fatalf("ERROR: want timestamp %v, got %v", result.Timestamp, event.Timestamp)
+	case cr.ExitStatusCode != nagios.StateCRITICALExitCode:
+		t.Fatalf("ERROR: want exit code %d, got %d", nagios.StateCRITICALExitCode, cr.ExitStatusCode)
+	case len(cr.PerfData) != 1 || cr.PerfData[0].Label != "down":
+		t.Fatalf("ERROR: unexpected performance data %+v", cr.PerfData)
+	}
+
+	t.Log("OK: event submitted after retries as expected")
+}
+
+func TestClient_Submit_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var attempts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "nagios" || pass != "wrong" {
+			t.Errorf("ERROR: unexpected credentials %q/%q", user, pass)
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetBasicAuth("nagios", "wrong")
+	client.SetRetry(3, time.Millisecond)
+
+	snapshot := nagios.NewPlugin().Snapshot()
+
+	err := client.SubmitCheckResult(context.Background(), "web01", "", &snapshot)
+	if !errors.Is(err, ErrSubmissionRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrSubmissionRejected, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if attempts != 1 {
+		t.Fatalf("ERROR: want 1 attempt, got %d", attempts)
+	}
+
+	t.Log("OK: rejected submission reported without retry as expected")
+}
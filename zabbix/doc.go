@@ -0,0 +1,37 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+/*
+Package zabbix converts Nagios plugin results to Zabbix trapper items and
+submits them using the Zabbix sender protocol.
+
+# OVERVIEW
+
+This package is intended to support dual-homed monitoring during a
+migration from Nagios to Zabbix; the same check logic reports to Nagios as
+usual while also submitting the final state, summary and performance data
+to Zabbix trapper items.
+
+By default the following item keys are used:
+
+	nagios.state                 (plugin exit status code)
+	nagios.output                (one-line summary)
+	nagios.perfdata[<label>]     (performance data metric value)
+
+The key prefix ("nagios") may be overridden. Matching trapper items must be
+defined in Zabbix for the values to be accepted.
+
+# HOW TO USE
+
+	sender := zabbix.NewSender("zabbix.example.com")
+
+	items := sender.PluginItems("web01", plugin)
+	if err := sender.Send(ctx, items...); err != nil {
+		// handle error
+	}
+*/
+package zabbix
@@ -0,0 +1,300 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+const (
+	// DefaultPort is the default TCP port used by the Zabbix server (or
+	// proxy) trapper.
+	DefaultPort string = "10051"
+
+	// DefaultKeyPrefix is the default prefix used for item keys.
+	DefaultKeyPrefix string = "nagios"
+
+	// protocolHeader is the Zabbix protocol signature followed by the
+	// protocol flags (0x01: Zabbix communications protocol).
+	protocolHeader string = "ZBXD\x01"
+
+	// headerSize is the size of the protocol header and data length fields.
+	headerSize int = 5 + 8
+
+	// senderDataRequest is the request type used to submit trapper items.
+	senderDataRequest string = "sender data"
+
+	// responseSuccess is the response value returned for processed
+	// requests.
+	responseSuccess string = "success"
+
+	// defaultTimeout is used for connecting to and communicating with the
+	// Zabbix server if not overridden.
+	defaultTimeout time.Duration = 10 * time.Second
+
+	// maxResponseSize is the maximum accepted response body size.
+	maxResponseSize uint64 = 1024 * 1024
+)
+
+// Sentinel error collection. Exported for potential use by client code to
+// detect & handle specific error scenarios.
+var (
+	// ErrMissingAddress indicates that the Zabbix server address was not
+	// provided.
+	ErrMissingAddress = errors.New("Zabbix address not specified")
+
+	// ErrNoItems indicates that no items were provided for submission.
+	ErrNoItems = errors.New("no items provided")
+
+	// ErrUnexpectedResponse indicates that the Zabbix server returned a
+	// response which could not be interpreted.
+	ErrUnexpectedResponse = errors.New("unexpected Zabbix response")
+
+	// ErrItemsRejected indicates that the Zabbix server did not accept one
+	// or more submitted items (e.g., no matching trapper item exists).
+	ErrItemsRejected = errors.New("Zabbix rejected one or more items")
+)
+
+// failedItemsRegex captures the number of failed items from the response
+// info field (e.g., "processed: 1; failed: 2; total: 3; seconds spent:
+// 0.000055").
+var failedItemsRegex = regexp.MustCompile(`failed:\s*(\d+)`)
+
+// Item is a single Zabbix trapper item value.
+type Item struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Clock int64  `json:"clock,omitempty"`
+}
+
+// senderRequest is the request body used to submit trapper items.
+type senderRequest struct {
+	Request string `json:"request"`
+	Data    []Item `json:"data"`
+	Clock   int64  `json:"clock,omitempty"`
+}
+
+// senderResponse is the response body returned by the Zabbix server.
+type senderResponse struct {
+	Response string `json:"response"`
+	Info     string `json:"info"`
+}
+
+// Sender submits trapper items to a Zabbix server or proxy.
+type Sender struct {
+	// address is the Zabbix server address in host:port format.
+	address string
+
+	// keyPrefix is the prefix used for generated item keys.
+	keyPrefix string
+
+	// timeout limits the time spent connecting to and communicating with
+	// the Zabbix server.
+	timeout time.Duration
+
+	// dialer is used to establish connections to the Zabbix server.
+	dialer net.Dialer
+}
+
+// NewSender constructs a new Sender for the given Zabbix server (or proxy)
+// address. If a port is not included in the address the default trapper
+// port is used.
+func NewSender(address string) *Sender {
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, DefaultPort)
+		}
+	}
+
+	return &Sender{
+		address:   address,
+		keyPrefix: DefaultKeyPrefix,
+		timeout:   defaultTimeout,
+	}
+}
+
+// SetKeyPrefix overrides the default prefix used for generated item keys.
+// An empty value is ignored.
+func (s *Sender) SetKeyPrefix(prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	s.keyPrefix = prefix
+}
+
+// SetTimeout overrides the default timeout used when connecting to and
+// communicating with the Zabbix server. Non-positive values are ignored.
+func (s *Sender) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	s.timeout = timeout
+}
+
+// PluginItems returns trapper items for the final state, one-line summary
+// and collected performance data of the given plugin. Performance data
+// metrics with an undetermined value ("U") are skipped.
+func (s *Sender) PluginItems(host string, p *nagios.Plugin) []Item {
+	clock := time.Now().Unix()
+	perfData := p.PerfData()
+
+	items := make([]Item, 0, len(perfData)+2)
+	items = append(items,
+		Item{Host: host, Key: s.keyPrefix + ".state", Value: strconv.Itoa(p.ExitStatusCode), Clock: clock},
+		Item{Host: host, Key: s.keyPrefix + ".output", Value: p.ServiceOutput, Clock: clock},
+	)
+
+	for _, pd := range perfData {
+		if pd.Value == "U" {
+			continue
+		}
+
+		items = append(items, Item{
+			Host:  host,
+			Key:   s.keyPrefix + ".perfdata[" + keyParameter(pd.Label) + "]",
+			Value: pd.Value,
+			Clock: clock,
+		})
+	}
+
+	return items
+}
+
+// keyParameter returns the given value as an item key parameter, quoting it
+// if it contains characters with special meaning.
+func keyParameter(value string) string {
+	if !strings.ContainsAny(value, `,]["' `) {
+		return value
+	}
+
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// Send submits the given items to the Zabbix server using a single request.
+// An error is returned if the server reports that any item failed to be
+// processed.
+func (s *Sender) Send(ctx context.Context, items ...Item) error {
+	switch {
+	case s.address == "":
+		return ErrMissingAddress
+	case len(items) == 0:
+		return ErrNoItems
+	}
+
+	packet, err := EncodeRequest(items...)
+	if err != nil {
+		return err
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	conn, err := s.dialer.DialContext(dialCtx, "tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Zabbix: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set Zabbix connection deadline: %w", err)
+	}
+
+	if _, err := conn.Write(packet); err != nil {
+		return fmt.Errorf("failed to send items to Zabbix: %w", err)
+	}
+
+	body, err := readPacket(conn)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(body)
+}
+
+// EncodeRequest returns the given items as a Zabbix sender protocol packet.
+func EncodeRequest(items ...Item) ([]byte, error) {
+	data, err := json.Marshal(senderRequest{
+		Request: senderDataRequest,
+		Data:    items,
+		Clock:   time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode items as JSON: %w", err)
+	}
+
+	packet := make([]byte, headerSize, headerSize+len(data))
+	copy(packet, protocolHeader)
+	binary.LittleEndian.PutUint64(packet[len(protocolHeader):], uint64(len(data)))
+
+	return append(packet, data...), nil
+}
+
+// readPacket reads a single Zabbix protocol packet and returns the data.
+func readPacket(r io.Reader) ([]byte, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read Zabbix response header: %w", err)
+	}
+
+	if !bytes.Equal(header[:len(protocolHeader)], []byte(protocolHeader)) {
+		return nil, fmt.Errorf("invalid response header %q: %w", header, ErrUnexpectedResponse)
+	}
+
+	length := binary.LittleEndian.Uint64(header[len(protocolHeader):])
+	if length > maxResponseSize {
+		return nil, fmt.Errorf("response length %d exceeds limit: %w", length, ErrUnexpectedResponse)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("failed to read Zabbix response: %w", err)
+	}
+
+	return data, nil
+}
+
+// parseResponse evaluates the given response data and returns an error if
+// the request or any submitted item was not processed.
+func parseResponse(data []byte) error {
+	var resp senderResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to decode JSON response: %v: %w", err, ErrUnexpectedResponse)
+	}
+
+	if resp.Response != responseSuccess {
+		return fmt.Errorf("response %q (%s): %w", resp.Response, resp.Info, ErrItemsRejected)
+	}
+
+	if matches := failedItemsRegex.FindStringSubmatch(resp.Info); len(matches) == 2 && matches[1] != "0" {
+		return fmt.Errorf("%s: %w", resp.Info, ErrItemsRejected)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/go-nagios
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package zabbix
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// fakeTrapper accepts a single connection, decodes the submitted request and
+// responds with the given info text.
+func fakeTrapper(t *testing.T, info string) (string, <-chan senderRequest) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR: failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	requests := make(chan senderRequest, 1)
+
+	go func() {
+		defer close(requests)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		data, err := readPacket(conn)
+		if err != nil {
+			t.Errorf("ERROR: failed to read request: %v", err)
+			return
+		}
+
+		var req senderRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			t.Errorf("ERROR: failed to decode request: %v", err)
+			return
+		}
+		requests <- req
+
+		body, _ := json.Marshal(senderResponse{Response: responseSuccess, Info: info})
+		header := make([]byte, headerSize)
+		copy(header, protocolHeader)
+		binary.LittleEndian.PutUint64(header[len(protocolHeader):], uint64(len(body)))
+
+		_, _ = conn.Write(append(header, body...))
+	}()
+
+	return listener.Addr().String(), requests
+}
+
+func TestSender_Send_SubmitsPluginItems(t *testing.T) {
+	t.Parallel()
+
+	addr, requests := fakeTrapper(t, "processed: 4; failed: 0; total: 4; seconds spent: 0.000055")
+
+	plugin := nagios.NewPlugin()
+	plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+	plugin.ServiceOutput = "WARNING: slow"
+	_ = plugin.AddPerfData(false,
+		nagios.PerformanceData{Label: "time", Value: "874", UnitOfMeasurement: "ms"},
+		nagios.PerformanceData{Label: "response size", Value: "10"},
+		nagios.PerformanceData{Label: "unknown", Value: "U"},
+	)
+
+	sender := NewSender(addr)
+	sender.SetKeyPrefix("legacy")
+
+	if err := sender.Send(context.Background(), sender.PluginItems("web01", plugin)...); err != nil {
+		t.Fatalf("ERROR: unexpected submission failure: %v", err)
+	}
+
+	req := <-requests
+
+	want := []Item{
+		{Host: "web01", Key: "legacy.state", Value: "1"},
+		{Host: "web01", Key: "legacy.output", Value: "WARNING: slow"},
+		{Host: "web01", Key: `legacy.perfdata["response size"]`, Value: "10"},
+		{Host: "web01", Key: "legacy.perfdata[time]", Value: "874"},
+	}
+
+	if req.Request != senderDataRequest {
+		t.Errorf("ERROR: want request %q, got %q", senderDataRequest, req.Request)
+	}
+
+	if d := cmp.Diff(want, req.Data, cmpopts.IgnoreFields(Item{}, "Clock")); d != "" {
+		t.Fatalf("(-want, +got)\n:%s", d)
+	}
+
+	t.Log("OK: plugin items submitted as expected")
+}
+
+func TestSender_Send_ReportsFailedItems(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := fakeTrapper(t, "processed: 0; failed: 1; total: 1; seconds spent: 0.000055")
+
+	err := NewSender(addr).Send(context.Background(), Item{Host: "web01", Key: "nagios.state", Value: "0"})
+	if !errors.Is(err, ErrItemsRejected) {
+		t.Fatalf("ERROR: want %v, got %v", ErrItemsRejected, err)
+	}
+
+	t.Log("OK: failed items reported as expected")
+}